@@ -0,0 +1,122 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type ActivateTwoFactorAction struct {
+	uc        usecase.ActivateTwoFactorUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewActivateTwoFactorAction(uc usecase.ActivateTwoFactorUseCase, log logger.Logger, v validator.Validator) ActivateTwoFactorAction {
+	return ActivateTwoFactorAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a ActivateTwoFactorAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "activate_two_factor"
+
+	var input usecase.ActivateTwoFactorInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input.Email = principal.Email
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidTwoFactorCode:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when activating two factor")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		case domain.ErrTwoFactorAlreadyEnabled:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusConflict,
+			).Log("error when activating two factor")
+
+			response.NewError("conflict", http.StatusConflict, err, "").Send(w)
+			return
+		case domain.ErrTwoFactorNotEnrolled:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusPreconditionFailed,
+			).Log("error when activating two factor")
+
+			response.NewError("condition_error", http.StatusPreconditionFailed, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when activating two factor")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success activating two factor")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a ActivateTwoFactorAction) validateInput(input usecase.ActivateTwoFactorInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
 	"chat-api/adapter/api/response"
 	"chat-api/adapter/logger"
 	"chat-api/adapter/validator"
+	"chat-api/domain"
 	"chat-api/usecase"
 )
 
@@ -44,6 +46,19 @@ func (a CreateMessageAction) Execute(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
+	// Only internal services holding an API key may post on behalf of someone else
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		if !principal.APIKey {
+			input.MessageFrom = principal.Email
+		}
+		input.Internal = domain.SeesInternalNotes(principal.Role)
+		input.Admin = principal.Role == domain.ADMIN
+	}
+	// Guests post through the channel their token is bound to
+	if channelId := r.URL.Query().Get("channelId"); channelId != "" {
+		input.ChannelId = channelId
+	}
+
 	if err := a.validateInput(input); err != nil {
 		logging.NewError(
 			a.log,
@@ -58,16 +73,36 @@ func (a CreateMessageAction) Execute(w http.ResponseWriter, r *http.Request) {
 
 	output, err := a.uc.Execute(r.Context(), input)
 	if err != nil {
-		logging.NewError(
-			a.log,
-			err,
-			logKey,
-			http.StatusInternalServerError,
-		).Log("error when creating message")
+		switch err {
+		case domain.ErrAttachmentNotFound, domain.ErrTooManyAttachments:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when creating message")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		case domain.ErrNotChannelParticipant:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusForbidden,
+			).Log("error when creating message")
 
-		// response.NewError(err, http.StatusInternalServerError).Send(w)
-		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			response.NewError("forbidden", http.StatusForbidden, err, "").Send(w)
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when creating message")
 
+			// response.NewError(err, http.StatusInternalServerError).Send(w)
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+		}
 		return
 	}
 	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success creating message")
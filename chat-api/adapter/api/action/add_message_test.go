@@ -2,6 +2,8 @@ package action
 
 import (
 	"bytes"
+	"chat-api/adapter/api/middleware"
+	"chat-api/domain"
 	"chat-api/infrastructure/log"
 	"chat-api/infrastructure/validation"
 	"chat-api/usecase"
@@ -72,6 +74,22 @@ func TestAddUserIdentityAction_Execute(t *testing.T) {
 			expectedBody:       `{"errors":[{"code":400,"message":"Message is a required field","type":"input_error"}]}`,
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			name: "error sender outside the channel",
+			args: args{
+				rawPayload: []byte(`{
+					"channelId": "30fj3094rjf0t934059rjf3094",
+					"messageFrom": "message_from@gmail.com",
+					"message": "message"
+					}`),
+			},
+			ucMock: mockAddMessage{
+				result: usecase.CreateMessageOutput{},
+				err:    domain.ErrNotChannelParticipant,
+			},
+			expectedBody:       `{"errors":[{"code":403,"message":"only the participants of the channel can do this","type":"forbidden"}]}`,
+			expectedStatusCode: http.StatusForbidden,
+		},
 	}
 
 	for _, tt := range tests {
@@ -110,3 +128,63 @@ func TestAddUserIdentityAction_Execute(t *testing.T) {
 		})
 	}
 }
+
+type mockCaptureAddMessage struct {
+	input *usecase.CreateMessageInput
+}
+
+func (m mockCaptureAddMessage) Execute(_ context.Context, input usecase.CreateMessageInput) (usecase.CreateMessageOutput, error) {
+	*m.input = input
+	return usecase.CreateMessageOutput{}, nil
+}
+
+func TestCreateMessageAction_ExecuteMessageFrom(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	tests := []struct {
+		name      string
+		principal middleware.Principal
+		expected  string
+	}{
+		{
+			name:      "user token cannot post as someone else",
+			principal: middleware.Principal{Email: "user@email.com", Role: domain.USER},
+			expected:  "user@email.com",
+		},
+		{
+			name:      "token claiming the service role cannot post as someone else",
+			principal: middleware.Principal{Email: "user@email.com", Role: domain.SERVICE},
+			expected:  "user@email.com",
+		},
+		{
+			name:      "service api key posts on behalf of the sender",
+			principal: middleware.Principal{Email: "apikey:socket", Role: domain.SERVICE, Scope: domain.ScopeMessagesWrite, APIKey: true},
+			expected:  "message_from@gmail.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"/message",
+				strings.NewReader(`{"channelId": "30fj3094rjf0t934059rjf3094", "messageFrom": "message_from@gmail.com", "message": "message"}`),
+			)
+			req = req.WithContext(middleware.WithPrincipal(req.Context(), tt.principal))
+
+			var (
+				input  usecase.CreateMessageInput
+				w      = httptest.NewRecorder()
+				action = NewCreateMessageAction(mockCaptureAddMessage{input: &input}, log.LoggerMock{}, validator)
+			)
+
+			action.Execute(w, req)
+
+			if input.MessageFrom != tt.expected {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, input.MessageFrom, tt.expected)
+			}
+		})
+	}
+}
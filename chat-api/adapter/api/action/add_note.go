@@ -0,0 +1,94 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type AddNoteAction struct {
+	uc        usecase.AddNoteUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewAddNoteAction(uc usecase.AddNoteUseCase, log logger.Logger, v validator.Validator) AddNoteAction {
+	return AddNoteAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a AddNoteAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "add_note"
+
+	var input usecase.AddNoteInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input.ChannelId = r.URL.Query().Get("channelId")
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.Author = principal.Email
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrAttachmentNotFound, domain.ErrTooManyAttachments:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when adding note")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		default:
+			messageChangeError(a.log, w, err, logKey, "error when adding note")
+		}
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success adding note")
+
+	response.NewSuccess(output, http.StatusCreated).Send(w)
+}
+
+func (a AddNoteAction) validateInput(input usecase.AddNoteInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/usecase"
+)
+
+type ApplyCannedResponseAction struct {
+	uc        usecase.ApplyCannedResponseUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewApplyCannedResponseAction(uc usecase.ApplyCannedResponseUseCase, log logger.Logger, v validator.Validator) ApplyCannedResponseAction {
+	return ApplyCannedResponseAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a ApplyCannedResponseAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "apply_canned_response"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.ApplyCannedResponseInput{
+		ChannelId:  r.URL.Query().Get("channelId"),
+		ResponseId: r.URL.Query().Get("responseId"),
+		Rep:        principal.Email,
+		IP:         middleware.ClientIPFromContext(r.Context()),
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		cannedResponseError(a.log, w, err, logKey, "error when applying canned response")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success applying canned response")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a ApplyCannedResponseAction) validateInput(input usecase.ApplyCannedResponseInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type ChangePasswordAction struct {
+	uc        usecase.ChangePasswordUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewChangePasswordAction(uc usecase.ChangePasswordUseCase, log logger.Logger, v validator.Validator) ChangePasswordAction {
+	return ChangePasswordAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a ChangePasswordAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "change_password"
+
+	var input usecase.ChangePasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input.Email = principal.Email
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrCurrentPasswordIncorrect:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when changing password")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		case domain.ErrUserNotFound:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusNotFound,
+			).Log("error when changing password")
+
+			response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when changing password")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success changing password")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a ChangePasswordAction) validateInput(input usecase.ChangePasswordInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
@@ -0,0 +1,130 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type CompleteSSOLoginAction struct {
+	uc        usecase.CompleteSSOLoginUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewCompleteSSOLoginAction(uc usecase.CompleteSSOLoginUseCase, log logger.Logger, v validator.Validator) CompleteSSOLoginAction {
+	return CompleteSSOLoginAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a CompleteSSOLoginAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "complete_sso_login"
+
+	var input usecase.CompleteSSOLoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrSSONotConfigured:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusNotFound,
+			).Log("error when completing sso login")
+
+			response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+			return
+		case domain.ErrInvalidSSOState:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when completing sso login")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		case domain.ErrSSOLoginFailed:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusUnauthorized,
+			).Log("error when completing sso login")
+
+			response.NewError("sso_login_failed", http.StatusUnauthorized, err, "").Send(w)
+			return
+		case domain.ErrSSOEmailUnverified, domain.ErrSSORoleNotAllowed, domain.ErrUserDeactivated:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusForbidden,
+			).Log("sso login refused")
+
+			response.NewError("forbidden", http.StatusForbidden, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when completing sso login")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success completing sso login")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a CompleteSSOLoginAction) validateInput(input usecase.CompleteSSOLoginInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
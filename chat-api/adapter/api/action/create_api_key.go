@@ -0,0 +1,88 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/usecase"
+)
+
+type CreateAPIKeyAction struct {
+	uc        usecase.CreateAPIKeyUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewCreateAPIKeyAction(uc usecase.CreateAPIKeyUseCase, log logger.Logger, v validator.Validator) CreateAPIKeyAction {
+	return CreateAPIKeyAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a CreateAPIKeyAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "create_api_key"
+
+	var input usecase.CreateAPIKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input.CreatedBy = principal.Email
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusInternalServerError,
+		).Log("error when creating api key")
+
+		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success creating api key")
+
+	response.NewSuccess(output, http.StatusCreated).Send(w)
+}
+
+func (a CreateAPIKeyAction) validateInput(input usecase.CreateAPIKeyInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
@@ -0,0 +1,122 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type CreateCannedResponseAction struct {
+	uc        usecase.CreateCannedResponseUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewCreateCannedResponseAction(uc usecase.CreateCannedResponseUseCase, log logger.Logger, v validator.Validator) CreateCannedResponseAction {
+	return CreateCannedResponseAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a CreateCannedResponseAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "create_canned_response"
+
+	var input usecase.CreateCannedResponseInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input.Owner = principal.Email
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		cannedResponseError(a.log, w, err, logKey, "error when creating canned response")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success creating canned response")
+
+	response.NewSuccess(output, http.StatusCreated).Send(w)
+}
+
+func (a CreateCannedResponseAction) validateInput(input usecase.CreateCannedResponseInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
+
+// cannedResponseError answers the errors shared by the canned response actions
+func cannedResponseError(log logger.Logger, w http.ResponseWriter, err error, logKey, message string) {
+	switch err {
+	case domain.ErrCannedResponseNotFound, domain.ErrUserNotFound:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusNotFound,
+		).Log(message)
+
+		response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+	case domain.ErrInvalidShortcut, domain.ErrUnknownTemplateVariable:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log(message)
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+	case domain.ErrCannedResponseShortcutTaken:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusConflict,
+		).Log(message)
+
+		response.NewError("conflict", http.StatusConflict, err, "").Send(w)
+	default:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusInternalServerError,
+		).Log(message)
+
+		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"chat-api/adapter/api/response"
 	"chat-api/adapter/logger"
 	"chat-api/adapter/validator"
+	"chat-api/domain"
 	"chat-api/usecase"
 )
 
@@ -57,6 +58,17 @@ func (a CreateChannelAction) Execute(w http.ResponseWriter, r *http.Request) {
 	}
 
 	output, err := a.uc.Execute(r.Context(), input)
+	if err == domain.ErrInvalidAttribute {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when creating channel")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
 	if err != nil {
 		logging.NewError(
 			a.log,
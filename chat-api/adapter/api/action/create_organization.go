@@ -0,0 +1,112 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type CreateOrganizationAction struct {
+	uc        usecase.CreateOrganizationUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewCreateOrganizationAction(uc usecase.CreateOrganizationUseCase, log logger.Logger, v validator.Validator) CreateOrganizationAction {
+	return CreateOrganizationAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a CreateOrganizationAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "create_organization"
+
+	var input usecase.CreateOrganizationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input.CreatedBy = principal.Email
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrTenantForbidden:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusForbidden,
+			).Log("error when creating organization")
+
+			response.NewError("forbidden", http.StatusForbidden, err, "").Send(w)
+			return
+		case domain.ErrOrganizationAlreadyExists:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusConflict,
+			).Log("error when creating organization")
+
+			response.NewError("conflict", http.StatusConflict, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when creating organization")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success creating organization")
+
+	response.NewSuccess(output, http.StatusCreated).Send(w)
+}
+
+func (a CreateOrganizationAction) validateInput(input usecase.CreateOrganizationInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
 	"chat-api/adapter/api/response"
 	"chat-api/adapter/logger"
 	"chat-api/adapter/validator"
+	"chat-api/domain"
 	"chat-api/usecase"
 )
 
@@ -44,6 +46,17 @@ func (a CreateUserAction) Execute(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		// A guest registering shows with its token which channel it opened
+		if principal.Scope == domain.ScopeGuest {
+			input.GuestChannelId, input.GuestEmail = principal.ChannelId, principal.Email
+		} else {
+			input.CreatedBy = principal.Email
+			input.CreatedByAdmin = principal.Role == domain.ADMIN && !principal.APIKey
+		}
+	}
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
 	if err := a.validateInput(input); err != nil {
 		logging.NewError(
 			a.log,
@@ -58,17 +71,39 @@ func (a CreateUserAction) Execute(w http.ResponseWriter, r *http.Request) {
 
 	output, err := a.uc.Execute(r.Context(), input)
 	if err != nil {
-		logging.NewError(
-			a.log,
-			err,
-			logKey,
-			http.StatusInternalServerError,
-		).Log("error when creating a user")
+		switch err {
+		case domain.ErrUserAlreadyExists:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusConflict,
+			).Log("error when creating a user")
 
-		// response.NewError(err, http.StatusInternalServerError).Send(w)
-		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			response.NewError("conflict", http.StatusConflict, err, "").Send(w)
+			return
+		case domain.ErrAdminCreatedByAdmin:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusForbidden,
+			).Log("error when creating a user")
 
-		return
+			response.NewError("forbidden", http.StatusForbidden, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when creating a user")
+
+			// response.NewError(err, http.StatusInternalServerError).Send(w)
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
 	}
 	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success creating user")
 
@@ -2,6 +2,7 @@ package action
 
 import (
 	"bytes"
+	"chat-api/domain"
 	"chat-api/infrastructure/log"
 	"chat-api/infrastructure/validation"
 	"chat-api/usecase"
@@ -98,6 +99,24 @@ func TestCreateUserAction_Execute(t *testing.T) {
 			expectedBody:       `{"errors":[{"code":400,"message":"FirstName is a required field,LastName is a required field","type":"input_error"}]}`,
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			name: "error user already exists",
+			args: args{
+				rawPayload: []byte(`{
+					"firstName": "John",
+					"lastName": "James",
+					"email": "User_Email@gmail.com",
+					"password": "supersecurepassword",
+					"role": "USER"
+				}`),
+			},
+			ucMock: mockCreateUser{
+				result: usecase.CreateUserOutput{},
+				err:    domain.ErrUserAlreadyExists,
+			},
+			expectedBody:       `{"errors":[{"code":409,"message":"user already exists with email","type":"conflict"}]}`,
+			expectedStatusCode: http.StatusConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,67 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/usecase"
+)
+
+type DeleteCannedResponseAction struct {
+	uc        usecase.DeleteCannedResponseUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewDeleteCannedResponseAction(uc usecase.DeleteCannedResponseUseCase, log logger.Logger, v validator.Validator) DeleteCannedResponseAction {
+	return DeleteCannedResponseAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a DeleteCannedResponseAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "delete_canned_response"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.DeleteCannedResponseInput{
+		Id:        r.URL.Query().Get("id"),
+		DeletedBy: principal.Email,
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		cannedResponseError(a.log, w, err, logKey, "error when deleting canned response")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success deleting canned response")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a DeleteCannedResponseAction) validateInput(input usecase.DeleteCannedResponseInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
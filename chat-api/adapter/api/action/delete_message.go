@@ -0,0 +1,72 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type DeleteMessageAction struct {
+	uc        usecase.DeleteMessageUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewDeleteMessageAction(uc usecase.DeleteMessageUseCase, log logger.Logger, v validator.Validator) DeleteMessageAction {
+	return DeleteMessageAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a DeleteMessageAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "delete_message"
+
+	input := usecase.DeleteMessageInput{
+		ChannelId: r.URL.Query().Get("channelId"),
+		MessageId: r.URL.Query().Get("messageId"),
+		IP:        middleware.ClientIPFromContext(r.Context()),
+	}
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.Editor = principal.Email
+		input.Admin = principal.Role == domain.ADMIN
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		messageChangeError(a.log, w, err, logKey, "error when deleting message")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success deleting message")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a DeleteMessageAction) validateInput(input usecase.DeleteMessageInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package action
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type DownloadAttachmentAction struct {
+	uc  usecase.DownloadAttachmentUseCase
+	log logger.Logger
+}
+
+func NewDownloadAttachmentAction(uc usecase.DownloadAttachmentUseCase, log logger.Logger) DownloadAttachmentAction {
+	return DownloadAttachmentAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+// Execute needs no credentials, the signature of the link stands in for them
+func (a DownloadAttachmentAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "download_attachment"
+
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || query.Get("id") == "" || query.Get("signature") == "" {
+		attachmentError(a.log, w, domain.ErrInvalidAttachmentURL, logKey, "invalid attachment url")
+		return
+	}
+
+	input := usecase.DownloadAttachmentInput{
+		AttachmentId: query.Get("id"),
+		Expires:      expires,
+		Signature:    query.Get("signature"),
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		attachmentError(a.log, w, err, logKey, "error when downloading attachment")
+		return
+	}
+	defer output.Content.Close()
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success downloading attachment")
+
+	inline := strings.HasPrefix(output.ContentType, "image/")
+	response.NewFile(output.Content, output.FileName, output.ContentType, output.Size, inline, http.StatusOK).Send(w)
+}
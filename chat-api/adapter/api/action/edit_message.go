@@ -0,0 +1,127 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type EditMessageAction struct {
+	uc        usecase.EditMessageUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewEditMessageAction(uc usecase.EditMessageUseCase, log logger.Logger, v validator.Validator) EditMessageAction {
+	return EditMessageAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a EditMessageAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "edit_message"
+
+	var input usecase.EditMessageInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input.ChannelId = r.URL.Query().Get("channelId")
+	input.MessageId = r.URL.Query().Get("messageId")
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.Editor = principal.Email
+		input.Admin = principal.Role == domain.ADMIN
+	}
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		messageChangeError(a.log, w, err, logKey, "error when editing message")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success editing message")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a EditMessageAction) validateInput(input usecase.EditMessageInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
+
+// messageChangeError answers the errors shared by message edits and deletions
+func messageChangeError(log logger.Logger, w http.ResponseWriter, err error, logKey, message string) {
+	switch err {
+	case domain.ErrUserNotFound, domain.ErrMessageNotFound:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusNotFound,
+		).Log(message)
+
+		response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+	case domain.ErrNotMessageAuthor, domain.ErrMessageEditWindowClosed, domain.ErrNotChannelParticipant:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusForbidden,
+		).Log(message)
+
+		response.NewError("forbidden", http.StatusForbidden, err, "").Send(w)
+	case domain.ErrMessageDeleted:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusConflict,
+		).Log(message)
+
+		response.NewError("conflict", http.StatusConflict, err, "").Send(w)
+	default:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusInternalServerError,
+		).Log(message)
+
+		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+	}
+}
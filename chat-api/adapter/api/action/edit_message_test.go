@@ -0,0 +1,136 @@
+package action
+
+import (
+	"bytes"
+	"chat-api/adapter/api/middleware"
+	"chat-api/domain"
+	"chat-api/infrastructure/log"
+	"chat-api/infrastructure/validation"
+	"chat-api/usecase"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type mockEditMessage struct {
+	err   error
+	input *usecase.EditMessageInput
+}
+
+func (m mockEditMessage) Execute(_ context.Context, input usecase.EditMessageInput) (usecase.EditMessageOutput, error) {
+	*m.input = input
+	return usecase.EditMessageOutput{}, m.err
+}
+
+func TestEditMessageAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	tests := []struct {
+		name               string
+		body               string
+		principal          middleware.Principal
+		ucErr              error
+		expectedStatusCode int
+		expectedInput      usecase.EditMessageInput
+	}{
+		{
+			name:               "author editing",
+			body:               `{"message": "hello"}`,
+			principal:          middleware.Principal{Email: "user@email.com", Role: "USER"},
+			expectedStatusCode: http.StatusOK,
+			expectedInput:      usecase.EditMessageInput{ChannelId: "c1", MessageId: "m1", Message: "hello", Editor: "user@email.com"},
+		},
+		{
+			name:               "admin editing",
+			body:               `{"message": "hello"}`,
+			principal:          middleware.Principal{Email: "rep@email.com", Role: domain.ADMIN},
+			expectedStatusCode: http.StatusOK,
+			expectedInput:      usecase.EditMessageInput{ChannelId: "c1", MessageId: "m1", Message: "hello", Editor: "rep@email.com", Admin: true},
+		},
+		{
+			name:               "empty text",
+			body:               `{"message": ""}`,
+			principal:          middleware.Principal{Email: "user@email.com"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "malformed body",
+			body:               `{"message": `,
+			principal:          middleware.Principal{Email: "user@email.com"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "unknown message",
+			body:               `{"message": "hello"}`,
+			principal:          middleware.Principal{Email: "user@email.com"},
+			ucErr:              domain.ErrMessageNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedInput:      usecase.EditMessageInput{ChannelId: "c1", MessageId: "m1", Message: "hello", Editor: "user@email.com"},
+		},
+		{
+			name:               "unknown channel",
+			body:               `{"message": "hello"}`,
+			principal:          middleware.Principal{Email: "user@email.com"},
+			ucErr:              domain.ErrUserNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedInput:      usecase.EditMessageInput{ChannelId: "c1", MessageId: "m1", Message: "hello", Editor: "user@email.com"},
+		},
+		{
+			name:               "someone else",
+			body:               `{"message": "hello"}`,
+			principal:          middleware.Principal{Email: "user@email.com"},
+			ucErr:              domain.ErrNotMessageAuthor,
+			expectedStatusCode: http.StatusForbidden,
+			expectedInput:      usecase.EditMessageInput{ChannelId: "c1", MessageId: "m1", Message: "hello", Editor: "user@email.com"},
+		},
+		{
+			name:               "window closed",
+			body:               `{"message": "hello"}`,
+			principal:          middleware.Principal{Email: "user@email.com"},
+			ucErr:              domain.ErrMessageEditWindowClosed,
+			expectedStatusCode: http.StatusForbidden,
+			expectedInput:      usecase.EditMessageInput{ChannelId: "c1", MessageId: "m1", Message: "hello", Editor: "user@email.com"},
+		},
+		{
+			name:               "message deleted",
+			body:               `{"message": "hello"}`,
+			principal:          middleware.Principal{Email: "user@email.com"},
+			ucErr:              domain.ErrMessageDeleted,
+			expectedStatusCode: http.StatusConflict,
+			expectedInput:      usecase.EditMessageInput{ChannelId: "c1", MessageId: "m1", Message: "hello", Editor: "user@email.com"},
+		},
+		{
+			name:               "failed update",
+			body:               `{"message": "hello"}`,
+			principal:          middleware.Principal{Email: "user@email.com"},
+			ucErr:              errors.New("db down"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedInput:      usecase.EditMessageInput{ChannelId: "c1", MessageId: "m1", Message: "hello", Editor: "user@email.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				input usecase.EditMessageInput
+				req   = httptest.NewRequest(http.MethodPut, "/channel?channelId=c1&messageId=m1", bytes.NewBufferString(tt.body))
+				w     = httptest.NewRecorder()
+			)
+			req = req.WithContext(middleware.WithPrincipal(req.Context(), tt.principal))
+
+			NewEditMessageAction(mockEditMessage{err: tt.ucErr, input: &input}, log.LoggerMock{}, validator).Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+			if !reflect.DeepEqual(input, tt.expectedInput) {
+				t.Errorf("[TestCase '%s'] Input: '%v' | Expected: '%v'", tt.name, input, tt.expectedInput)
+			}
+		})
+	}
+}
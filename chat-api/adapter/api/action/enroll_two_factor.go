@@ -0,0 +1,88 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type EnrollTwoFactorAction struct {
+	uc        usecase.EnrollTwoFactorUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewEnrollTwoFactorAction(uc usecase.EnrollTwoFactorUseCase, log logger.Logger, v validator.Validator) EnrollTwoFactorAction {
+	return EnrollTwoFactorAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a EnrollTwoFactorAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "enroll_two_factor"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.EnrollTwoFactorInput{
+		Email: principal.Email,
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrTwoFactorAlreadyEnabled:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusConflict,
+			).Log("error when enrolling two factor")
+
+			response.NewError("conflict", http.StatusConflict, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when enrolling two factor")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success enrolling two factor")
+
+	response.NewSuccess(output, http.StatusCreated).Send(w)
+}
+
+func (a EnrollTwoFactorAction) validateInput(input usecase.EnrollTwoFactorInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
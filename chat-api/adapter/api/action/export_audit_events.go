@@ -0,0 +1,102 @@
+package action
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type ExportAuditEventsAction struct {
+	uc  usecase.ExportAuditEventsUseCase
+	log logger.Logger
+}
+
+func NewExportAuditEventsAction(uc usecase.ExportAuditEventsUseCase, log logger.Logger) ExportAuditEventsAction {
+	return ExportAuditEventsAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a ExportAuditEventsAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "export_audit_events"
+
+	query, err := auditQueryInput(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid time range")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.ExportAuditEventsInput{
+		AuditQueryInput: query,
+		ExportedBy:      principal.Email,
+		IP:              middleware.ClientIPFromContext(r.Context()),
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidTimeRange:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when exporting audit events")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when exporting audit events")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success exporting audit events")
+
+	records := [][]string{{"timestamp", "action", "actor", "target", "ip", "outcome"}}
+	for _, event := range output.Data {
+		records = append(records, []string{
+			event.Timestamp.UTC().Format(time.RFC3339),
+			csvField(event.Action),
+			csvField(event.Actor),
+			csvField(event.Target),
+			csvField(event.IP),
+			csvField(event.Outcome),
+		})
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(output.TotalCount))
+	response.NewCSV(records, "audit_events.csv", http.StatusOK).Send(w)
+}
+
+// csvField keeps spreadsheets from evaluating user controlled values, such as
+// an email typed at registration, as formulas
+func csvField(value string) string {
+	if value != "" && strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,109 @@
+package action
+
+import (
+	"chat-api/adapter/api/middleware"
+	"chat-api/domain"
+	"chat-api/infrastructure/log"
+	"chat-api/usecase"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockExportAuditEvents struct {
+	result usecase.ExportAuditEventsOutput
+	err    error
+	input  *usecase.ExportAuditEventsInput
+}
+
+func (m mockExportAuditEvents) Execute(_ context.Context, input usecase.ExportAuditEventsInput) (usecase.ExportAuditEventsOutput, error) {
+	if m.input != nil {
+		*m.input = input
+	}
+	return m.result, m.err
+}
+
+func TestExportAuditEventsAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		url                string
+		ucMock             mockExportAuditEvents
+		expectedBody       string
+		expectedStatusCode int
+		expectedInput      usecase.ExportAuditEventsInput
+	}{
+		{
+			name: "export writes one row per event",
+			url:  "/audit/export?actor=admin@email.com&from=2021-03-01T00:00:00Z&to=2021-03-02T00:00:00Z",
+			ucMock: mockExportAuditEvents{result: usecase.ExportAuditEventsOutput{TotalCount: 2, Data: []usecase.AuditEventOutput{
+				{Action: domain.AuditChannelClaimed, Actor: "admin@email.com", Target: "60f1", IP: "10.0.0.1", Outcome: domain.AuditOutcomeSuccess, Timestamp: timestamp},
+				{Action: domain.AuditLogin, Actor: "=HYPERLINK(\"x\")", Target: "=HYPERLINK(\"x\")", IP: "10.0.0.2", Outcome: domain.AuditOutcomeFailure, Timestamp: timestamp},
+			}}},
+			expectedBody: "timestamp,action,actor,target,ip,outcome\n" +
+				"2021-03-01T10:00:00Z,CHANNEL_CLAIMED,admin@email.com,60f1,10.0.0.1,SUCCESS\n" +
+				"2021-03-01T10:00:00Z,LOGIN,\"'=HYPERLINK(\"\"x\"\")\",\"'=HYPERLINK(\"\"x\"\")\",10.0.0.2,FAILURE",
+			expectedStatusCode: http.StatusOK,
+			expectedInput: usecase.ExportAuditEventsInput{
+				AuditQueryInput: usecase.AuditQueryInput{
+					Actor: "admin@email.com",
+					From:  time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+					To:    time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+				},
+				ExportedBy: "root@email.com",
+			},
+		},
+		{
+			name:               "malformed time range refused",
+			url:                "/audit/export?from=yesterday",
+			expectedBody:       `{"errors":[{"code":400,"message":"from must be an RFC 3339 timestamp","type":"input_error"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "inverted time range refused",
+			url:                "/audit/export?from=2021-03-02T00:00:00Z&to=2021-03-01T00:00:00Z",
+			ucMock:             mockExportAuditEvents{err: domain.ErrInvalidTimeRange},
+			expectedBody:       `{"errors":[{"code":400,"message":"time range ends before it starts","type":"input_error"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			req = req.WithContext(middleware.WithPrincipal(req.Context(), middleware.Principal{Email: "root@email.com", Role: domain.ADMIN}))
+
+			var (
+				input  usecase.ExportAuditEventsInput
+				w      = httptest.NewRecorder()
+				ucMock = tt.ucMock
+			)
+			ucMock.input = &input
+			NewExportAuditEventsAction(ucMock, log.LoggerMock{}).Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf(
+					"[TestCase '%s'] HTTP handler returned wrong statusCode: recieved '%v' expected '%v'",
+					tt.name,
+					w.Code,
+					tt.expectedStatusCode,
+				)
+			}
+
+			var result = strings.TrimSpace(w.Body.String())
+			if result != tt.expectedBody {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expectedBody)
+			}
+
+			if tt.expectedStatusCode == http.StatusOK && input != tt.expectedInput {
+				t.Errorf("[TestCase '%s'] Input: '%v' | Expected: '%v'", tt.name, input, tt.expectedInput)
+			}
+		})
+	}
+}
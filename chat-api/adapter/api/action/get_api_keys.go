@@ -0,0 +1,42 @@
+package action
+
+import (
+	"net/http"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/usecase"
+)
+
+type GetAPIKeysAction struct {
+	uc  usecase.GetAPIKeysUseCase
+	log logger.Logger
+}
+
+func NewGetAPIKeysAction(uc usecase.GetAPIKeysUseCase, log logger.Logger) GetAPIKeysAction {
+	return GetAPIKeysAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a GetAPIKeysAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_api_keys"
+
+	output, err := a.uc.Execute(r.Context())
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusInternalServerError,
+		).Log("error when getting api keys")
+
+		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success getting api keys")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
@@ -0,0 +1,71 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type GetAttachmentURLAction struct {
+	uc        usecase.GetAttachmentURLUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewGetAttachmentURLAction(uc usecase.GetAttachmentURLUseCase, log logger.Logger, v validator.Validator) GetAttachmentURLAction {
+	return GetAttachmentURLAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a GetAttachmentURLAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_attachment_url"
+
+	input := usecase.GetAttachmentURLInput{
+		ChannelId:    r.URL.Query().Get("channelId"),
+		AttachmentId: r.URL.Query().Get("attachmentId"),
+	}
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.RequestedBy = principal.Email
+		input.Internal = domain.SeesInternalNotes(principal.Role)
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		attachmentError(a.log, w, err, logKey, "error when signing attachment url")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success signing attachment url")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a GetAttachmentURLAction) validateInput(input usecase.GetAttachmentURLInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
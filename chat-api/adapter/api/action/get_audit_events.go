@@ -0,0 +1,135 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+const (
+	defaultAuditEventsPage  = 1
+	defaultAuditEventsLimit = 20
+)
+
+type GetAuditEventsAction struct {
+	uc        usecase.GetAuditEventsUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewGetAuditEventsAction(uc usecase.GetAuditEventsUseCase, log logger.Logger, v validator.Validator) GetAuditEventsAction {
+	return GetAuditEventsAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a GetAuditEventsAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_audit_events"
+
+	query, err := auditQueryInput(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid time range")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	input := usecase.GetAuditEventsInput{
+		AuditQueryInput: query,
+		Page:            defaultAuditEventsPage,
+		Limit:           defaultAuditEventsLimit,
+	}
+	if page := r.URL.Query().Get("page"); page != "" {
+		input.Page, _ = strconv.Atoi(page)
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		input.Limit, _ = strconv.Atoi(limit)
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidTimeRange:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when returning audit events")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when returning audit events")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success returning audit events")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a GetAuditEventsAction) validateInput(input usecase.GetAuditEventsInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
+
+// auditQueryInput reads the actor, target and RFC 3339 time range filters shared by the audit endpoints
+func auditQueryInput(r *http.Request) (usecase.AuditQueryInput, error) {
+	input := usecase.AuditQueryInput{
+		Actor:  strings.TrimSpace(r.URL.Query().Get("actor")),
+		Target: strings.TrimSpace(r.URL.Query().Get("target")),
+	}
+
+	var err error
+	if from := r.URL.Query().Get("from"); from != "" {
+		if input.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return usecase.AuditQueryInput{}, errors.New("from must be an RFC 3339 timestamp")
+		}
+	}
+	if to := r.URL.Query().Get("to"); to != "" {
+		if input.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return usecase.AuditQueryInput{}, errors.New("to must be an RFC 3339 timestamp")
+		}
+	}
+	return input, nil
+}
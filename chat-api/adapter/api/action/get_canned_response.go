@@ -0,0 +1,67 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/usecase"
+)
+
+type GetCannedResponseAction struct {
+	uc        usecase.GetCannedResponseUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewGetCannedResponseAction(uc usecase.GetCannedResponseUseCase, log logger.Logger, v validator.Validator) GetCannedResponseAction {
+	return GetCannedResponseAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a GetCannedResponseAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_canned_response"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.GetCannedResponseInput{
+		Id:  r.URL.Query().Get("id"),
+		Rep: principal.Email,
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		cannedResponseError(a.log, w, err, logKey, "error when getting canned response")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success getting canned response")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a GetCannedResponseAction) validateInput(input usecase.GetCannedResponseInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
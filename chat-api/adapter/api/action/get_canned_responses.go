@@ -0,0 +1,68 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/usecase"
+)
+
+type GetCannedResponsesAction struct {
+	uc        usecase.GetCannedResponsesUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewGetCannedResponsesAction(uc usecase.GetCannedResponsesUseCase, log logger.Logger, v validator.Validator) GetCannedResponsesAction {
+	return GetCannedResponsesAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a GetCannedResponsesAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_canned_responses"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.GetCannedResponsesInput{
+		Shortcut: r.URL.Query().Get("shortcut"),
+		Tag:      r.URL.Query().Get("tag"),
+		Rep:      principal.Email,
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		cannedResponseError(a.log, w, err, logKey, "error when getting canned responses")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success getting canned responses")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a GetCannedResponsesAction) validateInput(input usecase.GetCannedResponsesInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type GetChannelAnalyticsAction struct {
+	uc        usecase.GetChannelAnalyticsUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewGetChannelAnalyticsAction(uc usecase.GetChannelAnalyticsUseCase, log logger.Logger, v validator.Validator) GetChannelAnalyticsAction {
+	return GetChannelAnalyticsAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+// Execute reads the interval, the RFC 3339 time range and the timeZone and tag filters from the query
+func (a GetChannelAnalyticsAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_channel_analytics"
+
+	input, err := analyticsInput(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid time range")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	if err := validateAnalyticsInput(a.validator, input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidTimeRange, domain.ErrReportRangeTooLong, domain.ErrHourlyRangeTooLong, domain.ErrUnknownTimeZone:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when returning channel analytics")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when returning channel analytics")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success returning channel analytics")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+// analyticsInput shares the time range filters of the audit endpoints
+func analyticsInput(r *http.Request) (usecase.AnalyticsInput, error) {
+	query, err := auditQueryInput(r)
+	if err != nil {
+		return usecase.AnalyticsInput{}, err
+	}
+
+	return usecase.AnalyticsInput{
+		Interval: strings.TrimSpace(r.URL.Query().Get("interval")),
+		From:     query.From,
+		To:       query.To,
+		TimeZone: strings.TrimSpace(r.URL.Query().Get("timeZone")),
+		Tag:      strings.TrimSpace(r.URL.Query().Get("tag")),
+	}, nil
+}
+
+func validateAnalyticsInput(v validator.Validator, input usecase.AnalyticsInput) error {
+	err := v.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(v.Messages(), ","))
+	}
+	return nil
+}
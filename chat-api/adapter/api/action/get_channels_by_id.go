@@ -6,9 +6,11 @@ import (
 	"strings"
 
 	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
 	"chat-api/adapter/api/response"
 	"chat-api/adapter/logger"
 	"chat-api/adapter/validator"
+	"chat-api/domain"
 	"chat-api/usecase"
 )
 
@@ -34,6 +36,9 @@ func (a GetChannelByIdAction) Execute(w http.ResponseWriter, r *http.Request) {
 	input := usecase.GetChannelByIdInput{
 		Id: channelID,
 	}
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.Internal = domain.SeesInternalNotes(principal.Role)
+	}
 
 	output, err := a.uc.Execute(r.Context(), input)
 	if err != nil {
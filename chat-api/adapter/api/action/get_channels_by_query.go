@@ -1,17 +1,25 @@
 package action
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
 	"chat-api/adapter/api/response"
 	"chat-api/adapter/logger"
 	"chat-api/adapter/validator"
 	"chat-api/domain"
 	"chat-api/usecase"
+)
 
-	"go.mongodb.org/mongo-driver/bson"
+const (
+	defaultChannelsPage  = 1
+	defaultChannelsLimit = 10
 )
 
 type GetChannelsByQueryAction struct {
@@ -20,41 +28,59 @@ type GetChannelsByQueryAction struct {
 	validator validator.Validator
 }
 
-func NewGetChannelsByQueryAction(uc usecase.GetChannelByQueryUseCase, log logger.Logger) GetChannelsByQueryAction {
+func NewGetChannelsByQueryAction(uc usecase.GetChannelByQueryUseCase, log logger.Logger, v validator.Validator) GetChannelsByQueryAction {
 	return GetChannelsByQueryAction{
-		uc:  uc,
-		log: log,
+		uc:        uc,
+		log:       log,
+		validator: v,
 	}
 }
 
 func (a GetChannelsByQueryAction) Execute(w http.ResponseWriter, r *http.Request) {
 	const logKey = "get_channels_by_query"
 
-	query := bson.M{}
-
-	var (
-		limit string
-		page  string
-	)
-
-	if len(r.URL.Query().Get("userEmail")) > 0 {
-		query["userEmail"] = fmt.Sprintf("%s", r.URL.Query().Get("userEmail"))
+	input, err := channelQueryInput(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid query parameter")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
 	}
-
-	if len(r.URL.Query().Get("repEmail")) > 0 {
-		query["repEmail"] = fmt.Sprintf("%s", r.URL.Query().Get("repEmail"))
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.Reader = principal.Email
+		input.Internal = domain.SeesInternalNotes(principal.Role)
 	}
 
-	if len(r.URL.Query().Get("currentStatus")) > 0 {
-		query["currentStatus"] = fmt.Sprintf("%s", r.URL.Query().Get("currentStatus"))
-	}
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
 
-	page = r.URL.Query().Get("page")
-	limit = r.URL.Query().Get("limit")
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
 
-	output, err := a.uc.Execute(r.Context(), query, limit, page)
+	output, err := a.uc.Execute(r.Context(), input)
 	if err != nil {
 		switch err {
+		case domain.ErrInvalidTimeRange, domain.ErrUnassignedChannelsHaveRep, domain.ErrInvalidCursor, domain.ErrInvalidAttribute:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error fetching channels")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
 		case domain.ErrUserNotFound:
 			logging.NewError(
 				a.log,
@@ -82,15 +108,85 @@ func (a GetChannelsByQueryAction) Execute(w http.ResponseWriter, r *http.Request
 	response.NewSuccess(output, http.StatusOK).Send(w)
 }
 
-// func (a FindAllUserAction) validateInput(input usecase.FindRelationshipChildrenInput) []string {
-// 	var msgs []string
+func (a GetChannelsByQueryAction) validateInput(input usecase.GetChannelByQueryInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
 
-// 	err := a.validator.Validate(input)
-// 	if err != nil {
-// 		for _, msg := range a.validator.Messages() {
-// 			msgs = append(msgs, msg)
-// 		}
-// 	}
+// channelQueryInput reads the listing parameters, currentStatus, tag and priority
+// take comma separated lists, attributes are matched with attr.<name>=<value> and
+// the time ranges take RFC 3339 timestamps. A cursor replaces the page number
+func channelQueryInput(r *http.Request) (usecase.GetChannelByQueryInput, error) {
+	var (
+		params = r.URL.Query()
+		input  = usecase.GetChannelByQueryInput{
+			UserEmail: strings.TrimSpace(params.Get("userEmail")),
+			RepEmail:  strings.TrimSpace(params.Get("repEmail")),
+			Sort:      params.Get("sort"),
+			Order:     params.Get("order"),
+			Cursor:    params.Get("cursor"),
+			Page:      defaultChannelsPage,
+			Limit:     defaultChannelsLimit,
+		}
+		err error
+	)
+
+	input.Statuses = commaSeparated(params.Get("currentStatus"))
+	input.Tags = commaSeparated(params.Get("tag"))
+	input.Priorities = commaSeparated(strings.ToUpper(params.Get("priority")))
+
+	for param, values := range params {
+		if name := strings.TrimPrefix(param, "attr."); name != param && len(values) > 0 {
+			if input.Attributes == nil {
+				input.Attributes = make(map[string]string)
+			}
+			input.Attributes[name] = values[0]
+		}
+	}
+
+	if unassigned := params.Get("unassigned"); unassigned != "" {
+		if input.Unassigned, err = strconv.ParseBool(unassigned); err != nil {
+			return usecase.GetChannelByQueryInput{}, errors.New("unassigned must be true or false")
+		}
+	}
+
+	for name, field := range map[string]*time.Time{
+		"createdFrom": &input.CreatedFrom,
+		"createdTo":   &input.CreatedTo,
+		"updatedFrom": &input.UpdatedFrom,
+		"updatedTo":   &input.UpdatedTo,
+	} {
+		if value := params.Get(name); value != "" {
+			if *field, err = time.Parse(time.RFC3339, value); err != nil {
+				return usecase.GetChannelByQueryInput{}, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+			}
+		}
+	}
 
-// 	return msgs
-// }
+	if input.Cursor != "" && params.Get("page") != "" {
+		return usecase.GetChannelByQueryInput{}, errors.New("page and cursor cannot be combined")
+	}
+
+	// Values that are not numbers become zero and fail validation
+	if page := params.Get("page"); page != "" {
+		input.Page, _ = strconv.Atoi(page)
+	}
+	if limit := params.Get("limit"); limit != "" {
+		input.Limit, _ = strconv.Atoi(limit)
+	}
+
+	return input, nil
+}
+
+func commaSeparated(param string) []string {
+	var values []string
+	for _, value := range strings.Split(param, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,132 @@
+package action
+
+import (
+	"chat-api/domain"
+	"chat-api/infrastructure/log"
+	"chat-api/infrastructure/validation"
+	"chat-api/usecase"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockGetChannelsByQuery struct {
+	err   error
+	input *usecase.GetChannelByQueryInput
+}
+
+func (m mockGetChannelsByQuery) Execute(_ context.Context, input usecase.GetChannelByQueryInput) (usecase.GetChannelByQueryOutput, error) {
+	*m.input = input
+	return usecase.GetChannelByQueryOutput{Page: input.Page, Limit: input.Limit}, m.err
+}
+
+func TestGetChannelsByQueryAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	tests := []struct {
+		name               string
+		url                string
+		ucErr              error
+		expectedStatusCode int
+		expectedInput      usecase.GetChannelByQueryInput
+	}{
+		{
+			name:               "defaults",
+			url:                "/channel",
+			expectedStatusCode: http.StatusOK,
+			expectedInput:      usecase.GetChannelByQueryInput{Page: 1, Limit: 10},
+		},
+		{
+			name: "every filter",
+			url: "/channel?userEmail=user@email.com&currentStatus=ACTIVE,IN_PROGRESS&unassigned=true" +
+				"&createdFrom=2021-03-01T00:00:00Z&createdTo=2021-03-02T00:00:00Z&updatedFrom=2021-03-01T12:00:00Z" +
+				"&sort=lastMessageAt&order=desc&page=2&limit=50",
+			expectedStatusCode: http.StatusOK,
+			expectedInput: usecase.GetChannelByQueryInput{
+				UserEmail:   "user@email.com",
+				Statuses:    []string{domain.ACTIVE, domain.IN_PROGRESS},
+				Unassigned:  true,
+				CreatedFrom: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+				CreatedTo:   time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+				UpdatedFrom: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+				Sort:        domain.ChannelSortLastMessageAt,
+				Order:       "desc",
+				Page:        2,
+				Limit:       50,
+			},
+		},
+		{
+			name:               "tags, priorities and attributes",
+			url:                "/channel?tag=billing,vip&priority=high,URGENT&attr.plan=pro&attr.orderId=A-1&sort=priority&order=desc",
+			expectedStatusCode: http.StatusOK,
+			expectedInput: usecase.GetChannelByQueryInput{
+				Tags:       []string{"billing", "vip"},
+				Priorities: []string{domain.PriorityHigh, domain.PriorityUrgent},
+				Attributes: map[string]string{"plan": "pro", "orderId": "A-1"},
+				Sort:       domain.ChannelSortPriority,
+				Order:      "desc",
+				Page:       1,
+				Limit:      10,
+			},
+		},
+		{name: "unknown priority", url: "/channel?priority=critical", expectedStatusCode: http.StatusBadRequest},
+		{
+			name:               "cursor",
+			url:                "/channel?cursor=abc.def&limit=20",
+			expectedStatusCode: http.StatusOK,
+			expectedInput:      usecase.GetChannelByQueryInput{Cursor: "abc.def", Page: 1, Limit: 20},
+		},
+		{name: "cursor with a page", url: "/channel?cursor=abc.def&page=2", expectedStatusCode: http.StatusBadRequest},
+		{
+			name:               "cursor refused by the use case",
+			url:                "/channel?cursor=abc.def",
+			ucErr:              domain.ErrInvalidCursor,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedInput:      usecase.GetChannelByQueryInput{Cursor: "abc.def", Page: 1, Limit: 10},
+		},
+		{name: "page that is not a number", url: "/channel?page=abc", expectedStatusCode: http.StatusBadRequest},
+		{name: "negative page", url: "/channel?page=-1", expectedStatusCode: http.StatusBadRequest},
+		{name: "limit above the maximum", url: "/channel?limit=51", expectedStatusCode: http.StatusBadRequest},
+		{name: "unknown sort", url: "/channel?sort=userEmail", expectedStatusCode: http.StatusBadRequest},
+		{name: "unknown order", url: "/channel?order=up", expectedStatusCode: http.StatusBadRequest},
+		{name: "unknown status", url: "/channel?currentStatus=ACTIVE,OPEN", expectedStatusCode: http.StatusBadRequest},
+		{name: "malformed date", url: "/channel?createdFrom=yesterday", expectedStatusCode: http.StatusBadRequest},
+		{name: "malformed flag", url: "/channel?unassigned=maybe", expectedStatusCode: http.StatusBadRequest},
+		{
+			name:               "range refused by the use case",
+			url:                "/channel?createdFrom=2021-03-02T00:00:00Z&createdTo=2021-03-01T00:00:00Z",
+			ucErr:              domain.ErrInvalidTimeRange,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedInput: usecase.GetChannelByQueryInput{
+				CreatedFrom: time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+				CreatedTo:   time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+				Page:        1,
+				Limit:       10,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				input usecase.GetChannelByQueryInput
+				req   = httptest.NewRequest(http.MethodGet, tt.url, nil)
+				w     = httptest.NewRecorder()
+			)
+
+			NewGetChannelsByQueryAction(mockGetChannelsByQuery{err: tt.ucErr, input: &input}, log.LoggerMock{}, validator).Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+			if !reflect.DeepEqual(input, tt.expectedInput) {
+				t.Errorf("[TestCase '%s'] Input: '%v' | Expected: '%v'", tt.name, input, tt.expectedInput)
+			}
+		})
+	}
+}
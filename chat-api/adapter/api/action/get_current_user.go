@@ -0,0 +1,72 @@
+package action
+
+import (
+	"net/http"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type GetCurrentUserAction struct {
+	uc  usecase.GetCurrentUserUseCase
+	log logger.Logger
+}
+
+func NewGetCurrentUserAction(uc usecase.GetCurrentUserUseCase, log logger.Logger) GetCurrentUserAction {
+	return GetCurrentUserAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a GetCurrentUserAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_current_user"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.GetUserByEmailInput{
+		Email: principal.Email,
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrUserDeactivated:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusForbidden,
+			).Log("error when fetching current user")
+
+			response.NewError("user_deactivated", http.StatusForbidden, err, "").Send(w)
+			return
+		case domain.ErrUserNotFound:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusNotFound,
+			).Log("error when fetching current user")
+
+			response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when fetching current user")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("successfully fetched current user")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
@@ -0,0 +1,63 @@
+package action
+
+import (
+	"net/http"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type GetGuestSessionAction struct {
+	uc  usecase.GetGuestSessionUseCase
+	log logger.Logger
+}
+
+func NewGetGuestSessionAction(uc usecase.GetGuestSessionUseCase, log logger.Logger) GetGuestSessionAction {
+	return GetGuestSessionAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a GetGuestSessionAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_guest_session"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.GetGuestSessionInput{
+		Email:     principal.Email,
+		ChannelId: principal.ChannelId,
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrGuestSessionInvalid:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusForbidden,
+			).Log("error when fetching guest session")
+
+			response.NewError("forbidden", http.StatusForbidden, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when fetching guest session")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("successfully fetched guest session")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
@@ -0,0 +1,42 @@
+package action
+
+import (
+	"net/http"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/usecase"
+)
+
+type GetOrganizationAction struct {
+	uc  usecase.GetOrganizationUseCase
+	log logger.Logger
+}
+
+func NewGetOrganizationAction(uc usecase.GetOrganizationUseCase, log logger.Logger) GetOrganizationAction {
+	return GetOrganizationAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a GetOrganizationAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_organization"
+
+	output, err := a.uc.Execute(r.Context())
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusInternalServerError,
+		).Log("error when getting organization")
+
+		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success getting organization")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
@@ -0,0 +1,85 @@
+package action
+
+import (
+	"net/http"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type GetRepAnalyticsAction struct {
+	uc        usecase.GetRepAnalyticsUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewGetRepAnalyticsAction(uc usecase.GetRepAnalyticsUseCase, log logger.Logger, v validator.Validator) GetRepAnalyticsAction {
+	return GetRepAnalyticsAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+// Execute reads the interval, the RFC 3339 time range and the timeZone and tag filters from the query
+func (a GetRepAnalyticsAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_rep_analytics"
+
+	input, err := analyticsInput(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid time range")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	if err := validateAnalyticsInput(a.validator, input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidTimeRange, domain.ErrReportRangeTooLong, domain.ErrHourlyRangeTooLong, domain.ErrUnknownTimeZone:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when returning rep analytics")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when returning rep analytics")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success returning rep analytics")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
@@ -0,0 +1,104 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type GetSatisfactionReportAction struct {
+	uc        usecase.GetSatisfactionReportUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewGetSatisfactionReportAction(uc usecase.GetSatisfactionReportUseCase, log logger.Logger, v validator.Validator) GetSatisfactionReportAction {
+	return GetSatisfactionReportAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+// Execute reads the grouping, the RFC 3339 time range and the repEmail and tag filters from the query
+func (a GetSatisfactionReportAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_satisfaction_report"
+
+	// The report shares the time range filters of the audit endpoints
+	query, err := auditQueryInput(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid time range")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	input := usecase.GetSatisfactionReportInput{
+		GroupBy:  r.URL.Query().Get("groupBy"),
+		From:     query.From,
+		To:       query.To,
+		RepEmail: strings.TrimSpace(r.URL.Query().Get("repEmail")),
+		Tag:      strings.TrimSpace(r.URL.Query().Get("tag")),
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidTimeRange, domain.ErrReportRangeTooLong, domain.ErrUnknownReportGroup:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when returning satisfaction report")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when returning satisfaction report")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success returning satisfaction report")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a GetSatisfactionReportAction) validateInput(input usecase.GetSatisfactionReportInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/usecase"
+)
+
+const (
+	defaultUsersPage  = 1
+	defaultUsersLimit = 10
+)
+
+type GetUsersAction struct {
+	uc        usecase.GetUsersUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewGetUsersAction(uc usecase.GetUsersUseCase, log logger.Logger, v validator.Validator) GetUsersAction {
+	return GetUsersAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a GetUsersAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "get_users"
+
+	input := usecase.GetUsersInput{
+		Role:  r.URL.Query().Get("role"),
+		Page:  defaultUsersPage,
+		Limit: defaultUsersLimit,
+	}
+
+	if page := r.URL.Query().Get("page"); page != "" {
+		input.Page, _ = strconv.Atoi(page)
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		input.Limit, _ = strconv.Atoi(limit)
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusInternalServerError,
+		).Log("error when returning users")
+
+		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success returning users")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a GetUsersAction) validateInput(input usecase.GetUsersInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
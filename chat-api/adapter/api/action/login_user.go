@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
 	"chat-api/adapter/api/response"
 	"chat-api/adapter/logger"
 	"chat-api/adapter/validator"
+	"chat-api/domain"
 	"chat-api/usecase"
 )
 
@@ -55,19 +57,68 @@ func (a LoginUserAction) Execute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
 	output, err := a.uc.Execute(r.Context(), input)
 	if err != nil {
-		logging.NewError(
-			a.log,
-			err,
-			logKey,
-			http.StatusBadRequest,
-		).Log("error when logging in")
+		switch err {
+		case domain.ErrAccountLocked:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusLocked,
+			).Log("login refused, account locked")
 
-		// response.NewError(err, http.StatusInternalServerError).Send(w)
-		response.NewError("internal_server_error", http.StatusBadRequest, err, "").Send(w)
+			response.NewError("account_locked", http.StatusLocked, err, "").Send(w)
+			return
+		case domain.ErrTooManyLoginAttempts:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusTooManyRequests,
+			).Log("login refused, too many attempts")
 
-		return
+			response.NewError("too_many_requests", http.StatusTooManyRequests, err, "").Send(w)
+			return
+		case domain.ErrUserDeactivated:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusForbidden,
+			).Log("login refused, user deactivated")
+
+			response.NewError("user_deactivated", http.StatusForbidden, err, "").Send(w)
+			return
+		case domain.ErrTwoFactorRequired:
+			logging.NewInfo(a.log, logKey, http.StatusUnauthorized).Log("two factor code required")
+
+			response.NewError("two_factor_required", http.StatusUnauthorized, err, "").Send(w)
+			return
+		case domain.ErrInvalidTwoFactorCode:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusUnauthorized,
+			).Log("login refused, invalid two factor code")
+
+			response.NewError("invalid_two_factor_code", http.StatusUnauthorized, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when logging in")
+
+			// response.NewError(err, http.StatusInternalServerError).Send(w)
+			response.NewError("internal_server_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		}
 	}
 	logging.NewInfo(a.log, logKey, http.StatusOK).Log("login successful")
 
@@ -2,6 +2,7 @@ package action
 
 import (
 	"bytes"
+	"chat-api/domain"
 	"chat-api/infrastructure/log"
 	"chat-api/infrastructure/validation"
 	"chat-api/usecase"
@@ -91,6 +92,36 @@ func TestLoginUserAction_Execute(t *testing.T) {
 			expectedBody:       `{"errors":[{"code":400,"message":"Email is a required field","type":"input_error"}]}`,
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			name: "error account locked",
+			args: args{
+				rawPayload: []byte(`{
+					"email": "user_email@gmail.com",
+					"password": "supersecurepassword"
+					}`),
+			},
+			ucMock: mockLoginUser{
+				result: usecase.LoginUserOutput{},
+				err:    domain.ErrAccountLocked,
+			},
+			expectedBody:       `{"errors":[{"code":423,"message":"account temporarily locked due to too many failed login attempts","type":"account_locked"}]}`,
+			expectedStatusCode: http.StatusLocked,
+		},
+		{
+			name: "error too many attempts",
+			args: args{
+				rawPayload: []byte(`{
+					"email": "user_email@gmail.com",
+					"password": "supersecurepassword"
+					}`),
+			},
+			ucMock: mockLoginUser{
+				result: usecase.LoginUserOutput{},
+				err:    domain.ErrTooManyLoginAttempts,
+			},
+			expectedBody:       `{"errors":[{"code":429,"message":"too many failed login attempts, try again later","type":"too_many_requests"}]}`,
+			expectedStatusCode: http.StatusTooManyRequests,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,85 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type MarkChannelReadAction struct {
+	uc        usecase.MarkChannelReadUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewMarkChannelReadAction(uc usecase.MarkChannelReadUseCase, log logger.Logger, v validator.Validator) MarkChannelReadAction {
+	return MarkChannelReadAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+// Execute reads the optional messageId from the body, without one the whole channel is read
+func (a MarkChannelReadAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "mark_channel_read"
+
+	var input usecase.MarkChannelReadInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input.ChannelId = r.URL.Query().Get("channelId")
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.Reader = principal.Email
+		input.Admin = principal.Role == domain.ADMIN
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		messageChangeError(a.log, w, err, logKey, "error when marking channel read")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success marking channel read")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a MarkChannelReadAction) validateInput(input usecase.MarkChannelReadInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
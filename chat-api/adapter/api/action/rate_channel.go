@@ -0,0 +1,104 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type RateChannelAction struct {
+	uc        usecase.RateChannelUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewRateChannelAction(uc usecase.RateChannelUseCase, log logger.Logger, v validator.Validator) RateChannelAction {
+	return RateChannelAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+// Execute stores the answer of the customer to the survey of a completed channel
+func (a RateChannelAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "rate_channel"
+
+	var input usecase.RateChannelInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input.ChannelId = r.URL.Query().Get("channelId")
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.RatedBy = principal.Email
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidRating:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when rating channel")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		case domain.ErrChannelNotComplete, domain.ErrAlreadyRated:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusConflict,
+			).Log("error when rating channel")
+
+			response.NewError("conflict", http.StatusConflict, err, "").Send(w)
+		default:
+			messageChangeError(a.log, w, err, logKey, "error when rating channel")
+		}
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success rating channel")
+
+	response.NewSuccess(output, http.StatusCreated).Send(w)
+}
+
+func (a RateChannelAction) validateInput(input usecase.RateChannelInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
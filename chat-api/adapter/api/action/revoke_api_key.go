@@ -0,0 +1,90 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type RevokeAPIKeyAction struct {
+	uc        usecase.RevokeAPIKeyUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewRevokeAPIKeyAction(uc usecase.RevokeAPIKeyUseCase, log logger.Logger, v validator.Validator) RevokeAPIKeyAction {
+	return RevokeAPIKeyAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a RevokeAPIKeyAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "revoke_api_key"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.RevokeAPIKeyInput{
+		Id:        r.URL.Query().Get("id"),
+		RevokedBy: principal.Email,
+		IP:        middleware.ClientIPFromContext(r.Context()),
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrAPIKeyNotFound:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusNotFound,
+			).Log("error when revoking api key")
+
+			response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when revoking api key")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success revoking api key")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a RevokeAPIKeyAction) validateInput(input usecase.RevokeAPIKeyInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
@@ -0,0 +1,139 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type SearchChannelsAction struct {
+	uc        usecase.SearchChannelsUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewSearchChannelsAction(uc usecase.SearchChannelsUseCase, log logger.Logger, v validator.Validator) SearchChannelsAction {
+	return SearchChannelsAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a SearchChannelsAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "search_channels"
+
+	input, err := searchChannelsInput(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid query parameter")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrEmptySearch, domain.ErrInvalidTimeRange:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error searching channels")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when searching channels")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success searching channels")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a SearchChannelsAction) validateInput(input usecase.SearchChannelsInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
+
+// searchChannelsInput reads the search text from q, the filters and paging
+// take the same parameters as the channel listing
+func searchChannelsInput(r *http.Request) (usecase.SearchChannelsInput, error) {
+	var (
+		params = r.URL.Query()
+		input  = usecase.SearchChannelsInput{
+			Text:     strings.TrimSpace(params.Get("q")),
+			RepEmail: strings.TrimSpace(params.Get("repEmail")),
+			Page:     defaultChannelsPage,
+			Limit:    defaultChannelsLimit,
+		}
+		err error
+	)
+
+	for _, status := range strings.Split(params.Get("currentStatus"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			input.Statuses = append(input.Statuses, status)
+		}
+	}
+
+	for name, field := range map[string]*time.Time{
+		"createdFrom": &input.CreatedFrom,
+		"createdTo":   &input.CreatedTo,
+	} {
+		if value := params.Get(name); value != "" {
+			if *field, err = time.Parse(time.RFC3339, value); err != nil {
+				return usecase.SearchChannelsInput{}, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+			}
+		}
+	}
+
+	// Values that are not numbers become zero and fail validation
+	if page := params.Get("page"); page != "" {
+		input.Page, _ = strconv.Atoi(page)
+	}
+	if limit := params.Get("limit"); limit != "" {
+		input.Limit, _ = strconv.Atoi(limit)
+	}
+
+	return input, nil
+}
@@ -0,0 +1,90 @@
+package action
+
+import (
+	"chat-api/domain"
+	"chat-api/infrastructure/log"
+	"chat-api/infrastructure/validation"
+	"chat-api/usecase"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockSearchChannels struct {
+	err   error
+	input *usecase.SearchChannelsInput
+}
+
+func (m mockSearchChannels) Execute(_ context.Context, input usecase.SearchChannelsInput) (usecase.SearchChannelsOutput, error) {
+	*m.input = input
+	return usecase.SearchChannelsOutput{Page: input.Page, Limit: input.Limit}, m.err
+}
+
+func TestSearchChannelsAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	tests := []struct {
+		name               string
+		url                string
+		ucErr              error
+		expectedStatusCode int
+		expectedInput      usecase.SearchChannelsInput
+	}{
+		{
+			name:               "defaults",
+			url:                "/search?q=refund+4411",
+			expectedStatusCode: http.StatusOK,
+			expectedInput:      usecase.SearchChannelsInput{Text: "refund 4411", Page: 1, Limit: 10},
+		},
+		{
+			name: "every filter",
+			url: "/search?q=refund&repEmail=rep@email.com&currentStatus=ACTIVE,COMPLETE" +
+				"&createdFrom=2021-03-01T00:00:00Z&createdTo=2021-03-02T00:00:00Z&page=3&limit=20",
+			expectedStatusCode: http.StatusOK,
+			expectedInput: usecase.SearchChannelsInput{
+				Text:        "refund",
+				RepEmail:    "rep@email.com",
+				Statuses:    []string{domain.ACTIVE, domain.COMPLETE},
+				CreatedFrom: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+				CreatedTo:   time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC),
+				Page:        3,
+				Limit:       20,
+			},
+		},
+		{name: "missing text", url: "/search", expectedStatusCode: http.StatusBadRequest},
+		{name: "unknown status", url: "/search?q=refund&currentStatus=OPEN", expectedStatusCode: http.StatusBadRequest},
+		{name: "malformed date", url: "/search?q=refund&createdTo=tomorrow", expectedStatusCode: http.StatusBadRequest},
+		{name: "limit above the maximum", url: "/search?q=refund&limit=100", expectedStatusCode: http.StatusBadRequest},
+		{
+			name:               "text refused by the use case",
+			url:                "/search?q=%3F%21",
+			ucErr:              domain.ErrEmptySearch,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedInput:      usecase.SearchChannelsInput{Text: "?!", Page: 1, Limit: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				input usecase.SearchChannelsInput
+				req   = httptest.NewRequest(http.MethodGet, tt.url, nil)
+				w     = httptest.NewRecorder()
+			)
+
+			NewSearchChannelsAction(mockSearchChannels{err: tt.ucErr, input: &input}, log.LoggerMock{}, validator).Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+			if !reflect.DeepEqual(input, tt.expectedInput) {
+				t.Errorf("[TestCase '%s'] Input: '%v' | Expected: '%v'", tt.name, input, tt.expectedInput)
+			}
+		})
+	}
+}
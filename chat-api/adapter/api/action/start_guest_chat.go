@@ -0,0 +1,107 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type StartGuestChatAction struct {
+	uc        usecase.StartGuestChatUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewStartGuestChatAction(uc usecase.StartGuestChatUseCase, log logger.Logger, v validator.Validator) StartGuestChatAction {
+	return StartGuestChatAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a StartGuestChatAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "start_guest_chat"
+
+	var input usecase.StartGuestChatInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrUserAlreadyExists:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusConflict,
+			).Log("error when starting guest chat")
+
+			response.NewError("conflict", http.StatusConflict, err, "").Send(w)
+			return
+		case domain.ErrInvalidAttribute:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when starting guest chat")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when starting guest chat")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success starting guest chat")
+
+	response.NewSuccess(output, http.StatusCreated).Send(w)
+}
+
+func (a StartGuestChatAction) validateInput(input usecase.StartGuestChatInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
@@ -0,0 +1,56 @@
+package action
+
+import (
+	"net/http"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type StartSSOLoginAction struct {
+	uc  usecase.StartSSOLoginUseCase
+	log logger.Logger
+}
+
+func NewStartSSOLoginAction(uc usecase.StartSSOLoginUseCase, log logger.Logger) StartSSOLoginAction {
+	return StartSSOLoginAction{
+		uc:  uc,
+		log: log,
+	}
+}
+
+func (a StartSSOLoginAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "start_sso_login"
+
+	output, err := a.uc.Execute(r.Context())
+	if err != nil {
+		switch err {
+		case domain.ErrSSONotConfigured:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusNotFound,
+			).Log("error when starting sso login")
+
+			response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when starting sso login")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success starting sso login")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
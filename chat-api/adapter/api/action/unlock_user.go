@@ -0,0 +1,90 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type UnlockUserAction struct {
+	uc        usecase.UnlockUserUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewUnlockUserAction(uc usecase.UnlockUserUseCase, log logger.Logger, v validator.Validator) UnlockUserAction {
+	return UnlockUserAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a UnlockUserAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "unlock_user"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.UnlockUserInput{
+		Email:      r.URL.Query().Get("email"),
+		UnlockedBy: principal.Email,
+		IP:         middleware.ClientIPFromContext(r.Context()),
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrUserNotFound:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusNotFound,
+			).Log("error when unlocking user")
+
+			response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when unlocking user")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success unlocking user")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a UnlockUserAction) validateInput(input usecase.UnlockUserInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
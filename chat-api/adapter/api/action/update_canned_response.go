@@ -0,0 +1,80 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/usecase"
+)
+
+type UpdateCannedResponseAction struct {
+	uc        usecase.UpdateCannedResponseUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewUpdateCannedResponseAction(uc usecase.UpdateCannedResponseUseCase, log logger.Logger, v validator.Validator) UpdateCannedResponseAction {
+	return UpdateCannedResponseAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a UpdateCannedResponseAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "update_canned_response"
+
+	var input usecase.UpdateCannedResponseInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input.Id = r.URL.Query().Get("id")
+	input.UpdatedBy = principal.Email
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		cannedResponseError(a.log, w, err, logKey, "error when updating canned response")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success updating canned response")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a UpdateCannedResponseAction) validateInput(input usecase.UpdateCannedResponseInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
@@ -7,9 +7,11 @@ import (
 	"strings"
 
 	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
 	"chat-api/adapter/api/response"
 	"chat-api/adapter/logger"
 	"chat-api/adapter/validator"
+	"chat-api/domain"
 	"chat-api/usecase"
 )
 
@@ -46,6 +48,12 @@ func (a UpdateChannelStatusAction) Execute(w http.ResponseWriter, r *http.Reques
 	}
 	defer r.Body.Close()
 
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.Actor = principal.Email
+		input.Admin = principal.Role == domain.ADMIN
+	}
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
 	if err := a.validateInput(input); err != nil {
 		logging.NewError(
 			a.log,
@@ -60,16 +68,27 @@ func (a UpdateChannelStatusAction) Execute(w http.ResponseWriter, r *http.Reques
 
 	output, err := a.uc.Execute(r.Context(), input)
 	if err != nil {
-		logging.NewError(
-			a.log,
-			err,
-			logKey,
-			http.StatusInternalServerError,
-		).Log("error when updating channel status")
+		switch err {
+		case domain.ErrChannelTakenByAdmin, domain.ErrNotChannelParticipant:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusForbidden,
+			).Log("error when updating channel status")
 
-		// response.NewError(err, http.StatusInternalServerError).Send(w)
-		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			response.NewError("forbidden", http.StatusForbidden, err, "").Send(w)
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when updating channel status")
 
+			// response.NewError(err, http.StatusInternalServerError).Send(w)
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+		}
 		return
 	}
 	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success updating channel status")
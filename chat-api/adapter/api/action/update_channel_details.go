@@ -0,0 +1,94 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type UpdateChannelDetailsAction struct {
+	uc        usecase.UpdateChannelDetailsUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewUpdateChannelDetailsAction(uc usecase.UpdateChannelDetailsUseCase, log logger.Logger, v validator.Validator) UpdateChannelDetailsAction {
+	return UpdateChannelDetailsAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a UpdateChannelDetailsAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "update_channel_details"
+
+	var input usecase.UpdateChannelDetailsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	input.ChannelId = r.URL.Query().Get("channelId")
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.UpdatedBy = principal.Email
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidAttribute, domain.ErrInvalidPriority:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when updating channel details")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		default:
+			messageChangeError(a.log, w, err, logKey, "error when updating channel details")
+		}
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success updating channel details")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a UpdateChannelDetailsAction) validateInput(input usecase.UpdateChannelDetailsInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type UpdateOrganizationSettingsAction struct {
+	uc        usecase.UpdateOrganizationSettingsUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewUpdateOrganizationSettingsAction(uc usecase.UpdateOrganizationSettingsUseCase, log logger.Logger, v validator.Validator) UpdateOrganizationSettingsAction {
+	return UpdateOrganizationSettingsAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a UpdateOrganizationSettingsAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "update_organization_settings"
+
+	var input usecase.UpdateOrganizationSettingsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when decoding json")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer r.Body.Close()
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input.UpdatedBy = principal.Email
+	input.IP = middleware.ClientIPFromContext(r.Context())
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidBusinessHours, domain.ErrInvalidSLAPolicy:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusBadRequest,
+			).Log("error when updating organization settings")
+
+			response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when updating organization settings")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success updating organization settings")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a UpdateOrganizationSettingsAction) validateInput(input usecase.UpdateOrganizationSettingsInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
@@ -0,0 +1,101 @@
+package action
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type UpdateUserActivationAction struct {
+	uc        usecase.UpdateUserActivationUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewUpdateUserActivationAction(uc usecase.UpdateUserActivationUseCase, log logger.Logger, v validator.Validator) UpdateUserActivationAction {
+	return UpdateUserActivationAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+func (a UpdateUserActivationAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "update_user_activation"
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	input := usecase.UpdateUserActivationInput{
+		Email:     r.URL.Query().Get("email"),
+		Active:    r.URL.Query().Get("active") == "true",
+		UpdatedBy: principal.Email,
+		IP:        middleware.ClientIPFromContext(r.Context()),
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		switch err {
+		case domain.ErrUserNotFound:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusNotFound,
+			).Log("error when updating user activation")
+
+			response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+			return
+		case domain.ErrCannotDeactivateSelf:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusPreconditionFailed,
+			).Log("error when updating user activation")
+
+			response.NewError("condition_error", http.StatusPreconditionFailed, err, "").Send(w)
+			return
+		default:
+			logging.NewError(
+				a.log,
+				err,
+				logKey,
+				http.StatusInternalServerError,
+			).Log("error when updating user activation")
+
+			response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+			return
+		}
+	}
+	logging.NewInfo(a.log, logKey, http.StatusOK).Log("success updating user activation")
+
+	response.NewSuccess(output, http.StatusOK).Send(w)
+}
+
+func (a UpdateUserActivationAction) validateInput(input usecase.UpdateUserActivationInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+
+}
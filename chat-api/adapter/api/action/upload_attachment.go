@@ -0,0 +1,177 @@
+package action
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"chat-api/adapter/api/logging"
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/api/response"
+	"chat-api/adapter/logger"
+	"chat-api/adapter/validator"
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+// maxUploadBody leaves room for the multipart headers around the file
+const maxUploadBody = domain.MaxAttachmentSize + 1<<20
+
+var errMissingFile = errors.New("the request has no file part")
+
+type UploadAttachmentAction struct {
+	uc        usecase.UploadAttachmentUseCase
+	log       logger.Logger
+	validator validator.Validator
+}
+
+func NewUploadAttachmentAction(uc usecase.UploadAttachmentUseCase, log logger.Logger, v validator.Validator) UploadAttachmentAction {
+	return UploadAttachmentAction{
+		uc:        uc,
+		log:       log,
+		validator: v,
+	}
+}
+
+// Execute reads the "file" part of a multipart/form-data body as it streams in
+func (a UploadAttachmentAction) Execute(w http.ResponseWriter, r *http.Request) {
+	const logKey = "upload_attachment"
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBody)
+	defer r.Body.Close()
+
+	file, err := filePart(r)
+	if err != nil {
+		logging.NewError(
+			a.log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log("error when reading the upload")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+	defer file.Close()
+
+	input := usecase.UploadAttachmentInput{
+		ChannelId: r.URL.Query().Get("channelId"),
+		FileName:  file.FileName(),
+		Content:   file,
+	}
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok {
+		input.UploadedBy = principal.Email
+	}
+
+	if err := a.validateInput(input); err != nil {
+		logging.NewError(
+			a.log,
+			response.ErrInvalidInput,
+			logKey,
+			http.StatusBadRequest,
+		).Log("invalid input")
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+		return
+	}
+
+	output, err := a.uc.Execute(r.Context(), input)
+	if err != nil {
+		attachmentError(a.log, w, err, logKey, "error when uploading attachment")
+		return
+	}
+	logging.NewInfo(a.log, logKey, http.StatusCreated).Log("success uploading attachment")
+
+	response.NewSuccess(output, http.StatusCreated).Send(w)
+}
+
+func (a UploadAttachmentAction) validateInput(input usecase.UploadAttachmentInput) error {
+	err := a.validator.Validate(input)
+	if err != nil {
+		return errors.New(strings.Join(a.validator.Messages(), ","))
+	}
+	return nil
+}
+
+// filePart skips the parts before the one named "file"
+func filePart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errMissingFile
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// attachmentError answers the errors shared by the attachment actions
+func attachmentError(log logger.Logger, w http.ResponseWriter, err error, logKey, message string) {
+	switch err {
+	case domain.ErrUserNotFound, domain.ErrAttachmentNotFound:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusNotFound,
+		).Log(message)
+
+		response.NewError("not_found", http.StatusNotFound, err, "").Send(w)
+	case domain.ErrNotChannelParticipant, domain.ErrInvalidAttachmentURL:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusForbidden,
+		).Log(message)
+
+		response.NewError("forbidden", http.StatusForbidden, err, "").Send(w)
+	case domain.ErrAttachmentTooLarge:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusRequestEntityTooLarge,
+		).Log(message)
+
+		response.NewError("input_error", http.StatusRequestEntityTooLarge, err, "").Send(w)
+	case domain.ErrAttachmentType:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusUnsupportedMediaType,
+		).Log(message)
+
+		response.NewError("input_error", http.StatusUnsupportedMediaType, err, "").Send(w)
+	case domain.ErrAttachmentEmpty:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusBadRequest,
+		).Log(message)
+
+		response.NewError("input_error", http.StatusBadRequest, err, "").Send(w)
+	default:
+		logging.NewError(
+			log,
+			err,
+			logKey,
+			http.StatusInternalServerError,
+		).Log(message)
+
+		response.NewError("internal_server_error", http.StatusInternalServerError, err, "").Send(w)
+	}
+}
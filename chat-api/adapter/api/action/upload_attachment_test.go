@@ -0,0 +1,147 @@
+package action
+
+import (
+	"bytes"
+	"chat-api/adapter/api/middleware"
+	"chat-api/domain"
+	"chat-api/infrastructure/log"
+	"chat-api/infrastructure/validation"
+	"chat-api/usecase"
+	"context"
+	"errors"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type mockUploadAttachment struct {
+	err     error
+	input   *usecase.UploadAttachmentInput
+	content *string
+}
+
+func (m mockUploadAttachment) Execute(_ context.Context, input usecase.UploadAttachmentInput) (usecase.AttachmentOutput, error) {
+	content, _ := ioutil.ReadAll(input.Content)
+	*m.content = string(content)
+	input.Content = nil
+	*m.input = input
+	return usecase.AttachmentOutput{}, m.err
+}
+
+func multipartBody(fields map[string]string, fileName, content string) (*bytes.Buffer, string) {
+	var (
+		body   = &bytes.Buffer{}
+		writer = multipart.NewWriter(body)
+	)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	if fileName != "" {
+		part, _ := writer.CreateFormFile("file", fileName)
+		part.Write([]byte(content))
+	}
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadAttachmentAction_Execute(t *testing.T) {
+	t.Parallel()
+
+	validator, _ := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+
+	tests := []struct {
+		name               string
+		fileName           string
+		contentType        string
+		ucErr              error
+		expectedStatusCode int
+		expectedInput      usecase.UploadAttachmentInput
+		expectedContent    string
+	}{
+		{
+			name:               "file after another field",
+			fileName:           "a.png",
+			expectedStatusCode: http.StatusCreated,
+			expectedInput:      usecase.UploadAttachmentInput{ChannelId: "c1", FileName: "a.png", UploadedBy: "user@email.com"},
+			expectedContent:    "content",
+		},
+		{
+			name:               "no file part",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "not multipart",
+			fileName:           "a.png",
+			contentType:        "application/json",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "unknown channel",
+			fileName:           "a.png",
+			ucErr:              domain.ErrUserNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedInput:      usecase.UploadAttachmentInput{ChannelId: "c1", FileName: "a.png", UploadedBy: "user@email.com"},
+			expectedContent:    "content",
+		},
+		{
+			name:               "not a participant",
+			fileName:           "a.png",
+			ucErr:              domain.ErrNotChannelParticipant,
+			expectedStatusCode: http.StatusForbidden,
+			expectedInput:      usecase.UploadAttachmentInput{ChannelId: "c1", FileName: "a.png", UploadedBy: "user@email.com"},
+			expectedContent:    "content",
+		},
+		{
+			name:               "too large",
+			fileName:           "a.png",
+			ucErr:              domain.ErrAttachmentTooLarge,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			expectedInput:      usecase.UploadAttachmentInput{ChannelId: "c1", FileName: "a.png", UploadedBy: "user@email.com"},
+			expectedContent:    "content",
+		},
+		{
+			name:               "type not allowed",
+			fileName:           "a.png",
+			ucErr:              domain.ErrAttachmentType,
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+			expectedInput:      usecase.UploadAttachmentInput{ChannelId: "c1", FileName: "a.png", UploadedBy: "user@email.com"},
+			expectedContent:    "content",
+		},
+		{
+			name:               "failed store",
+			fileName:           "a.png",
+			ucErr:              errors.New("disk full"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedInput:      usecase.UploadAttachmentInput{ChannelId: "c1", FileName: "a.png", UploadedBy: "user@email.com"},
+			expectedContent:    "content",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				input             usecase.UploadAttachmentInput
+				content           string
+				body, contentType = multipartBody(map[string]string{"note": "before the file"}, tt.fileName, "content")
+				req               = httptest.NewRequest(http.MethodPost, "/channel?channelId=c1", body)
+				w                 = httptest.NewRecorder()
+			)
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+			req.Header.Set("Content-Type", contentType)
+			req = req.WithContext(middleware.WithPrincipal(req.Context(), middleware.Principal{Email: "user@email.com"}))
+
+			NewUploadAttachmentAction(mockUploadAttachment{err: tt.ucErr, input: &input, content: &content}, log.LoggerMock{}, validator).Execute(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, w.Code, tt.expectedStatusCode)
+			}
+			if input != tt.expectedInput || content != tt.expectedContent {
+				t.Errorf("[TestCase '%s'] Input: '%v' '%v' | Expected: '%v' '%v'", tt.name, input, content, tt.expectedInput, tt.expectedContent)
+			}
+		})
+	}
+}
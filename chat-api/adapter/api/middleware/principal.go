@@ -0,0 +1,41 @@
+package middleware
+
+import "context"
+
+type contextKey string
+
+const (
+	principalKey contextKey = "principal"
+	clientIPKey  contextKey = "client_ip"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Email string
+	Role  string
+	Scope string
+	// ChannelId is set for guest tokens, which are bound to one channel
+	ChannelId string
+	TenantId  string
+	// APIKey is set for callers authenticated by an API key, never by a token,
+	// whatever role its claims carry
+	APIKey bool
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey).(Principal)
+	return p, ok
+}
+
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey).(string)
+	return ip
+}
@@ -0,0 +1,51 @@
+package response
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type CSV struct {
+	statusCode int
+	filename   string
+	records    [][]string
+}
+
+// NewCSV sends records as a file download, the first record being the header
+func NewCSV(records [][]string, filename string, status int) CSV {
+	return CSV{
+		statusCode: status,
+		filename:   filename,
+		records:    records,
+	}
+}
+
+func (r CSV) Send(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", r.filename))
+	w.WriteHeader(r.statusCode)
+
+	writer := csv.NewWriter(w)
+	for _, record := range r.records {
+		if err := writer.Write(escapeFormulas(record)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeFormulas quotes the cells a spreadsheet would run as a formula, the
+// values come from users so an export could otherwise run their commands
+func escapeFormulas(record []string) []string {
+	escaped := make([]string, len(record))
+	for i, cell := range record {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cell = "'" + cell
+		}
+		escaped[i] = cell
+	}
+	return escaped
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSV_Send(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		records  [][]string
+		expected string
+	}{
+		{
+			name:     "plain values",
+			records:  [][]string{{"action", "actor"}, {"user.login", "user@email.com"}},
+			expected: "action,actor\nuser.login,user@email.com\n",
+		},
+		{
+			name: "values a spreadsheet would run",
+			records: [][]string{
+				{"=HYPERLINK(\"http://evil\")", "+1", "-1", "@SUM(A1)"},
+				{"\tcmd", "\rcmd", "a=b", ""},
+			},
+			expected: "\"'=HYPERLINK(\"\"http://evil\"\")\",'+1,'-1,'@SUM(A1)\n'\tcmd,\"'\rcmd\",a=b,\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := NewCSV(tt.records, "export.csv", http.StatusOK).Send(w); err != nil {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, nil)
+			}
+
+			if got := w.Body.String(); got != tt.expected {
+				t.Errorf("[TestCase '%s'] Result: %q | Expected: %q", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package response
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+type File struct {
+	statusCode  int
+	filename    string
+	contentType string
+	size        int64
+	inline      bool
+	content     io.Reader
+}
+
+// NewFile streams user uploaded content, inline files are shown by the browser
+// and the others downloaded
+func NewFile(content io.Reader, filename, contentType string, size int64, inline bool, status int) File {
+	return File{
+		statusCode:  status,
+		filename:    filename,
+		contentType: contentType,
+		size:        size,
+		inline:      inline,
+		content:     content,
+	}
+}
+
+func (r File) Send(w http.ResponseWriter) error {
+	disposition := "attachment"
+	if r.inline {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", r.contentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(r.size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": r.filename}))
+	// The content comes from users, browsers must neither guess its type nor run it
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(r.statusCode)
+
+	_, err := io.Copy(w, r.content)
+	return err
+}
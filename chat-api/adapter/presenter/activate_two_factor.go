@@ -0,0 +1,21 @@
+package presenter
+
+import (
+	"chat-api/usecase"
+)
+
+type activateTwoFactorPresenter struct{}
+
+func NewActivateTwoFactorPresenter() usecase.ActivateTwoFactorPresenter {
+	return activateTwoFactorPresenter{}
+}
+
+func (a activateTwoFactorPresenter) Output(recoveryCodes []string) usecase.ActivateTwoFactorOutput {
+	if recoveryCodes == nil {
+		recoveryCodes = make([]string, 0)
+	}
+
+	return usecase.ActivateTwoFactorOutput{
+		RecoveryCodes: recoveryCodes,
+	}
+}
@@ -15,11 +15,7 @@ func (a createMessagePresenter) Output(channel domain.Channel) usecase.CreateMes
 
 	messages := make([]usecase.MessageOutput, 0)
 	for _, message := range channel.Messages() {
-		messages = append(messages, usecase.MessageOutput{
-			MessageFrom: message.MessageFrom,
-			Message:     message.Message,
-			Timestamp:   message.Timestamp,
-		})
+		messages = append(messages, messageOutput(message))
 	}
 
 	return usecase.CreateMessageOutput{
@@ -31,3 +27,34 @@ func (a createMessagePresenter) Output(channel domain.Channel) usecase.CreateMes
 		Messages:      messages,
 	}
 }
+
+// messageOutput turns deleted messages into tombstones, without their attachments
+func messageOutput(message domain.Message) usecase.MessageOutput {
+	output := usecase.MessageOutput{
+		MessageFrom: message.MessageFrom,
+		Message:     message.Message,
+		Deleted:     message.IsDeleted,
+		Timestamp:   message.Timestamp,
+		Internal:    message.Internal,
+	}
+	if !message.Id.IsZero() {
+		output.Id = message.Id.Hex()
+	}
+	if message.IsDeleted {
+		output.Message = ""
+	} else {
+		for _, attachment := range message.Attachments {
+			output.Attachments = append(output.Attachments, usecase.MessageAttachmentOutput{
+				Id:          attachment.Id.Hex(),
+				FileName:    attachment.FileName,
+				ContentType: attachment.ContentType,
+				Size:        attachment.Size,
+			})
+		}
+	}
+	if !message.EditedAt.IsZero() {
+		editedAt := message.EditedAt
+		output.EditedAt = &editedAt
+	}
+	return output
+}
@@ -40,6 +40,7 @@ func Test_addMessagePresenter_Output(t *testing.T) {
 				CurrentStatus: "ACTIVE",
 				CreatedAt:     createdAt,
 				Messages: []usecase.MessageOutput{{
+					Id:          channel.Messages()[0].Id.Hex(),
 					MessageFrom: "anthony.jones@gmail.com",
 					Message:     "Hello World",
 					Timestamp:   createdAt,
@@ -0,0 +1,19 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type addNotePresenter struct{}
+
+func NewAddNotePresenter() usecase.AddNotePresenter {
+	return addNotePresenter{}
+}
+
+func (a addNotePresenter) Output(channel domain.Channel, note domain.Message) usecase.AddNoteOutput {
+	return usecase.AddNoteOutput{
+		ChannelId: channel.Id().Hex(),
+		Note:      messageOutput(note),
+	}
+}
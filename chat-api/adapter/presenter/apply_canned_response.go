@@ -0,0 +1,22 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type applyCannedResponsePresenter struct{}
+
+func NewApplyCannedResponsePresenter() usecase.ApplyCannedResponsePresenter {
+	return applyCannedResponsePresenter{}
+}
+
+func (a applyCannedResponsePresenter) Output(channel domain.Channel, text string) usecase.ApplyCannedResponseOutput {
+	return usecase.ApplyCannedResponseOutput{
+		ChannelId:     channel.Id().Hex(),
+		Text:          text,
+		CurrentStatus: channel.CurrentStatus(),
+		RepEmail:      channel.RepEmail(),
+		Tags:          channelTags(channel),
+	}
+}
@@ -0,0 +1,21 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type authenticateAPIKeyPresenter struct{}
+
+func NewAuthenticateAPIKeyPresenter() usecase.AuthenticateAPIKeyPresenter {
+	return authenticateAPIKeyPresenter{}
+}
+
+func (a authenticateAPIKeyPresenter) Output(key domain.APIKey, scope string) usecase.AuthenticateAPIKeyOutput {
+	return usecase.AuthenticateAPIKeyOutput{
+		Id:       key.Id().Hex(),
+		Name:     key.Name(),
+		Scope:    scope,
+		TenantId: key.TenantId(),
+	}
+}
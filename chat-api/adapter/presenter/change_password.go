@@ -0,0 +1,19 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type changePasswordPresenter struct{}
+
+func NewChangePasswordPresenter() usecase.ChangePasswordPresenter {
+	return changePasswordPresenter{}
+}
+
+func (a changePasswordPresenter) Output(user domain.User) usecase.ChangePasswordOutput {
+	return usecase.ChangePasswordOutput{
+		Email:     user.Email(),
+		UpdatedAt: user.UpdatedAt(),
+	}
+}
@@ -0,0 +1,56 @@
+package presenter
+
+import (
+	"time"
+
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type checkSLABreachesPresenter struct{}
+
+func NewCheckSLABreachesPresenter() usecase.CheckSLABreachesPresenter {
+	return checkSLABreachesPresenter{}
+}
+
+func (a checkSLABreachesPresenter) Output(channels []domain.Channel) usecase.CheckSLABreachesOutput {
+	var breaches = make([]usecase.SLABreachOutput, 0)
+	for _, channel := range channels {
+		for _, target := range channel.UnannouncedBreaches() {
+			breaches = append(breaches, usecase.SLABreachOutput{
+				ChannelId: channel.Id().Hex(),
+				Target:    target,
+			})
+		}
+	}
+	return usecase.CheckSLABreachesOutput{Breaches: breaches}
+}
+
+// channelSLA is nil for channels that were not measured or whose policy tracks no target
+func channelSLA(channel domain.Channel) *usecase.SLAOutput {
+	sla := channel.SLA()
+	if sla.Status() == "" {
+		return nil
+	}
+	return &usecase.SLAOutput{
+		Status:        sla.Status(),
+		FirstResponse: slaTargetOutput(sla.FirstResponse),
+		Resolution:    slaTargetOutput(sla.Resolution),
+	}
+}
+
+func slaTargetOutput(target domain.SLATarget) *usecase.SLATargetOutput {
+	if target.Target == 0 {
+		return nil
+	}
+	output := &usecase.SLATargetOutput{
+		TargetSeconds: int64(target.Target / time.Second),
+		DueAt:         target.DueAt,
+		Status:        target.Status,
+	}
+	if !target.StoppedAt.IsZero() {
+		stoppedAt := target.StoppedAt
+		output.StoppedAt = &stoppedAt
+	}
+	return output
+}
@@ -0,0 +1,19 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type createAPIKeyPresenter struct{}
+
+func NewCreateAPIKeyPresenter() usecase.CreateAPIKeyPresenter {
+	return createAPIKeyPresenter{}
+}
+
+func (a createAPIKeyPresenter) Output(key domain.APIKey, plaintext string) usecase.CreateAPIKeyOutput {
+	return usecase.CreateAPIKeyOutput{
+		APIKeyOutput: apiKeyOutput(key),
+		Key:          plaintext,
+	}
+}
@@ -0,0 +1,16 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type createCannedResponsePresenter struct{}
+
+func NewCreateCannedResponsePresenter() usecase.CreateCannedResponsePresenter {
+	return createCannedResponsePresenter{}
+}
+
+func (a createCannedResponsePresenter) Output(response domain.CannedResponse) usecase.CannedResponseOutput {
+	return cannedResponseOutput(response)
+}
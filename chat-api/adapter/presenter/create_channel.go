@@ -11,11 +11,13 @@ func NewCreateChannelPresenter() usecase.CreateChannelPresenter {
 	return createChannelPresenter{}
 }
 
-func (a createChannelPresenter) Output(channel domain.Channel) usecase.CreateChannelOutput {
+func (a createChannelPresenter) Output(channel domain.Channel, outsideBusinessHours bool) usecase.CreateChannelOutput {
 	return usecase.CreateChannelOutput{
-		Id:            channel.Id().Hex(),
-		UserEmail:     channel.UserEmail(),
-		CurrentStatus: channel.CurrentStatus(),
+		Id:                   channel.Id().Hex(),
+		UserEmail:            channel.UserEmail(),
+		RepEmail:             channel.RepEmail(),
+		CurrentStatus:        channel.CurrentStatus(),
+		OutsideBusinessHours: outsideBusinessHours,
 		// CreatedAt:     channel.CreatedAt(),
 	}
 }
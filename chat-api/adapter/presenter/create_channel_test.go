@@ -44,7 +44,7 @@ func Test_createChannelPresenter_Output(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			pre := NewCreateChannelPresenter()
-			if got := pre.Output(tt.args.channel); !reflect.DeepEqual(got, tt.want) {
+			if got := pre.Output(tt.args.channel, false); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("[TestCase '%s'] Got: '%+v' | Want: '%+v'", tt.name, got, tt.want)
 			}
 		})
@@ -0,0 +1,16 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type createOrganizationPresenter struct{}
+
+func NewCreateOrganizationPresenter() usecase.CreateOrganizationPresenter {
+	return createOrganizationPresenter{}
+}
+
+func (a createOrganizationPresenter) Output(organization domain.Organization) usecase.OrganizationOutput {
+	return organizationOutput(organization)
+}
@@ -0,0 +1,16 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type deleteCannedResponsePresenter struct{}
+
+func NewDeleteCannedResponsePresenter() usecase.DeleteCannedResponsePresenter {
+	return deleteCannedResponsePresenter{}
+}
+
+func (a deleteCannedResponsePresenter) Output(response domain.CannedResponse) usecase.CannedResponseOutput {
+	return cannedResponseOutput(response)
+}
@@ -0,0 +1,19 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type deleteMessagePresenter struct{}
+
+func NewDeleteMessagePresenter() usecase.DeleteMessagePresenter {
+	return deleteMessagePresenter{}
+}
+
+func (a deleteMessagePresenter) Output(channel domain.Channel, message domain.Message) usecase.DeleteMessageOutput {
+	return usecase.DeleteMessageOutput{
+		ChannelId: channel.Id().Hex(),
+		Message:   messageOutput(message),
+	}
+}
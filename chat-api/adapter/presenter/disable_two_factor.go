@@ -0,0 +1,19 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type disableTwoFactorPresenter struct{}
+
+func NewDisableTwoFactorPresenter() usecase.DisableTwoFactorPresenter {
+	return disableTwoFactorPresenter{}
+}
+
+func (a disableTwoFactorPresenter) Output(user domain.User) usecase.DisableTwoFactorOutput {
+	return usecase.DisableTwoFactorOutput{
+		Email:            user.Email(),
+		TwoFactorEnabled: user.TwoFactorEnabled(),
+	}
+}
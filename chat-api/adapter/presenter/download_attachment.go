@@ -0,0 +1,23 @@
+package presenter
+
+import (
+	"io"
+
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type downloadAttachmentPresenter struct{}
+
+func NewDownloadAttachmentPresenter() usecase.DownloadAttachmentPresenter {
+	return downloadAttachmentPresenter{}
+}
+
+func (a downloadAttachmentPresenter) Output(attachment domain.Attachment, content io.ReadCloser) usecase.DownloadAttachmentOutput {
+	return usecase.DownloadAttachmentOutput{
+		FileName:    attachment.FileName(),
+		ContentType: attachment.ContentType(),
+		Size:        attachment.Size(),
+		Content:     content,
+	}
+}
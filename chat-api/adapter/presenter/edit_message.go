@@ -0,0 +1,19 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type editMessagePresenter struct{}
+
+func NewEditMessagePresenter() usecase.EditMessagePresenter {
+	return editMessagePresenter{}
+}
+
+func (a editMessagePresenter) Output(channel domain.Channel, message domain.Message) usecase.EditMessageOutput {
+	return usecase.EditMessageOutput{
+		ChannelId: channel.Id().Hex(),
+		Message:   messageOutput(message),
+	}
+}
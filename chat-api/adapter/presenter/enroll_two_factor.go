@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"chat-api/usecase"
+)
+
+type enrollTwoFactorPresenter struct{}
+
+func NewEnrollTwoFactorPresenter() usecase.EnrollTwoFactorPresenter {
+	return enrollTwoFactorPresenter{}
+}
+
+func (a enrollTwoFactorPresenter) Output(secret, provisioningURI string) usecase.EnrollTwoFactorOutput {
+	return usecase.EnrollTwoFactorOutput{
+		Secret:          secret,
+		ProvisioningURI: provisioningURI,
+	}
+}
@@ -0,0 +1,19 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type exportAuditEventsPresenter struct{}
+
+func NewExportAuditEventsPresenter() usecase.ExportAuditEventsPresenter {
+	return exportAuditEventsPresenter{}
+}
+
+func (e exportAuditEventsPresenter) Output(events []domain.AuditEvent, totalCount int) usecase.ExportAuditEventsOutput {
+	return usecase.ExportAuditEventsOutput{
+		TotalCount: totalCount,
+		Data:       auditEventsOutput(events),
+	}
+}
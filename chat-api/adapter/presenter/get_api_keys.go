@@ -0,0 +1,41 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getAPIKeysPresenter struct{}
+
+func NewGetAPIKeysPresenter() usecase.GetAPIKeysPresenter {
+	return getAPIKeysPresenter{}
+}
+
+func (a getAPIKeysPresenter) Output(keys []domain.APIKey) usecase.GetAPIKeysOutput {
+	var o = make([]usecase.APIKeyOutput, 0)
+
+	for _, key := range keys {
+		o = append(o, apiKeyOutput(key))
+	}
+
+	return usecase.GetAPIKeysOutput{
+		Count: len(o),
+		Data:  o,
+	}
+}
+
+func apiKeyOutput(key domain.APIKey) usecase.APIKeyOutput {
+	o := usecase.APIKeyOutput{
+		Id:        key.Id().Hex(),
+		Name:      key.Name(),
+		Prefix:    key.Prefix(),
+		Scopes:    key.Scopes(),
+		CreatedBy: key.CreatedBy(),
+		CreatedAt: key.CreatedAt(),
+	}
+	if key.IsRevoked() {
+		revokedAt := key.RevokedAt()
+		o.RevokedAt = &revokedAt
+	}
+	return o
+}
@@ -0,0 +1,34 @@
+package presenter
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getAttachmentURLPresenter struct{}
+
+func NewGetAttachmentURLPresenter() usecase.GetAttachmentURLPresenter {
+	return getAttachmentURLPresenter{}
+}
+
+// Output links to the download route relative to the API, the link carries the
+// organization since it is fetched without credentials
+func (a getAttachmentURLPresenter) Output(attachment domain.Attachment, expiresAt time.Time, signature string) usecase.AttachmentURLOutput {
+	if signature == "" {
+		return usecase.AttachmentURLOutput{}
+	}
+
+	query := url.Values{}
+	query.Set("tenant", attachment.TenantId())
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", signature)
+
+	return usecase.AttachmentURLOutput{
+		URL:       "/v1/attachment/" + attachment.Id().Hex() + "?" + query.Encode(),
+		ExpiresAt: expiresAt,
+	}
+}
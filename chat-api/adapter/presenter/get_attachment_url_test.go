@@ -0,0 +1,47 @@
+package presenter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/usecase"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func Test_getAttachmentURLPresenter_Output(t *testing.T) {
+	var (
+		id         = primitive.NewObjectID()
+		expiresAt  = time.Unix(1614600000, 0)
+		attachment = domain.NewAttachment(id, "c1", "user@email.com", "a.png", "image/png", 10, time.Now())
+	)
+	attachment.AssignTenant("acme")
+
+	tests := []struct {
+		name      string
+		signature string
+		want      usecase.AttachmentURLOutput
+	}{
+		{
+			name:      "Signed link",
+			signature: "abc-_",
+			want: usecase.AttachmentURLOutput{
+				URL:       "/v1/attachment/" + id.Hex() + "?expires=1614600000&signature=abc-_&tenant=acme",
+				ExpiresAt: expiresAt,
+			},
+		},
+		{
+			name: "No link",
+			want: usecase.AttachmentURLOutput{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewGetAttachmentURLPresenter().Output(attachment, expiresAt, tt.signature); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("[TestCase '%s'] Got: '%+v' | Want: '%+v'", tt.name, got, tt.want)
+			}
+		})
+	}
+}
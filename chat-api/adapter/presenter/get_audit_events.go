@@ -0,0 +1,40 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getAuditEventsPresenter struct{}
+
+func NewGetAuditEventsPresenter() usecase.GetAuditEventsPresenter {
+	return getAuditEventsPresenter{}
+}
+
+func (g getAuditEventsPresenter) Output(events []domain.AuditEvent, page, limit, totalCount int) usecase.GetAuditEventsOutput {
+	var o = auditEventsOutput(events)
+
+	return usecase.GetAuditEventsOutput{
+		Page:       page,
+		Count:      len(o),
+		Limit:      limit,
+		TotalCount: totalCount,
+		Data:       o,
+	}
+}
+
+func auditEventsOutput(events []domain.AuditEvent) []usecase.AuditEventOutput {
+	var o = make([]usecase.AuditEventOutput, 0)
+
+	for _, event := range events {
+		o = append(o, usecase.AuditEventOutput{
+			Action:    event.Action,
+			Actor:     event.Actor,
+			Target:    event.Target,
+			IP:        event.IP,
+			Outcome:   event.Outcome,
+			Timestamp: event.Timestamp,
+		})
+	}
+	return o
+}
@@ -0,0 +1,16 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getCannedResponsePresenter struct{}
+
+func NewGetCannedResponsePresenter() usecase.GetCannedResponsePresenter {
+	return getCannedResponsePresenter{}
+}
+
+func (a getCannedResponsePresenter) Output(response domain.CannedResponse) usecase.CannedResponseOutput {
+	return cannedResponseOutput(response)
+}
@@ -0,0 +1,50 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getCannedResponsesPresenter struct{}
+
+func NewGetCannedResponsesPresenter() usecase.GetCannedResponsesPresenter {
+	return getCannedResponsesPresenter{}
+}
+
+func (a getCannedResponsesPresenter) Output(responses []domain.CannedResponse) usecase.GetCannedResponsesOutput {
+	var o = make([]usecase.CannedResponseOutput, 0)
+
+	for _, response := range responses {
+		o = append(o, cannedResponseOutput(response))
+	}
+
+	return usecase.GetCannedResponsesOutput{
+		Count: len(o),
+		Data:  o,
+	}
+}
+
+func cannedResponseOutput(response domain.CannedResponse) usecase.CannedResponseOutput {
+	tags := response.Tags()
+	if tags == nil {
+		tags = make([]string, 0)
+	}
+	macro := response.Macro()
+
+	return usecase.CannedResponseOutput{
+		Id:       response.Id().Hex(),
+		Shortcut: response.Shortcut(),
+		Title:    response.Title(),
+		Body:     response.Body(),
+		Tags:     tags,
+		Owner:    response.Owner(),
+		Shared:   response.IsShared(),
+		Macro: usecase.MacroFields{
+			Status:     macro.Status,
+			AddTags:    macro.AddTags,
+			RemoveTags: macro.RemoveTags,
+		},
+		CreatedAt: response.CreatedAt(),
+		UpdatedAt: response.UpdatedAt(),
+	}
+}
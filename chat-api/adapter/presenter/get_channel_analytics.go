@@ -0,0 +1,74 @@
+package presenter
+
+import (
+	"time"
+
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getChannelAnalyticsPresenter struct{}
+
+func NewGetChannelAnalyticsPresenter() usecase.GetChannelAnalyticsPresenter {
+	return getChannelAnalyticsPresenter{}
+}
+
+// Output lists every bucket of the range, the ones nothing happened in as zeros,
+// and every open status in the backlog
+func (g getChannelAnalyticsPresenter) Output(query domain.AnalyticsQuery, buckets []domain.ChannelBucket, backlog map[string]int) usecase.GetChannelAnalyticsOutput {
+	var found = make(map[int64]domain.ChannelBucket, len(buckets))
+	for _, bucket := range buckets {
+		found[bucket.Start.Unix()] = bucket
+	}
+
+	var series = make([]usecase.ChannelAnalyticsBucketOutput, 0)
+	for _, start := range query.Buckets() {
+		bucket := found[start.Unix()]
+		o := usecase.ChannelAnalyticsBucketOutput{
+			Start:                      start,
+			Opened:                     bucket.Opened,
+			Closed:                     bucket.Closed,
+			MedianFirstResponseSeconds: medianSeconds(bucket.Answered, bucket.MedianFirstResponse),
+			MedianResolutionSeconds:    medianSeconds(bucket.Closed, bucket.MedianResolution),
+		}
+		if bucket.Opened > 0 {
+			o.MessagesPerChannel = float64(bucket.Messages) / float64(bucket.Opened)
+		}
+		series = append(series, o)
+	}
+
+	var statuses = make(map[string]int)
+	for _, status := range domain.OpenStatuses() {
+		statuses[status] = backlog[status]
+	}
+
+	return usecase.GetChannelAnalyticsOutput{
+		Interval: query.Interval,
+		TimeZone: analyticsTimeZone(query),
+		From:     query.From,
+		To:       query.To,
+		Series:   series,
+		Backlog:  statuses,
+	}
+}
+
+func analyticsTimeZone(query domain.AnalyticsQuery) string {
+	if query.Location == nil {
+		return ""
+	}
+	return query.Location.String()
+}
+
+// medianSeconds is nil when no channel was counted to take the median of
+func medianSeconds(count int, median time.Duration) *float64 {
+	if count == 0 {
+		return nil
+	}
+	// Status changes are stored to the second, a channel resolved in the second
+	// it was opened in reads a resolution time just under zero
+	if median < 0 {
+		median = 0
+	}
+	seconds := median.Seconds()
+	return &seconds
+}
@@ -0,0 +1,69 @@
+package presenter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+func Test_getChannelAnalyticsPresenter_Output(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("[TestCase 'load time zone'] Got: '%v' | Want: '%v'", err, nil)
+	}
+
+	var (
+		// Clocks are turned forward on March 14th, the day lasts 23 hours
+		query = domain.AnalyticsQuery{
+			From:     time.Date(2021, 3, 13, 12, 0, 0, 0, newYork),
+			To:       time.Date(2021, 3, 15, 12, 0, 0, 0, newYork),
+			Interval: domain.AnalyticsByDay,
+			Location: newYork,
+		}
+		seconds = func(s float64) *float64 { return &s }
+	)
+
+	buckets := []domain.ChannelBucket{
+		{
+			Start:               time.Date(2021, 3, 14, 0, 0, 0, 0, newYork),
+			Opened:              4,
+			Messages:            10,
+			Answered:            2,
+			Closed:              2,
+			MedianFirstResponse: 2 * time.Minute,
+			MedianResolution:    2 * time.Hour,
+		},
+	}
+
+	want := usecase.GetChannelAnalyticsOutput{
+		Interval: domain.AnalyticsByDay,
+		TimeZone: "America/New_York",
+		From:     query.From,
+		To:       query.To,
+		Series: []usecase.ChannelAnalyticsBucketOutput{
+			{Start: time.Date(2021, 3, 13, 0, 0, 0, 0, newYork)},
+			{
+				Start:                      time.Date(2021, 3, 14, 0, 0, 0, 0, newYork),
+				Opened:                     4,
+				Closed:                     2,
+				MedianFirstResponseSeconds: seconds(120),
+				MedianResolutionSeconds:    seconds(7200),
+				MessagesPerChannel:         2.5,
+			},
+			{Start: time.Date(2021, 3, 15, 0, 0, 0, 0, newYork)},
+		},
+		Backlog: map[string]int{domain.INACTIVE: 0, domain.ACTIVE: 3, domain.IN_PROGRESS: 0},
+	}
+
+	got := NewGetChannelAnalyticsPresenter().Output(query, buckets, map[string]int{domain.ACTIVE: 3})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("[TestCase 'zero filled series'] Got: '%+v' | Want: '%+v'", got, want)
+	}
+
+	if got := NewGetChannelAnalyticsPresenter().Output(domain.AnalyticsQuery{}, nil, nil); len(got.Series) != 0 || got.TimeZone != "" {
+		t.Errorf("[TestCase 'no query'] Got: '%+v' | Want: '%+v'", got, "an empty series")
+	}
+}
@@ -15,13 +15,24 @@ func (a getChannelByIdPresenter) Output(channel domain.Channel) usecase.GetChann
 
 	messages := make([]usecase.Message, 0)
 	for _, message := range channel.Messages() {
+		output := messageOutput(message)
 		messages = append(messages, usecase.Message{
-			MessageFrom: message.MessageFrom,
-			Message:     message.Message,
-			Timestamp:   message.Timestamp,
+			Id:          output.Id,
+			Message:     output.Message,
+			MessageFrom: output.MessageFrom,
+			Deleted:     output.Deleted,
+			Timestamp:   output.Timestamp,
+			EditedAt:    output.EditedAt,
+			Attachments: output.Attachments,
+			Internal:    output.Internal,
 		})
 	}
 
+	markers := make([]usecase.ReadMarkerOutput, 0)
+	for _, marker := range channel.ReadMarkers() {
+		markers = append(markers, usecase.ReadMarkerOutput{Participant: marker.Participant, LastReadAt: marker.LastReadAt})
+	}
+
 	return usecase.GetChannelByIdOutput{
 
 		Id:            channel.Id().Hex(),
@@ -29,6 +40,12 @@ func (a getChannelByIdPresenter) Output(channel domain.Channel) usecase.GetChann
 		RepEmail:      channel.RepEmail(),
 		CurrentStatus: channel.CurrentStatus(),
 		CreatedAt:     channel.CreatedAt(),
+		Tags:          channelTags(channel),
+		Priority:      channel.Priority(),
+		Attributes:    channelAttributes(channel),
 		Messages:      messages,
+		ReadMarkers:   markers,
+		SurveyPending: channel.SurveyPending(),
+		SLA:           channelSLA(channel),
 	}
 }
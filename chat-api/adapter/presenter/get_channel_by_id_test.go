@@ -26,6 +26,27 @@ func Test_getChannelByIdPresenter_Output(t *testing.T) {
 	channel.UpdateRepEmail("jones.anthony@gmail.com")
 	channel.AddMessage("jones.anthony@gmail.com", "Hello world", createdAt)
 
+	editedAt := createdAt.Add(time.Minute)
+	changed := channel
+	attachment := domain.MessageAttachment{Id: primitive.NewObjectID(), FileName: "a.png", ContentType: "image/png", Size: 42}
+	changed.AddMessage("anthony.jones@gmail.com", "Hello", createdAt, attachment)
+	changed.AddMessage("anthony.jones@gmail.com", "Wrong channel", createdAt, attachment)
+	changedMessages := changed.Messages()
+	if _, err := changed.EditMessage(changedMessages[1].Id, "Hello there", editedAt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := changed.DeleteMessage(changedMessages[2].Id, editedAt); err != nil {
+		t.Fatal(err)
+	}
+	changed.MarkRead("Jones.Anthony@gmail.com", createdAt)
+	changed.AddTags("Billing")
+	if err := changed.UpdatePriority(domain.PriorityHigh); err != nil {
+		t.Fatal(err)
+	}
+	if err := changed.UpdateAttributes(map[string]string{"orderId": "A-42"}); err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []struct {
 		name string
 		args args
@@ -42,11 +63,63 @@ func Test_getChannelByIdPresenter_Output(t *testing.T) {
 				RepEmail:      "jones.anthony@gmail.com",
 				CurrentStatus: "ACTIVE",
 				CreatedAt:     createdAt,
+				Tags:          []string{},
+				Priority:      domain.PriorityNormal,
+				Attributes:    map[string]string{},
 				Messages: []usecase.Message{{
+					Id:          channel.Messages()[0].Id.Hex(),
 					Message:     "Hello world",
 					MessageFrom: "jones.anthony@gmail.com",
 					Timestamp:   createdAt,
 				}},
+				ReadMarkers: []usecase.ReadMarkerOutput{},
+			},
+		},
+		{
+			name: "Edited and deleted messages with attachments, read by the rep",
+			args: args{
+				channel: changed,
+			},
+			want: usecase.GetChannelByIdOutput{
+				Id:            channelId.Hex(),
+				UserEmail:     "anthony.jones@gmail.com",
+				RepEmail:      "jones.anthony@gmail.com",
+				CurrentStatus: "ACTIVE",
+				CreatedAt:     createdAt,
+				Tags:          []string{"billing"},
+				Priority:      domain.PriorityHigh,
+				Attributes:    map[string]string{"orderId": "A-42"},
+				Messages: []usecase.Message{
+					{
+						Id:          changedMessages[0].Id.Hex(),
+						Message:     "Hello world",
+						MessageFrom: "jones.anthony@gmail.com",
+						Timestamp:   createdAt,
+					},
+					{
+						Id:          changedMessages[1].Id.Hex(),
+						Message:     "Hello there",
+						MessageFrom: "anthony.jones@gmail.com",
+						Timestamp:   createdAt,
+						EditedAt:    &editedAt,
+						Attachments: []usecase.MessageAttachmentOutput{{
+							Id:          attachment.Id.Hex(),
+							FileName:    "a.png",
+							ContentType: "image/png",
+							Size:        42,
+						}},
+					},
+					{
+						Id:          changedMessages[2].Id.Hex(),
+						MessageFrom: "anthony.jones@gmail.com",
+						Deleted:     true,
+						Timestamp:   createdAt,
+					},
+				},
+				ReadMarkers: []usecase.ReadMarkerOutput{{
+					Participant: "jones.anthony@gmail.com",
+					LastReadAt:  createdAt,
+				}},
 			},
 		},
 	}
@@ -11,21 +11,35 @@ func NewGetChannelsByQueryPresenter() usecase.GetChannelByQueryPresenter {
 	return getChannelsByQueryPresenter{}
 }
 
-func (a getChannelsByQueryPresenter) Output(channels []domain.Channel, page, limit, queryChannelCount, totalChannelCount int) usecase.GetChannelByQueryOutput {
+func (a getChannelsByQueryPresenter) Output(
+	channels []domain.Channel,
+	page, limit, queryChannelCount, totalChannelCount int,
+	nextCursor, prevCursor string,
+) usecase.GetChannelByQueryOutput {
 
 	var channelList = make([]usecase.ChannelByQueryOutput, 0)
 
 	for _, channel := range channels {
 
-		channelList = append(channelList, usecase.ChannelByQueryOutput{
+		output := usecase.ChannelByQueryOutput{
 			CurrentStatus: channel.CurrentStatus(),
 			Id:            channel.Id().Hex(),
 			CreatedAt:     channel.CreatedAt(),
+			UpdatedAt:     channel.UpdatedAt(),
 			UserEmail:     channel.UserEmail(),
 			UserFullName:  channel.UserFullName(),
 			RepEmail:      channel.RepEmail(),
-		})
-
+			UnreadCount:   channel.UnreadCount(),
+			Tags:          channelTags(channel),
+			Priority:      channel.Priority(),
+			Attributes:    channelAttributes(channel),
+			SLA:           channelSLA(channel),
+		}
+		if lastMessageAt := channel.LastMessageAt(); !lastMessageAt.IsZero() {
+			output.LastMessageAt = &lastMessageAt
+		}
+
+		channelList = append(channelList, output)
 	}
 
 	channelOutput := usecase.GetChannelByQueryOutput{
@@ -33,6 +47,8 @@ func (a getChannelsByQueryPresenter) Output(channels []domain.Channel, page, lim
 		Count:      queryChannelCount,
 		Limit:      limit,
 		TotalCount: totalChannelCount,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
 		Data:       channelList,
 	}
 	return channelOutput
@@ -0,0 +1,26 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getGuestSessionPresenter struct{}
+
+func NewGetGuestSessionPresenter() usecase.GetGuestSessionPresenter {
+	return getGuestSessionPresenter{}
+}
+
+func (a getGuestSessionPresenter) Output(channel domain.Channel) usecase.GetGuestSessionOutput {
+	if channel.UserEmail() == "" {
+		return usecase.GetGuestSessionOutput{}
+	}
+
+	return usecase.GetGuestSessionOutput{
+		ChannelId:     channel.Id().Hex(),
+		Email:         channel.UserEmail(),
+		FullName:      channel.UserFullName(),
+		CurrentStatus: channel.CurrentStatus(),
+		TenantId:      channel.TenantId(),
+	}
+}
@@ -0,0 +1,58 @@
+package presenter
+
+import (
+	"time"
+
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getOrganizationPresenter struct{}
+
+func NewGetOrganizationPresenter() usecase.GetOrganizationPresenter {
+	return getOrganizationPresenter{}
+}
+
+func (a getOrganizationPresenter) Output(organization domain.Organization) usecase.OrganizationOutput {
+	return organizationOutput(organization)
+}
+
+func organizationOutput(organization domain.Organization) usecase.OrganizationOutput {
+	var (
+		settings = organization.Settings()
+		days     = make([]usecase.BusinessDayOutput, 0)
+		domains  = make([]string, 0)
+	)
+	for _, day := range settings.BusinessHours.Days {
+		days = append(days, usecase.BusinessDayOutput{
+			Weekday: int(day.Weekday),
+			Open:    day.Open,
+			Close:   day.Close,
+		})
+	}
+	domains = append(domains, organization.Domains()...)
+
+	policies := make([]usecase.SLAPolicyOutput, 0)
+	for _, fallback := range domain.DefaultSLAPolicies() {
+		policy := settings.SLAPolicy(fallback.Priority)
+		policies = append(policies, usecase.SLAPolicyOutput{
+			Priority:             policy.Priority,
+			FirstResponseMinutes: int(policy.FirstResponse / time.Minute),
+			ResolutionMinutes:    int(policy.Resolution / time.Minute),
+		})
+	}
+
+	return usecase.OrganizationOutput{
+		Id:      organization.Id(),
+		Name:    organization.Name(),
+		Domains: domains,
+		Settings: usecase.OrganizationSettingsOutput{
+			TimeZone:        settings.BusinessHours.TimeZone,
+			BusinessDays:    days,
+			RoutingStrategy: settings.RoutingStrategy,
+			SLAPolicies:     policies,
+		},
+		CreatedAt: organization.CreatedAt(),
+		UpdatedAt: organization.UpdatedAt(),
+	}
+}
@@ -0,0 +1,79 @@
+package presenter
+
+import (
+	"sort"
+
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getRepAnalyticsPresenter struct{}
+
+func NewGetRepAnalyticsPresenter() usecase.GetRepAnalyticsPresenter {
+	return getRepAnalyticsPresenter{}
+}
+
+// Output lists the reps with open channels or channels resolved in the range,
+// by email, each with every bucket of the range
+func (g getRepAnalyticsPresenter) Output(
+	query domain.AnalyticsQuery,
+	buckets []domain.RepBucket,
+	resolutions []domain.RepResolution,
+	workload []domain.RepWorkload,
+) usecase.GetRepAnalyticsOutput {
+	type rep struct {
+		output   usecase.RepAnalyticsOutput
+		resolved map[int64]int
+	}
+	var reps = make(map[string]*rep)
+	find := func(email string) *rep {
+		r, ok := reps[email]
+		if !ok {
+			r = &rep{
+				output:   usecase.RepAnalyticsOutput{RepEmail: email, Workload: make(map[string]int)},
+				resolved: make(map[int64]int),
+			}
+			for _, status := range domain.OpenStatuses() {
+				r.output.Workload[status] = 0
+			}
+			reps[email] = r
+		}
+		return r
+	}
+
+	for _, w := range workload {
+		r := find(w.RepEmail)
+		r.output.Workload[w.Status] += w.Count
+		r.output.Open += w.Count
+	}
+	for _, bucket := range buckets {
+		r := find(bucket.RepEmail)
+		r.resolved[bucket.Start.Unix()] += bucket.Resolved
+		r.output.Resolved += bucket.Resolved
+	}
+	for _, resolution := range resolutions {
+		r := find(resolution.RepEmail)
+		r.output.MedianResolutionSeconds = medianSeconds(resolution.Resolved, resolution.MedianResolution)
+	}
+
+	var o = make([]usecase.RepAnalyticsOutput, 0, len(reps))
+	for _, r := range reps {
+		r.output.Series = make([]usecase.RepAnalyticsBucketOutput, 0)
+		for _, start := range query.Buckets() {
+			r.output.Series = append(r.output.Series, usecase.RepAnalyticsBucketOutput{
+				Start:    start,
+				Resolved: r.resolved[start.Unix()],
+			})
+		}
+		o = append(o, r.output)
+	}
+	sort.Slice(o, func(i, j int) bool { return o[i].RepEmail < o[j].RepEmail })
+
+	return usecase.GetRepAnalyticsOutput{
+		Interval: query.Interval,
+		TimeZone: analyticsTimeZone(query),
+		From:     query.From,
+		To:       query.To,
+		Reps:     o,
+	}
+}
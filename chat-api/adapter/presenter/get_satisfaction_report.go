@@ -0,0 +1,37 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getSatisfactionReportPresenter struct{}
+
+func NewGetSatisfactionReportPresenter() usecase.GetSatisfactionReportPresenter {
+	return getSatisfactionReportPresenter{}
+}
+
+func (g getSatisfactionReportPresenter) Output(groupBy string, query domain.SatisfactionQuery, overall domain.SatisfactionGroup, groups []domain.SatisfactionGroup) usecase.GetSatisfactionReportOutput {
+	var o = make([]usecase.SatisfactionGroupOutput, 0)
+	for _, group := range groups {
+		o = append(o, satisfactionGroupOutput(group))
+	}
+
+	return usecase.GetSatisfactionReportOutput{
+		GroupBy: groupBy,
+		From:    query.From,
+		To:      query.To,
+		Overall: satisfactionGroupOutput(overall),
+		Groups:  o,
+	}
+}
+
+func satisfactionGroupOutput(group domain.SatisfactionGroup) usecase.SatisfactionGroupOutput {
+	return usecase.SatisfactionGroupOutput{
+		Key:       group.Key,
+		Responses: group.Responses,
+		Average:   group.Average(),
+		CSAT:      group.CSAT(),
+		Scores:    append([]int{}, group.Scores[:]...),
+	}
+}
@@ -18,5 +18,6 @@ func (a getUserByEmailPresenter) Output(user domain.User) usecase.GetUserByEmail
 		LastName:  user.LastName(),
 		Email:     user.Email(),
 		Role:      user.Role(),
+		TenantId:  user.TenantId(),
 	}
 }
@@ -0,0 +1,37 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type getUsersPresenter struct{}
+
+func NewGetUsersPresenter() usecase.GetUsersPresenter {
+	return getUsersPresenter{}
+}
+
+func (a getUsersPresenter) Output(users []domain.User, page, limit, totalCount int) usecase.GetUsersOutput {
+	var o = make([]usecase.UserOutput, 0)
+
+	for _, user := range users {
+		o = append(o, usecase.UserOutput{
+			Id:               user.Id().Hex(),
+			FirstName:        user.FirstName(),
+			LastName:         user.LastName(),
+			Email:            user.Email(),
+			Role:             user.Role(),
+			Active:           user.IsActive(),
+			TwoFactorEnabled: user.TwoFactorEnabled(),
+			CreatedAt:        user.CreatedAt(),
+		})
+	}
+
+	return usecase.GetUsersOutput{
+		Page:       page,
+		Count:      len(o),
+		Limit:      limit,
+		TotalCount: totalCount,
+		Data:       o,
+	}
+}
@@ -0,0 +1,62 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func Test_getUsersPresenter_Output(t *testing.T) {
+	userId := primitive.NewObjectID()
+	createdAt := time.Now()
+	user := domain.NewUser(userId, "Anthony", "Jones", "anthony.jones@gmail.com", "hash", createdAt, createdAt)
+	user.Deactivate(createdAt)
+
+	tests := []struct {
+		name  string
+		users []domain.User
+		want  usecase.GetUsersOutput
+	}{
+		{
+			name:  "List users",
+			users: []domain.User{user},
+			want: usecase.GetUsersOutput{
+				Page:       2,
+				Count:      1,
+				Limit:      10,
+				TotalCount: 11,
+				Data: []usecase.UserOutput{{
+					Id:        userId.Hex(),
+					FirstName: "Anthony",
+					LastName:  "Jones",
+					Email:     "anthony.jones@gmail.com",
+					Role:      user.Role(),
+					Active:    false,
+					CreatedAt: createdAt,
+				}},
+			},
+		},
+		{
+			name:  "Empty page",
+			users: nil,
+			want: usecase.GetUsersOutput{
+				Page:       1,
+				Limit:      10,
+				TotalCount: 0,
+				Data:       []usecase.UserOutput{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pre := NewGetUsersPresenter()
+			if got := pre.Output(tt.users, tt.want.Page, tt.want.Limit, tt.want.TotalCount); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("[TestCase '%s'] Got: '%+v' | Want: '%+v'", tt.name, got, tt.want)
+			}
+		})
+	}
+}
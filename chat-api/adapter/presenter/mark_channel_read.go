@@ -0,0 +1,21 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type markChannelReadPresenter struct{}
+
+func NewMarkChannelReadPresenter() usecase.MarkChannelReadPresenter {
+	return markChannelReadPresenter{}
+}
+
+func (a markChannelReadPresenter) Output(channel domain.Channel, marker domain.ReadMarker) usecase.MarkChannelReadOutput {
+	return usecase.MarkChannelReadOutput{
+		ChannelId:   channel.Id().Hex(),
+		Participant: marker.Participant,
+		LastReadAt:  marker.LastReadAt,
+		UnreadCount: channel.CountUnread(marker.Participant),
+	}
+}
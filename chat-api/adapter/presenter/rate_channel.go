@@ -0,0 +1,22 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type rateChannelPresenter struct{}
+
+func NewRateChannelPresenter() usecase.RateChannelPresenter {
+	return rateChannelPresenter{}
+}
+
+func (a rateChannelPresenter) Output(rating domain.SatisfactionRating) usecase.RateChannelOutput {
+	return usecase.RateChannelOutput{
+		ChannelId:  rating.ChannelId.Hex(),
+		Completion: rating.Completion,
+		Score:      rating.Score,
+		Comment:    rating.Comment,
+		RatedAt:    rating.RatedAt,
+	}
+}
@@ -0,0 +1,16 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type revokeAPIKeyPresenter struct{}
+
+func NewRevokeAPIKeyPresenter() usecase.RevokeAPIKeyPresenter {
+	return revokeAPIKeyPresenter{}
+}
+
+func (a revokeAPIKeyPresenter) Output(key domain.APIKey) usecase.APIKeyOutput {
+	return apiKeyOutput(key)
+}
@@ -0,0 +1,56 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type searchChannelsPresenter struct{}
+
+func NewSearchChannelsPresenter() usecase.SearchChannelsPresenter {
+	return searchChannelsPresenter{}
+}
+
+func (a searchChannelsPresenter) Output(hits []domain.SearchHit, page, limit, totalCount int) usecase.SearchChannelsOutput {
+	var channels = make([]usecase.SearchChannelOutput, 0)
+
+	for _, hit := range hits {
+		output := usecase.SearchChannelOutput{
+			Id:            hit.Channel.Id().Hex(),
+			UserEmail:     hit.Channel.UserEmail(),
+			UserFullName:  hit.Channel.UserFullName(),
+			RepEmail:      hit.Channel.RepEmail(),
+			CurrentStatus: hit.Channel.CurrentStatus(),
+			CreatedAt:     hit.Channel.CreatedAt(),
+			Snippets:      make([]usecase.SearchSnippetOutput, 0),
+		}
+		if lastMessageAt := hit.Channel.LastMessageAt(); !lastMessageAt.IsZero() {
+			output.LastMessageAt = &lastMessageAt
+		}
+
+		for _, snippet := range hit.Snippets {
+			snippetOutput := usecase.SearchSnippetOutput{
+				Field:      snippet.Field,
+				Text:       snippet.Text,
+				Highlights: make([]usecase.SearchHighlightOutput, 0),
+			}
+			for _, highlight := range snippet.Highlights {
+				snippetOutput.Highlights = append(snippetOutput.Highlights, usecase.SearchHighlightOutput{
+					Start: highlight.Start,
+					End:   highlight.End,
+				})
+			}
+			output.Snippets = append(output.Snippets, snippetOutput)
+		}
+
+		channels = append(channels, output)
+	}
+
+	return usecase.SearchChannelsOutput{
+		Page:       page,
+		Count:      len(channels),
+		Limit:      limit,
+		TotalCount: totalCount,
+		Data:       channels,
+	}
+}
@@ -0,0 +1,20 @@
+package presenter
+
+import (
+	"chat-api/usecase"
+)
+
+type startGuestChatPresenter struct{}
+
+func NewStartGuestChatPresenter() usecase.StartGuestChatPresenter {
+	return startGuestChatPresenter{}
+}
+
+func (a startGuestChatPresenter) Output(channel usecase.CreateChannelOutput, token string) usecase.StartGuestChatOutput {
+	return usecase.StartGuestChatOutput{
+		ChannelId:     channel.Id,
+		Email:         channel.UserEmail,
+		CurrentStatus: channel.CurrentStatus,
+		Token:         token,
+	}
+}
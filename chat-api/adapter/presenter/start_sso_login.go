@@ -0,0 +1,19 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type startSSOLoginPresenter struct{}
+
+func NewStartSSOLoginPresenter() usecase.StartSSOLoginPresenter {
+	return startSSOLoginPresenter{}
+}
+
+func (a startSSOLoginPresenter) Output(state domain.SSOState, authorizationURL string) usecase.StartSSOLoginOutput {
+	return usecase.StartSSOLoginOutput{
+		AuthorizationURL: authorizationURL,
+		State:            state.State,
+	}
+}
@@ -0,0 +1,19 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type unlockUserPresenter struct{}
+
+func NewUnlockUserPresenter() usecase.UnlockUserPresenter {
+	return unlockUserPresenter{}
+}
+
+func (a unlockUserPresenter) Output(user domain.User) usecase.UnlockUserOutput {
+	return usecase.UnlockUserOutput{
+		Email:    user.Email(),
+		Unlocked: user.Email() != "",
+	}
+}
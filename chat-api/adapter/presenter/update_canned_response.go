@@ -0,0 +1,16 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type updateCannedResponsePresenter struct{}
+
+func NewUpdateCannedResponsePresenter() usecase.UpdateCannedResponsePresenter {
+	return updateCannedResponsePresenter{}
+}
+
+func (a updateCannedResponsePresenter) Output(response domain.CannedResponse) usecase.CannedResponseOutput {
+	return cannedResponseOutput(response)
+}
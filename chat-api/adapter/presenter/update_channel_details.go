@@ -0,0 +1,36 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type updateChannelDetailsPresenter struct{}
+
+func NewUpdateChannelDetailsPresenter() usecase.UpdateChannelDetailsPresenter {
+	return updateChannelDetailsPresenter{}
+}
+
+func (a updateChannelDetailsPresenter) Output(channel domain.Channel) usecase.UpdateChannelDetailsOutput {
+	return usecase.UpdateChannelDetailsOutput{
+		ChannelId:  channel.Id().Hex(),
+		Tags:       channelTags(channel),
+		Priority:   channel.Priority(),
+		Attributes: channelAttributes(channel),
+	}
+}
+
+// channelTags and channelAttributes never render as null
+func channelTags(channel domain.Channel) []string {
+	if channel.Tags() == nil {
+		return make([]string, 0)
+	}
+	return channel.Tags()
+}
+
+func channelAttributes(channel domain.Channel) map[string]string {
+	if channel.Attributes() == nil {
+		return make(map[string]string)
+	}
+	return channel.Attributes()
+}
@@ -0,0 +1,16 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type updateOrganizationSettingsPresenter struct{}
+
+func NewUpdateOrganizationSettingsPresenter() usecase.UpdateOrganizationSettingsPresenter {
+	return updateOrganizationSettingsPresenter{}
+}
+
+func (a updateOrganizationSettingsPresenter) Output(organization domain.Organization) usecase.OrganizationOutput {
+	return organizationOutput(organization)
+}
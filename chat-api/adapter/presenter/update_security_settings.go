@@ -0,0 +1,18 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type updateSecuritySettingsPresenter struct{}
+
+func NewUpdateSecuritySettingsPresenter() usecase.UpdateSecuritySettingsPresenter {
+	return updateSecuritySettingsPresenter{}
+}
+
+func (a updateSecuritySettingsPresenter) Output(settings domain.SecuritySettings) usecase.UpdateSecuritySettingsOutput {
+	return usecase.UpdateSecuritySettingsOutput{
+		RequireTwoFactorForAdmin: settings.RequireTwoFactorForAdmin,
+	}
+}
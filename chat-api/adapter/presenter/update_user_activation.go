@@ -0,0 +1,20 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type updateUserActivationPresenter struct{}
+
+func NewUpdateUserActivationPresenter() usecase.UpdateUserActivationPresenter {
+	return updateUserActivationPresenter{}
+}
+
+func (a updateUserActivationPresenter) Output(user domain.User) usecase.UpdateUserActivationOutput {
+	return usecase.UpdateUserActivationOutput{
+		Email:     user.Email(),
+		Active:    user.Email() != "" && user.IsActive(),
+		UpdatedAt: user.UpdatedAt(),
+	}
+}
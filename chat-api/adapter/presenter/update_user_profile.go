@@ -0,0 +1,22 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type updateUserProfilePresenter struct{}
+
+func NewUpdateUserProfilePresenter() usecase.UpdateUserProfilePresenter {
+	return updateUserProfilePresenter{}
+}
+
+func (a updateUserProfilePresenter) Output(user domain.User) usecase.UpdateUserProfileOutput {
+	return usecase.UpdateUserProfileOutput{
+		FirstName: user.FirstName(),
+		LastName:  user.LastName(),
+		Email:     user.Email(),
+		Role:      user.Role(),
+		UpdatedAt: user.UpdatedAt(),
+	}
+}
@@ -0,0 +1,26 @@
+package presenter
+
+import (
+	"chat-api/domain"
+	"chat-api/usecase"
+)
+
+type uploadAttachmentPresenter struct{}
+
+func NewUploadAttachmentPresenter() usecase.UploadAttachmentPresenter {
+	return uploadAttachmentPresenter{}
+}
+
+func (a uploadAttachmentPresenter) Output(attachment domain.Attachment) usecase.AttachmentOutput {
+	if attachment.Id().IsZero() {
+		return usecase.AttachmentOutput{}
+	}
+	return usecase.AttachmentOutput{
+		Id:          attachment.Id().Hex(),
+		ChannelId:   attachment.ChannelId(),
+		FileName:    attachment.FileName(),
+		ContentType: attachment.ContentType(),
+		Size:        attachment.Size(),
+		CreatedAt:   attachment.CreatedAt(),
+	}
+}
@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// bucketLayout parses the keys bucketKey groups by, a daily key keeps the hour at zero
+const bucketLayout = "2006-01-02T15"
+
+// bucketBSON is a group of the pipelines, Median is in milliseconds
+type bucketBSON struct {
+	ID       string  `bson:"_id"`
+	Count    int     `bson:"count"`
+	Messages int     `bson:"messages"`
+	Median   float64 `bson:"median"`
+}
+
+type repBucketBSON struct {
+	ID struct {
+		Rep    string `bson:"rep"`
+		Bucket string `bson:"bucket"`
+		Status string `bson:"status"`
+	} `bson:"_id"`
+	Count  int     `bson:"count"`
+	Median float64 `bson:"median"`
+}
+
+// AnalyticsNoSQL aggregates the channels collection on the database, only
+// counts and medians of groups leave it
+type AnalyticsNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewAnalyticsNoSQL(db NoSQL) AnalyticsNoSQL {
+	result := AnalyticsNoSQL{
+		db:             db,
+		collectionName: "channels",
+	}
+
+	// The channels repository indexes the creation time and the status
+	for _, key := range []string{"firstResponseAt", "resolvedAt"} {
+		err := db.EnsureIndex(
+			context.Background(),
+			result.collectionName,
+			bson.D{{Key: tenantField, Value: 1}, {Key: key, Value: 1}},
+			false,
+		)
+		if err != nil {
+			log.Panic(err)
+		}
+	}
+	return result
+}
+
+func (a AnalyticsNoSQL) GetChannelBuckets(ctx context.Context, query domain.AnalyticsQuery) ([]domain.ChannelBucket, error) {
+	var (
+		buckets = make(channelBuckets)
+		opened  = make([]bucketBSON, 0)
+	)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: analyticsFilter(ctx, query.Tag, bson.M{"createdAt": timeRange(query.From, query.To)})}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bucketKey(query, "createdAt")},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+			{Key: "messages", Value: bson.M{"$sum": bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$messages", bson.A{}}},
+				"cond":  bson.M{"$ne": bson.A{"$$this.internal", true}},
+			}}}}},
+		}}},
+	}
+	if err := a.db.Aggregate(ctx, a.collectionName, pipeline, &opened, nil); err != nil {
+		return []domain.ChannelBucket{}, errors.Wrap(err, "error aggregating channels")
+	}
+	for _, b := range opened {
+		start, err := time.ParseInLocation(bucketLayout, b.ID, query.Location)
+		if err != nil {
+			return []domain.ChannelBucket{}, errors.Wrap(err, "error aggregating channels")
+		}
+		bucket := buckets.at(start)
+		bucket.Opened += b.Count
+		bucket.Messages += b.Messages
+	}
+
+	for _, clock := range []struct {
+		field  string
+		record func(*domain.ChannelBucket, bucketBSON)
+	}{
+		{field: "firstResponseAt", record: func(bucket *domain.ChannelBucket, b bucketBSON) {
+			bucket.Answered = b.Count
+			bucket.MedianFirstResponse = milliseconds(b.Median)
+		}},
+		{field: "resolvedAt", record: func(bucket *domain.ChannelBucket, b bucketBSON) {
+			bucket.Closed = b.Count
+			bucket.MedianResolution = milliseconds(b.Median)
+		}},
+	} {
+		var (
+			stopped  = make([]bucketBSON, 0)
+			pipeline = clockPipeline(ctx, query, clock.field, bucketKey(query, clock.field), false)
+		)
+		if err := a.db.Aggregate(ctx, a.collectionName, pipeline, &stopped, clockOptions()); err != nil {
+			return []domain.ChannelBucket{}, errors.Wrap(err, "error aggregating channels")
+		}
+		for _, b := range stopped {
+			start, err := time.ParseInLocation(bucketLayout, b.ID, query.Location)
+			if err != nil {
+				return []domain.ChannelBucket{}, errors.Wrap(err, "error aggregating channels")
+			}
+			clock.record(buckets.at(start), b)
+		}
+	}
+
+	return buckets.sorted(), nil
+}
+
+func (a AnalyticsNoSQL) GetBacklog(ctx context.Context, tag string) (map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: analyticsFilter(ctx, tag, bson.M{"currentStatus": bson.M{"$in": domain.OpenStatuses()}})}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$currentStatus"},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+		}}},
+	}
+
+	var statuses = make([]bucketBSON, 0)
+	if err := a.db.Aggregate(ctx, a.collectionName, pipeline, &statuses, nil); err != nil {
+		return map[string]int{}, errors.Wrap(err, "error aggregating backlog")
+	}
+
+	var backlog = make(map[string]int, len(statuses))
+	for _, status := range statuses {
+		backlog[status.ID] = status.Count
+	}
+	return backlog, nil
+}
+
+func (a AnalyticsNoSQL) GetRepBuckets(ctx context.Context, query domain.AnalyticsQuery) ([]domain.RepBucket, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: clockFilter(ctx, query, "resolvedAt", true)}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "rep", Value: "$repEmail"}, {Key: "bucket", Value: bucketKey(query, "resolvedAt")}}},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+		}}},
+	}
+
+	var resolved = make([]repBucketBSON, 0)
+	if err := a.db.Aggregate(ctx, a.collectionName, pipeline, &resolved, nil); err != nil {
+		return []domain.RepBucket{}, errors.Wrap(err, "error aggregating reps")
+	}
+
+	var buckets = make([]domain.RepBucket, 0, len(resolved))
+	for _, b := range resolved {
+		start, err := time.ParseInLocation(bucketLayout, b.ID.Bucket, query.Location)
+		if err != nil {
+			return []domain.RepBucket{}, errors.Wrap(err, "error aggregating reps")
+		}
+		buckets = append(buckets, domain.RepBucket{
+			RepEmail: b.ID.Rep,
+			Start:    start,
+			Resolved: b.Count,
+		})
+	}
+	sortRepBuckets(buckets)
+	return buckets, nil
+}
+
+func (a AnalyticsNoSQL) GetRepResolutions(ctx context.Context, query domain.AnalyticsQuery) ([]domain.RepResolution, error) {
+	var (
+		resolved = make([]repBucketBSON, 0)
+		pipeline = clockPipeline(ctx, query, "resolvedAt", bson.D{{Key: "rep", Value: "$repEmail"}}, true)
+	)
+	if err := a.db.Aggregate(ctx, a.collectionName, pipeline, &resolved, clockOptions()); err != nil {
+		return []domain.RepResolution{}, errors.Wrap(err, "error aggregating reps")
+	}
+
+	var resolutions = make([]domain.RepResolution, 0, len(resolved))
+	for _, b := range resolved {
+		resolutions = append(resolutions, domain.RepResolution{
+			RepEmail:         b.ID.Rep,
+			Resolved:         b.Count,
+			MedianResolution: milliseconds(b.Median),
+		})
+	}
+	sort.Slice(resolutions, func(i, j int) bool { return resolutions[i].RepEmail < resolutions[j].RepEmail })
+	return resolutions, nil
+}
+
+func (a AnalyticsNoSQL) GetRepWorkload(ctx context.Context, tag string) ([]domain.RepWorkload, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: analyticsFilter(ctx, tag, bson.M{
+			"currentStatus": bson.M{"$in": domain.OpenStatuses()},
+			"repEmail":      bson.M{"$ne": ""},
+		})}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "rep", Value: "$repEmail"}, {Key: "status", Value: "$currentStatus"}}},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+		}}},
+	}
+
+	var groups = make([]repBucketBSON, 0)
+	if err := a.db.Aggregate(ctx, a.collectionName, pipeline, &groups, nil); err != nil {
+		return []domain.RepWorkload{}, errors.Wrap(err, "error aggregating workload")
+	}
+
+	var workload = make([]domain.RepWorkload, 0, len(groups))
+	for _, group := range groups {
+		workload = append(workload, domain.RepWorkload{RepEmail: group.ID.Rep, Status: group.ID.Status, Count: group.Count})
+	}
+	sortRepWorkload(workload)
+	return workload, nil
+}
+
+// clockFilter matches the channels whose clock in the field stopped in the
+// range, assigned ones only when assigned is set
+func clockFilter(ctx context.Context, query domain.AnalyticsQuery, field string, assigned bool) bson.M {
+	filter := bson.M{field: timeRange(query.From, query.To)}
+	if assigned {
+		filter["repEmail"] = bson.M{"$ne": ""}
+	}
+	return analyticsFilter(ctx, query.Tag, filter)
+}
+
+// clockPipeline groups the channels whose clock stopped in the range by the key,
+// with the median of how long it ran. The durations of a group are sorted and
+// read in the middle on the database, only the count and the median leave it
+func clockPipeline(ctx context.Context, query domain.AnalyticsQuery, field string, key interface{}, assigned bool) mongo.Pipeline {
+	// middle is the lower middle duration with an offset of 0, the upper with 1
+	middle := func(offset int) bson.M {
+		return bson.M{"$arrayElemAt": bson.A{"$durations", bson.M{"$floor": bson.M{"$divide": bson.A{
+			bson.M{"$subtract": bson.A{bson.M{"$size": "$durations"}, 1 - offset}},
+			2,
+		}}}}}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: clockFilter(ctx, query, field, assigned)}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "key", Value: key},
+			{Key: "duration", Value: bson.M{"$subtract": bson.A{"$" + field, "$createdAt"}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "duration", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$key"},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+			{Key: "durations", Value: bson.M{"$push": "$duration"}},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "count", Value: 1},
+			{Key: "median", Value: bson.M{"$avg": bson.A{middle(0), middle(1)}}},
+		}}},
+	}
+}
+
+// clockOptions lets the sort of a large range spill to disk instead of failing
+func clockOptions() *options.AggregateOptions {
+	return options.Aggregate().SetAllowDiskUse(true)
+}
+
+func analyticsFilter(ctx context.Context, tag string, filter bson.M) bson.M {
+	if tags := domain.NormalizeTags([]string{tag}); len(tags) > 0 {
+		filter["tags"] = tags[0]
+	}
+	return tenantQuery(ctx, filter)
+}
+
+// bucketKey formats a time as the start of its bucket in the time zone of the query
+func bucketKey(query domain.AnalyticsQuery, field string) bson.M {
+	format := "%Y-%m-%dT%H"
+	if query.Interval != domain.AnalyticsByHour {
+		format = "%Y-%m-%dT00"
+	}
+	return bson.M{"$dateToString": bson.M{
+		"format":   format,
+		"date":     "$" + field,
+		"timezone": query.Location.String(),
+	}}
+}
+
+func milliseconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Millisecond))
+}
+
+// channelBuckets merges what the queries of a series count in the same bucket
+type channelBuckets map[int64]*domain.ChannelBucket
+
+func (b channelBuckets) at(start time.Time) *domain.ChannelBucket {
+	bucket, ok := b[start.Unix()]
+	if !ok {
+		bucket = &domain.ChannelBucket{Start: start}
+		b[start.Unix()] = bucket
+	}
+	return bucket
+}
+
+func (b channelBuckets) sorted() []domain.ChannelBucket {
+	var buckets = make([]domain.ChannelBucket, 0, len(b))
+	for _, bucket := range b {
+		buckets = append(buckets, *bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Start.Before(buckets[j].Start) })
+	return buckets
+}
+
+func sortRepBuckets(buckets []domain.RepBucket) {
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].RepEmail != buckets[j].RepEmail {
+			return buckets[i].RepEmail < buckets[j].RepEmail
+		}
+		return buckets[i].Start.Before(buckets[j].Start)
+	})
+}
+
+func sortRepWorkload(workload []domain.RepWorkload) {
+	sort.Slice(workload, func(i, j int) bool {
+		if workload[i].RepEmail != workload[j].RepEmail {
+			return workload[i].RepEmail < workload[j].RepEmail
+		}
+		return workload[i].Status < workload[j].Status
+	})
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+)
+
+// AnalyticsSQL reads the times of the channels in the range and buckets them in
+// the time zone of the query, which the databases do not share a syntax for
+type AnalyticsSQL struct {
+	db SQL
+}
+
+func NewAnalyticsSQL(db SQL) AnalyticsSQL {
+	return AnalyticsSQL{db: db}
+}
+
+func (a AnalyticsSQL) GetChannelBuckets(ctx context.Context, query domain.AnalyticsQuery) ([]domain.ChannelBucket, error) {
+	var buckets = make(channelBuckets)
+
+	where, args := analyticsWhere(ctx, query.Tag, "c.created_at >= ? AND c.created_at < ?", query.From.UTC(), query.To.UTC())
+	rows, err := a.db.Query(
+		ctx,
+		`SELECT c.created_at, (SELECT COUNT(*) FROM channel_messages m WHERE m.channel_id = c.id AND m.internal = ?)
+		FROM channels c WHERE `+where,
+		append([]interface{}{false}, args...)...,
+	)
+	if err != nil {
+		return []domain.ChannelBucket{}, errors.Wrap(err, "error aggregating channels")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			createdAt time.Time
+			messages  int
+		)
+		if err := rows.Scan(&createdAt, &messages); err != nil {
+			return []domain.ChannelBucket{}, errors.Wrap(err, "error aggregating channels")
+		}
+		bucket := buckets.at(query.BucketStart(createdAt))
+		bucket.Opened++
+		bucket.Messages += messages
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.ChannelBucket{}, errors.Wrap(err, "error aggregating channels")
+	}
+
+	for _, clock := range []struct {
+		column string
+		record func(*domain.ChannelBucket, []time.Duration)
+	}{
+		{column: "first_response_at", record: func(bucket *domain.ChannelBucket, durations []time.Duration) {
+			bucket.Answered = len(durations)
+			bucket.MedianFirstResponse, _ = domain.MedianDuration(durations)
+		}},
+		{column: "resolved_at", record: func(bucket *domain.ChannelBucket, durations []time.Duration) {
+			bucket.Closed = len(durations)
+			bucket.MedianResolution, _ = domain.MedianDuration(durations)
+		}},
+	} {
+		var stopped = make(map[*domain.ChannelBucket][]time.Duration)
+		err := a.clocks(ctx, query, clock.column, false, func(_ string, createdAt, stoppedAt time.Time) {
+			bucket := buckets.at(query.BucketStart(stoppedAt))
+			stopped[bucket] = append(stopped[bucket], stoppedAt.Sub(createdAt))
+		})
+		if err != nil {
+			return []domain.ChannelBucket{}, errors.Wrap(err, "error aggregating channels")
+		}
+		for bucket, durations := range stopped {
+			clock.record(bucket, durations)
+		}
+	}
+
+	return buckets.sorted(), nil
+}
+
+func (a AnalyticsSQL) GetBacklog(ctx context.Context, tag string) (map[string]int, error) {
+	where, args := analyticsWhere(ctx, tag, openStatusesWhere(), openStatusesArgs()...)
+	rows, err := a.db.Query(ctx, `SELECT c.current_status, COUNT(*) FROM channels c WHERE `+where+` GROUP BY c.current_status`, args...)
+	if err != nil {
+		return map[string]int{}, errors.Wrap(err, "error aggregating backlog")
+	}
+	defer rows.Close()
+
+	var backlog = make(map[string]int)
+	for rows.Next() {
+		var (
+			status string
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return map[string]int{}, errors.Wrap(err, "error aggregating backlog")
+		}
+		backlog[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return map[string]int{}, errors.Wrap(err, "error aggregating backlog")
+	}
+	return backlog, nil
+}
+
+func (a AnalyticsSQL) GetRepBuckets(ctx context.Context, query domain.AnalyticsQuery) ([]domain.RepBucket, error) {
+	type key struct {
+		rep   string
+		start int64
+	}
+	var (
+		positions = make(map[key]int)
+		buckets   = make([]domain.RepBucket, 0)
+	)
+
+	err := a.clocks(ctx, query, "resolved_at", true, func(rep string, createdAt, resolvedAt time.Time) {
+		start := query.BucketStart(resolvedAt)
+		position, ok := positions[key{rep: rep, start: start.Unix()}]
+		if !ok {
+			buckets = append(buckets, domain.RepBucket{RepEmail: rep, Start: start})
+			position = len(buckets) - 1
+			positions[key{rep: rep, start: start.Unix()}] = position
+		}
+		buckets[position].Resolved++
+	})
+	if err != nil {
+		return []domain.RepBucket{}, errors.Wrap(err, "error aggregating reps")
+	}
+
+	sortRepBuckets(buckets)
+	return buckets, nil
+}
+
+func (a AnalyticsSQL) GetRepResolutions(ctx context.Context, query domain.AnalyticsQuery) ([]domain.RepResolution, error) {
+	var durations = make(map[string][]time.Duration)
+	err := a.clocks(ctx, query, "resolved_at", true, func(rep string, createdAt, resolvedAt time.Time) {
+		durations[rep] = append(durations[rep], resolvedAt.Sub(createdAt))
+	})
+	if err != nil {
+		return []domain.RepResolution{}, errors.Wrap(err, "error aggregating reps")
+	}
+
+	var resolutions = make([]domain.RepResolution, 0, len(durations))
+	for rep, resolved := range durations {
+		median, _ := domain.MedianDuration(resolved)
+		resolutions = append(resolutions, domain.RepResolution{RepEmail: rep, Resolved: len(resolved), MedianResolution: median})
+	}
+	sort.Slice(resolutions, func(i, j int) bool { return resolutions[i].RepEmail < resolutions[j].RepEmail })
+	return resolutions, nil
+}
+
+func (a AnalyticsSQL) GetRepWorkload(ctx context.Context, tag string) ([]domain.RepWorkload, error) {
+	where, args := analyticsWhere(ctx, tag, openStatusesWhere()+" AND c.rep_email <> ?", append(openStatusesArgs(), "")...)
+	rows, err := a.db.Query(
+		ctx,
+		`SELECT c.rep_email, c.current_status, COUNT(*) FROM channels c WHERE `+where+` GROUP BY c.rep_email, c.current_status`,
+		args...,
+	)
+	if err != nil {
+		return []domain.RepWorkload{}, errors.Wrap(err, "error aggregating workload")
+	}
+	defer rows.Close()
+
+	var workload = make([]domain.RepWorkload, 0)
+	for rows.Next() {
+		var w domain.RepWorkload
+		if err := rows.Scan(&w.RepEmail, &w.Status, &w.Count); err != nil {
+			return []domain.RepWorkload{}, errors.Wrap(err, "error aggregating workload")
+		}
+		workload = append(workload, w)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.RepWorkload{}, errors.Wrap(err, "error aggregating workload")
+	}
+
+	sortRepWorkload(workload)
+	return workload, nil
+}
+
+// clocks calls back with every channel whose clock in the column stopped in the
+// range, assigned ones only when withRep is set. A clock still running keeps the
+// zero time, which no range reaches
+func (a AnalyticsSQL) clocks(
+	ctx context.Context,
+	query domain.AnalyticsQuery,
+	column string,
+	withRep bool,
+	record func(rep string, createdAt, stoppedAt time.Time),
+) error {
+	condition := "c." + column + " >= ? AND c." + column + " < ?"
+	if withRep {
+		condition += " AND c.rep_email <> ''"
+	}
+	where, args := analyticsWhere(ctx, query.Tag, condition, query.From.UTC(), query.To.UTC())
+
+	rows, err := a.db.Query(ctx, `SELECT c.rep_email, c.created_at, c.`+column+` FROM channels c WHERE `+where, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rep                  string
+			createdAt, stoppedAt time.Time
+		)
+		if err := rows.Scan(&rep, &createdAt, &stoppedAt); err != nil {
+			return err
+		}
+		record(rep, createdAt, stoppedAt)
+	}
+	return rows.Err()
+}
+
+// analyticsWhere scopes a condition on the channels aliased c to the tenant and the tag
+func analyticsWhere(ctx context.Context, tag, condition string, args ...interface{}) (string, []interface{}) {
+	where := "c.tenant_id = ? AND " + condition
+	args = append([]interface{}{domain.TenantFromContext(ctx)}, args...)
+	if tags := domain.NormalizeTags([]string{tag}); len(tags) > 0 {
+		where += " AND EXISTS (SELECT 1 FROM channel_tags f WHERE f.channel_id = c.id AND f.tag = ?)"
+		args = append(args, tags[0])
+	}
+	return where, args
+}
+
+func openStatusesWhere() string {
+	return "c.current_status IN (?" + strings.Repeat(", ?", len(domain.OpenStatuses())-1) + ")"
+}
+
+func openStatusesArgs() []interface{} {
+	var args []interface{}
+	for _, status := range domain.OpenStatuses() {
+		args = append(args, status)
+	}
+	return args
+}
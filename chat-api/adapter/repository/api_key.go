@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type apiKeyBSON struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name"`
+	Prefix    string             `bson:"prefix"`
+	HashedKey string             `bson:"hashedKey"`
+	Scopes    []string           `bson:"scopes"`
+	CreatedBy string             `bson:"createdBy"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	RevokedAt time.Time          `bson:"revokedAt,omitempty"`
+	TenantId  string             `bson:"tenantId"`
+}
+
+type APIKeyNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewAPIKeyNoSQL(db NoSQL) APIKeyNoSQL {
+	result := APIKeyNoSQL{
+		db:             db,
+		collectionName: "api_keys",
+	}
+
+	err := db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: "prefix", Value: 1}},
+		true,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	err = db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: tenantField, Value: 1}, {Key: "createdAt", Value: -1}},
+		false,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+	return result
+}
+
+func (a APIKeyNoSQL) CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error) {
+	var keyBSON = apiKeyBSON{
+		ID:        key.Id(),
+		Name:      key.Name(),
+		Prefix:    key.Prefix(),
+		HashedKey: key.HashedKey(),
+		Scopes:    key.Scopes(),
+		CreatedBy: key.CreatedBy(),
+		CreatedAt: key.CreatedAt(),
+		TenantId:  domain.TenantFromContext(ctx),
+	}
+
+	if err := a.db.Store(ctx, a.collectionName, keyBSON); err != nil {
+		return domain.APIKey{}, errors.Wrap(err, "error creating api key")
+	}
+
+	key.AssignTenant(keyBSON.TenantId)
+	return key, nil
+}
+
+func (a APIKeyNoSQL) GetAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	var keyBSONs = make([]apiKeyBSON, 0)
+	if err := a.db.FindAll(ctx, a.collectionName, tenantQuery(ctx, bson.M{}), &keyBSONs, findOptions); err != nil {
+		return []domain.APIKey{}, errors.Wrap(err, "error listing api keys")
+	}
+
+	var keys = make([]domain.APIKey, 0)
+	for _, keyBSON := range keyBSONs {
+		keys = append(keys, apiKeyFromBSON(keyBSON))
+	}
+	return keys, nil
+}
+
+func (a APIKeyNoSQL) GetAPIKeyById(ctx context.Context, id string) (domain.APIKey, error) {
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.APIKey{}, domain.ErrAPIKeyNotFound
+	}
+	return a.findOne(ctx, tenantQuery(ctx, bson.M{"_id": idHex}))
+}
+
+func (a APIKeyNoSQL) GetAPIKeyByPrefix(ctx context.Context, prefix string) (domain.APIKey, error) {
+	return a.findOne(ctx, bson.M{"prefix": prefix})
+}
+
+func (a APIKeyNoSQL) RevokeAPIKey(ctx context.Context, key domain.APIKey) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"_id": key.Id()})
+		update = bson.M{"$set": bson.M{"revokedAt": key.RevokedAt()}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error revoking api key")
+	}
+	return nil
+}
+
+func (a APIKeyNoSQL) findOne(ctx context.Context, query bson.M) (domain.APIKey, error) {
+	var keyBSON = &apiKeyBSON{}
+
+	if err := a.db.FindOne(ctx, a.collectionName, query, nil, keyBSON); err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return domain.APIKey{}, domain.ErrAPIKeyNotFound
+		default:
+			return domain.APIKey{}, errors.Wrap(err, "error fetching api key")
+		}
+	}
+	return apiKeyFromBSON(*keyBSON), nil
+}
+
+func apiKeyFromBSON(keyBSON apiKeyBSON) domain.APIKey {
+	key := domain.NewAPIKey(
+		keyBSON.ID,
+		keyBSON.Name,
+		keyBSON.Prefix,
+		keyBSON.HashedKey,
+		keyBSON.Scopes,
+		keyBSON.CreatedBy,
+		keyBSON.CreatedAt,
+	)
+	key.AssignTenant(keyBSON.TenantId)
+	if !keyBSON.RevokedAt.IsZero() {
+		key.Revoke(keyBSON.RevokedAt)
+	}
+	return key
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type attachmentBSON struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	ChannelId   string             `bson:"channelId"`
+	UploadedBy  string             `bson:"uploadedBy"`
+	FileName    string             `bson:"fileName"`
+	ContentType string             `bson:"contentType"`
+	Size        int64              `bson:"size"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	TenantId    string             `bson:"tenantId"`
+}
+
+// AttachmentNoSQL keeps what is known about uploaded files, their content is
+// kept in a domain.BlobStore
+type AttachmentNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewAttachmentNoSQL(db NoSQL) AttachmentNoSQL {
+	result := AttachmentNoSQL{
+		db:             db,
+		collectionName: "attachments",
+	}
+
+	err := db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: tenantField, Value: 1}, {Key: "channelId", Value: 1}},
+		false,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+	return result
+}
+
+func (a AttachmentNoSQL) CreateAttachment(ctx context.Context, attachment domain.Attachment) (domain.Attachment, error) {
+	var attachmentBSON = attachmentBSON{
+		ID:          attachment.Id(),
+		ChannelId:   attachment.ChannelId(),
+		UploadedBy:  attachment.UploadedBy(),
+		FileName:    attachment.FileName(),
+		ContentType: attachment.ContentType(),
+		Size:        attachment.Size(),
+		CreatedAt:   attachment.CreatedAt(),
+		TenantId:    domain.TenantFromContext(ctx),
+	}
+
+	if err := a.db.Store(ctx, a.collectionName, attachmentBSON); err != nil {
+		return domain.Attachment{}, errors.Wrap(err, "error creating attachment")
+	}
+
+	attachment.AssignTenant(attachmentBSON.TenantId)
+	return attachment, nil
+}
+
+func (a AttachmentNoSQL) GetAttachmentById(ctx context.Context, id string) (domain.Attachment, error) {
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Attachment{}, domain.ErrAttachmentNotFound
+	}
+
+	var attachmentBSON = &attachmentBSON{}
+	if err := a.db.FindOne(ctx, a.collectionName, tenantQuery(ctx, bson.M{"_id": idHex}), nil, attachmentBSON); err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return domain.Attachment{}, domain.ErrAttachmentNotFound
+		default:
+			return domain.Attachment{}, errors.Wrap(err, "error fetching attachment")
+		}
+	}
+
+	attachment := domain.NewAttachment(
+		attachmentBSON.ID,
+		attachmentBSON.ChannelId,
+		attachmentBSON.UploadedBy,
+		attachmentBSON.FileName,
+		attachmentBSON.ContentType,
+		attachmentBSON.Size,
+		attachmentBSON.CreatedAt,
+	)
+	attachment.AssignTenant(attachmentBSON.TenantId)
+	return attachment, nil
+}
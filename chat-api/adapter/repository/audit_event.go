@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auditEventBSON struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Action    string             `bson:"action"`
+	Actor     string             `bson:"actor"`
+	Target    string             `bson:"target"`
+	IP        string             `bson:"ip"`
+	Outcome   string             `bson:"outcome"`
+	Timestamp time.Time          `bson:"timestamp"`
+	TenantId  string             `bson:"tenantId"`
+}
+
+type AuditEventNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewAuditEventNoSQL(db NoSQL) AuditEventNoSQL {
+	result := AuditEventNoSQL{
+		db:             db,
+		collectionName: "audit_events",
+	}
+
+	for _, field := range []string{"timestamp", "actor", "target"} {
+		err := db.EnsureIndex(
+			context.Background(),
+			result.collectionName,
+			bson.D{{Key: tenantField, Value: 1}, {Key: field, Value: -1}},
+			false,
+		)
+		if err != nil {
+			log.Panic(err)
+		}
+	}
+	return result
+}
+
+func (a AuditEventNoSQL) StoreAuditEvent(ctx context.Context, event domain.AuditEvent) error {
+	var eventBSON = auditEventBSON{
+		ID:        primitive.NewObjectID(),
+		Action:    event.Action,
+		Actor:     event.Actor,
+		Target:    event.Target,
+		IP:        event.IP,
+		Outcome:   event.Outcome,
+		Timestamp: event.Timestamp,
+		TenantId:  domain.TenantFromContext(ctx),
+	}
+
+	if err := a.db.Store(ctx, a.collectionName, eventBSON); err != nil {
+		return errors.Wrap(err, "error storing audit event")
+	}
+	return nil
+}
+
+func (a AuditEventNoSQL) GetAuditEvents(ctx context.Context, query domain.AuditQuery, start, limit int) ([]domain.AuditEvent, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	findOptions.SetSkip(int64(start))
+	findOptions.SetLimit(int64(limit))
+
+	var eventBSONs = make([]auditEventBSON, 0)
+	if err := a.db.FindAll(ctx, a.collectionName, tenantQuery(ctx, auditQuery(query)), &eventBSONs, findOptions); err != nil {
+		return []domain.AuditEvent{}, errors.Wrap(err, "error listing audit events")
+	}
+
+	var events = make([]domain.AuditEvent, 0)
+	for _, eventBSON := range eventBSONs {
+		events = append(events, domain.AuditEvent{
+			Action:    eventBSON.Action,
+			Actor:     eventBSON.Actor,
+			Target:    eventBSON.Target,
+			IP:        eventBSON.IP,
+			Outcome:   eventBSON.Outcome,
+			Timestamp: eventBSON.Timestamp,
+		})
+	}
+	return events, nil
+}
+
+func (a AuditEventNoSQL) GetAuditEventsCount(ctx context.Context, query domain.AuditQuery) (int64, error) {
+	count, err := a.db.FindCount(ctx, a.collectionName, tenantQuery(ctx, auditQuery(query)))
+	if err != nil {
+		return 0, errors.Wrap(err, "error counting audit events")
+	}
+	return count, nil
+}
+
+func auditQuery(query domain.AuditQuery) bson.M {
+	filter := bson.M{}
+	if query.Actor != "" {
+		filter["actor"] = query.Actor
+	}
+	if query.Target != "" {
+		filter["target"] = query.Target
+	}
+
+	if r := timeRange(query.From, query.To); len(r) > 0 {
+		filter["timestamp"] = r
+	}
+	return filter
+}
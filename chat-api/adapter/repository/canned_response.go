@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type macroBSON struct {
+	Status     string   `bson:"status,omitempty"`
+	AddTags    []string `bson:"addTags,omitempty"`
+	RemoveTags []string `bson:"removeTags,omitempty"`
+}
+
+type cannedResponseBSON struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Shortcut  string             `bson:"shortcut"`
+	Title     string             `bson:"title"`
+	Body      string             `bson:"body"`
+	Tags      []string           `bson:"tags,omitempty"`
+	Owner     string             `bson:"owner"`
+	Shared    bool               `bson:"shared"`
+	Macro     macroBSON          `bson:"macro"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt"`
+	TenantId  string             `bson:"tenantId"`
+}
+
+type CannedResponseNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewCannedResponseNoSQL(db NoSQL) CannedResponseNoSQL {
+	result := CannedResponseNoSQL{
+		db:             db,
+		collectionName: "canned_responses",
+	}
+
+	err := db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: tenantField, Value: 1}, {Key: "shortcut", Value: 1}},
+		false,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	err = db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: tenantField, Value: 1}, {Key: "owner", Value: 1}},
+		false,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+	return result
+}
+
+func (a CannedResponseNoSQL) CreateCannedResponse(ctx context.Context, response domain.CannedResponse) (domain.CannedResponse, error) {
+	var responseBSON = cannedResponseToBSON(response)
+	responseBSON.TenantId = domain.TenantFromContext(ctx)
+
+	if err := a.db.Store(ctx, a.collectionName, responseBSON); err != nil {
+		return domain.CannedResponse{}, errors.Wrap(err, "error creating canned response")
+	}
+
+	response.AssignTenant(responseBSON.TenantId)
+	return response, nil
+}
+
+func (a CannedResponseNoSQL) GetCannedResponseById(ctx context.Context, id string) (domain.CannedResponse, error) {
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.CannedResponse{}, domain.ErrCannedResponseNotFound
+	}
+
+	var responseBSON = &cannedResponseBSON{}
+	if err := a.db.FindOne(ctx, a.collectionName, tenantQuery(ctx, bson.M{"_id": idHex}), nil, responseBSON); err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return domain.CannedResponse{}, domain.ErrCannedResponseNotFound
+		default:
+			return domain.CannedResponse{}, errors.Wrap(err, "error fetching canned response")
+		}
+	}
+	return cannedResponseFromBSON(*responseBSON), nil
+}
+
+func (a CannedResponseNoSQL) GetCannedResponses(ctx context.Context, query domain.CannedResponseQuery) ([]domain.CannedResponse, error) {
+	var filter = bson.M{}
+	if query.VisibleTo != "" {
+		filter["$or"] = bson.A{
+			bson.M{"shared": true},
+			bson.M{"owner": domain.NormalizeEmail(query.VisibleTo)},
+		}
+	}
+	if query.Shortcut != "" {
+		filter["shortcut"] = domain.NormalizeShortcut(query.Shortcut)
+	} else if query.ShortcutPrefix != "" {
+		filter["shortcut"] = bson.M{"$regex": "^" + regexp.QuoteMeta(domain.NormalizeShortcut(query.ShortcutPrefix))}
+	}
+	if tags := domain.NormalizeTags([]string{query.Tag}); len(tags) > 0 {
+		filter["tags"] = tags[0]
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "shortcut", Value: 1}})
+
+	var responseBSONs = make([]cannedResponseBSON, 0)
+	if err := a.db.FindAll(ctx, a.collectionName, tenantQuery(ctx, filter), &responseBSONs, findOptions); err != nil {
+		return []domain.CannedResponse{}, errors.Wrap(err, "error listing canned responses")
+	}
+
+	var responses = make([]domain.CannedResponse, 0)
+	for _, responseBSON := range responseBSONs {
+		responses = append(responses, cannedResponseFromBSON(responseBSON))
+	}
+	return responses, nil
+}
+
+func (a CannedResponseNoSQL) UpdateCannedResponse(ctx context.Context, response domain.CannedResponse) error {
+	var (
+		responseBSON = cannedResponseToBSON(response)
+		query        = tenantQuery(ctx, bson.M{"_id": response.Id()})
+		update       = bson.M{"$set": bson.M{
+			"shortcut":  responseBSON.Shortcut,
+			"title":     responseBSON.Title,
+			"body":      responseBSON.Body,
+			"tags":      append(make([]string, 0), responseBSON.Tags...),
+			"shared":    responseBSON.Shared,
+			"macro":     responseBSON.Macro,
+			"updatedAt": responseBSON.UpdatedAt,
+		}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		switch err {
+		case mongo.ErrNilDocument:
+			return errors.Wrap(domain.ErrCannedResponseNotFound, "error updating canned response")
+		default:
+			return errors.Wrap(err, "error updating canned response")
+		}
+	}
+	return nil
+}
+
+func (a CannedResponseNoSQL) DeleteCannedResponse(ctx context.Context, response domain.CannedResponse) error {
+	var query = tenantQuery(ctx, bson.M{"_id": response.Id()})
+
+	if err := a.db.FindOneAndDelete(ctx, a.collectionName, query, &cannedResponseBSON{}); err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return errors.Wrap(domain.ErrCannedResponseNotFound, "error deleting canned response")
+		default:
+			return errors.Wrap(err, "error deleting canned response")
+		}
+	}
+	return nil
+}
+
+func cannedResponseToBSON(response domain.CannedResponse) cannedResponseBSON {
+	macro := response.Macro()
+	return cannedResponseBSON{
+		ID:       response.Id(),
+		Shortcut: response.Shortcut(),
+		Title:    response.Title(),
+		Body:     response.Body(),
+		Tags:     response.Tags(),
+		Owner:    response.Owner(),
+		Shared:   response.IsShared(),
+		Macro: macroBSON{
+			Status:     macro.Status,
+			AddTags:    macro.AddTags,
+			RemoveTags: macro.RemoveTags,
+		},
+		CreatedAt: response.CreatedAt(),
+		UpdatedAt: response.UpdatedAt(),
+		TenantId:  response.TenantId(),
+	}
+}
+
+func cannedResponseFromBSON(responseBSON cannedResponseBSON) domain.CannedResponse {
+	response := domain.NewCannedResponse(
+		responseBSON.ID,
+		responseBSON.Shortcut,
+		responseBSON.Title,
+		responseBSON.Body,
+		responseBSON.Tags,
+		responseBSON.Owner,
+		responseBSON.Shared,
+		domain.Macro{
+			Status:     responseBSON.Macro.Status,
+			AddTags:    responseBSON.Macro.AddTags,
+			RemoveTags: responseBSON.Macro.RemoveTags,
+		},
+		responseBSON.CreatedAt,
+		responseBSON.UpdatedAt,
+	)
+	response.AssignTenant(responseBSON.TenantId)
+	return response
+}
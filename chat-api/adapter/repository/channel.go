@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"log"
+	"sort"
 	"time"
 
 	"chat-api/domain"
@@ -20,21 +21,74 @@ type StatusHistory struct {
 	Timestamp int64  `bson:"timestamp"`
 }
 
+type MessageEdit struct {
+	Message  string    `bson:"message"`
+	EditedAt time.Time `bson:"editedAt"`
+}
+
+type MessageAttachment struct {
+	Id          primitive.ObjectID `bson:"id"`
+	FileName    string             `bson:"fileName"`
+	ContentType string             `bson:"contentType"`
+	Size        int64              `bson:"size"`
+}
+
 type Messages struct {
-	MessageFrom string    `bson:"messageFrom"`
-	Message     string    `bson:"message"`
-	Timestamp   time.Time `bson:"timestamp"`
+	Id          primitive.ObjectID  `bson:"id,omitempty"`
+	MessageFrom string              `bson:"messageFrom"`
+	Message     string              `bson:"message"`
+	Timestamp   time.Time           `bson:"timestamp"`
+	IsDeleted   bool                `bson:"isDeleted,omitempty"`
+	Internal    bool                `bson:"internal,omitempty"`
+	EditedAt    time.Time           `bson:"editedAt,omitempty"`
+	DeletedAt   time.Time           `bson:"deletedAt,omitempty"`
+	Edits       []MessageEdit       `bson:"edits,omitempty"`
+	Attachments []MessageAttachment `bson:"attachments,omitempty"`
+}
+
+type ReadMarker struct {
+	Participant string    `bson:"participant"`
+	LastReadAt  time.Time `bson:"lastReadAt"`
+}
+
+// ChannelAttribute is stored as a name and value pair so a single index serves every attribute
+type ChannelAttribute struct {
+	Name  string `bson:"name"`
+	Value string `bson:"value"`
+}
+
+type SatisfactionRating struct {
+	Completion int       `bson:"completion"`
+	Score      int       `bson:"score"`
+	Comment    string    `bson:"comment,omitempty"`
+	RepEmail   string    `bson:"repEmail"`
+	RatedBy    string    `bson:"ratedBy"`
+	RatedAt    time.Time `bson:"ratedAt"`
 }
 
 type channelBSON struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty"`
-	RepEmail      string             `bson:"repEmail"`
-	UserEmail     string             `bson:"userEmail"`
-	CurrentStatus string             `bson:"currentStatus"`
-	StatusHistory []StatusHistory    `bson:"statusHistory"`
-	Messages      []Messages         `bson:"messages"`
-	CreatedAt     time.Time          `bson:"createdAt,omitempty"`
-	UpdatedAt     time.Time          `bson:"updatedAt,omitempty"`
+	ID            primitive.ObjectID   `bson:"_id,omitempty"`
+	RepEmail      string               `bson:"repEmail"`
+	UserEmail     string               `bson:"userEmail"`
+	UserFullName  string               `bson:"userFullName,omitempty"`
+	Guest         bool                 `bson:"guest,omitempty"`
+	TenantId      string               `bson:"tenantId"`
+	CurrentStatus string               `bson:"currentStatus"`
+	StatusHistory []StatusHistory      `bson:"statusHistory"`
+	Messages      []Messages           `bson:"messages"`
+	ReadMarkers   []ReadMarker         `bson:"readMarkers,omitempty"`
+	Tags          []string             `bson:"tags,omitempty"`
+	Priority      int                  `bson:"priority"`
+	Attributes    []ChannelAttribute   `bson:"attributes,omitempty"`
+	Ratings       []SatisfactionRating `bson:"ratings,omitempty"`
+	CreatedAt     time.Time            `bson:"createdAt,omitempty"`
+	UpdatedAt     time.Time            `bson:"updatedAt,omitempty"`
+	LastMessageAt time.Time            `bson:"lastMessageAt,omitempty"`
+	// FirstResponseAt and ResolvedAt are derived from the history, they are
+	// stored so listings and reports do not need the messages
+	FirstResponseAt time.Time `bson:"firstResponseAt,omitempty"`
+	ResolvedAt      time.Time `bson:"resolvedAt,omitempty"`
+	SLABreaches     []string  `bson:"slaBreaches,omitempty"`
 }
 
 type ChannelNoSQL struct {
@@ -48,17 +102,47 @@ func NewChannelNoSQL(db NoSQL) ChannelNoSQL {
 		collectionName: "channels",
 	}
 
-	for _, key := range []string{"userEmail", "repEmail", "currentStatus"} {
+	keys := []string{
+		"userEmail",
+		"repEmail",
+		"currentStatus",
+		domain.ChannelSortCreatedAt,
+		domain.ChannelSortUpdatedAt,
+		domain.ChannelSortLastMessageAt,
+		domain.ChannelSortPriority,
+		"tags",
+	}
+	for _, key := range keys {
 		err := db.EnsureIndex(
 			context.Background(),
 			result.collectionName,
-			bson.D{{Key: key, Value: 1}},
+			bson.D{{Key: tenantField, Value: 1}, {Key: key, Value: 1}},
 			false,
 		)
 		if err != nil {
 			log.Panic(err)
 		}
 	}
+
+	err := db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: tenantField, Value: 1}, {Key: "attributes.name", Value: 1}, {Key: "attributes.value", Value: 1}},
+		false,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	err = db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: tenantField, Value: 1}, {Key: "ratings.ratedAt", Value: 1}},
+		false,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
 	return result
 }
 
@@ -67,9 +151,17 @@ func (a ChannelNoSQL) CreateChannel(ctx context.Context, channel domain.Channel)
 		ID:            channel.Id(),
 		RepEmail:      channel.RepEmail(),
 		UserEmail:     channel.UserEmail(),
+		UserFullName:  channel.UserFullName(),
+		Guest:         channel.IsGuest(),
 		CurrentStatus: channel.CurrentStatus(),
+		Tags:          channel.Tags(),
+		Priority:      channel.PriorityRank(),
+		Attributes:    attributesToBSON(channel),
 		CreatedAt:     channel.CreatedAt(),
 		UpdatedAt:     channel.UpdatedAt(),
+		TenantId:      domain.TenantFromContext(ctx),
+		// A channel opened as completed is already resolved
+		ResolvedAt: channel.ResolvedAt(),
 	}
 
 	for _, status := range channel.StatusHistory() {
@@ -84,6 +176,7 @@ func (a ChannelNoSQL) CreateChannel(ctx context.Context, channel domain.Channel)
 		return domain.Channel{}, errors.Wrap(err, "error creating channel")
 	}
 
+	channel.AssignTenant(channelBSON.TenantId)
 	return channel, nil
 }
 
@@ -96,7 +189,7 @@ func (a ChannelNoSQL) GetChannelById(ctx context.Context, id string) (domain.Cha
 
 	var (
 		channelBSON = &channelBSON{}
-		query       = bson.M{"_id": idHex}
+		query       = tenantQuery(ctx, bson.M{"_id": idHex})
 	)
 
 	if err := a.db.FindOne(ctx, a.collectionName, query, nil, channelBSON); err != nil {
@@ -115,20 +208,34 @@ func (a ChannelNoSQL) GetChannelById(ctx context.Context, id string) (domain.Cha
 		channelBSON.UpdatedAt,
 	)
 	channel.UpdateRepEmail(channelBSON.RepEmail)
+	channel.UpdateUserFullName(channelBSON.UserFullName)
+	channel.AssignTenant(channelBSON.TenantId)
+	if channelBSON.Guest {
+		channel.MarkGuest()
+	}
 	for _, status := range channelBSON.StatusHistory {
 		channel.UpdateStatus(status.Status, status.UpdatedBy, status.Timestamp)
 	}
 
 	for _, message := range channelBSON.Messages {
-		channel.AddMessage(message.MessageFrom, message.Message, message.Timestamp)
+		channel.RestoreMessage(messageFromBSON(message))
+	}
+	for _, marker := range channelBSON.ReadMarkers {
+		channel.MarkRead(marker.Participant, marker.LastReadAt)
 	}
+	for _, rating := range channelBSON.Ratings {
+		channel.RestoreRating(ratingFromBSON(rating))
+	}
+	// The history already stopped the SLA clocks
+	channel.RestoreSLA(time.Time{}, time.Time{}, channelBSON.SLABreaches)
+	restoreDetails(&channel, *channelBSON)
 
 	return channel, nil
 }
 
-func (a ChannelNoSQL) GetChannelsByQueryCount(ctx context.Context, query interface{}) (int64, error) {
+func (a ChannelNoSQL) GetChannelsByQueryCount(ctx context.Context, query domain.ChannelQuery) (int64, error) {
 
-	count, err := a.db.FindCount(ctx, a.collectionName, query)
+	count, err := a.db.FindCount(ctx, a.collectionName, tenantQuery(ctx, channelsQuery(query)))
 	if err != nil {
 		switch err {
 		case mongo.ErrNilDocument:
@@ -140,25 +247,16 @@ func (a ChannelNoSQL) GetChannelsByQueryCount(ctx context.Context, query interfa
 	return count, nil
 }
 
-func (a ChannelNoSQL) GetChannelsByQuery(ctx context.Context, query interface{}, start, limit int) ([]domain.Channel, error) {
-	// lookupStage := bson.D{{"$lookup", bson.D{{"from", "podcasts"}, {"localField", "podcast"}, {"foreignField", "_id"}, {"as", "podcast"}}}}
-	// unwindStage := bson.D{{"$unwind", bson.D{{"path", "$podcast"}, {"preserveNullAndEmptyArrays", false}}}}
-
-	// showLoadedCursor, err := episodesCollection.Aggregate(ctx, mongo.Pipeline{lookupStage, unwindStage})
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// var showsLoaded []bson.M
-	// if err = showLoadedCursor.All(ctx, &showsLoaded); err != nil {
-	// 	panic(err)
-	// }
-	// fmt.Println(showsLoaded)
-
+func (a ChannelNoSQL) GetChannelsByQuery(ctx context.Context, query domain.ChannelQuery) ([]domain.Channel, error) {
 	findOptions := options.Find()
-	findOptions.SetSkip(int64(start))
-	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(channelsSort(query))
+	findOptions.SetSkip(int64(query.Skip))
+	if query.Limit > 0 {
+		findOptions.SetLimit(int64(query.Limit))
+	}
+
 	var channelBSONs = make([]channelBSON, 0)
-	if err := a.db.FindAll(ctx, a.collectionName, query, &channelBSONs, findOptions); err != nil {
+	if err := a.db.FindAll(ctx, a.collectionName, tenantQuery(ctx, channelsQuery(query)), &channelBSONs, findOptions); err != nil {
 		switch err {
 		case mongo.ErrNilDocument:
 			return []domain.Channel{}, errors.Wrap(domain.ErrUserNotFound, "error listing channels")
@@ -170,20 +268,194 @@ func (a ChannelNoSQL) GetChannelsByQuery(ctx context.Context, query interface{},
 	var channels = make([]domain.Channel, 0)
 
 	for _, channelBSON := range channelBSONs {
-		var channel = domain.NewChannel(
-			channelBSON.ID,
-			channelBSON.UserEmail,
-			channelBSON.CurrentStatus,
-			channelBSON.CreatedAt,
-			channelBSON.UpdatedAt,
-		)
-		channel.UpdateRepEmail(channelBSON.RepEmail)
+		channel := channelSummaryFromBSON(channelBSON)
+		if query.Reader != "" {
+			channel.UpdateUnreadCount(unreadCount(channelBSON, query.Reader, query.Internal))
+		}
 		channels = append(channels, channel)
 	}
 
 	return channels, nil
 }
 
+// channelSummaryFromBSON reads a channel for listings, without its history and messages
+func channelSummaryFromBSON(channelBSON channelBSON) domain.Channel {
+	var channel = domain.NewChannel(
+		channelBSON.ID,
+		channelBSON.UserEmail,
+		channelBSON.CurrentStatus,
+		channelBSON.CreatedAt,
+		channelBSON.UpdatedAt,
+	)
+	channel.UpdateRepEmail(channelBSON.RepEmail)
+	channel.UpdateUserFullName(channelBSON.UserFullName)
+	channel.UpdateLastMessageAt(channelBSON.LastMessageAt)
+	channel.RestoreSLA(channelBSON.FirstResponseAt, channelBSON.ResolvedAt, channelBSON.SLABreaches)
+	channel.AssignTenant(channelBSON.TenantId)
+	restoreDetails(&channel, channelBSON)
+	if channelBSON.Guest {
+		channel.MarkGuest()
+	}
+	return channel
+}
+
+// restoreDetails reads the tags, the priority and the custom attributes back
+func restoreDetails(channel *domain.Channel, channelBSON channelBSON) {
+	channel.AddTags(channelBSON.Tags...)
+	_ = channel.UpdatePriority(domain.PriorityFromRank(channelBSON.Priority))
+
+	attributes := make(map[string]string, len(channelBSON.Attributes))
+	for _, attribute := range channelBSON.Attributes {
+		attributes[attribute.Name] = attribute.Value
+	}
+	_ = channel.UpdateAttributes(attributes)
+}
+
+func ratingFromBSON(rating SatisfactionRating) domain.SatisfactionRating {
+	return domain.SatisfactionRating{
+		Completion: rating.Completion,
+		Score:      rating.Score,
+		Comment:    rating.Comment,
+		RepEmail:   rating.RepEmail,
+		RatedBy:    rating.RatedBy,
+		RatedAt:    rating.RatedAt,
+	}
+}
+
+func attributesToBSON(channel domain.Channel) []ChannelAttribute {
+	attributes := make([]ChannelAttribute, 0, len(channel.Attributes()))
+	for _, name := range channel.AttributeNames() {
+		attributes = append(attributes, ChannelAttribute{Name: name, Value: channel.Attributes()[name]})
+	}
+	return attributes
+}
+
+// unreadCount counts the messages of a listed channel the reader has not read,
+// internal notes only count for readers who can see them
+func unreadCount(channelBSON channelBSON, reader string, internal bool) int {
+	var (
+		participant = domain.NormalizeEmail(reader)
+		lastReadAt  time.Time
+		count       int
+	)
+	for _, marker := range channelBSON.ReadMarkers {
+		if marker.Participant == participant {
+			lastReadAt = marker.LastReadAt
+		}
+	}
+	for _, message := range channelBSON.Messages {
+		if message.Internal && !internal {
+			continue
+		}
+		if messageFromBSON(message).UnreadBy(participant, lastReadAt) {
+			count++
+		}
+	}
+	return count
+}
+
+func channelsQuery(query domain.ChannelQuery) bson.M {
+	filter := bson.M{}
+	if query.UserEmail != "" {
+		filter["userEmail"] = query.UserEmail
+	}
+	if query.RepEmail != "" {
+		filter["repEmail"] = query.RepEmail
+	}
+	if query.Unassigned {
+		filter["repEmail"] = ""
+	}
+	if len(query.Statuses) > 0 {
+		filter["currentStatus"] = bson.M{"$in": query.Statuses}
+	}
+	if tags := domain.NormalizeTags(query.Tags); len(tags) > 0 {
+		filter["tags"] = bson.M{"$all": tags}
+	}
+	if len(query.Priorities) > 0 {
+		ranks := make([]int, 0, len(query.Priorities))
+		for _, priority := range query.Priorities {
+			ranks = append(ranks, domain.PriorityRank(priority))
+		}
+		filter["priority"] = bson.M{"$in": ranks}
+	}
+	if len(query.Attributes) > 0 {
+		// Each attribute has to match a single element, not a name and a value of different ones
+		var attributes bson.A
+		for name, value := range query.Attributes {
+			attributes = append(attributes, bson.M{"attributes": bson.M{"$elemMatch": bson.M{"name": name, "value": value}}})
+		}
+		filter["$and"] = attributes
+	}
+	if r := timeRange(query.CreatedFrom, query.CreatedTo); len(r) > 0 {
+		filter["createdAt"] = r
+	}
+	if r := timeRange(query.UpdatedFrom, query.UpdatedTo); len(r) > 0 {
+		filter["updatedAt"] = r
+	}
+	if query.After != nil {
+		filter["$or"] = channelsAfter(query)
+	}
+	return filter
+}
+
+// channelsAfter matches the channels past the cursor in the order of the query.
+// Zero times are never stored, a channel without messages has no lastMessageAt
+// and sorts before every channel that has one
+func channelsAfter(query domain.ChannelQuery) bson.A {
+	var (
+		field    = channelsSort(query)[0].Key
+		operator = "$gt"
+		value    interface{}
+	)
+	if query.SortDescending {
+		operator = "$lt"
+	}
+	if field == domain.ChannelSortPriority {
+		value = query.After.Rank
+	} else if !query.After.Value.IsZero() {
+		value = query.After.Value
+	}
+
+	after := bson.A{
+		bson.M{field: value, "_id": bson.M{operator: query.After.Id}},
+	}
+	if value != nil {
+		after = append(after, bson.M{field: bson.M{operator: value}})
+		if query.SortDescending {
+			after = append(after, bson.M{field: nil})
+		}
+	} else if !query.SortDescending {
+		after = append(after, bson.M{field: bson.M{"$ne": nil}})
+	}
+	return after
+}
+
+// timeRange matches times from the inclusive lower bound to the exclusive upper one
+func timeRange(from, to time.Time) bson.M {
+	r := bson.M{}
+	if !from.IsZero() {
+		r["$gte"] = from
+	}
+	if !to.IsZero() {
+		r["$lt"] = to
+	}
+	return r
+}
+
+func channelsSort(query domain.ChannelQuery) bson.D {
+	var (
+		field     = domain.ChannelSortCreatedAt
+		direction = 1
+	)
+	if query.SortBy != "" {
+		field = query.SortBy
+	}
+	if query.SortDescending {
+		direction = -1
+	}
+	return bson.D{{Key: field, Value: direction}, {Key: "_id", Value: direction}}
+}
+
 func (a ChannelNoSQL) UpdateChannelStatus(ctx context.Context, channel domain.Channel) error {
 
 	statusHistory := make([]StatusHistory, 0)
@@ -195,8 +467,13 @@ func (a ChannelNoSQL) UpdateChannelStatus(ctx context.Context, channel domain.Ch
 		})
 	}
 	var (
-		query  = bson.M{"_id": channel.Id()}
-		update = bson.M{"$set": bson.M{"statusHistory": statusHistory, "repEmail": channel.RepEmail(), "currentStatus": channel.CurrentStatus()}}
+		query  = tenantQuery(ctx, bson.M{"_id": channel.Id()})
+		update = withSLAClocks(bson.M{
+			"statusHistory": statusHistory,
+			"repEmail":      channel.RepEmail(),
+			"currentStatus": channel.CurrentStatus(),
+			"updatedAt":     time.Now(),
+		}, channel)
 	)
 
 	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
@@ -211,28 +488,285 @@ func (a ChannelNoSQL) UpdateChannelStatus(ctx context.Context, channel domain.Ch
 }
 
 func (a ChannelNoSQL) AddMessage(ctx context.Context, channel domain.Channel) error {
+	return a.storeMessages(ctx, channel, "error adding message")
+}
+
+func (a ChannelNoSQL) UpdateMessages(ctx context.Context, channel domain.Channel) error {
+	return a.storeMessages(ctx, channel, "error updating messages")
+}
+
+func (a ChannelNoSQL) storeMessages(ctx context.Context, channel domain.Channel, errorMessage string) error {
 
 	messages := make([]Messages, 0)
 	for _, message := range channel.Messages() {
-		messages = append(messages, Messages{
-			MessageFrom: message.MessageFrom,
-			Message:     message.Message,
-			Timestamp:   message.Timestamp,
+		messages = append(messages, messageToBSON(message))
+	}
+
+	var (
+		query  = tenantQuery(ctx, bson.M{"_id": channel.Id()})
+		update = withSLAClocks(bson.M{
+			"messages":      messages,
+			"lastMessageAt": channel.LastMessageAt(),
+			"updatedAt":     time.Now(),
+		}, channel)
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		switch err {
+		case mongo.ErrNilDocument:
+			return errors.Wrap(domain.ErrUserNotFound, errorMessage)
+		default:
+			return errors.Wrap(err, errorMessage)
+		}
+	}
+	return nil
+}
+
+// withSLAClocks sets the times that stopped the SLA clocks along with the other
+// changes, a clock that runs again after a reopen is unset
+func withSLAClocks(set bson.M, channel domain.Channel) bson.M {
+	var (
+		update = bson.M{}
+		unset  = bson.M{}
+		clocks = map[string]time.Time{
+			"firstResponseAt": channel.FirstResponseAt(),
+			"resolvedAt":      channel.ResolvedAt(),
+		}
+	)
+	for field, stoppedAt := range clocks {
+		if stoppedAt.IsZero() {
+			unset[field] = ""
+			continue
+		}
+		set[field] = stoppedAt
+	}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update
+}
+
+// AddSLABreaches leaves updatedAt alone, the SLA is measured on the channel and not
+// a change to it. Only the breaches are added, the channel may be a stale read
+func (a ChannelNoSQL) AddSLABreaches(ctx context.Context, channel domain.Channel, breaches []string) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"_id": channel.Id()})
+		update = bson.M{"$addToSet": bson.M{"slaBreaches": bson.M{"$each": append([]string{}, breaches...)}}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		switch err {
+		case mongo.ErrNilDocument:
+			return errors.Wrap(domain.ErrUserNotFound, "error adding SLA breaches")
+		default:
+			return errors.Wrap(err, "error adding SLA breaches")
+		}
+	}
+	return nil
+}
+
+// UpdateSLAClocks leaves updatedAt alone, the SLA is measured on the channel and not a change to it
+func (a ChannelNoSQL) UpdateSLAClocks(ctx context.Context, channel domain.Channel) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"_id": channel.Id()})
+		update = withSLAClocks(bson.M{}, channel)
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		switch err {
+		case mongo.ErrNilDocument:
+			return errors.Wrap(domain.ErrUserNotFound, "error updating SLA clocks")
+		default:
+			return errors.Wrap(err, "error updating SLA clocks")
+		}
+	}
+	return nil
+}
+
+// UpdateReadMarkers leaves updatedAt alone, reading a channel does not change it
+func (a ChannelNoSQL) UpdateReadMarkers(ctx context.Context, channel domain.Channel) error {
+	markers := make([]ReadMarker, 0)
+	for _, marker := range channel.ReadMarkers() {
+		markers = append(markers, ReadMarker{Participant: marker.Participant, LastReadAt: marker.LastReadAt})
+	}
+
+	var (
+		query  = tenantQuery(ctx, bson.M{"_id": channel.Id()})
+		update = bson.M{"$set": bson.M{"readMarkers": markers}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		switch err {
+		case mongo.ErrNilDocument:
+			return errors.Wrap(domain.ErrUserNotFound, "error updating read markers")
+		default:
+			return errors.Wrap(err, "error updating read markers")
+		}
+	}
+	return nil
+}
+
+// UpdateRatings leaves updatedAt alone, a rating is feedback on the channel and not a change to it
+func (a ChannelNoSQL) UpdateRatings(ctx context.Context, channel domain.Channel) error {
+	ratings := make([]SatisfactionRating, 0)
+	for _, rating := range channel.Ratings() {
+		ratings = append(ratings, SatisfactionRating{
+			Completion: rating.Completion,
+			Score:      rating.Score,
+			Comment:    rating.Comment,
+			RepEmail:   rating.RepEmail,
+			RatedBy:    rating.RatedBy,
+			RatedAt:    rating.RatedAt,
 		})
 	}
 
 	var (
-		query  = bson.M{"_id": channel.Id()}
-		update = bson.M{"$set": bson.M{"messages": messages}}
+		query  = tenantQuery(ctx, bson.M{"_id": channel.Id()})
+		update = bson.M{"$set": bson.M{"ratings": ratings}}
 	)
 
 	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
 		switch err {
 		case mongo.ErrNilDocument:
-			return errors.Wrap(domain.ErrUserNotFound, "error adding message")
+			return errors.Wrap(domain.ErrUserNotFound, "error updating ratings")
 		default:
-			return errors.Wrap(err, "error adding message")
+			return errors.Wrap(err, "error updating ratings")
 		}
 	}
 	return nil
 }
+
+// GetSatisfactionRatings reads the channels rated in the range, only their ratings
+// and tags, and keeps the ratings that match the query
+func (a ChannelNoSQL) GetSatisfactionRatings(ctx context.Context, query domain.SatisfactionQuery) ([]domain.SatisfactionRating, error) {
+	filter := bson.M{"ratings": bson.M{"$elemMatch": bson.M{"ratedAt": timeRange(query.From, query.To)}}}
+	if tags := domain.NormalizeTags([]string{query.Tag}); len(tags) > 0 {
+		filter["tags"] = tags[0]
+	}
+
+	findOptions := options.Find()
+	findOptions.SetProjection(bson.M{"ratings": 1, "tags": 1})
+
+	var channelBSONs = make([]channelBSON, 0)
+	if err := a.db.FindAll(ctx, a.collectionName, tenantQuery(ctx, filter), &channelBSONs, findOptions); err != nil {
+		return []domain.SatisfactionRating{}, errors.Wrap(err, "error listing ratings")
+	}
+
+	var ratings = make([]domain.SatisfactionRating, 0)
+	for _, channelBSON := range channelBSONs {
+		for _, ratingBSON := range channelBSON.Ratings {
+			rating := ratingFromBSON(ratingBSON)
+			rating.ChannelId = channelBSON.ID
+			rating.Tags = channelBSON.Tags
+			if satisfactionMatches(rating, query) {
+				ratings = append(ratings, rating)
+			}
+		}
+	}
+	sort.Slice(ratings, func(i, j int) bool { return ratings[i].RatedAt.Before(ratings[j].RatedAt) })
+	return ratings, nil
+}
+
+// satisfactionMatches checks the ratings of a rated channel one by one, the
+// channel may have older or newer ones out of the query
+func satisfactionMatches(rating domain.SatisfactionRating, query domain.SatisfactionQuery) bool {
+	if !query.From.IsZero() && rating.RatedAt.Before(query.From) {
+		return false
+	}
+	if !query.To.IsZero() && !rating.RatedAt.Before(query.To) {
+		return false
+	}
+	return query.RepEmail == "" || domain.NormalizeEmail(rating.RepEmail) == domain.NormalizeEmail(query.RepEmail)
+}
+
+func (a ChannelNoSQL) UpdateChannelDetails(ctx context.Context, channel domain.Channel) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"_id": channel.Id()})
+		update = bson.M{"$set": bson.M{
+			"tags":       append(make([]string, 0), channel.Tags()...),
+			"priority":   channel.PriorityRank(),
+			"attributes": attributesToBSON(channel),
+			"updatedAt":  time.Now(),
+		}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		switch err {
+		case mongo.ErrNilDocument:
+			return errors.Wrap(domain.ErrUserNotFound, "error updating details")
+		default:
+			return errors.Wrap(err, "error updating details")
+		}
+	}
+	return nil
+}
+
+func messageToBSON(message domain.Message) Messages {
+	messageBSON := Messages{
+		Id:          message.Id,
+		MessageFrom: message.MessageFrom,
+		Message:     message.Message,
+		Timestamp:   message.Timestamp,
+		IsDeleted:   message.IsDeleted,
+		Internal:    message.Internal,
+		EditedAt:    message.EditedAt,
+		DeletedAt:   message.DeletedAt,
+	}
+	for _, edit := range message.Edits {
+		messageBSON.Edits = append(messageBSON.Edits, MessageEdit{Message: edit.Message, EditedAt: edit.EditedAt})
+	}
+	for _, attachment := range message.Attachments {
+		messageBSON.Attachments = append(messageBSON.Attachments, MessageAttachment{
+			Id:          attachment.Id,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		})
+	}
+	return messageBSON
+}
+
+func messageFromBSON(messageBSON Messages) domain.Message {
+	message := domain.Message{
+		Id:          messageBSON.Id,
+		MessageFrom: messageBSON.MessageFrom,
+		Message:     messageBSON.Message,
+		Timestamp:   messageBSON.Timestamp,
+		IsDeleted:   messageBSON.IsDeleted,
+		Internal:    messageBSON.Internal,
+		EditedAt:    messageBSON.EditedAt,
+		DeletedAt:   messageBSON.DeletedAt,
+	}
+	for _, edit := range messageBSON.Edits {
+		message.Edits = append(message.Edits, domain.MessageEdit{Message: edit.Message, EditedAt: edit.EditedAt})
+	}
+	for _, attachment := range messageBSON.Attachments {
+		message.Attachments = append(message.Attachments, domain.MessageAttachment{
+			Id:          attachment.Id,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		})
+	}
+	return message
+}
+
+func (a ChannelNoSQL) MergeGuestChannel(ctx context.Context, email, id string) error {
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.Wrap(err, "error converting id")
+	}
+
+	var (
+		query  = tenantQuery(ctx, bson.M{"_id": idHex, "userEmail": domain.NormalizeEmail(email), "guest": true})
+		update = bson.M{"$set": bson.M{"guest": false, "updatedAt": time.Now()}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error merging guest channel")
+	}
+	return nil
+}
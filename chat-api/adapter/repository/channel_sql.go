@@ -0,0 +1,947 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const channelColumns = `id, user_email, rep_email, user_full_name, guest, current_status, priority, created_at, updated_at, last_message_at, tenant_id, first_response_at, resolved_at, sla_breaches`
+
+// unreadCountColumn counts the messages a reader has not read, as Message.UnreadBy
+// does. It is formatted with the condition on internal notes and takes false
+// twice, the reader twice and the zero time as arguments
+const unreadCountColumn = `(SELECT COUNT(*) FROM channel_messages m WHERE m.channel_id = channels.id AND m.deleted = ? AND %s AND LOWER(TRIM(m.message_from)) <> ? AND m.sent_at > COALESCE((SELECT r.last_read_at FROM channel_read_markers r WHERE r.channel_id = channels.id AND r.participant = ?), ?))`
+
+// channelSortColumns whitelists the columns a listing can be ordered by
+var channelSortColumns = map[string]string{
+	domain.ChannelSortCreatedAt:     "created_at",
+	domain.ChannelSortUpdatedAt:     "updated_at",
+	domain.ChannelSortLastMessageAt: "last_message_at",
+	domain.ChannelSortPriority:      "priority",
+}
+
+type ChannelSQL struct {
+	db SQL
+}
+
+func NewChannelSQL(db SQL) ChannelSQL {
+	return ChannelSQL{db: db}
+}
+
+func (a ChannelSQL) CreateChannel(ctx context.Context, channel domain.Channel) (domain.Channel, error) {
+	tenantId := domain.TenantFromContext(ctx)
+
+	err := a.db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := a.db.Execute(
+			ctx,
+			`INSERT INTO channels (`+channelColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			channel.Id().Hex(),
+			channel.UserEmail(),
+			channel.RepEmail(),
+			channel.UserFullName(),
+			channel.IsGuest(),
+			channel.CurrentStatus(),
+			channel.PriorityRank(),
+			channel.CreatedAt().UTC(),
+			channel.UpdatedAt().UTC(),
+			channel.LastMessageAt().UTC(),
+			tenantId,
+			channel.FirstResponseAt().UTC(),
+			channel.ResolvedAt().UTC(),
+			strings.Join(channel.SLABreaches(), ","),
+		)
+		if err != nil {
+			return err
+		}
+		if err := a.insertStatusHistory(ctx, channel); err != nil {
+			return err
+		}
+		return a.insertDetails(ctx, channel)
+	})
+	if err != nil {
+		return domain.Channel{}, errors.Wrap(err, "error creating channel")
+	}
+
+	channel.AssignTenant(tenantId)
+	return channel, nil
+}
+
+func (a ChannelSQL) GetChannelById(ctx context.Context, id string) (domain.Channel, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return domain.Channel{}, errors.Wrap(err, "error converting id")
+	}
+
+	row := a.db.QueryRow(
+		ctx,
+		`SELECT `+channelColumns+` FROM channels WHERE tenant_id = ? AND id = ?`,
+		domain.TenantFromContext(ctx),
+		id,
+	)
+	channel, err := scanChannel(row)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return domain.Channel{}, domain.ErrUserNotFound
+		default:
+			return domain.Channel{}, errors.Wrap(err, "error fetching user")
+		}
+	}
+
+	if err := a.loadStatusHistory(ctx, &channel); err != nil {
+		return domain.Channel{}, errors.Wrap(err, "error fetching status history")
+	}
+	if err := a.loadMessages(ctx, &channel); err != nil {
+		return domain.Channel{}, errors.Wrap(err, "error fetching messages")
+	}
+	if err := a.loadReadMarkers(ctx, &channel); err != nil {
+		return domain.Channel{}, errors.Wrap(err, "error fetching read markers")
+	}
+	if err := a.loadRatings(ctx, &channel); err != nil {
+		return domain.Channel{}, errors.Wrap(err, "error fetching ratings")
+	}
+	channels := []domain.Channel{channel}
+	if err := a.loadDetails(ctx, channels); err != nil {
+		return domain.Channel{}, errors.Wrap(err, "error fetching details")
+	}
+
+	return channels[0], nil
+}
+
+func (a ChannelSQL) GetChannelsByQueryCount(ctx context.Context, query domain.ChannelQuery) (int64, error) {
+	var (
+		count       int64
+		where, args = channelsWhere(ctx, query)
+	)
+	if err := a.db.QueryRow(ctx, `SELECT COUNT(*) FROM channels WHERE `+where, args...).Scan(&count); err != nil {
+		return 0, errors.Wrap(err, "error counting channels")
+	}
+	return count, nil
+}
+
+func (a ChannelSQL) GetChannelsByQuery(ctx context.Context, query domain.ChannelQuery) ([]domain.Channel, error) {
+	column, direction, err := channelsSortColumn(query)
+	if err != nil {
+		return []domain.Channel{}, errors.Wrap(err, "error listing channels")
+	}
+
+	var (
+		columns = channelColumns
+		args    []interface{}
+	)
+	if query.Reader != "" {
+		// Internal notes only count for readers who can see them
+		internal := "m.internal = ?"
+		if query.Internal {
+			internal = "(m.internal = ? OR m.internal)"
+		}
+		columns += `, ` + fmt.Sprintf(unreadCountColumn, internal)
+		args = append(args, false, false, domain.NormalizeEmail(query.Reader), domain.NormalizeEmail(query.Reader), time.Time{}.UTC())
+	}
+
+	where, whereArgs := channelsWhere(ctx, query)
+	args = append(args, whereArgs...)
+	if query.After != nil {
+		operator := ">"
+		if query.SortDescending {
+			operator = "<"
+		}
+		var value interface{} = query.After.Value.UTC()
+		if query.SortBy == domain.ChannelSortPriority {
+			value = query.After.Rank
+		}
+		where += fmt.Sprintf(" AND (%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, operator)
+		args = append(args, value, value, query.After.Id.Hex())
+	}
+	orderBy := fmt.Sprintf("%[1]s %[2]s, id %[2]s", column, direction)
+
+	statement := `SELECT ` + columns + ` FROM channels WHERE ` + where + ` ORDER BY ` + orderBy
+	if query.Limit > 0 {
+		statement += ` LIMIT ? OFFSET ?`
+		args = append(args, query.Limit, query.Skip)
+	} else if query.Skip > 0 {
+		// SQLite only accepts an offset after a limit
+		statement += ` LIMIT ? OFFSET ?`
+		args = append(args, int64(math.MaxInt64), query.Skip)
+	}
+
+	rows, err := a.db.Query(ctx, statement, args...)
+	if err != nil {
+		return []domain.Channel{}, errors.Wrap(err, "error listing channels")
+	}
+	defer rows.Close()
+
+	var channels = make([]domain.Channel, 0)
+	for rows.Next() {
+		var (
+			unread  int
+			channel domain.Channel
+			err     error
+		)
+		if query.Reader != "" {
+			channel, err = scanChannel(rows, &unread)
+		} else {
+			channel, err = scanChannel(rows)
+		}
+		if err != nil {
+			return []domain.Channel{}, errors.Wrap(err, "error listing channels")
+		}
+		channel.UpdateUnreadCount(unread)
+		channels = append(channels, channel)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.Channel{}, errors.Wrap(err, "error listing channels")
+	}
+	rows.Close()
+
+	if err := a.loadDetails(ctx, channels); err != nil {
+		return []domain.Channel{}, errors.Wrap(err, "error listing channels")
+	}
+
+	return channels, nil
+}
+
+func (a ChannelSQL) UpdateChannelStatus(ctx context.Context, channel domain.Channel) error {
+	err := a.db.WithTransaction(ctx, func(ctx context.Context) error {
+		updated, err := a.db.Execute(
+			ctx,
+			`UPDATE channels SET rep_email = ?, current_status = ?, resolved_at = ?, updated_at = ? WHERE tenant_id = ? AND id = ?`,
+			channel.RepEmail(),
+			channel.CurrentStatus(),
+			channel.ResolvedAt().UTC(),
+			time.Now().UTC(),
+			domain.TenantFromContext(ctx),
+			channel.Id().Hex(),
+		)
+		if err != nil || updated == 0 {
+			return err
+		}
+
+		if _, err := a.db.Execute(ctx, `DELETE FROM channel_status_history WHERE channel_id = ?`, channel.Id().Hex()); err != nil {
+			return err
+		}
+		return a.insertStatusHistory(ctx, channel)
+	})
+	if err != nil {
+		return errors.Wrap(err, "error updating status")
+	}
+	return nil
+}
+
+func (a ChannelSQL) AddMessage(ctx context.Context, channel domain.Channel) error {
+	if err := a.storeMessages(ctx, channel); err != nil {
+		return errors.Wrap(err, "error adding message")
+	}
+	return nil
+}
+
+func (a ChannelSQL) UpdateMessages(ctx context.Context, channel domain.Channel) error {
+	if err := a.storeMessages(ctx, channel); err != nil {
+		return errors.Wrap(err, "error updating messages")
+	}
+	return nil
+}
+
+// storeMessages replaces the messages of the channel with their edits and attachments
+func (a ChannelSQL) storeMessages(ctx context.Context, channel domain.Channel) error {
+	return a.db.WithTransaction(ctx, func(ctx context.Context) error {
+		updated, err := a.db.Execute(
+			ctx,
+			`UPDATE channels SET last_message_at = ?, first_response_at = ?, updated_at = ? WHERE tenant_id = ? AND id = ?`,
+			channel.LastMessageAt().UTC(),
+			channel.FirstResponseAt().UTC(),
+			time.Now().UTC(),
+			domain.TenantFromContext(ctx),
+			channel.Id().Hex(),
+		)
+		if err != nil || updated == 0 {
+			return err
+		}
+
+		for _, table := range []string{"channel_message_attachments", "channel_message_edits", "channel_messages"} {
+			if _, err := a.db.Execute(ctx, `DELETE FROM `+table+` WHERE channel_id = ?`, channel.Id().Hex()); err != nil {
+				return err
+			}
+		}
+		for i, message := range channel.Messages() {
+			var id string
+			if !message.Id.IsZero() {
+				id = message.Id.Hex()
+			}
+			_, err := a.db.Execute(
+				ctx,
+				`INSERT INTO channel_messages (channel_id, position, id, message_from, message, sent_at, deleted, internal, edited_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				channel.Id().Hex(),
+				i,
+				id,
+				message.MessageFrom,
+				message.Message,
+				message.Timestamp.UTC(),
+				message.IsDeleted,
+				message.Internal,
+				message.EditedAt.UTC(),
+				message.DeletedAt.UTC(),
+			)
+			if err != nil {
+				return err
+			}
+
+			for j, edit := range message.Edits {
+				_, err := a.db.Execute(
+					ctx,
+					`INSERT INTO channel_message_edits (channel_id, message_position, position, message, edited_at) VALUES (?, ?, ?, ?, ?)`,
+					channel.Id().Hex(),
+					i,
+					j,
+					edit.Message,
+					edit.EditedAt.UTC(),
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			for j, attachment := range message.Attachments {
+				_, err := a.db.Execute(
+					ctx,
+					`INSERT INTO channel_message_attachments (channel_id, message_position, position, id, file_name, content_type, size) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+					channel.Id().Hex(),
+					i,
+					j,
+					attachment.Id.Hex(),
+					attachment.FileName,
+					attachment.ContentType,
+					attachment.Size,
+				)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateReadMarkers leaves updated_at alone, reading a channel does not change it
+func (a ChannelSQL) UpdateReadMarkers(ctx context.Context, channel domain.Channel) error {
+	err := a.db.WithTransaction(ctx, func(ctx context.Context) error {
+		var exists bool
+		err := a.db.QueryRow(
+			ctx,
+			`SELECT COUNT(*) > 0 FROM channels WHERE tenant_id = ? AND id = ?`,
+			domain.TenantFromContext(ctx),
+			channel.Id().Hex(),
+		).Scan(&exists)
+		if err != nil || !exists {
+			return err
+		}
+
+		if _, err := a.db.Execute(ctx, `DELETE FROM channel_read_markers WHERE channel_id = ?`, channel.Id().Hex()); err != nil {
+			return err
+		}
+		for _, marker := range channel.ReadMarkers() {
+			_, err := a.db.Execute(
+				ctx,
+				`INSERT INTO channel_read_markers (channel_id, participant, last_read_at) VALUES (?, ?, ?)`,
+				channel.Id().Hex(),
+				marker.Participant,
+				marker.LastReadAt.UTC(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "error updating read markers")
+	}
+	return nil
+}
+
+// AddSLABreaches leaves updated_at alone, the SLA is measured on the channel and not
+// a change to it. Each breach is appended in the statement that checks it is not
+// stored yet, the channel may be a stale read
+func (a ChannelSQL) AddSLABreaches(ctx context.Context, channel domain.Channel, breaches []string) error {
+	err := a.db.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, breach := range breaches {
+			_, err := a.db.Execute(
+				ctx,
+				`UPDATE channels SET sla_breaches = CASE WHEN sla_breaches = '' THEN ? ELSE sla_breaches || ',' || ? END
+				WHERE tenant_id = ? AND id = ? AND ',' || sla_breaches || ',' NOT LIKE ?`,
+				breach,
+				breach,
+				domain.TenantFromContext(ctx),
+				channel.Id().Hex(),
+				"%,"+breach+",%",
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "error adding SLA breaches")
+	}
+	return nil
+}
+
+// UpdateSLAClocks leaves updated_at alone, the SLA is measured on the channel and not a change to it
+func (a ChannelSQL) UpdateSLAClocks(ctx context.Context, channel domain.Channel) error {
+	_, err := a.db.Execute(
+		ctx,
+		`UPDATE channels SET first_response_at = ?, resolved_at = ? WHERE tenant_id = ? AND id = ?`,
+		channel.FirstResponseAt().UTC(),
+		channel.ResolvedAt().UTC(),
+		domain.TenantFromContext(ctx),
+		channel.Id().Hex(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error updating SLA clocks")
+	}
+	return nil
+}
+
+// UpdateRatings leaves updated_at alone, a rating is feedback on the channel and not a change to it
+func (a ChannelSQL) UpdateRatings(ctx context.Context, channel domain.Channel) error {
+	err := a.db.WithTransaction(ctx, func(ctx context.Context) error {
+		var exists bool
+		err := a.db.QueryRow(
+			ctx,
+			`SELECT COUNT(*) > 0 FROM channels WHERE tenant_id = ? AND id = ?`,
+			domain.TenantFromContext(ctx),
+			channel.Id().Hex(),
+		).Scan(&exists)
+		if err != nil || !exists {
+			return err
+		}
+
+		if _, err := a.db.Execute(ctx, `DELETE FROM channel_ratings WHERE channel_id = ?`, channel.Id().Hex()); err != nil {
+			return err
+		}
+		for _, rating := range channel.Ratings() {
+			_, err := a.db.Execute(
+				ctx,
+				`INSERT INTO channel_ratings (channel_id, completion, score, comment, rep_email, rated_by, rated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				channel.Id().Hex(),
+				rating.Completion,
+				rating.Score,
+				rating.Comment,
+				rating.RepEmail,
+				rating.RatedBy,
+				rating.RatedAt.UTC(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "error updating ratings")
+	}
+	return nil
+}
+
+// GetSatisfactionRatings reads a row per rating and tag of its channel, the rows of
+// a rating follow each other
+func (a ChannelSQL) GetSatisfactionRatings(ctx context.Context, query domain.SatisfactionQuery) ([]domain.SatisfactionRating, error) {
+	var (
+		where = "c.tenant_id = ?"
+		args  = []interface{}{domain.TenantFromContext(ctx)}
+	)
+	if !query.From.IsZero() {
+		where += " AND r.rated_at >= ?"
+		args = append(args, query.From.UTC())
+	}
+	if !query.To.IsZero() {
+		where += " AND r.rated_at < ?"
+		args = append(args, query.To.UTC())
+	}
+	if query.RepEmail != "" {
+		where += " AND r.rep_email = ?"
+		args = append(args, domain.NormalizeEmail(query.RepEmail))
+	}
+	if tags := domain.NormalizeTags([]string{query.Tag}); len(tags) > 0 {
+		where += " AND EXISTS (SELECT 1 FROM channel_tags f WHERE f.channel_id = r.channel_id AND f.tag = ?)"
+		args = append(args, tags[0])
+	}
+
+	rows, err := a.db.Query(
+		ctx,
+		`SELECT r.channel_id, r.completion, r.score, r.comment, r.rep_email, r.rated_by, r.rated_at, t.tag
+		FROM channel_ratings r
+		JOIN channels c ON c.id = r.channel_id
+		LEFT JOIN channel_tags t ON t.channel_id = r.channel_id
+		WHERE `+where+`
+		ORDER BY r.rated_at, r.channel_id, r.completion, t.position`,
+		args...,
+	)
+	if err != nil {
+		return []domain.SatisfactionRating{}, errors.Wrap(err, "error listing ratings")
+	}
+	defer rows.Close()
+
+	var ratings = make([]domain.SatisfactionRating, 0)
+	for rows.Next() {
+		var (
+			id     string
+			tag    sql.NullString
+			rating domain.SatisfactionRating
+		)
+		if err := rows.Scan(&id, &rating.Completion, &rating.Score, &rating.Comment, &rating.RepEmail, &rating.RatedBy, &rating.RatedAt, &tag); err != nil {
+			return []domain.SatisfactionRating{}, errors.Wrap(err, "error listing ratings")
+		}
+		if rating.ChannelId, err = primitive.ObjectIDFromHex(id); err != nil {
+			return []domain.SatisfactionRating{}, errors.Wrap(err, "error listing ratings")
+		}
+
+		if last := len(ratings) - 1; last >= 0 && ratings[last].ChannelId == rating.ChannelId && ratings[last].Completion == rating.Completion {
+			ratings[last].Tags = append(ratings[last].Tags, tag.String)
+			continue
+		}
+		if tag.Valid {
+			rating.Tags = []string{tag.String}
+		}
+		ratings = append(ratings, rating)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.SatisfactionRating{}, errors.Wrap(err, "error listing ratings")
+	}
+	return ratings, nil
+}
+
+func (a ChannelSQL) UpdateChannelDetails(ctx context.Context, channel domain.Channel) error {
+	err := a.db.WithTransaction(ctx, func(ctx context.Context) error {
+		updated, err := a.db.Execute(
+			ctx,
+			`UPDATE channels SET priority = ?, updated_at = ? WHERE tenant_id = ? AND id = ?`,
+			channel.PriorityRank(),
+			time.Now().UTC(),
+			domain.TenantFromContext(ctx),
+			channel.Id().Hex(),
+		)
+		if err != nil || updated == 0 {
+			return err
+		}
+
+		for _, table := range []string{"channel_tags", "channel_attributes"} {
+			if _, err := a.db.Execute(ctx, `DELETE FROM `+table+` WHERE channel_id = ?`, channel.Id().Hex()); err != nil {
+				return err
+			}
+		}
+		return a.insertDetails(ctx, channel)
+	})
+	if err != nil {
+		return errors.Wrap(err, "error updating details")
+	}
+	return nil
+}
+
+// insertDetails stores the tags and the custom attributes, the priority is a column of the channel
+func (a ChannelSQL) insertDetails(ctx context.Context, channel domain.Channel) error {
+	for i, tag := range channel.Tags() {
+		_, err := a.db.Execute(
+			ctx,
+			`INSERT INTO channel_tags (channel_id, position, tag) VALUES (?, ?, ?)`,
+			channel.Id().Hex(),
+			i,
+			tag,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range channel.AttributeNames() {
+		_, err := a.db.Execute(
+			ctx,
+			`INSERT INTO channel_attributes (channel_id, name, value) VALUES (?, ?, ?)`,
+			channel.Id().Hex(),
+			name,
+			channel.Attributes()[name],
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a ChannelSQL) MergeGuestChannel(ctx context.Context, email, id string) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return errors.Wrap(err, "error converting id")
+	}
+
+	_, err := a.db.Execute(
+		ctx,
+		`UPDATE channels SET guest = ?, updated_at = ? WHERE tenant_id = ? AND id = ? AND user_email = ? AND guest = ?`,
+		false,
+		time.Now().UTC(),
+		domain.TenantFromContext(ctx),
+		id,
+		domain.NormalizeEmail(email),
+		true,
+	)
+	if err != nil {
+		return errors.Wrap(err, "error merging guest channel")
+	}
+	return nil
+}
+
+// loadStatusHistory and loadMessages drain their rows before returning, a
+// connection can only stream one result at a time
+func (a ChannelSQL) loadStatusHistory(ctx context.Context, channel *domain.Channel) error {
+	rows, err := a.db.Query(ctx, `SELECT status, updated_by, changed_at FROM channel_status_history WHERE channel_id = ? ORDER BY position`, channel.Id().Hex())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status domain.StatusHistory
+		if err := rows.Scan(&status.Status, &status.UpdatedBy, &status.Timestamp); err != nil {
+			return err
+		}
+		channel.UpdateStatus(status.Status, status.UpdatedBy, status.Timestamp)
+	}
+	return rows.Err()
+}
+
+func (a ChannelSQL) loadMessages(ctx context.Context, channel *domain.Channel) error {
+	rows, err := a.db.Query(
+		ctx,
+		`SELECT id, message_from, message, sent_at, deleted, internal, edited_at, deleted_at FROM channel_messages WHERE channel_id = ? ORDER BY position`,
+		channel.Id().Hex(),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var messages []domain.Message
+	for rows.Next() {
+		var (
+			id      string
+			message domain.Message
+		)
+		err := rows.Scan(&id, &message.MessageFrom, &message.Message, &message.Timestamp, &message.IsDeleted, &message.Internal, &message.EditedAt, &message.DeletedAt)
+		if err != nil {
+			return err
+		}
+		if id != "" {
+			if message.Id, err = primitive.ObjectIDFromHex(id); err != nil {
+				return err
+			}
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	if err := a.loadMessageEdits(ctx, channel.Id().Hex(), messages); err != nil {
+		return err
+	}
+	if err := a.loadMessageAttachments(ctx, channel.Id().Hex(), messages); err != nil {
+		return err
+	}
+	for _, message := range messages {
+		channel.RestoreMessage(message)
+	}
+	return nil
+}
+
+func (a ChannelSQL) loadMessageEdits(ctx context.Context, channelId string, messages []domain.Message) error {
+	rows, err := a.db.Query(
+		ctx,
+		`SELECT message_position, message, edited_at FROM channel_message_edits WHERE channel_id = ? ORDER BY message_position, position`,
+		channelId,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			position int
+			edit     domain.MessageEdit
+		)
+		if err := rows.Scan(&position, &edit.Message, &edit.EditedAt); err != nil {
+			return err
+		}
+		if position < len(messages) {
+			messages[position].Edits = append(messages[position].Edits, edit)
+		}
+	}
+	return rows.Err()
+}
+
+func (a ChannelSQL) loadMessageAttachments(ctx context.Context, channelId string, messages []domain.Message) error {
+	rows, err := a.db.Query(
+		ctx,
+		`SELECT message_position, id, file_name, content_type, size FROM channel_message_attachments WHERE channel_id = ? ORDER BY message_position, position`,
+		channelId,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			position   int
+			id         string
+			attachment domain.MessageAttachment
+		)
+		if err := rows.Scan(&position, &id, &attachment.FileName, &attachment.ContentType, &attachment.Size); err != nil {
+			return err
+		}
+		if attachment.Id, err = primitive.ObjectIDFromHex(id); err != nil {
+			return err
+		}
+		if position < len(messages) {
+			messages[position].Attachments = append(messages[position].Attachments, attachment)
+		}
+	}
+	return rows.Err()
+}
+
+func (a ChannelSQL) loadReadMarkers(ctx context.Context, channel *domain.Channel) error {
+	rows, err := a.db.Query(ctx, `SELECT participant, last_read_at FROM channel_read_markers WHERE channel_id = ?`, channel.Id().Hex())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var marker domain.ReadMarker
+		if err := rows.Scan(&marker.Participant, &marker.LastReadAt); err != nil {
+			return err
+		}
+		channel.MarkRead(marker.Participant, marker.LastReadAt)
+	}
+	return rows.Err()
+}
+
+// loadDetails reads the tags and the custom attributes of the channels with one query each
+func (a ChannelSQL) loadDetails(ctx context.Context, channels []domain.Channel) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	var (
+		positions    = make(map[string]int, len(channels))
+		ids          = make([]interface{}, 0, len(channels))
+		placeholders = "?" + strings.Repeat(", ?", len(channels)-1)
+	)
+	for i, channel := range channels {
+		positions[channel.Id().Hex()] = i
+		ids = append(ids, channel.Id().Hex())
+	}
+
+	rows, err := a.db.Query(ctx, `SELECT channel_id, tag FROM channel_tags WHERE channel_id IN (`+placeholders+`) ORDER BY channel_id, position`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return err
+		}
+		channels[positions[id]].AddTags(tag)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	attributes := make(map[string]map[string]string, len(channels))
+	rows, err = a.db.Query(ctx, `SELECT channel_id, name, value FROM channel_attributes WHERE channel_id IN (`+placeholders+`)`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, name, value string
+		if err := rows.Scan(&id, &name, &value); err != nil {
+			return err
+		}
+		if attributes[id] == nil {
+			attributes[id] = make(map[string]string)
+		}
+		attributes[id][name] = value
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for id, values := range attributes {
+		if err := channels[positions[id]].UpdateAttributes(values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a ChannelSQL) loadRatings(ctx context.Context, channel *domain.Channel) error {
+	rows, err := a.db.Query(
+		ctx,
+		`SELECT completion, score, comment, rep_email, rated_by, rated_at FROM channel_ratings WHERE channel_id = ? ORDER BY completion`,
+		channel.Id().Hex(),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rating domain.SatisfactionRating
+		if err := rows.Scan(&rating.Completion, &rating.Score, &rating.Comment, &rating.RepEmail, &rating.RatedBy, &rating.RatedAt); err != nil {
+			return err
+		}
+		channel.RestoreRating(rating)
+	}
+	return rows.Err()
+}
+
+func (a ChannelSQL) insertStatusHistory(ctx context.Context, channel domain.Channel) error {
+	for i, status := range channel.StatusHistory() {
+		_, err := a.db.Execute(
+			ctx,
+			`INSERT INTO channel_status_history (channel_id, position, status, updated_by, changed_at) VALUES (?, ?, ?, ?, ?)`,
+			channel.Id().Hex(),
+			i,
+			status.Status,
+			status.UpdatedBy,
+			status.Timestamp,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func channelsWhere(ctx context.Context, query domain.ChannelQuery) (string, []interface{}) {
+	var (
+		where = "tenant_id = ?"
+		args  = []interface{}{domain.TenantFromContext(ctx)}
+	)
+	if query.UserEmail != "" {
+		where += " AND user_email = ?"
+		args = append(args, query.UserEmail)
+	}
+	if query.RepEmail != "" {
+		where += " AND rep_email = ?"
+		args = append(args, query.RepEmail)
+	}
+	if query.Unassigned {
+		where += " AND rep_email = ''"
+	}
+	if len(query.Statuses) > 0 {
+		where += " AND current_status IN (?" + strings.Repeat(", ?", len(query.Statuses)-1) + ")"
+		for _, status := range query.Statuses {
+			args = append(args, status)
+		}
+	}
+	for _, tag := range domain.NormalizeTags(query.Tags) {
+		where += " AND EXISTS (SELECT 1 FROM channel_tags t WHERE t.channel_id = channels.id AND t.tag = ?)"
+		args = append(args, tag)
+	}
+	if len(query.Priorities) > 0 {
+		where += " AND priority IN (?" + strings.Repeat(", ?", len(query.Priorities)-1) + ")"
+		for _, priority := range query.Priorities {
+			args = append(args, domain.PriorityRank(priority))
+		}
+	}
+	for name, value := range query.Attributes {
+		where += " AND EXISTS (SELECT 1 FROM channel_attributes a WHERE a.channel_id = channels.id AND a.name = ? AND a.value = ?)"
+		args = append(args, name, value)
+	}
+	for _, r := range []struct {
+		column   string
+		from, to time.Time
+	}{
+		{column: "created_at", from: query.CreatedFrom, to: query.CreatedTo},
+		{column: "updated_at", from: query.UpdatedFrom, to: query.UpdatedTo},
+	} {
+		if !r.from.IsZero() {
+			where += " AND " + r.column + " >= ?"
+			args = append(args, r.from.UTC())
+		}
+		if !r.to.IsZero() {
+			where += " AND " + r.column + " < ?"
+			args = append(args, r.to.UTC())
+		}
+	}
+	return where, args
+}
+
+func channelsSortColumn(query domain.ChannelQuery) (string, string, error) {
+	var (
+		column    = channelSortColumns[domain.ChannelSortCreatedAt]
+		direction = "ASC"
+	)
+	if query.SortBy != "" {
+		var ok bool
+		if column, ok = channelSortColumns[query.SortBy]; !ok {
+			return "", "", fmt.Errorf("unsupported channel sort %q", query.SortBy)
+		}
+	}
+	if query.SortDescending {
+		direction = "DESC"
+	}
+	return column, direction, nil
+}
+
+// scanChannel reads the channelColumns of a row, extra receives the columns selected after them
+func scanChannel(row Row, extra ...interface{}) (domain.Channel, error) {
+	var (
+		id, userEmail, repEmail, userFullName, currentStatus, tenantId, slaBreaches string
+		guest                                                                       bool
+		priority                                                                    int
+		createdAt, updatedAt, lastMessageAt, firstResponseAt, resolvedAt            time.Time
+	)
+	dest := append([]interface{}{
+		&id, &userEmail, &repEmail, &userFullName, &guest, &currentStatus, &priority, &createdAt, &updatedAt, &lastMessageAt, &tenantId,
+		&firstResponseAt, &resolvedAt, &slaBreaches,
+	}, extra...)
+	err := row.Scan(dest...)
+	if err != nil {
+		return domain.Channel{}, err
+	}
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Channel{}, err
+	}
+
+	channel := domain.NewChannel(objectId, userEmail, currentStatus, createdAt, updatedAt)
+	channel.UpdateRepEmail(repEmail)
+	channel.UpdateUserFullName(userFullName)
+	channel.UpdateLastMessageAt(lastMessageAt)
+	var breaches []string
+	if slaBreaches != "" {
+		breaches = strings.Split(slaBreaches, ",")
+	}
+	channel.RestoreSLA(firstResponseAt, resolvedAt, breaches)
+	channel.AssignTenant(tenantId)
+	_ = channel.UpdatePriority(domain.PriorityFromRank(priority))
+	if guest {
+		channel.MarkGuest()
+	}
+	return channel, nil
+}
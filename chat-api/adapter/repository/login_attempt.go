@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type loginAttemptBSON struct {
+	Key           string    `bson:"key"`
+	Failures      int       `bson:"failures"`
+	LastFailureAt time.Time `bson:"lastFailureAt"`
+	LockedUntil   time.Time `bson:"lockedUntil"`
+}
+
+func (a loginAttemptBSON) toDomain() domain.LoginAttempt {
+	return domain.NewLoginAttempt(a.Key, a.Failures, a.LastFailureAt, a.LockedUntil)
+}
+
+type LoginAttemptNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewLoginAttemptNoSQL(db NoSQL) LoginAttemptNoSQL {
+	result := LoginAttemptNoSQL{
+		db:             db,
+		collectionName: "login_attempts",
+	}
+
+	err := db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: tenantField, Value: 1}, {Key: "key", Value: 1}},
+		true,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+	return result
+}
+
+func (a LoginAttemptNoSQL) GetLoginAttempt(ctx context.Context, key string) (domain.LoginAttempt, error) {
+	var (
+		attemptBSON = &loginAttemptBSON{}
+		query       = tenantQuery(ctx, bson.M{"key": key})
+	)
+
+	if err := a.db.FindOne(ctx, a.collectionName, query, nil, attemptBSON); err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return domain.NewLoginAttempt(key, 0, time.Time{}, time.Time{}), nil
+		default:
+			return domain.LoginAttempt{}, errors.Wrap(err, "error fetching login attempt")
+		}
+	}
+
+	return attemptBSON.toDomain(), nil
+}
+
+func (a LoginAttemptNoSQL) AddLoginFailure(ctx context.Context, key string, now, since time.Time) (domain.LoginAttempt, error) {
+	// Stale failures are cleared first. The failure counted next moves the last
+	// failure to now, so a concurrent attempt can not clear it again
+	var stale = tenantQuery(ctx, bson.M{
+		"key": key,
+		"$or": bson.A{
+			bson.M{"lastFailureAt": bson.M{"$lt": since}},
+			bson.M{"lockedUntil": bson.M{"$gt": time.Time{}, "$lte": now}},
+		},
+	})
+	reset := bson.M{"$set": bson.M{"failures": 0, "lastFailureAt": now, "lockedUntil": time.Time{}}}
+	if err := a.db.Update(ctx, a.collectionName, stale, reset); err != nil {
+		return domain.LoginAttempt{}, errors.Wrap(err, "error saving login attempt")
+	}
+
+	var (
+		attemptBSON = &loginAttemptBSON{}
+		query       = tenantQuery(ctx, bson.M{"key": key})
+		update      = bson.M{
+			"$inc":         bson.M{"failures": 1},
+			"$set":         bson.M{"lastFailureAt": now},
+			"$setOnInsert": bson.M{"lockedUntil": time.Time{}},
+		}
+	)
+
+	if err := a.db.FindOneAndUpdate(ctx, a.collectionName, query, update, true, attemptBSON); err != nil {
+		return domain.LoginAttempt{}, errors.Wrap(err, "error saving login attempt")
+	}
+	return attemptBSON.toDomain(), nil
+}
+
+func (a LoginAttemptNoSQL) LockLoginAttempt(ctx context.Context, key string, now, until time.Time) (bool, error) {
+	var (
+		attemptBSON = &loginAttemptBSON{}
+		query       = tenantQuery(ctx, bson.M{"key": key, "lockedUntil": bson.M{"$lte": now}})
+		update      = bson.M{"$set": bson.M{"lockedUntil": until}}
+	)
+
+	if err := a.db.FindOneAndUpdate(ctx, a.collectionName, query, update, false, attemptBSON); err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return false, nil
+		default:
+			return false, errors.Wrap(err, "error locking login attempt")
+		}
+	}
+	return true, nil
+}
+
+func (a LoginAttemptNoSQL) SaveLoginAttempt(ctx context.Context, attempt domain.LoginAttempt) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"key": attempt.Key()})
+		update = bson.M{"$set": bson.M{
+			"failures":      attempt.Failures(),
+			"lastFailureAt": attempt.LastFailureAt(),
+			"lockedUntil":   attempt.LockedUntil(),
+		}}
+	)
+
+	if err := a.db.Upsert(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error saving login attempt")
+	}
+	return nil
+}
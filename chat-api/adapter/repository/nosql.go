@@ -10,9 +10,14 @@ type NoSQL interface {
 	EnsureIndex(context.Context, string, interface{}, bool) error
 	Store(context.Context, string, interface{}) error
 	Update(context.Context, string, interface{}, interface{}) error
+	Upsert(context.Context, string, interface{}, interface{}) error
+	UpdateMany(context.Context, string, interface{}, interface{}) error
 	FindAll(context.Context, string, interface{}, interface{}, *options.FindOptions) error
 	FindOne(context.Context, string, interface{}, interface{}, interface{}) error
 	FindCount(context.Context, string, interface{}) (int64, error)
+	Aggregate(context.Context, string, interface{}, interface{}, *options.AggregateOptions) error
+	FindOneAndDelete(context.Context, string, interface{}, interface{}) error
+	FindOneAndUpdate(context.Context, string, interface{}, interface{}, bool, interface{}) error
 	StartSession() (Session, error)
 }
 
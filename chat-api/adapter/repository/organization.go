@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type businessDayBSON struct {
+	Weekday int    `bson:"weekday"`
+	Open    string `bson:"open"`
+	Close   string `bson:"close"`
+}
+
+// slaPolicyBSON keeps the targets in seconds
+type slaPolicyBSON struct {
+	Priority             string `bson:"priority"`
+	FirstResponseSeconds int64  `bson:"firstResponseSeconds"`
+	ResolutionSeconds    int64  `bson:"resolutionSeconds"`
+}
+
+type organizationSettingsBSON struct {
+	TimeZone        string            `bson:"timeZone"`
+	BusinessDays    []businessDayBSON `bson:"businessDays"`
+	RoutingStrategy string            `bson:"routingStrategy"`
+	SLAPolicies     []slaPolicyBSON   `bson:"slaPolicies,omitempty"`
+}
+
+type organizationBSON struct {
+	ID        string                   `bson:"_id"`
+	Name      string                   `bson:"name"`
+	Domains   []string                 `bson:"domains"`
+	Settings  organizationSettingsBSON `bson:"settings"`
+	CreatedAt time.Time                `bson:"createdAt"`
+	UpdatedAt time.Time                `bson:"updatedAt"`
+}
+
+// OrganizationNoSQL stores the tenants themselves, so unlike the other
+// repositories its queries are not scoped to the tenant of the request
+type OrganizationNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewOrganizationNoSQL(db NoSQL) OrganizationNoSQL {
+	result := OrganizationNoSQL{
+		db:             db,
+		collectionName: "organizations",
+	}
+
+	err := db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: "domains", Value: 1}},
+		true,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+	return result
+}
+
+func (a OrganizationNoSQL) CreateOrganization(ctx context.Context, organization domain.Organization) (domain.Organization, error) {
+	if err := a.db.Store(ctx, a.collectionName, organizationToBSON(organization)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.Organization{}, domain.ErrOrganizationAlreadyExists
+		}
+		return domain.Organization{}, errors.Wrap(err, "error creating organization")
+	}
+	return organization, nil
+}
+
+func (a OrganizationNoSQL) GetOrganizationById(ctx context.Context, id string) (domain.Organization, error) {
+	return a.findOne(ctx, bson.M{"_id": id})
+}
+
+func (a OrganizationNoSQL) GetOrganizationByDomain(ctx context.Context, host string) (domain.Organization, error) {
+	return a.findOne(ctx, bson.M{"domains": domain.NormalizeHost(host)})
+}
+
+func (a OrganizationNoSQL) GetOrganizations(ctx context.Context) ([]domain.Organization, error) {
+	var organizationBSONs = make([]organizationBSON, 0)
+	if err := a.db.FindAll(ctx, a.collectionName, bson.M{}, &organizationBSONs, nil); err != nil {
+		return []domain.Organization{}, errors.Wrap(err, "error listing organizations")
+	}
+
+	var organizations = make([]domain.Organization, 0, len(organizationBSONs))
+	for _, organizationBSON := range organizationBSONs {
+		organizations = append(organizations, organizationFromBSON(organizationBSON))
+	}
+	return organizations, nil
+}
+
+func (a OrganizationNoSQL) UpdateOrganizationSettings(ctx context.Context, organization domain.Organization) error {
+	var (
+		organizationBSON = organizationToBSON(organization)
+		query            = bson.M{"_id": organization.Id()}
+		update           = bson.M{
+			"$set": bson.M{
+				"settings":  organizationBSON.Settings,
+				"updatedAt": organizationBSON.UpdatedAt,
+			},
+			"$setOnInsert": bson.M{
+				"name":      organizationBSON.Name,
+				"domains":   organizationBSON.Domains,
+				"createdAt": organizationBSON.UpdatedAt,
+			},
+		}
+	)
+
+	// The default organization is only stored once its settings are first saved
+	if err := a.db.Upsert(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error updating organization settings")
+	}
+	return nil
+}
+
+func (a OrganizationNoSQL) findOne(ctx context.Context, query bson.M) (domain.Organization, error) {
+	var organizationBSON = &organizationBSON{}
+
+	if err := a.db.FindOne(ctx, a.collectionName, query, nil, organizationBSON); err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return domain.Organization{}, domain.ErrOrganizationNotFound
+		default:
+			return domain.Organization{}, errors.Wrap(err, "error fetching organization")
+		}
+	}
+	return organizationFromBSON(*organizationBSON), nil
+}
+
+func organizationToBSON(organization domain.Organization) organizationBSON {
+	var (
+		settings = organization.Settings()
+		days     = make([]businessDayBSON, 0)
+	)
+	for _, day := range settings.BusinessHours.Days {
+		days = append(days, businessDayBSON{
+			Weekday: int(day.Weekday),
+			Open:    day.Open,
+			Close:   day.Close,
+		})
+	}
+
+	var policies []slaPolicyBSON
+	for _, policy := range settings.SLAPolicies {
+		policies = append(policies, slaPolicyBSON{
+			Priority:             policy.Priority,
+			FirstResponseSeconds: int64(policy.FirstResponse / time.Second),
+			ResolutionSeconds:    int64(policy.Resolution / time.Second),
+		})
+	}
+
+	domains := organization.Domains()
+	if domains == nil {
+		domains = []string{}
+	}
+
+	return organizationBSON{
+		ID:      organization.Id(),
+		Name:    organization.Name(),
+		Domains: domains,
+		Settings: organizationSettingsBSON{
+			TimeZone:        settings.BusinessHours.TimeZone,
+			BusinessDays:    days,
+			RoutingStrategy: settings.RoutingStrategy,
+			SLAPolicies:     policies,
+		},
+		CreatedAt: organization.CreatedAt(),
+		UpdatedAt: organization.UpdatedAt(),
+	}
+}
+
+func organizationFromBSON(organizationBSON organizationBSON) domain.Organization {
+	organization := domain.NewOrganization(
+		organizationBSON.ID,
+		organizationBSON.Name,
+		organizationBSON.Domains,
+		organizationBSON.CreatedAt,
+		organizationBSON.UpdatedAt,
+	)
+
+	var days []domain.BusinessDay
+	for _, day := range organizationBSON.Settings.BusinessDays {
+		days = append(days, domain.BusinessDay{
+			Weekday: time.Weekday(day.Weekday),
+			Open:    day.Open,
+			Close:   day.Close,
+		})
+	}
+
+	var policies []domain.SLAPolicy
+	for _, policy := range organizationBSON.Settings.SLAPolicies {
+		policies = append(policies, domain.SLAPolicy{
+			Priority:      policy.Priority,
+			FirstResponse: time.Duration(policy.FirstResponseSeconds) * time.Second,
+			Resolution:    time.Duration(policy.ResolutionSeconds) * time.Second,
+		})
+	}
+
+	strategy := organizationBSON.Settings.RoutingStrategy
+	if strategy == "" {
+		strategy = domain.RoutingManual
+	}
+
+	organization.UpdateSettings(domain.OrganizationSettings{
+		BusinessHours: domain.BusinessHours{
+			TimeZone: organizationBSON.Settings.TimeZone,
+			Days:     days,
+		},
+		RoutingStrategy: strategy,
+		SLAPolicies:     policies,
+	}, organizationBSON.UpdatedAt)
+
+	return organization
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxSearchSnippets = 3
+	// searchSnippetLength and searchSnippetLead are in characters, the lead
+	// is the context kept before the first matched word
+	searchSnippetLength = 160
+	searchSnippetLead   = 40
+)
+
+// SearchIndexNoSQL searches the channels collection through a MongoDB text
+// index on the customer name and email and the message text
+type SearchIndexNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewSearchIndexNoSQL(db NoSQL) SearchIndexNoSQL {
+	result := SearchIndexNoSQL{
+		db:             db,
+		collectionName: "channels",
+	}
+
+	// A collection holds a single text index, the tenant prefix makes every
+	// search name its tenant which tenantQuery always does
+	err := db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{
+			{Key: tenantField, Value: 1},
+			{Key: "userFullName", Value: "text"},
+			{Key: "userEmail", Value: "text"},
+			{Key: "messages.message", Value: "text"},
+		},
+		false,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+	return result
+}
+
+// IndexChannel has nothing to do, MongoDB maintains the text index as channels are written
+func (a SearchIndexNoSQL) IndexChannel(_ context.Context, _ domain.Channel) {}
+
+func (a SearchIndexNoSQL) SearchChannels(ctx context.Context, query domain.SearchQuery) ([]domain.SearchHit, int64, error) {
+	terms := domain.SearchTerms(query.Text)
+	if len(terms) == 0 {
+		return []domain.SearchHit{}, 0, domain.ErrEmptySearch
+	}
+
+	filter := tenantQuery(ctx, searchChannelsQuery(query, terms))
+	count, err := a.db.FindCount(ctx, a.collectionName, filter)
+	if err != nil {
+		return []domain.SearchHit{}, 0, errors.Wrap(err, "error counting search results")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: domain.ChannelSortLastMessageAt, Value: -1}, {Key: "_id", Value: -1}})
+	findOptions.SetSkip(int64(query.Skip))
+	if query.Limit > 0 {
+		findOptions.SetLimit(int64(query.Limit))
+	}
+
+	var channelBSONs = make([]channelBSON, 0)
+	if err := a.db.FindAll(ctx, a.collectionName, filter, &channelBSONs, findOptions); err != nil {
+		return []domain.SearchHit{}, 0, errors.Wrap(err, "error searching channels")
+	}
+
+	var hits = make([]domain.SearchHit, 0, len(channelBSONs))
+	for _, channelBSON := range channelBSONs {
+		messages := make([]string, 0, len(channelBSON.Messages))
+		for _, message := range channelBSON.Messages {
+			messages = append(messages, message.Message)
+		}
+
+		hits = append(hits, domain.SearchHit{
+			Channel:  channelSummaryFromBSON(channelBSON),
+			Snippets: searchSnippets(channelBSON.UserFullName, channelBSON.UserEmail, messages, terms),
+		})
+	}
+
+	return hits, count, nil
+}
+
+// searchChannelsQuery quotes every term, MongoDB requires all the phrases of a
+// text search where bare words would only need one of them
+func searchChannelsQuery(query domain.SearchQuery, terms []string) bson.M {
+	filter := channelsQuery(domain.ChannelQuery{
+		RepEmail:    query.RepEmail,
+		Statuses:    query.Statuses,
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+	})
+
+	phrases := make([]string, 0, len(terms))
+	for _, term := range terms {
+		phrases = append(phrases, `"`+term+`"`)
+	}
+	filter["$text"] = bson.M{"$search": strings.Join(phrases, " ")}
+	return filter
+}
+
+// searchSnippets cuts a passage around the matched words of each field that
+// has some, the customer name and email come before the messages
+func searchSnippets(userFullName, userEmail string, messages []string, terms []string) []domain.SearchSnippet {
+	type field struct {
+		name string
+		text string
+	}
+
+	fields := []field{
+		{name: domain.SearchFieldUserFullName, text: userFullName},
+		{name: domain.SearchFieldUserEmail, text: userEmail},
+	}
+	for _, message := range messages {
+		fields = append(fields, field{name: domain.SearchFieldMessage, text: message})
+	}
+
+	var snippets = make([]domain.SearchSnippet, 0)
+	for _, f := range fields {
+		if len(snippets) == maxSearchSnippets {
+			break
+		}
+		if snippet, ok := searchSnippet(f.name, f.text, terms); ok {
+			snippets = append(snippets, snippet)
+		}
+	}
+	return snippets
+}
+
+func searchSnippet(field, text string, terms []string) (domain.SearchSnippet, bool) {
+	var (
+		runes      = []rune(text)
+		highlights []domain.SearchHighlight
+	)
+	for _, word := range searchWords(runes) {
+		if matchesSearchTerm(strings.ToLower(string(runes[word.Start:word.End])), terms) {
+			highlights = append(highlights, word)
+		}
+	}
+	if len(highlights) == 0 {
+		return domain.SearchSnippet{}, false
+	}
+
+	start := highlights[0].Start - searchSnippetLead
+	if start < 0 {
+		start = 0
+	}
+	end := start + searchSnippetLength
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	snippet := domain.SearchSnippet{Field: field, Text: string(runes[start:end])}
+	for _, highlight := range highlights {
+		if highlight.End > end {
+			break
+		}
+		snippet.Highlights = append(snippet.Highlights, domain.SearchHighlight{
+			Start: highlight.Start - start,
+			End:   highlight.End - start,
+		})
+	}
+	return snippet, true
+}
+
+// searchWords locates the words of a text the way domain.SearchTerms splits them
+func searchWords(runes []rune) []domain.SearchHighlight {
+	var words []domain.SearchHighlight
+	for i := 0; i < len(runes); {
+		if !isSearchWordRune(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isSearchWordRune(runes[j]) {
+			j++
+		}
+		words = append(words, domain.SearchHighlight{Start: i, End: j})
+		i = j
+	}
+	return words
+}
+
+func matchesSearchTerm(word string, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSearchWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
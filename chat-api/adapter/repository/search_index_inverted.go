@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SearchIndexInverted keeps an inverted index of the channels in process, for
+// deployments whose channels are not in MongoDB. The channels of a tenant are
+// read from the repository on its first search and IndexChannel keeps them
+// current afterwards. The index belongs to one process, every API instance
+// builds its own and it is rebuilt after a restart
+type SearchIndexInverted struct {
+	channels domain.ChannelRepository
+	mu       *sync.Mutex
+	tenants  map[string]*invertedTenant
+}
+
+type invertedTenant struct {
+	loaded    bool
+	documents map[primitive.ObjectID]invertedDocument
+	// postings lists the channels holding each word, words keeps the same
+	// words sorted to find the ones starting with a term
+	postings map[string]map[primitive.ObjectID]bool
+	words    []string
+}
+
+type invertedDocument struct {
+	channel  domain.Channel
+	messages []string
+	words    []string
+}
+
+func NewSearchIndexInverted(channels domain.ChannelRepository) SearchIndexInverted {
+	return SearchIndexInverted{
+		channels: channels,
+		mu:       &sync.Mutex{},
+		tenants:  map[string]*invertedTenant{},
+	}
+}
+
+func (a SearchIndexInverted) IndexChannel(ctx context.Context, channel domain.Channel) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.tenant(domain.TenantFromContext(ctx)).index(channel)
+}
+
+func (a SearchIndexInverted) SearchChannels(ctx context.Context, query domain.SearchQuery) ([]domain.SearchHit, int64, error) {
+	terms := domain.SearchTerms(query.Text)
+	if len(terms) == 0 {
+		return []domain.SearchHit{}, 0, domain.ErrEmptySearch
+	}
+
+	if err := a.load(ctx); err != nil {
+		return []domain.SearchHit{}, 0, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var (
+		tenant    = a.tenant(domain.TenantFromContext(ctx))
+		documents []invertedDocument
+	)
+	for id := range tenant.matching(terms) {
+		document := tenant.documents[id]
+		if searchFilterMatches(query, document.channel) {
+			documents = append(documents, document)
+		}
+	}
+
+	sort.Slice(documents, func(i, j int) bool {
+		x, y := documents[i].channel, documents[j].channel
+		if !x.LastMessageAt().Equal(y.LastMessageAt()) {
+			return x.LastMessageAt().After(y.LastMessageAt())
+		}
+		return x.Id().Hex() > y.Id().Hex()
+	})
+
+	total := int64(len(documents))
+	if query.Skip >= len(documents) {
+		documents = nil
+	} else {
+		documents = documents[query.Skip:]
+	}
+	if query.Limit > 0 && query.Limit < len(documents) {
+		documents = documents[:query.Limit]
+	}
+
+	var hits = make([]domain.SearchHit, 0, len(documents))
+	for _, document := range documents {
+		hits = append(hits, domain.SearchHit{
+			Channel:  document.channel,
+			Snippets: searchSnippets(document.channel.UserFullName(), document.channel.UserEmail(), document.messages, terms),
+		})
+	}
+	return hits, total, nil
+}
+
+// load reads the channels of the tenant the first time it is searched. The
+// repository is read without holding the lock, channels indexed meanwhile are
+// newer than what was read and are kept
+func (a SearchIndexInverted) load(ctx context.Context) error {
+	tenantId := domain.TenantFromContext(ctx)
+
+	a.mu.Lock()
+	loaded := a.tenant(tenantId).loaded
+	a.mu.Unlock()
+	if loaded {
+		return nil
+	}
+
+	summaries, err := a.channels.GetChannelsByQuery(ctx, domain.ChannelQuery{})
+	if err != nil {
+		return errors.Wrap(err, "error loading search index")
+	}
+	// Listings leave the messages out, they are read channel by channel
+	channels := make([]domain.Channel, 0, len(summaries))
+	for _, summary := range summaries {
+		channel, err := a.channels.GetChannelById(ctx, summary.Id().Hex())
+		if err != nil {
+			return errors.Wrap(err, "error loading search index")
+		}
+		channels = append(channels, channel)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	tenant := a.tenant(tenantId)
+	if tenant.loaded {
+		return nil
+	}
+	for _, channel := range channels {
+		if _, indexed := tenant.documents[channel.Id()]; !indexed {
+			tenant.index(channel)
+		}
+	}
+	tenant.loaded = true
+	return nil
+}
+
+func (a SearchIndexInverted) tenant(tenantId string) *invertedTenant {
+	tenant, ok := a.tenants[tenantId]
+	if !ok {
+		tenant = &invertedTenant{
+			documents: map[primitive.ObjectID]invertedDocument{},
+			postings:  map[string]map[primitive.ObjectID]bool{},
+		}
+		a.tenants[tenantId] = tenant
+	}
+	return tenant
+}
+
+// index replaces what is known of a channel
+func (t *invertedTenant) index(channel domain.Channel) {
+	t.remove(channel.Id())
+
+	document := invertedDocument{channel: channelSummary(channel)}
+	text := []string{channel.UserFullName(), channel.UserEmail()}
+	for _, message := range channel.Messages() {
+		document.messages = append(document.messages, message.Message)
+		text = append(text, message.Message)
+	}
+	document.words = domain.SearchTerms(strings.Join(text, " "))
+
+	for _, word := range document.words {
+		channels, ok := t.postings[word]
+		if !ok {
+			channels = map[primitive.ObjectID]bool{}
+			t.postings[word] = channels
+
+			i := sort.SearchStrings(t.words, word)
+			t.words = append(t.words, "")
+			copy(t.words[i+1:], t.words[i:])
+			t.words[i] = word
+		}
+		channels[channel.Id()] = true
+	}
+	t.documents[channel.Id()] = document
+}
+
+func (t *invertedTenant) remove(id primitive.ObjectID) {
+	document, ok := t.documents[id]
+	if !ok {
+		return
+	}
+
+	for _, word := range document.words {
+		channels := t.postings[word]
+		delete(channels, id)
+		if len(channels) > 0 {
+			continue
+		}
+
+		delete(t.postings, word)
+		i := sort.SearchStrings(t.words, word)
+		t.words = append(t.words[:i], t.words[i+1:]...)
+	}
+	delete(t.documents, id)
+}
+
+// matching returns the channels holding a word starting with each of the terms
+func (t *invertedTenant) matching(terms []string) map[primitive.ObjectID]bool {
+	var result map[primitive.ObjectID]bool
+	for _, term := range terms {
+		found := map[primitive.ObjectID]bool{}
+		for i := sort.SearchStrings(t.words, term); i < len(t.words) && strings.HasPrefix(t.words[i], term); i++ {
+			for id := range t.postings[t.words[i]] {
+				if result == nil || result[id] {
+					found[id] = true
+				}
+			}
+		}
+		result = found
+		if len(result) == 0 {
+			break
+		}
+	}
+	return result
+}
+
+func searchFilterMatches(query domain.SearchQuery, channel domain.Channel) bool {
+	if query.RepEmail != "" && channel.RepEmail() != query.RepEmail {
+		return false
+	}
+	if len(query.Statuses) > 0 {
+		found := false
+		for _, status := range query.Statuses {
+			found = found || channel.CurrentStatus() == status
+		}
+		if !found {
+			return false
+		}
+	}
+	if !query.CreatedFrom.IsZero() && channel.CreatedAt().Before(query.CreatedFrom) {
+		return false
+	}
+	if !query.CreatedTo.IsZero() && !channel.CreatedAt().Before(query.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// channelSummary copies a channel without its history and messages
+func channelSummary(channel domain.Channel) domain.Channel {
+	summary := domain.NewChannel(channel.Id(), channel.UserEmail(), channel.CurrentStatus(), channel.CreatedAt(), channel.UpdatedAt())
+	summary.UpdateRepEmail(channel.RepEmail())
+	summary.UpdateUserFullName(channel.UserFullName())
+	summary.UpdateLastMessageAt(channel.LastMessageAt())
+	summary.AssignTenant(channel.TenantId())
+	if channel.IsGuest() {
+		summary.MarkGuest()
+	}
+	return summary
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const securitySettingsKey = "security"
+
+type securitySettingsBSON struct {
+	Key                      string `bson:"key"`
+	RequireTwoFactorForAdmin bool   `bson:"requireTwoFactorForAdmin"`
+}
+
+type SettingsNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewSettingsNoSQL(db NoSQL) SettingsNoSQL {
+	return SettingsNoSQL{
+		db:             db,
+		collectionName: "settings",
+	}
+}
+
+func (a SettingsNoSQL) GetSecuritySettings(ctx context.Context) (domain.SecuritySettings, error) {
+	var (
+		settingsBSON = &securitySettingsBSON{}
+		query        = tenantQuery(ctx, bson.M{"key": securitySettingsKey})
+	)
+
+	if err := a.db.FindOne(ctx, a.collectionName, query, nil, settingsBSON); err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return domain.SecuritySettings{}, nil
+		default:
+			return domain.SecuritySettings{}, errors.Wrap(err, "error fetching security settings")
+		}
+	}
+
+	return domain.SecuritySettings{
+		RequireTwoFactorForAdmin: settingsBSON.RequireTwoFactorForAdmin,
+	}, nil
+}
+
+func (a SettingsNoSQL) SaveSecuritySettings(ctx context.Context, settings domain.SecuritySettings) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"key": securitySettingsKey})
+		update = bson.M{"$set": bson.M{"requireTwoFactorForAdmin": settings.RequireTwoFactorForAdmin}}
+	)
+
+	if err := a.db.Upsert(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error saving security settings")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDuplicateKey is returned by SQL handlers when a write breaks a unique constraint
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// SQL is implemented by the relational databases. Queries use ? placeholders,
+// handlers rewrite them for drivers that expect another style
+type SQL interface {
+	// Execute runs a statement and returns the number of rows it affected
+	Execute(context.Context, string, ...interface{}) (int64, error)
+	Query(context.Context, string, ...interface{}) (Rows, error)
+	QueryRow(context.Context, string, ...interface{}) Row
+	// WithTransaction runs the function in a transaction, calls made with the
+	// context it receives take part in it
+	WithTransaction(context.Context, func(context.Context) error) error
+}
+
+type Rows interface {
+	Scan(...interface{}) error
+	Next() bool
+	Err() error
+	Close() error
+}
+
+type Row interface {
+	Scan(...interface{}) error
+}
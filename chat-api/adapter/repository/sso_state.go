@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ssoStateBSON struct {
+	State        string    `bson:"state"`
+	Nonce        string    `bson:"nonce"`
+	CodeVerifier string    `bson:"codeVerifier"`
+	ExpiresAt    time.Time `bson:"expiresAt"`
+	TenantId     string    `bson:"tenantId"`
+}
+
+type SSOStateNoSQL struct {
+	collectionName string
+	db             NoSQL
+}
+
+func NewSSOStateNoSQL(db NoSQL) SSOStateNoSQL {
+	result := SSOStateNoSQL{
+		db:             db,
+		collectionName: "sso_states",
+	}
+
+	err := db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: "state", Value: 1}},
+		true,
+	)
+	if err != nil {
+		log.Panic(err)
+	}
+	return result
+}
+
+func (a SSOStateNoSQL) CreateSSOState(ctx context.Context, state domain.SSOState) error {
+	var stateBSON = ssoStateBSON{
+		State:        state.State,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+		ExpiresAt:    state.ExpiresAt,
+		TenantId:     domain.TenantFromContext(ctx),
+	}
+
+	if err := a.db.Store(ctx, a.collectionName, stateBSON); err != nil {
+		return errors.Wrap(err, "error creating sso state")
+	}
+	return nil
+}
+
+func (a SSOStateNoSQL) ConsumeSSOState(ctx context.Context, state string) (domain.SSOState, error) {
+	var (
+		stateBSON = &ssoStateBSON{}
+		// A login started on one organization cannot be completed on another
+		query = tenantQuery(ctx, bson.M{"state": state})
+	)
+
+	if err := a.db.FindOneAndDelete(ctx, a.collectionName, query, stateBSON); err != nil {
+		switch err {
+		case mongo.ErrNoDocuments:
+			return domain.SSOState{}, domain.ErrInvalidSSOState
+		default:
+			return domain.SSOState{}, errors.Wrap(err, "error consuming sso state")
+		}
+	}
+
+	return domain.SSOState{
+		State:        stateBSON.State,
+		Nonce:        stateBSON.Nonce,
+		CodeVerifier: stateBSON.CodeVerifier,
+		ExpiresAt:    stateBSON.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const tenantField = "tenantId"
+
+// tenantQuery narrows a query down to the organization of the request, every
+// read and write on tenant owned collections goes through it
+func tenantQuery(ctx context.Context, query bson.M) bson.M {
+	scoped := bson.M{tenantField: domain.TenantFromContext(ctx)}
+	for key, value := range query {
+		if key == tenantField {
+			continue
+		}
+		scoped[key] = value
+	}
+	return scoped
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recordingNoSQL keeps every filter and document the repositories send to the database
+type recordingNoSQL struct {
+	filters   *[]interface{}
+	documents *[]interface{}
+}
+
+func newRecordingNoSQL() recordingNoSQL {
+	return recordingNoSQL{filters: &[]interface{}{}, documents: &[]interface{}{}}
+}
+
+func (r recordingNoSQL) EnsureIndex(context.Context, string, interface{}, bool) error { return nil }
+
+func (r recordingNoSQL) Store(_ context.Context, _ string, document interface{}) error {
+	*r.documents = append(*r.documents, document)
+	return nil
+}
+
+func (r recordingNoSQL) Update(_ context.Context, _ string, query, _ interface{}) error {
+	*r.filters = append(*r.filters, query)
+	return nil
+}
+
+func (r recordingNoSQL) Upsert(_ context.Context, _ string, query, _ interface{}) error {
+	*r.filters = append(*r.filters, query)
+	return nil
+}
+
+func (r recordingNoSQL) UpdateMany(_ context.Context, _ string, query, _ interface{}) error {
+	*r.filters = append(*r.filters, query)
+	return nil
+}
+
+func (r recordingNoSQL) FindAll(_ context.Context, _ string, query, _ interface{}, _ *options.FindOptions) error {
+	*r.filters = append(*r.filters, query)
+	return nil
+}
+
+func (r recordingNoSQL) FindOne(_ context.Context, _ string, query, _, _ interface{}) error {
+	*r.filters = append(*r.filters, query)
+	return mongo.ErrNoDocuments
+}
+
+func (r recordingNoSQL) FindCount(_ context.Context, _ string, query interface{}) (int64, error) {
+	*r.filters = append(*r.filters, query)
+	return 0, nil
+}
+
+// Aggregate records the first stage, every pipeline starts matching the documents it reads
+func (r recordingNoSQL) Aggregate(_ context.Context, _ string, pipeline, _ interface{}, _ *options.AggregateOptions) error {
+	var match interface{}
+	if stages, ok := pipeline.(mongo.Pipeline); ok && len(stages) > 0 && stages[0][0].Key == "$match" {
+		match = stages[0][0].Value
+	}
+	*r.filters = append(*r.filters, match)
+	return nil
+}
+
+func (r recordingNoSQL) FindOneAndDelete(_ context.Context, _ string, query, _ interface{}) error {
+	*r.filters = append(*r.filters, query)
+	return mongo.ErrNoDocuments
+}
+
+func (r recordingNoSQL) FindOneAndUpdate(_ context.Context, _ string, query, _ interface{}, _ bool, _ interface{}) error {
+	*r.filters = append(*r.filters, query)
+	return mongo.ErrNoDocuments
+}
+
+func (r recordingNoSQL) StartSession() (Session, error) { return nil, nil }
+
+// scopedTo reports whether a filter or stored document only reaches data of the tenant
+func scopedTo(value interface{}, tenant string) bool {
+	raw, err := bson.Marshal(value)
+	if err != nil {
+		return false
+	}
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return false
+	}
+
+	if m[tenantField] == tenant {
+		return true
+	}
+	if and, ok := m["$and"].(bson.A); ok {
+		for _, clause := range and {
+			if scopedTo(clause, tenant) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestRepositories_TenantIsolation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now     = time.Now()
+		user    = domain.NewUser(primitive.NewObjectID(), "first", "last", "user@email.com", "hash", now, now)
+		channel = domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, now, now)
+		key     = domain.NewAPIKey(primitive.NewObjectID(), "socket", "0123456789ab", "hash", []string{domain.ScopeMessagesWrite}, "admin@email.com", now)
+	)
+
+	tests := []struct {
+		name string
+		call func(context.Context, NoSQL)
+	}{
+		{name: "create user", call: func(ctx context.Context, db NoSQL) { _, _ = NewUserNoSQL(db).CreateUser(ctx, user) }},
+		{name: "get user by email", call: func(ctx context.Context, db NoSQL) { _, _ = NewUserNoSQL(db).GetUserByEmail(ctx, "user@email.com") }},
+		{name: "list users", call: func(ctx context.Context, db NoSQL) { _, _ = NewUserNoSQL(db).GetUsers(ctx, domain.ADMIN, 0, 10) }},
+		{name: "count users", call: func(ctx context.Context, db NoSQL) { _, _ = NewUserNoSQL(db).GetUsersCount(ctx, "") }},
+		{name: "update password", call: func(ctx context.Context, db NoSQL) { _ = NewUserNoSQL(db).UpdatePassword(ctx, user) }},
+		{name: "update two factor", call: func(ctx context.Context, db NoSQL) { _ = NewUserNoSQL(db).UpdateTwoFactor(ctx, user) }},
+		{name: "update profile", call: func(ctx context.Context, db NoSQL) { _ = NewUserNoSQL(db).UpdateProfile(ctx, user) }},
+		{name: "update activation", call: func(ctx context.Context, db NoSQL) { _ = NewUserNoSQL(db).UpdateActivation(ctx, user) }},
+		{name: "update role", call: func(ctx context.Context, db NoSQL) { _ = NewUserNoSQL(db).UpdateRole(ctx, user) }},
+
+		{name: "create channel", call: func(ctx context.Context, db NoSQL) { _, _ = NewChannelNoSQL(db).CreateChannel(ctx, channel) }},
+		{name: "get channel by id", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewChannelNoSQL(db).GetChannelById(ctx, channel.Id().Hex())
+		}},
+		{name: "query channels", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewChannelNoSQL(db).GetChannelsByQuery(ctx, domain.ChannelQuery{RepEmail: "rep@email.com", Limit: 10})
+		}},
+		{name: "query channels by status", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewChannelNoSQL(db).GetChannelsByQuery(ctx, domain.ChannelQuery{Statuses: []string{domain.ACTIVE}, Limit: 10})
+		}},
+		{name: "count channels", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewChannelNoSQL(db).GetChannelsByQueryCount(ctx, domain.ChannelQuery{})
+		}},
+		{name: "update channel status", call: func(ctx context.Context, db NoSQL) { _ = NewChannelNoSQL(db).UpdateChannelStatus(ctx, channel) }},
+		{name: "add message", call: func(ctx context.Context, db NoSQL) { _ = NewChannelNoSQL(db).AddMessage(ctx, channel) }},
+		{name: "merge guest channel", call: func(ctx context.Context, db NoSQL) {
+			_ = NewChannelNoSQL(db).MergeGuestChannel(ctx, "user@email.com", channel.Id().Hex())
+		}},
+
+		{name: "create api key", call: func(ctx context.Context, db NoSQL) { _, _ = NewAPIKeyNoSQL(db).CreateAPIKey(ctx, key) }},
+		{name: "list api keys", call: func(ctx context.Context, db NoSQL) { _, _ = NewAPIKeyNoSQL(db).GetAPIKeys(ctx) }},
+		{name: "get api key by id", call: func(ctx context.Context, db NoSQL) { _, _ = NewAPIKeyNoSQL(db).GetAPIKeyById(ctx, key.Id().Hex()) }},
+		{name: "revoke api key", call: func(ctx context.Context, db NoSQL) { _ = NewAPIKeyNoSQL(db).RevokeAPIKey(ctx, key) }},
+
+		{name: "get security settings", call: func(ctx context.Context, db NoSQL) { _, _ = NewSettingsNoSQL(db).GetSecuritySettings(ctx) }},
+		{name: "save security settings", call: func(ctx context.Context, db NoSQL) {
+			_ = NewSettingsNoSQL(db).SaveSecuritySettings(ctx, domain.SecuritySettings{})
+		}},
+		{name: "get login attempt", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewLoginAttemptNoSQL(db).GetLoginAttempt(ctx, "user@email.com")
+		}},
+		{name: "save login attempt", call: func(ctx context.Context, db NoSQL) {
+			_ = NewLoginAttemptNoSQL(db).SaveLoginAttempt(ctx, domain.NewLoginAttempt("user@email.com", 1, now, time.Time{}))
+		}},
+		{name: "add login failure", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewLoginAttemptNoSQL(db).AddLoginFailure(ctx, "user@email.com", now, now.Add(-time.Hour))
+		}},
+		{name: "lock login attempt", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewLoginAttemptNoSQL(db).LockLoginAttempt(ctx, "user@email.com", now, now.Add(time.Hour))
+		}},
+		{name: "create sso state", call: func(ctx context.Context, db NoSQL) {
+			_ = NewSSOStateNoSQL(db).CreateSSOState(ctx, domain.SSOState{State: "state"})
+		}},
+		{name: "consume sso state", call: func(ctx context.Context, db NoSQL) { _, _ = NewSSOStateNoSQL(db).ConsumeSSOState(ctx, "state") }},
+
+		{name: "aggregate channels", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewAnalyticsNoSQL(db).GetChannelBuckets(ctx, domain.AnalyticsQuery{Location: time.UTC, Tag: "billing"})
+		}},
+		{name: "aggregate backlog", call: func(ctx context.Context, db NoSQL) { _, _ = NewAnalyticsNoSQL(db).GetBacklog(ctx, "") }},
+		{name: "aggregate rep throughput", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewAnalyticsNoSQL(db).GetRepBuckets(ctx, domain.AnalyticsQuery{Location: time.UTC})
+		}},
+		{name: "aggregate rep resolutions", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewAnalyticsNoSQL(db).GetRepResolutions(ctx, domain.AnalyticsQuery{Location: time.UTC})
+		}},
+		{name: "aggregate rep workload", call: func(ctx context.Context, db NoSQL) { _, _ = NewAnalyticsNoSQL(db).GetRepWorkload(ctx, "") }},
+
+		{name: "store audit event", call: func(ctx context.Context, db NoSQL) {
+			_ = NewAuditEventNoSQL(db).StoreAuditEvent(ctx, domain.AuditEvent{Action: domain.AuditLogin, Timestamp: now})
+		}},
+		{name: "list audit events", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewAuditEventNoSQL(db).GetAuditEvents(ctx, domain.AuditQuery{Actor: "admin@email.com", From: now}, 0, 10)
+		}},
+		{name: "count audit events", call: func(ctx context.Context, db NoSQL) {
+			_, _ = NewAuditEventNoSQL(db).GetAuditEventsCount(ctx, domain.AuditQuery{Target: "user@email.com"})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				db  = newRecordingNoSQL()
+				ctx = domain.WithTenant(context.Background(), "acme")
+			)
+
+			tt.call(ctx, db)
+
+			if len(*db.filters)+len(*db.documents) == 0 {
+				t.Errorf("[TestCase '%s'] Result: no database access | Expected: at least one", tt.name)
+			}
+			for _, filter := range *db.filters {
+				if !scopedTo(filter, "acme") {
+					t.Errorf("[TestCase '%s'] Result: '%v' | Expected: filter scoped to tenant '%s'", tt.name, filter, "acme")
+				}
+			}
+			for _, document := range *db.documents {
+				if !scopedTo(document, "acme") {
+					t.Errorf("[TestCase '%s'] Result: '%v' | Expected: document stored for tenant '%s'", tt.name, document, "acme")
+				}
+			}
+		})
+	}
+}
+
+func TestTenantFromContext_DefaultsToDefaultTenant(t *testing.T) {
+	t.Parallel()
+
+	db := newRecordingNoSQL()
+	_, _ = NewUserNoSQL(db).GetUserByEmail(context.Background(), "user@email.com")
+
+	for _, filter := range *db.filters {
+		if !scopedTo(filter, domain.DefaultTenantID) {
+			t.Errorf("[TestCase 'no tenant on context'] Result: '%v' | Expected: filter scoped to '%s'", filter, domain.DefaultTenantID)
+		}
+	}
+}
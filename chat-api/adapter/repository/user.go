@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"chat-api/domain"
@@ -9,6 +10,8 @@ import (
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // User schema
@@ -21,6 +24,14 @@ type userBSON struct {
 	Role      string             `bson:"role"`
 	CreatedAt time.Time          `bson:"createdAt,omitempty"`
 	UpdatedAt time.Time          `bson:"updatedAt,omitempty"`
+
+	TwoFactorEnabled bool     `bson:"twoFactorEnabled"`
+	TwoFactorSecret  string   `bson:"twoFactorSecret,omitempty"`
+	RecoveryCodes    []string `bson:"recoveryCodes,omitempty"`
+
+	Deactivated bool `bson:"deactivated"`
+
+	TenantId string `bson:"tenantId"`
 }
 
 type UserNoSQL struct {
@@ -34,17 +45,18 @@ func NewUserNoSQL(db NoSQL) UserNoSQL {
 		collectionName: "users",
 	}
 
-	// for _, key := range []string{"email"} {
-	// 	err := db.EnsureIndex(
-	// 		context.Background(),
-	// 		result.collectionName,
-	// 		bson.D{{Key: key, Value: 1}},
-	// 		false,
-	// 	)
-	// 	if err != nil {
-	// 		log.Panic(err)
-	// 	}
-	// }
+	// Index creation fails while duplicate emails are still stored, report
+	// them with cmd/duplicateemails instead of refusing to serve requests.
+	// Emails are unique per organization, see cmd/assigntenant for upgrades
+	err := db.EnsureIndex(
+		context.Background(),
+		result.collectionName,
+		bson.D{{Key: tenantField, Value: 1}, {Key: "email", Value: 1}},
+		true,
+	)
+	if err != nil {
+		log.Printf("error ensuring unique email index on %s: %v", result.collectionName, err)
+	}
 	return result
 }
 
@@ -57,24 +69,144 @@ func (a UserNoSQL) CreateUser(ctx context.Context, user domain.User) (domain.Use
 		Password:  user.Password(),
 		CreatedAt: user.CreatedAt(),
 		UpdatedAt: user.UpdatedAt(),
+		TenantId:  domain.TenantFromContext(ctx),
 	}
 
 	if err := a.db.Store(ctx, a.collectionName, userBSON); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.User{}, domain.ErrUserAlreadyExists
+		}
 		return domain.User{}, errors.Wrap(err, "error creating user")
 	}
 
+	user.AssignTenant(userBSON.TenantId)
 	return user, nil
 }
 
 func (a UserNoSQL) GetUserByEmail(ctx context.Context, emailAddress string) (domain.User, error) {
 	var (
 		userBSON = &userBSON{}
-		query    = bson.M{"email": emailAddress}
+		query    = tenantQuery(ctx, bson.M{"email": domain.NormalizeEmail(emailAddress)})
 	)
 	if err := a.db.FindOne(ctx, a.collectionName, query, nil, userBSON); err != nil {
-		return domain.User{}, errors.Wrap(err, "error fetching user")
+		switch err {
+		case mongo.ErrNoDocuments:
+			return domain.User{}, domain.ErrUserNotFound
+		default:
+			return domain.User{}, errors.Wrap(err, "error fetching user")
+		}
+	}
+
+	return userFromBSON(*userBSON), nil
+}
+
+// GetUsersByEmails reads every user of a list in one round trip, emails
+// without a user are left out of the result
+func (a UserNoSQL) GetUsersByEmails(ctx context.Context, emailAddresses []string) ([]domain.User, error) {
+	var users = make([]domain.User, 0)
+	if len(emailAddresses) == 0 {
+		return users, nil
+	}
+
+	emails := make([]string, 0, len(emailAddresses))
+	for _, email := range emailAddresses {
+		emails = append(emails, domain.NormalizeEmail(email))
+	}
+
+	var userBSONs = make([]userBSON, 0)
+	if err := a.db.FindAll(ctx, a.collectionName, tenantQuery(ctx, bson.M{"email": bson.M{"$in": emails}}), &userBSONs, options.Find()); err != nil {
+		return []domain.User{}, errors.Wrap(err, "error fetching users")
+	}
+
+	for _, userBSON := range userBSONs {
+		users = append(users, userFromBSON(userBSON))
+	}
+
+	return users, nil
+}
+
+func (a UserNoSQL) GetUsers(ctx context.Context, role string, start, limit int) ([]domain.User, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	findOptions.SetSkip(int64(start))
+	findOptions.SetLimit(int64(limit))
+
+	var userBSONs = make([]userBSON, 0)
+	if err := a.db.FindAll(ctx, a.collectionName, tenantQuery(ctx, usersQuery(role)), &userBSONs, findOptions); err != nil {
+		return []domain.User{}, errors.Wrap(err, "error listing users")
+	}
+
+	var users = make([]domain.User, 0)
+	for _, userBSON := range userBSONs {
+		users = append(users, userFromBSON(userBSON))
+	}
+
+	return users, nil
+}
+
+func (a UserNoSQL) GetUsersCount(ctx context.Context, role string) (int64, error) {
+	count, err := a.db.FindCount(ctx, a.collectionName, tenantQuery(ctx, usersQuery(role)))
+	if err != nil {
+		return 0, errors.Wrap(err, "error counting users")
+	}
+	return count, nil
+}
+
+func (a UserNoSQL) UpdateProfile(ctx context.Context, user domain.User) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"email": user.Email()})
+		update = bson.M{"$set": bson.M{
+			"firstName": user.FirstName(),
+			"lastName":  user.LastName(),
+			"updatedAt": user.UpdatedAt(),
+		}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error updating profile")
+	}
+	return nil
+}
+
+func (a UserNoSQL) UpdateActivation(ctx context.Context, user domain.User) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"email": user.Email()})
+		update = bson.M{"$set": bson.M{
+			"deactivated": !user.IsActive(),
+			"updatedAt":   user.UpdatedAt(),
+		}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error updating activation")
+	}
+	return nil
+}
+
+func (a UserNoSQL) UpdateRole(ctx context.Context, user domain.User) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"email": user.Email()})
+		update = bson.M{"$set": bson.M{
+			"role":      user.Role(),
+			"updatedAt": user.UpdatedAt(),
+		}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error updating role")
 	}
+	return nil
+}
 
+func usersQuery(role string) bson.M {
+	query := bson.M{}
+	if role != "" {
+		query["role"] = role
+	}
+	return query
+}
+
+func userFromBSON(userBSON userBSON) domain.User {
 	user := domain.NewUser(
 		userBSON.ID,
 		userBSON.FirstName,
@@ -85,6 +217,40 @@ func (a UserNoSQL) GetUserByEmail(ctx context.Context, emailAddress string) (dom
 		userBSON.UpdatedAt,
 	)
 	user.UpdateRole(userBSON.Role)
+	user.AssignTenant(userBSON.TenantId)
+	user.UpdateTwoFactor(userBSON.TwoFactorSecret, userBSON.TwoFactorEnabled, userBSON.RecoveryCodes)
+	if userBSON.Deactivated {
+		user.Deactivate(userBSON.UpdatedAt)
+	}
 
-	return user, nil
+	return user
+}
+
+func (a UserNoSQL) UpdatePassword(ctx context.Context, user domain.User) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"email": user.Email()})
+		update = bson.M{"$set": bson.M{"password": user.Password(), "updatedAt": user.UpdatedAt()}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error updating password")
+	}
+	return nil
+}
+
+func (a UserNoSQL) UpdateTwoFactor(ctx context.Context, user domain.User) error {
+	var (
+		query  = tenantQuery(ctx, bson.M{"email": user.Email()})
+		update = bson.M{"$set": bson.M{
+			"twoFactorEnabled": user.TwoFactorEnabled(),
+			"twoFactorSecret":  user.TwoFactorSecret(),
+			"recoveryCodes":    user.RecoveryCodes(),
+			"updatedAt":        time.Now(),
+		}}
+	)
+
+	if err := a.db.Update(ctx, a.collectionName, query, update); err != nil {
+		return errors.Wrap(err, "error updating two factor settings")
+	}
+	return nil
 }
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"chat-api/domain"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const userColumns = `id, first_name, last_name, email, password, role, created_at, updated_at,
+	two_factor_enabled, two_factor_secret, recovery_codes, deactivated, tenant_id`
+
+type UserSQL struct {
+	db SQL
+}
+
+func NewUserSQL(db SQL) UserSQL {
+	return UserSQL{db: db}
+}
+
+func (a UserSQL) CreateUser(ctx context.Context, user domain.User) (domain.User, error) {
+	id := user.Id()
+	if id.IsZero() {
+		id = primitive.NewObjectID()
+	}
+
+	recoveryCodes, err := json.Marshal(user.RecoveryCodes())
+	if err != nil {
+		return domain.User{}, errors.Wrap(err, "error creating user")
+	}
+
+	tenantId := domain.TenantFromContext(ctx)
+	_, err = a.db.Execute(
+		ctx,
+		`INSERT INTO users (`+userColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id.Hex(),
+		user.FirstName(),
+		user.LastName(),
+		user.Email(),
+		user.Password(),
+		user.Role(),
+		user.CreatedAt().UTC(),
+		user.UpdatedAt().UTC(),
+		user.TwoFactorEnabled(),
+		user.TwoFactorSecret(),
+		string(recoveryCodes),
+		!user.IsActive(),
+		tenantId,
+	)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateKey) {
+			return domain.User{}, domain.ErrUserAlreadyExists
+		}
+		return domain.User{}, errors.Wrap(err, "error creating user")
+	}
+
+	user.AssignTenant(tenantId)
+	return user, nil
+}
+
+func (a UserSQL) GetUserByEmail(ctx context.Context, emailAddress string) (domain.User, error) {
+	row := a.db.QueryRow(
+		ctx,
+		`SELECT `+userColumns+` FROM users WHERE tenant_id = ? AND email = ?`,
+		domain.TenantFromContext(ctx),
+		domain.NormalizeEmail(emailAddress),
+	)
+
+	user, err := scanUser(row)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return domain.User{}, domain.ErrUserNotFound
+		default:
+			return domain.User{}, errors.Wrap(err, "error fetching user")
+		}
+	}
+	return user, nil
+}
+
+// GetUsersByEmails reads every user of a list in one round trip, emails
+// without a user are left out of the result
+func (a UserSQL) GetUsersByEmails(ctx context.Context, emailAddresses []string) ([]domain.User, error) {
+	var users = make([]domain.User, 0)
+	if len(emailAddresses) == 0 {
+		return users, nil
+	}
+
+	args := []interface{}{domain.TenantFromContext(ctx)}
+	for _, email := range emailAddresses {
+		args = append(args, domain.NormalizeEmail(email))
+	}
+	rows, err := a.db.Query(
+		ctx,
+		`SELECT `+userColumns+` FROM users WHERE tenant_id = ? AND email IN (?`+strings.Repeat(", ?", len(emailAddresses)-1)+`)`,
+		args...,
+	)
+	if err != nil {
+		return []domain.User{}, errors.Wrap(err, "error fetching users")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return []domain.User{}, errors.Wrap(err, "error fetching users")
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.User{}, errors.Wrap(err, "error fetching users")
+	}
+
+	return users, nil
+}
+
+func (a UserSQL) GetUsers(ctx context.Context, role string, start, limit int) ([]domain.User, error) {
+	where, args := usersWhere(ctx, role)
+	rows, err := a.db.Query(
+		ctx,
+		`SELECT `+userColumns+` FROM users WHERE `+where+` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`,
+		append(args, limit, start)...,
+	)
+	if err != nil {
+		return []domain.User{}, errors.Wrap(err, "error listing users")
+	}
+	defer rows.Close()
+
+	var users = make([]domain.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return []domain.User{}, errors.Wrap(err, "error listing users")
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.User{}, errors.Wrap(err, "error listing users")
+	}
+
+	return users, nil
+}
+
+func (a UserSQL) GetUsersCount(ctx context.Context, role string) (int64, error) {
+	var (
+		count       int64
+		where, args = usersWhere(ctx, role)
+	)
+	if err := a.db.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE `+where, args...).Scan(&count); err != nil {
+		return 0, errors.Wrap(err, "error counting users")
+	}
+	return count, nil
+}
+
+func (a UserSQL) UpdateProfile(ctx context.Context, user domain.User) error {
+	if err := a.update(ctx, user, "first_name = ?, last_name = ?, updated_at = ?",
+		user.FirstName(), user.LastName(), user.UpdatedAt().UTC()); err != nil {
+		return errors.Wrap(err, "error updating profile")
+	}
+	return nil
+}
+
+func (a UserSQL) UpdateActivation(ctx context.Context, user domain.User) error {
+	if err := a.update(ctx, user, "deactivated = ?, updated_at = ?", !user.IsActive(), user.UpdatedAt().UTC()); err != nil {
+		return errors.Wrap(err, "error updating activation")
+	}
+	return nil
+}
+
+func (a UserSQL) UpdateRole(ctx context.Context, user domain.User) error {
+	if err := a.update(ctx, user, "role = ?, updated_at = ?", user.Role(), user.UpdatedAt().UTC()); err != nil {
+		return errors.Wrap(err, "error updating role")
+	}
+	return nil
+}
+
+func (a UserSQL) UpdatePassword(ctx context.Context, user domain.User) error {
+	if err := a.update(ctx, user, "password = ?, updated_at = ?", user.Password(), user.UpdatedAt().UTC()); err != nil {
+		return errors.Wrap(err, "error updating password")
+	}
+	return nil
+}
+
+func (a UserSQL) UpdateTwoFactor(ctx context.Context, user domain.User) error {
+	recoveryCodes, err := json.Marshal(user.RecoveryCodes())
+	if err != nil {
+		return errors.Wrap(err, "error updating two factor settings")
+	}
+
+	if err := a.update(ctx, user, "two_factor_enabled = ?, two_factor_secret = ?, recovery_codes = ?, updated_at = ?",
+		user.TwoFactorEnabled(), user.TwoFactorSecret(), string(recoveryCodes), time.Now().UTC()); err != nil {
+		return errors.Wrap(err, "error updating two factor settings")
+	}
+	return nil
+}
+
+// update sets columns on the user with the same email in the organization of the request
+func (a UserSQL) update(ctx context.Context, user domain.User, set string, args ...interface{}) error {
+	args = append(args, domain.TenantFromContext(ctx), user.Email())
+	_, err := a.db.Execute(ctx, `UPDATE users SET `+set+` WHERE tenant_id = ? AND email = ?`, args...)
+	return err
+}
+
+func usersWhere(ctx context.Context, role string) (string, []interface{}) {
+	var (
+		where = "tenant_id = ?"
+		args  = []interface{}{domain.TenantFromContext(ctx)}
+	)
+	if role != "" {
+		where += " AND role = ?"
+		args = append(args, role)
+	}
+	return where, args
+}
+
+func scanUser(row Row) (domain.User, error) {
+	var (
+		id, firstName, lastName, email, password, role string
+		createdAt, updatedAt                           time.Time
+		twoFactorEnabled, deactivated                  bool
+		twoFactorSecret, recoveryCodesJSON, tenantId   string
+		recoveryCodes                                  []string
+	)
+	err := row.Scan(
+		&id, &firstName, &lastName, &email, &password, &role, &createdAt, &updatedAt,
+		&twoFactorEnabled, &twoFactorSecret, &recoveryCodesJSON, &deactivated, &tenantId,
+	)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err := json.Unmarshal([]byte(recoveryCodesJSON), &recoveryCodes); err != nil {
+		return domain.User{}, err
+	}
+
+	user := domain.NewUser(objectId, firstName, lastName, email, password, createdAt, updatedAt)
+	user.UpdateRole(role)
+	user.AssignTenant(tenantId)
+	user.UpdateTwoFactor(twoFactorSecret, twoFactorEnabled, recoveryCodes)
+	if deactivated {
+		user.Deactivate(updatedAt)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"chat-api/infrastructure/log"
+)
+
+func TestAPIKeyService_GenerateAndVerify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = context.Background()
+		service = NewAPIKeyService(log.LoggerMock{})
+	)
+
+	key, prefix, hashedKey, err := service.GenerateAPIKey(ctx)
+	if err != nil {
+		t.Fatalf("[TestCase 'generate'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	if strings.Contains(hashedKey, key) || hashedKey == key {
+		t.Errorf("[TestCase 'generate'] Hash must not contain the plaintext key")
+	}
+
+	parsed, err := service.ParseAPIKey(ctx, key)
+	if err != nil || parsed != prefix {
+		t.Errorf("[TestCase 'parse'] Result: '%v', '%v' | Expected: '%v'", parsed, err, prefix)
+	}
+
+	if !service.VerifyAPIKey(ctx, key, hashedKey) {
+		t.Errorf("[TestCase 'verify'] Result: '%v' | Expected: '%v'", false, true)
+	}
+
+	if service.VerifyAPIKey(ctx, key+"x", hashedKey) {
+		t.Errorf("[TestCase 'verify tampered'] Result: '%v' | Expected: '%v'", true, false)
+	}
+
+	other, otherPrefix, _, _ := service.GenerateAPIKey(ctx)
+	if other == key || otherPrefix == prefix {
+		t.Errorf("[TestCase 'generate twice'] Keys must be unique")
+	}
+}
+
+func TestAPIKeyService_ParseAPIKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "well formed", key: "csk_0123456789ab_c2VjcmV0"},
+		{name: "secret containing separator", key: "csk_0123456789ab_se_cret"},
+		{name: "wrong label", key: "abc_0123456789ab_c2VjcmV0", wantErr: true},
+		{name: "short prefix", key: "csk_0123_c2VjcmV0", wantErr: true},
+		{name: "missing secret", key: "csk_0123456789ab_", wantErr: true},
+		{name: "bearer token", key: "eyJhbGciOiJIUzI1NiJ9.e30.sig", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAPIKeyService(log.LoggerMock{}).ParseAPIKey(context.Background(), tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected error: '%v'", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/infrastructure/config"
+)
+
+// AttachmentURLSigner signs download links with a key derived from ACCESS_SECRET.
+// The signature covers the tenant, the attachment and the expiry so a link
+// cannot be reused for another file, organization or for longer
+type AttachmentURLSigner struct {
+	key []byte
+	now func() time.Time
+}
+
+func NewAttachmentURLSigner() AttachmentURLSigner {
+	key := sha256.Sum256([]byte("attachment-url:" + config.GetConfig().AccessSecret))
+	return AttachmentURLSigner{key: key[:], now: time.Now}
+}
+
+func (s AttachmentURLSigner) Sign(ctx context.Context, attachmentId string, expiresAt time.Time) string {
+	return base64.RawURLEncoding.EncodeToString(s.sign(ctx, attachmentId, expiresAt))
+}
+
+func (s AttachmentURLSigner) Verify(ctx context.Context, attachmentId string, expiresAt time.Time, signature string) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(decoded, s.sign(ctx, attachmentId, expiresAt)) {
+		return domain.ErrInvalidAttachmentURL
+	}
+	if !s.now().Before(expiresAt) {
+		return domain.ErrInvalidAttachmentURL
+	}
+	return nil
+}
+
+func (s AttachmentURLSigner) sign(ctx context.Context, attachmentId string, expiresAt time.Time) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(domain.TenantFromContext(ctx) + "\n" + attachmentId + "\n" + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+)
+
+func TestAttachmentURLSigner_SignAndVerify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		signer    = NewAttachmentURLSigner()
+		ctx       = domain.WithTenant(context.Background(), "acme")
+		expiresAt = time.Now().Add(domain.AttachmentURLTTL).Truncate(time.Second)
+		signature = signer.Sign(ctx, "a1", expiresAt)
+	)
+
+	if err := signer.Verify(ctx, "a1", expiresAt, signature); err != nil {
+		t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", "valid link", err, nil)
+	}
+
+	var (
+		past    = time.Now().Add(-time.Minute).Truncate(time.Second)
+		expired = signer.Sign(ctx, "a1", past)
+	)
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		id        string
+		expiresAt time.Time
+		signature string
+	}{
+		{name: "other attachment", ctx: ctx, id: "a2", expiresAt: expiresAt, signature: signature},
+		{name: "other organization", ctx: domain.WithTenant(context.Background(), "other"), id: "a1", expiresAt: expiresAt, signature: signature},
+		{name: "extended expiry", ctx: ctx, id: "a1", expiresAt: expiresAt.Add(time.Hour), signature: signature},
+		{name: "expired", ctx: ctx, id: "a1", expiresAt: past, signature: expired},
+		{name: "not base64", ctx: ctx, id: "a1", expiresAt: expiresAt, signature: "!!"},
+		{name: "empty", ctx: ctx, id: "a1", expiresAt: expiresAt, signature: ""},
+	}
+
+	for _, tt := range tests {
+		if err := signer.Verify(tt.ctx, tt.id, tt.expiresAt, tt.signature); err != domain.ErrInvalidAttachmentURL {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, domain.ErrInvalidAttachmentURL)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"chat-api/adapter/logger"
+	"chat-api/domain"
+	"context"
+)
+
+type AuditLogger struct {
+	log  logger.Logger
+	repo domain.AuditEventRepository
+}
+
+func NewAuditLogger(log logger.Logger, repo domain.AuditEventRepository) AuditLogger {
+	return AuditLogger{
+		log:  log,
+		repo: repo,
+	}
+}
+
+// Record logs the event and appends it to the audit trail. Failing to store
+// it must not fail the action being audited, so the error is only logged.
+func (a AuditLogger) Record(ctx context.Context, event domain.AuditEvent) {
+	fields := logger.Fields{
+		"key":       "audit",
+		"tenant":    domain.TenantFromContext(ctx),
+		"action":    event.Action,
+		"actor":     event.Actor,
+		"target":    event.Target,
+		"ip":        event.IP,
+		"outcome":   event.Outcome,
+		"timestamp": event.Timestamp,
+	}
+	a.log.WithFields(fields).Infof("audit event")
+
+	if err := a.repo.StoreAuditEvent(ctx, event); err != nil {
+		a.log.WithFields(fields).WithError(err).Errorf("error storing audit event")
+	}
+}
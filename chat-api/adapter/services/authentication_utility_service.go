@@ -8,51 +8,88 @@ import (
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthenticationUtility struct {
-	log logger.Logger
+	log    logger.Logger
+	hasher PasswordHasher
 }
 
 func NewAuthenticationUtility(log logger.Logger) AuthenticationUtility {
-	return AuthenticationUtility{
-		log: log,
+	hasher, err := NewConfiguredPasswordHasher()
+	if err != nil {
+		log.WithError(err).Warnf("falling back to %s password hashing", HashAlgorithmArgon2id)
+		hasher = argon2idHasher{params: Argon2idParams{Memory: 64 * 1024, Iterations: 1, Parallelism: 4}}
 	}
+
+	return NewAuthenticationUtilityWithHasher(log, hasher)
 }
 
-func (a AuthenticationUtility) HashPassword(ctx context.Context, password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	if err != nil {
-		return "", err
+// NewConfiguredPasswordHasher builds the hasher the settings ask for, the server
+// calls it on startup to refuse settings it can not hash with
+func NewConfiguredPasswordHasher() (PasswordHasher, error) {
+	cfg := config.GetConfig()
+
+	var params Argon2idParams
+	if cfg.PasswordHashAlgorithm == HashAlgorithmArgon2id {
+		var err error
+		if params, err = NewArgon2idParams(cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism); err != nil {
+			return nil, err
+		}
 	}
-	return string(bytes), nil
 
+	return NewPasswordHasher(PasswordHashConfig{
+		Algorithm:  cfg.PasswordHashAlgorithm,
+		Argon2id:   params,
+		BcryptCost: cfg.BcryptCost,
+	})
+}
+
+func NewAuthenticationUtilityWithHasher(log logger.Logger, hasher PasswordHasher) AuthenticationUtility {
+	return AuthenticationUtility{
+		log:    log,
+		hasher: hasher,
+	}
+}
+
+func (a AuthenticationUtility) HashPassword(ctx context.Context, password string) (string, error) {
+	return a.hasher.Hash(password)
 }
 
 func (a AuthenticationUtility) CheckPasswordHash(ctx context.Context, password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	return a.hasher.Verify(password, hash)
+}
 
+// NeedsRehash reports whether a stored hash was produced with another algorithm or parameters than the configured ones
+func (a AuthenticationUtility) NeedsRehash(ctx context.Context, hash string) bool {
+	return a.hasher.NeedsRehash(hash)
 }
 
 func (a AuthenticationUtility) GenerateToken(ctx context.Context, user domain.User) (string, error) {
+	return a.generateToken(user, tokenTenant(ctx, user), "", "", time.Minute*30)
+}
+
+// GenerateScopedToken issues a short lived token that is only accepted by the routes allowing its scope
+func (a AuthenticationUtility) GenerateScopedToken(ctx context.Context, user domain.User, scope string) (string, error) {
+	return a.generateToken(user, tokenTenant(ctx, user), scope, "", time.Minute*10)
+}
+
+// GenerateGuestToken issues a token that only grants access to the given channel
+func (a AuthenticationUtility) GenerateGuestToken(ctx context.Context, user domain.User, channelID string) (string, error) {
+	return a.generateToken(user, tokenTenant(ctx, user), domain.ScopeGuest, channelID, time.Hour*2)
+}
+
+// tokenTenant binds tokens to the organization of the user, guests are not stored so they take the one of the request
+func tokenTenant(ctx context.Context, user domain.User) string {
+	if user.TenantId() != "" {
+		return user.TenantId()
+	}
+	return domain.TenantFromContext(ctx)
+}
+
+func (a AuthenticationUtility) generateToken(user domain.User, tenantID, scope, channelID string, ttl time.Duration) (string, error) {
 	cfg := config.GetConfig()
 
-	// var err error
-	// atClaims := jwt.MapClaims{}
-	// atClaims["authorized"] = true
-	// atClaims["first_name"] = user.FirstName()
-	// atClaims["last_name"] = user.LastName()
-	// atClaims["email"] = user.Email()
-	// atClaims["role"] = user.Role()
-	// atClaims["exp"] = time.Now().Add(time.Minute * 15).Unix()
-	// at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
-	// token, err := at.SignedString([]byte(cfg.AccessSecret))
-	// if err != nil {
-	// 	return "", err
-	// }
-	// return token, nil
 	token := jwt.New(jwt.SigningMethodHS256)
 
 	claims := token.Claims.(jwt.MapClaims)
@@ -62,7 +99,14 @@ func (a AuthenticationUtility) GenerateToken(ctx context.Context, user domain.Us
 	claims["last_name"] = user.LastName()
 	claims["email"] = user.Email()
 	claims["role"] = user.Role()
-	claims["exp"] = time.Now().Add(time.Minute * 30).Unix()
+	claims["tenant_id"] = tenantID
+	claims["exp"] = time.Now().Add(ttl).Unix()
+	if scope != "" {
+		claims["scope"] = scope
+	}
+	if channelID != "" {
+		claims["channel_id"] = channelID
+	}
 
 	tokenString, err := token.SignedString([]byte(cfg.AccessSecret))
 
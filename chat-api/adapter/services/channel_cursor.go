@@ -0,0 +1,95 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"chat-api/adapter/logger"
+	"chat-api/domain"
+	"chat-api/infrastructure/config"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type channelCursorJSON struct {
+	SortBy         string    `json:"s"`
+	SortDescending bool      `json:"d,omitempty"`
+	Backward       bool      `json:"b,omitempty"`
+	Value          time.Time `json:"v"`
+	Rank           int       `json:"r,omitempty"`
+	Id             string    `json:"i"`
+}
+
+// ChannelCursor signs listing cursors with a key derived from ACCESS_SECRET,
+// a token is the base64 JSON position followed by its HMAC
+type ChannelCursor struct {
+	log logger.Logger
+	key []byte
+}
+
+func NewChannelCursor(log logger.Logger) ChannelCursor {
+	key := sha256.Sum256([]byte("channel-cursor:" + config.GetConfig().AccessSecret))
+	return ChannelCursor{log: log, key: key[:]}
+}
+
+func (c ChannelCursor) Encode(cursor domain.ChannelCursor) (string, error) {
+	payload, err := json.Marshal(channelCursorJSON{
+		SortBy:         cursor.SortBy,
+		SortDescending: cursor.SortDescending,
+		Backward:       cursor.Backward,
+		Value:          cursor.Value.UTC(),
+		Rank:           cursor.Rank,
+		Id:             cursor.Id.Hex(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(c.sign(encoded)), nil
+}
+
+func (c ChannelCursor) Decode(token string) (domain.ChannelCursor, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return domain.ChannelCursor{}, domain.ErrInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, c.sign(parts[0])) {
+		return domain.ChannelCursor{}, domain.ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return domain.ChannelCursor{}, domain.ErrInvalidCursor
+	}
+
+	var cursor channelCursorJSON
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return domain.ChannelCursor{}, domain.ErrInvalidCursor
+	}
+	id, err := primitive.ObjectIDFromHex(cursor.Id)
+	if err != nil {
+		return domain.ChannelCursor{}, domain.ErrInvalidCursor
+	}
+
+	return domain.ChannelCursor{
+		SortBy:         cursor.SortBy,
+		SortDescending: cursor.SortDescending,
+		Backward:       cursor.Backward,
+		Value:          cursor.Value,
+		Rank:           cursor.Rank,
+		Id:             id,
+	}, nil
+}
+
+func (c ChannelCursor) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
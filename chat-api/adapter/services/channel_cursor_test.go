@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/infrastructure/log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestChannelCursor_EncodeAndDecode(t *testing.T) {
+	t.Parallel()
+
+	var (
+		codec  = NewChannelCursor(log.LoggerMock{})
+		cursor = domain.ChannelCursor{
+			SortBy:         domain.ChannelSortLastMessageAt,
+			SortDescending: true,
+			Backward:       true,
+			Value:          time.Date(2021, 3, 1, 12, 30, 0, 0, time.UTC),
+			Id:             primitive.NewObjectID(),
+		}
+	)
+
+	token, err := codec.Encode(cursor)
+	if err != nil {
+		t.Fatalf("[TestCase 'encode'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	decoded, err := codec.Decode(token)
+	if err != nil || decoded != cursor {
+		t.Errorf("[TestCase 'round trip'] Result: '%v', '%v' | Expected: '%v'", decoded, err, cursor)
+	}
+
+	ranked := domain.ChannelCursor{SortBy: domain.ChannelSortPriority, Rank: 4, Id: cursor.Id}
+	if token, _ := codec.Encode(ranked); token == "" {
+		t.Errorf("[TestCase 'encode rank'] Result: '%v' | Expected: a token", token)
+	} else if decoded, err := codec.Decode(token); err != nil || decoded.Rank != ranked.Rank {
+		t.Errorf("[TestCase 'round trip rank'] Result: '%v', '%v' | Expected: '%v'", decoded.Rank, err, ranked.Rank)
+	}
+
+	parts := strings.Split(token, ".")
+	other, _ := codec.Encode(domain.ChannelCursor{SortBy: domain.ChannelSortCreatedAt, Id: cursor.Id})
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "no signature", token: parts[0]},
+		{name: "signature of another cursor", token: parts[0] + "." + strings.Split(other, ".")[1]},
+		{name: "signature that is not base64", token: parts[0] + ".!!"},
+		{name: "too many parts", token: token + ".x"},
+	}
+
+	for _, tt := range tests {
+		if _, err := codec.Decode(tt.token); err != domain.ErrInvalidCursor {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, domain.ErrInvalidCursor)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"chat-api/adapter/logger"
+	"chat-api/domain"
+	"chat-api/infrastructure/config"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// ChannelNotifier posts channel events to the socket server, which
+	// broadcasts them to the connected clients
+	ChannelNotifier struct {
+		log    logger.Logger
+		url    string
+		key    string
+		client *http.Client
+	}
+
+	// channelEventJSON matches the messages the socket server sends to its clients,
+	// it only sends them to the clients of TenantId, Internal ones to reps and the
+	// others to reps and UserEmail
+	channelEventJSON struct {
+		Type        string                       `json:"type"`
+		TenantId    string                       `json:"tenantId"`
+		ChannelId   string                       `json:"channelId"`
+		UserEmail   string                       `json:"userEmail"`
+		MessageId   string                       `json:"messageId"`
+		MessageFrom string                       `json:"messageFrom"`
+		Message     string                       `json:"message"`
+		TimeStamp   time.Time                    `json:"timeStamp"`
+		Internal    bool                         `json:"internal,omitempty"`
+		Attachments []channelEventAttachmentJSON `json:"attachments,omitempty"`
+	}
+
+	channelEventAttachmentJSON struct {
+		Id          string `json:"id"`
+		FileName    string `json:"fileName"`
+		ContentType string `json:"contentType"`
+		Size        int64  `json:"size"`
+	}
+)
+
+func NewChannelNotifier(log logger.Logger) ChannelNotifier {
+	cfg := config.GetConfig()
+
+	return NewChannelNotifierWithURL(log, cfg.SocketEventsURL, cfg.SocketEventsKey, &http.Client{Timeout: 5 * time.Second})
+}
+
+// NewChannelNotifierWithURL posts to url with the key in the X-API-Key header,
+// an empty url turns notifications off
+func NewChannelNotifierWithURL(log logger.Logger, url, key string, client *http.Client) ChannelNotifier {
+	return ChannelNotifier{
+		log:    log,
+		url:    url,
+		key:    key,
+		client: client,
+	}
+}
+
+// Notify only logs failures, the change it reports is already stored
+func (n ChannelNotifier) Notify(ctx context.Context, event domain.ChannelEvent) {
+	if n.url == "" {
+		return
+	}
+
+	if err := n.post(ctx, event); err != nil {
+		n.log.WithFields(logger.Fields{
+			"key":     "channel_notifier",
+			"type":    event.Type,
+			"channel": event.ChannelId,
+		}).WithError(err).Warnf("error notifying channel participants")
+	}
+}
+
+func (n ChannelNotifier) post(ctx context.Context, event domain.ChannelEvent) error {
+	eventJSON := channelEventJSON{
+		Type:        event.Type,
+		TenantId:    domain.TenantFromContext(ctx),
+		ChannelId:   event.ChannelId,
+		UserEmail:   event.UserEmail,
+		MessageId:   event.MessageId,
+		MessageFrom: event.MessageFrom,
+		Message:     event.Message,
+		TimeStamp:   event.Timestamp,
+		Internal:    event.Internal,
+	}
+	for _, attachment := range event.Attachments {
+		eventJSON.Attachments = append(eventJSON.Attachments, channelEventAttachmentJSON{
+			Id:          attachment.Id.Hex(),
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		})
+	}
+
+	body, err := json.Marshal(eventJSON)
+	if err != nil {
+		return errors.Wrap(err, "error encoding channel event")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "error creating channel event request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", n.key)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error posting channel event")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("socket server answered %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/infrastructure/log"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestChannelNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		timestamp    = time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+		attachmentId = primitive.NewObjectID()
+		event        = domain.ChannelEvent{
+			Type:        domain.ChannelEventMessageEdited,
+			ChannelId:   "c1",
+			UserEmail:   "user@email.com",
+			MessageId:   "m1",
+			MessageFrom: "user@email.com",
+			Message:     "hello",
+			Timestamp:   timestamp,
+		}
+		note = domain.ChannelEvent{
+			Type:        domain.ChannelEventNote,
+			ChannelId:   "c1",
+			UserEmail:   "user@email.com",
+			MessageId:   "m2",
+			MessageFrom: "rep@email.com",
+			Attachments: []domain.MessageAttachment{{Id: attachmentId, FileName: "refund.pdf", ContentType: "application/pdf", Size: 42}},
+			Internal:    true,
+			Timestamp:   timestamp,
+		}
+		received []map[string]interface{}
+		keys     []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		received = append(received, body)
+		keys = append(keys, r.Header.Get("X-API-Key"))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	NewChannelNotifierWithURL(log.LoggerMock{}, server.URL, "secret", server.Client()).Notify(context.Background(), event)
+	NewChannelNotifierWithURL(log.LoggerMock{}, server.URL, "secret", server.Client()).Notify(domain.WithTenant(context.Background(), "acme"), note)
+	// Without a url nothing is posted
+	NewChannelNotifierWithURL(log.LoggerMock{}, "", "secret", server.Client()).Notify(context.Background(), event)
+
+	expected := []map[string]interface{}{{
+		"type":        "edit",
+		"tenantId":    domain.DefaultTenantID,
+		"channelId":   "c1",
+		"userEmail":   "user@email.com",
+		"messageId":   "m1",
+		"messageFrom": "user@email.com",
+		"message":     "hello",
+		"timeStamp":   "2021-03-01T10:00:00Z",
+	}, {
+		"type":        "note",
+		"tenantId":    "acme",
+		"channelId":   "c1",
+		"userEmail":   "user@email.com",
+		"messageId":   "m2",
+		"messageFrom": "rep@email.com",
+		"message":     "",
+		"timeStamp":   "2021-03-01T10:00:00Z",
+		"internal":    true,
+		"attachments": []interface{}{map[string]interface{}{
+			"id":          attachmentId.Hex(),
+			"fileName":    "refund.pdf",
+			"contentType": "application/pdf",
+			"size":        float64(42),
+		}},
+	}}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", "posted event", received, expected)
+	}
+	if !reflect.DeepEqual(keys, []string{"secret", "secret"}) {
+		t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", "api key", keys, []string{"secret", "secret"})
+	}
+}
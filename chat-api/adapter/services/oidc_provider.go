@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"chat-api/adapter/logger"
+	"chat-api/domain"
+	"chat-api/infrastructure/config"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+const oidcRandomSize = 32
+
+type (
+	OIDCConfig struct {
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		RedirectURL  string
+		Scopes       []string
+		RoleClaim    string
+		// RoleMapping maps values of RoleClaim to our roles
+		RoleMapping map[string]string
+	}
+
+	OIDCProvider struct {
+		log    logger.Logger
+		cfg    OIDCConfig
+		client *http.Client
+		now    func() time.Time
+	}
+
+	oidcDiscovery struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+
+	oidcTokenResponse struct {
+		IDToken     string `json:"id_token"`
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+	}
+
+	jsonWebKeySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	jsonWebKey struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+)
+
+func NewOIDCProvider(log logger.Logger) OIDCProvider {
+	cfg := config.GetConfig()
+
+	return NewOIDCProviderWithConfig(log, OIDCConfig{
+		Issuer:       strings.TrimRight(cfg.OIDCIssuer, "/"),
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCClientSecret,
+		RedirectURL:  cfg.OIDCRedirectURL,
+		Scopes:       strings.Fields(cfg.OIDCScopes),
+		RoleClaim:    cfg.OIDCRoleClaim,
+		RoleMapping:  ParseRoleMapping(cfg.OIDCRoleMapping),
+	}, &http.Client{Timeout: 10 * time.Second})
+}
+
+func NewOIDCProviderWithConfig(log logger.Logger, cfg OIDCConfig, client *http.Client) OIDCProvider {
+	return OIDCProvider{
+		log:    log,
+		cfg:    cfg,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// ParseRoleMapping reads "claimValue=ROLE" pairs separated by commas
+func ParseRoleMapping(raw string) map[string]string {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			continue
+		}
+		mapping[strings.TrimSpace(parts[0])] = strings.ToUpper(strings.TrimSpace(parts[1]))
+	}
+	return mapping
+}
+
+func (p OIDCProvider) AuthorizationRequest(ctx context.Context) (domain.SSOState, string, error) {
+	if p.cfg.Issuer == "" || p.cfg.ClientID == "" {
+		return domain.SSOState{}, "", domain.ErrSSONotConfigured
+	}
+
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return domain.SSOState{}, "", err
+	}
+
+	var values [3]string
+	for i := range values {
+		if values[i], err = randomURLSafe(); err != nil {
+			return domain.SSOState{}, "", err
+		}
+	}
+	state := domain.SSOState{
+		State:        values[0],
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+		ExpiresAt:    p.now().Add(domain.SSOStateTTL),
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.cfg.ClientID)
+	params.Set("redirect_uri", p.cfg.RedirectURL)
+	params.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	params.Set("state", state.State)
+	params.Set("nonce", state.Nonce)
+	params.Set("code_challenge", codeChallenge(state.CodeVerifier))
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return state, discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+func (p OIDCProvider) Exchange(ctx context.Context, code string, state domain.SSOState) (domain.Identity, error) {
+	if p.cfg.Issuer == "" || p.cfg.ClientID == "" {
+		return domain.Identity{}, domain.ErrSSONotConfigured
+	}
+
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("code_verifier", state.CodeVerifier)
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return domain.Identity{}, errors.Wrap(err, "error creating token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tokens oidcTokenResponse
+	if err := p.doJSON(req, &tokens); err != nil {
+		p.log.WithError(err).Warnf("oidc token exchange failed")
+		return domain.Identity{}, domain.ErrSSOLoginFailed
+	}
+
+	claims, err := p.verifyIDToken(ctx, discovery, tokens.IDToken, state.Nonce)
+	if err != nil {
+		p.log.WithError(err).Warnf("oidc id token rejected")
+		return domain.Identity{}, domain.ErrSSOLoginFailed
+	}
+
+	return p.identity(claims), nil
+}
+
+func (p OIDCProvider) discover(ctx context.Context) (oidcDiscovery, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return oidcDiscovery{}, errors.Wrap(err, "error creating discovery request")
+	}
+
+	var discovery oidcDiscovery
+	if err := p.doJSON(req, &discovery); err != nil {
+		return oidcDiscovery{}, errors.Wrap(err, "error fetching oidc discovery document")
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != p.cfg.Issuer {
+		return oidcDiscovery{}, errors.Errorf("discovery issuer %q does not match %q", discovery.Issuer, p.cfg.Issuer)
+	}
+	return discovery, nil
+}
+
+func (p OIDCProvider) verifyIDToken(ctx context.Context, discovery oidcDiscovery, rawToken, nonce string) (jwt.MapClaims, error) {
+	if rawToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating jwks request")
+	}
+	var keys jsonWebKeySet
+	if err := p.doJSON(req, &keys); err != nil {
+		return nil, errors.Wrap(err, "error fetching jwks")
+	}
+
+	token, err := jwt.Parse(rawToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return keys.publicKey(kid)
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.Wrap(err, "invalid id token")
+	}
+
+	claims, _ := token.Claims.(jwt.MapClaims)
+	if iss, _ := claims["iss"].(string); strings.TrimRight(iss, "/") != p.cfg.Issuer {
+		return nil, errors.Errorf("unexpected issuer %q", iss)
+	}
+	if !audienceContains(claims["aud"], p.cfg.ClientID) {
+		return nil, errors.New("id token was not issued for this client")
+	}
+	if claimNonce, _ := claims["nonce"].(string); claimNonce == "" || claimNonce != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("id token has no expiry")
+	}
+
+	return claims, nil
+}
+
+func (p OIDCProvider) identity(claims jwt.MapClaims) domain.Identity {
+	identity := domain.Identity{}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Email, _ = claims["email"].(string)
+	identity.EmailVerified, _ = claims["email_verified"].(bool)
+	identity.FirstName, _ = claims["given_name"].(string)
+	identity.LastName, _ = claims["family_name"].(string)
+
+	if identity.FirstName == "" && identity.LastName == "" {
+		name, _ := claims["name"].(string)
+		parts := strings.SplitN(strings.TrimSpace(name), " ", 2)
+		identity.FirstName = parts[0]
+		if len(parts) == 2 {
+			identity.LastName = parts[1]
+		}
+	}
+
+	for _, value := range claimValues(claims[p.cfg.RoleClaim]) {
+		role := p.cfg.RoleMapping[value]
+		if role == domain.ADMIN {
+			identity.Role = domain.ADMIN
+			break
+		}
+		if role != "" {
+			identity.Role = role
+		}
+	}
+
+	return identity
+}
+
+func (p OIDCProvider) doJSON(req *http.Request, result interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("%s %s returned %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (s jsonWebKeySet) publicKey(kid string) (*rsa.PublicKey, error) {
+	for _, key := range s.Keys {
+		if key.Kty != "RSA" || (kid != "" && key.Kid != kid) {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid jwk modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid jwk exponent")
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	}
+	return nil, errors.Errorf("no signing key found for kid %q", kid)
+}
+
+func audienceContains(aud interface{}, clientID string) bool {
+	for _, value := range claimValues(aud) {
+		if value == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// claimValues reads a claim that providers send either as a single string or as a list
+func claimValues(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomURLSafe() (string, error) {
+	raw := make([]byte, oidcRandomSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.Wrap(err, "error generating random value")
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
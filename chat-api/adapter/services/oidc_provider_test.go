@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/infrastructure/log"
+	"chat-api/infrastructure/oidctest"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const oidcRedirectURL = "http://localhost:3000/sso/callback"
+
+func newTestOIDCProvider(t *testing.T, user oidctest.User) (*oidctest.Server, OIDCProvider) {
+	server, err := oidctest.NewServer("chat-api", user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewOIDCProviderWithConfig(log.LoggerMock{}, OIDCConfig{
+		Issuer:      server.URL,
+		ClientID:    "chat-api",
+		RedirectURL: oidcRedirectURL,
+		Scopes:      []string{"openid", "email", "profile"},
+		RoleClaim:   "groups",
+		RoleMapping: ParseRoleMapping("chat-admins=ADMIN, chat-users=user"),
+	}, server.Client())
+
+	return server, provider
+}
+
+// authorize follows the authorization URL the way a browser would and returns the code sent back
+func authorize(t *testing.T, authorizationURL string) (string, string) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(authorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorization failed with status %d", resp.StatusCode)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestOIDCProvider_Flow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		user          oidctest.User
+		tamper        func(*domain.SSOState)
+		expected      domain.Identity
+		expectedError error
+	}{
+		{
+			name: "admin group mapped to ADMIN role",
+			user: oidctest.User{Subject: "42", Email: "rep@corp.com", EmailVerified: true, GivenName: "Ada", FamilyName: "Rep", Groups: []string{"chat-users", "chat-admins"}},
+			expected: domain.Identity{
+				Subject: "42", Email: "rep@corp.com", EmailVerified: true, FirstName: "Ada", LastName: "Rep", Role: domain.ADMIN,
+			},
+		},
+		{
+			name: "unmapped groups leave the role empty",
+			user: oidctest.User{Subject: "43", Email: "other@corp.com", Groups: []string{"finance"}},
+			expected: domain.Identity{
+				Subject: "43", Email: "other@corp.com",
+			},
+		},
+		{
+			name:          "wrong code verifier rejected by the provider",
+			user:          oidctest.User{Subject: "42", Email: "rep@corp.com", EmailVerified: true},
+			tamper:        func(s *domain.SSOState) { s.CodeVerifier = "not-the-verifier" },
+			expectedError: domain.ErrSSOLoginFailed,
+		},
+		{
+			name:          "nonce mismatch rejected",
+			user:          oidctest.User{Subject: "42", Email: "rep@corp.com", EmailVerified: true},
+			tamper:        func(s *domain.SSOState) { s.Nonce = "replayed" },
+			expectedError: domain.ErrSSOLoginFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, provider := newTestOIDCProvider(t, tt.user)
+			defer server.Close()
+
+			state, authorizationURL, err := provider.AuthorizationRequest(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			code, returnedState := authorize(t, authorizationURL)
+			if returnedState != state.State {
+				t.Fatalf("[TestCase '%s'] State: '%v' | Expected: '%v'", tt.name, returnedState, state.State)
+			}
+
+			if tt.tamper != nil {
+				tt.tamper(&state)
+			}
+
+			got, err := provider.Exchange(context.Background(), code, state)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%+v' | Expected: '%+v'", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOIDCProvider_verifyIDToken(t *testing.T) {
+	t.Parallel()
+
+	server, provider := newTestOIDCProvider(t, oidctest.User{})
+	defer server.Close()
+
+	discovery, err := provider.discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   server.URL,
+			"aud":   []string{"other", "chat-api"},
+			"sub":   "42",
+			"nonce": "nonce",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(jwt.MapClaims)
+		isValid bool
+	}{
+		{name: "valid token with audience list", mutate: func(jwt.MapClaims) {}, isValid: true},
+		{name: "other issuer", mutate: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "other audience", mutate: func(c jwt.MapClaims) { c["aud"] = "other" }},
+		{name: "expired", mutate: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{name: "no expiry", mutate: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "missing nonce", mutate: func(c jwt.MapClaims) { delete(c, "nonce") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid()
+			tt.mutate(claims)
+
+			token, err := server.SignIDToken(claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = provider.verifyIDToken(context.Background(), discovery, token, "nonce")
+			if (err == nil) != tt.isValid {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected valid: '%v'", tt.name, err, tt.isValid)
+			}
+		})
+	}
+}
+
+func TestOIDCProvider_NotConfigured(t *testing.T) {
+	t.Parallel()
+
+	provider := NewOIDCProviderWithConfig(log.LoggerMock{}, OIDCConfig{}, http.DefaultClient)
+	if _, _, err := provider.AuthorizationRequest(context.Background()); err != domain.ErrSSONotConfigured {
+		t.Errorf("Result: '%v' | ExpectedError: '%v'", err, domain.ErrSSONotConfigured)
+	}
+}
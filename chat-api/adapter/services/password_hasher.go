@@ -0,0 +1,204 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	HashAlgorithmArgon2id = "argon2id"
+	HashAlgorithmBcrypt   = "bcrypt"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var (
+	errUnknownHashAlgorithm = errors.New("unknown password hash algorithm")
+	errInvalidHash          = errors.New("invalid password hash")
+	errInvalidArgon2id      = errors.New("argon2id memory, iterations and parallelism must be positive, parallelism at most 255")
+	errInvalidBcryptCost    = errors.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+)
+
+// PasswordHasher hashes passwords into self describing strings that carry
+// their algorithm and parameters, so older hashes can still be verified and
+// detected as outdated after the configuration changes.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, hash string) bool
+	NeedsRehash(hash string) bool
+}
+
+type Argon2idParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// NewArgon2idParams checks the parameters fit argon2id before narrowing them,
+// a zero or a wrapped around value makes the key derivation panic
+func NewArgon2idParams(memory, iterations, parallelism int) (Argon2idParams, error) {
+	if memory <= 0 || int64(memory) > math.MaxUint32 ||
+		iterations <= 0 || int64(iterations) > math.MaxUint32 ||
+		parallelism <= 0 || parallelism > math.MaxUint8 {
+		return Argon2idParams{}, errInvalidArgon2id
+	}
+	return Argon2idParams{Memory: uint32(memory), Iterations: uint32(iterations), Parallelism: uint8(parallelism)}, nil
+}
+
+func (p Argon2idParams) validate() error {
+	if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 {
+		return errInvalidArgon2id
+	}
+	return nil
+}
+
+type PasswordHashConfig struct {
+	Algorithm  string
+	Argon2id   Argon2idParams
+	BcryptCost int
+}
+
+func NewPasswordHasher(cfg PasswordHashConfig) (PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case HashAlgorithmArgon2id:
+		if err := cfg.Argon2id.validate(); err != nil {
+			return nil, err
+		}
+		return argon2idHasher{params: cfg.Argon2id}, nil
+	case HashAlgorithmBcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, errInvalidBcryptCost
+		}
+		return bcryptHasher{cost: cfg.BcryptCost}, nil
+	default:
+		return nil, errUnknownHashAlgorithm
+	}
+}
+
+type argon2idHasher struct {
+	params Argon2idParams
+}
+
+// Hash encodes the result in the PHC string format: $argon2id$v=19$m=65536,t=1,p=4$<salt>$<key>
+func (a argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Wrap(err, "error generating salt")
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.params.Iterations, a.params.Memory, a.params.Parallelism, argon2KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		a.params.Memory,
+		a.params.Iterations,
+		a.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (a argon2idHasher) Verify(password, hash string) bool {
+	return verifyHash(password, hash)
+}
+
+func (a argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, _, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+	return params != a.params
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+func (b bcryptHasher) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+func (b bcryptHasher) Verify(password, hash string) bool {
+	return verifyHash(password, hash)
+}
+
+func (b bcryptHasher) NeedsRehash(hash string) bool {
+	if !isBcryptHash(hash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return true
+	}
+	return cost != b.cost
+}
+
+// verifyHash checks a password against a hash produced by any supported algorithm
+func verifyHash(password, hash string) bool {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		params, salt, key, err := decodeArgon2idHash(hash)
+		if err != nil {
+			return false
+		}
+		other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1
+	case isBcryptHash(hash):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	default:
+		return false
+	}
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func decodeArgon2idHash(hash string) (Argon2idParams, []byte, []byte, error) {
+	var (
+		params  Argon2idParams
+		version int
+	)
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != HashAlgorithmArgon2id {
+		return params, nil, nil, errInvalidHash
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errInvalidHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, errInvalidHash
+	}
+	if err := params.validate(); err != nil {
+		return params, nil, nil, errInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errInvalidHash
+	}
+
+	return params, salt, key, nil
+}
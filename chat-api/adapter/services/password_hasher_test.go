@@ -0,0 +1,143 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordHasher(t *testing.T) {
+	t.Parallel()
+
+	var (
+		argon2Config = PasswordHashConfig{
+			Algorithm: HashAlgorithmArgon2id,
+			Argon2id:  Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1},
+		}
+		bcryptConfig = PasswordHashConfig{Algorithm: HashAlgorithmBcrypt, BcryptCost: 4}
+	)
+
+	tests := []struct {
+		name              string
+		hashWith          PasswordHashConfig
+		verifyWith        PasswordHashConfig
+		expectedPrefix    string
+		expectedRehashing bool
+	}{
+		{
+			name:           "argon2id hash verified by argon2id hasher",
+			hashWith:       argon2Config,
+			verifyWith:     argon2Config,
+			expectedPrefix: "$argon2id$v=19$m=1024,t=1,p=1$",
+		},
+		{
+			name:           "bcrypt hash verified by bcrypt hasher",
+			hashWith:       bcryptConfig,
+			verifyWith:     bcryptConfig,
+			expectedPrefix: "$2a$04$",
+		},
+		{
+			name:              "bcrypt hash verified and flagged by argon2id hasher",
+			hashWith:          bcryptConfig,
+			verifyWith:        argon2Config,
+			expectedPrefix:    "$2a$04$",
+			expectedRehashing: true,
+		},
+		{
+			name:     "argon2id hash with outdated parameters flagged",
+			hashWith: argon2Config,
+			verifyWith: PasswordHashConfig{
+				Algorithm: HashAlgorithmArgon2id,
+				Argon2id:  Argon2idParams{Memory: 2048, Iterations: 2, Parallelism: 1},
+			},
+			expectedPrefix:    "$argon2id$v=19$m=1024,t=1,p=1$",
+			expectedRehashing: true,
+		},
+		{
+			name:              "bcrypt hash with outdated cost flagged",
+			hashWith:          bcryptConfig,
+			verifyWith:        PasswordHashConfig{Algorithm: HashAlgorithmBcrypt, BcryptCost: 5},
+			expectedPrefix:    "$2a$04$",
+			expectedRehashing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher, err := NewPasswordHasher(tt.hashWith)
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] unexpected error: '%v'", tt.name, err)
+			}
+			verifier, err := NewPasswordHasher(tt.verifyWith)
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] unexpected error: '%v'", tt.name, err)
+			}
+
+			hash, err := hasher.Hash("supersecurepassword")
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] unexpected error: '%v'", tt.name, err)
+			}
+
+			if !strings.HasPrefix(hash, tt.expectedPrefix) {
+				t.Errorf("[TestCase '%s'] Hash: '%v' | Expected prefix: '%v'", tt.name, hash, tt.expectedPrefix)
+			}
+
+			if !verifier.Verify("supersecurepassword", hash) {
+				t.Errorf("[TestCase '%s'] correct password was rejected", tt.name)
+			}
+
+			if verifier.Verify("wrongpassword", hash) {
+				t.Errorf("[TestCase '%s'] wrong password was accepted", tt.name)
+			}
+
+			if got := verifier.NeedsRehash(hash); got != tt.expectedRehashing {
+				t.Errorf("[TestCase '%s'] NeedsRehash: '%v' | Expected: '%v'", tt.name, got, tt.expectedRehashing)
+			}
+		})
+	}
+}
+
+func TestPasswordHasher_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name                            string
+		memory, iterations, parallelism int
+	}{
+		{name: "zero memory", memory: 0, iterations: 1, parallelism: 1},
+		{name: "zero iterations", memory: 1024, iterations: 0, parallelism: 1},
+		{name: "zero parallelism", memory: 1024, iterations: 1, parallelism: 0},
+		{name: "parallelism wrapping to zero", memory: 1024, iterations: 1, parallelism: 256},
+		{name: "negative memory", memory: -1, iterations: 1, parallelism: 1},
+	} {
+		if _, err := NewArgon2idParams(tt.memory, tt.iterations, tt.parallelism); err == nil {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, errInvalidArgon2id)
+		}
+	}
+
+	if _, err := NewPasswordHasher(PasswordHashConfig{Algorithm: HashAlgorithmArgon2id}); err != errInvalidArgon2id {
+		t.Errorf("[TestCase 'zero argon2id params'] Result: '%v' | Expected: '%v'", err, errInvalidArgon2id)
+	}
+	if _, err := NewPasswordHasher(PasswordHashConfig{Algorithm: HashAlgorithmBcrypt, BcryptCost: 32}); err != errInvalidBcryptCost {
+		t.Errorf("[TestCase 'bcrypt cost too high'] Result: '%v' | Expected: '%v'", err, errInvalidBcryptCost)
+	}
+
+	verifier, err := NewPasswordHasher(PasswordHashConfig{
+		Algorithm: HashAlgorithmArgon2id,
+		Argon2id:  Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1},
+	})
+	if err != nil {
+		t.Fatalf("[TestCase 'valid params'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	for _, hash := range []string{
+		"$argon2id$v=19$m=1024,t=0,p=1$c29tZXNhbHRzb21lc2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=0$c29tZXNhbHRzb21lc2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=256$c29tZXNhbHRzb21lc2FsdA$a2V5",
+	} {
+		if verifier.Verify("supersecurepassword", hash) {
+			t.Errorf("[TestCase 'hash with invalid params'] Result: '%v' | Expected: '%v'", true, false)
+		}
+		if !verifier.NeedsRehash(hash) {
+			t.Errorf("[TestCase 'hash with invalid params rehashed'] Result: '%v' | Expected: '%v'", false, true)
+		}
+	}
+}
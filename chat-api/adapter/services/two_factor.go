@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"chat-api/adapter/logger"
+	"chat-api/infrastructure/config"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	totpDigits     = 6
+	totpPeriod     = 30
+	totpSkewSteps  = 1
+	totpSecretSize = 20
+
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 5
+)
+
+var (
+	errInvalidCiphertext = errors.New("invalid ciphertext")
+
+	base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+type TwoFactor struct {
+	log    logger.Logger
+	issuer string
+	key    []byte
+	now    func() time.Time
+}
+
+func NewTwoFactor(log logger.Logger) TwoFactor {
+	cfg := config.GetConfig()
+
+	key, err := base64.StdEncoding.DecodeString(cfg.TwoFactorEncryptionKey)
+	if err != nil || len(key) != 32 {
+		if cfg.TwoFactorEncryptionKey != "" {
+			log.Warnf("TWO_FACTOR_ENCRYPTION_KEY must be 32 base64 encoded bytes, deriving key from ACCESS_SECRET")
+		}
+		derived := sha256.Sum256([]byte("two-factor:" + cfg.AccessSecret))
+		key = derived[:]
+	}
+
+	return NewTwoFactorWithKey(log, cfg.TOTPIssuer, key)
+}
+
+func NewTwoFactorWithKey(log logger.Logger, issuer string, key []byte) TwoFactor {
+	return TwoFactor{
+		log:    log,
+		issuer: issuer,
+		key:    key,
+		now:    time.Now,
+	}
+}
+
+func (t TwoFactor) GenerateSecret(_ context.Context) (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, "error generating secret")
+	}
+	return base32NoPadding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI builds the otpauth:// URI authenticator apps read from a QR code
+func (t TwoFactor) ProvisioningURI(_ context.Context, secret, accountName string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", t.issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", totpDigits))
+	params.Set("period", fmt.Sprintf("%d", totpPeriod))
+
+	label := url.PathEscape(t.issuer + ":" + accountName)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// ValidateCode checks a TOTP code (RFC 6238) allowing one step of clock skew
+func (t TwoFactor) ValidateCode(_ context.Context, secret, code string) bool {
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return false
+	}
+
+	step := t.now().Unix() / totpPeriod
+	for skew := -totpSkewSteps; skew <= totpSkewSteps; skew++ {
+		if hmac.Equal([]byte(totpCode(key, step+int64(skew))), []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t TwoFactor) GenerateRecoveryCodes(_ context.Context) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, errors.Wrap(err, "error generating recovery codes")
+		}
+		code := strings.ToLower(base32NoPadding.EncodeToString(raw))
+		codes = append(codes, code[:4]+"-"+code[4:])
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode uses a fast hash, recovery codes are random and long enough not to need a slow one
+func (t TwoFactor) HashRecoveryCode(_ context.Context, code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t TwoFactor) EncryptSecret(_ context.Context, plaintext string) (string, error) {
+	gcm, err := t.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "error generating nonce")
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (t TwoFactor) DecryptSecret(_ context.Context, ciphertext string) (string, error) {
+	gcm, err := t.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errInvalidCiphertext
+	}
+
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errInvalidCiphertext
+	}
+	return string(plaintext), nil
+}
+
+func (t TwoFactor) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(t.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating cipher")
+	}
+	return cipher.NewGCM(block)
+}
+
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-api/infrastructure/log"
+)
+
+func TestTwoFactor_ValidateCode(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors from RFC 6238 appendix B, truncated to six digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		code     string
+		expected bool
+	}{
+		{name: "valid code at 59", now: time.Unix(59, 0), code: "287082", expected: true},
+		{name: "valid code at 1111111109", now: time.Unix(1111111109, 0), code: "081804", expected: true},
+		{name: "previous step accepted for clock skew", now: time.Unix(1111111109+30, 0), code: "081804", expected: true},
+		{name: "code two steps old refused", now: time.Unix(1111111109+60, 0), code: "081804", expected: false},
+		{name: "wrong code refused", now: time.Unix(59, 0), code: "123456", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twoFactor := NewTwoFactorWithKey(log.LoggerMock{}, "Test", make([]byte, 32))
+			twoFactor.now = func() time.Time { return tt.now }
+
+			if got := twoFactor.ValidateCode(context.Background(), secret, tt.code); got != tt.expected {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTwoFactor_EncryptSecret(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx       = context.Background()
+		twoFactor = NewTwoFactorWithKey(log.LoggerMock{}, "Test", []byte("0123456789abcdef0123456789abcdef"))
+		other     = NewTwoFactorWithKey(log.LoggerMock{}, "Test", []byte("fedcba9876543210fedcba9876543210"))
+	)
+
+	secret, err := twoFactor.GenerateSecret(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: '%v'", err)
+	}
+
+	encrypted, err := twoFactor.EncryptSecret(ctx, secret)
+	if err != nil {
+		t.Fatalf("unexpected error: '%v'", err)
+	}
+
+	if strings.Contains(encrypted, secret) {
+		t.Errorf("encrypted secret '%v' contains the plain secret", encrypted)
+	}
+
+	decrypted, err := twoFactor.DecryptSecret(ctx, encrypted)
+	if err != nil || decrypted != secret {
+		t.Errorf("Result: '%v' '%v' | Expected: '%v'", decrypted, err, secret)
+	}
+
+	if _, err := other.DecryptSecret(ctx, encrypted); err == nil {
+		t.Errorf("secret decrypted with the wrong key")
+	}
+
+	uri := twoFactor.ProvisioningURI(ctx, secret, "rep@email.com")
+	if !strings.HasPrefix(uri, "otpauth://totp/Test:rep@email.com?") || !strings.Contains(uri, "secret="+secret) {
+		t.Errorf("unexpected provisioning uri: '%v'", uri)
+	}
+}
@@ -0,0 +1,85 @@
+// Command assigntenant upgrades a database created before organizations
+// existed. Documents without a tenant are handed to the default organization
+// and the indexes that made emails and login attempt keys globally unique are
+// dropped, since those are now only unique within an organization.
+//
+// It is safe to run several times.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/infrastructure/common"
+	"chat-api/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type indexDropper interface {
+	DropIndex(context.Context, string, string) error
+}
+
+var (
+	tenantCollections = []string{"users", "channels", "api_keys", "settings", "login_attempts", "sso_states"}
+
+	// legacyIndexes were created by the repositories before they were scoped by tenant
+	legacyIndexes = map[string][]string{
+		"users":          {"email_1"},
+		"login_attempts": {"key_1"},
+		"channels":       {"userEmail_1", "repEmail_1", "currentStatus_1"},
+	}
+)
+
+func init() {
+	common.LoadEnvVars()
+}
+
+func main() {
+	db, err := database.NewDatabaseNoSQLFactory(database.InstanceMongoDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	for _, collection := range tenantCollections {
+		var (
+			query  = bson.M{"tenantId": bson.M{"$exists": false}}
+			update = bson.M{"$set": bson.M{"tenantId": domain.DefaultTenantID}}
+		)
+
+		count, err := db.FindCount(ctx, collection, query)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := db.UpdateMany(ctx, collection, query, update); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: %d documents assigned to %q\n", collection, count, domain.DefaultTenantID)
+	}
+
+	dropper, ok := db.(indexDropper)
+	if !ok {
+		log.Fatal("database does not support dropping indexes")
+	}
+	for collection, names := range legacyIndexes {
+		for _, name := range names {
+			err := dropper.DropIndex(ctx, collection, name)
+			if err == nil {
+				fmt.Printf("%s: dropped index %s\n", collection, name)
+				continue
+			}
+			// Already dropped by a previous run, or the collection was never created
+			if cmdErr, ok := err.(mongo.CommandError); ok && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound") {
+				continue
+			}
+			log.Fatal(err)
+		}
+	}
+}
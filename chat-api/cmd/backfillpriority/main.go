@@ -0,0 +1,47 @@
+// Command backfillpriority upgrades a database created before channels had a
+// priority. Channels without one are stored as NORMAL, so they match priority
+// filters and sort among the other channels instead of before all of them.
+//
+// It is safe to run several times.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/infrastructure/common"
+	"chat-api/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func init() {
+	common.LoadEnvVars()
+}
+
+func main() {
+	db, err := database.NewDatabaseNoSQLFactory(database.InstanceMongoDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	var (
+		query  = bson.M{"priority": bson.M{"$exists": false}}
+		update = bson.M{"$set": bson.M{"priority": domain.PriorityRank(domain.PriorityNormal)}}
+	)
+
+	count, err := db.FindCount(ctx, "channels", query)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := db.UpdateMany(ctx, "channels", query, update); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("channels: %d documents set to %s\n", count, domain.PriorityNormal)
+}
@@ -0,0 +1,81 @@
+// Command backfillsla upgrades a database created before channels tracked
+// their SLA. The time of the first rep response and of the resolution are
+// derived from the messages and the status history of every channel and
+// stored, so listings and reports see them without reading the history.
+//
+// Channels are read from the SQL database when SQL_DATABASE is set, like the
+// API does. It is safe to run several times.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"chat-api/adapter/repository"
+	"chat-api/domain"
+	"chat-api/infrastructure/common"
+	"chat-api/infrastructure/database"
+)
+
+func init() {
+	common.LoadEnvVars()
+}
+
+func main() {
+	db, err := database.NewDatabaseNoSQLFactory(database.InstanceMongoDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var channels domain.ChannelRepository = repository.NewChannelNoSQL(db)
+	switch common.GetEnv("SQL_DATABASE", "") {
+	case "postgres":
+		channels = repository.NewChannelSQL(sqlDatabase(database.InstancePostgres))
+	case "sqlite":
+		channels = repository.NewChannelSQL(sqlDatabase(database.InstanceSQLite))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	organizations, err := repository.NewOrganizationNoSQL(db).GetOrganizations(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tenants := []string{domain.DefaultTenantID}
+	for _, organization := range organizations {
+		if organization.Id() != domain.DefaultTenantID {
+			tenants = append(tenants, organization.Id())
+		}
+	}
+
+	for _, tenant := range tenants {
+		tenantCtx := domain.WithTenant(ctx, tenant)
+
+		summaries, err := channels.GetChannelsByQuery(tenantCtx, domain.ChannelQuery{SortBy: domain.ChannelSortCreatedAt})
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, summary := range summaries {
+			// Only the full channel carries the history the times are derived from
+			channel, err := channels.GetChannelById(tenantCtx, summary.Id().Hex())
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := channels.UpdateSLAClocks(tenantCtx, channel); err != nil {
+				log.Fatal(err)
+			}
+		}
+		fmt.Printf("%s: %d channels backfilled\n", tenant, len(summaries))
+	}
+}
+
+func sqlDatabase(instance int) repository.SQL {
+	db, err := database.NewDatabaseSQLFactory(instance)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return db
+}
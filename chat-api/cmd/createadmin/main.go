@@ -0,0 +1,85 @@
+// Command createadmin creates the first admin of an organization. The API only
+// lets admins create admins, so the first one has to be created here.
+//
+//	ADMIN_PASSWORD=... createadmin -email admin@example.com -tenant acme
+//
+// The password is read from ADMIN_PASSWORD, not from a flag, so it does not
+// show in the process list. Users are stored in the SQL database when
+// SQL_DATABASE is set, like the API does.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"chat-api/adapter/repository"
+	"chat-api/adapter/services"
+	"chat-api/domain"
+	"chat-api/infrastructure/common"
+	"chat-api/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func init() {
+	common.LoadEnvVars()
+}
+
+func main() {
+	var (
+		email     = flag.String("email", "", "email of the admin")
+		firstName = flag.String("first-name", "Admin", "first name of the admin")
+		lastName  = flag.String("last-name", "Admin", "last name of the admin")
+		tenant    = flag.String("tenant", domain.DefaultTenantID, "organization of the admin")
+		password  = os.Getenv("ADMIN_PASSWORD")
+	)
+	flag.Parse()
+	if *email == "" || password == "" {
+		log.Fatal("an -email and ADMIN_PASSWORD are required")
+	}
+
+	db, err := database.NewDatabaseNoSQLFactory(database.InstanceMongoDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var users domain.UserRepository = repository.NewUserNoSQL(db)
+	switch common.GetEnv("SQL_DATABASE", "") {
+	case "postgres":
+		users = repository.NewUserSQL(sqlDatabase(database.InstancePostgres))
+	case "sqlite":
+		users = repository.NewUserSQL(sqlDatabase(database.InstanceSQLite))
+	}
+
+	hasher, err := services.NewConfiguredPasswordHasher()
+	if err != nil {
+		log.Fatal(err)
+	}
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	admin := domain.NewUser(primitive.NewObjectID(), *firstName, *lastName, *email, hash, time.Now(), time.Now())
+	admin.UpdateRole(domain.ADMIN)
+	created, err := users.CreateUser(domain.WithTenant(ctx, *tenant), admin)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s: admin %s created\n", *tenant, created.Email())
+}
+
+func sqlDatabase(instance int) repository.SQL {
+	db, err := database.NewDatabaseSQLFactory(instance)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return db
+}
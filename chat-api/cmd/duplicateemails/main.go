@@ -0,0 +1,106 @@
+// Command duplicateemails reports user accounts whose emails only differ by
+// case or surrounding whitespace. Those accounts keep the unique email index
+// from being created and have to be merged by hand before it can be enforced.
+//
+// With -normalize, stored emails that are not in canonical form and do not
+// collide with another account are rewritten to lower case.
+//
+// Emails only have to be unique within an organization, accounts of
+// different organizations sharing an email are not reported.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"chat-api/domain"
+	"chat-api/infrastructure/common"
+	"chat-api/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const usersCollection = "users"
+
+type userEmailBSON struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Email     string             `bson:"email"`
+	Role      string             `bson:"role"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	TenantId  string             `bson:"tenantId"`
+}
+
+func init() {
+	common.LoadEnvVars()
+}
+
+func main() {
+	normalize := flag.Bool("normalize", false, "lower case stored emails that do not collide with another account")
+	flag.Parse()
+
+	db, err := database.NewDatabaseNoSQLFactory(database.InstanceMongoDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var users []userEmailBSON
+	findOptions := options.Find().
+		SetProjection(bson.M{"email": 1, "role": 1, "createdAt": 1, "tenantId": 1}).
+		SetSort(bson.M{"createdAt": 1})
+	if err := db.FindAll(ctx, usersCollection, bson.M{}, &users, findOptions); err != nil {
+		log.Fatal(err)
+	}
+
+	groups := make(map[string][]userEmailBSON)
+	for _, user := range users {
+		key := user.TenantId + " " + domain.NormalizeEmail(user.Email)
+		groups[key] = append(groups[key], user)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var duplicates, normalized int
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) > 1 {
+			duplicates++
+			fmt.Printf("%s: %d accounts\n", key, len(group))
+			for _, user := range group {
+				fmt.Printf("\t%s\t%s\t%s\t%s\n", user.ID.Hex(), user.Email, user.Role, user.CreatedAt.Format(time.RFC3339))
+			}
+			continue
+		}
+
+		if email := domain.NormalizeEmail(group[0].Email); *normalize && group[0].Email != email {
+			update := bson.M{"$set": bson.M{"email": email}}
+			if err := db.Update(ctx, usersCollection, bson.M{"_id": group[0].ID}, update); err != nil {
+				log.Fatal(err)
+			}
+			normalized++
+		}
+	}
+
+	fmt.Printf("%d users scanned, %d duplicated emails", len(users), duplicates)
+	if *normalize {
+		fmt.Printf(", %d emails normalized", normalized)
+	}
+	fmt.Println()
+
+	if duplicates > 0 {
+		os.Exit(1)
+	}
+}
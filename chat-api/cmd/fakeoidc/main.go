@@ -0,0 +1,35 @@
+// Command fakeoidc runs the oidctest provider so the single sign-on flow can
+// be exercised locally without a corporate identity provider.
+package main
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"chat-api/infrastructure/common"
+	"chat-api/infrastructure/oidctest"
+)
+
+func main() {
+	var (
+		addr   = common.GetEnv("FAKE_OIDC_ADDR", ":9000")
+		issuer = common.GetEnv("FAKE_OIDC_ISSUER", "http://localhost:9000")
+		client = common.GetEnv("OIDC_CLIENT_ID", "chat-api")
+	)
+
+	provider, err := oidctest.New(issuer, client, oidctest.User{
+		Subject:       common.GetEnv("FAKE_OIDC_SUBJECT", "fake-admin"),
+		Email:         common.GetEnv("FAKE_OIDC_EMAIL", "admin@example.com"),
+		EmailVerified: true,
+		GivenName:     common.GetEnv("FAKE_OIDC_GIVEN_NAME", "Fake"),
+		FamilyName:    common.GetEnv("FAKE_OIDC_FAMILY_NAME", "Admin"),
+		Groups:        strings.Split(common.GetEnv("FAKE_OIDC_GROUPS", "chat-admins"), ","),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("fake oidc provider listening on %s as issuer %s", addr, issuer)
+	log.Fatal(http.ListenAndServe(addr, provider))
+}
@@ -6,6 +6,7 @@ import (
 	"chat-api/infrastructure/database"
 	"chat-api/infrastructure/log"
 	"chat-api/infrastructure/router"
+	"chat-api/infrastructure/storage"
 	"chat-api/infrastructure/validation"
 	"os"
 	"time"
@@ -18,12 +19,33 @@ func init() {
 }
 
 func main() {
+	// NOSQL_DATABASE=memory runs the API without MongoDB, for demos and local development
+	var dbInstance = database.InstanceMongoDB
+	if common.GetEnv("NOSQL_DATABASE", "mongodb") == "memory" {
+		dbInstance = database.InstanceMemory
+	}
+
 	var app = infrastructure.NewConfig().
 		Name(os.Getenv("APP_NAME")).
 		ContextTimeout(30 * time.Second).
 		Logger(log.InstanceLogrusLogger).
 		Validator(validation.InstanceGoPlayground).
-		DbNoSQL(database.InstanceMongoDB)
+		DbNoSQL(dbInstance)
+
+	// SQL_DATABASE=postgres|sqlite keeps users and channels in a relational database
+	switch common.GetEnv("SQL_DATABASE", "") {
+	case "postgres":
+		app.DbSQL(database.InstancePostgres)
+	case "sqlite":
+		app.DbSQL(database.InstanceSQLite)
+	}
+
+	// BLOB_STORE=local|s3 keeps attachments on disk, under ATTACHMENTS_DIR, or in an S3 compatible bucket
+	if common.GetEnv("BLOB_STORE", "local") == "s3" {
+		app.BlobStore(storage.InstanceS3)
+	} else {
+		app.BlobStore(storage.InstanceLocal)
+	}
 
 	app.WebServerPort(os.Getenv("PORT")).
 		WebServer(router.InstanceGin).
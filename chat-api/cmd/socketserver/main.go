@@ -2,13 +2,14 @@ package main
 
 import (
 	"bytes"
+	"chat-api/domain"
 	"chat-api/usecase"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
@@ -22,21 +23,85 @@ var upgrader = websocket.Upgrader{
 	WriteBufferSize: 1024,
 }
 
+// Types of the messages sent to clients, the API posts edits, deletions, read
+// markers, internal notes, surveys and SLA breaches to /events. Clients send
+// messages, read markers, notes and ratings answering a survey
+const (
+	typeMessage = "message"
+	typeEdit    = "edit"
+	typeDelete  = "delete"
+	typeRead    = "read"
+	typeNote    = "note"
+	typeSurvey  = "survey"
+	typeRating  = "rating"
+	typeBreach  = "breach"
+)
+
+// roleRep is the role of the users who answer customers, only they see internal notes
+const roleRep = "ADMIN"
+
+// Message carries the ids of the files a client uploaded beforehand in
+// AttachmentIds, the clients receive what the API kept of them in Attachments.
+// Messages are only sent to the clients of the organization in TenantId.
+// Internal messages, notes and SLA breaches, are only sent to reps. The others
+// are sent to the reps and to UserEmail, the customer of the channel. Ratings
+// carry the score in Rating and the optional comment in Message. Breaches
+// carry the breached target in Message
 type Message struct {
-	ChannelId   string    `json:"channelId"`
-	Message     string    `json:"message"`
-	MessageFrom string    `json:"messageFrom"`
-	TimeStamp   time.Time `json:"timeStamp"`
+	Type          string                            `json:"type"`
+	TenantId      string                            `json:"tenantId"`
+	ChannelId     string                            `json:"channelId"`
+	UserEmail     string                            `json:"userEmail,omitempty"`
+	MessageId     string                            `json:"messageId"`
+	Message       string                            `json:"message"`
+	MessageFrom   string                            `json:"messageFrom"`
+	TimeStamp     time.Time                         `json:"timeStamp"`
+	AttachmentIds []string                          `json:"attachmentIds,omitempty"`
+	Attachments   []usecase.MessageAttachmentOutput `json:"attachments,omitempty"`
+	Internal      bool                              `json:"internal,omitempty"`
+	Rating        int                               `json:"rating,omitempty"`
+}
+
+// Client is who a connection authenticated as, a guest is bound to the
+// channel of its token
+type Client struct {
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	ChannelId string `json:"channelId"`
+	TenantId  string `json:"tenantId"`
+	Guest     bool   `json:"-"`
+}
+
+// receives tells whether the message is for the client. Clients only see the
+// channels of their organization. Reps see every one of them, as they do through
+// the API, and its internal notes. Customers only see the channels they are the
+// customer of, guests only the channel of their token
+func (c Client) receives(message Message) bool {
+	// Guests are customers, whatever their session says
+	rep := c.Role == roleRep && !c.Guest
+	switch {
+	case c.TenantId == "" || message.TenantId != c.TenantId:
+		return false
+	case rep:
+		return true
+	case message.Internal:
+		return false
+	case c.Guest:
+		return message.ChannelId != "" && message.ChannelId == c.ChannelId
+	default:
+		return c.Email != "" && domain.NormalizeEmail(message.UserEmail) == domain.NormalizeEmail(c.Email)
+	}
 }
 
+// Hub keeps who each connection authenticated as
 type Hub struct {
-	clients   map[*websocket.Conn]bool
+	clients   map[*websocket.Conn]Client
 	broadcast chan Message
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		clients:   make(map[*websocket.Conn]bool),
+		clients:   make(map[*websocket.Conn]Client),
 		broadcast: make(chan Message),
 	}
 }
@@ -45,8 +110,11 @@ func (h *Hub) run() {
 	for {
 		select {
 		case message := <-h.broadcast:
-			for client := range h.clients {
-				if err := client.WriteJSON(message); !errors.Is(err, nil) {
+			for conn, client := range h.clients {
+				if !client.receives(message) {
+					continue
+				}
+				if err := conn.WriteJSON(message); !errors.Is(err, nil) {
 					log.Printf("error occurred: %v", err)
 				}
 			}
@@ -72,6 +140,18 @@ func main() {
 	e.GET("/ws", func(c echo.Context) error {
 		upgrader.CheckOrigin = func(r *http.Request) bool { return true }
 
+		// Guests hold a token bound to their channel and are checked against the guest session instead
+		authURL := os.Getenv("SERVER_USER_URL")
+		if c.QueryParam("guest") == "true" {
+			authURL = os.Getenv("SERVER_GUEST_URL")
+		}
+
+		status, client := authenticate(authURL, c.QueryParam("token"))
+		if status != http.StatusOK {
+			return c.NoContent(status)
+		}
+		client.Guest = c.QueryParam("guest") == "true"
+
 		ws, err := upgrader.Upgrade(c.Response().Writer, c.Request(), nil)
 		if !errors.Is(err, nil) {
 			log.Println(err)
@@ -83,22 +163,172 @@ func main() {
 		}()
 
 		// Add client
-		hub.clients[ws] = true
+		hub.clients[ws] = client
 
 		log.Println("Connected!")
 
 		// Listen on connection
-		read(hub, ws)
+		read(hub, ws, client, c.QueryParam("token"))
 		return nil
 	})
+
+	// The API reports changes made over REST here, it authenticates with SOCKET_EVENTS_KEY
+	e.POST("/events", func(c echo.Context) error {
+		key := os.Getenv("SOCKET_EVENTS_KEY")
+		if key == "" || c.Request().Header.Get("X-API-Key") != key {
+			return c.NoContent(http.StatusUnauthorized)
+		}
+
+		var message Message
+		if err := c.Bind(&message); err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+		switch message.Type {
+		case typeEdit, typeDelete, typeRead, typeNote, typeSurvey, typeBreach:
+		default:
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		hub.broadcast <- message
+		return c.NoContent(http.StatusAccepted)
+	})
 	e.Logger.Fatal(e.Start(":8080"))
 }
 
-func read(hub *Hub, client *websocket.Conn) {
+// authenticate asks the API who owns the token, so expired tokens and
+// deactivated accounts are refused before the connection is upgraded. It
+// returns the email, role and organization of the owner, or the channel of a
+// guest, along with the status
+func authenticate(authURL, token string) (int, Client) {
+	if token == "" {
+		return http.StatusUnauthorized, Client{}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, authURL, nil)
+	if err != nil {
+		log.Printf("error occurred: %v", err)
+		return http.StatusInternalServerError, Client{}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		log.Printf("error occurred: %v", err)
+		return http.StatusBadGateway, Client{}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var owner Client
+		if err := json.NewDecoder(resp.Body).Decode(&owner); err != nil {
+			log.Printf("error occurred: %v", err)
+			return http.StatusBadGateway, Client{}
+		}
+		return http.StatusOK, owner
+	case http.StatusForbidden:
+		return http.StatusForbidden, Client{}
+	default:
+		return http.StatusUnauthorized, Client{}
+	}
+}
+
+// markRead stores the read marker of the client with its own token, the API
+// then posts the marker to /events for every client to see
+func markRead(token string, message Message) {
+	body, _ := json.Marshal(map[string]string{"messageId": message.MessageId})
+	forward(token, http.MethodPut, message.ChannelId, "read", body)
+}
+
+// addNote stores the note with the token of the client, so only reps can add
+// one. The API then posts it to /events for the reps to see
+func addNote(token string, message Message) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"message":       message.Message,
+		"attachmentIds": message.AttachmentIds,
+	})
+	forward(token, http.MethodPost, message.ChannelId, "note", body)
+}
+
+// rate stores the answer of the customer to the survey with their own token, so
+// only the customer of the channel can rate it
+func rate(token string, message Message) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"score":   message.Rating,
+		"comment": message.Message,
+	})
+	forward(token, http.MethodPost, message.ChannelId, "rating", body)
+}
+
+// forward sends a request about the channel to the API on behalf of the client,
+// it returns the body of the answer when the API accepted it
+func forward(token, method, channelId, path string, body []byte) ([]byte, bool) {
+	req, err := http.NewRequest(
+		method,
+		os.Getenv("SERVER_CHANNEL_URL")+"/"+url.PathEscape(channelId)+"/"+path,
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		log.Printf("error occurred: %v", err)
+		return nil, false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		log.Printf("error occurred: %v", err)
+		return nil, false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		log.Printf("error forwarding %s of channel %s: %s", path, channelId, resp.Status)
+		return nil, false
+	}
+	result, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Printf("error occurred: %v", err)
+		return nil, false
+	}
+	return result, true
+}
+
+// send stores the message with the token of the client, so only the participants
+// of the channel can post in it, and tells whether it can be broadcast
+func send(token string, message *Message) bool {
+	body, _ := json.Marshal(map[string]interface{}{
+		"message":       message.Message,
+		"attachmentIds": message.AttachmentIds,
+	})
+	answer, ok := forward(token, http.MethodPost, message.ChannelId, "message", body)
+	if !ok {
+		return false
+	}
+
+	result := usecase.CreateMessageOutput{}
+	if err := json.Unmarshal(answer, &result); err != nil {
+		log.Printf("error occurred: %v", err)
+		return false
+	}
+	// Clients need the id the API gave the message to edit or delete it
+	if len(result.Messages) > 0 {
+		last := result.Messages[len(result.Messages)-1]
+		message.MessageId = last.Id
+		message.MessageFrom = last.MessageFrom
+		message.Attachments = last.Attachments
+	}
+	message.UserEmail = result.UserEmail
+	message.AttachmentIds = nil
+	return true
+}
+
+func read(hub *Hub, client *websocket.Conn, owner Client, token string) {
 
 	for {
 		var message Message
-		httpClient := &http.Client{}
 		err := client.ReadJSON(&message)
 
 		if !errors.Is(err, nil) {
@@ -107,31 +337,26 @@ func read(hub *Hub, client *websocket.Conn) {
 			break
 		}
 
-		message.TimeStamp = time.Now()
-		requestBody, _ := json.Marshal(message)
-		req, err := http.NewRequest(http.MethodPost, os.Getenv("SERVER_MESSAGE_URL"), bytes.NewBuffer(requestBody))
-		if err != nil {
-			fmt.Println(err)
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-		resp, err := httpClient.Do(req)
-		if err != nil {
-			fmt.Println(err)
+		switch message.Type {
+		case typeRead:
+			markRead(token, message)
+			continue
+		case typeNote:
+			addNote(token, message)
+			continue
+		case typeRating:
+			rate(token, message)
+			continue
 		}
 
-		defer resp.Body.Close()
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			fmt.Println(err)
-		}
-
-		result := usecase.CreateMessageOutput{}
-		err = json.Unmarshal(body, &result)
-		if err != nil {
-			fmt.Println(err)
+		message.Type = typeMessage
+		// The API stored the message in the organization of the token
+		message.TenantId = owner.TenantId
+		message.Internal = false
+		message.TimeStamp = time.Now()
+		if !send(token, &message) {
+			continue
 		}
-		fmt.Println(result)
 		// Send a message to hub
 		hub.broadcast <- message
 	}
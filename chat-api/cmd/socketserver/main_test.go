@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestHub_Broadcast(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hub       = NewHub()
+		connected = make(chan struct{})
+	)
+	go hub.run()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		q := r.URL.Query()
+		hub.clients[ws] = Client{Email: q.Get("email"), Role: q.Get("role"), ChannelId: q.Get("channelId"), TenantId: q.Get("tenantId"), Guest: q.Get("guest") == "true"}
+		connected <- struct{}{}
+	}))
+	defer server.Close()
+
+	dial := func(query string) *websocket.Conn {
+		ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?"+query, nil)
+		if err != nil {
+			t.Fatalf("[TestCase 'dial'] Result: '%v' | Expected: '%v'", err, nil)
+		}
+		<-connected
+		return ws
+	}
+	var (
+		customer        = dial("email=customer@email.com&role=USER&tenantId=t1")
+		otherCustomer   = dial("email=other@email.com&role=USER&tenantId=t1")
+		guest           = dial("email=guest@email.com&channelId=c3&guest=true&tenantId=t1")
+		otherGuest      = dial("email=other@email.com&channelId=c2&guest=true&role=ADMIN&tenantId=t1")
+		rep             = dial("email=rep@email.com&role=ADMIN&tenantId=t1")
+		foreignCustomer = dial("email=customer@email.com&role=USER&tenantId=t2")
+		foreignRep      = dial("email=rep@other.com&role=ADMIN&tenantId=t2")
+	)
+	for _, client := range []*websocket.Conn{customer, otherCustomer, guest, otherGuest, rep, foreignCustomer, foreignRep} {
+		defer client.Close()
+	}
+
+	hub.broadcast <- Message{Type: typeNote, TenantId: "t1", ChannelId: "c1", UserEmail: "customer@email.com", Message: "courier lost the parcel", Internal: true}
+	hub.broadcast <- Message{Type: typeMessage, TenantId: "t1", ChannelId: "c1", UserEmail: "Customer@Email.com", Message: "your parcel is on its way"}
+	hub.broadcast <- Message{Type: typeMessage, TenantId: "t1", ChannelId: "c2", UserEmail: "other@email.com", Message: "where is my refund"}
+	hub.broadcast <- Message{Type: typeMessage, TenantId: "t1", ChannelId: "c3", UserEmail: "guest@email.com", Message: "hello"}
+	hub.broadcast <- Message{Type: typeBreach, TenantId: "t1", ChannelId: "c3", Message: "first_response", Internal: true}
+	hub.broadcast <- Message{Type: typeMessage, TenantId: "t1", ChannelId: "c1", UserEmail: "customer@email.com", Message: "thank you"}
+	hub.broadcast <- Message{Type: typeMessage, TenantId: "t2", ChannelId: "c9", UserEmail: "customer@email.com", Message: "your order shipped"}
+
+	// Every client reads what it expects in order, a message it should not have
+	// received would come first
+	for _, tt := range []struct {
+		name     string
+		client   *websocket.Conn
+		expected []string
+	}{
+		{name: "customer", client: customer, expected: []string{"your parcel is on its way", "thank you"}},
+		{name: "customer of another channel", client: otherCustomer, expected: []string{"where is my refund"}},
+		{name: "guest", client: guest, expected: []string{"hello"}},
+		{name: "guest bound to another channel", client: otherGuest, expected: []string{"where is my refund"}},
+		{name: "rep", client: rep, expected: []string{"courier lost the parcel", "your parcel is on its way", "where is my refund", "hello", "first_response", "thank you"}},
+		{name: "customer of another organization", client: foreignCustomer, expected: []string{"your order shipped"}},
+		{name: "rep of another organization", client: foreignRep, expected: []string{"your order shipped"}},
+	} {
+		for _, expected := range tt.expected {
+			var message Message
+			tt.client.SetReadDeadline(time.Now().Add(5 * time.Second))
+			if err := tt.client.ReadJSON(&message); err != nil || message.Message != expected {
+				t.Errorf("[TestCase '%s'] Result: '%v', '%v' | Expected: '%v'", tt.name, message.Message, err, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Intervals of the buckets of an analytics series
+const (
+	AnalyticsByHour = "hour"
+	AnalyticsByDay  = "day"
+
+	// MaxHourlyAnalyticsDays bounds the range of an hourly series, daily series
+	// are bound by MaxSatisfactionReportDays like every report
+	MaxHourlyAnalyticsDays = 31
+)
+
+var (
+	ErrHourlyRangeTooLong = errors.New("hourly series cover up to 31 days")
+	ErrUnknownTimeZone    = errors.New("unknown time zone")
+)
+
+type (
+	// AnalyticsQuery selects what happened from the inclusive From to the
+	// exclusive To, in buckets of an Interval starting at midnight or on the
+	// hour in Location. An empty Tag matches every channel
+	AnalyticsQuery struct {
+		From     time.Time
+		To       time.Time
+		Interval string
+		Location *time.Location
+		Tag      string
+	}
+
+	// ChannelBucket aggregates the channels of a bucket. Channels count as
+	// opened, with their messages, in the bucket they were created in, as
+	// answered in the bucket of their first rep response and as closed in the
+	// bucket they were resolved in. A median is zero without channels to take it of
+	ChannelBucket struct {
+		Start               time.Time
+		Opened              int
+		Messages            int
+		Answered            int
+		Closed              int
+		MedianFirstResponse time.Duration
+		MedianResolution    time.Duration
+	}
+
+	// RepBucket is the throughput of a rep, the channels they resolved in a bucket
+	RepBucket struct {
+		RepEmail string
+		Start    time.Time
+		Resolved int
+	}
+
+	// RepResolution is how long a rep took to resolve the channels of the whole range
+	RepResolution struct {
+		RepEmail         string
+		Resolved         int
+		MedianResolution time.Duration
+	}
+
+	// RepWorkload counts the open channels of a status assigned to a rep
+	RepWorkload struct {
+		RepEmail string
+		Status   string
+		Count    int
+	}
+
+	// AnalyticsRepository aggregates the channels of the tenant for dashboards.
+	// Messages count what customers and reps sent, internal notes left out
+	AnalyticsRepository interface {
+		GetChannelBuckets(context.Context, AnalyticsQuery) ([]ChannelBucket, error)
+		// GetBacklog counts the open channels by status, whenever they were opened
+		GetBacklog(context.Context, string) (map[string]int, error)
+		GetRepBuckets(context.Context, AnalyticsQuery) ([]RepBucket, error)
+		// GetRepResolutions takes the medians over the range, which the medians
+		// of its buckets do not add up to
+		GetRepResolutions(context.Context, AnalyticsQuery) ([]RepResolution, error)
+		// GetRepWorkload counts the open channels of every rep by status
+		GetRepWorkload(context.Context, string) ([]RepWorkload, error)
+	}
+)
+
+// BucketStart is the start of the bucket a time falls in
+func (q AnalyticsQuery) BucketStart(t time.Time) time.Time {
+	t = t.In(q.Location)
+	if q.Interval == AnalyticsByHour {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, q.Location)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, q.Location)
+}
+
+// Buckets lists the start of every bucket of the range, so a series has no gaps.
+// Days are stepped on the calendar, they last 23 or 25 hours around DST changes.
+// A query without a Location has none
+func (q AnalyticsQuery) Buckets() []time.Time {
+	var buckets []time.Time
+	if q.Location == nil {
+		return buckets
+	}
+	for start := q.BucketStart(q.From); start.Before(q.To); {
+		buckets = append(buckets, start)
+		if q.Interval == AnalyticsByHour {
+			start = start.Add(time.Hour)
+		} else {
+			start = start.AddDate(0, 0, 1)
+		}
+	}
+	return buckets
+}
+
+// MedianDuration is the middle duration, the mean of the two middle ones for an
+// even count. It is false without durations
+func MedianDuration(durations []time.Duration) (time.Duration, bool) {
+	if len(durations) == 0 {
+		return 0, false
+	}
+
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle], true
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2, true
+}
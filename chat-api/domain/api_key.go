@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// SERVICE is the role of principals authenticated with an API key
+	SERVICE = "SERVICE"
+
+	// ScopeMessagesWrite lets internal callers post messages on behalf of users
+	ScopeMessagesWrite = "messages:write"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyScope    = errors.New("api key is missing the required scope")
+)
+
+type (
+	APIKeyRepository interface {
+		CreateAPIKey(context.Context, APIKey) (APIKey, error)
+		GetAPIKeys(context.Context) ([]APIKey, error)
+		GetAPIKeyById(context.Context, string) (APIKey, error)
+		// GetAPIKeyByPrefix finds a key in any organization, the key then decides the tenant of the request
+		GetAPIKeyByPrefix(context.Context, string) (APIKey, error)
+		RevokeAPIKey(context.Context, APIKey) error
+	}
+
+	APIKeyService interface {
+		// GenerateAPIKey returns the plaintext key, its public lookup prefix and its hash
+		GenerateAPIKey(context.Context) (string, string, string, error)
+		// ParseAPIKey returns the lookup prefix of a presented key
+		ParseAPIKey(context.Context, string) (string, error)
+		VerifyAPIKey(context.Context, string, string) bool
+	}
+
+	APIKey struct {
+		id        primitive.ObjectID
+		name      string
+		prefix    string
+		hashedKey string
+		scopes    []string
+		createdBy string
+		createdAt time.Time
+		revokedAt time.Time
+		tenantId  string
+	}
+)
+
+func NewAPIKey(id primitive.ObjectID, name, prefix, hashedKey string, scopes []string, createdBy string, createdAt time.Time) APIKey {
+	return APIKey{
+		id:        id,
+		name:      name,
+		prefix:    prefix,
+		hashedKey: hashedKey,
+		scopes:    scopes,
+		createdBy: createdBy,
+		createdAt: createdAt,
+	}
+}
+
+func (k *APIKey) Revoke(at time.Time) {
+	if k.revokedAt.IsZero() {
+		k.revokedAt = at
+	}
+}
+
+func (k *APIKey) AssignTenant(tenantId string) {
+	k.tenantId = tenantId
+}
+
+func (k APIKey) IsRevoked() bool {
+	return !k.revokedAt.IsZero()
+}
+
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (k APIKey) Id() primitive.ObjectID {
+	return k.id
+}
+
+func (k APIKey) Name() string {
+	return k.name
+}
+
+func (k APIKey) Prefix() string {
+	return k.prefix
+}
+
+func (k APIKey) HashedKey() string {
+	return k.hashedKey
+}
+
+func (k APIKey) Scopes() []string {
+	return k.scopes
+}
+
+func (k APIKey) CreatedBy() string {
+	return k.createdBy
+}
+
+func (k APIKey) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+func (k APIKey) RevokedAt() time.Time {
+	return k.revokedAt
+}
+
+func (k APIKey) TenantId() string {
+	return k.tenantId
+}
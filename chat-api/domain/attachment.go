@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxAttachmentSize is the largest file a participant can upload, in bytes
+	MaxAttachmentSize = 10 << 20
+	// MaxMessageAttachments is how many attachments one message can reference
+	MaxMessageAttachments = 10
+	// AttachmentURLTTL is how long a download URL stays valid
+	AttachmentURLTTL = 5 * time.Minute
+)
+
+// AttachmentContentTypes are the types accepted for upload, detected from the
+// content of the file rather than what the client claims
+var AttachmentContentTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain; charset=utf-8",
+}
+
+var (
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrAttachmentTooLarge    = errors.New("attachment is too large")
+	ErrAttachmentEmpty       = errors.New("attachment is empty")
+	ErrAttachmentType        = errors.New("attachment type is not allowed")
+	ErrTooManyAttachments    = errors.New("too many attachments")
+	ErrInvalidAttachmentURL  = errors.New("attachment url is invalid or expired")
+	ErrNotChannelParticipant = errors.New("only the participants of the channel can do this")
+	ErrBlobNotFound          = errors.New("blob not found")
+)
+
+type (
+	// BlobStore keeps the content of files under keys made of letters, digits and slashes
+	BlobStore interface {
+		Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+		// Get returns ErrBlobNotFound for unknown keys, the caller closes the content
+		Get(ctx context.Context, key string) (io.ReadCloser, error)
+		Delete(ctx context.Context, key string) error
+	}
+
+	AttachmentRepository interface {
+		CreateAttachment(context.Context, Attachment) (Attachment, error)
+		GetAttachmentById(context.Context, string) (Attachment, error)
+	}
+
+	// AttachmentURLSigner signs download links so they can be fetched without
+	// credentials until they expire
+	AttachmentURLSigner interface {
+		Sign(ctx context.Context, attachmentId string, expiresAt time.Time) string
+		// Verify returns ErrInvalidAttachmentURL for forged or expired links
+		Verify(ctx context.Context, attachmentId string, expiresAt time.Time, signature string) error
+	}
+
+	// MessageAttachment is what a message keeps of the attachments it references
+	MessageAttachment struct {
+		Id          primitive.ObjectID
+		FileName    string
+		ContentType string
+		Size        int64
+	}
+
+	Attachment struct {
+		id          primitive.ObjectID
+		channelId   string
+		uploadedBy  string
+		fileName    string
+		contentType string
+		size        int64
+		createdAt   time.Time
+		tenantId    string
+	}
+)
+
+func NewAttachment(id primitive.ObjectID, channelId, uploadedBy, fileName, contentType string, size int64, createdAt time.Time) Attachment {
+	return Attachment{
+		id:          id,
+		channelId:   channelId,
+		uploadedBy:  uploadedBy,
+		fileName:    fileName,
+		contentType: contentType,
+		size:        size,
+		createdAt:   createdAt,
+	}
+}
+
+func (a *Attachment) AssignTenant(tenantId string) {
+	a.tenantId = tenantId
+}
+
+func (a Attachment) Id() primitive.ObjectID {
+	return a.id
+}
+
+func (a Attachment) ChannelId() string {
+	return a.channelId
+}
+
+func (a Attachment) UploadedBy() string {
+	return a.uploadedBy
+}
+
+func (a Attachment) FileName() string {
+	return a.fileName
+}
+
+func (a Attachment) ContentType() string {
+	return a.contentType
+}
+
+func (a Attachment) Size() int64 {
+	return a.size
+}
+
+func (a Attachment) CreatedAt() time.Time {
+	return a.createdAt
+}
+
+func (a Attachment) TenantId() string {
+	return a.tenantId
+}
+
+// Key is where the content of the attachment is kept in the BlobStore
+func (a Attachment) Key() string {
+	tenantId := a.tenantId
+	if tenantId == "" {
+		tenantId = DefaultTenantID
+	}
+	return "attachments/" + tenantId + "/" + a.channelId + "/" + a.id.Hex()
+}
+
+// MessageAttachment is what messages referencing the attachment keep of it
+func (a Attachment) MessageAttachment() MessageAttachment {
+	return MessageAttachment{
+		Id:          a.id,
+		FileName:    a.fileName,
+		ContentType: a.contentType,
+		Size:        a.size,
+	}
+}
+
+// AttachmentFileName keeps the last element of a client supplied file name,
+// without control characters and at most 255 bytes long
+func AttachmentFileName(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name))
+
+	for len(name) > 255 {
+		runes := []rune(name)
+		name = string(runes[:len(runes)-1])
+	}
+	if name == "" || name == "." || name == ".." {
+		return "attachment"
+	}
+	return name
+}
+
+// IsAttachmentContentType tells whether files of the detected type can be uploaded
+func IsAttachmentContentType(contentType string) bool {
+	for _, allowed := range AttachmentContentTypes {
+		if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// IsParticipant tells whether the email is the customer or the rep of the channel
+func (c Channel) IsParticipant(email string) bool {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return false
+	}
+	return email == NormalizeEmail(c.userEmail) || email == NormalizeEmail(c.repEmail)
+}
+
+// HidesAttachment tells whether the attachment was sent with a message that was
+// deleted since, like the text of the message it is no longer shown. Attachments
+// of internal notes are hidden too unless internal notes are shown
+func (c Channel) HidesAttachment(id primitive.ObjectID, internal bool) bool {
+	for _, message := range c.messages {
+		if !message.IsDeleted && (internal || !message.Internal) {
+			continue
+		}
+		for _, attachment := range message.Attachments {
+			if attachment.Id == id {
+				return true
+			}
+		}
+	}
+	return false
+}
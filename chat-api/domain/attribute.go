@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+)
+
+const (
+	MaxChannelAttributes    = 20
+	maxAttributeValueLength = 256
+)
+
+var ErrInvalidAttribute = errors.New("attributes are named with letters, digits and underscores, values hold up to 256 characters and channels up to 20 attributes")
+
+var attributeNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,63}$`)
+
+// UpdateAttributes merges custom attributes such as an order id or the page the
+// customer wrote from into the channel, an empty value removes the attribute
+func (c *Channel) UpdateAttributes(attributes map[string]string) error {
+	merged := make(map[string]string, len(c.attributes)+len(attributes))
+	for name, value := range c.attributes {
+		merged[name] = value
+	}
+	for name, value := range attributes {
+		if !IsAttributeName(name) || len(value) > maxAttributeValueLength {
+			return ErrInvalidAttribute
+		}
+		if value == "" {
+			delete(merged, name)
+			continue
+		}
+		merged[name] = value
+	}
+	if len(merged) > MaxChannelAttributes {
+		return ErrInvalidAttribute
+	}
+
+	c.attributes = merged
+	return nil
+}
+
+// IsAttributeName tells whether the name can be stored and filtered on
+func IsAttributeName(name string) bool {
+	return attributeNamePattern.MatchString(name)
+}
+
+func (c Channel) Attributes() map[string]string {
+	return c.attributes
+}
+
+// AttributeNames lists the attributes of the channel in alphabetical order
+func (c Channel) AttributeNames() []string {
+	names := make([]string, 0, len(c.attributes))
+	for name := range c.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	AuditAccountLocked   = "ACCOUNT_LOCKED"
+	AuditAccountUnlocked = "ACCOUNT_UNLOCKED"
+
+	AuditTwoFactorEnabled        = "TWO_FACTOR_ENABLED"
+	AuditTwoFactorDisabled       = "TWO_FACTOR_DISABLED"
+	AuditSecuritySettingsUpdated = "SECURITY_SETTINGS_UPDATED"
+
+	AuditPasswordChanged = "PASSWORD_CHANGED"
+	AuditUserDeactivated = "USER_DEACTIVATED"
+	AuditUserReactivated = "USER_REACTIVATED"
+
+	AuditSSOLogin        = "SSO_LOGIN"
+	AuditUserProvisioned = "USER_PROVISIONED"
+
+	AuditAPIKeyCreated = "API_KEY_CREATED"
+	AuditAPIKeyRevoked = "API_KEY_REVOKED"
+
+	AuditOrganizationCreated         = "ORGANIZATION_CREATED"
+	AuditOrganizationSettingsUpdated = "ORGANIZATION_SETTINGS_UPDATED"
+
+	AuditLogin        = "LOGIN"
+	AuditUserCreated  = "USER_CREATED"
+	AuditAdminCreated = "ADMIN_CREATED"
+	AuditRoleChanged  = "ROLE_CHANGED"
+
+	AuditChannelClaimed       = "CHANNEL_CLAIMED"
+	AuditChannelTransferred   = "CHANNEL_TRANSFERRED"
+	AuditChannelClosed        = "CHANNEL_CLOSED"
+	AuditChannelStatusChanged = "CHANNEL_STATUS_CHANGED"
+
+	AuditMessageEdited  = "MESSAGE_EDITED"
+	AuditMessageDeleted = "MESSAGE_DELETED"
+
+	AuditEventsExported = "AUDIT_EVENTS_EXPORTED"
+
+	AuditOutcomeSuccess = "SUCCESS"
+	AuditOutcomeFailure = "FAILURE"
+)
+
+// MaxAuditExportRows caps how many events a single export returns
+const MaxAuditExportRows = 10000
+
+var ErrInvalidTimeRange = errors.New("time range ends before it starts")
+
+type (
+	AuditLogger interface {
+		Record(context.Context, AuditEvent)
+	}
+
+	// AuditEventRepository is append only, events are never updated or deleted
+	AuditEventRepository interface {
+		StoreAuditEvent(context.Context, AuditEvent) error
+		GetAuditEvents(context.Context, AuditQuery, int, int) ([]AuditEvent, error)
+		GetAuditEventsCount(context.Context, AuditQuery) (int64, error)
+	}
+
+	// AuditQuery narrows audit events down, zero values match everything
+	AuditQuery struct {
+		Actor  string
+		Target string
+		From   time.Time
+		To     time.Time
+	}
+
+	AuditEvent struct {
+		Action    string
+		Actor     string
+		Target    string
+		IP        string
+		Outcome   string
+		Timestamp time.Time
+	}
+)
@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrCannedResponseNotFound      = errors.New("canned response not found")
+	ErrCannedResponseShortcutTaken = errors.New("shortcut is already used by another canned response")
+	ErrInvalidShortcut             = errors.New("shortcuts are made of lowercase letters, digits, dashes and underscores")
+	ErrUnknownTemplateVariable     = errors.New("unknown template variable")
+)
+
+var (
+	shortcutPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+	// templateVariablePattern matches {{customer.firstName}}, spaces inside the braces are allowed
+	templateVariablePattern = regexp.MustCompile(`\{\{\s*([a-zA-Z]+\.[a-zA-Z]+)\s*\}\}`)
+)
+
+// TemplateVariables are the variables the body of a canned response can use
+var TemplateVariables = []string{
+	"customer.firstName",
+	"customer.lastName",
+	"customer.fullName",
+	"customer.email",
+	"rep.firstName",
+	"rep.lastName",
+	"rep.fullName",
+	"rep.email",
+}
+
+type (
+	CannedResponseRepository interface {
+		CreateCannedResponse(context.Context, CannedResponse) (CannedResponse, error)
+		GetCannedResponseById(context.Context, string) (CannedResponse, error)
+		GetCannedResponses(context.Context, CannedResponseQuery) ([]CannedResponse, error)
+		UpdateCannedResponse(context.Context, CannedResponse) error
+		DeleteCannedResponse(context.Context, CannedResponse) error
+	}
+
+	// CannedResponseQuery lists canned responses ordered by shortcut, empty filters match every response
+	CannedResponseQuery struct {
+		// VisibleTo keeps the shared responses and those of the rep
+		VisibleTo      string
+		Shortcut       string
+		ShortcutPrefix string
+		Tag            string
+	}
+
+	// Macro changes the channel a canned response is inserted in, empty fields change nothing
+	Macro struct {
+		Status     string
+		AddTags    []string
+		RemoveTags []string
+	}
+
+	// CannedResponse is an answer reps insert by its shortcut. Personal responses
+	// are only seen by their owner, shared ones by every rep of the organization
+	CannedResponse struct {
+		id        primitive.ObjectID
+		shortcut  string
+		title     string
+		body      string
+		tags      []string
+		owner     string
+		shared    bool
+		macro     Macro
+		createdAt time.Time
+		updatedAt time.Time
+		tenantId  string
+	}
+)
+
+func NewCannedResponse(
+	id primitive.ObjectID,
+	shortcut, title, body string,
+	tags []string,
+	owner string,
+	shared bool,
+	macro Macro,
+	createdAt, updatedAt time.Time,
+) CannedResponse {
+	return CannedResponse{
+		id:        id,
+		shortcut:  NormalizeShortcut(shortcut),
+		title:     title,
+		body:      body,
+		tags:      NormalizeTags(tags),
+		owner:     NormalizeEmail(owner),
+		shared:    shared,
+		macro:     normalizeMacro(macro),
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+// Update replaces what reps can change of the response, its owner stays
+func (r *CannedResponse) Update(shortcut, title, body string, tags []string, shared bool, macro Macro, at time.Time) {
+	r.shortcut = NormalizeShortcut(shortcut)
+	r.title = title
+	r.body = body
+	r.tags = NormalizeTags(tags)
+	r.shared = shared
+	r.macro = normalizeMacro(macro)
+	r.updatedAt = at
+}
+
+func (r *CannedResponse) AssignTenant(tenantId string) {
+	r.tenantId = tenantId
+}
+
+// VisibleTo tells whether the rep can see, insert and change the response
+func (r CannedResponse) VisibleTo(email string) bool {
+	return r.shared || r.owner == NormalizeEmail(email)
+}
+
+// Validate checks the shortcut and the variables used in the body
+func (r CannedResponse) Validate() error {
+	if !shortcutPattern.MatchString(r.shortcut) {
+		return ErrInvalidShortcut
+	}
+	for _, match := range templateVariablePattern.FindAllStringSubmatch(r.body, -1) {
+		if !isTemplateVariable(match[1]) {
+			return ErrUnknownTemplateVariable
+		}
+	}
+	return nil
+}
+
+// Render replaces the template variables of the body with their values
+func (r CannedResponse) Render(values map[string]string) string {
+	return templateVariablePattern.ReplaceAllStringFunc(r.body, func(variable string) string {
+		return values[templateVariablePattern.FindStringSubmatch(variable)[1]]
+	})
+}
+
+func (r CannedResponse) Id() primitive.ObjectID {
+	return r.id
+}
+
+func (r CannedResponse) Shortcut() string {
+	return r.shortcut
+}
+
+func (r CannedResponse) Title() string {
+	return r.title
+}
+
+func (r CannedResponse) Body() string {
+	return r.body
+}
+
+func (r CannedResponse) Tags() []string {
+	return r.tags
+}
+
+func (r CannedResponse) Owner() string {
+	return r.owner
+}
+
+func (r CannedResponse) IsShared() bool {
+	return r.shared
+}
+
+func (r CannedResponse) Macro() Macro {
+	return r.macro
+}
+
+func (r CannedResponse) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+func (r CannedResponse) UpdatedAt() time.Time {
+	return r.updatedAt
+}
+
+func (r CannedResponse) TenantId() string {
+	return r.tenantId
+}
+
+// TemplateValues are the values of the template variables in a channel answered
+// by the rep. Customers without an account are named after the pre-chat form
+func TemplateValues(channel Channel, customer, rep User) map[string]string {
+	var (
+		customerFirstName = customer.FirstName()
+		customerLastName  = customer.LastName()
+	)
+	if customer.Email() == "" {
+		names := strings.Fields(channel.UserFullName())
+		if len(names) > 0 {
+			customerFirstName = names[0]
+			customerLastName = strings.Join(names[1:], " ")
+		}
+	}
+
+	return map[string]string{
+		"customer.firstName": customerFirstName,
+		"customer.lastName":  customerLastName,
+		"customer.fullName":  strings.TrimSpace(customerFirstName + " " + customerLastName),
+		"customer.email":     channel.UserEmail(),
+		"rep.firstName":      rep.FirstName(),
+		"rep.lastName":       rep.LastName(),
+		"rep.fullName":       strings.TrimSpace(rep.FirstName() + " " + rep.LastName()),
+		"rep.email":          rep.Email(),
+	}
+}
+
+// NormalizeShortcut drops the slash reps type before shortcuts
+func NormalizeShortcut(shortcut string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(shortcut), "/"))
+}
+
+func isTemplateVariable(variable string) bool {
+	for _, known := range TemplateVariables {
+		if variable == known {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeMacro(macro Macro) Macro {
+	return Macro{
+		Status:     macro.Status,
+		AddTags:    NormalizeTags(macro.AddTags),
+		RemoveTags: NormalizeTags(macro.RemoveTags),
+	}
+}
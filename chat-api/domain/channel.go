@@ -15,34 +15,143 @@ const (
 	COMPLETE    = "COMPLETE"
 )
 
+// Types of ChannelEvent
+const (
+	ChannelEventMessageEdited  = "edit"
+	ChannelEventMessageDeleted = "delete"
+	ChannelEventRead           = "read"
+	ChannelEventNote           = "note"
+	// ChannelEventSurvey asks the customer to rate a completed channel
+	ChannelEventSurvey = "survey"
+	// ChannelEventBreach tells the reps a channel breached a target of its SLA policy
+	ChannelEventBreach = "breach"
+)
+
+const (
+	ChannelSortCreatedAt     = "createdAt"
+	ChannelSortUpdatedAt     = "updatedAt"
+	ChannelSortLastMessageAt = "lastMessageAt"
+	ChannelSortPriority      = "priority"
+)
+
 var (
-	ChannelNotFound = errors.New("channel not found")
+	ChannelNotFound              = errors.New("channel not found")
+	ErrUnassignedChannelsHaveRep = errors.New("unassigned channels cannot be filtered by rep")
+	ErrInvalidCursor             = errors.New("invalid cursor")
+	ErrChannelTakenByAdmin       = errors.New("only admins can take a channel")
 )
 
 type (
 	ChannelRepository interface {
 		CreateChannel(context.Context, Channel) (Channel, error)
 		GetChannelById(context.Context, string) (Channel, error)
-		GetChannelsByQuery(context.Context, interface{}, int, int) ([]Channel, error)
-		GetChannelsByQueryCount(context.Context, interface{}) (int64, error)
+		GetChannelsByQuery(context.Context, ChannelQuery) ([]Channel, error)
+		GetChannelsByQueryCount(context.Context, ChannelQuery) (int64, error)
 		// GetChannelsByStatus(context.Context, string) ([]Channel, error)
 		UpdateChannelStatus(context.Context, Channel) error
 		AddMessage(context.Context, Channel) error
+		// UpdateMessages stores edited and deleted messages
+		UpdateMessages(context.Context, Channel) error
+		// UpdateReadMarkers stores the read markers of the participants
+		UpdateReadMarkers(context.Context, Channel) error
+		// UpdateChannelDetails stores the tags, the priority and the custom attributes
+		UpdateChannelDetails(context.Context, Channel) error
+		// UpdateRatings stores the satisfaction ratings of the channel
+		UpdateRatings(context.Context, Channel) error
+		// AddSLABreaches adds the breaches just announced to those stored, the
+		// message and status writes keep the times that stopped the SLA clocks
+		AddSLABreaches(context.Context, Channel, []string) error
+		// UpdateSLAClocks stores the times that stopped the SLA clocks of a channel
+		// read before they were tracked
+		UpdateSLAClocks(context.Context, Channel) error
+		// GetSatisfactionRatings reads the ratings of every channel for a report
+		GetSatisfactionRatings(context.Context, SatisfactionQuery) ([]SatisfactionRating, error)
+		// MergeGuestChannel hands the guest channel with the id over to the account
+		// registered with the email it was opened with, other channels are left alone
+		MergeGuestChannel(context.Context, string, string) error
 	}
 
-	StatusHistory struct {
-		Status    string
-		UpdatedBy string
-		Timestamp int64
+	// ChannelQuery filters, sorts and pages a channel listing. Empty filters match every
+	// channel, an empty SortBy orders by creation and a zero Limit returns every match.
+	// Repositories break ties on the id so pages never overlap
+	ChannelQuery struct {
+		UserEmail string
+		RepEmail  string
+		// Statuses matches channels in any of the statuses
+		Statuses []string
+		// Unassigned keeps the channels no rep has claimed yet
+		Unassigned bool
+		// Tags keeps the channels tagged with every tag, Priorities those with
+		// any of the priorities and Attributes those with every attribute value
+		Tags       []string
+		Priorities []string
+		Attributes map[string]string
+		// Reader has the unread messages of the participant counted in the
+		// UnreadCount of every channel listed, internal notes only with Internal
+		Reader   string
+		Internal bool
+
+		// Time ranges include From and exclude To, a zero bound leaves the range open
+		CreatedFrom time.Time
+		CreatedTo   time.Time
+		UpdatedFrom time.Time
+		UpdatedTo   time.Time
+
+		SortBy         string
+		SortDescending bool
+
+		// After keeps the channels that sort after the cursor position, it
+		// replaces Skip for listings paged with cursors
+		After *ChannelCursor
+		Skip  int
+		Limit int
+	}
+
+	// ChannelCursor is a position in a sorted channel listing, the value of the
+	// sort key and the id of a channel. It is only valid for the sort it was
+	// made for, Backward cursors page towards the start of the listing.
+	// Listings sorted by priority keep the priority rank in Rank instead of Value
+	ChannelCursor struct {
+		SortBy         string
+		SortDescending bool
+		Backward       bool
+		Value          time.Time
+		Rank           int
+		Id             primitive.ObjectID
+	}
+
+	// ChannelCursorCodec turns cursors into opaque tokens clients cannot forge
+	ChannelCursorCodec interface {
+		Encode(ChannelCursor) (string, error)
+		Decode(string) (ChannelCursor, error)
 	}
 
-	Message struct {
+	// ChannelNotifier pushes events to the participants connected to a channel.
+	// Pushing is best effort, participants reading the channel later see the change anyway
+	ChannelNotifier interface {
+		Notify(context.Context, ChannelEvent)
+	}
+
+	// ChannelEvent about an internal note is Internal, it only reaches reps. Other
+	// events reach the reps and UserEmail, the customer of the channel
+	ChannelEvent struct {
+		Type        string
+		ChannelId   string
+		UserEmail   string
+		MessageId   string
 		MessageFrom string
 		Message     string
-		IsDeleted   bool
+		Attachments []MessageAttachment
+		Internal    bool
 		Timestamp   time.Time
 	}
 
+	StatusHistory struct {
+		Status    string
+		UpdatedBy string
+		Timestamp int64
+	}
+
 	Channel struct {
 		id            primitive.ObjectID
 		userFullName  string
@@ -51,8 +160,23 @@ type (
 		currentStatus string
 		statusHistory []StatusHistory
 		messages      []Message
-		createdAt     time.Time
-		updatedAt     time.Time
+		readMarkers   []ReadMarker
+		unreadCount   int
+		tags          []string
+		priority      string
+		attributes    map[string]string
+		ratings       []SatisfactionRating
+		// firstResponseAt and resolvedAt stop the SLA clocks, sla is the outcome of
+		// the latest TrackSLA and slaBreaches the breaches announced
+		firstResponseAt time.Time
+		resolvedAt      time.Time
+		sla             SLA
+		slaBreaches     []string
+		guest           bool
+		tenantId        string
+		lastMessageAt   time.Time
+		createdAt       time.Time
+		updatedAt       time.Time
 	}
 )
 
@@ -74,14 +198,40 @@ func (c *Channel) UpdateUserFullName(fullname string) {
 	c.userFullName = fullname
 }
 
-func (c *Channel) AddMessage(messageFrom, message string, timestamp time.Time) {
-	c.messages = append(c.messages, Message{
+// MarkGuest flags a channel opened from the pre-chat form without an account
+func (c *Channel) MarkGuest() {
+	c.guest = true
+}
+
+func (c *Channel) AssignTenant(tenantId string) {
+	c.tenantId = tenantId
+}
+
+func (c *Channel) AddMessage(messageFrom, message string, timestamp time.Time, attachments ...MessageAttachment) {
+	c.RestoreMessage(Message{
+		Id:          primitive.NewObjectID(),
 		MessageFrom: messageFrom,
 		Message:     message,
 		Timestamp:   timestamp,
+		Attachments: attachments,
 	})
 }
 
+// RestoreMessage adds a stored message as it is, repositories read channels with it
+func (c *Channel) RestoreMessage(message Message) {
+	c.messages = append(c.messages, message)
+	c.UpdateLastMessageAt(message.Timestamp)
+	c.recordResponse(message)
+}
+
+// UpdateLastMessageAt moves the time of the latest message forward, listings
+// restore it without loading the messages
+func (c *Channel) UpdateLastMessageAt(timestamp time.Time) {
+	if timestamp.After(c.lastMessageAt) {
+		c.lastMessageAt = timestamp
+	}
+}
+
 func (c *Channel) UpdateStatus(status, updatedBy string, timestamp int64) {
 	c.currentStatus = status
 	c.statusHistory = append(c.statusHistory, StatusHistory{
@@ -89,6 +239,7 @@ func (c *Channel) UpdateStatus(status, updatedBy string, timestamp int64) {
 		UpdatedBy: updatedBy,
 		Status:    status,
 	})
+	c.recordStatus(status, timestamp)
 }
 
 func (c Channel) StatusHistory() []StatusHistory {
@@ -99,6 +250,14 @@ func (c Channel) UserFullName() string {
 	return c.userFullName
 }
 
+func (c Channel) IsGuest() bool {
+	return c.guest
+}
+
+func (c Channel) TenantId() string {
+	return c.tenantId
+}
+
 func (c Channel) UserEmail() string {
 	return c.userEmail
 }
@@ -126,3 +285,19 @@ func (c Channel) CreatedAt() time.Time {
 func (c Channel) UpdatedAt() time.Time {
 	return c.updatedAt
 }
+
+func (c Channel) LastMessageAt() time.Time {
+	return c.lastMessageAt
+}
+
+// SortValue is the value of the channel for a sort key of ChannelQuery
+func (c Channel) SortValue(sortBy string) time.Time {
+	switch sortBy {
+	case ChannelSortUpdatedAt:
+		return c.updatedAt
+	case ChannelSortLastMessageAt:
+		return c.lastMessageAt
+	default:
+		return c.createdAt
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+const (
+	// ScopeGuest restricts a token to the single channel opened by a guest
+	ScopeGuest = "guest"
+)
+
+var (
+	ErrGuestSessionInvalid = errors.New("guest session is no longer valid")
+)
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrAccountLocked        = errors.New("account temporarily locked due to too many failed login attempts")
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts, try again later")
+)
+
+type (
+	LoginAttemptRepository interface {
+		GetLoginAttempt(context.Context, string) (LoginAttempt, error)
+		SaveLoginAttempt(context.Context, LoginAttempt) error
+		// AddLoginFailure counts a failure in one write, concurrent attempts each
+		// get back the count their own failure reached. The count starts over when
+		// the last failure is older than the given time or a lockout has expired
+		AddLoginFailure(context.Context, string, time.Time, time.Time) (LoginAttempt, error)
+		// LockLoginAttempt locks the subject until the given time unless it is
+		// locked at now already, reporting whether this call locked it
+		LockLoginAttempt(context.Context, string, time.Time, time.Time) (bool, error)
+	}
+
+	// LoginThrottlePolicy describes how failed logins are slowed down and when
+	// the subject of the attempts (an account or an IP) gets locked out.
+	// Failures are forgotten once FailureWindow passes without a new one.
+	LoginThrottlePolicy struct {
+		FreeAttempts     int
+		BaseDelay        time.Duration
+		MaxDelay         time.Duration
+		LockoutThreshold int
+		LockoutDuration  time.Duration
+		FailureWindow    time.Duration
+	}
+
+	LoginAttempt struct {
+		key           string
+		failures      int
+		lastFailureAt time.Time
+		lockedUntil   time.Time
+	}
+)
+
+func DefaultAccountThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Minute,
+		LockoutThreshold: 10,
+		LockoutDuration:  30 * time.Minute,
+		FailureWindow:    time.Hour,
+	}
+}
+
+func DefaultIPThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		FreeAttempts:     10,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Minute,
+		LockoutThreshold: 50,
+		LockoutDuration:  time.Hour,
+		FailureWindow:    time.Hour,
+	}
+}
+
+// Delay returns the backoff a subject has to wait after its n-th consecutive failure
+func (p LoginThrottlePolicy) Delay(failures int) time.Duration {
+	if failures < p.FreeAttempts {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts; i < failures; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+func AccountAttemptKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func IPAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+func NewLoginAttempt(key string, failures int, lastFailureAt, lockedUntil time.Time) LoginAttempt {
+	return LoginAttempt{
+		key:           key,
+		failures:      failures,
+		lastFailureAt: lastFailureAt,
+		lockedUntil:   lockedUntil,
+	}
+}
+
+// FailuresSince is when the oldest failure still counted at now may have happened
+func (p LoginThrottlePolicy) FailuresSince(now time.Time) time.Time {
+	return now.Add(-p.FailureWindow)
+}
+
+// ShouldLock reports whether the failures counted on an attempt call for a lockout
+func (p LoginThrottlePolicy) ShouldLock(attempt LoginAttempt, now time.Time) bool {
+	return p.LockoutThreshold > 0 && attempt.failures >= p.LockoutThreshold && !attempt.IsLocked(now)
+}
+
+func (l *LoginAttempt) Reset() {
+	l.failures = 0
+	l.lastFailureAt = time.Time{}
+	l.lockedUntil = time.Time{}
+}
+
+func (l LoginAttempt) IsLocked(now time.Time) bool {
+	return now.Before(l.lockedUntil)
+}
+
+// RetryAt returns the earliest time another attempt is accepted
+func (l LoginAttempt) RetryAt(policy LoginThrottlePolicy) time.Time {
+	retryAt := l.lastFailureAt.Add(policy.Delay(l.failures))
+	if l.lockedUntil.After(retryAt) {
+		return l.lockedUntil
+	}
+	return retryAt
+}
+
+func (l LoginAttempt) Key() string {
+	return l.key
+}
+
+func (l LoginAttempt) Failures() int {
+	return l.failures
+}
+
+func (l LoginAttempt) LastFailureAt() time.Time {
+	return l.lastFailureAt
+}
+
+func (l LoginAttempt) LockedUntil() time.Time {
+	return l.lockedUntil
+}
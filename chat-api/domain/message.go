@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MessageEditWindow is how long authors can edit or delete a message after sending it
+const MessageEditWindow = 15 * time.Minute
+
+var (
+	ErrMessageNotFound         = errors.New("message not found")
+	ErrMessageDeleted          = errors.New("message was deleted")
+	ErrNotMessageAuthor        = errors.New("only the author can change the message")
+	ErrMessageEditWindowClosed = errors.New("message can no longer be changed")
+)
+
+type (
+	// MessageEdit is a text a message held before it was edited or deleted
+	MessageEdit struct {
+		Message  string
+		EditedAt time.Time
+	}
+
+	Message struct {
+		// Id is zero for messages stored before messages had ids, they cannot be changed
+		Id          primitive.ObjectID
+		MessageFrom string
+		Message     string
+		IsDeleted   bool
+		Timestamp   time.Time
+		EditedAt    time.Time
+		DeletedAt   time.Time
+		// Edits keeps the previous texts oldest first, a deleted message keeps its last text here
+		Edits       []MessageEdit
+		Attachments []MessageAttachment
+		// Internal notes are left by reps for each other, customers never see them
+		Internal bool
+	}
+)
+
+// SeesInternalNotes tells whether principals of the role can read internal notes, only reps can
+func SeesInternalNotes(role string) bool {
+	return role == ADMIN
+}
+
+// CanBeChangedBy checks that the editor may edit or delete the message at the
+// given time. Authors can within MessageEditWindow of sending it, admins always can
+func (m Message) CanBeChangedBy(editor string, admin bool, at time.Time) error {
+	if admin {
+		return nil
+	}
+	if NormalizeEmail(editor) != NormalizeEmail(m.MessageFrom) {
+		return ErrNotMessageAuthor
+	}
+	if at.Sub(m.Timestamp) > MessageEditWindow {
+		return ErrMessageEditWindowClosed
+	}
+	return nil
+}
+
+// AddNote adds an internal note to the channel
+func (c *Channel) AddNote(noteFrom, note string, timestamp time.Time, attachments ...MessageAttachment) {
+	c.RestoreMessage(Message{
+		Id:          primitive.NewObjectID(),
+		MessageFrom: noteFrom,
+		Message:     note,
+		Timestamp:   timestamp,
+		Attachments: attachments,
+		Internal:    true,
+	})
+}
+
+// WithoutInternalNotes is the channel as its customer sees it
+func (c Channel) WithoutInternalNotes() Channel {
+	messages := make([]Message, 0, len(c.messages))
+	for _, message := range c.messages {
+		if !message.Internal {
+			messages = append(messages, message)
+		}
+	}
+	c.messages = messages
+	return c
+}
+
+// Message finds a message of the channel by its id
+func (c Channel) Message(id primitive.ObjectID) (Message, error) {
+	if i := c.messageIndex(id); i >= 0 {
+		return c.messages[i], nil
+	}
+	return Message{}, ErrMessageNotFound
+}
+
+// EditMessage replaces the text of a message, the previous one is kept in its edits
+func (c *Channel) EditMessage(id primitive.ObjectID, text string, editedAt time.Time) (Message, error) {
+	i := c.messageIndex(id)
+	if i < 0 {
+		return Message{}, ErrMessageNotFound
+	}
+
+	message := c.messages[i]
+	if message.IsDeleted {
+		return Message{}, ErrMessageDeleted
+	}
+	message.Edits = append(append([]MessageEdit{}, message.Edits...), MessageEdit{
+		Message:  message.Message,
+		EditedAt: editedAt,
+	})
+	message.Message = text
+	message.EditedAt = editedAt
+
+	c.messages[i] = message
+	return message, nil
+}
+
+// DeleteMessage leaves a tombstone in place of the message. Its text moves to
+// its edits so it is kept for audit but no longer shown or searched
+func (c *Channel) DeleteMessage(id primitive.ObjectID, deletedAt time.Time) (Message, error) {
+	i := c.messageIndex(id)
+	if i < 0 {
+		return Message{}, ErrMessageNotFound
+	}
+
+	message := c.messages[i]
+	if message.IsDeleted {
+		return Message{}, ErrMessageDeleted
+	}
+	message.Edits = append(append([]MessageEdit{}, message.Edits...), MessageEdit{
+		Message:  message.Message,
+		EditedAt: deletedAt,
+	})
+	message.Message = ""
+	message.IsDeleted = true
+	message.DeletedAt = deletedAt
+
+	c.messages[i] = message
+	return message, nil
+}
+
+func (c Channel) messageIndex(id primitive.ObjectID) int {
+	if id.IsZero() {
+		return -1
+	}
+	for i, message := range c.messages {
+		if message.Id == id {
+			return i
+		}
+	}
+	return -1
+}
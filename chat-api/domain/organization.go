@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTenantID owns all data created before organizations existed and
+	// every request that does not resolve to another organization
+	DefaultTenantID = "default"
+
+	// RoutingManual leaves new channels in the queue until a rep picks them up
+	RoutingManual = "MANUAL"
+	// RoutingLeastBusy assigns new channels to the admin with the fewest channels in progress
+	RoutingLeastBusy = "LEAST_BUSY"
+)
+
+var (
+	ErrOrganizationNotFound      = errors.New("organization not found")
+	ErrOrganizationAlreadyExists = errors.New("organization already exists")
+	ErrTenantMismatch            = errors.New("credentials belong to another organization")
+	ErrTenantForbidden           = errors.New("only the default organization can manage organizations")
+	ErrInvalidBusinessHours      = errors.New("invalid business hours")
+)
+
+type tenantContextKey struct{}
+
+type (
+	OrganizationRepository interface {
+		CreateOrganization(context.Context, Organization) (Organization, error)
+		GetOrganizationById(context.Context, string) (Organization, error)
+		// GetOrganizationByDomain resolves the organization serving a host, it is not tenant scoped
+		GetOrganizationByDomain(context.Context, string) (Organization, error)
+		// GetOrganizations lists every stored organization, it is not tenant scoped
+		GetOrganizations(context.Context) ([]Organization, error)
+		UpdateOrganizationSettings(context.Context, Organization) error
+	}
+
+	// BusinessDay opens a weekday from Open to Close, both written as HH:MM
+	BusinessDay struct {
+		Weekday time.Weekday
+		Open    string
+		Close   string
+	}
+
+	// BusinessHours without any day configured means the organization is always open
+	BusinessHours struct {
+		TimeZone string
+		Days     []BusinessDay
+	}
+
+	// OrganizationSettings keep the SLA policies the organization configured,
+	// the other priorities follow DefaultSLAPolicies
+	OrganizationSettings struct {
+		BusinessHours   BusinessHours
+		RoutingStrategy string
+		SLAPolicies     []SLAPolicy
+	}
+
+	Organization struct {
+		id        string
+		name      string
+		domains   []string
+		settings  OrganizationSettings
+		createdAt time.Time
+		updatedAt time.Time
+	}
+)
+
+func NewOrganization(id, name string, domains []string, createdAt, updatedAt time.Time) Organization {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		normalized = append(normalized, NormalizeHost(domain))
+	}
+
+	return Organization{
+		id:        id,
+		name:      name,
+		domains:   normalized,
+		settings:  OrganizationSettings{RoutingStrategy: RoutingManual},
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+// DefaultOrganization stands in for the default tenant until its settings are saved
+func DefaultOrganization() Organization {
+	return NewOrganization(DefaultTenantID, DefaultTenantID, nil, time.Time{}, time.Time{})
+}
+
+// NormalizeHost drops the port and case of a Host header so it can be matched against organization domains
+func NormalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.LastIndex(host, ":"); i != -1 && !strings.HasSuffix(host, "]") {
+		host = host[:i]
+	}
+	return host
+}
+
+// WithTenant scopes everything done with the context to one organization
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the organization a request was resolved to
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(tenantContextKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenantID
+}
+
+func (o *Organization) UpdateSettings(settings OrganizationSettings, updatedAt time.Time) {
+	o.settings = settings
+	o.updatedAt = updatedAt
+}
+
+func (o Organization) Id() string {
+	return o.id
+}
+
+func (o Organization) Name() string {
+	return o.name
+}
+
+func (o Organization) Domains() []string {
+	return o.domains
+}
+
+func (o Organization) Settings() OrganizationSettings {
+	return o.settings
+}
+
+func (o Organization) CreatedAt() time.Time {
+	return o.createdAt
+}
+
+func (o Organization) UpdatedAt() time.Time {
+	return o.updatedAt
+}
+
+// Validate checks the time zone exists and every day opens before it closes
+func (b BusinessHours) Validate() error {
+	if _, err := time.LoadLocation(b.TimeZone); err != nil {
+		return ErrInvalidBusinessHours
+	}
+	for _, day := range b.Days {
+		open, okOpen := minuteOfDay(day.Open)
+		close, okClose := minuteOfDay(day.Close)
+		if !okOpen || !okClose || open >= close || day.Weekday < time.Sunday || day.Weekday > time.Saturday {
+			return ErrInvalidBusinessHours
+		}
+	}
+	return nil
+}
+
+// IsOpen reports whether t falls within the business hours, in their own time zone
+func (b BusinessHours) IsOpen(t time.Time) bool {
+	if len(b.Days) == 0 {
+		return true
+	}
+
+	location, err := time.LoadLocation(b.TimeZone)
+	if err != nil {
+		return true
+	}
+	local := t.In(location)
+	now := local.Hour()*60 + local.Minute()
+
+	for _, day := range b.Days {
+		if day.Weekday != local.Weekday() {
+			continue
+		}
+		open, _ := minuteOfDay(day.Open)
+		close, _ := minuteOfDay(day.Close)
+		if now >= open && now < close {
+			return true
+		}
+	}
+	return false
+}
+
+func minuteOfDay(clock string) (int, bool) {
+	parts := strings.Split(clock, ":")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, false
+	}
+	hour, errHour := strconv.Atoi(parts[0])
+	minute, errMinute := strconv.Atoi(parts[1])
+	if errHour != nil || errMinute != nil || hour < 0 || hour > 24 || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
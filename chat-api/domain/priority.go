@@ -0,0 +1,65 @@
+package domain
+
+import "errors"
+
+const (
+	PriorityLow    = "LOW"
+	PriorityNormal = "NORMAL"
+	PriorityHigh   = "HIGH"
+	PriorityUrgent = "URGENT"
+)
+
+var ErrInvalidPriority = errors.New("priority must be LOW, NORMAL, HIGH or URGENT")
+
+// priorityRanks order the priorities, repositories store the rank so channels sort by urgency
+var priorityRanks = map[string]int{
+	PriorityLow:    1,
+	PriorityNormal: 2,
+	PriorityHigh:   3,
+	PriorityUrgent: 4,
+}
+
+// PriorityRank is the stored form of a priority, unknown priorities rank as NORMAL
+func PriorityRank(priority string) int {
+	if rank, ok := priorityRanks[priority]; ok {
+		return rank
+	}
+	return priorityRanks[PriorityNormal]
+}
+
+// PriorityFromRank reads a stored priority back
+func PriorityFromRank(rank int) string {
+	for priority, r := range priorityRanks {
+		if r == rank {
+			return priority
+		}
+	}
+	return PriorityNormal
+}
+
+// IsPriority tells whether the priority is one of the known levels
+func IsPriority(priority string) bool {
+	_, ok := priorityRanks[priority]
+	return ok
+}
+
+// UpdatePriority sets how urgent the channel is
+func (c *Channel) UpdatePriority(priority string) error {
+	if !IsPriority(priority) {
+		return ErrInvalidPriority
+	}
+	c.priority = priority
+	return nil
+}
+
+// Priority of the channel, channels nobody prioritized are NORMAL
+func (c Channel) Priority() string {
+	if c.priority == "" {
+		return PriorityNormal
+	}
+	return c.priority
+}
+
+func (c Channel) PriorityRank() int {
+	return PriorityRank(c.Priority())
+}
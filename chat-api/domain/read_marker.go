@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// ReadMarker is how far a participant has read a channel, every message sent
+// up to LastReadAt counts as read by them
+type ReadMarker struct {
+	Participant string
+	LastReadAt  time.Time
+}
+
+// MarkRead moves the marker of the participant forward to the given time,
+// markers never move back. It returns the marker after the change and whether it moved
+func (c *Channel) MarkRead(participant string, at time.Time) (ReadMarker, bool) {
+	participant = NormalizeEmail(participant)
+	for i, marker := range c.readMarkers {
+		if marker.Participant != participant {
+			continue
+		}
+		if !at.After(marker.LastReadAt) {
+			return marker, false
+		}
+		c.readMarkers[i].LastReadAt = at
+		return c.readMarkers[i], true
+	}
+
+	marker := ReadMarker{Participant: participant, LastReadAt: at}
+	c.readMarkers = append(c.readMarkers, marker)
+	return marker, true
+}
+
+func (c Channel) ReadMarkers() []ReadMarker {
+	return c.readMarkers
+}
+
+// LastReadAt is the marker of the participant, zero when they never read the channel
+func (c Channel) LastReadAt(participant string) time.Time {
+	participant = NormalizeEmail(participant)
+	for _, marker := range c.readMarkers {
+		if marker.Participant == participant {
+			return marker.LastReadAt
+		}
+	}
+	return time.Time{}
+}
+
+// CountUnread counts the messages the participant has not read yet
+func (c Channel) CountUnread(participant string) int {
+	var (
+		lastReadAt = c.LastReadAt(participant)
+		count      int
+	)
+	for _, message := range c.messages {
+		if message.UnreadBy(participant, lastReadAt) {
+			count++
+		}
+	}
+	return count
+}
+
+// UpdateUnreadCount keeps the unread count listings compute for ChannelQuery.Reader
+func (c *Channel) UpdateUnreadCount(count int) {
+	c.unreadCount = count
+}
+
+func (c Channel) UnreadCount() int {
+	return c.unreadCount
+}
+
+// UnreadBy tells whether the message is unread by the participant, it was sent
+// by someone else after their marker and was not deleted since
+func (m Message) UnreadBy(participant string, lastReadAt time.Time) bool {
+	return !m.IsDeleted &&
+		m.Timestamp.After(lastReadAt) &&
+		NormalizeEmail(m.MessageFrom) != NormalizeEmail(participant)
+}
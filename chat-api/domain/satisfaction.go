@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MinSatisfactionScore = 1
+	MaxSatisfactionScore = 5
+	// satisfiedScore is the lowest score counted as satisfied in the CSAT
+	satisfiedScore = 4
+
+	MaxSatisfactionComment = 1000
+	// MaxSatisfactionReportDays bounds the range a report reads ratings from
+	MaxSatisfactionReportDays = 366
+)
+
+// Groupings of a satisfaction report
+const (
+	SatisfactionByRep   = "rep"
+	SatisfactionByTag   = "tag"
+	SatisfactionByDay   = "day"
+	SatisfactionByWeek  = "week"
+	SatisfactionByMonth = "month"
+)
+
+var (
+	ErrInvalidRating      = errors.New("ratings go from 1 to 5 with a comment of up to 1000 characters")
+	ErrChannelNotComplete = errors.New("only completed channels can be rated")
+	ErrAlreadyRated       = errors.New("the channel was already rated since it was completed")
+	ErrReportRangeTooLong = errors.New("reports cover up to 366 days")
+	ErrUnknownReportGroup = errors.New("reports are grouped by rep, tag, day, week or month")
+)
+
+type (
+	// SatisfactionRating is the answer of the customer to the survey sent when the
+	// channel was completed. Channels take one rating per completion, Completion
+	// counts the completions up to the rated one
+	SatisfactionRating struct {
+		ChannelId  primitive.ObjectID
+		Completion int
+		Score      int
+		Comment    string
+		// RepEmail is the rep who had the channel when it was rated
+		RepEmail string
+		RatedBy  string
+		RatedAt  time.Time
+		// Tags are the tags of the channel when the ratings are read for a report
+		Tags []string
+	}
+
+	// SatisfactionQuery selects the ratings given from the inclusive From to the
+	// exclusive To, an empty RepEmail or Tag matches every rating
+	SatisfactionQuery struct {
+		From     time.Time
+		To       time.Time
+		RepEmail string
+		Tag      string
+	}
+
+	// SatisfactionGroup sums up the ratings of a report group, Scores counts the
+	// ratings of each score from 1 to 5
+	SatisfactionGroup struct {
+		Key       string
+		Responses int
+		Scores    [MaxSatisfactionScore]int
+	}
+)
+
+// Completions counts the times the channel was completed
+func (c Channel) Completions() int {
+	var completions int
+	for _, status := range c.statusHistory {
+		if status.Status == COMPLETE {
+			completions++
+		}
+	}
+	return completions
+}
+
+// SurveyPending tells whether the customer can still rate the latest completion
+func (c Channel) SurveyPending() bool {
+	if c.currentStatus != COMPLETE {
+		return false
+	}
+	_, rated := c.rating(c.Completions())
+	return !rated
+}
+
+// Rate stores the answer of the customer to the survey of the latest completion
+func (c *Channel) Rate(score int, comment, ratedBy string, at time.Time) (SatisfactionRating, error) {
+	comment = strings.TrimSpace(comment)
+	if score < MinSatisfactionScore || score > MaxSatisfactionScore || len(comment) > MaxSatisfactionComment {
+		return SatisfactionRating{}, ErrInvalidRating
+	}
+	if c.currentStatus != COMPLETE {
+		return SatisfactionRating{}, ErrChannelNotComplete
+	}
+	if !c.SurveyPending() {
+		return SatisfactionRating{}, ErrAlreadyRated
+	}
+
+	rating := SatisfactionRating{
+		ChannelId:  c.id,
+		Completion: c.Completions(),
+		Score:      score,
+		Comment:    comment,
+		RepEmail:   NormalizeEmail(c.repEmail),
+		RatedBy:    NormalizeEmail(ratedBy),
+		RatedAt:    at,
+	}
+	c.RestoreRating(rating)
+	return rating, nil
+}
+
+// RestoreRating adds a stored rating as it is, repositories read channels with it
+func (c *Channel) RestoreRating(rating SatisfactionRating) {
+	rating.ChannelId = c.id
+	c.ratings = append(c.ratings, rating)
+}
+
+func (c Channel) Ratings() []SatisfactionRating {
+	return c.ratings
+}
+
+func (c Channel) rating(completion int) (SatisfactionRating, bool) {
+	for _, rating := range c.ratings {
+		if rating.Completion == completion {
+			return rating, true
+		}
+	}
+	return SatisfactionRating{}, false
+}
+
+// IsSatisfactionGroup tells whether reports can be grouped that way
+func IsSatisfactionGroup(groupBy string) bool {
+	switch groupBy {
+	case SatisfactionByRep, SatisfactionByTag, SatisfactionByDay, SatisfactionByWeek, SatisfactionByMonth:
+		return true
+	}
+	return false
+}
+
+// SatisfactionReport groups the ratings and sorts the groups by key. Periods are
+// keyed by their first day in UTC, weeks start on Monday. A rating counts once for
+// each tag of its channel and untagged or unassigned ratings fall in the "" group
+func SatisfactionReport(ratings []SatisfactionRating, groupBy string) ([]SatisfactionGroup, error) {
+	if !IsSatisfactionGroup(groupBy) {
+		return nil, ErrUnknownReportGroup
+	}
+
+	groups := make(map[string]*SatisfactionGroup)
+	for _, rating := range ratings {
+		for _, key := range satisfactionKeys(rating, groupBy) {
+			group, ok := groups[key]
+			if !ok {
+				group = &SatisfactionGroup{Key: key}
+				groups[key] = group
+			}
+			group.Add(rating)
+		}
+	}
+
+	report := make([]SatisfactionGroup, 0, len(groups))
+	for _, group := range groups {
+		report = append(report, *group)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Key < report[j].Key })
+	return report, nil
+}
+
+func satisfactionKeys(rating SatisfactionRating, groupBy string) []string {
+	ratedAt := rating.RatedAt.UTC()
+	switch groupBy {
+	case SatisfactionByRep:
+		return []string{rating.RepEmail}
+	case SatisfactionByTag:
+		if len(rating.Tags) == 0 {
+			return []string{""}
+		}
+		return rating.Tags
+	case SatisfactionByWeek:
+		// Sunday is the last day of the week
+		offset := (int(ratedAt.Weekday()) + 6) % 7
+		return []string{ratedAt.AddDate(0, 0, -offset).Format("2006-01-02")}
+	case SatisfactionByMonth:
+		return []string{ratedAt.Format("2006-01")}
+	default:
+		return []string{ratedAt.Format("2006-01-02")}
+	}
+}
+
+// Add counts the rating in the group, scores out of range are left out
+func (g *SatisfactionGroup) Add(rating SatisfactionRating) {
+	if rating.Score < MinSatisfactionScore || rating.Score > MaxSatisfactionScore {
+		return
+	}
+	g.Responses++
+	g.Scores[rating.Score-1]++
+}
+
+// Average score of the group, zero without ratings
+func (g SatisfactionGroup) Average() float64 {
+	if g.Responses == 0 {
+		return 0
+	}
+	var total int
+	for i, count := range g.Scores {
+		total += (i + 1) * count
+	}
+	return float64(total) / float64(g.Responses)
+}
+
+// CSAT is the percentage of the ratings scoring 4 or 5
+func (g SatisfactionGroup) CSAT() float64 {
+	if g.Responses == 0 {
+		return 0
+	}
+	var satisfied int
+	for score := satisfiedScore; score <= MaxSatisfactionScore; score++ {
+		satisfied += g.Scores[score-1]
+	}
+	return 100 * float64(satisfied) / float64(g.Responses)
+}
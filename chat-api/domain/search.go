@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrEmptySearch = errors.New("search text has no words")
+
+type (
+	// SearchIndex finds channels by the text of their messages and by the name
+	// and email of their customer. Writers hand every channel they store to
+	// IndexChannel, indexes backed by the channel collection itself ignore it
+	SearchIndex interface {
+		IndexChannel(context.Context, Channel)
+		SearchChannels(context.Context, SearchQuery) ([]SearchHit, int64, error)
+	}
+
+	// SearchQuery matches the channels holding every term of Text, terms match
+	// the start of words. Results come most recent activity first
+	SearchQuery struct {
+		Text     string
+		RepEmail string
+		Statuses []string
+
+		// The range includes From and excludes To, a zero bound leaves it open
+		CreatedFrom time.Time
+		CreatedTo   time.Time
+
+		Skip  int
+		Limit int
+	}
+
+	// SearchHit is a channel found by a search, without its messages, with the
+	// passages that matched
+	SearchHit struct {
+		Channel  Channel
+		Snippets []SearchSnippet
+	}
+
+	// SearchSnippet is a passage of a channel field around the matched terms
+	SearchSnippet struct {
+		Field      string
+		Text       string
+		Highlights []SearchHighlight
+	}
+
+	// SearchHighlight locates a matched term in a snippet text, in characters
+	// from the start of the text, End excluded
+	SearchHighlight struct {
+		Start int
+		End   int
+	}
+)
+
+const (
+	SearchFieldMessage      = "message"
+	SearchFieldUserFullName = "userFullName"
+	SearchFieldUserEmail    = "userEmail"
+)
+
+// SearchTerms lowercases a text and splits it into its words, dropping
+// repeated words. Anything but letters and digits separates words
+func SearchTerms(text string) []string {
+	var (
+		terms []string
+		seen  = map[string]bool{}
+	)
+	for _, term := range strings.FieldsFunc(strings.ToLower(text), isSearchSeparator) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+func isSearchSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
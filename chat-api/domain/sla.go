@@ -0,0 +1,272 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Targets of an SLA policy
+const (
+	SLAFirstResponse = "firstResponse"
+	SLAResolution    = "resolution"
+)
+
+// Statuses of an SLA target, a target is MET or BREACHED once its clock stops
+const (
+	SLAOnTrack     = "ON_TRACK"
+	SLAApproaching = "APPROACHING"
+	SLABreached    = "BREACHED"
+	SLAMet         = "MET"
+)
+
+// slaApproachingShare is the share of a target after which a running clock is approaching it
+const slaApproachingShare = 0.8
+
+var ErrInvalidSLAPolicy = errors.New("SLA policies need a known priority, once each, and targets of zero or more")
+
+type (
+	// SLAPolicy sets how fast channels of a priority are answered and resolved,
+	// counted from the creation of the channel. A zero target is not tracked
+	SLAPolicy struct {
+		Priority      string
+		FirstResponse time.Duration
+		Resolution    time.Duration
+	}
+
+	// SLATarget is how a channel fares against one target of its policy, Target
+	// is zero when the policy leaves it out. StoppedAt is zero while the clock runs
+	SLATarget struct {
+		Target    time.Duration
+		DueAt     time.Time
+		StoppedAt time.Time
+		Status    string
+	}
+
+	// SLA is how a channel fares against the policy of its priority
+	SLA struct {
+		FirstResponse SLATarget
+		Resolution    SLATarget
+	}
+)
+
+// DefaultSLAPolicies apply to the priorities an organization did not configure
+func DefaultSLAPolicies() []SLAPolicy {
+	return []SLAPolicy{
+		{Priority: PriorityUrgent, FirstResponse: 15 * time.Minute, Resolution: 4 * time.Hour},
+		{Priority: PriorityHigh, FirstResponse: time.Hour, Resolution: 8 * time.Hour},
+		{Priority: PriorityNormal, FirstResponse: 4 * time.Hour, Resolution: 24 * time.Hour},
+		{Priority: PriorityLow, FirstResponse: 8 * time.Hour, Resolution: 72 * time.Hour},
+	}
+}
+
+// OpenStatuses are the statuses of the channels still waiting on a rep, their SLA clocks may run
+func OpenStatuses() []string {
+	return []string{INACTIVE, ACTIVE, IN_PROGRESS}
+}
+
+// ValidateSLAPolicies checks every policy names a known priority no other policy names
+func ValidateSLAPolicies(policies []SLAPolicy) error {
+	seen := make(map[string]bool, len(policies))
+	for _, policy := range policies {
+		if !IsPriority(policy.Priority) || seen[policy.Priority] || policy.FirstResponse < 0 || policy.Resolution < 0 {
+			return ErrInvalidSLAPolicy
+		}
+		seen[policy.Priority] = true
+	}
+	return nil
+}
+
+// MergeSLAPolicies replaces the policies of the priorities the changes name and keeps the others
+func MergeSLAPolicies(policies, changes []SLAPolicy) []SLAPolicy {
+	if len(changes) == 0 {
+		return policies
+	}
+	merged := make([]SLAPolicy, 0, len(policies)+len(changes))
+	for _, policy := range policies {
+		if _, changed := slaPolicy(changes, policy.Priority); !changed {
+			merged = append(merged, policy)
+		}
+	}
+	return append(merged, changes...)
+}
+
+// SLAPolicy of a priority, the default one when the organization did not configure it
+func (s OrganizationSettings) SLAPolicy(priority string) SLAPolicy {
+	if policy, ok := slaPolicy(s.SLAPolicies, priority); ok {
+		return policy
+	}
+	policy, _ := slaPolicy(DefaultSLAPolicies(), priority)
+	return policy
+}
+
+func slaPolicy(policies []SLAPolicy, priority string) (SLAPolicy, bool) {
+	for _, policy := range policies {
+		if policy.Priority == priority {
+			return policy, true
+		}
+	}
+	return SLAPolicy{}, false
+}
+
+// recordResponse stops the first response clock at the first message a rep sent,
+// internal notes are not answers to the customer
+func (c *Channel) recordResponse(message Message) {
+	if message.Internal || NormalizeEmail(message.MessageFrom) == NormalizeEmail(c.userEmail) {
+		return
+	}
+	if c.firstResponseAt.IsZero() || message.Timestamp.Before(c.firstResponseAt) {
+		c.firstResponseAt = message.Timestamp
+	}
+}
+
+// recordStatus stops the resolution clock when the channel is completed and
+// starts it again when the channel is reopened
+func (c *Channel) recordStatus(status string, timestamp int64) {
+	if status == COMPLETE {
+		c.resolvedAt = time.Unix(timestamp, 0)
+		return
+	}
+	c.resolvedAt = time.Time{}
+}
+
+// RestoreSLA sets what the history of the channel tells, listings restore it
+// without loading the history. Breaches are the targets announced as breached
+func (c *Channel) RestoreSLA(firstResponseAt, resolvedAt time.Time, breaches []string) {
+	if !firstResponseAt.IsZero() {
+		c.firstResponseAt = firstResponseAt
+	}
+	if !resolvedAt.IsZero() {
+		c.resolvedAt = resolvedAt
+	}
+	c.slaBreaches = append([]string{}, breaches...)
+}
+
+// FirstResponseAt is when a rep first answered, zero until then
+func (c Channel) FirstResponseAt() time.Time {
+	return c.firstResponseAt
+}
+
+// ResolvedAt is when the channel was completed, zero while it is open
+func (c Channel) ResolvedAt() time.Time {
+	return c.resolvedAt
+}
+
+// FirstResponseTime is how long the customer waited for an answer, false until answered
+func (c Channel) FirstResponseTime() (time.Duration, bool) {
+	if c.firstResponseAt.IsZero() {
+		return 0, false
+	}
+	return c.firstResponseAt.Sub(c.createdAt), true
+}
+
+// ResolutionTime is how long the channel took to complete, false while it is open
+func (c Channel) ResolutionTime() (time.Duration, bool) {
+	if c.resolvedAt.IsZero() {
+		return 0, false
+	}
+	return c.resolvedAt.Sub(c.createdAt), true
+}
+
+// TrackSLA measures the channel against the policy of its priority at now,
+// SLA then tells the outcome
+func (c *Channel) TrackSLA(policy SLAPolicy, now time.Time) {
+	// Channels resolved without an answer stop waiting for one
+	responded := c.firstResponseAt
+	if responded.IsZero() {
+		responded = c.resolvedAt
+	}
+	c.sla = SLA{
+		FirstResponse: slaTarget(c.createdAt, policy.FirstResponse, responded, now),
+		Resolution:    slaTarget(c.createdAt, policy.Resolution, c.resolvedAt, now),
+	}
+}
+
+func slaTarget(start time.Time, target time.Duration, stoppedAt, now time.Time) SLATarget {
+	if target == 0 {
+		return SLATarget{}
+	}
+
+	t := SLATarget{Target: target, DueAt: start.Add(target), StoppedAt: stoppedAt}
+	switch {
+	case !stoppedAt.IsZero() && stoppedAt.After(t.DueAt):
+		t.Status = SLABreached
+	case !stoppedAt.IsZero():
+		t.Status = SLAMet
+	case now.After(t.DueAt):
+		t.Status = SLABreached
+	case now.Sub(start) >= time.Duration(float64(target)*slaApproachingShare):
+		t.Status = SLAApproaching
+	default:
+		t.Status = SLAOnTrack
+	}
+	return t
+}
+
+// SLA is the outcome of the latest TrackSLA
+func (c Channel) SLA() SLA {
+	return c.sla
+}
+
+// Status flags the channel with the worst status of its targets, empty when the
+// policy tracks none of them
+func (s SLA) Status() string {
+	var status string
+	for _, target := range []SLATarget{s.FirstResponse, s.Resolution} {
+		if slaSeverity(target.Status) > slaSeverity(status) {
+			status = target.Status
+		}
+	}
+	return status
+}
+
+func slaSeverity(status string) int {
+	switch status {
+	case SLABreached:
+		return 4
+	case SLAApproaching:
+		return 3
+	case SLAOnTrack:
+		return 2
+	case SLAMet:
+		return 1
+	}
+	return 0
+}
+
+// SLABreaches are the targets announced as breached
+func (c Channel) SLABreaches() []string {
+	return c.slaBreaches
+}
+
+// UnannouncedBreaches are the targets breached since TrackSLA that were not announced yet
+func (c Channel) UnannouncedBreaches() []string {
+	var breaches []string
+	for _, target := range []struct {
+		name   string
+		target SLATarget
+	}{
+		{name: SLAFirstResponse, target: c.sla.FirstResponse},
+		{name: SLAResolution, target: c.sla.Resolution},
+	} {
+		if target.target.Status == SLABreached && !c.announced(target.name) {
+			breaches = append(breaches, target.name)
+		}
+	}
+	return breaches
+}
+
+// AnnounceSLABreach records that the breach of a target was announced, so it is only announced once
+func (c *Channel) AnnounceSLABreach(target string) {
+	if !c.announced(target) {
+		c.slaBreaches = append(c.slaBreaches, target)
+	}
+}
+
+func (c Channel) announced(target string) bool {
+	for _, breach := range c.slaBreaches {
+		if breach == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// SSOStateTTL bounds how long a user may take to come back from the identity provider
+	SSOStateTTL = 10 * time.Minute
+)
+
+var (
+	ErrSSONotConfigured   = errors.New("single sign-on is not configured")
+	ErrInvalidSSOState    = errors.New("invalid or expired single sign-on state")
+	ErrSSOLoginFailed     = errors.New("single sign-on login failed")
+	ErrSSORoleNotAllowed  = errors.New("identity is not allowed to sign in with single sign-on")
+	ErrSSOEmailUnverified = errors.New("identity provider email is not verified")
+)
+
+type (
+	// IdentityProvider runs the OpenID Connect authorization code flow with PKCE
+	IdentityProvider interface {
+		// AuthorizationRequest creates the state, nonce and code verifier of a new
+		// login and the URL the browser is sent to
+		AuthorizationRequest(context.Context) (SSOState, string, error)
+		// Exchange redeems the authorization code and returns the verified identity
+		Exchange(context.Context, string, SSOState) (Identity, error)
+	}
+
+	SSOStateRepository interface {
+		CreateSSOState(context.Context, SSOState) error
+		// ConsumeSSOState returns the state and deletes it so it can only be used once
+		ConsumeSSOState(context.Context, string) (SSOState, error)
+	}
+
+	SSOState struct {
+		State        string
+		Nonce        string
+		CodeVerifier string
+		ExpiresAt    time.Time
+	}
+
+	// Identity is what the identity provider asserts about the user, Role is
+	// already mapped from the provider claims and empty when nothing matched
+	Identity struct {
+		Subject       string
+		Email         string
+		EmailVerified bool
+		FirstName     string
+		LastName      string
+		Role          string
+	}
+)
+
+func (s SSOState) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
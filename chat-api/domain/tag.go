@@ -0,0 +1,56 @@
+package domain
+
+import "strings"
+
+// NormalizeTags lowercases and trims tags, dropping empty and repeated ones
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !hasTag(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// AddTags tags the channel, it returns whether a tag was missing
+func (c *Channel) AddTags(tags ...string) bool {
+	var added bool
+	for _, tag := range NormalizeTags(tags) {
+		if !hasTag(c.tags, tag) {
+			c.tags = append(c.tags, tag)
+			added = true
+		}
+	}
+	return added
+}
+
+// RemoveTags untags the channel, it returns whether a tag was there
+func (c *Channel) RemoveTags(tags ...string) bool {
+	var (
+		removed = NormalizeTags(tags)
+		kept    []string
+	)
+	for _, tag := range c.tags {
+		if !hasTag(removed, tag) {
+			kept = append(kept, tag)
+		}
+	}
+	changed := len(kept) != len(c.tags)
+	c.tags = kept
+	return changed
+}
+
+func (c Channel) Tags() []string {
+	return c.tags
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	// ScopeTwoFactorEnrollment restricts a token to the two factor enrollment endpoints
+	ScopeTwoFactorEnrollment = "2fa_enrollment"
+)
+
+var (
+	ErrTwoFactorRequired       = errors.New("two factor code required")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two factor code")
+	ErrTwoFactorNotEnrolled    = errors.New("two factor authentication not enrolled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two factor authentication already enabled")
+)
+
+type (
+	TwoFactorService interface {
+		GenerateSecret(context.Context) (string, error)
+		ProvisioningURI(context.Context, string, string) string
+		ValidateCode(context.Context, string, string) bool
+		GenerateRecoveryCodes(context.Context) ([]string, error)
+		HashRecoveryCode(context.Context, string) string
+		EncryptSecret(context.Context, string) (string, error)
+		DecryptSecret(context.Context, string) (string, error)
+	}
+
+	SettingsRepository interface {
+		GetSecuritySettings(context.Context) (SecuritySettings, error)
+		SaveSecuritySettings(context.Context, SecuritySettings) error
+	}
+
+	SecuritySettings struct {
+		RequireTwoFactorForAdmin bool
+	}
+)
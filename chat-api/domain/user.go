@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,23 +12,40 @@ import (
 const (
 	ADMIN = "ADMIN"
 	USER  = "USER"
+	GUEST = "GUEST"
 )
 
 var (
 	ErrUserNotFound                = errors.New("user not found")
+	ErrUserAlreadyExists           = errors.New("user already exists with email")
 	ErrUsernameOrPasswordIncorrect = errors.New("username or password incorrect")
+	ErrUserDeactivated             = errors.New("user account is deactivated")
+	ErrCurrentPasswordIncorrect    = errors.New("current password incorrect")
+	ErrCannotDeactivateSelf        = errors.New("users cannot deactivate their own account")
+	ErrAdminCreatedByAdmin         = errors.New("only admins can create admins")
 )
 
 type (
 	AuthenticationUtilityService interface {
 		HashPassword(context.Context, string) (string, error)
 		CheckPasswordHash(context.Context, string, string) bool
+		NeedsRehash(context.Context, string) bool
 		GenerateToken(context.Context, User) (string, error)
+		GenerateScopedToken(context.Context, User, string) (string, error)
+		GenerateGuestToken(context.Context, User, string) (string, error)
 	}
 
 	UserRepository interface {
 		CreateUser(context.Context, User) (User, error)
 		GetUserByEmail(context.Context, string) (User, error)
+		GetUsersByEmails(context.Context, []string) ([]User, error)
+		UpdatePassword(context.Context, User) error
+		UpdateTwoFactor(context.Context, User) error
+		UpdateProfile(context.Context, User) error
+		UpdateActivation(context.Context, User) error
+		UpdateRole(context.Context, User) error
+		GetUsers(context.Context, string, int, int) ([]User, error)
+		GetUsersCount(context.Context, string) (int64, error)
 	}
 
 	User struct {
@@ -39,6 +57,14 @@ type (
 		role      string
 		createdAt time.Time
 		updatedAt time.Time
+
+		twoFactorEnabled bool
+		twoFactorSecret  string
+		recoveryCodes    []string
+
+		deactivated bool
+
+		tenantId string
 	}
 )
 
@@ -47,17 +73,83 @@ func NewUser(id primitive.ObjectID, firstName, lastName, email, password string,
 		id:        id,
 		firstName: firstName,
 		lastName:  lastName,
-		email:     email,
+		email:     NormalizeEmail(email),
 		password:  password,
 		createdAt: createdAt,
 		updatedAt: updatedAt,
 	}
 }
 
+// NormalizeEmail is the canonical form an email is stored and looked up in,
+// so that Foo@x.com and foo@x.com resolve to the same account
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 func (u *User) UpdateRole(role string) {
 	u.role = role
 }
 
+// AssignTenant places the user in an organization, emails are only unique within one
+func (u *User) AssignTenant(tenantId string) {
+	u.tenantId = tenantId
+}
+
+func (u *User) UpdatePassword(password string, updatedAt time.Time) {
+	u.password = password
+	u.updatedAt = updatedAt
+}
+
+func (u *User) UpdateProfile(firstName, lastName string, updatedAt time.Time) {
+	u.firstName = firstName
+	u.lastName = lastName
+	u.updatedAt = updatedAt
+}
+
+func (u *User) Deactivate(updatedAt time.Time) {
+	u.deactivated = true
+	u.updatedAt = updatedAt
+}
+
+func (u *User) Reactivate(updatedAt time.Time) {
+	u.deactivated = false
+	u.updatedAt = updatedAt
+}
+
+// UpdateTwoFactor sets the encrypted TOTP secret, whether it is active and the hashed recovery codes
+func (u *User) UpdateTwoFactor(secret string, enabled bool, recoveryCodes []string) {
+	u.twoFactorSecret = secret
+	u.twoFactorEnabled = enabled
+	u.recoveryCodes = recoveryCodes
+}
+
+// UseRecoveryCode consumes a hashed recovery code, reporting whether it was valid
+func (u *User) UseRecoveryCode(hashedCode string) bool {
+	for i, code := range u.recoveryCodes {
+		if code == hashedCode {
+			u.recoveryCodes = append(u.recoveryCodes[:i:i], u.recoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (u User) IsActive() bool {
+	return !u.deactivated
+}
+
+func (u User) TwoFactorEnabled() bool {
+	return u.twoFactorEnabled
+}
+
+func (u User) TwoFactorSecret() string {
+	return u.twoFactorSecret
+}
+
+func (u User) RecoveryCodes() []string {
+	return u.recoveryCodes
+}
+
 func (u User) FirstName() string {
 	return u.firstName
 }
@@ -86,6 +178,10 @@ func (u User) UpdatedAt() time.Time {
 	return u.updatedAt
 }
 
+func (u User) TenantId() string {
+	return u.tenantId
+}
+
 func (u User) Id() primitive.ObjectID {
 	return u.id
 }
@@ -2,11 +2,38 @@ package config
 
 import (
 	"os"
+	"strconv"
 )
 
 // Config represents the configuration parameters for the app
 type Config struct {
 	AccessSecret string
+
+	PasswordHashAlgorithm string
+	Argon2Memory          int
+	Argon2Iterations      int
+	Argon2Parallelism     int
+	BcryptCost            int
+
+	TwoFactorEncryptionKey string
+	TOTPIssuer             string
+
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	OIDCRedirectURL  string
+	OIDCScopes       string
+	OIDCRoleClaim    string
+	OIDCRoleMapping  string
+
+	SocketEventsURL string
+	SocketEventsKey string
+
+	// SLACheckIntervalSeconds is how often open channels are checked for SLA breaches, zero turns the check off
+	SLACheckIntervalSeconds int
+
+	// TrustedProxies lists the addresses or CIDR ranges, comma separated, whose X-Forwarded-For is believed
+	TrustedProxies string
 }
 
 // GetConfig returns Configuration items
@@ -14,6 +41,30 @@ func GetConfig() *Config {
 
 	return &Config{
 		AccessSecret: Getenv("ACCESS_SECRET", ""),
+
+		PasswordHashAlgorithm: Getenv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+		Argon2Memory:          GetenvInt("ARGON2_MEMORY_KIB", 64*1024),
+		Argon2Iterations:      GetenvInt("ARGON2_ITERATIONS", 1),
+		Argon2Parallelism:     GetenvInt("ARGON2_PARALLELISM", 4),
+		BcryptCost:            GetenvInt("BCRYPT_COST", 12),
+
+		TwoFactorEncryptionKey: Getenv("TWO_FACTOR_ENCRYPTION_KEY", ""),
+		TOTPIssuer:             Getenv("TOTP_ISSUER", "Customer Service Chat"),
+
+		OIDCIssuer:       Getenv("OIDC_ISSUER", ""),
+		OIDCClientID:     Getenv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret: Getenv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:  Getenv("OIDC_REDIRECT_URL", "http://localhost:3000/sso/callback"),
+		OIDCScopes:       Getenv("OIDC_SCOPES", "openid email profile"),
+		OIDCRoleClaim:    Getenv("OIDC_ROLE_CLAIM", "groups"),
+		OIDCRoleMapping:  Getenv("OIDC_ROLE_MAPPING", ""),
+
+		SocketEventsURL: Getenv("SOCKET_EVENTS_URL", ""),
+		SocketEventsKey: Getenv("SOCKET_EVENTS_KEY", ""),
+
+		SLACheckIntervalSeconds: GetenvInt("SLA_CHECK_INTERVAL_SECONDS", 60),
+
+		TrustedProxies: Getenv("TRUSTED_PROXIES", ""),
 	}
 }
 
@@ -25,3 +76,12 @@ func Getenv(key, fallback string) string {
 	}
 	return value
 }
+
+// GetenvInt gets particular env value as an int, falling back when unset or invalid
+func GetenvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -24,3 +24,22 @@ func newConfigMongoDB() *config {
 		ctxTimeout: 60 * time.Second,
 	}
 }
+
+func newConfigPostgres() *config {
+	return &config{
+		uri:        os.Getenv("POSTGRES_DSN"),
+		ctxTimeout: 60 * time.Second,
+	}
+}
+
+// newConfigSQLite defaults to a database that lives as long as the process
+func newConfigSQLite() *config {
+	uri := os.Getenv("SQLITE_PATH")
+	if uri == "" {
+		uri = ":memory:"
+	}
+	return &config{
+		uri:        uri,
+		ctxTimeout: 60 * time.Second,
+	}
+}
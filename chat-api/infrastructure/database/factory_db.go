@@ -7,17 +7,35 @@ import (
 
 var (
 	errInvalidNoSQLDatabaseInstance = errors.New("invalid nosql db instance")
+	errInvalidSQLDatabaseInstance   = errors.New("invalid sql db instance")
 )
 
 const (
 	InstanceMongoDB int = iota
+	InstanceMemory
+	InstancePostgres
+	InstanceSQLite
 )
 
 func NewDatabaseNoSQLFactory(instance int) (repository.NoSQL, error) {
 	switch instance {
 	case InstanceMongoDB:
 		return NewMongoHandler(newConfigMongoDB())
+	case InstanceMemory:
+		return NewMemoryHandler(), nil
 	default:
 		return nil, errInvalidNoSQLDatabaseInstance
 	}
 }
+
+// NewDatabaseSQLFactory connects to a relational database and migrates its schema
+func NewDatabaseSQLFactory(instance int) (repository.SQL, error) {
+	switch instance {
+	case InstancePostgres:
+		return NewPostgresHandler(newConfigPostgres())
+	case InstanceSQLite:
+		return NewSQLiteHandler(newConfigSQLite())
+	default:
+		return nil, errInvalidSQLDatabaseInstance
+	}
+}
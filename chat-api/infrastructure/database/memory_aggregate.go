@@ -0,0 +1,447 @@
+package database
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Aggregate runs the pipeline stages the repositories use: $match, $project,
+// $group with $sum and $push, and $sort. Expressions support field paths,
+// $dateToString, $size, $ifNull, $filter, $eq, $ne, $subtract, $divide, $floor,
+// $arrayElemAt and $avg
+func (m *memoryHandler) Aggregate(ctx context.Context, collection string, pipeline interface{}, result interface{}, _ *options.AggregateOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	value, err := toValue(pipeline)
+	if err != nil {
+		return err
+	}
+	stages, ok := value.(bson.A)
+	if !ok {
+		return errors.New("pipeline must be an array of stages")
+	}
+
+	docs, err := m.find(collection, bson.D{})
+	if err != nil {
+		return err
+	}
+
+	for _, stage := range stages {
+		spec, ok := stage.(bson.D)
+		if !ok || len(spec) != 1 {
+			return errors.New("pipeline stages must be documents with a single stage")
+		}
+
+		switch spec[0].Key {
+		case "$match":
+			filter, ok := spec[0].Value.(bson.D)
+			if !ok {
+				return errors.New("$match takes a query document")
+			}
+			matched := make([]bson.D, 0, len(docs))
+			for _, doc := range docs {
+				ok, err := m.matches(collection, doc, filter)
+				if err != nil {
+					return err
+				}
+				if ok {
+					matched = append(matched, doc)
+				}
+			}
+			docs = matched
+		case "$project":
+			if docs, err = projectDocuments(docs, spec[0].Value); err != nil {
+				return err
+			}
+		case "$group":
+			if docs, err = groupDocuments(docs, spec[0].Value); err != nil {
+				return err
+			}
+		case "$sort":
+			if err := sortDocuments(docs, spec[0].Value); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("unsupported aggregation stage %s", spec[0].Key)
+		}
+	}
+
+	return decodeAll(docs, result)
+}
+
+// projectDocuments keeps the _id unless it is excluded, the fields set to 1 and
+// the ones computed by an expression
+func projectDocuments(docs []bson.D, value interface{}) ([]bson.D, error) {
+	spec, ok := value.(bson.D)
+	if !ok {
+		return nil, errors.New("$project takes a document")
+	}
+
+	projected := make([]bson.D, 0, len(docs))
+	for _, doc := range docs {
+		var result bson.D
+		if id, ok := get(spec, "_id"); !ok || truthy(id) {
+			if value, ok := get(doc, "_id"); ok {
+				result = append(result, bson.E{Key: "_id", Value: value})
+			}
+		}
+		for _, field := range spec {
+			if field.Key == "_id" {
+				continue
+			}
+			switch field.Value.(type) {
+			case bool, int32, int64, float64:
+				if !truthy(field.Value) {
+					return nil, errors.Errorf("$project can not exclude %s", field.Key)
+				}
+				if value, ok := get(doc, field.Key); ok {
+					result = append(result, bson.E{Key: field.Key, Value: value})
+				}
+				continue
+			}
+			value, err := evaluate(doc, field.Value, nil)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, bson.E{Key: field.Key, Value: value})
+		}
+		projected = append(projected, result)
+	}
+	return projected, nil
+}
+
+// groupDocuments keeps the groups in the order their first document came in
+func groupDocuments(docs []bson.D, value interface{}) ([]bson.D, error) {
+	spec, ok := value.(bson.D)
+	if !ok {
+		return nil, errors.New("$group takes a document")
+	}
+	idExpression, ok := get(spec, "_id")
+	if !ok {
+		return nil, errors.New("$group needs an _id")
+	}
+
+	var groups []bson.D
+	for _, doc := range docs {
+		id, err := evaluate(doc, idExpression, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		position := -1
+		for i, group := range groups {
+			if equal(group[0].Value, id) {
+				position = i
+				break
+			}
+		}
+		if position < 0 {
+			group := bson.D{{Key: "_id", Value: id}}
+			for _, field := range spec {
+				if field.Key == "_id" {
+					continue
+				}
+				accumulator, ok := field.Value.(bson.D)
+				if !ok || len(accumulator) != 1 {
+					return nil, errors.Errorf("invalid accumulator for %s", field.Key)
+				}
+				switch accumulator[0].Key {
+				case "$sum":
+					group = append(group, bson.E{Key: field.Key, Value: int64(0)})
+				case "$push":
+					group = append(group, bson.E{Key: field.Key, Value: bson.A{}})
+				default:
+					return nil, errors.Errorf("unsupported accumulator %s", accumulator[0].Key)
+				}
+			}
+			groups = append(groups, group)
+			position = len(groups) - 1
+		}
+
+		group := groups[position]
+		for i := 1; i < len(group); i++ {
+			field, _ := get(spec, group[i].Key)
+			accumulator := field.(bson.D)[0]
+			operand, err := evaluate(doc, accumulator.Value, nil)
+			if err != nil {
+				return nil, err
+			}
+			switch accumulator.Key {
+			case "$sum":
+				// Values that are not numbers are ignored, like MongoDB does
+				group[i].Value = add(group[i].Value, operand)
+			case "$push":
+				group[i].Value = append(group[i].Value.(bson.A), operand)
+			}
+		}
+	}
+
+	if groups == nil {
+		groups = make([]bson.D, 0)
+	}
+	return groups, nil
+}
+
+func add(sum, value interface{}) interface{} {
+	if v, ok := value.(int32); ok {
+		value = int64(v)
+	}
+
+	switch v := value.(type) {
+	case int64:
+		if total, ok := sum.(int64); ok {
+			return total + v
+		}
+		return toFloat(sum) + float64(v)
+	case float64:
+		return toFloat(sum) + v
+	default:
+		return sum
+	}
+}
+
+func toFloat(value interface{}) float64 {
+	f, _ := number(value)
+	return f
+}
+
+// evaluate computes an aggregation expression against a document, vars holds
+// the variables $filter binds
+func evaluate(doc bson.D, expression interface{}, vars map[string]interface{}) (interface{}, error) {
+	switch e := expression.(type) {
+	case string:
+		switch {
+		case strings.HasPrefix(e, "$$"):
+			path := strings.Split(strings.TrimPrefix(e, "$$"), ".")
+			return fieldValue(vars[path[0]], path[1:]), nil
+		case strings.HasPrefix(e, "$"):
+			return fieldValue(doc, strings.Split(strings.TrimPrefix(e, "$"), ".")), nil
+		}
+		return e, nil
+	case bson.A:
+		values := make(bson.A, 0, len(e))
+		for _, element := range e {
+			value, err := evaluate(doc, element, vars)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case bson.D:
+		if len(e) == 1 && strings.HasPrefix(e[0].Key, "$") {
+			return evaluateOperator(doc, e[0].Key, e[0].Value, vars)
+		}
+		result := make(bson.D, 0, len(e))
+		for _, element := range e {
+			value, err := evaluate(doc, element.Value, vars)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, bson.E{Key: element.Key, Value: value})
+		}
+		return result, nil
+	default:
+		return e, nil
+	}
+}
+
+func evaluateOperator(doc bson.D, operator string, argument interface{}, vars map[string]interface{}) (interface{}, error) {
+	switch operator {
+	case "$dateToString":
+		return dateToString(doc, argument, vars)
+	case "$filter":
+		return filterArray(doc, argument, vars)
+	case "$size":
+		value, err := evaluate(doc, argument, vars)
+		if err != nil {
+			return nil, err
+		}
+		array, ok := value.(bson.A)
+		if !ok {
+			return nil, errors.New("the argument to $size must be an array")
+		}
+		return int32(len(array)), nil
+	case "$floor":
+		value, err := evaluate(doc, argument, vars)
+		if err != nil {
+			return nil, err
+		}
+		x, ok := number(value)
+		if !ok {
+			return nil, nil
+		}
+		return math.Floor(x), nil
+	case "$avg":
+		values, err := evaluate(doc, argument, vars)
+		if err != nil {
+			return nil, err
+		}
+		array, ok := values.(bson.A)
+		if !ok {
+			array = bson.A{values}
+		}
+		// Values that are not numbers are ignored, like MongoDB does
+		var sum, count float64
+		for _, value := range array {
+			if x, ok := number(value); ok {
+				sum += x
+				count++
+			}
+		}
+		if count == 0 {
+			return nil, nil
+		}
+		return sum / count, nil
+	}
+
+	operands, err := evaluate(doc, argument, vars)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := operands.(bson.A)
+	if !ok || len(values) != 2 {
+		return nil, errors.Errorf("%s takes two arguments", operator)
+	}
+
+	switch operator {
+	case "$ifNull":
+		if values[0] == nil {
+			return values[1], nil
+		}
+		return values[0], nil
+	case "$eq":
+		return equal(values[0], values[1]), nil
+	case "$ne":
+		return !equal(values[0], values[1]), nil
+	case "$subtract":
+		if x, ok := values[0].(primitive.DateTime); ok {
+			if y, ok := values[1].(primitive.DateTime); ok {
+				return int64(x) - int64(y), nil
+			}
+			if y, ok := number(values[1]); ok {
+				return x - primitive.DateTime(y), nil
+			}
+		}
+		x, okX := number(values[0])
+		y, okY := number(values[1])
+		if !okX || !okY {
+			return nil, nil
+		}
+		return x - y, nil
+	case "$divide":
+		x, okX := number(values[0])
+		y, okY := number(values[1])
+		if !okX || !okY {
+			return nil, nil
+		}
+		if y == 0 {
+			return nil, errors.New("$divide can not divide by zero")
+		}
+		return x / y, nil
+	case "$arrayElemAt":
+		array, ok := values[0].(bson.A)
+		index, okIndex := number(values[1])
+		if !ok || !okIndex {
+			return nil, nil
+		}
+		// A negative index counts from the end of the array
+		position := int(index)
+		if position < 0 {
+			position += len(array)
+		}
+		if position < 0 || position >= len(array) {
+			return nil, nil
+		}
+		return array[position], nil
+	default:
+		return nil, errors.Errorf("unsupported expression operator %s", operator)
+	}
+}
+
+func fieldValue(value interface{}, path []string) interface{} {
+	for _, key := range path {
+		doc, ok := value.(bson.D)
+		if !ok {
+			return nil
+		}
+		value, _ = get(doc, key)
+	}
+	return value
+}
+
+// dateToString supports the %Y, %m, %d, %H, %M and %S specifiers
+func dateToString(doc bson.D, argument interface{}, vars map[string]interface{}) (interface{}, error) {
+	spec, ok := argument.(bson.D)
+	if !ok {
+		return nil, errors.New("$dateToString takes a document")
+	}
+
+	date, err := evaluate(doc, mustGet(spec, "date"), vars)
+	if err != nil {
+		return nil, err
+	}
+	dateTime, ok := date.(primitive.DateTime)
+	if !ok {
+		return nil, nil
+	}
+
+	location := time.UTC
+	if timezone, ok := mustGet(spec, "timezone").(string); ok && timezone != "" {
+		if location, err = time.LoadLocation(timezone); err != nil {
+			return nil, errors.Wrap(err, "$dateToString timezone")
+		}
+	}
+
+	format, _ := mustGet(spec, "format").(string)
+	replacer := strings.NewReplacer("%Y", "2006", "%m", "01", "%d", "02", "%H", "15", "%M", "04", "%S", "05")
+	return dateTime.Time().In(location).Format(replacer.Replace(format)), nil
+}
+
+func filterArray(doc bson.D, argument interface{}, vars map[string]interface{}) (interface{}, error) {
+	spec, ok := argument.(bson.D)
+	if !ok {
+		return nil, errors.New("$filter takes a document")
+	}
+
+	input, err := evaluate(doc, mustGet(spec, "input"), vars)
+	if err != nil {
+		return nil, err
+	}
+	array, ok := input.(bson.A)
+	if !ok {
+		return nil, nil
+	}
+
+	name, _ := mustGet(spec, "as").(string)
+	if name == "" {
+		name = "this"
+	}
+
+	filtered := bson.A{}
+	for _, element := range array {
+		scope := map[string]interface{}{name: element}
+		for key, value := range vars {
+			if key != name {
+				scope[key] = value
+			}
+		}
+		keep, err := evaluate(doc, mustGet(spec, "cond"), scope)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(keep) {
+			filtered = append(filtered, element)
+		}
+	}
+	return filtered, nil
+}
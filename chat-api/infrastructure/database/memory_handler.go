@@ -0,0 +1,577 @@
+package database
+
+import (
+	"chat-api/adapter/repository"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// memoryHandler implements repository.NoSQL in process, it supports the
+// query and update operators the repositories use and is meant for tests
+// and local development, data is lost when the process exits
+type memoryHandler struct {
+	mu          *sync.RWMutex
+	txMu        *sync.Mutex
+	collections map[string][]bson.D
+	// uniqueIndexes holds the keys of every unique index per collection
+	uniqueIndexes map[string][][]string
+	// textIndexes holds the fields of the text index per collection, $text
+	// looks at every string of collections without one
+	textIndexes map[string][]string
+}
+
+func NewMemoryHandler() *memoryHandler {
+	return &memoryHandler{
+		mu:            &sync.RWMutex{},
+		txMu:          &sync.Mutex{},
+		collections:   map[string][]bson.D{},
+		uniqueIndexes: map[string][][]string{},
+		textIndexes:   map[string][]string{},
+	}
+}
+
+func (m *memoryHandler) EnsureIndex(ctx context.Context, collection string, keys interface{}, unique bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	spec, err := toDocument(keys)
+	if err != nil {
+		return err
+	}
+	if len(spec) == 0 {
+		return errors.New("index keys must not be empty")
+	}
+
+	var textFields []string
+	for _, key := range spec {
+		if key.Value == "text" {
+			textFields = append(textFields, key.Key)
+		}
+	}
+	if len(textFields) > 0 {
+		m.mu.Lock()
+		m.textIndexes[collection] = textFields
+		m.mu.Unlock()
+	}
+	if !unique {
+		return nil
+	}
+
+	fields := make([]string, 0, len(spec))
+	for _, key := range spec {
+		fields = append(fields, key.Key)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, index := range m.uniqueIndexes[collection] {
+		if reflect.DeepEqual(index, fields) {
+			return nil
+		}
+	}
+	for i, doc := range m.collections[collection] {
+		if err := m.checkUnique(collection, doc, i, [][]string{fields}); err != nil {
+			return err
+		}
+	}
+	m.uniqueIndexes[collection] = append(m.uniqueIndexes[collection], fields)
+	return nil
+}
+
+// DropIndex mirrors mongoHandler.DropIndex, only unique indexes are tracked so
+// the name is matched against their default MongoDB names
+// matches runs a query against a document, $text only sees the fields of the
+// text index of the collection the way MongoDB does
+func (m *memoryHandler) matches(collection string, doc, filter bson.D) (bool, error) {
+	fields := m.textIndexes[collection]
+	if len(fields) == 0 {
+		return matches(doc, filter)
+	}
+
+	rest := make(bson.D, 0, len(filter))
+	for _, element := range filter {
+		if element.Key != "$text" {
+			rest = append(rest, element)
+			continue
+		}
+
+		indexed := bson.D{}
+		for _, field := range fields {
+			if values, ok := resolve(doc, strings.Split(field, ".")); ok {
+				indexed = append(indexed, bson.E{Key: field, Value: bson.A(values)})
+			}
+		}
+		if ok, err := matchText(indexed, element.Value); err != nil || !ok {
+			return false, err
+		}
+	}
+	return matches(doc, rest)
+}
+
+func (m *memoryHandler) DropIndex(_ context.Context, collection, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	indexes := m.uniqueIndexes[collection]
+	for i, index := range indexes {
+		if indexName(index) == name {
+			m.uniqueIndexes[collection] = append(indexes[:i:i], indexes[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memoryHandler) Store(ctx context.Context, collection string, data interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	doc, err := toDocument(data)
+	if err != nil {
+		return err
+	}
+	if _, ok := get(doc, "_id"); !ok {
+		doc = append(bson.D{{Key: "_id", Value: primitive.NewObjectID()}}, doc...)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkUnique(collection, doc, -1, m.uniqueIndexes[collection]); err != nil {
+		return err
+	}
+	m.collections[collection] = append(m.collections[collection], doc)
+	return nil
+}
+
+func (m *memoryHandler) Update(ctx context.Context, collection string, query interface{}, update interface{}) error {
+	_, err := m.update(ctx, collection, query, update, false, false)
+	return err
+}
+
+func (m *memoryHandler) UpdateMany(ctx context.Context, collection string, query interface{}, update interface{}) error {
+	_, err := m.update(ctx, collection, query, update, true, false)
+	return err
+}
+
+func (m *memoryHandler) Upsert(ctx context.Context, collection string, query interface{}, update interface{}) error {
+	_, err := m.update(ctx, collection, query, update, false, true)
+	return err
+}
+
+func (m *memoryHandler) FindOneAndUpdate(ctx context.Context, collection string, query, update interface{}, upsert bool, result interface{}) error {
+	doc, err := m.update(ctx, collection, query, update, false, upsert)
+	if err != nil {
+		return err
+	}
+	if doc == nil {
+		return mongo.ErrNoDocuments
+	}
+	return decode(doc, result)
+}
+
+// update returns the last document it changed or inserted, nil when none matched
+func (m *memoryHandler) update(ctx context.Context, collection string, query, update interface{}, many, upsert bool) (bson.D, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	filter, err := toDocument(query)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := toDocument(update)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	docs := m.collections[collection]
+	var last bson.D
+	for i, doc := range docs {
+		ok, err := m.matches(collection, doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		updated, err := applyUpdate(doc, changes, false)
+		if err != nil {
+			return nil, err
+		}
+		if id, _ := get(doc, "_id"); !equal(id, mustGet(updated, "_id")) {
+			return nil, errors.New("the _id field cannot be modified")
+		}
+		if err := m.checkUnique(collection, updated, i, m.uniqueIndexes[collection]); err != nil {
+			return nil, err
+		}
+		docs[i] = updated
+
+		last = updated
+		if !many {
+			break
+		}
+	}
+	if last != nil || !upsert {
+		return last, nil
+	}
+
+	seed, err := upsertSeed(filter)
+	if err != nil {
+		return nil, err
+	}
+	inserted, err := applyUpdate(seed, changes, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := get(inserted, "_id"); !ok {
+		inserted = append(bson.D{{Key: "_id", Value: primitive.NewObjectID()}}, inserted...)
+	}
+	if err := m.checkUnique(collection, inserted, -1, m.uniqueIndexes[collection]); err != nil {
+		return nil, err
+	}
+	m.collections[collection] = append(docs, inserted)
+	return inserted, nil
+}
+
+func (m *memoryHandler) FindAll(ctx context.Context, collection string, query interface{}, result interface{}, findOptions *options.FindOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	docs, err := m.find(collection, query)
+	if err != nil {
+		return err
+	}
+
+	if findOptions != nil {
+		if findOptions.Sort != nil {
+			if err := sortDocuments(docs, findOptions.Sort); err != nil {
+				return err
+			}
+		}
+		if findOptions.Skip != nil {
+			skip := int(*findOptions.Skip)
+			if skip > len(docs) {
+				skip = len(docs)
+			}
+			docs = docs[skip:]
+		}
+		if findOptions.Limit != nil && *findOptions.Limit != 0 {
+			limit := int(*findOptions.Limit)
+			if limit < 0 {
+				limit = -limit
+			}
+			if limit < len(docs) {
+				docs = docs[:limit]
+			}
+		}
+		if findOptions.Projection != nil {
+			if docs, err = projectAll(docs, findOptions.Projection); err != nil {
+				return err
+			}
+		}
+	}
+
+	return decodeAll(docs, result)
+}
+
+func (m *memoryHandler) FindOne(ctx context.Context, collection string, query interface{}, projection interface{}, result interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	docs, err := m.find(collection, query)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	projected, err := projectAll(docs[:1], projection)
+	if err != nil {
+		return err
+	}
+	return decode(projected[0], result)
+}
+
+func (m *memoryHandler) FindCount(ctx context.Context, collection string, query interface{}) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	docs, err := m.find(collection, query)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(docs)), nil
+}
+
+func (m *memoryHandler) FindOneAndDelete(ctx context.Context, collection string, query interface{}, result interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	filter, err := toDocument(query)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	docs := m.collections[collection]
+	for i, doc := range docs {
+		ok, err := m.matches(collection, doc, filter)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		m.collections[collection] = append(docs[:i:i], docs[i+1:]...)
+		return decode(doc, result)
+	}
+	return mongo.ErrNoDocuments
+}
+
+func (m *memoryHandler) StartSession() (repository.Session, error) {
+	return memorySession{handler: m}, nil
+}
+
+// find returns copies of the matching documents in insertion order
+func (m *memoryHandler) find(collection string, query interface{}) ([]bson.D, error) {
+	filter, err := toDocument(query)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]bson.D, 0)
+	for _, doc := range m.collections[collection] {
+		ok, err := m.matches(collection, doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, copyValue(doc).(bson.D))
+		}
+	}
+	return result, nil
+}
+
+// checkUnique reports a duplicate key error like MongoDB does when doc
+// collides with another document, skip is the position of doc itself
+func (m *memoryHandler) checkUnique(collection string, doc bson.D, skip int, indexes [][]string) error {
+	indexes = append([][]string{{"_id"}}, indexes...)
+
+	for _, index := range indexes {
+		for i, other := range m.collections[collection] {
+			if i == skip {
+				continue
+			}
+			if collides(doc, other, index) {
+				return mongo.WriteException{WriteErrors: mongo.WriteErrors{{
+					Code:    11000,
+					Message: fmt.Sprintf("E11000 duplicate key error collection: %s index: %s", collection, indexName(index)),
+				}}}
+			}
+		}
+	}
+	return nil
+}
+
+// collides compares the index keys of two documents, array fields collide
+// when any of their elements does, as in a multikey index
+func collides(a, b bson.D, index []string) bool {
+	for _, key := range index {
+		var (
+			path            = strings.Split(key, ".")
+			valuesA, foundA = resolve(a, path)
+			valuesB, foundB = resolve(b, path)
+		)
+		if !foundA {
+			valuesA = []interface{}{nil}
+		}
+		if !foundB {
+			valuesB = []interface{}{nil}
+		}
+
+		overlap := false
+		for _, x := range expandArrays(valuesA) {
+			for _, y := range expandArrays(valuesB) {
+				if equal(x, y) {
+					overlap = true
+				}
+			}
+		}
+		if !overlap {
+			return false
+		}
+	}
+	return true
+}
+
+func expandArrays(values []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		if array, ok := value.(bson.A); ok && len(array) > 0 {
+			result = append(result, array...)
+			continue
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+func indexName(fields []string) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field+"_1")
+	}
+	return strings.Join(parts, "_")
+}
+
+func mustGet(doc bson.D, key string) interface{} {
+	value, _ := get(doc, key)
+	return value
+}
+
+func sortDocuments(docs []bson.D, spec interface{}) error {
+	keys, err := toDocument(spec)
+	if err != nil {
+		return err
+	}
+
+	directions := make([]float64, len(keys))
+	for i, key := range keys {
+		direction, ok := number(key.Value)
+		if !ok || (direction != 1 && direction != -1) {
+			return errors.Errorf("invalid sort direction for %s", key.Key)
+		}
+		directions[i] = direction
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		for k, key := range keys {
+			order := sortCompare(sortValue(docs[i], key.Key), sortValue(docs[j], key.Key))
+			if order != 0 {
+				return float64(order)*directions[k] < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+func projectAll(docs []bson.D, projection interface{}) ([]bson.D, error) {
+	spec, err := toDocument(projection)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bson.D, 0, len(docs))
+	for _, doc := range docs {
+		projected, err := project(doc, spec)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, projected)
+	}
+	return result, nil
+}
+
+func decode(doc bson.D, result interface{}) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, result)
+}
+
+// decodeAll fills the slice result points to, like mongo.Cursor.All
+func decodeAll(docs []bson.D, result interface{}) error {
+	target := reflect.ValueOf(result)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Slice {
+		return errors.New("result argument must be a pointer to a slice")
+	}
+
+	var (
+		slice       = target.Elem()
+		elementType = slice.Type().Elem()
+		values      = reflect.MakeSlice(slice.Type(), 0, len(docs))
+	)
+	for _, doc := range docs {
+		element := reflect.New(elementType)
+		if err := decode(doc, element.Interface()); err != nil {
+			return err
+		}
+		values = reflect.Append(values, element.Elem())
+	}
+	slice.Set(values)
+	return nil
+}
+
+// memorySession runs transactions one at a time and rolls back every
+// collection when the transaction fails. Writes made outside of a
+// transaction meanwhile are not isolated from it.
+type memorySession struct {
+	handler *memoryHandler
+}
+
+func (s memorySession) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	s.handler.txMu.Lock()
+	defer s.handler.txMu.Unlock()
+
+	snapshot := s.handler.snapshot()
+	if err := fn(ctx); err != nil {
+		s.handler.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+func (s memorySession) EndSession(context.Context) {}
+
+func (m *memoryHandler) snapshot() map[string][]bson.D {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot := make(map[string][]bson.D, len(m.collections))
+	for name, docs := range m.collections {
+		copied := make([]bson.D, 0, len(docs))
+		for _, doc := range docs {
+			copied = append(copied, copyValue(doc).(bson.D))
+		}
+		snapshot[name] = copied
+	}
+	return snapshot
+}
+
+func (m *memoryHandler) restore(snapshot map[string][]bson.D) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.collections = snapshot
+}
@@ -0,0 +1,597 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"chat-api/adapter/repository"
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type memoryTestDocument struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Name      string             `bson:"name"`
+	Status    string             `bson:"status"`
+	Count     int                `bson:"count"`
+	Tags      []string           `bson:"tags"`
+	Owner     *memoryTestOwner   `bson:"owner,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+type memoryTestOwner struct {
+	Email string `bson:"email"`
+}
+
+func seedMemoryHandler(t *testing.T) (*memoryHandler, time.Time) {
+	var (
+		db   = NewMemoryHandler()
+		ctx  = context.Background()
+		base = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	docs := []memoryTestDocument{
+		{Name: "a", Status: "ACTIVE", Count: 1, Tags: []string{"billing"}, Owner: &memoryTestOwner{Email: "rep@email.com"}, CreatedAt: base},
+		{Name: "b", Status: "IN_PROGRESS", Count: 5, Tags: []string{"billing", "vip"}, CreatedAt: base.Add(time.Hour)},
+		{Name: "c", Status: "COMPLETE", Count: 3, CreatedAt: base.Add(2 * time.Hour)},
+	}
+	for _, doc := range docs {
+		if err := db.Store(ctx, "docs", doc); err != nil {
+			t.Fatalf("[TestCase 'seed'] Result: '%v' | Expected: '%v'", err, nil)
+		}
+	}
+	return db, base
+}
+
+func names(docs []memoryTestDocument) []string {
+	result := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		result = append(result, doc.Name)
+	}
+	return result
+}
+
+func TestMemoryHandler_FindAll(t *testing.T) {
+	t.Parallel()
+
+	db, base := seedMemoryHandler(t)
+
+	tests := []struct {
+		name     string
+		query    interface{}
+		options  *options.FindOptions
+		expected []string
+	}{
+		{name: "empty query", query: bson.M{}, expected: []string{"a", "b", "c"}},
+		{name: "equality", query: bson.M{"status": "ACTIVE"}, expected: []string{"a"}},
+		{name: "equality on array element", query: bson.M{"tags": "vip"}, expected: []string{"b"}},
+		{name: "dotted path", query: bson.M{"owner.email": "rep@email.com"}, expected: []string{"a"}},
+		{name: "missing field equals null", query: bson.M{"owner": nil}, expected: []string{"b", "c"}},
+		{name: "$ne", query: bson.M{"status": bson.M{"$ne": "ACTIVE"}}, expected: []string{"b", "c"}},
+		{name: "$gt and $lte", query: bson.M{"count": bson.M{"$gt": 1, "$lte": 5}}, expected: []string{"b", "c"}},
+		{name: "$gte on dates", query: bson.M{"createdAt": bson.M{"$gte": base.Add(time.Hour)}}, expected: []string{"b", "c"}},
+		{name: "$lt on dates", query: bson.M{"createdAt": bson.M{"$lt": base.Add(time.Hour)}}, expected: []string{"a"}},
+		{name: "$in", query: bson.M{"status": bson.M{"$in": bson.A{"ACTIVE", "COMPLETE"}}}, expected: []string{"a", "c"}},
+		{name: "$nin", query: bson.M{"status": bson.M{"$nin": bson.A{"ACTIVE", "COMPLETE"}}}, expected: []string{"b"}},
+		{name: "$exists", query: bson.M{"owner": bson.M{"$exists": true}}, expected: []string{"a"}},
+		{name: "$regex with options", query: bson.M{"status": bson.M{"$regex": "^in_", "$options": "i"}}, expected: []string{"b"}},
+		{name: "regex value", query: bson.M{"status": primitive.Regex{Pattern: "ACTIVE$"}}, expected: []string{"a"}},
+		{name: "$not", query: bson.M{"count": bson.M{"$not": bson.M{"$gt": 2}}}, expected: []string{"a"}},
+		{name: "$size", query: bson.M{"tags": bson.M{"$size": 2}}, expected: []string{"b"}},
+		{name: "$all", query: bson.M{"tags": bson.M{"$all": bson.A{"billing", "vip"}}}, expected: []string{"b"}},
+		{name: "$elemMatch", query: bson.M{"tags": bson.M{"$elemMatch": bson.M{"$eq": "vip"}}}, expected: []string{"b"}},
+		{name: "$and", query: bson.M{"$and": bson.A{bson.M{"tags": "billing"}, bson.D{{Key: "count", Value: bson.M{"$gt": 1}}}}}, expected: []string{"b"}},
+		{name: "$or", query: bson.M{"$or": bson.A{bson.M{"status": "ACTIVE"}, bson.M{"count": 3}}}, expected: []string{"a", "c"}},
+		{name: "$nor", query: bson.M{"$nor": bson.A{bson.M{"status": "ACTIVE"}, bson.M{"count": 3}}}, expected: []string{"b"}},
+		{name: "$text words", query: bson.M{"$text": bson.M{"$search": "vip unknown"}}, expected: []string{"b"}},
+		{name: "$text phrases", query: bson.M{"$text": bson.M{"$search": `"Billing" "rep@"`}}, expected: []string{"a"}},
+		{name: "typed filter", query: bson.D{{Key: "status", Value: "COMPLETE"}}, expected: []string{"c"}},
+		{name: "sort descending", query: bson.M{}, options: options.Find().SetSort(bson.D{{Key: "count", Value: -1}}), expected: []string{"b", "c", "a"}},
+		{name: "sort on dates", query: bson.M{}, options: options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}), expected: []string{"c", "b", "a"}},
+		{
+			name:     "skip and limit",
+			query:    bson.M{},
+			options:  options.Find().SetSort(bson.D{{Key: "name", Value: 1}}).SetSkip(1).SetLimit(1),
+			expected: []string{"b"},
+		},
+		{name: "skip past the end", query: bson.M{}, options: options.Find().SetSkip(10), expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var docs []memoryTestDocument
+			if err := db.FindAll(context.Background(), "docs", tt.query, &docs, tt.options); err != nil {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, nil)
+			}
+
+			if got := names(docs); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+
+			count, err := db.FindCount(context.Background(), "docs", tt.query)
+			if tt.options == nil && (err != nil || int(count) != len(tt.expected)) {
+				t.Errorf("[TestCase '%s'] Count: '%v' | Expected: '%v'", tt.name, count, len(tt.expected))
+			}
+		})
+	}
+}
+
+func TestMemoryHandler_TextIndex(t *testing.T) {
+	t.Parallel()
+
+	db, _ := seedMemoryHandler(t)
+	ctx := context.Background()
+
+	// Once the collection has a text index $text only looks at its fields
+	if err := db.EnsureIndex(ctx, "docs", bson.D{{Key: "tags", Value: "text"}}, false); err != nil {
+		t.Fatalf("[TestCase 'text index'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	tests := []struct {
+		name     string
+		query    interface{}
+		expected []string
+	}{
+		{name: "indexed field", query: bson.M{"$text": bson.M{"$search": "vip"}}, expected: []string{"b"}},
+		{name: "field outside the index", query: bson.M{"$text": bson.M{"$search": "IN_PROGRESS"}}, expected: []string{}},
+		{name: "with other conditions", query: bson.M{"$text": bson.M{"$search": "billing"}, "count": bson.M{"$gt": 2}}, expected: []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		var result []memoryTestDocument
+		if err := db.FindAll(ctx, "docs", tt.query, &result, nil); err != nil {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, nil)
+			continue
+		}
+		if got := names(result); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestMemoryHandler_UnsupportedOperator(t *testing.T) {
+	t.Parallel()
+
+	db, _ := seedMemoryHandler(t)
+
+	var docs []memoryTestDocument
+	err := db.FindAll(context.Background(), "docs", bson.M{"$where": "this.count > 1"}, &docs, nil)
+	if err == nil {
+		t.Errorf("[TestCase 'unsupported operator'] Result: '%v' | Expected: an error", err)
+	}
+}
+
+func TestMemoryHandler_Update(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db, _ = seedMemoryHandler(t)
+		ctx   = context.Background()
+	)
+
+	if err := db.Update(ctx, "docs", bson.M{"name": "a"}, bson.M{
+		"$set":  bson.M{"status": "COMPLETE", "owner.email": "other@email.com"},
+		"$inc":  bson.M{"count": 2},
+		"$push": bson.M{"tags": "vip"},
+	}); err != nil {
+		t.Fatalf("[TestCase 'update'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	if err := db.UpdateMany(ctx, "docs", bson.M{"tags": "billing"}, bson.M{"$pull": bson.M{"tags": "billing"}}); err != nil {
+		t.Fatalf("[TestCase 'update many'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	var doc memoryTestDocument
+	if err := db.FindOne(ctx, "docs", bson.M{"name": "a"}, nil, &doc); err != nil {
+		t.Fatalf("[TestCase 'find updated'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	expected := memoryTestDocument{
+		ID:        doc.ID,
+		Name:      "a",
+		Status:    "COMPLETE",
+		Count:     3,
+		Tags:      []string{"vip"},
+		Owner:     &memoryTestOwner{Email: "other@email.com"},
+		CreatedAt: doc.CreatedAt,
+	}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("[TestCase 'update'] Result: '%v' | Expected: '%v'", doc, expected)
+	}
+
+	var vip []memoryTestDocument
+	_ = db.FindAll(ctx, "docs", bson.M{"tags": "vip"}, &vip, nil)
+	if got := names(vip); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("[TestCase 'update many'] Result: '%v' | Expected: '%v'", got, []string{"a", "b"})
+	}
+
+	err := db.Update(ctx, "docs", bson.M{"name": "b"}, bson.M{"name": "replaced"})
+	if err == nil {
+		t.Errorf("[TestCase 'replacement document'] Result: '%v' | Expected: an error", err)
+	}
+}
+
+func TestMemoryHandler_Upsert(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db  = NewMemoryHandler()
+		ctx = context.Background()
+	)
+
+	upsert := func(status string) {
+		err := db.Upsert(ctx, "settings", bson.M{"_id": "security", "tenantId": "acme"}, bson.M{
+			"$set":         bson.M{"status": status},
+			"$setOnInsert": bson.M{"createdBy": "first"},
+		})
+		if err != nil {
+			t.Fatalf("[TestCase 'upsert'] Result: '%v' | Expected: '%v'", err, nil)
+		}
+	}
+	upsert("created")
+	upsert("updated")
+
+	var docs []bson.M
+	if err := db.FindAll(ctx, "settings", bson.M{}, &docs, nil); err != nil {
+		t.Fatalf("[TestCase 'upsert'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	expected := []bson.M{{"_id": "security", "tenantId": "acme", "status": "updated", "createdBy": "first"}}
+	if !reflect.DeepEqual(docs, expected) {
+		t.Errorf("[TestCase 'upsert'] Result: '%v' | Expected: '%v'", docs, expected)
+	}
+}
+
+func TestMemoryHandler_UniqueIndex(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db  = NewMemoryHandler()
+		ctx = context.Background()
+	)
+
+	if err := db.EnsureIndex(ctx, "users", bson.D{{Key: "tenantId", Value: 1}, {Key: "email", Value: 1}}, true); err != nil {
+		t.Fatalf("[TestCase 'ensure index'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	if err := db.EnsureIndex(ctx, "organizations", bson.D{{Key: "domains", Value: 1}}, true); err != nil {
+		t.Fatalf("[TestCase 'ensure index'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	tests := []struct {
+		name       string
+		collection string
+		doc        bson.M
+		duplicate  bool
+	}{
+		{name: "first user", collection: "users", doc: bson.M{"tenantId": "acme", "email": "user@email.com"}},
+		{name: "same email in another tenant", collection: "users", doc: bson.M{"tenantId": "globex", "email": "user@email.com"}},
+		{name: "same email in the same tenant", collection: "users", doc: bson.M{"tenantId": "acme", "email": "user@email.com"}, duplicate: true},
+		{name: "first organization", collection: "organizations", doc: bson.M{"_id": "acme", "domains": bson.A{"acme.com", "acme.io"}}},
+		{name: "organization sharing a domain", collection: "organizations", doc: bson.M{"_id": "other", "domains": bson.A{"acme.io"}}, duplicate: true},
+		{name: "organization reusing an id", collection: "organizations", doc: bson.M{"_id": "acme", "domains": bson.A{"acme.org"}}, duplicate: true},
+	}
+
+	for _, tt := range tests {
+		err := db.Store(ctx, tt.collection, tt.doc)
+		if mongo.IsDuplicateKeyError(err) != tt.duplicate || (!tt.duplicate && err != nil) {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected duplicate: '%v'", tt.name, err, tt.duplicate)
+		}
+	}
+
+	err := db.Update(ctx, "users", bson.M{"tenantId": "globex"}, bson.M{"$set": bson.M{"tenantId": "acme"}})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("[TestCase 'update into a duplicate'] Result: '%v' | Expected: a duplicate key error", err)
+	}
+}
+
+func TestMemoryHandler_FindOneAndDelete(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db, _ = seedMemoryHandler(t)
+		ctx   = context.Background()
+		doc   memoryTestDocument
+	)
+
+	if err := db.FindOneAndDelete(ctx, "docs", bson.M{"name": "b"}, &doc); err != nil || doc.Name != "b" {
+		t.Fatalf("[TestCase 'delete'] Result: '%v', '%v' | Expected: '%v'", doc.Name, err, "b")
+	}
+	if err := db.FindOneAndDelete(ctx, "docs", bson.M{"name": "b"}, &doc); err != mongo.ErrNoDocuments {
+		t.Errorf("[TestCase 'delete twice'] Result: '%v' | Expected: '%v'", err, mongo.ErrNoDocuments)
+	}
+	if err := db.FindOne(ctx, "docs", bson.M{"name": "b"}, nil, &doc); err != mongo.ErrNoDocuments {
+		t.Errorf("[TestCase 'find deleted'] Result: '%v' | Expected: '%v'", err, mongo.ErrNoDocuments)
+	}
+}
+
+func TestMemoryHandler_Projection(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db, _ = seedMemoryHandler(t)
+		doc   bson.M
+	)
+
+	if err := db.FindOne(context.Background(), "docs", bson.M{"name": "c"}, bson.M{"name": 1, "_id": 0}, &doc); err != nil {
+		t.Fatalf("[TestCase 'projection'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	if !reflect.DeepEqual(doc, bson.M{"name": "c"}) {
+		t.Errorf("[TestCase 'projection'] Result: '%v' | Expected: '%v'", doc, bson.M{"name": "c"})
+	}
+}
+
+func TestMemoryHandler_Transaction(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db, _   = seedMemoryHandler(t)
+		ctx     = context.Background()
+		failure = errors.New("rollback")
+	)
+
+	session, err := db.StartSession()
+	if err != nil {
+		t.Fatalf("[TestCase 'start session'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	defer session.EndSession(ctx)
+
+	err = session.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := db.Store(ctx, "docs", memoryTestDocument{Name: "d"}); err != nil {
+			return err
+		}
+		if err := db.Update(ctx, "docs", bson.M{"name": "a"}, bson.M{"$set": bson.M{"status": "COMPLETE"}}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("[TestCase 'transaction'] Result: '%v' | Expected: '%v'", err, failure)
+	}
+
+	count, _ := db.FindCount(ctx, "docs", bson.M{"$or": bson.A{bson.M{"name": "d"}, bson.M{"status": "COMPLETE", "name": "a"}}})
+	if count != 0 {
+		t.Errorf("[TestCase 'transaction'] Documents left by a failed transaction: '%v' | Expected: '%v'", count, 0)
+	}
+
+	err = session.WithTransaction(ctx, func(ctx context.Context) error {
+		return db.Store(ctx, "docs", memoryTestDocument{Name: "d"})
+	})
+	if count, _ := db.FindCount(ctx, "docs", bson.M{"name": "d"}); err != nil || count != 1 {
+		t.Errorf("[TestCase 'transaction'] Result: '%v', '%v' | Expected: '%v'", count, err, 1)
+	}
+}
+
+func TestMemoryHandler_Aggregate(t *testing.T) {
+	t.Parallel()
+
+	db, base := seedMemoryHandler(t)
+
+	type group struct {
+		ID     string   `bson:"_id"`
+		Count  int      `bson:"count"`
+		Total  int      `bson:"total"`
+		Tagged int      `bson:"tagged"`
+		Ages   []int64  `bson:"ages"`
+		Names  []string `bson:"names"`
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"createdAt": bson.M{"$gte": base}}}},
+		{{Key: "$group", Value: bson.D{
+			// Until 5 in the morning in UTC it is still February 28th in New York
+			{Key: "_id", Value: bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$createdAt", "timezone": "America/New_York"}}},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+			{Key: "total", Value: bson.M{"$sum": "$count"}},
+			{Key: "tagged", Value: bson.M{"$sum": bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$tags", bson.A{}}},
+				"cond":  bson.M{"$eq": bson.A{"$$this", "billing"}},
+			}}}}},
+			{Key: "ages", Value: bson.M{"$push": bson.M{"$subtract": bson.A{"$createdAt", base}}}},
+			{Key: "names", Value: bson.M{"$push": "$name"}},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	var groups []group
+	if err := db.Aggregate(context.Background(), "docs", pipeline, &groups, nil); err != nil {
+		t.Fatalf("[TestCase 'aggregate'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	expected := []group{
+		{ID: "2021-02-28", Count: 3, Total: 9, Tagged: 2, Ages: []int64{0, 3600000, 7200000}, Names: []string{"a", "b", "c"}},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("[TestCase 'aggregate'] Result: '%+v' | Expected: '%+v'", groups, expected)
+	}
+
+	type median struct {
+		ID     string  `bson:"_id"`
+		Count  int     `bson:"count"`
+		Median float64 `bson:"median"`
+		Last   string  `bson:"last"`
+	}
+	middle := func(offset int) bson.M {
+		return bson.M{"$arrayElemAt": bson.A{"$ages", bson.M{"$floor": bson.M{"$divide": bson.A{
+			bson.M{"$subtract": bson.A{bson.M{"$size": "$ages"}, 1 - offset}},
+			2,
+		}}}}}
+	}
+	for _, tt := range []struct {
+		name     string
+		from     time.Time
+		expected []median
+	}{
+		{name: "median of an odd count", from: base, expected: []median{{ID: "all", Count: 3, Median: 3600000, Last: "a"}}},
+		{name: "median of an even count", from: base.Add(time.Minute), expected: []median{{ID: "all", Count: 2, Median: 5400000, Last: "b"}}},
+	} {
+		var medians []median
+		err := db.Aggregate(context.Background(), "docs", mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"createdAt": bson.M{"$gte": tt.from}}}},
+			{{Key: "$project", Value: bson.D{
+				{Key: "_id", Value: 0},
+				{Key: "name", Value: 1},
+				{Key: "age", Value: bson.M{"$subtract": bson.A{"$createdAt", base}}},
+			}}},
+			{{Key: "$sort", Value: bson.D{{Key: "age", Value: -1}}}},
+			{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "all"},
+				{Key: "count", Value: bson.M{"$sum": 1}},
+				{Key: "ages", Value: bson.M{"$push": "$age"}},
+				{Key: "names", Value: bson.M{"$push": "$name"}},
+			}}},
+			{{Key: "$project", Value: bson.D{
+				{Key: "count", Value: 1},
+				{Key: "median", Value: bson.M{"$avg": bson.A{middle(0), middle(1)}}},
+				{Key: "last", Value: bson.M{"$arrayElemAt": bson.A{"$names", -1}}},
+			}}},
+		}, &medians, nil)
+		if err != nil || !reflect.DeepEqual(medians, tt.expected) {
+			t.Errorf("[TestCase '%s'] Result: '%+v', '%v' | Expected: '%+v'", tt.name, medians, err, tt.expected)
+		}
+	}
+
+	err := db.Aggregate(context.Background(), "docs", mongo.Pipeline{{{Key: "$lookup", Value: bson.M{}}}}, &groups, nil)
+	if err == nil {
+		t.Errorf("[TestCase 'unsupported stage'] Result: '%v' | Expected: an error", err)
+	}
+}
+
+func TestMemoryHandler_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := NewMemoryHandler().Store(ctx, "docs", bson.M{"name": "a"}); err != context.Canceled {
+		t.Errorf("[TestCase 'canceled context'] Result: '%v' | Expected: '%v'", err, context.Canceled)
+	}
+}
+
+// The repositories are exercised against the in-memory backend to make sure
+// it understands every query they send
+func TestMemoryHandler_Repositories(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db       repository.NoSQL = NewMemoryHandler()
+		acme                      = domain.WithTenant(context.Background(), "acme")
+		globex                    = domain.WithTenant(context.Background(), "globex")
+		now                       = time.Now().UTC().Truncate(time.Millisecond)
+		users                     = repository.NewUserNoSQL(db)
+		channels                  = repository.NewChannelNoSQL(db)
+		attempts                  = repository.NewLoginAttemptNoSQL(db)
+		audit                     = repository.NewAuditEventNoSQL(db)
+	)
+
+	user := domain.NewUser(primitive.NewObjectID(), "first", "last", "User@Email.com", "hash", now, now)
+	if _, err := users.CreateUser(acme, user); err != nil {
+		t.Fatalf("[TestCase 'create user'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	if _, err := users.CreateUser(acme, user); err != domain.ErrUserAlreadyExists {
+		t.Errorf("[TestCase 'create duplicate user'] Result: '%v' | Expected: '%v'", err, domain.ErrUserAlreadyExists)
+	}
+	if _, err := users.CreateUser(globex, user); err != nil {
+		t.Errorf("[TestCase 'create user in another tenant'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	if found, err := users.GetUserByEmail(acme, "USER@email.com"); err != nil || found.Email() != "user@email.com" || found.TenantId() != "acme" {
+		t.Errorf("[TestCase 'get user by email'] Result: '%v', '%v' | Expected: '%v'", found.Email(), err, "user@email.com")
+	}
+
+	channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, now, now)
+	if _, err := channels.CreateChannel(acme, channel); err != nil {
+		t.Fatalf("[TestCase 'create channel'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	channel.UpdateRepEmail("rep@email.com")
+	channel.UpdateStatus(domain.IN_PROGRESS, "rep@email.com", now.Unix())
+	if err := channels.UpdateChannelStatus(acme, channel); err != nil {
+		t.Fatalf("[TestCase 'update channel status'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	found, err := channels.GetChannelById(acme, channel.Id().Hex())
+	if err != nil || found.RepEmail() != "rep@email.com" || found.CurrentStatus() != domain.IN_PROGRESS {
+		t.Errorf("[TestCase 'get channel by id'] Result: '%v', '%v' | Expected: '%v'", found.CurrentStatus(), err, domain.IN_PROGRESS)
+	}
+	if _, err := channels.GetChannelById(globex, channel.Id().Hex()); err != domain.ErrUserNotFound {
+		t.Errorf("[TestCase 'get channel of another tenant'] Result: '%v' | Expected: '%v'", err, domain.ErrUserNotFound)
+	}
+	if count, err := channels.GetChannelsByQueryCount(acme, domain.ChannelQuery{RepEmail: "rep@email.com", Statuses: []string{domain.IN_PROGRESS}}); err != nil || count != 1 {
+		t.Errorf("[TestCase 'count channels'] Result: '%v', '%v' | Expected: '%v'", count, err, 1)
+	}
+
+	attempt := domain.NewLoginAttempt(domain.AccountAttemptKey("user@email.com"), 3, now, time.Time{})
+	if err := attempts.SaveLoginAttempt(acme, attempt); err != nil {
+		t.Fatalf("[TestCase 'save login attempt'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	if saved, err := attempts.GetLoginAttempt(acme, attempt.Key()); err != nil || saved.Failures() != 3 {
+		t.Errorf("[TestCase 'get login attempt'] Result: '%v', '%v' | Expected: '%v'", saved.Failures(), err, 3)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		counts   = make(chan int, 10)
+		ipKey    = domain.IPAttemptKey("192.0.2.1")
+		lockedBy = make(chan bool, 10)
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			failed, err := attempts.AddLoginFailure(acme, ipKey, now, now.Add(-time.Hour))
+			if err != nil {
+				t.Errorf("[TestCase 'add login failure'] Result: '%v' | Expected: '%v'", err, nil)
+				return
+			}
+			counts <- failed.Failures()
+			locked, _ := attempts.LockLoginAttempt(acme, ipKey, now, now.Add(time.Hour))
+			lockedBy <- locked
+		}()
+	}
+	wg.Wait()
+	close(counts)
+	close(lockedBy)
+
+	var seen = make(map[int]bool)
+	for count := range counts {
+		seen[count] = true
+	}
+	if len(seen) != 10 || !seen[1] || !seen[10] {
+		t.Errorf("[TestCase 'concurrent login failures'] Result: '%v' | Expected: '%v'", seen, "every count from 1 to 10 once")
+	}
+	var locks int
+	for locked := range lockedBy {
+		if locked {
+			locks++
+		}
+	}
+	if locks != 1 {
+		t.Errorf("[TestCase 'concurrent lockouts'] Result: '%v' | Expected: '%v'", locks, 1)
+	}
+	if saved, err := attempts.GetLoginAttempt(acme, ipKey); err != nil || saved.Failures() != 10 || !saved.IsLocked(now) {
+		t.Errorf("[TestCase 'get counted login attempt'] Result: '%v', '%v' | Expected: '%v'", saved.Failures(), err, 10)
+	}
+	if failed, err := attempts.AddLoginFailure(acme, ipKey, now.Add(2*time.Hour), now); err != nil || failed.Failures() != 1 || failed.IsLocked(now) {
+		t.Errorf("[TestCase 'failure after an expired lockout'] Result: '%v', '%v' | Expected: '%v'", failed.Failures(), err, 1)
+	}
+	if failed, err := attempts.AddLoginFailure(acme, attempt.Key(), now.Add(2*time.Hour), now.Add(time.Hour)); err != nil || failed.Failures() != 1 {
+		t.Errorf("[TestCase 'failure after the window'] Result: '%v', '%v' | Expected: '%v'", failed.Failures(), err, 1)
+	}
+
+	for i, actor := range []string{"admin@email.com", "admin@email.com", "rep@email.com"} {
+		event := domain.AuditEvent{Action: domain.AuditLogin, Actor: actor, Outcome: domain.AuditOutcomeSuccess, Timestamp: now.Add(time.Duration(i) * time.Minute)}
+		if err := audit.StoreAuditEvent(acme, event); err != nil {
+			t.Fatalf("[TestCase 'store audit event'] Result: '%v' | Expected: '%v'", err, nil)
+		}
+	}
+	events, err := audit.GetAuditEvents(acme, domain.AuditQuery{Actor: "admin@email.com", From: now.Add(time.Second)}, 0, 10)
+	if err != nil || len(events) != 1 || !events[0].Timestamp.Equal(now.Add(time.Minute)) {
+		t.Errorf("[TestCase 'query audit events'] Result: '%v', '%v' | Expected: one event", events, err)
+	}
+}
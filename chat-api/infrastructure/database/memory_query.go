@@ -0,0 +1,942 @@
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// The in-memory backend keeps documents the way they travel over the wire:
+// every value is first marshaled to BSON and read back, so embedded documents
+// become bson.D, arrays bson.A and times primitive.DateTime regardless of the
+// Go types the repositories used to build them.
+
+func toDocument(value interface{}) (bson.D, error) {
+	if value == nil {
+		return bson.D{}, nil
+	}
+
+	raw, err := bson.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return documentFromRaw(raw)
+}
+
+func documentFromRaw(raw bson.Raw) (bson.D, error) {
+	elements, err := raw.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(bson.D, 0, len(elements))
+	for _, element := range elements {
+		value, err := valueFromRaw(element.Value())
+		if err != nil {
+			return nil, err
+		}
+		doc = append(doc, bson.E{Key: element.Key(), Value: value})
+	}
+	return doc, nil
+}
+
+func valueFromRaw(raw bson.RawValue) (interface{}, error) {
+	switch raw.Type {
+	case bsontype.EmbeddedDocument:
+		return documentFromRaw(raw.Document())
+	case bsontype.Array:
+		values, err := raw.Array().Values()
+		if err != nil {
+			return nil, err
+		}
+		array := make(bson.A, 0, len(values))
+		for _, value := range values {
+			converted, err := valueFromRaw(value)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, converted)
+		}
+		return array, nil
+	case bsontype.Null, bsontype.Undefined:
+		return nil, nil
+	default:
+		var value interface{}
+		if err := raw.Unmarshal(&value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+func toValue(value interface{}) (interface{}, error) {
+	kind, data, err := bson.MarshalValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return valueFromRaw(bson.RawValue{Type: kind, Value: data})
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		doc := make(bson.D, len(v))
+		for i, element := range v {
+			doc[i] = bson.E{Key: element.Key, Value: copyValue(element.Value)}
+		}
+		return doc
+	case bson.A:
+		array := make(bson.A, len(v))
+		for i, element := range v {
+			array[i] = copyValue(element)
+		}
+		return array
+	default:
+		return v
+	}
+}
+
+func get(doc bson.D, key string) (interface{}, bool) {
+	for _, element := range doc {
+		if element.Key == key {
+			return element.Value, true
+		}
+	}
+	return nil, false
+}
+
+// resolve follows a dotted path, walking into every document of the arrays it crosses
+func resolve(value interface{}, path []string) ([]interface{}, bool) {
+	if len(path) == 0 {
+		return []interface{}{value}, true
+	}
+
+	switch v := value.(type) {
+	case bson.D:
+		child, ok := get(v, path[0])
+		if !ok {
+			return nil, false
+		}
+		return resolve(child, path[1:])
+	case bson.A:
+		if index, err := strconv.Atoi(path[0]); err == nil {
+			if index < 0 || index >= len(v) {
+				return nil, false
+			}
+			return resolve(v[index], path[1:])
+		}
+
+		var (
+			values []interface{}
+			found  bool
+		)
+		for _, element := range v {
+			if _, ok := element.(bson.D); !ok {
+				continue
+			}
+			resolved, ok := resolve(element, path)
+			if ok {
+				values = append(values, resolved...)
+				found = true
+			}
+		}
+		return values, found
+	default:
+		return nil, false
+	}
+}
+
+// candidates are the values a condition is checked against, an array field
+// matches both as a whole and through any of its elements
+func candidates(values []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+		if array, ok := value.(bson.A); ok {
+			result = append(result, array...)
+		}
+	}
+	return result
+}
+
+func matches(doc bson.D, query bson.D) (bool, error) {
+	for _, element := range query {
+		var (
+			ok  bool
+			err error
+		)
+
+		switch element.Key {
+		case "$and", "$or", "$nor":
+			ok, err = matchLogical(doc, element.Key, element.Value)
+		case "$text":
+			ok, err = matchText(doc, element.Value)
+		default:
+			if strings.HasPrefix(element.Key, "$") {
+				return false, errors.Errorf("unsupported query operator %s", element.Key)
+			}
+			ok, err = matchField(doc, element.Key, element.Value)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchLogical(doc bson.D, operator string, value interface{}) (bool, error) {
+	clauses, ok := value.(bson.A)
+	if !ok || len(clauses) == 0 {
+		return false, errors.Errorf("%s needs a non empty array", operator)
+	}
+
+	for _, clause := range clauses {
+		sub, ok := clause.(bson.D)
+		if !ok {
+			return false, errors.Errorf("%s entries must be documents", operator)
+		}
+		matched, err := matches(doc, sub)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case operator == "$and" && !matched:
+			return false, nil
+		case operator == "$or" && matched:
+			return true, nil
+		case operator == "$nor" && matched:
+			return false, nil
+		}
+	}
+	return operator != "$or", nil
+}
+
+var textPhrase = regexp.MustCompile(`"([^"]*)"`)
+
+// matchText behaves as if every string of the document was covered by a text
+// index without stemming: quoted phrases must all appear in one of the strings,
+// when there are other words one of them must be a word of the document
+func matchText(doc bson.D, value interface{}) (bool, error) {
+	options, ok := value.(bson.D)
+	if !ok {
+		return false, errors.New("$text needs a document")
+	}
+	search, ok := get(options, "$search")
+	text, isString := search.(string)
+	if !ok || !isString {
+		return false, errors.New("$text needs a $search string")
+	}
+
+	var strs []string
+	collectStrings(doc, &strs)
+	content := strings.ToLower(strings.Join(strs, "\n"))
+
+	for _, phrase := range textPhrase.FindAllStringSubmatch(text, -1) {
+		if !strings.Contains(content, strings.ToLower(phrase[1])) {
+			return false, nil
+		}
+	}
+
+	words := strings.Fields(strings.ToLower(textPhrase.ReplaceAllString(text, " ")))
+	if len(words) == 0 {
+		return true, nil
+	}
+	documentWords := map[string]bool{}
+	for _, word := range strings.FieldsFunc(content, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		documentWords[word] = true
+	}
+	for _, word := range words {
+		if documentWords[word] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func collectStrings(value interface{}, strs *[]string) {
+	switch v := value.(type) {
+	case string:
+		*strs = append(*strs, v)
+	case bson.D:
+		for _, element := range v {
+			collectStrings(element.Value, strs)
+		}
+	case bson.A:
+		for _, element := range v {
+			collectStrings(element, strs)
+		}
+	}
+}
+
+func isOperatorDocument(value interface{}) (bson.D, bool) {
+	doc, ok := value.(bson.D)
+	if !ok || len(doc) == 0 || !strings.HasPrefix(doc[0].Key, "$") {
+		return nil, false
+	}
+	return doc, true
+}
+
+func matchField(doc bson.D, key string, condition interface{}) (bool, error) {
+	values, found := resolve(doc, strings.Split(key, "."))
+
+	operators, ok := isOperatorDocument(condition)
+	if !ok {
+		return matchEqual(values, found, condition), nil
+	}
+	return matchOperators(values, found, operators)
+}
+
+func matchOperators(values []interface{}, found bool, operators bson.D) (bool, error) {
+	var options string
+	if value, ok := get(operators, "$options"); ok {
+		options, _ = value.(string)
+	}
+
+	for _, operator := range operators {
+		ok, err := matchOperator(values, found, operator.Key, operator.Value, options)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchOperator(values []interface{}, found bool, operator string, argument interface{}, options string) (bool, error) {
+	switch operator {
+	case "$eq":
+		return matchEqual(values, found, argument), nil
+	case "$ne":
+		return !matchEqual(values, found, argument), nil
+	case "$gt", "$gte", "$lt", "$lte":
+		for _, value := range candidates(values) {
+			order, ok := compare(value, argument)
+			if !ok {
+				continue
+			}
+			if (operator == "$gt" && order > 0) ||
+				(operator == "$gte" && order >= 0) ||
+				(operator == "$lt" && order < 0) ||
+				(operator == "$lte" && order <= 0) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "$in", "$nin":
+		options, ok := argument.(bson.A)
+		if !ok {
+			return false, errors.Errorf("%s needs an array", operator)
+		}
+		in := false
+		for _, option := range options {
+			if matchEqual(values, found, option) {
+				in = true
+				break
+			}
+		}
+		return in == (operator == "$in"), nil
+	case "$exists":
+		return found == truthy(argument), nil
+	case "$regex":
+		return matchRegex(values, argument, options)
+	case "$options":
+		return true, nil
+	case "$not":
+		operators, ok := isOperatorDocument(argument)
+		if !ok {
+			if _, isRegex := argument.(primitive.Regex); !isRegex {
+				return false, errors.New("$not needs an operator document or a regular expression")
+			}
+			operators = bson.D{{Key: "$regex", Value: argument}}
+		}
+		matched, err := matchOperators(values, found, operators)
+		return !matched, err
+	case "$size":
+		size, ok := number(argument)
+		if !ok {
+			return false, errors.New("$size needs a number")
+		}
+		for _, value := range values {
+			if array, ok := value.(bson.A); ok && float64(len(array)) == size {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "$all":
+		required, ok := argument.(bson.A)
+		if !ok {
+			return false, errors.New("$all needs an array")
+		}
+		for _, value := range required {
+			if !matchEqual(values, found, value) {
+				return false, nil
+			}
+		}
+		return len(required) > 0, nil
+	case "$elemMatch":
+		condition, ok := argument.(bson.D)
+		if !ok {
+			return false, errors.New("$elemMatch needs a document")
+		}
+		for _, value := range values {
+			array, ok := value.(bson.A)
+			if !ok {
+				continue
+			}
+			for _, element := range array {
+				matched, err := matchElement(element, condition)
+				if err != nil {
+					return false, err
+				}
+				if matched {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	default:
+		return false, errors.Errorf("unsupported query operator %s", operator)
+	}
+}
+
+// matchElement checks one array element, which is either a document matched
+// as a query or a scalar matched against operators
+func matchElement(element interface{}, condition bson.D) (bool, error) {
+	if operators, ok := isOperatorDocument(condition); ok {
+		return matchOperators([]interface{}{element}, true, operators)
+	}
+	doc, ok := element.(bson.D)
+	if !ok {
+		return false, nil
+	}
+	return matches(doc, condition)
+}
+
+func matchEqual(values []interface{}, found bool, expected interface{}) bool {
+	if regex, ok := expected.(primitive.Regex); ok {
+		matched, _ := matchRegex(values, regex, "")
+		return matched
+	}
+	if expected == nil && !found {
+		return true
+	}
+	for _, value := range candidates(values) {
+		if equal(value, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchRegex(values []interface{}, argument interface{}, options string) (bool, error) {
+	pattern, ok := argument.(string)
+	if regex, isRegex := argument.(primitive.Regex); isRegex {
+		pattern, options, ok = regex.Pattern, regex.Options+options, true
+	}
+	if !ok {
+		return false, errors.New("$regex needs a string")
+	}
+
+	var flags string
+	for _, option := range options {
+		if strings.ContainsRune("imsx", option) && !strings.ContainsRune(flags, option) {
+			flags += string(option)
+		}
+	}
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid $regex")
+	}
+	for _, value := range candidates(values) {
+		if text, ok := value.(string); ok && expression.MatchString(text) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case nil:
+		return false
+	default:
+		n, ok := number(v)
+		return !ok || n != 0
+	}
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func equal(a, b interface{}) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+
+	switch x := a.(type) {
+	case bson.D:
+		y, ok := b.(bson.D)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if x[i].Key != y[i].Key || !equal(x[i].Value, y[i].Value) {
+				return false
+			}
+		}
+		return true
+	case bson.A:
+		y, ok := b.(bson.A)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// compare orders two values of the same kind, reporting false for values
+// that cannot be compared with each other
+func compare(a, b interface{}) (int, bool) {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	switch x := a.(type) {
+	case string:
+		y, ok := b.(string)
+		return strings.Compare(x, y), ok
+	case primitive.DateTime:
+		y, ok := b.(primitive.DateTime)
+		switch {
+		case !ok:
+			return 0, false
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		default:
+			return 0, true
+		}
+	case primitive.ObjectID:
+		y, ok := b.(primitive.ObjectID)
+		return bytes.Compare(x[:], y[:]), ok
+	case bool:
+		y, ok := b.(bool)
+		switch {
+		case !ok:
+			return 0, false
+		case x == y:
+			return 0, true
+		case !x:
+			return -1, true
+		default:
+			return 1, true
+		}
+	default:
+		return 0, false
+	}
+}
+
+// typeRank follows the order MongoDB uses when sorting values of different types
+func typeRank(value interface{}) int {
+	if _, ok := number(value); ok {
+		return 2
+	}
+	switch value.(type) {
+	case nil:
+		return 1
+	case string:
+		return 3
+	case bson.D:
+		return 4
+	case bson.A:
+		return 5
+	case primitive.Binary:
+		return 6
+	case primitive.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case primitive.DateTime:
+		return 9
+	default:
+		return 10
+	}
+}
+
+func sortCompare(a, b interface{}) int {
+	if rankA, rankB := typeRank(a), typeRank(b); rankA != rankB {
+		return rankA - rankB
+	}
+	if order, ok := compare(a, b); ok {
+		return order
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func sortValue(doc bson.D, key string) interface{} {
+	values, found := resolve(doc, strings.Split(key, "."))
+	if !found || len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+func applyUpdate(doc bson.D, update bson.D, insert bool) (bson.D, error) {
+	if len(update) == 0 {
+		return nil, errors.New("update document must not be empty")
+	}
+
+	doc = copyValue(doc).(bson.D)
+	for _, operator := range update {
+		fields, ok := operator.Value.(bson.D)
+		if !ok {
+			return nil, errors.Errorf("update operator %s needs a document", operator.Key)
+		}
+
+		for _, field := range fields {
+			var (
+				path = strings.Split(field.Key, ".")
+				err  error
+			)
+
+			switch operator.Key {
+			case "$set":
+				doc, err = setPath(doc, path, copyValue(field.Value))
+			case "$setOnInsert":
+				if insert {
+					doc, err = setPath(doc, path, copyValue(field.Value))
+				}
+			case "$unset":
+				doc = unsetPath(doc, path)
+			case "$inc":
+				doc, err = incPath(doc, path, field.Value)
+			case "$push", "$addToSet":
+				doc, err = pushPath(doc, path, field.Value, operator.Key == "$addToSet")
+			case "$pull":
+				doc, err = pullPath(doc, path, field.Value)
+			default:
+				if !strings.HasPrefix(operator.Key, "$") {
+					return nil, errors.New("update document must contain key beginning with '$'")
+				}
+				return nil, errors.Errorf("unsupported update operator %s", operator.Key)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return doc, nil
+}
+
+func setPath(doc bson.D, path []string, value interface{}) (bson.D, error) {
+	for _, key := range path {
+		if key == "$" || strings.HasPrefix(key, "$[") {
+			return nil, errors.New("positional updates are not supported")
+		}
+	}
+
+	for i, element := range doc {
+		if element.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			doc[i].Value = value
+			return doc, nil
+		}
+
+		child, err := setIn(element.Value, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		doc[i].Value = child
+		return doc, nil
+	}
+
+	if len(path) == 1 {
+		return append(doc, bson.E{Key: path[0], Value: value}), nil
+	}
+	child, err := setPath(bson.D{}, path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	return append(doc, bson.E{Key: path[0], Value: child}), nil
+}
+
+func setIn(container interface{}, path []string, value interface{}) (interface{}, error) {
+	switch v := container.(type) {
+	case bson.D:
+		return setPath(v, path, value)
+	case bson.A:
+		index, err := strconv.Atoi(path[0])
+		if err != nil || index < 0 {
+			return nil, errors.Errorf("cannot create field %s in an array", path[0])
+		}
+		for len(v) <= index {
+			v = append(v, nil)
+		}
+		if len(path) == 1 {
+			v[index] = value
+			return v, nil
+		}
+		child, err := setIn(v[index], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		v[index] = child
+		return v, nil
+	case nil:
+		return setPath(bson.D{}, path, value)
+	default:
+		return nil, errors.Errorf("cannot create field %s in a scalar value", path[0])
+	}
+}
+
+func unsetPath(doc bson.D, path []string) bson.D {
+	for i, element := range doc {
+		if element.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return append(doc[:i], doc[i+1:]...)
+		}
+		if child, ok := element.Value.(bson.D); ok {
+			doc[i].Value = unsetPath(child, path[1:])
+		}
+		return doc
+	}
+	return doc
+}
+
+func incPath(doc bson.D, path []string, delta interface{}) (bson.D, error) {
+	increment, ok := number(delta)
+	if !ok {
+		return nil, errors.New("$inc needs a number")
+	}
+
+	values, found := resolve(doc, path)
+	if !found {
+		return setPath(doc, path, delta)
+	}
+
+	var current interface{}
+	if len(values) > 0 {
+		current = values[0]
+	}
+	switch c := current.(type) {
+	case int32:
+		if d, ok := delta.(int32); ok {
+			return setPath(doc, path, c+d)
+		}
+		if d, ok := delta.(int64); ok {
+			return setPath(doc, path, int64(c)+d)
+		}
+		return setPath(doc, path, float64(c)+increment)
+	case int64:
+		if _, ok := delta.(float64); !ok {
+			return setPath(doc, path, c+int64(increment))
+		}
+		return setPath(doc, path, float64(c)+increment)
+	case float64:
+		return setPath(doc, path, c+increment)
+	default:
+		return nil, errors.Errorf("cannot apply $inc to a non numeric field %s", strings.Join(path, "."))
+	}
+}
+
+func pushPath(doc bson.D, path []string, value interface{}, unique bool) (bson.D, error) {
+	items := bson.A{value}
+	if modifiers, ok := isOperatorDocument(value); ok {
+		each, ok := get(modifiers, "$each")
+		if !ok || len(modifiers) != 1 {
+			return nil, errors.New("only the $each modifier is supported")
+		}
+		if items, ok = each.(bson.A); !ok {
+			return nil, errors.New("$each needs an array")
+		}
+	}
+
+	var array bson.A
+	if values, found := resolve(doc, path); found && len(values) > 0 {
+		existing, ok := values[0].(bson.A)
+		if !ok && values[0] != nil {
+			return nil, errors.Errorf("field %s is not an array", strings.Join(path, "."))
+		}
+		array = existing
+	}
+
+	result := append(bson.A{}, array...)
+	for _, item := range items {
+		if unique && contains(result, item) {
+			continue
+		}
+		result = append(result, copyValue(item))
+	}
+	return setPath(doc, path, result)
+}
+
+func pullPath(doc bson.D, path []string, condition interface{}) (bson.D, error) {
+	values, found := resolve(doc, path)
+	if !found || len(values) == 0 {
+		return doc, nil
+	}
+	array, ok := values[0].(bson.A)
+	if !ok {
+		return nil, errors.Errorf("field %s is not an array", strings.Join(path, "."))
+	}
+
+	result := bson.A{}
+	for _, element := range array {
+		var (
+			remove bool
+			err    error
+		)
+		if query, ok := condition.(bson.D); ok {
+			remove, err = matchElement(element, query)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			remove = equal(element, condition)
+		}
+		if !remove {
+			result = append(result, element)
+		}
+	}
+	return setPath(doc, path, result)
+}
+
+func contains(array bson.A, value interface{}) bool {
+	for _, element := range array {
+		if equal(element, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// upsertSeed is the document an upsert starts from, built from the equality conditions of its query
+func upsertSeed(query bson.D) (bson.D, error) {
+	seed := bson.D{}
+	for _, element := range query {
+		var err error
+		switch {
+		case element.Key == "$and":
+			clauses, _ := element.Value.(bson.A)
+			for _, clause := range clauses {
+				sub, ok := clause.(bson.D)
+				if !ok {
+					continue
+				}
+				fields, err := upsertSeed(sub)
+				if err != nil {
+					return nil, err
+				}
+				for _, field := range fields {
+					if seed, err = setPath(seed, strings.Split(field.Key, "."), field.Value); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case strings.HasPrefix(element.Key, "$"):
+		default:
+			operators, isOperator := isOperatorDocument(element.Value)
+			if !isOperator {
+				seed, err = setPath(seed, strings.Split(element.Key, "."), copyValue(element.Value))
+			} else if value, ok := get(operators, "$eq"); ok {
+				seed, err = setPath(seed, strings.Split(element.Key, "."), copyValue(value))
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return seed, nil
+}
+
+// project keeps or drops top level fields, _id is kept unless excluded explicitly
+func project(doc bson.D, projection bson.D) (bson.D, error) {
+	if len(projection) == 0 {
+		return doc, nil
+	}
+
+	var include, exclude bool
+	fields := map[string]bool{}
+	for _, field := range projection {
+		if strings.Contains(field.Key, ".") || strings.HasPrefix(field.Key, "$") {
+			return nil, errors.Errorf("unsupported projection on %s", field.Key)
+		}
+		keep := truthy(field.Value)
+		fields[field.Key] = keep
+		if field.Key == "_id" {
+			continue
+		}
+		if keep {
+			include = true
+		} else {
+			exclude = true
+		}
+	}
+	if include && exclude {
+		return nil, errors.New("projection cannot both include and exclude fields")
+	}
+
+	result := bson.D{}
+	for _, element := range doc {
+		keep, listed := fields[element.Key]
+		switch {
+		case element.Key == "_id" && !listed:
+			keep = true
+		case !listed:
+			keep = !include
+		}
+		if keep {
+			result = append(result, element)
+		}
+	}
+	return result, nil
+}
@@ -53,6 +53,12 @@ func (mgo mongoHandler) EnsureIndex(
 	return err
 }
 
+// DropIndex removes an index by name, it is used by maintenance commands and not part of repository.NoSQL
+func (mgo mongoHandler) DropIndex(ctx context.Context, collection, name string) error {
+	_, err := mgo.db.Collection(collection).Indexes().DropOne(ctx, name)
+	return err
+}
+
 func (mgo mongoHandler) Store(ctx context.Context, collection string, data interface{}) error {
 	if _, err := mgo.db.Collection(collection).InsertOne(ctx, data); err != nil {
 		return err
@@ -69,6 +75,23 @@ func (mgo mongoHandler) Update(ctx context.Context, collection string, query int
 	return nil
 }
 
+func (mgo mongoHandler) UpdateMany(ctx context.Context, collection string, query interface{}, update interface{}) error {
+	if _, err := mgo.db.Collection(collection).UpdateMany(ctx, query, update); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (mgo mongoHandler) Upsert(ctx context.Context, collection string, query interface{}, update interface{}) error {
+	opts := options.Update().SetUpsert(true)
+	if _, err := mgo.db.Collection(collection).UpdateOne(ctx, query, update, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (mgo mongoHandler) FindCount(ctx context.Context, collection string, query interface{}) (int64, error) {
 	count, err := mgo.db.Collection(collection).CountDocuments(ctx, query)
 	if err != nil {
@@ -133,6 +156,28 @@ func (mgo mongoHandler) FindOne(
 	return nil
 }
 
+func (mgo mongoHandler) FindOneAndDelete(
+	ctx context.Context,
+	collection string,
+	query interface{},
+	result interface{},
+) error {
+	return mgo.db.Collection(collection).FindOneAndDelete(ctx, query).Decode(result)
+}
+
+// FindOneAndUpdate decodes the document as the update left it
+func (mgo mongoHandler) FindOneAndUpdate(
+	ctx context.Context,
+	collection string,
+	query interface{},
+	update interface{},
+	upsert bool,
+	result interface{},
+) error {
+	opts := options.FindOneAndUpdate().SetUpsert(upsert).SetReturnDocument(options.After)
+	return mgo.db.Collection(collection).FindOneAndUpdate(ctx, query, update, opts).Decode(result)
+}
+
 func (mgo *mongoHandler) StartSession() (repository.Session, error) {
 	session, err := mgo.client.StartSession()
 	if err != nil {
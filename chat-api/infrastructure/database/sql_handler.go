@@ -0,0 +1,165 @@
+package database
+
+import (
+	"chat-api/adapter/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+type dialect struct {
+	driver string
+	// placeholder numbers the bind parameters for drivers that do not accept ?
+	placeholder func(int) string
+	// timestamp is the column type times are stored in
+	timestamp      string
+	isDuplicateKey func(error) bool
+}
+
+var (
+	postgresDialect = dialect{
+		driver:      "postgres",
+		placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		timestamp:   "TIMESTAMPTZ",
+		isDuplicateKey: func(err error) bool {
+			pqErr, ok := err.(*pq.Error)
+			return ok && pqErr.Code == "23505"
+		},
+	}
+
+	sqliteDialect = dialect{
+		driver:    "sqlite3",
+		timestamp: "TIMESTAMP",
+		isDuplicateKey: func(err error) bool {
+			sqliteErr, ok := err.(sqlite3.Error)
+			return ok && (sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+				sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey)
+		},
+	}
+)
+
+type sqlTxKey struct{}
+
+// executor is satisfied by both *sql.DB and *sql.Tx
+type executor interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+type sqlHandler struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+func NewPostgresHandler(c *config) (*sqlHandler, error) {
+	return newSQLHandler(postgresDialect, c)
+}
+
+func NewSQLiteHandler(c *config) (*sqlHandler, error) {
+	return newSQLHandler(sqliteDialect, c)
+}
+
+func newSQLHandler(d dialect, c *config) (*sqlHandler, error) {
+	db, err := sql.Open(d.driver, c.uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.driver == sqliteDialect.driver {
+		// SQLite takes one writer at a time, and every connection to an
+		// in-memory database would open a database of its own
+		db.SetMaxOpenConns(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.ctxTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	handler := &sqlHandler{db: db, dialect: d}
+	if err := handler.migrate(ctx); err != nil {
+		return nil, fmt.Errorf("error migrating %s schema: %w", d.driver, err)
+	}
+	return handler, nil
+}
+
+func (s sqlHandler) Execute(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	result, err := s.executor(ctx).ExecContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return 0, s.translate(err)
+	}
+	return result.RowsAffected()
+}
+
+func (s sqlHandler) Query(ctx context.Context, query string, args ...interface{}) (repository.Rows, error) {
+	rows, err := s.executor(ctx).QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, s.translate(err)
+	}
+	return rows, nil
+}
+
+func (s sqlHandler) QueryRow(ctx context.Context, query string, args ...interface{}) repository.Row {
+	return s.executor(ctx).QueryRowContext(ctx, s.rebind(query), args...)
+}
+
+func (s sqlHandler) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	// Nested calls join the transaction already running
+	if _, ok := ctx.Value(sqlTxKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, sqlTxKey{}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s sqlHandler) executor(ctx context.Context) executor {
+	if tx, ok := ctx.Value(sqlTxKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return s.db
+}
+
+// rebind turns ? placeholders into the style of the driver
+func (s sqlHandler) rebind(query string) string {
+	if s.dialect.placeholder == nil {
+		return query
+	}
+
+	var (
+		b strings.Builder
+		n int
+	)
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString(s.dialect.placeholder(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s sqlHandler) translate(err error) error {
+	if s.dialect.isDuplicateKey(err) {
+		return fmt.Errorf("%w: %v", repository.ErrDuplicateKey, err)
+	}
+	return err
+}
@@ -0,0 +1,862 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"chat-api/adapter/repository"
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type repositoryBackend struct {
+	name     string
+	users    domain.UserRepository
+	channels domain.ChannelRepository
+	// search builds a search index over the channels, a new one starts empty
+	// where the index is kept in process
+	search    func() domain.SearchIndex
+	analytics domain.AnalyticsRepository
+}
+
+// repositoryBackends lists every backend the user and channel repositories run
+// on, Postgres only when POSTGRES_TEST_DSN points at a scratch database
+func repositoryBackends(t *testing.T) []repositoryBackend {
+	memory := NewMemoryHandler()
+	sqlite, err := NewSQLiteHandler(&config{uri: ":memory:", ctxTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("[TestCase 'sqlite'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	backends := []repositoryBackend{
+		{
+			name:      "memory",
+			users:     repository.NewUserNoSQL(memory),
+			channels:  repository.NewChannelNoSQL(memory),
+			search:    func() domain.SearchIndex { return repository.NewSearchIndexNoSQL(memory) },
+			analytics: repository.NewAnalyticsNoSQL(memory),
+		},
+		{
+			name:      "sqlite",
+			users:     repository.NewUserSQL(sqlite),
+			channels:  repository.NewChannelSQL(sqlite),
+			search:    func() domain.SearchIndex { return repository.NewSearchIndexInverted(repository.NewChannelSQL(sqlite)) },
+			analytics: repository.NewAnalyticsSQL(sqlite),
+		},
+	}
+
+	if dsn := os.Getenv("POSTGRES_TEST_DSN"); dsn != "" {
+		postgres, err := NewPostgresHandler(&config{uri: dsn, ctxTimeout: 5 * time.Second})
+		if err != nil {
+			t.Fatalf("[TestCase 'postgres'] Result: '%v' | Expected: '%v'", err, nil)
+		}
+		for _, table := range []string{"channel_ratings", "channel_attributes", "channel_tags", "channel_read_markers", "channel_message_attachments", "channel_message_edits", "channel_messages", "channel_status_history", "channels", "users"} {
+			if _, err := postgres.Execute(context.Background(), "DELETE FROM "+table); err != nil {
+				t.Fatalf("[TestCase 'postgres'] Result: '%v' | Expected: '%v'", err, nil)
+			}
+		}
+		backends = append(backends, repositoryBackend{
+			name:     "postgres",
+			users:    repository.NewUserSQL(postgres),
+			channels: repository.NewChannelSQL(postgres),
+			search: func() domain.SearchIndex {
+				return repository.NewSearchIndexInverted(repository.NewChannelSQL(postgres))
+			},
+			analytics: repository.NewAnalyticsSQL(postgres),
+		})
+	}
+	return backends
+}
+
+func TestRepositories_Users(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range repositoryBackends(t) {
+		var (
+			acme   = domain.WithTenant(context.Background(), "acme")
+			globex = domain.WithTenant(context.Background(), "globex")
+			now    = time.Now().UTC().Truncate(time.Millisecond)
+			users  = backend.users
+		)
+
+		for i, email := range []string{"first@email.com", "second@email.com", "admin@email.com"} {
+			user := domain.NewUser(primitive.NewObjectID(), "first", "last", email, "hash", now.Add(time.Duration(i)*time.Second), now)
+			user.UpdateRole(domain.USER)
+			if email == "admin@email.com" {
+				user.UpdateRole(domain.ADMIN)
+			}
+			if _, err := users.CreateUser(acme, user); err != nil {
+				t.Fatalf("[TestCase '%s create user'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+		}
+
+		duplicate := domain.NewUser(primitive.NewObjectID(), "first", "last", "FIRST@email.com", "hash", now, now)
+		if _, err := users.CreateUser(acme, duplicate); err != domain.ErrUserAlreadyExists {
+			t.Errorf("[TestCase '%s duplicate email'] Result: '%v' | Expected: '%v'", backend.name, err, domain.ErrUserAlreadyExists)
+		}
+		if _, err := users.CreateUser(globex, duplicate); err != nil {
+			t.Errorf("[TestCase '%s same email in another tenant'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+
+		found, err := users.GetUserByEmail(acme, "First@Email.com")
+		if err != nil || found.Email() != "first@email.com" || found.Role() != domain.USER || found.TenantId() != "acme" {
+			t.Errorf("[TestCase '%s get user by email'] Result: '%v', '%v' | Expected: '%v'", backend.name, found.Email(), err, "first@email.com")
+		}
+		if !found.CreatedAt().Equal(now) {
+			t.Errorf("[TestCase '%s created at'] Result: '%v' | Expected: '%v'", backend.name, found.CreatedAt(), now)
+		}
+		if batch, err := users.GetUsersByEmails(acme, []string{"Second@email.com", "admin@email.com", "missing@email.com"}); err != nil || len(batch) != 2 {
+			t.Errorf("[TestCase '%s get users by emails'] Result: '%v', '%v' | Expected: '%v'", backend.name, len(batch), err, 2)
+		}
+		if batch, err := users.GetUsersByEmails(globex, []string{"second@email.com"}); err != nil || len(batch) != 0 {
+			t.Errorf("[TestCase '%s get users by emails of another tenant'] Result: '%v', '%v' | Expected: '%v'", backend.name, len(batch), err, 0)
+		}
+		if batch, err := users.GetUsersByEmails(acme, nil); err != nil || len(batch) != 0 {
+			t.Errorf("[TestCase '%s get users by no emails'] Result: '%v', '%v' | Expected: '%v'", backend.name, len(batch), err, 0)
+		}
+		if _, err := users.GetUserByEmail(globex, "second@email.com"); err != domain.ErrUserNotFound {
+			t.Errorf("[TestCase '%s user of another tenant'] Result: '%v' | Expected: '%v'", backend.name, err, domain.ErrUserNotFound)
+		}
+
+		found.UpdateProfile("new", "name", now)
+		found.UpdatePassword("new-hash", now)
+		found.UpdateTwoFactor("secret", true, []string{"a", "b"})
+		found.Deactivate(now)
+		found.UpdateRole(domain.ADMIN)
+		for name, update := range map[string]func(context.Context, domain.User) error{
+			"profile":    users.UpdateProfile,
+			"password":   users.UpdatePassword,
+			"two factor": users.UpdateTwoFactor,
+			"activation": users.UpdateActivation,
+			"role":       users.UpdateRole,
+		} {
+			if err := update(acme, found); err != nil {
+				t.Errorf("[TestCase '%s update %s'] Result: '%v' | Expected: '%v'", backend.name, name, err, nil)
+			}
+		}
+
+		updated, _ := users.GetUserByEmail(acme, "first@email.com")
+		if updated.FirstName() != "new" || updated.Password() != "new-hash" || !updated.TwoFactorEnabled() ||
+			len(updated.RecoveryCodes()) != 2 || updated.IsActive() || updated.Role() != domain.ADMIN {
+			t.Errorf("[TestCase '%s updates'] Result: '%v' | Expected: '%v'", backend.name, updated, found)
+		}
+
+		admins, err := users.GetUsers(acme, domain.ADMIN, 0, 10)
+		if err != nil || len(admins) != 2 || admins[0].Email() != "admin@email.com" {
+			t.Errorf("[TestCase '%s get users by role'] Result: '%v', '%v' | Expected: '%v'", backend.name, len(admins), err, 2)
+		}
+		if page, _ := users.GetUsers(acme, "", 1, 1); len(page) != 1 || page[0].Email() != "second@email.com" {
+			t.Errorf("[TestCase '%s get users page'] Result: '%v' | Expected: '%v'", backend.name, page, "second@email.com")
+		}
+		if count, err := users.GetUsersCount(acme, ""); err != nil || count != 3 {
+			t.Errorf("[TestCase '%s count users'] Result: '%v', '%v' | Expected: '%v'", backend.name, count, err, 3)
+		}
+	}
+}
+
+func TestRepositories_Channels(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range repositoryBackends(t) {
+		var (
+			acme   = domain.WithTenant(context.Background(), "acme")
+			globex = domain.WithTenant(context.Background(), "globex")
+			// Created an hour ago so the updates made below stand out
+			now      = time.Now().UTC().Add(-time.Hour).Truncate(time.Millisecond)
+			channels = backend.channels
+			created  []domain.Channel
+		)
+
+		for i, email := range []string{"first@email.com", "second@email.com", "first@email.com"} {
+			channel := domain.NewChannel(primitive.NewObjectID(), email, domain.ACTIVE, now.Add(time.Duration(i)*time.Second), now)
+			channel.UpdateStatus(domain.ACTIVE, email, now.Unix())
+			if i == 1 {
+				_ = channel.UpdatePriority(domain.PriorityUrgent)
+				_ = channel.UpdateAttributes(map[string]string{"plan": "pro"})
+			}
+			if i == 2 {
+				channel.MarkGuest()
+			}
+			channel, err := channels.CreateChannel(acme, channel)
+			if err != nil {
+				t.Fatalf("[TestCase '%s create channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+			created = append(created, channel)
+		}
+
+		channel := created[0]
+		channel.UpdateRepEmail("rep@email.com")
+		channel.UpdateStatus(domain.IN_PROGRESS, "rep@email.com", now.Unix())
+		channel.AddMessage("first@email.com", "hello", now)
+		channel.AddMessage("rep@email.com", "hi", now.Add(time.Second))
+		if err := channels.UpdateChannelStatus(acme, channel); err != nil {
+			t.Fatalf("[TestCase '%s update channel status'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		if err := channels.AddMessage(acme, channel); err != nil {
+			t.Fatalf("[TestCase '%s add message'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		if err := channels.AddMessage(globex, created[1]); err != nil {
+			t.Errorf("[TestCase '%s add message in another tenant'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+
+		found, err := channels.GetChannelById(acme, channel.Id().Hex())
+		if err != nil || found.RepEmail() != "rep@email.com" || found.CurrentStatus() != domain.IN_PROGRESS {
+			t.Errorf("[TestCase '%s get channel by id'] Result: '%v', '%v' | Expected: '%v'", backend.name, found.CurrentStatus(), err, domain.IN_PROGRESS)
+		}
+		if len(found.StatusHistory()) != 2 || len(found.Messages()) != 2 || found.Messages()[1].Message != "hi" {
+			t.Errorf("[TestCase '%s history and messages'] Result: '%v', '%v' | Expected: '%v'", backend.name, found.StatusHistory(), found.Messages(), 2)
+		}
+		if _, err := channels.GetChannelById(globex, channel.Id().Hex()); err != domain.ErrUserNotFound {
+			t.Errorf("[TestCase '%s channel of another tenant'] Result: '%v' | Expected: '%v'", backend.name, err, domain.ErrUserNotFound)
+		}
+
+		tagged := found
+		tagged.AddTags("billing", "vip")
+		if err := channels.UpdateChannelDetails(acme, tagged); err != nil {
+			t.Errorf("[TestCase '%s update channel tags'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		tagged.RemoveTags("billing")
+		tagged.AddTags("refund")
+		_ = tagged.UpdatePriority(domain.PriorityHigh)
+		_ = tagged.UpdateAttributes(map[string]string{"plan": "pro", "orderId": "A-1"})
+		if err := channels.UpdateChannelDetails(acme, tagged); err != nil {
+			t.Errorf("[TestCase '%s replace channel tags'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		if tagged, _ = channels.GetChannelById(acme, channel.Id().Hex()); !reflect.DeepEqual(tagged.Tags(), []string{"vip", "refund"}) {
+			t.Errorf("[TestCase '%s channel tags'] Result: '%v' | Expected: '%v'", backend.name, tagged.Tags(), []string{"vip", "refund"})
+		}
+		if tagged.Priority() != domain.PriorityHigh || tagged.Attributes()["orderId"] != "A-1" {
+			t.Errorf("[TestCase '%s channel details'] Result: '%v', '%v' | Expected: '%v', '%v'", backend.name, tagged.Priority(), tagged.Attributes(), domain.PriorityHigh, "A-1")
+		}
+		if urgent, _ := channels.GetChannelById(acme, created[1].Id().Hex()); urgent.Priority() != domain.PriorityUrgent || urgent.Attributes()["plan"] != "pro" {
+			t.Errorf("[TestCase '%s details at creation'] Result: '%v', '%v' | Expected: '%v', '%v'", backend.name, urgent.Priority(), urgent.Attributes(), domain.PriorityUrgent, "pro")
+		}
+		if listed, _ := channels.GetChannelsByQuery(acme, domain.ChannelQuery{Tags: []string{"vip"}}); len(listed) != 1 || !reflect.DeepEqual(listed[0].Tags(), tagged.Tags()) || len(listed[0].Attributes()) != 2 {
+			t.Errorf("[TestCase '%s listed details'] Result: '%v' | Expected: '%v'", backend.name, listed, tagged.Tags())
+		}
+
+		after := func(channel domain.Channel, sortBy string, descending bool) *domain.ChannelCursor {
+			if sortBy == domain.ChannelSortPriority {
+				return &domain.ChannelCursor{SortBy: sortBy, SortDescending: descending, Rank: channel.PriorityRank(), Id: channel.Id()}
+			}
+			return &domain.ChannelCursor{SortBy: sortBy, SortDescending: descending, Value: channel.SortValue(sortBy), Id: channel.Id()}
+		}
+
+		type testCase struct {
+			name     string
+			query    domain.ChannelQuery
+			expected []domain.Channel
+		}
+
+		tests := []testCase{
+			{name: "every channel", query: domain.ChannelQuery{}, expected: created},
+			{name: "by user", query: domain.ChannelQuery{UserEmail: "first@email.com"}, expected: []domain.Channel{created[0], created[2]}},
+			{name: "by rep and status", query: domain.ChannelQuery{RepEmail: "rep@email.com", Statuses: []string{domain.IN_PROGRESS}}, expected: created[:1]},
+			{name: "newest first", query: domain.ChannelQuery{SortDescending: true}, expected: []domain.Channel{created[2], created[1], created[0]}},
+			{name: "page", query: domain.ChannelQuery{Skip: 1, Limit: 1}, expected: created[1:2]},
+			{name: "skip without limit", query: domain.ChannelQuery{Skip: 2}, expected: created[2:]},
+			{name: "any of the statuses", query: domain.ChannelQuery{Statuses: []string{domain.ACTIVE, domain.IN_PROGRESS}}, expected: created},
+			{name: "unassigned", query: domain.ChannelQuery{Unassigned: true}, expected: created[1:]},
+			{name: "created range", query: domain.ChannelQuery{CreatedFrom: now.Add(time.Second), CreatedTo: now.Add(2 * time.Second)}, expected: created[1:2]},
+			{name: "updated since", query: domain.ChannelQuery{UpdatedFrom: now.Add(time.Minute)}, expected: created[:1]},
+			{name: "latest message first", query: domain.ChannelQuery{SortBy: domain.ChannelSortLastMessageAt, SortDescending: true}, expected: []domain.Channel{created[0], created[2], created[1]}},
+			{name: "least recently updated", query: domain.ChannelQuery{SortBy: domain.ChannelSortUpdatedAt}, expected: []domain.Channel{created[1], created[2], created[0]}},
+			{name: "with every tag", query: domain.ChannelQuery{Tags: []string{"VIP", "refund"}}, expected: created[:1]},
+			{name: "with a missing tag", query: domain.ChannelQuery{Tags: []string{"vip", "billing"}}, expected: nil},
+			{name: "any of the priorities", query: domain.ChannelQuery{Priorities: []string{domain.PriorityHigh, domain.PriorityUrgent}}, expected: created[:2]},
+			{name: "unprioritized channels", query: domain.ChannelQuery{Priorities: []string{domain.PriorityNormal}}, expected: created[2:]},
+			{name: "by attribute", query: domain.ChannelQuery{Attributes: map[string]string{"plan": "pro"}}, expected: created[:2]},
+			{name: "by every attribute", query: domain.ChannelQuery{Attributes: map[string]string{"plan": "pro", "orderId": "A-1"}}, expected: created[:1]},
+			{name: "name and value of different attributes", query: domain.ChannelQuery{Attributes: map[string]string{"orderId": "pro"}}, expected: nil},
+			{name: "most urgent first", query: domain.ChannelQuery{SortBy: domain.ChannelSortPriority, SortDescending: true}, expected: []domain.Channel{created[1], created[0], created[2]}},
+			{
+				name:     "after a cursor",
+				query:    domain.ChannelQuery{SortBy: domain.ChannelSortCreatedAt, After: after(created[0], domain.ChannelSortCreatedAt, false), Limit: 1},
+				expected: created[1:2],
+			},
+			{
+				name:     "after a cursor newest first",
+				query:    domain.ChannelQuery{SortBy: domain.ChannelSortCreatedAt, SortDescending: true, After: after(created[2], domain.ChannelSortCreatedAt, true)},
+				expected: []domain.Channel{created[1], created[0]},
+			},
+			{
+				name:     "after a cursor most urgent first",
+				query:    domain.ChannelQuery{SortBy: domain.ChannelSortPriority, SortDescending: true, After: after(created[1], domain.ChannelSortPriority, true)},
+				expected: []domain.Channel{created[0], created[2]},
+			},
+			{
+				name:     "after a channel without messages",
+				query:    domain.ChannelQuery{SortBy: domain.ChannelSortLastMessageAt, After: after(created[1], domain.ChannelSortLastMessageAt, false)},
+				expected: []domain.Channel{created[2], channel},
+			},
+			{
+				name:     "after a channel with messages latest first",
+				query:    domain.ChannelQuery{SortBy: domain.ChannelSortLastMessageAt, SortDescending: true, After: after(channel, domain.ChannelSortLastMessageAt, true)},
+				expected: []domain.Channel{created[2], created[1]},
+			},
+			{
+				name:     "after the last channel without messages latest first",
+				query:    domain.ChannelQuery{SortBy: domain.ChannelSortLastMessageAt, SortDescending: true, After: after(created[2], domain.ChannelSortLastMessageAt, true)},
+				expected: created[1:2],
+			},
+		}
+
+		for _, tt := range tests {
+			result, err := channels.GetChannelsByQuery(acme, tt.query)
+			if err != nil || len(result) != len(tt.expected) {
+				t.Errorf("[TestCase '%s %s'] Result: '%v', '%v' | Expected: '%v'", backend.name, tt.name, len(result), err, len(tt.expected))
+				continue
+			}
+			for i := range result {
+				if result[i].Id() != tt.expected[i].Id() {
+					t.Errorf("[TestCase '%s %s'] Result: '%v' | Expected: '%v'", backend.name, tt.name, result[i].Id(), tt.expected[i].Id())
+				}
+			}
+
+			if tt.query.Limit > 0 || tt.query.Skip > 0 || tt.query.After != nil {
+				continue
+			}
+			if count, err := channels.GetChannelsByQueryCount(acme, tt.query); err != nil || count != int64(len(tt.expected)) {
+				t.Errorf("[TestCase '%s %s count'] Result: '%v', '%v' | Expected: '%v'", backend.name, tt.name, count, err, len(tt.expected))
+			}
+		}
+
+		if result, _ := channels.GetChannelsByQuery(globex, domain.ChannelQuery{}); len(result) != 0 {
+			t.Errorf("[TestCase '%s query another tenant'] Result: '%v' | Expected: '%v'", backend.name, len(result), 0)
+		}
+
+		if err := channels.MergeGuestChannel(acme, "second@email.com", created[2].Id().Hex()); err != nil {
+			t.Errorf("[TestCase '%s merge guest channel of another email'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		if kept, _ := channels.GetChannelById(acme, created[2].Id().Hex()); !kept.IsGuest() {
+			t.Errorf("[TestCase '%s merge guest channel of another email'] Result: '%v' | Expected: '%v'", backend.name, kept.IsGuest(), true)
+		}
+		if err := channels.MergeGuestChannel(acme, "first@email.com", created[2].Id().Hex()); err != nil {
+			t.Errorf("[TestCase '%s merge guest channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		if merged, _ := channels.GetChannelById(acme, created[2].Id().Hex()); merged.IsGuest() {
+			t.Errorf("[TestCase '%s merge guest channel'] Result: '%v' | Expected: '%v'", backend.name, merged.IsGuest(), false)
+		}
+	}
+}
+
+func TestRepositories_Ratings(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range repositoryBackends(t) {
+		var (
+			acme     = domain.WithTenant(context.Background(), "acme")
+			globex   = domain.WithTenant(context.Background(), "globex")
+			now      = time.Now().UTC().Truncate(time.Millisecond)
+			channels = backend.channels
+			rated    []domain.Channel
+		)
+
+		for i, rep := range []string{"rep@email.com", "other@email.com"} {
+			channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, now, now)
+			channel.UpdateStatus(domain.ACTIVE, "user@email.com", now.Unix())
+			if i == 0 {
+				channel.AddTags("refund", "vip")
+			}
+			channel, err := channels.CreateChannel(acme, channel)
+			if err != nil {
+				t.Fatalf("[TestCase '%s create channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+			channel.UpdateRepEmail(rep)
+			channel.UpdateStatus(domain.COMPLETE, rep, now.Unix())
+			if err := channels.UpdateChannelStatus(acme, channel); err != nil {
+				t.Fatalf("[TestCase '%s complete channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+			if _, err := channel.Rate(5-2*i, "thanks", "user@email.com", now.Add(time.Duration(i)*time.Hour)); err != nil {
+				t.Fatalf("[TestCase '%s rate channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+			if err := channels.UpdateRatings(acme, channel); err != nil {
+				t.Errorf("[TestCase '%s store rating'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+			rated = append(rated, channel)
+		}
+
+		found, err := channels.GetChannelById(acme, rated[0].Id().Hex())
+		if err != nil || !reflect.DeepEqual(found.Ratings(), rated[0].Ratings()) || found.SurveyPending() {
+			t.Errorf("[TestCase '%s read ratings'] Result: '%v', '%v' | Expected: '%v'", backend.name, found.Ratings(), err, rated[0].Ratings())
+		}
+		if _, err := found.Rate(1, "", "user@email.com", now); err != domain.ErrAlreadyRated {
+			t.Errorf("[TestCase '%s rate twice'] Result: '%v' | Expected: '%v'", backend.name, err, domain.ErrAlreadyRated)
+		}
+
+		tests := []struct {
+			name     string
+			query    domain.SatisfactionQuery
+			expected []int
+		}{
+			{name: "range", query: domain.SatisfactionQuery{From: now, To: now.Add(2 * time.Hour)}, expected: []int{5, 3}},
+			{name: "range end is exclusive", query: domain.SatisfactionQuery{From: now, To: now.Add(time.Hour)}, expected: []int{5}},
+			{name: "by rep", query: domain.SatisfactionQuery{From: now, To: now.Add(2 * time.Hour), RepEmail: "other@email.com"}, expected: []int{3}},
+			{name: "by tag", query: domain.SatisfactionQuery{From: now, To: now.Add(2 * time.Hour), Tag: "refund"}, expected: []int{5}},
+		}
+		for _, tt := range tests {
+			ratings, err := channels.GetSatisfactionRatings(acme, tt.query)
+			var scores []int
+			for _, rating := range ratings {
+				scores = append(scores, rating.Score)
+			}
+			if err != nil || !reflect.DeepEqual(scores, tt.expected) {
+				t.Errorf("[TestCase '%s %s'] Result: '%v', '%v' | Expected: '%v'", backend.name, tt.name, scores, err, tt.expected)
+			}
+		}
+
+		if ratings, _ := channels.GetSatisfactionRatings(acme, tests[0].query); len(ratings) > 0 &&
+			(!reflect.DeepEqual(ratings[0].Tags, []string{"refund", "vip"}) || ratings[0].RepEmail != "rep@email.com") {
+			t.Errorf("[TestCase '%s rating tags'] Result: '%v' | Expected: '%v'", backend.name, ratings[0], []string{"refund", "vip"})
+		}
+		if ratings, _ := channels.GetSatisfactionRatings(globex, tests[0].query); len(ratings) != 0 {
+			t.Errorf("[TestCase '%s ratings of another tenant'] Result: '%v' | Expected: '%v'", backend.name, len(ratings), 0)
+		}
+	}
+}
+
+func TestRepositories_SLA(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range repositoryBackends(t) {
+		var (
+			acme     = domain.WithTenant(context.Background(), "acme")
+			now      = time.Now().UTC().Truncate(time.Second)
+			channels = backend.channels
+		)
+
+		channel, err := channels.CreateChannel(acme, domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, now, now))
+		if err != nil {
+			t.Fatalf("[TestCase '%s create channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		channel.AddMessage("user@email.com", "my order is late", now.Add(time.Minute))
+		channel.AddNote("rep@email.com", "customer verified", now.Add(2*time.Minute))
+		channel.AddMessage("rep@email.com", "looking into it", now.Add(3*time.Minute))
+		if err := channels.AddMessage(acme, channel); err != nil {
+			t.Fatalf("[TestCase '%s add messages'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		channel.UpdateStatus(domain.COMPLETE, "rep@email.com", now.Add(time.Hour).Unix())
+		if err := channels.UpdateChannelStatus(acme, channel); err != nil {
+			t.Fatalf("[TestCase '%s complete channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+
+		// Listings read the stored clocks, the channel itself derives them from its history
+		listed, err := channels.GetChannelsByQuery(acme, domain.ChannelQuery{SortBy: domain.ChannelSortCreatedAt})
+		if err != nil || len(listed) != 1 {
+			t.Fatalf("[TestCase '%s list channels'] Result: '%v', '%v' | Expected: '%v'", backend.name, len(listed), err, 1)
+		}
+		found, err := channels.GetChannelById(acme, channel.Id().Hex())
+		if err != nil {
+			t.Fatalf("[TestCase '%s read channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		for name, read := range map[string]domain.Channel{"listed": listed[0], "read": found} {
+			if !read.FirstResponseAt().Equal(now.Add(3*time.Minute)) || !read.ResolvedAt().Equal(now.Add(time.Hour)) {
+				t.Errorf("[TestCase '%s %s clocks'] Result: '%v', '%v' | Expected: '%v', '%v'", backend.name, name, read.FirstResponseAt(), read.ResolvedAt(), now.Add(3*time.Minute), now.Add(time.Hour))
+			}
+		}
+
+		// The breach check may hold a read of the channel from before it was reopened
+		stale := found
+		found.UpdateStatus(domain.IN_PROGRESS, "user@email.com", now.Add(2*time.Hour).Unix())
+		if err := channels.UpdateChannelStatus(acme, found); err != nil {
+			t.Fatalf("[TestCase '%s reopen channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		for _, breaches := range [][]string{{domain.SLAResolution}, {domain.SLAResolution, domain.SLAFirstResponse}} {
+			if err := channels.AddSLABreaches(acme, stale, breaches); err != nil {
+				t.Fatalf("[TestCase '%s store breaches'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+		}
+
+		expected := []string{domain.SLAResolution, domain.SLAFirstResponse}
+		listed, _ = channels.GetChannelsByQuery(acme, domain.ChannelQuery{SortBy: domain.ChannelSortCreatedAt})
+		if len(listed) != 1 || !listed[0].ResolvedAt().IsZero() || !listed[0].FirstResponseAt().Equal(now.Add(3*time.Minute)) ||
+			!reflect.DeepEqual(listed[0].SLABreaches(), expected) {
+			t.Errorf("[TestCase '%s reopened'] Result: '%v' | Expected: '%v'", backend.name, listed, expected)
+		}
+	}
+}
+
+func TestRepositories_Analytics(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("[TestCase 'load time zone'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	for _, backend := range repositoryBackends(t) {
+		var (
+			acme   = domain.WithTenant(context.Background(), "acme")
+			globex = domain.WithTenant(context.Background(), "globex")
+			// 21:30 on March 1st in New York, already March 2nd in UTC
+			opened = time.Date(2021, 3, 2, 2, 30, 0, 0, time.UTC)
+			query  = domain.AnalyticsQuery{
+				From:     time.Date(2021, 3, 1, 0, 0, 0, 0, newYork),
+				To:       time.Date(2021, 3, 4, 0, 0, 0, 0, newYork),
+				Interval: domain.AnalyticsByDay,
+				Location: newYork,
+			}
+		)
+
+		create := func(ctx context.Context, rep, status string, createdAt time.Time, tags ...string) domain.Channel {
+			channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", status, createdAt, createdAt)
+			channel.UpdateRepEmail(rep)
+			channel.AddTags(tags...)
+			created, err := backend.channels.CreateChannel(ctx, channel)
+			if err != nil {
+				t.Fatalf("[TestCase '%s create channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+			return created
+		}
+
+		resolved := create(acme, "rep@email.com", domain.ACTIVE, opened, "billing")
+		resolved.AddMessage("user@email.com", "my card was charged twice", opened.Add(time.Minute))
+		resolved.AddNote("rep@email.com", "refund approved", opened.Add(2*time.Minute))
+		resolved.AddMessage("rep@email.com", "refunded", opened.Add(10*time.Minute))
+		if err := backend.channels.AddMessage(acme, resolved); err != nil {
+			t.Fatalf("[TestCase '%s add messages'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		resolved.UpdateStatus(domain.COMPLETE, "rep@email.com", opened.Add(time.Hour).Unix())
+		if err := backend.channels.UpdateChannelStatus(acme, resolved); err != nil {
+			t.Fatalf("[TestCase '%s complete channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+
+		waiting := create(acme, "other@email.com", domain.IN_PROGRESS, opened.Add(25*time.Hour))
+		waiting.AddMessage("user@email.com", "where is my order", opened.Add(25*time.Hour))
+		if err := backend.channels.AddMessage(acme, waiting); err != nil {
+			t.Fatalf("[TestCase '%s add messages'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		create(acme, "", domain.INACTIVE, opened.Add(25*time.Hour))
+		create(globex, "rep@email.com", domain.ACTIVE, opened)
+
+		buckets, err := backend.analytics.GetChannelBuckets(acme, query)
+		if err != nil {
+			t.Fatalf("[TestCase '%s channel buckets'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		expected := []domain.ChannelBucket{
+			{
+				Start:               time.Date(2021, 3, 1, 0, 0, 0, 0, newYork),
+				Opened:              1,
+				Messages:            2,
+				Answered:            1,
+				Closed:              1,
+				MedianFirstResponse: 10 * time.Minute,
+				MedianResolution:    time.Hour,
+			},
+			{Start: time.Date(2021, 3, 2, 0, 0, 0, 0, newYork), Opened: 2, Messages: 1},
+		}
+		if len(buckets) != len(expected) {
+			t.Fatalf("[TestCase '%s channel buckets'] Result: '%+v' | Expected: '%+v'", backend.name, buckets, expected)
+		}
+		for i := range expected {
+			// Backends differ on the location of the start
+			got := buckets[i]
+			got.Start = got.Start.In(newYork)
+			if !reflect.DeepEqual(got, expected[i]) {
+				t.Errorf("[TestCase '%s channel bucket %d'] Result: '%+v' | Expected: '%+v'", backend.name, i, got, expected[i])
+			}
+		}
+
+		hourly := query
+		hourly.Interval, hourly.Tag = domain.AnalyticsByHour, "Billing"
+		buckets, err = backend.analytics.GetChannelBuckets(acme, hourly)
+		if err != nil || len(buckets) != 2 || !buckets[0].Start.Equal(opened.Truncate(time.Hour)) || buckets[0].Opened != 1 || buckets[1].Closed != 1 {
+			t.Errorf("[TestCase '%s hourly tagged buckets'] Result: '%+v', '%v' | Expected: '%v'", backend.name, buckets, err, "opened and closed an hour apart")
+		}
+
+		backlog, err := backend.analytics.GetBacklog(acme, "")
+		if expected := map[string]int{domain.IN_PROGRESS: 1, domain.INACTIVE: 1}; err != nil || !reflect.DeepEqual(backlog, expected) {
+			t.Errorf("[TestCase '%s backlog'] Result: '%v', '%v' | Expected: '%v'", backend.name, backlog, err, expected)
+		}
+
+		reps, err := backend.analytics.GetRepBuckets(acme, query)
+		if err != nil || len(reps) != 1 || reps[0].RepEmail != "rep@email.com" || reps[0].Resolved != 1 ||
+			!reps[0].Start.Equal(time.Date(2021, 3, 1, 0, 0, 0, 0, newYork)) {
+			t.Errorf("[TestCase '%s rep buckets'] Result: '%+v', '%v' | Expected: '%v'", backend.name, reps, err, "one resolved by rep@email.com")
+		}
+
+		resolutions, err := backend.analytics.GetRepResolutions(acme, query)
+		if expected := []domain.RepResolution{{RepEmail: "rep@email.com", Resolved: 1, MedianResolution: time.Hour}}; err != nil || !reflect.DeepEqual(resolutions, expected) {
+			t.Errorf("[TestCase '%s rep resolutions'] Result: '%+v', '%v' | Expected: '%+v'", backend.name, resolutions, err, expected)
+		}
+
+		workload, err := backend.analytics.GetRepWorkload(acme, "")
+		if expected := []domain.RepWorkload{{RepEmail: "other@email.com", Status: domain.IN_PROGRESS, Count: 1}}; err != nil || !reflect.DeepEqual(workload, expected) {
+			t.Errorf("[TestCase '%s rep workload'] Result: '%v', '%v' | Expected: '%v'", backend.name, workload, err, expected)
+		}
+	}
+}
+
+func TestRepositories_Messages(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range repositoryBackends(t) {
+		var (
+			acme  = domain.WithTenant(context.Background(), "acme")
+			now   = time.Now().UTC().Add(-time.Hour).Truncate(time.Millisecond)
+			index = backend.search()
+		)
+
+		channel, err := backend.channels.CreateChannel(acme, domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, now, now))
+		if err != nil {
+			t.Fatalf("[TestCase '%s create channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		channel.AddMessage("user@email.com", "helo", now)
+		channel.AddMessage("user@email.com", "my card number is 4111", now.Add(time.Second))
+		channel.AddMessage("rep@email.com", "hi", now.Add(2*time.Second),
+			domain.MessageAttachment{Id: primitive.NewObjectID(), FileName: "a.png", ContentType: "image/png", Size: 42},
+			domain.MessageAttachment{Id: primitive.NewObjectID(), FileName: "b.pdf", ContentType: "application/pdf", Size: 7},
+		)
+		channel.AddNote("admin@email.com", "card was charged twice", now.Add(1500*time.Millisecond))
+		if err := backend.channels.AddMessage(acme, channel); err != nil {
+			t.Fatalf("[TestCase '%s add message'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+
+		var (
+			messages = channel.Messages()
+			editedAt = now.Add(time.Minute)
+		)
+		for _, text := range []string{"hello", "hello there"} {
+			if _, err := channel.EditMessage(messages[0].Id, text, editedAt); err != nil {
+				t.Fatalf("[TestCase '%s edit message'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+		}
+		if _, err := channel.DeleteMessage(messages[1].Id, editedAt); err != nil {
+			t.Fatalf("[TestCase '%s delete message'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		if err := backend.channels.UpdateMessages(acme, channel); err != nil {
+			t.Fatalf("[TestCase '%s update messages'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		index.IndexChannel(acme, channel)
+
+		found, err := backend.channels.GetChannelById(acme, channel.Id().Hex())
+		if err != nil || len(found.Messages()) != 4 {
+			t.Fatalf("[TestCase '%s get channel by id'] Result: '%v', '%v' | Expected: '%v'", backend.name, len(found.Messages()), err, 4)
+		}
+		for i, message := range found.Messages() {
+			expected := channel.Messages()[i]
+			if !sameMessage(message, expected) {
+				t.Errorf("[TestCase '%s message %d'] Result: '%+v' | Expected: '%+v'", backend.name, i, message, expected)
+			}
+		}
+		if !found.LastMessageAt().Equal(now.Add(2 * time.Second)) {
+			t.Errorf("[TestCase '%s last message at'] Result: '%v' | Expected: '%v'", backend.name, found.LastMessageAt(), now.Add(2*time.Second))
+		}
+
+		// The text of a deleted message is kept for audit but no longer found
+		if _, total, err := index.SearchChannels(acme, domain.SearchQuery{Text: "4111"}); err != nil || total != 0 {
+			t.Errorf("[TestCase '%s search deleted message'] Result: '%v', '%v' | Expected: '%v'", backend.name, total, err, 0)
+		}
+
+		// The customer read up to their own messages, the rep nothing yet
+		channel.MarkRead("user@email.com", now.Add(time.Second))
+		if err := backend.channels.UpdateReadMarkers(acme, channel); err != nil {
+			t.Fatalf("[TestCase '%s update read markers'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		found, err = backend.channels.GetChannelById(acme, channel.Id().Hex())
+		if err != nil || !found.LastReadAt("user@email.com").Equal(now.Add(time.Second)) || len(found.ReadMarkers()) != 1 {
+			t.Errorf("[TestCase '%s read markers'] Result: '%v', '%v' | Expected: '%v'", backend.name, found.ReadMarkers(), err, now.Add(time.Second))
+		}
+		for reader, expected := range map[string]int{"User@Email.com": 1, "rep@email.com": 1, "admin@email.com": 2} {
+			listed, err := backend.channels.GetChannelsByQuery(acme, domain.ChannelQuery{Reader: reader})
+			if err != nil || len(listed) != 1 || listed[0].UnreadCount() != expected {
+				t.Errorf("[TestCase '%s unread by %s'] Result: '%v', '%v' | Expected: '%v'", backend.name, reader, listed, err, expected)
+			}
+		}
+		// Internal notes only count for readers who can see them
+		for reader, expected := range map[string]int{"rep@email.com": 2, "admin@email.com": 2} {
+			listed, err := backend.channels.GetChannelsByQuery(acme, domain.ChannelQuery{Reader: reader, Internal: true})
+			if err != nil || len(listed) != 1 || listed[0].UnreadCount() != expected {
+				t.Errorf("[TestCase '%s internal unread by %s'] Result: '%v', '%v' | Expected: '%v'", backend.name, reader, listed, err, expected)
+			}
+		}
+	}
+}
+
+// sameMessage compares messages read back from a database, whose times may
+// come back in another location
+func sameMessage(x, y domain.Message) bool {
+	if x.Id != y.Id || x.MessageFrom != y.MessageFrom || x.Message != y.Message || x.IsDeleted != y.IsDeleted || x.Internal != y.Internal ||
+		!x.Timestamp.Equal(y.Timestamp) || !x.EditedAt.Equal(y.EditedAt) || !x.DeletedAt.Equal(y.DeletedAt) || len(x.Edits) != len(y.Edits) ||
+		!reflect.DeepEqual(x.Attachments, y.Attachments) {
+		return false
+	}
+	for i := range x.Edits {
+		if x.Edits[i].Message != y.Edits[i].Message || !x.Edits[i].EditedAt.Equal(y.Edits[i].EditedAt) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRepositories_Search(t *testing.T) {
+	t.Parallel()
+
+	for _, backend := range repositoryBackends(t) {
+		var (
+			acme   = domain.WithTenant(context.Background(), "acme")
+			globex = domain.WithTenant(context.Background(), "globex")
+			now    = time.Now().UTC().Add(-time.Hour).Truncate(time.Millisecond)
+			index  = backend.search()
+		)
+
+		write := func(ctx context.Context, email, name, repEmail string, messages ...string) domain.Channel {
+			channel := domain.NewChannel(primitive.NewObjectID(), email, domain.ACTIVE, now, now)
+			channel.UpdateUserFullName(name)
+			if repEmail != "" {
+				channel.UpdateRepEmail(repEmail)
+				channel.UpdateStatus(domain.COMPLETE, repEmail, now.Unix())
+			}
+			channel, err := backend.channels.CreateChannel(ctx, channel)
+			if err != nil {
+				t.Fatalf("[TestCase '%s create channel'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+			for i, message := range messages {
+				now = now.Add(time.Second)
+				channel.AddMessage(email, message, now.Add(time.Duration(i)*time.Millisecond))
+			}
+			if err := backend.channels.AddMessage(ctx, channel); err != nil {
+				t.Fatalf("[TestCase '%s add message'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+			}
+			index.IndexChannel(ctx, channel)
+			return channel
+		}
+
+		var (
+			ana   = write(acme, "ana@email.com", "Ana Lima", "", "I want a refund for order 4411", "thanks")
+			bruno = write(acme, "bruno@email.com", "Bruno Costa", "", "where is my order 5000")
+			carla = write(acme, "carla@email.com", "Carla Dias", "rep@email.com", "Refunds for order 4411 and 5000 arrived")
+			_     = write(globex, "dora@email.com", "Dora Reis", "", "refund order 4411")
+		)
+
+		tests := []struct {
+			name     string
+			query    domain.SearchQuery
+			expected []domain.Channel
+			total    int64
+		}{
+			{name: "every term", query: domain.SearchQuery{Text: "refund 4411"}, expected: []domain.Channel{carla, ana}, total: 2},
+			{name: "customer name", query: domain.SearchQuery{Text: "lima"}, expected: []domain.Channel{ana}, total: 1},
+			{name: "customer email", query: domain.SearchQuery{Text: "bruno@email.com"}, expected: []domain.Channel{bruno}, total: 1},
+			{name: "status", query: domain.SearchQuery{Text: "order", Statuses: []string{domain.COMPLETE}}, expected: []domain.Channel{carla}, total: 1},
+			{name: "rep", query: domain.SearchQuery{Text: "5000", RepEmail: "rep@email.com"}, expected: []domain.Channel{carla}, total: 1},
+			{name: "created range", query: domain.SearchQuery{Text: "order", CreatedTo: now.Add(-time.Hour)}, expected: []domain.Channel{}, total: 0},
+			{name: "page", query: domain.SearchQuery{Text: "order", Skip: 1, Limit: 1}, expected: []domain.Channel{bruno}, total: 3},
+			{name: "nothing found", query: domain.SearchQuery{Text: "invoice"}, expected: []domain.Channel{}, total: 0},
+		}
+
+		for _, tt := range tests {
+			hits, total, err := index.SearchChannels(acme, tt.query)
+			if err != nil || total != tt.total || len(hits) != len(tt.expected) {
+				t.Errorf("[TestCase '%s %s'] Result: '%v', '%v', '%v' | Expected: '%v', '%v'", backend.name, tt.name, len(hits), total, err, len(tt.expected), tt.total)
+				continue
+			}
+			for i := range hits {
+				if hits[i].Channel.Id() != tt.expected[i].Id() {
+					t.Errorf("[TestCase '%s %s'] Result: '%v' | Expected: '%v'", backend.name, tt.name, hits[i].Channel.UserEmail(), tt.expected[i].UserEmail())
+				}
+			}
+		}
+
+		hits, _, _ := index.SearchChannels(acme, domain.SearchQuery{Text: "refund 4411"})
+		expected := []domain.SearchSnippet{{
+			Field:      domain.SearchFieldMessage,
+			Text:       "Refunds for order 4411 and 5000 arrived",
+			Highlights: []domain.SearchHighlight{{Start: 0, End: 7}, {Start: 18, End: 22}},
+		}}
+		if len(hits) == 0 || !reflect.DeepEqual(hits[0].Snippets, expected) {
+			t.Errorf("[TestCase '%s snippets'] Result: '%v' | Expected: '%v'", backend.name, hits, expected)
+		}
+
+		if hits, total, _ := index.SearchChannels(globex, domain.SearchQuery{Text: "refund"}); total != 1 || len(hits) != 1 || hits[0].Channel.UserEmail() != "dora@email.com" {
+			t.Errorf("[TestCase '%s search another tenant'] Result: '%v', '%v' | Expected: '%v'", backend.name, hits, total, 1)
+		}
+		if _, _, err := index.SearchChannels(acme, domain.SearchQuery{Text: "?!"}); err != domain.ErrEmptySearch {
+			t.Errorf("[TestCase '%s empty search'] Result: '%v' | Expected: '%v'", backend.name, err, domain.ErrEmptySearch)
+		}
+
+		// A new index finds what was written before it existed
+		if _, total, err := backend.search().SearchChannels(acme, domain.SearchQuery{Text: "4411"}); err != nil || total != 2 {
+			t.Errorf("[TestCase '%s new index'] Result: '%v', '%v' | Expected: '%v'", backend.name, total, err, 2)
+		}
+
+		bruno.AddMessage("bruno@email.com", "and a refund too", now.Add(time.Minute))
+		if err := backend.channels.AddMessage(acme, bruno); err != nil {
+			t.Fatalf("[TestCase '%s add message'] Result: '%v' | Expected: '%v'", backend.name, err, nil)
+		}
+		index.IndexChannel(acme, bruno)
+		if hits, _, _ := index.SearchChannels(acme, domain.SearchQuery{Text: "refund"}); len(hits) != 3 || hits[0].Channel.Id() != bruno.Id() {
+			t.Errorf("[TestCase '%s reindexed channel'] Result: '%v' | Expected: '%v' first", backend.name, len(hits), bruno.UserEmail())
+		}
+	}
+}
+
+func TestSQLHandler_Migrate(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewSQLiteHandler(&config{uri: ":memory:", ctxTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("[TestCase 'migrate'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	// Running the migrations again finds nothing left to apply
+	if err := db.migrate(context.Background()); err != nil {
+		t.Errorf("[TestCase 'migrate twice'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	var version int
+	if err := db.QueryRow(context.Background(), `SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil || version != len(migrations) {
+		t.Errorf("[TestCase 'schema version'] Result: '%v', '%v' | Expected: '%v'", version, err, len(migrations))
+	}
+}
+
+func TestSQLHandler_Rebind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		dialect  dialect
+		expected string
+	}{
+		{name: "postgres", dialect: postgresDialect, expected: "SELECT * FROM users WHERE tenant_id = $1 AND email = $2"},
+		{name: "sqlite", dialect: sqliteDialect, expected: "SELECT * FROM users WHERE tenant_id = ? AND email = ?"},
+	}
+
+	for _, tt := range tests {
+		result := sqlHandler{dialect: tt.dialect}.rebind("SELECT * FROM users WHERE tenant_id = ? AND email = ?")
+		if result != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestSQLHandler_Transaction(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db, _ = NewSQLiteHandler(&config{uri: ":memory:", ctxTimeout: 5 * time.Second})
+		ctx   = context.Background()
+		now   = time.Now().UTC()
+	)
+
+	err := db.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := db.Execute(ctx, `INSERT INTO users (id, email, created_at, updated_at) VALUES (?, ?, ?, ?)`, "a", "a@email.com", now, now); err != nil {
+			return err
+		}
+		_, err := db.Execute(ctx, `INSERT INTO users (id, email, created_at, updated_at) VALUES (?, ?, ?, ?)`, "b", "a@email.com", now, now)
+		return err
+	})
+	if !errors.Is(err, repository.ErrDuplicateKey) {
+		t.Errorf("[TestCase 'duplicate key'] Result: '%v' | Expected: '%v'", err, repository.ErrDuplicateKey)
+	}
+
+	var count int
+	if err := db.QueryRow(ctx, `SELECT COUNT(*) FROM users`).Scan(&count); err != nil || count != 0 {
+		t.Errorf("[TestCase 'rolled back'] Result: '%v', '%v' | Expected: '%v'", count, err, 0)
+	}
+}
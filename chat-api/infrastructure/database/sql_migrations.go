@@ -0,0 +1,182 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// migrations are applied in order and recorded in schema_migrations, a
+// released migration is never edited, changes go in a new one. TIMESTAMP is
+// replaced by the timestamp type of the dialect
+var migrations = []string{
+	`CREATE TABLE users (
+		id                 VARCHAR(24) PRIMARY KEY,
+		tenant_id          VARCHAR(255) NOT NULL DEFAULT '',
+		first_name         TEXT NOT NULL DEFAULT '',
+		last_name          TEXT NOT NULL DEFAULT '',
+		email              VARCHAR(255) NOT NULL,
+		password           TEXT NOT NULL DEFAULT '',
+		role               VARCHAR(32) NOT NULL DEFAULT '',
+		created_at         TIMESTAMP NOT NULL,
+		updated_at         TIMESTAMP NOT NULL,
+		two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+		two_factor_secret  TEXT NOT NULL DEFAULT '',
+		recovery_codes     TEXT NOT NULL DEFAULT 'null',
+		deactivated        BOOLEAN NOT NULL DEFAULT FALSE,
+		UNIQUE (tenant_id, email)
+	);
+	CREATE INDEX users_tenant_role ON users (tenant_id, role, created_at);`,
+
+	`CREATE TABLE channels (
+		id             VARCHAR(24) PRIMARY KEY,
+		tenant_id      VARCHAR(255) NOT NULL DEFAULT '',
+		user_email     VARCHAR(255) NOT NULL DEFAULT '',
+		rep_email      VARCHAR(255) NOT NULL DEFAULT '',
+		user_full_name TEXT NOT NULL DEFAULT '',
+		guest          BOOLEAN NOT NULL DEFAULT FALSE,
+		current_status VARCHAR(32) NOT NULL DEFAULT '',
+		created_at     TIMESTAMP NOT NULL,
+		updated_at     TIMESTAMP NOT NULL
+	);
+	CREATE INDEX channels_tenant_user_email ON channels (tenant_id, user_email);
+	CREATE INDEX channels_tenant_rep_email ON channels (tenant_id, rep_email);
+	CREATE INDEX channels_tenant_current_status ON channels (tenant_id, current_status);
+
+	CREATE TABLE channel_status_history (
+		channel_id VARCHAR(24) NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+		position   INTEGER NOT NULL,
+		status     VARCHAR(32) NOT NULL,
+		updated_by VARCHAR(255) NOT NULL DEFAULT '',
+		changed_at BIGINT NOT NULL,
+		PRIMARY KEY (channel_id, position)
+	);
+
+	CREATE TABLE channel_messages (
+		channel_id   VARCHAR(24) NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+		position     INTEGER NOT NULL,
+		message_from VARCHAR(255) NOT NULL DEFAULT '',
+		message      TEXT NOT NULL DEFAULT '',
+		sent_at      TIMESTAMP NOT NULL,
+		PRIMARY KEY (channel_id, position)
+	);`,
+
+	// A channel without messages keeps the zero time so it sorts like a
+	// missing field does on MongoDB, first in ascending order
+	`ALTER TABLE channels ADD COLUMN last_message_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+	CREATE INDEX channels_tenant_created_at ON channels (tenant_id, created_at);
+	CREATE INDEX channels_tenant_updated_at ON channels (tenant_id, updated_at);
+	CREATE INDEX channels_tenant_last_message_at ON channels (tenant_id, last_message_at);`,
+
+	// Messages stored before messages had ids keep an empty one
+	`ALTER TABLE channel_messages ADD COLUMN id VARCHAR(24) NOT NULL DEFAULT '';
+	ALTER TABLE channel_messages ADD COLUMN deleted BOOLEAN NOT NULL DEFAULT FALSE;
+	ALTER TABLE channel_messages ADD COLUMN edited_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+	ALTER TABLE channel_messages ADD COLUMN deleted_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+
+	CREATE TABLE channel_message_edits (
+		channel_id       VARCHAR(24) NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+		message_position INTEGER NOT NULL,
+		position         INTEGER NOT NULL,
+		message          TEXT NOT NULL DEFAULT '',
+		edited_at        TIMESTAMP NOT NULL,
+		PRIMARY KEY (channel_id, message_position, position)
+	);`,
+
+	`CREATE TABLE channel_message_attachments (
+		channel_id       VARCHAR(24) NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+		message_position INTEGER NOT NULL,
+		position         INTEGER NOT NULL,
+		id               VARCHAR(24) NOT NULL,
+		file_name        VARCHAR(255) NOT NULL DEFAULT '',
+		content_type     VARCHAR(255) NOT NULL DEFAULT '',
+		size             BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (channel_id, message_position, position)
+	);`,
+
+	`CREATE TABLE channel_read_markers (
+		channel_id   VARCHAR(24) NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+		participant  VARCHAR(255) NOT NULL,
+		last_read_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (channel_id, participant)
+	);`,
+
+	`ALTER TABLE channel_messages ADD COLUMN internal BOOLEAN NOT NULL DEFAULT FALSE;`,
+
+	`CREATE TABLE channel_tags (
+		channel_id VARCHAR(24) NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+		position   INTEGER NOT NULL,
+		tag        VARCHAR(64) NOT NULL,
+		PRIMARY KEY (channel_id, tag)
+	);`,
+
+	`ALTER TABLE channels ADD COLUMN priority INTEGER NOT NULL DEFAULT 2;
+	CREATE INDEX channels_tenant_priority ON channels (tenant_id, priority);
+	CREATE INDEX channel_tags_tag ON channel_tags (tag);
+	CREATE TABLE channel_attributes (
+		channel_id VARCHAR(24) NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+		name       VARCHAR(64) NOT NULL,
+		value      VARCHAR(256) NOT NULL,
+		PRIMARY KEY (channel_id, name)
+	);
+	CREATE INDEX channel_attributes_name_value ON channel_attributes (name, value);`,
+
+	`CREATE TABLE channel_ratings (
+		channel_id VARCHAR(24) NOT NULL REFERENCES channels (id) ON DELETE CASCADE,
+		completion INTEGER NOT NULL,
+		score      INTEGER NOT NULL,
+		comment    VARCHAR(1000) NOT NULL DEFAULT '',
+		rep_email  VARCHAR(255) NOT NULL,
+		rated_by   VARCHAR(255) NOT NULL,
+		rated_at   TIMESTAMP NOT NULL,
+		PRIMARY KEY (channel_id, completion)
+	);
+	CREATE INDEX channel_ratings_rated_at ON channel_ratings (rated_at);`,
+
+	// The times that stopped the SLA clocks keep the zero time while they run,
+	// sla_breaches lists the targets announced as breached separated by commas
+	`ALTER TABLE channels ADD COLUMN first_response_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+	ALTER TABLE channels ADD COLUMN resolved_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00+00:00';
+	ALTER TABLE channels ADD COLUMN sla_breaches VARCHAR(64) NOT NULL DEFAULT '';
+	CREATE INDEX channels_tenant_resolved_at ON channels (tenant_id, resolved_at);`,
+}
+
+// migrate brings the schema up to date, each migration runs in its own transaction
+func (s sqlHandler) migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, s.schema(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`))
+	if err != nil {
+		return err
+	}
+
+	var applied int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&applied); err != nil {
+		return err
+	}
+
+	for version := applied + 1; version <= len(migrations); version++ {
+		err := s.WithTransaction(ctx, func(ctx context.Context) error {
+			// Not every driver runs several statements in one call
+			for _, statement := range strings.Split(migrations[version-1], ";") {
+				if strings.TrimSpace(statement) == "" {
+					continue
+				}
+				if _, err := s.Execute(ctx, s.schema(statement)); err != nil {
+					return err
+				}
+			}
+			_, err := s.Execute(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s sqlHandler) schema(statement string) string {
+	return strings.Replace(statement, "TIMESTAMP", s.dialect.timestamp, -1)
+}
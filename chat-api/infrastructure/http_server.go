@@ -4,9 +4,11 @@ import (
 	"chat-api/adapter/logger"
 	"chat-api/adapter/repository"
 	"chat-api/adapter/validator"
+	"chat-api/domain"
 	"chat-api/infrastructure/database"
 	"chat-api/infrastructure/log"
 	"chat-api/infrastructure/router"
+	"chat-api/infrastructure/storage"
 	"chat-api/infrastructure/validation"
 	"strconv"
 	"time"
@@ -17,6 +19,8 @@ type config struct {
 	logger        logger.Logger
 	validator     validator.Validator
 	dbNoSQL       repository.NoSQL
+	dbSQL         repository.SQL
+	blobStore     domain.BlobStore
 	ctxTimeout    time.Duration
 	webServerPort router.Port
 	webServer     router.Server
@@ -59,6 +63,32 @@ func (c *config) DbNoSQL(instance int) *config {
 	return c
 }
 
+// DbSQL moves users and channels to a relational database, the NoSQL database keeps everything else
+func (c *config) DbSQL(instance int) *config {
+	db, err := database.NewDatabaseSQLFactory(instance)
+	if err != nil {
+		c.logger.Fatalln(err, "Could not make a connection to the SQL database")
+	}
+
+	c.logger.Infof("Successfully connected to the SQL database")
+
+	c.dbSQL = db
+	return c
+}
+
+// BlobStore keeps the content of attachments
+func (c *config) BlobStore(instance int) *config {
+	store, err := storage.NewBlobStoreFactory(instance)
+	if err != nil {
+		c.logger.Fatalln(err, "Could not configure the blob store")
+	}
+
+	c.logger.Infof("Successfully configured the blob store")
+
+	c.blobStore = store
+	return c
+}
+
 func (c *config) Validator(instance int) *config {
 	v, err := validation.NewValidatorFactory(instance)
 	if err != nil {
@@ -76,6 +106,8 @@ func (c *config) WebServer(instance int) *config {
 		instance,
 		c.logger,
 		c.dbNoSQL,
+		c.dbSQL,
+		c.blobStore,
 		c.validator,
 		c.webServerPort,
 		c.ctxTimeout,
@@ -0,0 +1,79 @@
+package router
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// trustedProxies are the networks of the proxies in front of the API, only they
+// may tell who the client is with X-Forwarded-For
+type trustedProxies []*net.IPNet
+
+// parseTrustedProxies reads a comma separated list of addresses and CIDR ranges
+func parseTrustedProxies(value string) (trustedProxies, error) {
+	var proxies = make(trustedProxies, 0)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, errors.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy %q", entry)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+func (t trustedProxies) contains(ip net.IP) bool {
+	for _, network := range t {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP is the address the request came from. When that is a trusted proxy
+// X-Forwarded-For is read from the right, every proxy appends the address it was
+// reached from, so the first one that is not a trusted proxy is the client. What
+// is left of it was sent by the client and is never believed
+func (t trustedProxies) clientIP(r *http.Request) string {
+	remote, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		remote = strings.TrimSpace(r.RemoteAddr)
+	}
+	ip := net.ParseIP(remote)
+	if ip == nil || !t.contains(ip) {
+		return remote
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			break
+		}
+		ip = hop
+		if !t.contains(hop) {
+			break
+		}
+	}
+	return ip.String()
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"chat-api/adapter/repository"
+	"chat-api/domain"
 	"errors"
 	"time"
 
@@ -28,13 +29,15 @@ func NewWebServerFactory(
 	instance int,
 	log logger.Logger,
 	dbNoSQL repository.NoSQL,
+	dbSQL repository.SQL,
+	blobs domain.BlobStore,
 	validator validator.Validator,
 	port Port,
 	ctxTimeout time.Duration,
 ) (Server, error) {
 	switch instance {
 	case InstanceGin:
-		return newGinServer(log, dbNoSQL, validator, port, ctxTimeout), nil
+		return newGinServer(log, dbNoSQL, dbSQL, blobs, validator, port, ctxTimeout), nil
 	default:
 		return nil, errInvalidWebServerInstance
 	}
@@ -2,14 +2,18 @@ package router
 
 import (
 	"chat-api/adapter/api/action"
+	"chat-api/adapter/api/middleware"
 	"chat-api/adapter/presenter"
 	"chat-api/adapter/services"
 	"chat-api/adapter/validator"
+	"chat-api/domain"
 	"chat-api/infrastructure/common"
 	"chat-api/infrastructure/config"
 	"chat-api/usecase"
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,39 +28,77 @@ type ginEngine struct {
 	router     *gin.Engine
 	log        logger.Logger
 	db         repository.NoSQL
+	dbSQL      repository.SQL
+	blobs      domain.BlobStore
+	search     domain.SearchIndex
+	attempts   domain.LoginAttemptRepository
+	notifier   domain.ChannelNotifier
 	validator  validator.Validator
 	port       Port
+	proxies    trustedProxies
 	ctxTimeout time.Duration
 }
 
 func newGinServer(
 	log logger.Logger,
 	db repository.NoSQL,
+	dbSQL repository.SQL,
+	blobs domain.BlobStore,
 	validator validator.Validator,
 	port Port,
 	t time.Duration,
 ) *ginEngine {
+	proxies, err := parseTrustedProxies(config.GetConfig().TrustedProxies)
+	if err != nil {
+		log.WithError(err).Fatalln("Error reading trusted proxies")
+	}
+	if _, err := services.NewConfiguredPasswordHasher(); err != nil {
+		log.WithError(err).Fatalln("Error configuring password hashing")
+	}
+
 	return &ginEngine{
 		router:     gin.New(),
 		log:        log,
 		db:         db,
+		dbSQL:      dbSQL,
+		blobs:      blobs,
+		search:     newSearchIndex(db, dbSQL),
+		attempts:   repository.NewLoginAttemptNoSQL(db),
+		notifier:   services.NewChannelNotifier(log),
 		validator:  validator,
 		port:       port,
+		proxies:    proxies,
 		ctxTimeout: t,
 	}
 }
 
+// newSearchIndex searches channels with the MongoDB text index, an index kept
+// in process takes over when channels live in the SQL database
+func newSearchIndex(db repository.NoSQL, dbSQL repository.SQL) domain.SearchIndex {
+	if dbSQL != nil {
+		return repository.NewSearchIndexInverted(repository.NewChannelSQL(dbSQL))
+	}
+	return repository.NewSearchIndexNoSQL(db)
+}
+
 func (g ginEngine) Listen() {
 	gin.SetMode(gin.ReleaseMode)
 	gin.Recovery()
 
 	g.setAppHandlers(g.router)
 
+	if interval := config.GetConfig().SLACheckIntervalSeconds; interval > 0 {
+		go g.watchSLABreaches(time.Duration(interval) * time.Second)
+	}
+
+	// Bodies and responses get longer than headers, attachments of
+	// domain.MaxAttachmentSize must go through on slow connections
 	server := &http.Server{
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		Addr:         fmt.Sprintf(":%d", g.port),
-		Handler:      g.router,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       60 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		Addr:              fmt.Sprintf(":%d", g.port),
+		Handler:           g.router,
 	}
 
 	g.log.WithFields(logger.Fields{"port": g.port}).Infof("Starting HTTP Server")
@@ -65,23 +107,126 @@ func (g ginEngine) Listen() {
 	}
 }
 
+// watchSLABreaches checks the open channels of every organization for SLA
+// breaches. Every API server runs the check, two servers checking at the same
+// time may both announce a breach
+func (g ginEngine) watchSLABreaches(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		g.checkSLABreaches(context.Background())
+	}
+}
+
+func (g ginEngine) checkSLABreaches(ctx context.Context) {
+	var (
+		orgRepo = repository.NewOrganizationNoSQL(g.db)
+		uc      = usecase.NewCheckSLABreachesInteractor(
+			g.channelRepository(),
+			orgRepo,
+			g.notifier,
+			presenter.NewCheckSLABreachesPresenter(),
+			g.ctxTimeout,
+		)
+	)
+
+	organizations, err := orgRepo.GetOrganizations(ctx)
+	if err != nil {
+		g.log.WithError(err).Errorf("error listing organizations for the SLA check")
+		return
+	}
+
+	// The default organization is only stored once its settings are saved
+	tenants := []string{domain.DefaultTenantID}
+	for _, organization := range organizations {
+		if organization.Id() != domain.DefaultTenantID {
+			tenants = append(tenants, organization.Id())
+		}
+	}
+
+	for _, tenant := range tenants {
+		output, err := uc.Execute(domain.WithTenant(ctx, tenant))
+		if err != nil {
+			g.log.WithFields(logger.Fields{"tenant": tenant}).WithError(err).Errorf("error checking SLA breaches")
+			continue
+		}
+		if len(output.Breaches) > 0 {
+			g.log.WithFields(logger.Fields{"tenant": tenant, "breaches": len(output.Breaches)}).Infof("SLA breaches announced")
+		}
+	}
+}
+
 func (g ginEngine) setAppHandlers(router *gin.Engine) {
 	router.Use(g.CORSMiddleware())
+	router.Use(g.ClientIPMiddleware())
 
 	router.GET("/health", g.healthcheck())
 
 	v1 := router.Group("/v1")
+	v1.Use(g.TenantMiddleware())
 
 	v1.POST("/channel", g.AuthenticationMiddleware(), g.buildCreateChannelAction())
-	v1.POST("/message", g.buildCreateMessageAction())
-	v1.GET("/channel/:id", g.AuthenticationMiddleware(), g.buildGetChannelByIdAction())
+	v1.POST("/message", g.APIKeyOrAuthenticationMiddleware(domain.ScopeMessagesWrite), g.buildCreateMessageAction())
+	v1.POST("/channel/:id/message", g.ScopedAuthenticationMiddleware(domain.ScopeGuest), g.ChannelBindingMiddleware(), g.buildCreateMessageAction())
+	v1.GET("/channel/:id", g.ScopedAuthenticationMiddleware(domain.ScopeGuest), g.ChannelBindingMiddleware(), g.buildGetChannelByIdAction())
 	v1.PUT("/channel/:id", g.AuthenticationMiddleware(), g.buildUpdateChannelStatusAction())
 	v1.GET("/channel", g.AuthenticationMiddleware(), g.buildGetChannelsByQueryAction())
+	v1.PUT("/channel/:id/message/:messageId", g.ScopedAuthenticationMiddleware(domain.ScopeGuest), g.ChannelBindingMiddleware(), g.buildEditMessageAction())
+	v1.DELETE("/channel/:id/message/:messageId", g.ScopedAuthenticationMiddleware(domain.ScopeGuest), g.ChannelBindingMiddleware(), g.buildDeleteMessageAction())
+	v1.POST("/channel/:id/note", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildAddNoteAction())
+	v1.PUT("/channel/:id/details", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildUpdateChannelDetailsAction())
+	v1.POST("/channel/:id/cannedresponse/:responseId", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildApplyCannedResponseAction())
+	v1.PUT("/channel/:id/read", g.ScopedAuthenticationMiddleware(domain.ScopeGuest), g.ChannelBindingMiddleware(), g.buildMarkChannelReadAction())
+	v1.POST("/channel/:id/rating", g.ScopedAuthenticationMiddleware(domain.ScopeGuest), g.ChannelBindingMiddleware(), g.buildRateChannelAction())
+	v1.POST("/channel/:id/attachment", g.ScopedAuthenticationMiddleware(domain.ScopeGuest), g.ChannelBindingMiddleware(), g.buildUploadAttachmentAction())
+	v1.GET("/channel/:id/attachment/:attachmentId", g.ScopedAuthenticationMiddleware(domain.ScopeGuest), g.ChannelBindingMiddleware(), g.buildGetAttachmentURLAction())
+	v1.GET("/attachment/:id", g.buildDownloadAttachmentAction())
+	v1.GET("/search", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildSearchChannelsAction())
 
-	v1.POST("/user", g.buildCreateUserAction())
+	v1.POST("/user", g.OptionalAuthenticationMiddleware(domain.ScopeGuest), g.buildCreateUserAction())
 
+	v1.POST("/guest", g.buildStartGuestChatAction())
+	v1.GET("/guest/me", g.ScopedAuthenticationMiddleware(domain.ScopeGuest), g.buildGetGuestSessionAction())
+
+	v1.GET("/user", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildGetUsersAction())
+	v1.GET("/user/me", g.AuthenticationMiddleware(), g.buildGetCurrentUserAction())
+	v1.PUT("/user/me", g.AuthenticationMiddleware(), g.buildUpdateUserProfileAction())
+	v1.PUT("/user/me/password", g.AuthenticationMiddleware(), g.buildChangePasswordAction())
 	v1.GET("/user/:email", g.AuthenticationMiddleware(), g.buildGetUserByEmailAction())
 	v1.POST("/user/login", g.buildLoginUserAction())
+	v1.GET("/user/sso/start", g.buildStartSSOLoginAction())
+	v1.POST("/user/sso/callback", g.buildCompleteSSOLoginAction())
+	v1.POST("/user/:email/unlock", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildUnlockUserAction())
+	v1.POST("/user/:email/deactivate", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildUpdateUserActivationAction(false))
+	v1.POST("/user/:email/reactivate", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildUpdateUserActivationAction(true))
+
+	v1.POST("/user/2fa/enroll", g.ScopedAuthenticationMiddleware(domain.ScopeTwoFactorEnrollment), g.buildEnrollTwoFactorAction())
+	v1.POST("/user/2fa/activate", g.ScopedAuthenticationMiddleware(domain.ScopeTwoFactorEnrollment), g.buildActivateTwoFactorAction())
+	v1.POST("/user/2fa/disable", g.AuthenticationMiddleware(), g.buildDisableTwoFactorAction())
+
+	v1.PUT("/settings/security", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildUpdateSecuritySettingsAction())
+
+	v1.POST("/apikey", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildCreateAPIKeyAction())
+	v1.GET("/apikey", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildGetAPIKeysAction())
+	v1.POST("/apikey/:id/revoke", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildRevokeAPIKeyAction())
+
+	v1.POST("/cannedresponse", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildCreateCannedResponseAction())
+	v1.GET("/cannedresponse", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildGetCannedResponsesAction())
+	v1.GET("/cannedresponse/:id", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildGetCannedResponseAction())
+	v1.PUT("/cannedresponse/:id", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildUpdateCannedResponseAction())
+	v1.DELETE("/cannedresponse/:id", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildDeleteCannedResponseAction())
+
+	v1.POST("/organization", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildCreateOrganizationAction())
+	v1.GET("/organization", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildGetOrganizationAction())
+	v1.PUT("/organization/settings", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildUpdateOrganizationSettingsAction())
+
+	v1.GET("/audit", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildGetAuditEventsAction())
+	v1.GET("/audit/export", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildExportAuditEventsAction())
+
+	v1.GET("/report/satisfaction/:groupBy", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildGetSatisfactionReportAction())
+	v1.GET("/report/channels", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildGetChannelAnalyticsAction())
+	v1.GET("/report/reps", g.AuthenticationMiddleware(), g.AdminMiddleware(), g.buildGetRepAnalyticsAction())
 
 }
 
@@ -109,7 +254,7 @@ func (g ginEngine) LoggingMiddleWare() gin.HandlerFunc {
 			"path":       c.Request.URL.Path,
 			"method":     c.Request.Method,
 			"header":     header,
-			"ip":         c.ClientIP(),
+			"ip":         g.proxies.clientIP(c.Request),
 			"latency":    time.Since(start).Milliseconds(),
 			"user-agent": c.Request.UserAgent(),
 		}).Infof("request handled")
@@ -125,7 +270,7 @@ func (g ginEngine) CORSMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == http.MethodOptions {
 			if _, exists := common.Find(strings.Split(allowedOrigins, ","), origin); !exists {
@@ -141,6 +286,25 @@ func (g ginEngine) CORSMiddleware() gin.HandlerFunc {
 }
 
 func (g ginEngine) AuthenticationMiddleware() gin.HandlerFunc {
+	return g.ScopedAuthenticationMiddleware()
+}
+
+// OptionalAuthenticationMiddleware lets anonymous requests through, a request
+// carrying a token is checked like by ScopedAuthenticationMiddleware
+func (g ginEngine) OptionalAuthenticationMiddleware(scopes ...string) gin.HandlerFunc {
+	authenticate := g.ScopedAuthenticationMiddleware(scopes...)
+
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		authenticate(c)
+	}
+}
+
+// ScopedAuthenticationMiddleware accepts full access tokens as well as tokens restricted to one of the given scopes
+func (g ginEngine) ScopedAuthenticationMiddleware(scopes ...string) gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 		cfg := config.GetConfig()
@@ -157,14 +321,33 @@ func (g ginEngine) AuthenticationMiddleware() gin.HandlerFunc {
 				return []byte(cfg.AccessSecret), nil
 			})
 
-			if err != nil {
+			if err != nil || !token.Valid {
 				c.AbortWithStatus(http.StatusUnauthorized)
 				return
 			}
 
-			if token.Valid {
-				c.Next()
+			claims, _ := token.Claims.(jwt.MapClaims)
+			email, _ := claims["email"].(string)
+			role, _ := claims["role"].(string)
+			scope, _ := claims["scope"].(string)
+			channelId, _ := claims["channel_id"].(string)
+			tenantId, _ := claims["tenant_id"].(string)
+			if _, allowed := common.Find(scopes, scope); scope != "" && !allowed {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			if !g.bindTenant(c, tenantId) {
+				return
 			}
+
+			c.Request = c.Request.WithContext(middleware.WithPrincipal(c.Request.Context(), middleware.Principal{
+				Email:     email,
+				Role:      role,
+				Scope:     scope,
+				ChannelId: channelId,
+				TenantId:  domain.TenantFromContext(c.Request.Context()),
+			}))
+			c.Next()
 		} else {
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
@@ -173,17 +356,378 @@ func (g ginEngine) AuthenticationMiddleware() gin.HandlerFunc {
 	}
 }
 
+// APIKeyOrAuthenticationMiddleware lets internal services in with an API key holding the scope,
+// any other caller needs a regular user token
+func (g ginEngine) APIKeyOrAuthenticationMiddleware(scope string) gin.HandlerFunc {
+	authenticate := g.AuthenticationMiddleware()
+
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			authenticate(c)
+			return
+		}
+
+		uc := usecase.NewAuthenticateAPIKeyInteractor(
+			repository.NewAPIKeyNoSQL(g.db),
+			services.NewAPIKeyService(g.log),
+			presenter.NewAuthenticateAPIKeyPresenter(),
+			g.ctxTimeout,
+		)
+		output, err := uc.Execute(c.Request.Context(), usecase.AuthenticateAPIKeyInput{Key: key, Scope: scope})
+		switch err {
+		case nil:
+		case domain.ErrAPIKeyScope:
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		case domain.ErrInvalidAPIKey:
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		default:
+			g.log.WithError(err).Errorf("error authenticating api key")
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		if !g.bindTenant(c, output.TenantId) {
+			return
+		}
+
+		c.Request = c.Request.WithContext(middleware.WithPrincipal(c.Request.Context(), middleware.Principal{
+			Email:    "apikey:" + output.Name,
+			Role:     domain.SERVICE,
+			Scope:    output.Scope,
+			TenantId: output.TenantId,
+			APIKey:   true,
+		}))
+		c.Next()
+	}
+}
+
+// TenantMiddleware resolves the organization from the Host header, hosts that
+// no organization claims are served by the default one
+func (g ginEngine) TenantMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantId := domain.DefaultTenantID
+
+		organization, err := repository.NewOrganizationNoSQL(g.db).GetOrganizationByDomain(c.Request.Context(), c.Request.Host)
+		switch err {
+		case nil:
+			tenantId = organization.Id()
+		case domain.ErrOrganizationNotFound:
+		default:
+			g.log.WithError(err).Errorf("error resolving organization")
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.Request = c.Request.WithContext(domain.WithTenant(c.Request.Context(), tenantId))
+		c.Next()
+	}
+}
+
+// bindTenant switches the request to the organization of its credentials. Credentials
+// of one organization are refused on the host of another, the default host accepts all
+func (g ginEngine) bindTenant(c *gin.Context, tenantId string) bool {
+	if tenantId == "" {
+		tenantId = domain.DefaultTenantID
+	}
+
+	hostTenant := domain.TenantFromContext(c.Request.Context())
+	if hostTenant != domain.DefaultTenantID && hostTenant != tenantId {
+		c.AbortWithStatus(http.StatusForbidden)
+		return false
+	}
+
+	c.Request = c.Request.WithContext(domain.WithTenant(c.Request.Context(), tenantId))
+	return true
+}
+
+// AdminMiddleware only lets through principals with the ADMIN role, it must run after AuthenticationMiddleware
+func (g ginEngine) AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := middleware.PrincipalFromContext(c.Request.Context())
+		if !ok || principal.Role != domain.ADMIN {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+// ChannelBindingMiddleware keeps guest tokens on the channel they were issued for, it must run after ScopedAuthenticationMiddleware
+func (g ginEngine) ChannelBindingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, _ := middleware.PrincipalFromContext(c.Request.Context())
+		if principal.Scope == domain.ScopeGuest && principal.ChannelId != c.Param("id") {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+// ClientIPMiddleware stores the caller IP on the request context for use cases that need it
+func (g ginEngine) ClientIPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(middleware.WithClientIP(c.Request.Context(), g.proxies.clientIP(c.Request)))
+		c.Next()
+	}
+}
+
+// auditLogger logs audit events and appends them to the audit trail of the tenant
+func (g ginEngine) auditLogger() services.AuditLogger {
+	return services.NewAuditLogger(g.log, repository.NewAuditEventNoSQL(g.db))
+}
+
+// userRepository and channelRepository read and write the SQL database when
+// one is configured, every other collection stays on the NoSQL database
+func (g ginEngine) userRepository() domain.UserRepository {
+	if g.dbSQL != nil {
+		return repository.NewUserSQL(g.dbSQL)
+	}
+	return repository.NewUserNoSQL(g.db)
+}
+
+func (g ginEngine) channelRepository() domain.ChannelRepository {
+	if g.dbSQL != nil {
+		return repository.NewChannelSQL(g.dbSQL)
+	}
+	return repository.NewChannelNoSQL(g.db)
+}
+
+func (g ginEngine) analyticsRepository() domain.AnalyticsRepository {
+	if g.dbSQL != nil {
+		return repository.NewAnalyticsSQL(g.dbSQL)
+	}
+	return repository.NewAnalyticsNoSQL(g.db)
+}
+
 func (g ginEngine) buildCreateMessageAction() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
 			uc = usecase.NewCreateMessageInteractor(
-				repository.NewChannelNoSQL(g.db),
+				g.channelRepository(),
+				repository.NewAttachmentNoSQL(g.db),
+				g.search,
 				presenter.NewCreateMessagePresenter(),
 				g.ctxTimeout,
 			)
 
 			act = action.NewCreateMessageAction(uc, g.log, g.validator)
 		)
+
+		if id := c.Param("id"); id != "" {
+			q := c.Request.URL.Query()
+			q.Set("channelId", id)
+			c.Request.URL.RawQuery = q.Encode()
+		}
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildAddNoteAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewAddNoteInteractor(
+				g.channelRepository(),
+				repository.NewAttachmentNoSQL(g.db),
+				g.search,
+				g.notifier,
+				presenter.NewAddNotePresenter(),
+				g.ctxTimeout,
+			)
+
+			act = action.NewAddNoteAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("channelId", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildUpdateChannelDetailsAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewUpdateChannelDetailsInteractor(
+				g.channelRepository(),
+				g.search,
+				presenter.NewUpdateChannelDetailsPresenter(),
+				g.ctxTimeout,
+			)
+
+			act = action.NewUpdateChannelDetailsAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("channelId", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildEditMessageAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewEditMessageInteractor(
+				g.channelRepository(),
+				g.search,
+				g.notifier,
+				g.auditLogger(),
+				presenter.NewEditMessagePresenter(),
+				g.ctxTimeout,
+			)
+
+			act = action.NewEditMessageAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Add("channelId", c.Param("id"))
+		q.Add("messageId", c.Param("messageId"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildMarkChannelReadAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewMarkChannelReadInteractor(
+				g.channelRepository(),
+				g.notifier,
+				presenter.NewMarkChannelReadPresenter(),
+				g.ctxTimeout,
+			)
+
+			act = action.NewMarkChannelReadAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("channelId", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildRateChannelAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewRateChannelInteractor(
+				g.channelRepository(),
+				presenter.NewRateChannelPresenter(),
+				g.ctxTimeout,
+			)
+
+			act = action.NewRateChannelAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("channelId", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildUploadAttachmentAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewUploadAttachmentInteractor(
+				g.channelRepository(),
+				repository.NewAttachmentNoSQL(g.db),
+				g.blobs,
+				presenter.NewUploadAttachmentPresenter(),
+				g.ctxTimeout,
+			)
+
+			act = action.NewUploadAttachmentAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("channelId", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetAttachmentURLAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetAttachmentURLInteractor(
+				g.channelRepository(),
+				repository.NewAttachmentNoSQL(g.db),
+				services.NewAttachmentURLSigner(),
+				presenter.NewGetAttachmentURLPresenter(),
+				g.ctxTimeout,
+			)
+
+			act = action.NewGetAttachmentURLAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("channelId", c.Param("id"))
+		q.Set("attachmentId", c.Param("attachmentId"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+// buildDownloadAttachmentAction serves signed links without credentials, the
+// organization comes from the link and its signature
+func (g ginEngine) buildDownloadAttachmentAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !g.bindTenant(c, c.Query("tenant")) {
+			return
+		}
+
+		var (
+			uc = usecase.NewDownloadAttachmentInteractor(
+				repository.NewAttachmentNoSQL(g.db),
+				g.blobs,
+				services.NewAttachmentURLSigner(),
+				presenter.NewDownloadAttachmentPresenter(),
+				g.ctxTimeout,
+			)
+
+			act = action.NewDownloadAttachmentAction(uc, g.log)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("id", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildDeleteMessageAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewDeleteMessageInteractor(
+				g.channelRepository(),
+				g.search,
+				g.notifier,
+				g.auditLogger(),
+				presenter.NewDeleteMessagePresenter(),
+				g.ctxTimeout,
+			)
+
+			act = action.NewDeleteMessageAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Add("channelId", c.Param("id"))
+		q.Add("messageId", c.Param("messageId"))
+		c.Request.URL.RawQuery = q.Encode()
+
 		act.Execute(c.Writer, c.Request)
 	}
 }
@@ -192,7 +736,10 @@ func (g ginEngine) buildCreateChannelAction() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
 			uc = usecase.NewCreateChannelInteractor(
-				repository.NewChannelNoSQL(g.db),
+				g.channelRepository(),
+				g.search,
+				repository.NewOrganizationNoSQL(g.db),
+				g.userRepository(),
 				presenter.NewCreateChannelPresenter(),
 				g.ctxTimeout,
 			)
@@ -208,8 +755,10 @@ func (g ginEngine) buildCreateUserAction() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
 			uc = usecase.NewCreateUserInteractor(
-				repository.NewUserNoSQL(g.db),
+				g.userRepository(),
+				g.channelRepository(),
 				services.NewAuthenticationUtility(g.log),
+				g.auditLogger(),
 				presenter.NewCreateUserPresenter(),
 				g.ctxTimeout,
 			)
@@ -225,7 +774,8 @@ func (g ginEngine) buildGetChannelByIdAction() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
 			uc = usecase.NewGetChannelByIdInteractor(
-				repository.NewChannelNoSQL(g.db),
+				g.channelRepository(),
+				repository.NewOrganizationNoSQL(g.db),
 				presenter.NewGetChannelByIdPresenter(),
 				g.ctxTimeout,
 			)
@@ -244,22 +794,30 @@ func (g ginEngine) buildGetChannelsByQueryAction() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
 			uc = usecase.NewGetChannelByQueryInteractor(
-				repository.NewChannelNoSQL(g.db),
-				repository.NewUserNoSQL(g.db),
+				g.channelRepository(),
+				g.userRepository(),
+				repository.NewOrganizationNoSQL(g.db),
+				services.NewChannelCursor(g.log),
 				presenter.NewGetChannelsByQueryPresenter(),
 				g.ctxTimeout,
 			)
-			act = action.NewGetChannelsByQueryAction(uc, g.log)
+			act = action.NewGetChannelsByQueryAction(uc, g.log, g.validator)
 		)
 
-		q := c.Request.URL.Query()
-		q.Add("sort", c.Query("sort"))
-		q.Add("limit", c.Query("limit"))
-		q.Add("page", c.Query("page"))
-		q.Add("repEmail", c.Query("repEmail"))
-		q.Add("userEmail", c.Query("userEmail"))
-		q.Add("currentStatus", c.Query("currentStatus"))
-		c.Request.URL.RawQuery = q.Encode()
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildSearchChannelsAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewSearchChannelsInteractor(
+				g.search,
+				presenter.NewSearchChannelsPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewSearchChannelsAction(uc, g.log, g.validator)
+		)
 
 		act.Execute(c.Writer, c.Request)
 	}
@@ -269,7 +827,7 @@ func (g ginEngine) buildGetUserByEmailAction() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
 			uc = usecase.NewUserByEmailInteractor(
-				repository.NewUserNoSQL(g.db),
+				g.userRepository(),
 				presenter.NewGetUserByEmailPresenter(),
 				g.ctxTimeout,
 			)
@@ -288,8 +846,12 @@ func (g ginEngine) buildLoginUserAction() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
 			uc = usecase.NewLoginUserInteractor(
-				repository.NewUserNoSQL(g.db),
+				g.userRepository(),
+				g.attempts,
+				repository.NewSettingsNoSQL(g.db),
 				services.NewAuthenticationUtility(g.log),
+				services.NewTwoFactor(g.log),
+				g.auditLogger(),
 				presenter.NewLoginPresenter(),
 				g.ctxTimeout,
 			)
@@ -301,15 +863,39 @@ func (g ginEngine) buildLoginUserAction() gin.HandlerFunc {
 	}
 }
 
-func (g ginEngine) buildUpdateChannelStatusAction() gin.HandlerFunc {
+func (g ginEngine) buildUnlockUserAction() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
-			uc = usecase.NewUpdateChannelStatusInteractor(
-				repository.NewChannelNoSQL(g.db),
-				presenter.NewUpdateChannelStatusPresenter(),
+			uc = usecase.NewUnlockUserInteractor(
+				g.userRepository(),
+				g.attempts,
+				g.auditLogger(),
+				presenter.NewUnlockUserPresenter(),
 				g.ctxTimeout,
 			)
-			act = action.NewUpdateChannelAction(uc, g.log, g.validator)
+			act = action.NewUnlockUserAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Add("email", c.Param("email"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildUpdateChannelStatusAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewUpdateChannelStatusInteractor(
+				g.channelRepository(),
+				g.search,
+				g.notifier,
+				g.auditLogger(),
+				presenter.NewUpdateChannelStatusPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewUpdateChannelAction(uc, g.log, g.validator)
 		)
 
 		q := c.Request.URL.Query()
@@ -319,3 +905,520 @@ func (g ginEngine) buildUpdateChannelStatusAction() gin.HandlerFunc {
 		act.Execute(c.Writer, c.Request)
 	}
 }
+
+func (g ginEngine) buildEnrollTwoFactorAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewEnrollTwoFactorInteractor(
+				g.userRepository(),
+				services.NewTwoFactor(g.log),
+				presenter.NewEnrollTwoFactorPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewEnrollTwoFactorAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildActivateTwoFactorAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewActivateTwoFactorInteractor(
+				g.userRepository(),
+				services.NewTwoFactor(g.log),
+				g.auditLogger(),
+				presenter.NewActivateTwoFactorPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewActivateTwoFactorAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildDisableTwoFactorAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewDisableTwoFactorInteractor(
+				g.userRepository(),
+				services.NewTwoFactor(g.log),
+				g.auditLogger(),
+				presenter.NewDisableTwoFactorPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewDisableTwoFactorAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildUpdateSecuritySettingsAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewUpdateSecuritySettingsInteractor(
+				repository.NewSettingsNoSQL(g.db),
+				g.auditLogger(),
+				presenter.NewUpdateSecuritySettingsPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewUpdateSecuritySettingsAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetCurrentUserAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetCurrentUserInteractor(
+				g.userRepository(),
+				presenter.NewGetUserByEmailPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetCurrentUserAction(uc, g.log)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildUpdateUserProfileAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewUpdateUserProfileInteractor(
+				g.userRepository(),
+				presenter.NewUpdateUserProfilePresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewUpdateUserProfileAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildChangePasswordAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewChangePasswordInteractor(
+				g.userRepository(),
+				services.NewAuthenticationUtility(g.log),
+				g.auditLogger(),
+				presenter.NewChangePasswordPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewChangePasswordAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildUpdateUserActivationAction(active bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewUpdateUserActivationInteractor(
+				g.userRepository(),
+				g.auditLogger(),
+				presenter.NewUpdateUserActivationPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewUpdateUserActivationAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Add("email", c.Param("email"))
+		q.Set("active", strconv.FormatBool(active))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetUsersAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetUsersInteractor(
+				g.userRepository(),
+				presenter.NewGetUsersPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetUsersAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildStartSSOLoginAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewStartSSOLoginInteractor(
+				repository.NewSSOStateNoSQL(g.db),
+				services.NewOIDCProvider(g.log),
+				presenter.NewStartSSOLoginPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewStartSSOLoginAction(uc, g.log)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildCompleteSSOLoginAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewCompleteSSOLoginInteractor(
+				g.userRepository(),
+				repository.NewSSOStateNoSQL(g.db),
+				services.NewOIDCProvider(g.log),
+				services.NewAuthenticationUtility(g.log),
+				g.auditLogger(),
+				presenter.NewLoginPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewCompleteSSOLoginAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildStartGuestChatAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			createChannel = usecase.NewCreateChannelInteractor(
+				g.channelRepository(),
+				g.search,
+				repository.NewOrganizationNoSQL(g.db),
+				g.userRepository(),
+				presenter.NewCreateChannelPresenter(),
+				g.ctxTimeout,
+			)
+			uc = usecase.NewStartGuestChatInteractor(
+				g.userRepository(),
+				createChannel,
+				services.NewAuthenticationUtility(g.log),
+				presenter.NewStartGuestChatPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewStartGuestChatAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetGuestSessionAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetGuestSessionInteractor(
+				g.channelRepository(),
+				presenter.NewGetGuestSessionPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetGuestSessionAction(uc, g.log)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildCreateAPIKeyAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewCreateAPIKeyInteractor(
+				repository.NewAPIKeyNoSQL(g.db),
+				services.NewAPIKeyService(g.log),
+				g.auditLogger(),
+				presenter.NewCreateAPIKeyPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewCreateAPIKeyAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetAPIKeysAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetAPIKeysInteractor(
+				repository.NewAPIKeyNoSQL(g.db),
+				presenter.NewGetAPIKeysPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetAPIKeysAction(uc, g.log)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildRevokeAPIKeyAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewRevokeAPIKeyInteractor(
+				repository.NewAPIKeyNoSQL(g.db),
+				g.auditLogger(),
+				presenter.NewRevokeAPIKeyPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewRevokeAPIKeyAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Add("id", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildCreateCannedResponseAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewCreateCannedResponseInteractor(
+				repository.NewCannedResponseNoSQL(g.db),
+				presenter.NewCreateCannedResponsePresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewCreateCannedResponseAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetCannedResponsesAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetCannedResponsesInteractor(
+				repository.NewCannedResponseNoSQL(g.db),
+				presenter.NewGetCannedResponsesPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetCannedResponsesAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetCannedResponseAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetCannedResponseInteractor(
+				repository.NewCannedResponseNoSQL(g.db),
+				presenter.NewGetCannedResponsePresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetCannedResponseAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("id", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildUpdateCannedResponseAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewUpdateCannedResponseInteractor(
+				repository.NewCannedResponseNoSQL(g.db),
+				presenter.NewUpdateCannedResponsePresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewUpdateCannedResponseAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("id", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildDeleteCannedResponseAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewDeleteCannedResponseInteractor(
+				repository.NewCannedResponseNoSQL(g.db),
+				presenter.NewDeleteCannedResponsePresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewDeleteCannedResponseAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("id", c.Param("id"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildApplyCannedResponseAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewApplyCannedResponseInteractor(
+				g.channelRepository(),
+				repository.NewCannedResponseNoSQL(g.db),
+				g.userRepository(),
+				g.search,
+				g.notifier,
+				g.auditLogger(),
+				presenter.NewApplyCannedResponsePresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewApplyCannedResponseAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("channelId", c.Param("id"))
+		q.Set("responseId", c.Param("responseId"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildCreateOrganizationAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewCreateOrganizationInteractor(
+				repository.NewOrganizationNoSQL(g.db),
+				g.auditLogger(),
+				presenter.NewCreateOrganizationPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewCreateOrganizationAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetOrganizationAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetOrganizationInteractor(
+				repository.NewOrganizationNoSQL(g.db),
+				presenter.NewGetOrganizationPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetOrganizationAction(uc, g.log)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildUpdateOrganizationSettingsAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewUpdateOrganizationSettingsInteractor(
+				repository.NewOrganizationNoSQL(g.db),
+				g.auditLogger(),
+				presenter.NewUpdateOrganizationSettingsPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewUpdateOrganizationSettingsAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetAuditEventsAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetAuditEventsInteractor(
+				repository.NewAuditEventNoSQL(g.db),
+				presenter.NewGetAuditEventsPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetAuditEventsAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetSatisfactionReportAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetSatisfactionReportInteractor(
+				g.channelRepository(),
+				presenter.NewGetSatisfactionReportPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetSatisfactionReportAction(uc, g.log, g.validator)
+		)
+
+		q := c.Request.URL.Query()
+		q.Set("groupBy", c.Param("groupBy"))
+		c.Request.URL.RawQuery = q.Encode()
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetChannelAnalyticsAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetChannelAnalyticsInteractor(
+				g.analyticsRepository(),
+				repository.NewOrganizationNoSQL(g.db),
+				presenter.NewGetChannelAnalyticsPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetChannelAnalyticsAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildGetRepAnalyticsAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewGetRepAnalyticsInteractor(
+				g.analyticsRepository(),
+				repository.NewOrganizationNoSQL(g.db),
+				presenter.NewGetRepAnalyticsPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewGetRepAnalyticsAction(uc, g.log, g.validator)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
+
+func (g ginEngine) buildExportAuditEventsAction() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			uc = usecase.NewExportAuditEventsInteractor(
+				repository.NewAuditEventNoSQL(g.db),
+				g.auditLogger(),
+				presenter.NewExportAuditEventsPresenter(),
+				g.ctxTimeout,
+			)
+			act = action.NewExportAuditEventsAction(uc, g.log)
+		)
+
+		act.Execute(c.Writer, c.Request)
+	}
+}
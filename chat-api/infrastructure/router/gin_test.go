@@ -0,0 +1,940 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-api/adapter/api/middleware"
+	"chat-api/adapter/repository"
+	"chat-api/adapter/services"
+	"chat-api/domain"
+	"chat-api/infrastructure/database"
+	"chat-api/infrastructure/log"
+	"chat-api/infrastructure/storage"
+	"chat-api/infrastructure/validation"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTestServer runs the whole HTTP stack against the in-memory database, with
+// users and channels in the SQL database when one is given
+func newTestServer(t *testing.T, dbSQL repository.SQL) http.Handler {
+	os.Setenv("ACCESS_SECRET", "integration-test-secret")
+	os.Setenv("ARGON2_MEMORY_KIB", "1024")
+
+	db, err := database.NewDatabaseNoSQLFactory(database.InstanceMemory)
+	if err != nil {
+		t.Fatalf("[TestCase 'database'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	validator, err := validation.NewValidatorFactory(validation.InstanceGoPlayground)
+	if err != nil {
+		t.Fatalf("[TestCase 'validator'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	dir, err := ioutil.TempDir("", "attachments")
+	if err != nil {
+		t.Fatalf("[TestCase 'blob store'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	blobs, err := storage.NewLocalStore(dir)
+	if err != nil {
+		t.Fatalf("[TestCase 'blob store'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	g := newGinServer(log.LoggerMock{}, db, dbSQL, blobs, validator, 0, 5*time.Second)
+	g.setAppHandlers(g.router)
+
+	// Only admins create admins, the first one is seeded like cmd/createadmin does
+	hasher, err := services.NewConfiguredPasswordHasher()
+	if err != nil {
+		t.Fatalf("[TestCase 'password hasher'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	hash, err := hasher.Hash("supersecurepassword")
+	if err != nil {
+		t.Fatalf("[TestCase 'hash admin password'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	admin := domain.NewUser(primitive.NewObjectID(), "first", "last", "admin@email.com", hash, time.Now(), time.Now())
+	admin.UpdateRole(domain.ADMIN)
+	if _, err := g.userRepository().CreateUser(domain.WithTenant(context.Background(), domain.DefaultTenantID), admin); err != nil {
+		t.Fatalf("[TestCase 'seed admin'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	return g.router
+}
+
+func doRequest(t *testing.T, handler http.Handler, method, url, token string, body interface{}) (int, map[string]interface{}) {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+
+	req := httptest.NewRequest(method, url, bytes.NewReader(payload))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var result map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &result)
+	return w.Code, result
+}
+
+func doUpload(t *testing.T, handler http.Handler, url, token, fileName, content string) (int, map[string]interface{}) {
+	var (
+		payload = &bytes.Buffer{}
+		writer  = multipart.NewWriter(payload)
+	)
+	part, _ := writer.CreateFormFile("file", fileName)
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, url, payload)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var result map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &result)
+	return w.Code, result
+}
+
+func TestGinServer_MemoryDatabase(t *testing.T) {
+	testGinServer(t, newTestServer(t, nil))
+}
+
+func TestGinServer_SQLiteDatabase(t *testing.T) {
+	os.Setenv("SQLITE_PATH", ":memory:")
+
+	dbSQL, err := database.NewDatabaseSQLFactory(database.InstanceSQLite)
+	if err != nil {
+		t.Fatalf("[TestCase 'sql database'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+	testGinServer(t, newTestServer(t, dbSQL))
+}
+
+func testGinServer(t *testing.T, handler http.Handler) {
+	// Browsers ask before deleting messages and canned responses
+	preflight := httptest.NewRequest(http.MethodOptions, "/v1/channel", nil)
+	preflight.Header.Set("Origin", "http://localhost:3000")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, preflight)
+	if methods := w.Header().Get("Access-Control-Allow-Methods"); w.Code != http.StatusNoContent || !strings.Contains(methods, http.MethodDelete) {
+		t.Errorf("[TestCase 'preflight'] Result: '%v', '%v' | Expected: '%v', '%v'", w.Code, methods, http.StatusNoContent, http.MethodDelete)
+	}
+
+	register := func(email, role string) {
+		status, body := doRequest(t, handler, http.MethodPost, "/v1/user", "", map[string]string{
+			"firstName": "first",
+			"lastName":  "last",
+			"email":     email,
+			"password":  "supersecurepassword",
+			"role":      role,
+		})
+		if status != http.StatusCreated {
+			t.Fatalf("[TestCase 'register %s'] Result: '%v' %v | Expected: '%v'", email, status, body, http.StatusCreated)
+		}
+	}
+	login := func(email string) string {
+		status, body := doRequest(t, handler, http.MethodPost, "/v1/user/login", "", map[string]string{
+			"email":    email,
+			"password": "supersecurepassword",
+		})
+		if status != http.StatusOK {
+			t.Fatalf("[TestCase 'login %s'] Result: '%v' %v | Expected: '%v'", email, status, body, http.StatusOK)
+		}
+		token, _ := body["token"].(string)
+		return token
+	}
+
+	register("user@email.com", domain.USER)
+	for role, expected := range map[string]int{domain.ADMIN: http.StatusForbidden, domain.SERVICE: http.StatusBadRequest} {
+		if status, body := doRequest(t, handler, http.MethodPost, "/v1/user", "", map[string]string{
+			"firstName": "first",
+			"lastName":  "last",
+			"email":     "self@email.com",
+			"password":  "supersecurepassword",
+			"role":      role,
+		}); status != expected {
+			t.Errorf("[TestCase 'register as %s'] Result: '%v' %v | Expected: '%v'", role, status, body, expected)
+		}
+	}
+
+	if status, _ := doRequest(t, handler, http.MethodPost, "/v1/user", "", map[string]string{
+		"firstName": "first",
+		"lastName":  "last",
+		"email":     "USER@email.com",
+		"password":  "supersecurepassword",
+		"role":      domain.USER,
+	}); status != http.StatusConflict {
+		t.Errorf("[TestCase 'register twice'] Result: '%v' | Expected: '%v'", status, http.StatusConflict)
+	}
+
+	userToken := login("user@email.com")
+	// The socket server only broadcasts to the clients of the organization /user/me names
+	if _, me := doRequest(t, handler, http.MethodGet, "/v1/user/me", userToken, nil); me["tenantId"] != domain.DefaultTenantID {
+		t.Errorf("[TestCase 'current user tenant'] Result: '%v' | Expected: '%v'", me["tenantId"], domain.DefaultTenantID)
+	}
+
+	status, channel := doRequest(t, handler, http.MethodPost, "/v1/channel", userToken, map[string]string{
+		"userEmail": "user@email.com",
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("[TestCase 'create channel'] Result: '%v' %v | Expected: '%v'", status, channel, http.StatusCreated)
+	}
+	channelId, _ := channel["id"].(string)
+
+	if status, _ := doRequest(t, handler, http.MethodPost, "/v1/message", userToken, map[string]string{
+		"channelId": channelId,
+		"message":   "hello",
+	}); status != http.StatusCreated {
+		t.Errorf("[TestCase 'add message'] Result: '%v' | Expected: '%v'", status, http.StatusCreated)
+	}
+
+	status, fetched := doRequest(t, handler, http.MethodGet, "/v1/channel/"+channelId, userToken, nil)
+	if status != http.StatusOK {
+		t.Fatalf("[TestCase 'get channel'] Result: '%v' %v | Expected: '%v'", status, fetched, http.StatusOK)
+	}
+	if messages, _ := fetched["messages"].([]interface{}); len(messages) != 1 {
+		t.Errorf("[TestCase 'get channel'] Messages: '%v' | Expected: '%v'", fetched["messages"], 1)
+	}
+
+	for url, expected := range map[string]float64{
+		"/v1/channel?userEmail=user@email.com":                        1,
+		"/v1/channel?userEmail=user@email.com&currentStatus=COMPLETE": 0,
+		"/v1/channel?sort=lastMessageAt&order=desc":                   1,
+	} {
+		status, listed := doRequest(t, handler, http.MethodGet, url, userToken, nil)
+		if total, _ := listed["totalCount"].(float64); status != http.StatusOK || total != expected {
+			t.Errorf("[TestCase 'list %s'] Result: '%v', '%v' | Expected: '%v'", url, status, listed["totalCount"], expected)
+		}
+	}
+	if status, _ := doRequest(t, handler, http.MethodGet, "/v1/channel?page=0", userToken, nil); status != http.StatusBadRequest {
+		t.Errorf("[TestCase 'list invalid page'] Result: '%v' | Expected: '%v'", status, http.StatusBadRequest)
+	}
+	if status, _ := doRequest(t, handler, http.MethodGet, "/v1/channel?cursor=forged.cursor", userToken, nil); status != http.StatusBadRequest {
+		t.Errorf("[TestCase 'list forged cursor'] Result: '%v' | Expected: '%v'", status, http.StatusBadRequest)
+	}
+
+	if status, _ := doRequest(t, handler, http.MethodGet, "/v1/audit", userToken, nil); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'audit as user'] Result: '%v' | Expected: '%v'", status, http.StatusForbidden)
+	}
+
+	adminToken := login("admin@email.com")
+	status, found := doRequest(t, handler, http.MethodGet, "/v1/search?q=hello+first", adminToken, nil)
+	if total, _ := found["totalCount"].(float64); status != http.StatusOK || total != 1 {
+		t.Errorf("[TestCase 'search as admin'] Result: '%v', '%v' | Expected: '%v'", status, found, 1)
+	}
+	if status, _ := doRequest(t, handler, http.MethodGet, "/v1/search?q=hello", userToken, nil); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'search as user'] Result: '%v' | Expected: '%v'", status, http.StatusForbidden)
+	}
+
+	status, events := doRequest(t, handler, http.MethodGet, "/v1/audit?actor=user@email.com", adminToken, nil)
+	if status != http.StatusOK {
+		t.Fatalf("[TestCase 'audit as admin'] Result: '%v' %v | Expected: '%v'", status, events, http.StatusOK)
+	}
+	// the registration and the login of the user
+	if total, _ := events["totalCount"].(float64); total != 2 {
+		t.Errorf("[TestCase 'audit as admin'] Result: '%v' | Expected: '%v'", events["totalCount"], 2)
+	}
+
+	if status, body := doRequest(t, handler, http.MethodPost, "/v1/user", adminToken, map[string]string{
+		"firstName": "first",
+		"lastName":  "last",
+		"email":     "staff@email.com",
+		"password":  "supersecurepassword",
+		"role":      domain.ADMIN,
+	}); status != http.StatusCreated {
+		t.Fatalf("[TestCase 'register as admin'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusCreated)
+	}
+	_, events = doRequest(t, handler, http.MethodGet, "/v1/audit?target=staff@email.com", adminToken, nil)
+	if data, _ := events["data"].([]interface{}); len(data) != 1 || data[0].(map[string]interface{})["actor"] != "admin@email.com" {
+		t.Errorf("[TestCase 'audit register as admin'] Result: '%v' | Expected actor: '%v'", events["data"], "admin@email.com")
+	}
+	if status, _ := doRequest(t, handler, http.MethodPost, "/v1/user", "forged.token", map[string]string{
+		"firstName": "first",
+		"lastName":  "last",
+		"email":     "forged@email.com",
+		"password":  "supersecurepassword",
+		"role":      domain.USER,
+	}); status != http.StatusUnauthorized {
+		t.Errorf("[TestCase 'register with a forged token'] Result: '%v' | Expected: '%v'", status, http.StatusUnauthorized)
+	}
+
+	messages, _ := fetched["messages"].([]interface{})
+	if len(messages) == 0 {
+		t.Fatalf("[TestCase 'message id'] Messages: '%v' | Expected: '%v'", fetched["messages"], 1)
+	}
+	messageId, _ := messages[0].(map[string]interface{})["id"].(string)
+	messageURL := "/v1/channel/" + channelId + "/message/" + messageId
+
+	register("other@email.com", domain.USER)
+	for _, tt := range []struct {
+		name     string
+		method   string
+		url      string
+		token    string
+		expected int
+	}{
+		{name: "edit as author", method: http.MethodPut, url: messageURL, token: userToken, expected: http.StatusOK},
+		{name: "edit as someone else", method: http.MethodPut, url: messageURL, token: login("other@email.com"), expected: http.StatusForbidden},
+		{name: "edit unknown message", method: http.MethodPut, url: "/v1/channel/" + channelId + "/message/" + channelId, token: userToken, expected: http.StatusNotFound},
+		{name: "delete as admin", method: http.MethodDelete, url: messageURL, token: adminToken, expected: http.StatusOK},
+		{name: "edit deleted message", method: http.MethodPut, url: messageURL, token: userToken, expected: http.StatusConflict},
+	} {
+		if status, body := doRequest(t, handler, tt.method, tt.url, tt.token, map[string]string{"message": "hello again"}); status != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' %v | Expected: '%v'", tt.name, status, body, tt.expected)
+		}
+	}
+
+	_, fetched = doRequest(t, handler, http.MethodGet, "/v1/channel/"+channelId, userToken, nil)
+	messages, _ = fetched["messages"].([]interface{})
+	if len(messages) != 1 {
+		t.Fatalf("[TestCase 'tombstone'] Messages: '%v' | Expected: '%v'", fetched["messages"], 1)
+	}
+	if tombstone := messages[0].(map[string]interface{}); tombstone["deleted"] != true || tombstone["message"] != "" || tombstone["editedAt"] == nil {
+		t.Errorf("[TestCase 'tombstone'] Result: '%v' | Expected a deleted message without text", tombstone)
+	}
+
+	testAttachments(t, handler, userToken, login("other@email.com"), channelId)
+	testReadReceipts(t, handler, userToken, adminToken, login("other@email.com"))
+	testInternalNotes(t, handler, userToken, adminToken)
+	testCannedResponses(t, handler, userToken, adminToken)
+	testChannelDetails(t, handler, userToken, adminToken)
+	testSatisfaction(t, handler, userToken, adminToken)
+	testSLA(t, handler, userToken, adminToken)
+	testAnalytics(t, handler, userToken, adminToken)
+}
+
+func testReadReceipts(t *testing.T, handler http.Handler, userToken, adminToken, otherToken string) {
+	status, channel := doRequest(t, handler, http.MethodPost, "/v1/channel", userToken, map[string]string{
+		"userEmail": "user@email.com",
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("[TestCase 'create unread channel'] Result: '%v' %v | Expected: '%v'", status, channel, http.StatusCreated)
+	}
+	channelId, _ := channel["id"].(string)
+	for url, text := range map[string]string{
+		"/v1/message":                           "anyone there?",
+		"/v1/channel/" + channelId + "/message": "hello?",
+	} {
+		if status, body := doRequest(t, handler, http.MethodPost, url, userToken, map[string]string{
+			"channelId": channelId,
+			"message":   text,
+		}); status != http.StatusCreated {
+			t.Fatalf("[TestCase 'add unread message to %s'] Result: '%v' %v | Expected: '%v'", url, status, body, http.StatusCreated)
+		}
+		if status, body := doRequest(t, handler, http.MethodPost, url, otherToken, map[string]string{
+			"channelId": channelId,
+			"message":   "let me in",
+		}); status != http.StatusForbidden {
+			t.Errorf("[TestCase 'add message to %s as someone else'] Result: '%v' %v | Expected: '%v'", url, status, body, http.StatusForbidden)
+		}
+	}
+
+	unread := func(token string) interface{} {
+		_, listed := doRequest(t, handler, http.MethodGet, "/v1/channel?limit=50", token, nil)
+		data, _ := listed["data"].([]interface{})
+		for _, item := range data {
+			if listedChannel, _ := item.(map[string]interface{}); listedChannel["id"] == channelId {
+				return listedChannel["unreadCount"]
+			}
+		}
+		return nil
+	}
+	if count := unread(adminToken); count != float64(2) {
+		t.Errorf("[TestCase 'unread before reading'] Result: '%v' | Expected: '%v'", count, 2)
+	}
+	if count := unread(userToken); count != float64(0) {
+		t.Errorf("[TestCase 'unread own messages'] Result: '%v' | Expected: '%v'", count, 0)
+	}
+
+	readURL := "/v1/channel/" + channelId + "/read"
+	if status, body := doRequest(t, handler, http.MethodPut, readURL, otherToken, nil); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'read as someone else'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusForbidden)
+	}
+	if status, body := doRequest(t, handler, http.MethodPut, readURL, adminToken, map[string]string{"messageId": channelId}); status != http.StatusNotFound {
+		t.Errorf("[TestCase 'read unknown message'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusNotFound)
+	}
+	status, read := doRequest(t, handler, http.MethodPut, readURL, adminToken, nil)
+	if status != http.StatusOK || read["participant"] != "admin@email.com" || read["unreadCount"] != float64(0) {
+		t.Errorf("[TestCase 'read as admin'] Result: '%v' %v | Expected: '%v'", status, read, http.StatusOK)
+	}
+	if count := unread(adminToken); count != float64(0) {
+		t.Errorf("[TestCase 'unread after reading'] Result: '%v' | Expected: '%v'", count, 0)
+	}
+
+	_, fetched := doRequest(t, handler, http.MethodGet, "/v1/channel/"+channelId, userToken, nil)
+	markers, _ := fetched["readMarkers"].([]interface{})
+	if len(markers) != 1 || markers[0].(map[string]interface{})["lastReadAt"] != read["lastReadAt"] {
+		t.Errorf("[TestCase 'read markers'] Result: '%v' | Expected the marker of admin@email.com", fetched["readMarkers"])
+	}
+}
+
+// testInternalNotes checks customers never get internal notes, whatever the way they read the channel
+func testInternalNotes(t *testing.T, handler http.Handler, userToken, adminToken string) {
+	status, channel := doRequest(t, handler, http.MethodPost, "/v1/channel", userToken, map[string]string{
+		"userEmail": "user@email.com",
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("[TestCase 'create noted channel'] Result: '%v' %v | Expected: '%v'", status, channel, http.StatusCreated)
+	}
+	channelId, _ := channel["id"].(string)
+	if status, body := doRequest(t, handler, http.MethodPost, "/v1/message", userToken, map[string]string{
+		"channelId": channelId,
+		"message":   "my order is late",
+	}); status != http.StatusCreated {
+		t.Fatalf("[TestCase 'add noted message'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusCreated)
+	}
+
+	noteURL := "/v1/channel/" + channelId + "/note"
+	if status, body := doRequest(t, handler, http.MethodPost, noteURL, userToken, map[string]string{"message": "hi"}); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'note as user'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusForbidden)
+	}
+	if status, body := doRequest(t, handler, http.MethodPost, noteURL, adminToken, map[string]string{}); status != http.StatusBadRequest {
+		t.Errorf("[TestCase 'empty note'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusBadRequest)
+	}
+	status, added := doRequest(t, handler, http.MethodPost, noteURL, adminToken, map[string]string{"message": "courier lost the parcel"})
+	note, _ := added["note"].(map[string]interface{})
+	if status != http.StatusCreated || note["internal"] != true {
+		t.Fatalf("[TestCase 'note as admin'] Result: '%v' %v | Expected: '%v'", status, added, http.StatusCreated)
+	}
+	noteId, _ := note["id"].(string)
+
+	count := func(token string) int {
+		_, fetched := doRequest(t, handler, http.MethodGet, "/v1/channel/"+channelId, token, nil)
+		messages, _ := fetched["messages"].([]interface{})
+		return len(messages)
+	}
+	if got := count(userToken); got != 1 {
+		t.Errorf("[TestCase 'get channel as user'] Result: '%v' | Expected: '%v'", got, 1)
+	}
+	if got := count(adminToken); got != 2 {
+		t.Errorf("[TestCase 'get channel as admin'] Result: '%v' | Expected: '%v'", got, 2)
+	}
+
+	status, sent := doRequest(t, handler, http.MethodPost, "/v1/message", userToken, map[string]string{
+		"channelId": channelId,
+		"message":   "any news?",
+	})
+	if messages, _ := sent["messages"].([]interface{}); status != http.StatusCreated || len(messages) != 2 {
+		t.Errorf("[TestCase 'add message as user'] Result: '%v' %v | Expected: '%v' messages", status, sent, 2)
+	}
+
+	noteMessageURL := "/v1/channel/" + channelId + "/message/" + noteId
+	for _, tt := range []struct {
+		name   string
+		method string
+		url    string
+		body   map[string]string
+	}{
+		{name: "edit note as user", method: http.MethodPut, url: noteMessageURL, body: map[string]string{"message": "hi"}},
+		{name: "delete note as user", method: http.MethodDelete, url: noteMessageURL},
+		{name: "read up to note as user", method: http.MethodPut, url: "/v1/channel/" + channelId + "/read", body: map[string]string{"messageId": noteId}},
+	} {
+		if status, body := doRequest(t, handler, tt.method, tt.url, userToken, tt.body); status != http.StatusNotFound {
+			t.Errorf("[TestCase '%s'] Result: '%v' %v | Expected: '%v'", tt.name, status, body, http.StatusNotFound)
+		}
+	}
+
+	_, listed := doRequest(t, handler, http.MethodGet, "/v1/channel?limit=50", userToken, nil)
+	data, _ := listed["data"].([]interface{})
+	for _, item := range data {
+		if listedChannel, _ := item.(map[string]interface{}); listedChannel["id"] == channelId && listedChannel["unreadCount"] != float64(0) {
+			t.Errorf("[TestCase 'unread notes as user'] Result: '%v' | Expected: '%v'", listedChannel["unreadCount"], 0)
+		}
+	}
+}
+
+func testCannedResponses(t *testing.T, handler http.Handler, userToken, adminToken string) {
+	status, channel := doRequest(t, handler, http.MethodPost, "/v1/channel", userToken, map[string]string{
+		"userEmail": "user@email.com",
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("[TestCase 'create macro channel'] Result: '%v' %v | Expected: '%v'", status, channel, http.StatusCreated)
+	}
+	channelId, _ := channel["id"].(string)
+
+	refund := map[string]interface{}{
+		"shortcut": "/Refund",
+		"title":    "Refund issued",
+		"body":     "Hi {{customer.firstName}}, {{ rep.email }} issued your refund",
+		"tags":     []string{"Billing"},
+		"shared":   true,
+		"macro":    map[string]interface{}{"status": domain.IN_PROGRESS, "addTags": []string{"refund"}},
+	}
+	if status, body := doRequest(t, handler, http.MethodPost, "/v1/cannedresponse", userToken, refund); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'create canned response as user'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusForbidden)
+	}
+	status, created := doRequest(t, handler, http.MethodPost, "/v1/cannedresponse", adminToken, refund)
+	if status != http.StatusCreated || created["shortcut"] != "refund" || created["owner"] != "admin@email.com" {
+		t.Fatalf("[TestCase 'create canned response'] Result: '%v' %v | Expected: '%v'", status, created, http.StatusCreated)
+	}
+	responseId, _ := created["id"].(string)
+
+	for _, tt := range []struct {
+		name     string
+		body     map[string]interface{}
+		expected int
+	}{
+		{name: "shortcut taken", body: refund, expected: http.StatusConflict},
+		{name: "unknown variable", body: map[string]interface{}{"shortcut": "bye", "title": "Bye", "body": "Bye {{customer.age}}"}, expected: http.StatusBadRequest},
+		{name: "unknown macro status", body: map[string]interface{}{"shortcut": "bye", "title": "Bye", "body": "Bye", "macro": map[string]string{"status": "CLOSED"}}, expected: http.StatusBadRequest},
+	} {
+		if status, body := doRequest(t, handler, http.MethodPost, "/v1/cannedresponse", adminToken, tt.body); status != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' %v | Expected: '%v'", tt.name, status, body, tt.expected)
+		}
+	}
+
+	_, listed := doRequest(t, handler, http.MethodGet, "/v1/cannedresponse?shortcut=/ref&tag=billing", adminToken, nil)
+	if listed["count"] != float64(1) {
+		t.Errorf("[TestCase 'search canned responses'] Result: '%v' | Expected: '%v'", listed, 1)
+	}
+	_, listed = doRequest(t, handler, http.MethodGet, "/v1/cannedresponse?shortcut=bye", adminToken, nil)
+	if listed["count"] != float64(0) {
+		t.Errorf("[TestCase 'search unknown shortcut'] Result: '%v' | Expected: '%v'", listed, 0)
+	}
+
+	status, applied := doRequest(t, handler, http.MethodPost, "/v1/channel/"+channelId+"/cannedresponse/"+responseId, adminToken, nil)
+	if status != http.StatusOK || applied["text"] != "Hi first, admin@email.com issued your refund" {
+		t.Errorf("[TestCase 'apply canned response'] Result: '%v' %v | Expected: '%v'", status, applied, http.StatusOK)
+	}
+	if tags, _ := applied["tags"].([]interface{}); applied["currentStatus"] != domain.IN_PROGRESS || applied["repEmail"] != "admin@email.com" || len(tags) != 1 {
+		t.Errorf("[TestCase 'apply macro'] Result: '%v' | Expected: '%v'", applied, domain.IN_PROGRESS)
+	}
+	if status, body := doRequest(t, handler, http.MethodPost, "/v1/channel/"+responseId+"/cannedresponse/"+responseId, adminToken, nil); status != http.StatusNotFound {
+		t.Errorf("[TestCase 'apply to unknown channel'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusNotFound)
+	}
+
+	refund["body"] = "Your refund is on its way"
+	status, updated := doRequest(t, handler, http.MethodPut, "/v1/cannedresponse/"+responseId, adminToken, refund)
+	if status != http.StatusOK || updated["body"] != refund["body"] {
+		t.Errorf("[TestCase 'update canned response'] Result: '%v' %v | Expected: '%v'", status, updated, http.StatusOK)
+	}
+	if status, body := doRequest(t, handler, http.MethodDelete, "/v1/cannedresponse/"+responseId, adminToken, nil); status != http.StatusOK {
+		t.Errorf("[TestCase 'delete canned response'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusOK)
+	}
+	if status, body := doRequest(t, handler, http.MethodGet, "/v1/cannedresponse/"+responseId, adminToken, nil); status != http.StatusNotFound {
+		t.Errorf("[TestCase 'get deleted canned response'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusNotFound)
+	}
+}
+
+// testChannelDetails sets tags, a priority and attributes at creation and as a rep, then lists by them
+func testChannelDetails(t *testing.T, handler http.Handler, userToken, adminToken string) {
+	status, channel := doRequest(t, handler, http.MethodPost, "/v1/channel", userToken, map[string]interface{}{
+		"userEmail":  "user@email.com",
+		"tags":       []string{"Shipping"},
+		"priority":   domain.PriorityHigh,
+		"attributes": map[string]string{"orderId": "A-1"},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("[TestCase 'create classified channel'] Result: '%v' %v | Expected: '%v'", status, channel, http.StatusCreated)
+	}
+	channelId, _ := channel["id"].(string)
+
+	if status, body := doRequest(t, handler, http.MethodPost, "/v1/channel", userToken, map[string]interface{}{
+		"userEmail":  "user@email.com",
+		"attributes": map[string]string{"order id": "A-1"},
+	}); status != http.StatusBadRequest {
+		t.Errorf("[TestCase 'create with an invalid attribute'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusBadRequest)
+	}
+
+	detailsURL := "/v1/channel/" + channelId + "/details"
+	details := map[string]interface{}{"priority": domain.PriorityUrgent, "attributes": map[string]string{"plan": "pro"}}
+	if status, body := doRequest(t, handler, http.MethodPut, detailsURL, userToken, details); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'update details as user'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusForbidden)
+	}
+	if status, body := doRequest(t, handler, http.MethodPut, detailsURL, adminToken, map[string]string{"priority": "CRITICAL"}); status != http.StatusBadRequest {
+		t.Errorf("[TestCase 'unknown priority'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusBadRequest)
+	}
+	status, updated := doRequest(t, handler, http.MethodPut, detailsURL, adminToken, details)
+	if attributes, _ := updated["attributes"].(map[string]interface{}); status != http.StatusOK || updated["priority"] != domain.PriorityUrgent || len(attributes) != 2 {
+		t.Errorf("[TestCase 'update details'] Result: '%v' %v | Expected: '%v'", status, updated, http.StatusOK)
+	}
+	if status, body := doRequest(t, handler, http.MethodPut, "/v1/channel/000000000000000000000000/details", adminToken, details); status != http.StatusNotFound {
+		t.Errorf("[TestCase 'update details of unknown channel'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusNotFound)
+	}
+
+	_, listed := doRequest(t, handler, http.MethodGet, "/v1/channel?tag=shipping&priority=URGENT&attr.orderId=A-1&sort=priority&order=desc", adminToken, nil)
+	data, _ := listed["data"].([]interface{})
+	if len(data) != 1 {
+		t.Fatalf("[TestCase 'list by details'] Result: '%v' | Expected: '%v'", listed, 1)
+	}
+	if listedChannel, _ := data[0].(map[string]interface{}); listedChannel["id"] != channelId || listedChannel["priority"] != domain.PriorityUrgent {
+		t.Errorf("[TestCase 'listed details'] Result: '%v' | Expected: '%v'", listedChannel, channelId)
+	}
+	_, listed = doRequest(t, handler, http.MethodGet, "/v1/channel?tag=shipping&priority=LOW", adminToken, nil)
+	if listed["totalCount"] != float64(0) {
+		t.Errorf("[TestCase 'list by another priority'] Result: '%v' | Expected: '%v'", listed, 0)
+	}
+}
+
+func testAttachments(t *testing.T, handler http.Handler, userToken, otherToken, channelId string) {
+	var (
+		uploadURL = "/v1/channel/" + channelId + "/attachment"
+		png       = "\x89PNG\r\n\x1a\n" + strings.Repeat("x", 64)
+	)
+
+	status, uploaded := doUpload(t, handler, uploadURL, userToken, "screenshot.png", png)
+	if status != http.StatusCreated || uploaded["contentType"] != "image/png" {
+		t.Fatalf("[TestCase 'upload'] Result: '%v' %v | Expected: '%v'", status, uploaded, http.StatusCreated)
+	}
+	attachmentId, _ := uploaded["id"].(string)
+
+	for _, tt := range []struct {
+		name     string
+		token    string
+		content  string
+		expected int
+	}{
+		{name: "upload as someone else", token: otherToken, content: png, expected: http.StatusForbidden},
+		{name: "upload html", token: userToken, content: "<html><script>alert(1)</script></html>", expected: http.StatusUnsupportedMediaType},
+		{name: "upload too large", token: userToken, content: png + strings.Repeat("x", domain.MaxAttachmentSize), expected: http.StatusRequestEntityTooLarge},
+	} {
+		if status, body := doUpload(t, handler, uploadURL, tt.token, "file.png", tt.content); status != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' %v | Expected: '%v'", tt.name, status, body, tt.expected)
+		}
+	}
+
+	if status, body := doRequest(t, handler, http.MethodPost, "/v1/message", userToken, map[string]interface{}{
+		"channelId":     channelId,
+		"attachmentIds": []string{attachmentId},
+	}); status != http.StatusCreated {
+		t.Errorf("[TestCase 'message with attachment'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusCreated)
+	}
+	if status, body := doRequest(t, handler, http.MethodPost, "/v1/message", otherToken, map[string]interface{}{
+		"channelId":     channelId,
+		"attachmentIds": []string{attachmentId},
+	}); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'message with the attachment of someone else'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusForbidden)
+	}
+
+	_, fetched := doRequest(t, handler, http.MethodGet, "/v1/channel/"+channelId, userToken, nil)
+	messages, _ := fetched["messages"].([]interface{})
+	if len(messages) == 0 {
+		t.Fatalf("[TestCase 'attachment in channel'] Messages: '%v'", fetched["messages"])
+	}
+	if attachments, _ := messages[len(messages)-1].(map[string]interface{})["attachments"].([]interface{}); len(attachments) != 1 {
+		t.Errorf("[TestCase 'attachment in channel'] Result: '%v' | Expected: '%v'", attachments, 1)
+	}
+
+	linkURL := uploadURL + "/" + attachmentId
+	if status, _ := doRequest(t, handler, http.MethodGet, linkURL, otherToken, nil); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'link as someone else'] Result: '%v' | Expected: '%v'", status, http.StatusForbidden)
+	}
+	status, link := doRequest(t, handler, http.MethodGet, linkURL, userToken, nil)
+	url, _ := link["url"].(string)
+	if status != http.StatusOK || url == "" {
+		t.Fatalf("[TestCase 'link'] Result: '%v' %v | Expected: '%v'", status, link, http.StatusOK)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != png || w.Header().Get("Content-Type") != "image/png" || w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("[TestCase 'download'] Result: '%v' %v | Expected: '%v'", w.Code, w.Header(), http.StatusOK)
+	}
+
+	for name, forged := range map[string]string{
+		"forged signature": strings.Replace(url, "signature=", "signature=x", 1),
+		"other tenant":     strings.Replace(url, "tenant=default", "tenant=acme", 1),
+		"no signature":     "/v1/attachment/" + attachmentId,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, forged, nil))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", name, w.Code, http.StatusForbidden)
+		}
+	}
+}
+
+func testSatisfaction(t *testing.T, handler http.Handler, userToken, adminToken string) {
+	status, channel := doRequest(t, handler, http.MethodPost, "/v1/channel", userToken, map[string]interface{}{
+		"userEmail": "user@email.com",
+		"tags":      []string{"satisfaction"},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("[TestCase 'create rated channel'] Result: '%v' %v | Expected: '%v'", status, channel, http.StatusCreated)
+	}
+	channelId, _ := channel["id"].(string)
+	channelURL := "/v1/channel/" + channelId
+	ratingURL := channelURL + "/rating"
+
+	if status, body := doRequest(t, handler, http.MethodPut, channelURL, userToken, map[string]string{"updatedBy": "user@email.com", "status": domain.IN_PROGRESS}); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'customer takes own channel'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusForbidden)
+	}
+	if _, fetched := doRequest(t, handler, http.MethodGet, channelURL, userToken, nil); fetched["repEmail"] != "" {
+		t.Errorf("[TestCase 'customer not made rep'] Result: '%v' | Expected: '%v'", fetched["repEmail"], "")
+	}
+	if status, body := doRequest(t, handler, http.MethodPut, channelURL, adminToken, map[string]string{"updatedBy": "user@email.com", "status": domain.IN_PROGRESS}); status != http.StatusOK {
+		t.Fatalf("[TestCase 'claim rated channel'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusOK)
+	}
+	if _, fetched := doRequest(t, handler, http.MethodGet, channelURL, userToken, nil); fetched["repEmail"] != "admin@email.com" {
+		t.Errorf("[TestCase 'claim ignores updatedBy'] Result: '%v' | Expected: '%v'", fetched["repEmail"], "admin@email.com")
+	}
+	_, events := doRequest(t, handler, http.MethodGet, "/v1/audit?target="+channelId, adminToken, nil)
+	if data, _ := events["data"].([]interface{}); len(data) != 1 ||
+		data[0].(map[string]interface{})["actor"] != "admin@email.com" ||
+		data[0].(map[string]interface{})["action"] != domain.AuditChannelClaimed {
+		t.Errorf("[TestCase 'audit claim ignores updatedBy'] Result: '%v' | Expected: '%v' by '%v'", events["data"], domain.AuditChannelClaimed, "admin@email.com")
+	}
+	if status, body := doRequest(t, handler, http.MethodPost, ratingURL, userToken, map[string]interface{}{"score": 5}); status != http.StatusConflict {
+		t.Errorf("[TestCase 'rate open channel'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusConflict)
+	}
+	if status, body := doRequest(t, handler, http.MethodPut, channelURL, adminToken, map[string]string{"status": domain.COMPLETE}); status != http.StatusOK {
+		t.Fatalf("[TestCase 'complete rated channel'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusOK)
+	}
+	if _, fetched := doRequest(t, handler, http.MethodGet, channelURL, userToken, nil); fetched["surveyPending"] != true {
+		t.Errorf("[TestCase 'survey pending'] Result: '%v' | Expected: '%v'", fetched["surveyPending"], true)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		token    string
+		body     map[string]interface{}
+		expected int
+	}{
+		{name: "rate as rep", token: adminToken, body: map[string]interface{}{"score": 5}, expected: http.StatusForbidden},
+		{name: "score out of range", token: userToken, body: map[string]interface{}{"score": 6}, expected: http.StatusBadRequest},
+		{name: "rate", token: userToken, body: map[string]interface{}{"score": 5, "comment": "quick answer"}, expected: http.StatusCreated},
+		{name: "rate twice", token: userToken, body: map[string]interface{}{"score": 1}, expected: http.StatusConflict},
+	} {
+		if status, body := doRequest(t, handler, http.MethodPost, ratingURL, tt.token, tt.body); status != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' %v | Expected: '%v'", tt.name, status, body, tt.expected)
+		}
+	}
+	if _, fetched := doRequest(t, handler, http.MethodGet, channelURL, userToken, nil); fetched["surveyPending"] != false {
+		t.Errorf("[TestCase 'survey answered'] Result: '%v' | Expected: '%v'", fetched["surveyPending"], false)
+	}
+
+	if status, _ := doRequest(t, handler, http.MethodGet, "/v1/report/satisfaction/rep", userToken, nil); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'report as user'] Result: '%v' | Expected: '%v'", status, http.StatusForbidden)
+	}
+	if status, body := doRequest(t, handler, http.MethodGet, "/v1/report/satisfaction/year", adminToken, nil); status != http.StatusBadRequest {
+		t.Errorf("[TestCase 'report by unknown group'] Result: '%v' %v | Expected: '%v'", status, body, http.StatusBadRequest)
+	}
+	status, report := doRequest(t, handler, http.MethodGet, "/v1/report/satisfaction/tag?tag=Satisfaction", adminToken, nil)
+	groups, _ := report["groups"].([]interface{})
+	if status != http.StatusOK || len(groups) != 1 {
+		t.Fatalf("[TestCase 'report by tag'] Result: '%v' %v | Expected: '%v'", status, report, 1)
+	}
+	if group, _ := groups[0].(map[string]interface{}); group["key"] != "satisfaction" || group["csat"] != float64(100) {
+		t.Errorf("[TestCase 'report by tag'] Result: '%v' | Expected: '%v'", group, "satisfaction")
+	}
+}
+
+func testSLA(t *testing.T, handler http.Handler, userToken, adminToken string) {
+	settingsURL := "/v1/organization/settings"
+	for _, tt := range []struct {
+		name     string
+		policies []map[string]interface{}
+		expected int
+	}{
+		{
+			name:     "priority configured twice",
+			policies: []map[string]interface{}{{"priority": domain.PriorityUrgent}, {"priority": domain.PriorityUrgent}},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown priority",
+			policies: []map[string]interface{}{{"priority": "CRITICAL", "firstResponseMinutes": 5}},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "configure urgent channels",
+			policies: []map[string]interface{}{{"priority": domain.PriorityUrgent, "firstResponseMinutes": 0, "resolutionMinutes": 60}},
+			expected: http.StatusOK,
+		},
+	} {
+		body := map[string]interface{}{"timeZone": "UTC", "routingStrategy": domain.RoutingManual, "slaPolicies": tt.policies}
+		if status, response := doRequest(t, handler, http.MethodPut, settingsURL, adminToken, body); status != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' %v | Expected: '%v'", tt.name, status, response, tt.expected)
+		}
+	}
+
+	_, organization := doRequest(t, handler, http.MethodGet, "/v1/organization", adminToken, nil)
+	settings, _ := organization["settings"].(map[string]interface{})
+	if policies, _ := settings["slaPolicies"].([]interface{}); len(policies) != 4 {
+		t.Errorf("[TestCase 'effective SLA policies'] Result: '%v' | Expected: '%v'", settings["slaPolicies"], 4)
+	}
+
+	status, channel := doRequest(t, handler, http.MethodPost, "/v1/channel", userToken, map[string]interface{}{
+		"userEmail": "user@email.com",
+		"priority":  domain.PriorityUrgent,
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("[TestCase 'create urgent channel'] Result: '%v' %v | Expected: '%v'", status, channel, http.StatusCreated)
+	}
+	channelId, _ := channel["id"].(string)
+
+	_, fetched := doRequest(t, handler, http.MethodGet, "/v1/channel/"+channelId, adminToken, nil)
+	sla, _ := fetched["sla"].(map[string]interface{})
+	if sla["status"] != domain.SLAOnTrack || sla["firstResponse"] != nil || sla["resolution"] == nil {
+		t.Errorf("[TestCase 'SLA of the channel'] Result: '%v' | Expected: '%v'", fetched["sla"], domain.SLAOnTrack)
+	}
+	if _, fetched := doRequest(t, handler, http.MethodGet, "/v1/channel/"+channelId, userToken, nil); fetched["sla"] != nil {
+		t.Errorf("[TestCase 'SLA hidden from customers'] Result: '%v' | Expected: '%v'", fetched["sla"], nil)
+	}
+
+	_, listing := doRequest(t, handler, http.MethodGet, "/v1/channel?priorities=URGENT&page=1&limit=10", adminToken, nil)
+	data, _ := listing["data"].([]interface{})
+	for _, item := range data {
+		if listed, _ := item.(map[string]interface{}); listed["sla"] == nil {
+			t.Errorf("[TestCase 'SLA in listings'] Result: '%v' | Expected an SLA", listed)
+		}
+	}
+	if len(data) == 0 {
+		t.Errorf("[TestCase 'SLA in listings'] Result: '%v' | Expected: '%v'", listing, "urgent channels")
+	}
+}
+
+func testAnalytics(t *testing.T, handler http.Handler, userToken, adminToken string) {
+	if status, _ := doRequest(t, handler, http.MethodGet, "/v1/report/channels", userToken, nil); status != http.StatusForbidden {
+		t.Errorf("[TestCase 'analytics for customers'] Result: '%v' | Expected: '%v'", status, http.StatusForbidden)
+	}
+	for name, url := range map[string]string{
+		"unknown interval":   "/v1/report/channels?interval=week",
+		"unknown time zone":  "/v1/report/reps?timeZone=Mars/Olympus",
+		"long hourly series": "/v1/report/channels?interval=hour&from=2021-01-01T00:00:00Z&to=2021-03-01T00:00:00Z",
+		"reversed range":     "/v1/report/reps?from=2021-03-01T00:00:00Z&to=2021-01-01T00:00:00Z",
+	} {
+		if status, body := doRequest(t, handler, http.MethodGet, url, adminToken, nil); status != http.StatusBadRequest {
+			t.Errorf("[TestCase '%s'] Result: '%v' %v | Expected: '%v'", name, status, body, http.StatusBadRequest)
+		}
+	}
+
+	status, channel := doRequest(t, handler, http.MethodPost, "/v1/channel", userToken, map[string]interface{}{
+		"userEmail": "user@email.com",
+		"tags":      []string{"analytics"},
+	})
+	if status != http.StatusCreated {
+		t.Fatalf("[TestCase 'create tagged channel'] Result: '%v' %v | Expected: '%v'", status, channel, http.StatusCreated)
+	}
+
+	from := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	status, report := doRequest(t, handler, http.MethodGet, "/v1/report/channels?interval=hour&tag=Analytics&timeZone=Asia/Kolkata&from="+from, adminToken, nil)
+	if status != http.StatusOK || report["timeZone"] != "Asia/Kolkata" || report["interval"] != domain.AnalyticsByHour {
+		t.Fatalf("[TestCase 'channel analytics'] Result: '%v' %v | Expected: '%v'", status, report, http.StatusOK)
+	}
+	var opened float64
+	series, _ := report["series"].([]interface{})
+	for _, item := range series {
+		bucket, _ := item.(map[string]interface{})
+		count, _ := bucket["opened"].(float64)
+		opened += count
+	}
+	// Two hours back from now span two or three hourly buckets
+	if opened != 1 || len(series) < 2 || len(series) > 3 {
+		t.Errorf("[TestCase 'hourly series of the tag'] Result: '%v' | Expected: '%v'", series, "one channel opened")
+	}
+	if backlog, _ := report["backlog"].(map[string]interface{}); len(backlog) != len(domain.OpenStatuses()) || backlog[domain.ACTIVE] == float64(0) {
+		t.Errorf("[TestCase 'backlog of the tag'] Result: '%v' | Expected: '%v'", report["backlog"], "the open channel")
+	}
+
+	status, reps := doRequest(t, handler, http.MethodGet, "/v1/report/reps", adminToken, nil)
+	if list, ok := reps["reps"].([]interface{}); status != http.StatusOK || !ok {
+		t.Errorf("[TestCase 'rep analytics'] Result: '%v' %v | Expected: '%v'", status, list, http.StatusOK)
+	}
+}
+
+func TestGinEngine_ClientIPMiddleware(t *testing.T) {
+	proxies, err := parseTrustedProxies("10.0.0.0/8, 192.0.2.7")
+	if err != nil {
+		t.Fatalf("[TestCase 'trusted proxies'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	tests := []struct {
+		name         string
+		proxies      trustedProxies
+		remoteAddr   string
+		forwardedFor string
+		expectedIP   string
+	}{
+		{
+			name:         "Forged header without trusted proxies",
+			proxies:      trustedProxies{},
+			remoteAddr:   "192.0.2.1:1234",
+			forwardedFor: "203.0.113.9",
+			expectedIP:   "192.0.2.1",
+		},
+		{
+			name:         "Forged header from an untrusted address",
+			proxies:      proxies,
+			remoteAddr:   "192.0.2.1:1234",
+			forwardedFor: "203.0.113.9",
+			expectedIP:   "192.0.2.1",
+		},
+		{
+			name:         "Client behind a trusted proxy",
+			proxies:      proxies,
+			remoteAddr:   "10.0.0.1:1234",
+			forwardedFor: "203.0.113.9",
+			expectedIP:   "203.0.113.9",
+		},
+		{
+			name:         "Client forging the left of the header behind trusted proxies",
+			proxies:      proxies,
+			remoteAddr:   "10.0.0.1:1234",
+			forwardedFor: "198.51.100.4, 203.0.113.9, 192.0.2.7",
+			expectedIP:   "203.0.113.9",
+		},
+		{
+			name:       "Trusted proxy without the header",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.1:1234",
+			expectedIP: "10.0.0.1",
+		},
+		{
+			name:         "Garbage in the header",
+			proxies:      proxies,
+			remoteAddr:   "10.0.0.1:1234",
+			forwardedFor: "203.0.113.9, not-an-ip",
+			expectedIP:   "10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := ginEngine{router: gin.New(), proxies: tt.proxies}
+			g.router.Use(g.ClientIPMiddleware())
+
+			var got string
+			g.router.GET("/ip", func(c *gin.Context) {
+				got = middleware.ClientIPFromContext(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			req.Header.Set("X-Real-IP", "203.0.113.200")
+			g.router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.expectedIP {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expectedIP)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	if _, err := parseTrustedProxies("10.0.0.0/8,proxy.local"); err == nil {
+		t.Errorf("[TestCase 'invalid proxy'] Result: '%v' | Expected: '%v'", err, "error")
+	}
+	if _, err := parseTrustedProxies("10.0.0.0/33"); err == nil {
+		t.Errorf("[TestCase 'invalid range'] Result: '%v' | Expected: '%v'", err, "error")
+	}
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"chat-api/domain"
+)
+
+var (
+	errInvalidBlobStoreInstance = errors.New("invalid blob store instance")
+	errInvalidKey               = errors.New("invalid blob key")
+)
+
+const (
+	InstanceLocal int = iota
+	InstanceS3
+)
+
+func NewBlobStoreFactory(instance int) (domain.BlobStore, error) {
+	switch instance {
+	case InstanceLocal:
+		return NewLocalStore(os.Getenv("ATTACHMENTS_DIR"))
+	case InstanceS3:
+		return NewS3Store(newConfigS3())
+	default:
+		return nil, errInvalidBlobStoreInstance
+	}
+}
+
+// validKey refuses keys that could escape the store, such as relative segments
+func validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" {
+			return false
+		}
+		for _, r := range segment {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
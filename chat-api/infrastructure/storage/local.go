@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"chat-api/domain"
+)
+
+// localStore keeps blobs as files under a directory, for single instance
+// deployments and local development
+type localStore struct {
+	dir string
+}
+
+// NewLocalStore keeps blobs under dir, ./attachments when it is empty
+func NewLocalStore(dir string) (*localStore, error) {
+	if dir == "" {
+		dir = "attachments"
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	return &localStore{dir: dir}, nil
+}
+
+// Put writes to a temporary file first so readers never see a partial blob
+func (l *localStore) Put(_ context.Context, key string, content io.Reader, _ int64, _ string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), ".upload-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func (l *localStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, domain.ErrBlobNotFound
+	}
+	return file, err
+}
+
+func (l *localStore) Delete(_ context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *localStore) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", errInvalidKey
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,237 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"chat-api/domain"
+)
+
+// unsignedPayload lets uploads stream without hashing their content first
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+type configS3 struct {
+	endpoint        string
+	bucket          string
+	region          string
+	accessKeyId     string
+	secretAccessKey string
+}
+
+func newConfigS3() configS3 {
+	region := os.Getenv("S3_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	return configS3{
+		endpoint:        strings.TrimSuffix(os.Getenv("S3_ENDPOINT"), "/"),
+		bucket:          os.Getenv("S3_BUCKET"),
+		region:          region,
+		accessKeyId:     os.Getenv("S3_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+	}
+}
+
+// s3Store keeps blobs in a bucket of any S3 compatible service, such as AWS S3
+// or MinIO. Objects are addressed path style, endpoint/bucket/key, and requests
+// are signed with AWS Signature Version 4
+type s3Store struct {
+	config configS3
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Store(c configS3) (*s3Store, error) {
+	if c.endpoint == "" || c.bucket == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required")
+	}
+	return &s3Store{config: c, client: &http.Client{Timeout: 60 * time.Second}, now: time.Now}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	req, err := s.request(ctx, http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(req)
+	if err != nil && err != domain.ErrBlobNotFound {
+		return err
+	}
+	if res != nil {
+		res.Body.Close()
+	}
+	return nil
+}
+
+func (s *s3Store) request(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if !validKey(key) {
+		return nil, errInvalidKey
+	}
+	return http.NewRequestWithContext(ctx, method, s.config.endpoint+"/"+s.config.bucket+"/"+key, body)
+}
+
+// do signs and sends the request, a missing object is ErrBlobNotFound and any
+// other failure carries the status and the error body of the service
+func (s *s3Store) do(req *http.Request) (*http.Response, error) {
+	signRequest(req, unsignedPayload, s.config, s.now())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res, nil
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrBlobNotFound
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, res.Status, body)
+}
+
+// signRequest adds the Signature Version 4 authorization of the request, every
+// header already set on it is signed along with the host
+func signRequest(req *http.Request, payloadHash string, c configS3, now time.Time) {
+	var (
+		amzDate = now.UTC().Format("20060102T150405Z")
+		date    = amzDate[:8]
+		scope   = date + "/" + c.region + "/s3/aws4_request"
+	)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretAccessKey), date)
+	for _, part := range []string{c.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKeyId,
+		scope,
+		signedHeaders,
+		hex.EncodeToString(hmacSHA256(key, stringToSign)),
+	))
+}
+
+func canonicalPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(query url.Values) string {
+	var params []string
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// uriEncode escapes everything but the unreserved characters of RFC 3986, as
+// Signature Version 4 expects
+func uriEncode(value string) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		if b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b == '-' || b == '_' || b == '.' || b == '~' {
+			encoded.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&encoded, "%%%02X", b)
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -32,6 +32,17 @@ func NewGoPlayground() (validator.Validator, error) {
 	if err := en_translations.RegisterDefaultTranslations(v, translate); err != nil {
 		return nil, errors.New("translator not found")
 	}
+	// The default translations leave required_without untranslated, it reads
+	// like required since the field is only asked for when the other is missing
+	err := v.RegisterTranslation("required_without", translate, func(ut ut.Translator) error {
+		return ut.Add("required_without", "{0} is a required field", true)
+	}, func(ut ut.Translator, fe go_playground.FieldError) string {
+		t, _ := ut.T("required_without", fe.Field())
+		return t
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &goPlayground{validator: v, translate: translate}, nil
 }
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	ActivateTwoFactorUseCase interface {
+		Execute(context.Context, ActivateTwoFactorInput) (ActivateTwoFactorOutput, error)
+	}
+
+	// Input data
+	ActivateTwoFactorInput struct {
+		Email string `json:"-" validate:"required"`
+		Code  string `json:"code" validate:"required"`
+		IP    string `json:"-"`
+	}
+
+	// Output port
+	ActivateTwoFactorPresenter interface {
+		Output([]string) ActivateTwoFactorOutput
+	}
+
+	// Output data
+	ActivateTwoFactorOutput struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+
+	activateTwoFactorInteractor struct {
+		repo       domain.UserRepository
+		twoFactor  domain.TwoFactorService
+		audit      domain.AuditLogger
+		presenter  ActivateTwoFactorPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewActivateTwoFactorInteractor(
+	repo domain.UserRepository,
+	twoFactor domain.TwoFactorService,
+	audit domain.AuditLogger,
+	presenter ActivateTwoFactorPresenter,
+	t time.Duration,
+) ActivateTwoFactorUseCase {
+	return activateTwoFactorInteractor{
+		repo:       repo,
+		twoFactor:  twoFactor,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a activateTwoFactorInteractor) Execute(ctx context.Context, input ActivateTwoFactorInput) (ActivateTwoFactorOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	user, err := a.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		return a.presenter.Output(nil), err
+	}
+
+	if user.TwoFactorEnabled() {
+		return a.presenter.Output(nil), domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	if user.TwoFactorSecret() == "" {
+		return a.presenter.Output(nil), domain.ErrTwoFactorNotEnrolled
+	}
+
+	secret, err := a.twoFactor.DecryptSecret(ctx, user.TwoFactorSecret())
+	if err != nil {
+		return a.presenter.Output(nil), err
+	}
+
+	if !a.twoFactor.ValidateCode(ctx, secret, input.Code) {
+		return a.presenter.Output(nil), domain.ErrInvalidTwoFactorCode
+	}
+
+	recoveryCodes, err := a.twoFactor.GenerateRecoveryCodes(ctx)
+	if err != nil {
+		return a.presenter.Output(nil), err
+	}
+
+	hashedCodes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashedCodes = append(hashedCodes, a.twoFactor.HashRecoveryCode(ctx, code))
+	}
+
+	user.UpdateTwoFactor(user.TwoFactorSecret(), true, hashedCodes)
+	if err := a.repo.UpdateTwoFactor(ctx, user); err != nil {
+		return a.presenter.Output(nil), err
+	}
+
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditTwoFactorEnabled,
+		Actor:     user.Email(),
+		Target:    user.Email(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return a.presenter.Output(recoveryCodes), nil
+}
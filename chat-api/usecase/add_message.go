@@ -13,10 +13,18 @@ type (
 		Execute(context.Context, CreateMessageInput) (CreateMessageOutput, error)
 	}
 
+	// CreateMessageInput needs a text, attachments or both. Attachments must have
+	// been uploaded to the channel by the author of the message, who must take
+	// part in the channel unless Admin is set
 	CreateMessageInput struct {
-		ChannelId   string `json:"channelId" validate:"required"`
-		MessageFrom string `json:"messageFrom" validate:"required"`
-		Message     string `json:"message" validate:"required"`
+		ChannelId     string   `json:"channelId" validate:"required"`
+		MessageFrom   string   `json:"messageFrom" validate:"required"`
+		Message       string   `json:"message" validate:"required_without=AttachmentIds"`
+		AttachmentIds []string `json:"attachmentIds" validate:"omitempty,max=10,dive,required"`
+		// Internal returns the internal notes of the channel too, only reps see them
+		Internal bool `json:"-"`
+		// Admin lets reps answer channels they are not assigned to yet
+		Admin bool `json:"-"`
 	}
 
 	// Output port
@@ -24,10 +32,24 @@ type (
 		Output(domain.Channel) CreateMessageOutput
 	}
 
+	// MessageOutput of a deleted message is a tombstone without text nor attachments
 	MessageOutput struct {
-		MessageFrom string    `json:"messageFrom"`
-		Message     string    `json:"message"`
-		Timestamp   time.Time `json:"timestamp"`
+		Id          string                    `json:"id"`
+		MessageFrom string                    `json:"messageFrom"`
+		Message     string                    `json:"message"`
+		Deleted     bool                      `json:"deleted"`
+		Timestamp   time.Time                 `json:"timestamp"`
+		EditedAt    *time.Time                `json:"editedAt,omitempty"`
+		Attachments []MessageAttachmentOutput `json:"attachments,omitempty"`
+		Internal    bool                      `json:"internal,omitempty"`
+	}
+
+	// MessageAttachmentOutput is downloaded through a link from GetAttachmentURLUseCase
+	MessageAttachmentOutput struct {
+		Id          string `json:"id"`
+		FileName    string `json:"fileName"`
+		ContentType string `json:"contentType"`
+		Size        int64  `json:"size"`
 	}
 
 	// Output data
@@ -41,21 +63,27 @@ type (
 	}
 
 	createMessageInteractor struct {
-		repo       domain.ChannelRepository
-		presenter  CreateMessagePresenter
-		ctxTimeout time.Duration
+		repo        domain.ChannelRepository
+		attachments domain.AttachmentRepository
+		index       domain.SearchIndex
+		presenter   CreateMessagePresenter
+		ctxTimeout  time.Duration
 	}
 )
 
 func NewCreateMessageInteractor(
 	repo domain.ChannelRepository,
+	attachments domain.AttachmentRepository,
+	index domain.SearchIndex,
 	presenter CreateMessagePresenter,
 	t time.Duration,
 ) CreateMessageUseCase {
 	return createMessageInteractor{
-		repo:       repo,
-		presenter:  presenter,
-		ctxTimeout: t,
+		repo:        repo,
+		attachments: attachments,
+		index:       index,
+		presenter:   presenter,
+		ctxTimeout:  t,
 	}
 }
 
@@ -69,12 +97,60 @@ func (c createMessageInteractor) Execute(ctx context.Context, input CreateMessag
 		return c.presenter.Output(domain.Channel{}), err
 	}
 
-	channel.AddMessage(input.MessageFrom, input.Message, time.Now())
+	if !input.Admin && !channel.IsParticipant(input.MessageFrom) {
+		return c.presenter.Output(domain.Channel{}), domain.ErrNotChannelParticipant
+	}
+
+	attachments, err := messageAttachments(ctx, c.attachments, channel, input.MessageFrom, input.AttachmentIds)
+	if err != nil {
+		return c.presenter.Output(domain.Channel{}), err
+	}
+
+	channel.AddMessage(input.MessageFrom, input.Message, time.Now(), attachments...)
 
 	err = c.repo.AddMessage(ctx, channel)
 	if err != nil {
 		return c.presenter.Output(domain.Channel{}), err
 	}
+	c.index.IndexChannel(ctx, channel)
 
+	if !input.Internal {
+		channel = channel.WithoutInternalNotes()
+	}
 	return c.presenter.Output(channel), nil
 }
+
+// messageAttachments reads the attachments a message references, attachments
+// of another channel or author are reported as not found
+func messageAttachments(
+	ctx context.Context,
+	repo domain.AttachmentRepository,
+	channel domain.Channel,
+	author string,
+	ids []string,
+) ([]domain.MessageAttachment, error) {
+	if len(ids) > domain.MaxMessageAttachments {
+		return nil, domain.ErrTooManyAttachments
+	}
+
+	var (
+		attachments []domain.MessageAttachment
+		seen        = map[string]bool{}
+	)
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		attachment, err := repo.GetAttachmentById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if attachment.ChannelId() != channel.Id().Hex() || attachment.UploadedBy() != domain.NormalizeEmail(author) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		attachments = append(attachments, attachment.MessageAttachment())
+	}
+	return attachments, nil
+}
@@ -92,7 +92,7 @@ func TestAddMessageInteractor_Execute(t *testing.T) {
 			name: "create message successfully",
 			args: args{input: CreateMessageInput{
 				ChannelId:   newChannelId.Hex(),
-				MessageFrom: "validemail@gmail.com",
+				MessageFrom: "testemail@email.com",
 				Message:     "this is a test email",
 			}},
 			channelRepo: mockAddMessageRepo{
@@ -126,11 +126,69 @@ func TestAddMessageInteractor_Execute(t *testing.T) {
 				// CreatedAt:     time.Now(),
 			},
 		},
+		{
+			name: "create message as someone outside the channel",
+			args: args{input: CreateMessageInput{
+				ChannelId:   newChannelId.Hex(),
+				MessageFrom: "validemail@gmail.com",
+				Message:     "this is a test email",
+			}},
+			channelRepo: mockAddMessageRepo{
+				addMessageFake: func() error {
+					return nil
+				},
+				findByIDFake: func() (domain.Channel, error) {
+					return domain.NewChannel(
+						newChannelId,
+						"testemail@email.com",
+						domain.ACTIVE,
+						time.Now(),
+						time.Now(),
+					), nil
+				},
+			},
+			presenter:     mockAddMessagePresenter{result: CreateMessageOutput{}},
+			expected:      CreateMessageOutput{},
+			expectedError: domain.ErrNotChannelParticipant.Error(),
+		},
+		{
+			name: "create message as a rep not assigned yet",
+			args: args{input: CreateMessageInput{
+				ChannelId:   newChannelId.Hex(),
+				MessageFrom: "repTestemail@email.com",
+				Message:     "this is a test email",
+				Admin:       true,
+			}},
+			channelRepo: mockAddMessageRepo{
+				addMessageFake: func() error {
+					return nil
+				},
+				findByIDFake: func() (domain.Channel, error) {
+					return domain.NewChannel(
+						newChannelId,
+						"testemail@email.com",
+						domain.ACTIVE,
+						time.Now(),
+						time.Now(),
+					), nil
+				},
+			},
+			presenter: mockAddMessagePresenter{result: CreateMessageOutput{
+				Id:            newChannelId.Hex(),
+				UserEmail:     "testemail@email.com",
+				CurrentStatus: domain.ACTIVE,
+			}},
+			expected: CreateMessageOutput{
+				Id:            newChannelId.Hex(),
+				UserEmail:     "testemail@email.com",
+				CurrentStatus: domain.ACTIVE,
+			},
+		},
 		{
 			name: "create messages returning error",
 			args: args{input: CreateMessageInput{
 				ChannelId:   "",
-				MessageFrom: "",
+				MessageFrom: "testemail@email.com",
 				Message:     "",
 			}},
 			channelRepo: mockAddMessageRepo{
@@ -155,10 +213,10 @@ func TestAddMessageInteractor_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var uc = NewCreateMessageInteractor(tt.channelRepo, tt.presenter, time.Second)
+			var uc = NewCreateMessageInteractor(tt.channelRepo, mockAttachmentRepo{}, mockSearchIndex{}, tt.presenter, time.Second)
 
 			got, err := uc.Execute(context.Background(), tt.args.input)
-			if (err != nil) && (err.Error() != tt.expectedError) {
+			if (err != nil) != (tt.expectedError != "") || (err != nil && err.Error() != tt.expectedError) {
 				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
 				return
 			}
@@ -170,3 +228,90 @@ func TestAddMessageInteractor_Execute(t *testing.T) {
 	}
 
 }
+
+func TestCreateMessageInteractor_Attachments(t *testing.T) {
+	t.Parallel()
+
+	var (
+		channel   = attachmentChannel()
+		channelId = channel.Id().Hex()
+		own       = domain.NewAttachment(primitive.NewObjectID(), channelId, "user@email.com", "a.png", "image/png", 10, time.Now())
+		repFile   = domain.NewAttachment(primitive.NewObjectID(), channelId, "rep@email.com", "b.png", "image/png", 10, time.Now())
+		elsewhere = domain.NewAttachment(primitive.NewObjectID(), primitive.NewObjectID().Hex(), "user@email.com", "c.png", "image/png", 10, time.Now())
+		repo      = mockAttachmentRepo{attachments: map[string]domain.Attachment{
+			own.Id().Hex():       own,
+			repFile.Id().Hex():   repFile,
+			elsewhere.Id().Hex(): elsewhere,
+		}}
+		tooMany []string
+	)
+	for i := 0; i <= domain.MaxMessageAttachments; i++ {
+		tooMany = append(tooMany, primitive.NewObjectID().Hex())
+	}
+
+	tests := []struct {
+		name          string
+		attachmentIds []string
+		expected      []domain.MessageAttachment
+		expectedError error
+	}{
+		{
+			name:          "own attachment, listed twice",
+			attachmentIds: []string{own.Id().Hex(), own.Id().Hex()},
+			expected:      []domain.MessageAttachment{own.MessageAttachment()},
+		},
+		{
+			name:          "attachment of the other participant",
+			attachmentIds: []string{repFile.Id().Hex()},
+			expectedError: domain.ErrAttachmentNotFound,
+		},
+		{
+			name:          "attachment of another channel",
+			attachmentIds: []string{elsewhere.Id().Hex()},
+			expectedError: domain.ErrAttachmentNotFound,
+		},
+		{
+			name:          "unknown attachment",
+			attachmentIds: []string{primitive.NewObjectID().Hex()},
+			expectedError: domain.ErrAttachmentNotFound,
+		},
+		{
+			name:          "too many attachments",
+			attachmentIds: tooMany,
+			expectedError: domain.ErrTooManyAttachments,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				stored domain.Channel
+				uc     = NewCreateMessageInteractor(
+					mockMessageRepo{channel: channel, updated: &stored},
+					repo,
+					mockSearchIndex{},
+					mockAddMessagePresenter{},
+					time.Second,
+				)
+			)
+
+			_, err := uc.Execute(context.Background(), CreateMessageInput{
+				ChannelId:     channelId,
+				MessageFrom:   "User@Email.com",
+				AttachmentIds: tt.attachmentIds,
+			})
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			messages := stored.Messages()
+			if len(messages) != 1 || !reflect.DeepEqual(messages[0].Attachments, tt.expected) {
+				t.Errorf("[TestCase '%s'] Stored: '%+v' | Expected: '%+v'", tt.name, messages, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	AddNoteUseCase interface {
+		Execute(context.Context, AddNoteInput) (AddNoteOutput, error)
+	}
+
+	// AddNoteInput needs a text, attachments or both, like CreateMessageInput.
+	// Notes are only shown to reps, never to the customer of the channel
+	AddNoteInput struct {
+		ChannelId     string   `json:"channelId" validate:"required"`
+		Message       string   `json:"message" validate:"required_without=AttachmentIds"`
+		AttachmentIds []string `json:"attachmentIds" validate:"omitempty,max=10,dive,required"`
+		// Author is the authenticated rep
+		Author string `json:"-" validate:"required"`
+	}
+
+	// Output port
+	AddNotePresenter interface {
+		Output(domain.Channel, domain.Message) AddNoteOutput
+	}
+
+	// Output data
+	AddNoteOutput struct {
+		ChannelId string        `json:"channelId"`
+		Note      MessageOutput `json:"note"`
+	}
+
+	addNoteInteractor struct {
+		repo        domain.ChannelRepository
+		attachments domain.AttachmentRepository
+		index       domain.SearchIndex
+		notifier    domain.ChannelNotifier
+		presenter   AddNotePresenter
+		ctxTimeout  time.Duration
+	}
+)
+
+func NewAddNoteInteractor(
+	repo domain.ChannelRepository,
+	attachments domain.AttachmentRepository,
+	index domain.SearchIndex,
+	notifier domain.ChannelNotifier,
+	presenter AddNotePresenter,
+	t time.Duration,
+) AddNoteUseCase {
+	return addNoteInteractor{
+		repo:        repo,
+		attachments: attachments,
+		index:       index,
+		notifier:    notifier,
+		presenter:   presenter,
+		ctxTimeout:  t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a addNoteInteractor) Execute(ctx context.Context, input AddNoteInput) (AddNoteOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	channel, err := a.repo.GetChannelById(ctx, input.ChannelId)
+	if err != nil {
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	attachments, err := messageAttachments(ctx, a.attachments, channel, input.Author, input.AttachmentIds)
+	if err != nil {
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	channel.AddNote(input.Author, input.Message, time.Now(), attachments...)
+	note := channel.Messages()[len(channel.Messages())-1]
+
+	if err := a.repo.AddMessage(ctx, channel); err != nil {
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+	a.index.IndexChannel(ctx, channel)
+
+	a.notifier.Notify(ctx, domain.ChannelEvent{
+		Type:        domain.ChannelEventNote,
+		ChannelId:   channel.Id().Hex(),
+		UserEmail:   channel.UserEmail(),
+		MessageId:   note.Id.Hex(),
+		MessageFrom: note.MessageFrom,
+		Message:     note.Message,
+		Attachments: note.Attachments,
+		Internal:    true,
+		Timestamp:   note.Timestamp,
+	})
+
+	return a.presenter.Output(channel, note), nil
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockAddNotePresenter struct{}
+
+func (m mockAddNotePresenter) Output(channel domain.Channel, note domain.Message) AddNoteOutput {
+	return AddNoteOutput{ChannelId: channel.Id().Hex(), Note: MessageOutput{Message: note.Message, Internal: note.Internal}}
+}
+
+func TestAddNoteInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		channel   = attachmentChannel()
+		channelId = channel.Id().Hex()
+		own       = domain.NewAttachment(primitive.NewObjectID(), channelId, "rep@email.com", "refund.pdf", "application/pdf", 10, time.Now())
+		customers = domain.NewAttachment(primitive.NewObjectID(), channelId, "user@email.com", "a.png", "image/png", 10, time.Now())
+		repo      = mockAttachmentRepo{attachments: map[string]domain.Attachment{
+			own.Id().Hex():       own,
+			customers.Id().Hex(): customers,
+		}}
+	)
+
+	tests := []struct {
+		name          string
+		message       string
+		attachmentIds []string
+		expected      []domain.MessageAttachment
+		expectedError error
+	}{
+		{
+			name:    "text",
+			message: "customer verified, refund approved",
+		},
+		{
+			name:          "own attachment",
+			attachmentIds: []string{own.Id().Hex()},
+			expected:      []domain.MessageAttachment{own.MessageAttachment()},
+		},
+		{
+			name:          "attachment of the customer",
+			attachmentIds: []string{customers.Id().Hex()},
+			expectedError: domain.ErrAttachmentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				stored   domain.Channel
+				notified []domain.ChannelEvent
+				uc       = NewAddNoteInteractor(
+					mockMessageRepo{channel: channel, updated: &stored},
+					repo,
+					mockSearchIndex{},
+					mockChannelNotifier{events: &notified},
+					mockAddNotePresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), AddNoteInput{
+				ChannelId:     channelId,
+				Message:       tt.message,
+				AttachmentIds: tt.attachmentIds,
+				Author:        "Rep@Email.com",
+			})
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+			if err != nil {
+				if len(notified) != 0 || !stored.Id().IsZero() {
+					t.Errorf("[TestCase '%s'] Notified: '%v' | Expected: nothing stored nor notified", tt.name, notified)
+				}
+				return
+			}
+
+			if expected := (AddNoteOutput{ChannelId: channelId, Note: MessageOutput{Message: tt.message, Internal: true}}); !reflect.DeepEqual(got, expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, expected)
+			}
+			messages := stored.Messages()
+			if len(messages) != 1 || !messages[0].Internal || !reflect.DeepEqual(messages[0].Attachments, tt.expected) {
+				t.Fatalf("[TestCase '%s'] Stored: '%+v' | Expected one internal note", tt.name, messages)
+			}
+			expected := []domain.ChannelEvent{{
+				Type:        domain.ChannelEventNote,
+				ChannelId:   channelId,
+				UserEmail:   "user@email.com",
+				MessageId:   messages[0].Id.Hex(),
+				MessageFrom: "Rep@Email.com",
+				Message:     tt.message,
+				Attachments: tt.expected,
+				Internal:    true,
+				Timestamp:   messages[0].Timestamp,
+			}}
+			if !reflect.DeepEqual(notified, expected) {
+				t.Errorf("[TestCase '%s'] Notified: '%v' | Expected: '%v'", tt.name, notified, expected)
+			}
+		})
+	}
+}
+
+type mockCreateMessageChannelPresenter struct {
+	channel *domain.Channel
+}
+
+func (m mockCreateMessageChannelPresenter) Output(channel domain.Channel) CreateMessageOutput {
+	*m.channel = channel
+	return CreateMessageOutput{}
+}
+
+func TestCreateMessageInteractor_InternalNotes(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name     string
+		internal bool
+		expected int
+	}{
+		{name: "customer", expected: 1},
+		{name: "rep", internal: true, expected: 2},
+	} {
+		channel := attachmentChannel()
+		channel.AddNote("rep@email.com", "customer verified", time.Now())
+
+		var (
+			stored    domain.Channel
+			presented domain.Channel
+			uc        = NewCreateMessageInteractor(
+				mockMessageRepo{channel: channel, updated: &stored},
+				mockAttachmentRepo{},
+				mockSearchIndex{},
+				mockCreateMessageChannelPresenter{channel: &presented},
+				time.Second,
+			)
+		)
+
+		_, err := uc.Execute(context.Background(), CreateMessageInput{
+			ChannelId:   channel.Id().Hex(),
+			MessageFrom: "user@email.com",
+			Message:     "any news?",
+			Internal:    tt.internal,
+		})
+		if err != nil || len(stored.Messages()) != 2 {
+			t.Fatalf("[TestCase '%s'] Result: '%v', '%v' | Expected: '%v'", tt.name, stored.Messages(), err, 2)
+		}
+		if len(presented.Messages()) != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%+v' | Expected: '%d' messages", tt.name, presented.Messages(), tt.expected)
+		}
+	}
+}
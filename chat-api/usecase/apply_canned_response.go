@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	ApplyCannedResponseUseCase interface {
+		Execute(context.Context, ApplyCannedResponseInput) (ApplyCannedResponseOutput, error)
+	}
+
+	// ApplyCannedResponseInput inserts a response in a channel. The text is only
+	// rendered, the rep sends it as a message once reviewed, while the macro of
+	// the response is applied to the channel right away
+	ApplyCannedResponseInput struct {
+		ChannelId  string `json:"channelId" validate:"required"`
+		ResponseId string `json:"responseId" validate:"required"`
+		// Rep is the authenticated rep
+		Rep string `json:"-" validate:"required"`
+		IP  string `json:"-"`
+	}
+
+	// Output port
+	ApplyCannedResponsePresenter interface {
+		Output(domain.Channel, string) ApplyCannedResponseOutput
+	}
+
+	// Output data
+	ApplyCannedResponseOutput struct {
+		ChannelId     string   `json:"channelId"`
+		Text          string   `json:"text"`
+		CurrentStatus string   `json:"currentStatus"`
+		RepEmail      string   `json:"repEmail"`
+		Tags          []string `json:"tags"`
+	}
+
+	applyCannedResponseInteractor struct {
+		repo       domain.ChannelRepository
+		responses  domain.CannedResponseRepository
+		users      domain.UserRepository
+		index      domain.SearchIndex
+		notifier   domain.ChannelNotifier
+		audit      domain.AuditLogger
+		presenter  ApplyCannedResponsePresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewApplyCannedResponseInteractor(
+	repo domain.ChannelRepository,
+	responses domain.CannedResponseRepository,
+	users domain.UserRepository,
+	index domain.SearchIndex,
+	notifier domain.ChannelNotifier,
+	audit domain.AuditLogger,
+	presenter ApplyCannedResponsePresenter,
+	t time.Duration,
+) ApplyCannedResponseUseCase {
+	return applyCannedResponseInteractor{
+		repo:       repo,
+		responses:  responses,
+		users:      users,
+		index:      index,
+		notifier:   notifier,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a applyCannedResponseInteractor) Execute(ctx context.Context, input ApplyCannedResponseInput) (ApplyCannedResponseOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	response, err := visibleCannedResponse(ctx, a.responses, input.ResponseId, input.Rep)
+	if err != nil {
+		return a.presenter.Output(domain.Channel{}, ""), err
+	}
+
+	channel, err := a.repo.GetChannelById(ctx, input.ChannelId)
+	if err != nil {
+		return a.presenter.Output(domain.Channel{}, ""), err
+	}
+
+	var customer domain.User
+	if !channel.IsGuest() {
+		if customer, err = a.user(ctx, channel.UserEmail()); err != nil {
+			return a.presenter.Output(domain.Channel{}, ""), err
+		}
+	}
+	rep, err := a.user(ctx, input.Rep)
+	if err != nil {
+		return a.presenter.Output(domain.Channel{}, ""), err
+	}
+	text := response.Render(domain.TemplateValues(channel, customer, rep))
+
+	if err := a.applyMacro(ctx, &channel, response.Macro(), input); err != nil {
+		return a.presenter.Output(domain.Channel{}, ""), err
+	}
+
+	return a.presenter.Output(channel, text), nil
+}
+
+// user fetches the user behind a template variable, unknown users render as blanks
+func (a applyCannedResponseInteractor) user(ctx context.Context, email string) (domain.User, error) {
+	user, err := a.users.GetUserByEmail(ctx, email)
+	if err != nil && err != domain.ErrUserNotFound {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// applyMacro changes the status and the tags of the channel the way the macro says,
+// the status change is audited like one made from the status endpoint
+func (a applyCannedResponseInteractor) applyMacro(ctx context.Context, channel *domain.Channel, macro domain.Macro, input ApplyCannedResponseInput) error {
+	var changed bool
+
+	if macro.Status != "" && (macro.Status != channel.CurrentStatus() || (macro.Status == domain.IN_PROGRESS && channel.RepEmail() != input.Rep)) {
+		statusInput := UpdateChannelStatusInput{
+			ID:     input.ChannelId,
+			Status: macro.Status,
+			Actor:  input.Rep,
+			IP:     input.IP,
+			Admin:  true,
+		}
+		action := channelStatusAuditAction(*channel, statusInput)
+
+		if macro.Status == domain.IN_PROGRESS {
+			channel.UpdateRepEmail(input.Rep)
+		}
+		channel.UpdateStatus(macro.Status, input.Rep, time.Now().Unix())
+
+		if err := a.repo.UpdateChannelStatus(ctx, *channel); err != nil {
+			a.record(ctx, action, input, domain.AuditOutcomeFailure)
+			return err
+		}
+		a.record(ctx, action, input, domain.AuditOutcomeSuccess)
+		promptSatisfactionSurvey(ctx, a.notifier, *channel)
+		changed = true
+	}
+
+	added := channel.AddTags(macro.AddTags...)
+	removed := channel.RemoveTags(macro.RemoveTags...)
+	if added || removed {
+		if err := a.repo.UpdateChannelDetails(ctx, *channel); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if changed {
+		a.index.IndexChannel(ctx, *channel)
+	}
+	return nil
+}
+
+func (a applyCannedResponseInteractor) record(ctx context.Context, action string, input ApplyCannedResponseInput, outcome string) {
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    action,
+		Actor:     input.Rep,
+		Target:    input.ChannelId,
+		IP:        input.IP,
+		Outcome:   outcome,
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockMacroChannelRepo struct {
+	domain.ChannelRepository
+
+	channel       domain.Channel
+	statusUpdates *[]domain.Channel
+	tagUpdates    *[]domain.Channel
+}
+
+func (m mockMacroChannelRepo) GetChannelById(_ context.Context, _ string) (domain.Channel, error) {
+	return m.channel, nil
+}
+
+func (m mockMacroChannelRepo) UpdateChannelStatus(_ context.Context, channel domain.Channel) error {
+	*m.statusUpdates = append(*m.statusUpdates, channel)
+	return nil
+}
+
+func (m mockMacroChannelRepo) UpdateChannelDetails(_ context.Context, channel domain.Channel) error {
+	*m.tagUpdates = append(*m.tagUpdates, channel)
+	return nil
+}
+
+type mockTemplateUserRepo struct {
+	domain.UserRepository
+
+	users map[string]domain.User
+}
+
+func (m mockTemplateUserRepo) GetUserByEmail(_ context.Context, email string) (domain.User, error) {
+	if user, ok := m.users[email]; ok {
+		return user, nil
+	}
+	return domain.User{}, domain.ErrUserNotFound
+}
+
+type mockApplyCannedResponsePresenter struct{}
+
+func (m mockApplyCannedResponsePresenter) Output(channel domain.Channel, text string) ApplyCannedResponseOutput {
+	return ApplyCannedResponseOutput{Text: text, CurrentStatus: channel.CurrentStatus(), RepEmail: channel.RepEmail(), Tags: channel.Tags()}
+}
+
+func TestApplyCannedResponseInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		users = mockTemplateUserRepo{users: map[string]domain.User{
+			"user@email.com": domain.NewUser(primitive.NewObjectID(), "Ada", "Lovelace", "user@email.com", "hash", time.Now(), time.Now()),
+			"rep@email.com":  domain.NewUser(primitive.NewObjectID(), "Grace", "Hopper", "rep@email.com", "hash", time.Now(), time.Now()),
+		}}
+		body = "Hi {{customer.firstName}}, {{ rep.fullName }} here. Reach {{customer.email}}?"
+	)
+
+	newChannel := func(guest bool) domain.Channel {
+		channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, time.Now(), time.Now())
+		channel.UpdateStatus(domain.ACTIVE, "user@email.com", time.Now().Unix())
+		channel.AddTags("billing")
+		if guest {
+			channel.MarkGuest()
+			channel.UpdateUserFullName("Guest Visitor")
+		}
+		return channel
+	}
+
+	tests := []struct {
+		name           string
+		channel        domain.Channel
+		response       domain.CannedResponse
+		expected       ApplyCannedResponseOutput
+		expectedError  error
+		expectedAudit  []string
+		expectedWrites int
+	}{
+		{
+			name:     "render only",
+			channel:  newChannel(false),
+			response: domain.NewCannedResponse(primitive.NewObjectID(), "hi", "Hi", body, nil, "rep@email.com", false, domain.Macro{}, time.Now(), time.Now()),
+			expected: ApplyCannedResponseOutput{
+				Text:          "Hi Ada, Grace Hopper here. Reach user@email.com?",
+				CurrentStatus: domain.ACTIVE,
+				Tags:          []string{"billing"},
+			},
+		},
+		{
+			name:    "guest customer",
+			channel: newChannel(true),
+			response: domain.NewCannedResponse(primitive.NewObjectID(), "hi", "Hi", "Hi {{customer.firstName}} {{customer.lastName}}", nil, "rep@email.com", false,
+				domain.Macro{}, time.Now(), time.Now()),
+			expected: ApplyCannedResponseOutput{Text: "Hi Guest Visitor", CurrentStatus: domain.ACTIVE, Tags: []string{"billing"}},
+		},
+		{
+			name:    "macro claims and retags",
+			channel: newChannel(false),
+			response: domain.NewCannedResponse(primitive.NewObjectID(), "refund", "Refund", "On it", nil, "other@email.com", true,
+				domain.Macro{Status: domain.IN_PROGRESS, AddTags: []string{"Refund"}, RemoveTags: []string{"billing"}}, time.Now(), time.Now()),
+			expected: ApplyCannedResponseOutput{
+				Text:          "On it",
+				CurrentStatus: domain.IN_PROGRESS,
+				RepEmail:      "rep@email.com",
+				Tags:          []string{"refund"},
+			},
+			expectedAudit:  []string{domain.AuditChannelClaimed},
+			expectedWrites: 2,
+		},
+		{
+			name:          "personal response of another rep",
+			channel:       newChannel(false),
+			response:      domain.NewCannedResponse(primitive.NewObjectID(), "hi", "Hi", body, nil, "other@email.com", false, domain.Macro{}, time.Now(), time.Now()),
+			expectedError: domain.ErrCannedResponseNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		var (
+			responses     = []domain.CannedResponse{tt.response}
+			statusUpdates []domain.Channel
+			tagUpdates    []domain.Channel
+			events        []domain.AuditEvent
+			notified      []domain.ChannelEvent
+			indexed       []domain.Channel
+			uc            = NewApplyCannedResponseInteractor(
+				mockMacroChannelRepo{channel: tt.channel, statusUpdates: &statusUpdates, tagUpdates: &tagUpdates},
+				mockCannedResponseRepo{responses: &responses},
+				users,
+				mockSearchIndex{indexed: &indexed},
+				mockChannelNotifier{events: &notified},
+				mockAuditLogger{events: &events},
+				mockApplyCannedResponsePresenter{},
+				time.Second,
+			)
+		)
+
+		got, err := uc.Execute(context.Background(), ApplyCannedResponseInput{
+			ChannelId:  tt.channel.Id().Hex(),
+			ResponseId: tt.response.Id().Hex(),
+			Rep:        "rep@email.com",
+		})
+		if err != tt.expectedError {
+			t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+		}
+
+		var audit []string
+		for _, event := range events {
+			audit = append(audit, event.Action)
+		}
+		if !reflect.DeepEqual(audit, tt.expectedAudit) {
+			t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, audit, tt.expectedAudit)
+		}
+		if writes := len(statusUpdates) + len(tagUpdates); writes != tt.expectedWrites || len(indexed) != tt.expectedWrites/2 {
+			t.Errorf("[TestCase '%s'] Writes: '%v', '%v' | Expected: '%v'", tt.name, writes, len(indexed), tt.expectedWrites)
+		}
+	}
+}
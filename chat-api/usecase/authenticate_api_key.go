@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	AuthenticateAPIKeyUseCase interface {
+		Execute(context.Context, AuthenticateAPIKeyInput) (AuthenticateAPIKeyOutput, error)
+	}
+
+	// Input data
+	AuthenticateAPIKeyInput struct {
+		Key   string
+		Scope string
+	}
+
+	// Output port
+	AuthenticateAPIKeyPresenter interface {
+		Output(domain.APIKey, string) AuthenticateAPIKeyOutput
+	}
+
+	// Output data
+	AuthenticateAPIKeyOutput struct {
+		Id       string
+		Name     string
+		Scope    string
+		TenantId string
+	}
+
+	authenticateAPIKeyInteractor struct {
+		repo       domain.APIKeyRepository
+		service    domain.APIKeyService
+		presenter  AuthenticateAPIKeyPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewAuthenticateAPIKeyInteractor(
+	repo domain.APIKeyRepository,
+	service domain.APIKeyService,
+	presenter AuthenticateAPIKeyPresenter,
+	t time.Duration,
+) AuthenticateAPIKeyUseCase {
+	return authenticateAPIKeyInteractor{
+		repo:       repo,
+		service:    service,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a authenticateAPIKeyInteractor) Execute(ctx context.Context, input AuthenticateAPIKeyInput) (AuthenticateAPIKeyOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	prefix, err := a.service.ParseAPIKey(ctx, input.Key)
+	if err != nil {
+		return a.presenter.Output(domain.APIKey{}, ""), domain.ErrInvalidAPIKey
+	}
+
+	key, err := a.repo.GetAPIKeyByPrefix(ctx, prefix)
+	switch err {
+	case nil:
+	case domain.ErrAPIKeyNotFound:
+		return a.presenter.Output(domain.APIKey{}, ""), domain.ErrInvalidAPIKey
+	default:
+		return a.presenter.Output(domain.APIKey{}, ""), err
+	}
+
+	if key.IsRevoked() || !a.service.VerifyAPIKey(ctx, input.Key, key.HashedKey()) {
+		return a.presenter.Output(domain.APIKey{}, ""), domain.ErrInvalidAPIKey
+	}
+
+	if !key.HasScope(input.Scope) {
+		return a.presenter.Output(domain.APIKey{}, ""), domain.ErrAPIKeyScope
+	}
+
+	return a.presenter.Output(key, input.Scope), nil
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockAPIKeyRepo struct {
+	domain.APIKeyRepository
+
+	key     domain.APIKey
+	err     error
+	created *domain.APIKey
+	revoked *domain.APIKey
+}
+
+func (m mockAPIKeyRepo) CreateAPIKey(_ context.Context, key domain.APIKey) (domain.APIKey, error) {
+	*m.created = key
+	return key, m.err
+}
+
+func (m mockAPIKeyRepo) GetAPIKeyById(_ context.Context, _ string) (domain.APIKey, error) {
+	return m.key, m.err
+}
+
+func (m mockAPIKeyRepo) GetAPIKeyByPrefix(_ context.Context, _ string) (domain.APIKey, error) {
+	return m.key, m.err
+}
+
+func (m mockAPIKeyRepo) RevokeAPIKey(_ context.Context, key domain.APIKey) error {
+	*m.revoked = key
+	return nil
+}
+
+// mockAPIKeyService treats keys as "prefix.secret" and stores the secret as its own hash
+type mockAPIKeyService struct{}
+
+func (m mockAPIKeyService) GenerateAPIKey(_ context.Context) (string, string, string, error) {
+	return "prefix.secret", "prefix", "secret", nil
+}
+
+func (m mockAPIKeyService) ParseAPIKey(_ context.Context, key string) (string, error) {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 {
+		return "", errors.New("malformed key")
+	}
+	return parts[0], nil
+}
+
+func (m mockAPIKeyService) VerifyAPIKey(_ context.Context, key, hashedKey string) bool {
+	return strings.HasSuffix(key, "."+hashedKey)
+}
+
+type mockAuthenticateAPIKeyPresenter struct{}
+
+func (m mockAuthenticateAPIKeyPresenter) Output(key domain.APIKey, scope string) AuthenticateAPIKeyOutput {
+	return AuthenticateAPIKeyOutput{Name: key.Name(), Scope: scope}
+}
+
+func TestAuthenticateAPIKeyInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	key := domain.NewAPIKey(primitive.NewObjectID(), "socket", "prefix", "secret", []string{domain.ScopeMessagesWrite}, "admin@email.com", time.Now())
+	revoked := key
+	revoked.Revoke(time.Now())
+
+	tests := []struct {
+		name          string
+		key           domain.APIKey
+		err           error
+		input         AuthenticateAPIKeyInput
+		expected      AuthenticateAPIKeyOutput
+		expectedError error
+	}{
+		{
+			name:     "valid key with scope",
+			key:      key,
+			input:    AuthenticateAPIKeyInput{Key: "prefix.secret", Scope: domain.ScopeMessagesWrite},
+			expected: AuthenticateAPIKeyOutput{Name: "socket", Scope: domain.ScopeMessagesWrite},
+		},
+		{
+			name:          "valid key without scope",
+			key:           key,
+			input:         AuthenticateAPIKeyInput{Key: "prefix.secret", Scope: "channels:read"},
+			expectedError: domain.ErrAPIKeyScope,
+		},
+		{
+			name:          "wrong secret",
+			key:           key,
+			input:         AuthenticateAPIKeyInput{Key: "prefix.guess", Scope: domain.ScopeMessagesWrite},
+			expectedError: domain.ErrInvalidAPIKey,
+		},
+		{
+			name:          "revoked key",
+			key:           revoked,
+			input:         AuthenticateAPIKeyInput{Key: "prefix.secret", Scope: domain.ScopeMessagesWrite},
+			expectedError: domain.ErrInvalidAPIKey,
+		},
+		{
+			name:          "unknown prefix",
+			err:           domain.ErrAPIKeyNotFound,
+			input:         AuthenticateAPIKeyInput{Key: "other.secret", Scope: domain.ScopeMessagesWrite},
+			expectedError: domain.ErrInvalidAPIKey,
+		},
+		{
+			name:          "malformed key",
+			key:           key,
+			input:         AuthenticateAPIKeyInput{Key: "garbage", Scope: domain.ScopeMessagesWrite},
+			expectedError: domain.ErrInvalidAPIKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewAuthenticateAPIKeyInteractor(
+				mockAPIKeyRepo{key: tt.key, err: tt.err},
+				mockAPIKeyService{},
+				mockAuthenticateAPIKeyPresenter{},
+				time.Second,
+			)
+
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
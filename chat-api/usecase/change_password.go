@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	ChangePasswordUseCase interface {
+		Execute(context.Context, ChangePasswordInput) (ChangePasswordOutput, error)
+	}
+
+	// Input data
+	ChangePasswordInput struct {
+		Email           string `json:"-" validate:"required"`
+		CurrentPassword string `json:"currentPassword" validate:"required"`
+		NewPassword     string `json:"newPassword" validate:"required,min=8"`
+		IP              string `json:"-"`
+	}
+
+	// Output port
+	ChangePasswordPresenter interface {
+		Output(domain.User) ChangePasswordOutput
+	}
+
+	// Output data
+	ChangePasswordOutput struct {
+		Email     string    `json:"email"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	changePasswordInteractor struct {
+		repo       domain.UserRepository
+		service    domain.AuthenticationUtilityService
+		audit      domain.AuditLogger
+		presenter  ChangePasswordPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewChangePasswordInteractor(
+	repo domain.UserRepository,
+	service domain.AuthenticationUtilityService,
+	audit domain.AuditLogger,
+	presenter ChangePasswordPresenter,
+	t time.Duration,
+) ChangePasswordUseCase {
+	return changePasswordInteractor{
+		repo:       repo,
+		service:    service,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a changePasswordInteractor) Execute(ctx context.Context, input ChangePasswordInput) (ChangePasswordOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	user, err := a.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	if !a.service.CheckPasswordHash(ctx, input.CurrentPassword, user.Password()) {
+		a.audit.Record(ctx, domain.AuditEvent{
+			Action:    domain.AuditPasswordChanged,
+			Actor:     user.Email(),
+			Target:    user.Email(),
+			IP:        input.IP,
+			Outcome:   domain.AuditOutcomeFailure,
+			Timestamp: time.Now(),
+		})
+		return a.presenter.Output(domain.User{}), domain.ErrCurrentPasswordIncorrect
+	}
+
+	hashedPassword, err := a.service.HashPassword(ctx, input.NewPassword)
+	if err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	user.UpdatePassword(hashedPassword, time.Now())
+	if err := a.repo.UpdatePassword(ctx, user); err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditPasswordChanged,
+		Actor:     user.Email(),
+		Target:    user.Email(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return a.presenter.Output(user), nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockChangePasswordPresenter struct{}
+
+func (m mockChangePasswordPresenter) Output(user domain.User) ChangePasswordOutput {
+	return ChangePasswordOutput{Email: user.Email()}
+}
+
+func TestChangePasswordInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	user := domain.NewUser(newUserId, "firstName", "lastName", "user@email.com", "hash", time.Now(), time.Now())
+
+	tests := []struct {
+		name             string
+		input            ChangePasswordInput
+		passwordCorrect  bool
+		expected         ChangePasswordOutput
+		expectedError    error
+		expectedPassword string
+		expectedOutcome  string
+	}{
+		{
+			name:             "password changed",
+			input:            ChangePasswordInput{Email: "user@email.com", CurrentPassword: "password", NewPassword: "new-password"},
+			passwordCorrect:  true,
+			expected:         ChangePasswordOutput{Email: "user@email.com"},
+			expectedPassword: "rehashed",
+			expectedOutcome:  domain.AuditOutcomeSuccess,
+		},
+		{
+			name:            "wrong current password refused",
+			input:           ChangePasswordInput{Email: "user@email.com", CurrentPassword: "wrong", NewPassword: "new-password"},
+			expectedError:   domain.ErrCurrentPasswordIncorrect,
+			expectedOutcome: domain.AuditOutcomeFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				events  []domain.AuditEvent
+				updated domain.User
+				uc      = NewChangePasswordInteractor(
+					mockLoginUserRepo{user: user, updated: &updated},
+					mockLoginAuthenticationService{passwordCorrect: tt.passwordCorrect},
+					mockAuditLogger{events: &events},
+					mockChangePasswordPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+
+			if updated.Password() != tt.expectedPassword {
+				t.Errorf("[TestCase '%s'] Stored password: '%v' | Expected: '%v'", tt.name, updated.Password(), tt.expectedPassword)
+			}
+
+			if len(events) != 1 || events[0].Action != domain.AuditPasswordChanged || events[0].Outcome != tt.expectedOutcome {
+				t.Errorf("[TestCase '%s'] Audit: '%v' | Expected outcome: '%v'", tt.name, events, tt.expectedOutcome)
+			}
+		})
+	}
+}
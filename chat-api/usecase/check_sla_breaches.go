@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	CheckSLABreachesUseCase interface {
+		Execute(context.Context) (CheckSLABreachesOutput, error)
+	}
+
+	// Output port
+	CheckSLABreachesPresenter interface {
+		Output([]domain.Channel) CheckSLABreachesOutput
+	}
+
+	SLATargetOutput struct {
+		TargetSeconds int64      `json:"targetSeconds"`
+		DueAt         time.Time  `json:"dueAt"`
+		StoppedAt     *time.Time `json:"stoppedAt,omitempty"`
+		Status        string     `json:"status"`
+	}
+
+	// SLAOutput flags the channel with the worst status of its targets, a target
+	// the policy leaves out is missing
+	SLAOutput struct {
+		Status        string           `json:"status"`
+		FirstResponse *SLATargetOutput `json:"firstResponse,omitempty"`
+		Resolution    *SLATargetOutput `json:"resolution,omitempty"`
+	}
+
+	SLABreachOutput struct {
+		ChannelId string `json:"channelId"`
+		Target    string `json:"target"`
+	}
+
+	// Output data, the breaches announced by this check
+	CheckSLABreachesOutput struct {
+		Breaches []SLABreachOutput `json:"breaches"`
+	}
+
+	checkSLABreachesInteractor struct {
+		repo       domain.ChannelRepository
+		orgRepo    domain.OrganizationRepository
+		notifier   domain.ChannelNotifier
+		presenter  CheckSLABreachesPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewCheckSLABreachesInteractor(
+	repo domain.ChannelRepository,
+	orgRepo domain.OrganizationRepository,
+	notifier domain.ChannelNotifier,
+	presenter CheckSLABreachesPresenter,
+	t time.Duration,
+) CheckSLABreachesUseCase {
+	return checkSLABreachesInteractor{
+		repo:       repo,
+		orgRepo:    orgRepo,
+		notifier:   notifier,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute measures the open channels of the tenant against their SLA policy and
+// tells the reps about every target breached since the last check. A breach is
+// recorded before it is announced, so a failed check does not announce it twice
+func (a checkSLABreachesInteractor) Execute(ctx context.Context) (CheckSLABreachesOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	channels, err := a.repo.GetChannelsByQuery(ctx, domain.ChannelQuery{
+		Statuses: domain.OpenStatuses(),
+		SortBy:   domain.ChannelSortCreatedAt,
+	})
+	if err != nil {
+		return a.presenter.Output([]domain.Channel{}), err
+	}
+
+	now := time.Now()
+	if err := trackSLA(ctx, a.orgRepo, channels, now); err != nil {
+		return a.presenter.Output([]domain.Channel{}), err
+	}
+
+	var breached = make([]domain.Channel, 0)
+	for _, channel := range channels {
+		breaches := channel.UnannouncedBreaches()
+		if len(breaches) == 0 {
+			continue
+		}
+
+		if err := a.repo.AddSLABreaches(ctx, channel, breaches); err != nil {
+			return a.presenter.Output([]domain.Channel{}), err
+		}
+
+		for _, target := range breaches {
+			a.notifier.Notify(ctx, domain.ChannelEvent{
+				Type:        domain.ChannelEventBreach,
+				ChannelId:   channel.Id().Hex(),
+				UserEmail:   channel.UserEmail(),
+				MessageFrom: channel.RepEmail(),
+				Message:     target,
+				Internal:    true,
+				Timestamp:   now,
+			})
+		}
+		// The presenter lists the breaches of this check, not the ones announced before
+		breached = append(breached, channel)
+	}
+
+	return a.presenter.Output(breached), nil
+}
+
+// trackSLA measures the channels against the SLA policies of the organization
+func trackSLA(ctx context.Context, repo domain.OrganizationRepository, channels []domain.Channel, now time.Time) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	organization, err := currentOrganization(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	settings := organization.Settings()
+	for i := range channels {
+		channels[i].TrackSLA(settings.SLAPolicy(channels[i].Priority()), now)
+	}
+	return nil
+}
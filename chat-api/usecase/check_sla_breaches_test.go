@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockSLAChannelRepo struct {
+	domain.ChannelRepository
+
+	channels []domain.Channel
+	query    *domain.ChannelQuery
+	added    *[]string
+}
+
+func (m mockSLAChannelRepo) GetChannelsByQuery(_ context.Context, query domain.ChannelQuery) ([]domain.Channel, error) {
+	*m.query = query
+	return append([]domain.Channel{}, m.channels...), nil
+}
+
+func (m mockSLAChannelRepo) AddSLABreaches(_ context.Context, _ domain.Channel, breaches []string) error {
+	*m.added = append(*m.added, breaches...)
+	return nil
+}
+
+func TestCheckSLABreachesInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	// newChannel was opened by a customer the given time ago
+	newChannel := func(priority string, age time.Duration) domain.Channel {
+		channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, time.Now().Add(-age), time.Now())
+		channel.UpdateRepEmail("rep@email.com")
+		_ = channel.UpdatePriority(priority)
+		channel.AddMessage("user@email.com", "my order is late", time.Now().Add(-age))
+		return channel
+	}
+
+	var (
+		waiting  = newChannel(domain.PriorityUrgent, 20*time.Minute)
+		answered = newChannel(domain.PriorityUrgent, 20*time.Minute)
+		noted    = newChannel(domain.PriorityUrgent, 20*time.Minute)
+		late     = newChannel(domain.PriorityUrgent, 5*time.Hour)
+		known    = newChannel(domain.PriorityUrgent, 20*time.Minute)
+		recent   = newChannel(domain.PriorityNormal, 20*time.Minute)
+	)
+	answered.AddMessage("rep@email.com", "looking into it", answered.CreatedAt().Add(10*time.Minute))
+	noted.AddNote("rep@email.com", "customer verified", noted.CreatedAt().Add(5*time.Minute))
+	late.AddMessage("rep@email.com", "looking into it", late.CreatedAt().Add(5*time.Minute))
+	known.RestoreSLA(time.Time{}, time.Time{}, []string{domain.SLAFirstResponse})
+
+	tests := []struct {
+		name     string
+		channels []domain.Channel
+		settings domain.OrganizationSettings
+		expected []SLABreachOutput
+	}{
+		{
+			name:     "customer waiting past the first response target",
+			channels: []domain.Channel{waiting},
+			expected: []SLABreachOutput{{ChannelId: waiting.Id().Hex(), Target: domain.SLAFirstResponse}},
+		},
+		{
+			name:     "rep answered in time",
+			channels: []domain.Channel{answered},
+			expected: []SLABreachOutput{},
+		},
+		{
+			name:     "internal notes do not answer the customer",
+			channels: []domain.Channel{noted},
+			expected: []SLABreachOutput{{ChannelId: noted.Id().Hex(), Target: domain.SLAFirstResponse}},
+		},
+		{
+			name:     "answered in time but not resolved",
+			channels: []domain.Channel{late},
+			expected: []SLABreachOutput{{ChannelId: late.Id().Hex(), Target: domain.SLAResolution}},
+		},
+		{
+			name:     "breach announced by a previous check",
+			channels: []domain.Channel{known},
+			expected: []SLABreachOutput{},
+		},
+		{
+			name:     "default policy of a lower priority",
+			channels: []domain.Channel{recent},
+			expected: []SLABreachOutput{},
+		},
+		{
+			name:     "policy configured by the organization",
+			channels: []domain.Channel{recent},
+			settings: domain.OrganizationSettings{SLAPolicies: []domain.SLAPolicy{
+				{Priority: domain.PriorityNormal, FirstResponse: 10 * time.Minute},
+			}},
+			expected: []SLABreachOutput{{ChannelId: recent.Id().Hex(), Target: domain.SLAFirstResponse}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			organization := domain.NewOrganization("acme", "Acme", []string{"support.acme.com"}, time.Now(), time.Now())
+			organization.UpdateSettings(tt.settings, time.Now())
+
+			var (
+				query  domain.ChannelQuery
+				added  []string
+				events []domain.ChannelEvent
+				uc     = NewCheckSLABreachesInteractor(
+					mockSLAChannelRepo{channels: tt.channels, query: &query, added: &added},
+					mockOrganizationRepo{organization: organization},
+					mockChannelNotifier{events: &events},
+					mockCheckSLABreachesPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(domain.WithTenant(context.Background(), "acme"))
+			if err != nil {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, nil)
+			}
+			if !reflect.DeepEqual(got.Breaches, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got.Breaches, tt.expected)
+			}
+			if expected := []string{domain.INACTIVE, domain.ACTIVE, domain.IN_PROGRESS}; !reflect.DeepEqual(query.Statuses, expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, query.Statuses, expected)
+			}
+
+			if len(events) != len(tt.expected) {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, len(events), len(tt.expected))
+			}
+			for i, event := range events {
+				if event.Type != domain.ChannelEventBreach || !event.Internal || event.Message != tt.expected[i].Target {
+					t.Errorf("[TestCase '%s'] Result: '%+v' | Expected: '%v'", tt.name, event, tt.expected[i].Target)
+				}
+			}
+
+			// Breaches are stored before they are announced, so the next check skips them
+			if len(added) != len(tt.expected) {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, added, tt.expected)
+			}
+			for i, target := range added {
+				if target != tt.expected[i].Target {
+					t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, target, tt.expected[i].Target)
+				}
+			}
+		})
+	}
+}
+
+type mockCheckSLABreachesPresenter struct{}
+
+func (m mockCheckSLABreachesPresenter) Output(channels []domain.Channel) CheckSLABreachesOutput {
+	breaches := make([]SLABreachOutput, 0)
+	for _, channel := range channels {
+		for _, target := range channel.UnannouncedBreaches() {
+			breaches = append(breaches, SLABreachOutput{ChannelId: channel.Id().Hex(), Target: target})
+		}
+	}
+	return CheckSLABreachesOutput{Breaches: breaches}
+}
+
+func TestChannel_TrackSLA(t *testing.T) {
+	t.Parallel()
+
+	var (
+		createdAt = time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)
+		policy    = domain.SLAPolicy{Priority: domain.PriorityHigh, FirstResponse: time.Hour, Resolution: 8 * time.Hour}
+	)
+	newChannel := func() domain.Channel {
+		channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, createdAt, createdAt)
+		channel.AddMessage("user@email.com", "my order is late", createdAt)
+		return channel
+	}
+
+	answered := newChannel()
+	answered.AddMessage("rep@email.com", "looking into it", createdAt.Add(30*time.Minute))
+	answered.AddMessage("rep@email.com", "found it", createdAt.Add(40*time.Minute))
+
+	resolved := newChannel()
+	resolved.UpdateStatus(domain.COMPLETE, "rep@email.com", createdAt.Add(9*time.Hour).Unix())
+
+	reopened := newChannel()
+	reopened.AddMessage("rep@email.com", "looking into it", createdAt.Add(2*time.Hour))
+	reopened.UpdateStatus(domain.COMPLETE, "rep@email.com", createdAt.Add(3*time.Hour).Unix())
+	reopened.UpdateStatus(domain.IN_PROGRESS, "user@email.com", createdAt.Add(4*time.Hour).Unix())
+
+	tests := []struct {
+		name                  string
+		channel               domain.Channel
+		now                   time.Time
+		expectedFirstResponse string
+		expectedResolution    string
+		expectedStatus        string
+	}{
+		{
+			name:                  "clocks running",
+			channel:               newChannel(),
+			now:                   createdAt.Add(10 * time.Minute),
+			expectedFirstResponse: domain.SLAOnTrack,
+			expectedResolution:    domain.SLAOnTrack,
+			expectedStatus:        domain.SLAOnTrack,
+		},
+		{
+			name:                  "approaching the first response target",
+			channel:               newChannel(),
+			now:                   createdAt.Add(50 * time.Minute),
+			expectedFirstResponse: domain.SLAApproaching,
+			expectedResolution:    domain.SLAOnTrack,
+			expectedStatus:        domain.SLAApproaching,
+		},
+		{
+			name:                  "first answer in time",
+			channel:               answered,
+			now:                   createdAt.Add(2 * time.Hour),
+			expectedFirstResponse: domain.SLAMet,
+			expectedResolution:    domain.SLAOnTrack,
+			expectedStatus:        domain.SLAOnTrack,
+		},
+		{
+			name:                  "resolved late without an answer",
+			channel:               resolved,
+			now:                   createdAt.Add(10 * time.Hour),
+			expectedFirstResponse: domain.SLABreached,
+			expectedResolution:    domain.SLABreached,
+			expectedStatus:        domain.SLABreached,
+		},
+		{
+			name:                  "reopened channels run the resolution clock again",
+			channel:               reopened,
+			now:                   createdAt.Add(7 * time.Hour),
+			expectedFirstResponse: domain.SLABreached,
+			expectedResolution:    domain.SLAApproaching,
+			expectedStatus:        domain.SLABreached,
+		},
+	}
+
+	for _, tt := range tests {
+		tt.channel.TrackSLA(policy, tt.now)
+		sla := tt.channel.SLA()
+
+		if sla.FirstResponse.Status != tt.expectedFirstResponse {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, sla.FirstResponse.Status, tt.expectedFirstResponse)
+		}
+		if sla.Resolution.Status != tt.expectedResolution {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, sla.Resolution.Status, tt.expectedResolution)
+		}
+		if sla.Status() != tt.expectedStatus {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, sla.Status(), tt.expectedStatus)
+		}
+	}
+
+	if got, _ := answered.FirstResponseTime(); got != 30*time.Minute {
+		t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", "first response time", got, 30*time.Minute)
+	}
+	if _, ok := reopened.ResolutionTime(); ok {
+		t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", "reopened resolution time", ok, false)
+	}
+}
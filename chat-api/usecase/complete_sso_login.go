@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	// Input port
+	CompleteSSOLoginUseCase interface {
+		Execute(context.Context, CompleteSSOLoginInput) (LoginUserOutput, error)
+	}
+
+	// Input data
+	CompleteSSOLoginInput struct {
+		Code  string `json:"code" validate:"required"`
+		State string `json:"state" validate:"required"`
+		IP    string `json:"-"`
+	}
+
+	completeSSOLoginInteractor struct {
+		repo       domain.UserRepository
+		stateRepo  domain.SSOStateRepository
+		provider   domain.IdentityProvider
+		service    domain.AuthenticationUtilityService
+		audit      domain.AuditLogger
+		presenter  LoginUserPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewCompleteSSOLoginInteractor(
+	repo domain.UserRepository,
+	stateRepo domain.SSOStateRepository,
+	provider domain.IdentityProvider,
+	service domain.AuthenticationUtilityService,
+	audit domain.AuditLogger,
+	presenter LoginUserPresenter,
+	t time.Duration,
+) CompleteSSOLoginUseCase {
+	return completeSSOLoginInteractor{
+		repo:       repo,
+		stateRepo:  stateRepo,
+		provider:   provider,
+		service:    service,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (c completeSSOLoginInteractor) Execute(ctx context.Context, input CompleteSSOLoginInput) (LoginUserOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.ctxTimeout)
+	defer cancel()
+
+	state, err := c.stateRepo.ConsumeSSOState(ctx, input.State)
+	if err != nil {
+		return c.presenter.Output(domain.User{}, ""), err
+	}
+	if state.IsExpired(time.Now()) {
+		return c.presenter.Output(domain.User{}, ""), domain.ErrInvalidSSOState
+	}
+
+	identity, err := c.provider.Exchange(ctx, input.Code, state)
+	if err != nil {
+		return c.presenter.Output(domain.User{}, ""), err
+	}
+
+	if !identity.EmailVerified || identity.Email == "" {
+		c.record(ctx, domain.AuditSSOLogin, identity.Email, input.IP, domain.AuditOutcomeFailure)
+		return c.presenter.Output(domain.User{}, ""), domain.ErrSSOEmailUnverified
+	}
+
+	// Single sign-on is only offered to reps, anyone else keeps using a local password
+	if identity.Role != domain.ADMIN {
+		c.record(ctx, domain.AuditSSOLogin, identity.Email, input.IP, domain.AuditOutcomeFailure)
+		return c.presenter.Output(domain.User{}, ""), domain.ErrSSORoleNotAllowed
+	}
+
+	user, err := c.provision(ctx, identity, input.IP)
+	if err != nil {
+		return c.presenter.Output(domain.User{}, ""), err
+	}
+
+	if !user.IsActive() {
+		c.record(ctx, domain.AuditSSOLogin, user.Email(), input.IP, domain.AuditOutcomeFailure)
+		return c.presenter.Output(domain.User{}, ""), domain.ErrUserDeactivated
+	}
+
+	token, err := c.service.GenerateToken(ctx, user)
+	if err != nil {
+		return c.presenter.Output(domain.User{}, ""), err
+	}
+
+	c.record(ctx, domain.AuditSSOLogin, user.Email(), input.IP, domain.AuditOutcomeSuccess)
+
+	return c.presenter.Output(user, token), nil
+}
+
+// provision creates the user on first sign in and keeps the role in sync with the provider afterwards
+func (c completeSSOLoginInteractor) provision(ctx context.Context, identity domain.Identity, ip string) (domain.User, error) {
+	user, err := c.repo.GetUserByEmail(ctx, identity.Email)
+	switch err {
+	case nil:
+		if user.Role() != identity.Role {
+			user.UpdateRole(identity.Role)
+			if err := c.repo.UpdateRole(ctx, user); err != nil {
+				return domain.User{}, err
+			}
+			c.record(ctx, domain.AuditRoleChanged, user.Email(), ip, domain.AuditOutcomeSuccess)
+		}
+		return user, nil
+	case domain.ErrUserNotFound:
+	default:
+		return domain.User{}, err
+	}
+
+	// No password is stored, the account can only sign in through the provider
+	user = domain.NewUser(
+		primitive.NewObjectID(),
+		identity.FirstName,
+		identity.LastName,
+		identity.Email,
+		"",
+		time.Now(),
+		time.Now(),
+	)
+	user.UpdateRole(identity.Role)
+
+	user, err = c.repo.CreateUser(ctx, user)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	c.record(ctx, domain.AuditUserProvisioned, user.Email(), ip, domain.AuditOutcomeSuccess)
+	return user, nil
+}
+
+func (c completeSSOLoginInteractor) record(ctx context.Context, action, email, ip, outcome string) {
+	c.audit.Record(ctx, domain.AuditEvent{
+		Action:    action,
+		Actor:     email,
+		Target:    email,
+		IP:        ip,
+		Outcome:   outcome,
+		Timestamp: time.Now(),
+	})
+}
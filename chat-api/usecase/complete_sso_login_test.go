@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockSSOStateRepo struct {
+	domain.SSOStateRepository
+
+	state domain.SSOState
+	err   error
+}
+
+func (m mockSSOStateRepo) ConsumeSSOState(_ context.Context, _ string) (domain.SSOState, error) {
+	return m.state, m.err
+}
+
+type mockIdentityProvider struct {
+	domain.IdentityProvider
+
+	identity domain.Identity
+	err      error
+}
+
+func (m mockIdentityProvider) Exchange(_ context.Context, _ string, _ domain.SSOState) (domain.Identity, error) {
+	return m.identity, m.err
+}
+
+type mockSSOUserRepo struct {
+	domain.UserRepository
+
+	user    domain.User
+	err     error
+	created *domain.User
+	updated *domain.User
+}
+
+func (m mockSSOUserRepo) GetUserByEmail(_ context.Context, _ string) (domain.User, error) {
+	return m.user, m.err
+}
+
+func (m mockSSOUserRepo) CreateUser(_ context.Context, user domain.User) (domain.User, error) {
+	*m.created = user
+	return user, nil
+}
+
+func (m mockSSOUserRepo) UpdateRole(_ context.Context, user domain.User) error {
+	*m.updated = user
+	return nil
+}
+
+type mockSSOPresenter struct{}
+
+func (m mockSSOPresenter) Output(user domain.User, token string) LoginUserOutput {
+	return LoginUserOutput{Email: user.Email(), Role: user.Role(), Token: token}
+}
+
+func TestCompleteSSOLoginInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	validState := domain.SSOState{State: "state", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: time.Now().Add(time.Minute)}
+	admin := domain.Identity{Subject: "42", Email: "Rep@Corp.com", EmailVerified: true, FirstName: "Ada", LastName: "Rep", Role: domain.ADMIN}
+	existing := domain.NewUser(newUserId, "Ada", "Rep", "rep@corp.com", "hash", time.Now(), time.Now())
+	existing.UpdateRole(domain.USER)
+	deactivated := domain.NewUser(newUserId, "Ada", "Rep", "rep@corp.com", "hash", time.Now(), time.Now())
+	deactivated.UpdateRole(domain.ADMIN)
+	deactivated.Deactivate(time.Now())
+
+	tests := []struct {
+		name            string
+		state           domain.SSOState
+		stateErr        error
+		identity        domain.Identity
+		exchangeErr     error
+		user            domain.User
+		userErr         error
+		expected        LoginUserOutput
+		expectedError   error
+		expectedCreated string
+		expectedUpdated string
+		expectedAudit   []string
+	}{
+		{
+			name:            "first sign in provisions the user",
+			state:           validState,
+			identity:        admin,
+			userErr:         domain.ErrUserNotFound,
+			expected:        LoginUserOutput{Email: "rep@corp.com", Role: domain.ADMIN, Token: "token"},
+			expectedCreated: "rep@corp.com",
+			expectedAudit:   []string{domain.AuditUserProvisioned, domain.AuditSSOLogin},
+		},
+		{
+			name:            "existing user gets the mapped role",
+			state:           validState,
+			identity:        admin,
+			user:            existing,
+			expected:        LoginUserOutput{Email: "rep@corp.com", Role: domain.ADMIN, Token: "token"},
+			expectedUpdated: domain.ADMIN,
+			expectedAudit:   []string{domain.AuditRoleChanged, domain.AuditSSOLogin},
+		},
+		{
+			name:          "unknown state refused",
+			stateErr:      domain.ErrInvalidSSOState,
+			expectedError: domain.ErrInvalidSSOState,
+		},
+		{
+			name:          "expired state refused",
+			state:         domain.SSOState{State: "state", ExpiresAt: time.Now().Add(-time.Second)},
+			identity:      admin,
+			expectedError: domain.ErrInvalidSSOState,
+		},
+		{
+			name:          "failed exchange refused",
+			state:         validState,
+			exchangeErr:   domain.ErrSSOLoginFailed,
+			expectedError: domain.ErrSSOLoginFailed,
+		},
+		{
+			name:          "identity without admin mapping refused",
+			state:         validState,
+			identity:      domain.Identity{Email: "user@corp.com", EmailVerified: true, Role: domain.USER},
+			expectedError: domain.ErrSSORoleNotAllowed,
+			expectedAudit: []string{domain.AuditSSOLogin},
+		},
+		{
+			name:          "unverified email refused",
+			state:         validState,
+			identity:      domain.Identity{Email: "rep@corp.com", Role: domain.ADMIN},
+			expectedError: domain.ErrSSOEmailUnverified,
+			expectedAudit: []string{domain.AuditSSOLogin},
+		},
+		{
+			name:          "deactivated user refused",
+			state:         validState,
+			identity:      admin,
+			user:          deactivated,
+			expectedError: domain.ErrUserDeactivated,
+			expectedAudit: []string{domain.AuditSSOLogin},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				events  []domain.AuditEvent
+				created domain.User
+				updated domain.User
+				uc      = NewCompleteSSOLoginInteractor(
+					mockSSOUserRepo{user: tt.user, err: tt.userErr, created: &created, updated: &updated},
+					mockSSOStateRepo{state: tt.state, err: tt.stateErr},
+					mockIdentityProvider{identity: tt.identity, err: tt.exchangeErr},
+					mockLoginAuthenticationService{token: "token"},
+					mockAuditLogger{events: &events},
+					mockSSOPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), CompleteSSOLoginInput{Code: "code", State: "state"})
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+
+			if created.Email() != tt.expectedCreated {
+				t.Errorf("[TestCase '%s'] Created: '%v' | Expected: '%v'", tt.name, created.Email(), tt.expectedCreated)
+			}
+
+			if updated.Role() != tt.expectedUpdated {
+				t.Errorf("[TestCase '%s'] Updated role: '%v' | Expected: '%v'", tt.name, updated.Role(), tt.expectedUpdated)
+			}
+
+			var actions []string
+			for _, event := range events {
+				actions = append(actions, event.Action)
+			}
+			if !reflect.DeepEqual(actions, tt.expectedAudit) {
+				t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, actions, tt.expectedAudit)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	// Input port
+	CreateAPIKeyUseCase interface {
+		Execute(context.Context, CreateAPIKeyInput) (CreateAPIKeyOutput, error)
+	}
+
+	// Input data
+	CreateAPIKeyInput struct {
+		Name      string   `json:"name" validate:"required"`
+		Scopes    []string `json:"scopes" validate:"required,min=1,dive,oneof=messages:write"`
+		CreatedBy string   `json:"-"`
+		IP        string   `json:"-"`
+	}
+
+	// Output port
+	CreateAPIKeyPresenter interface {
+		Output(domain.APIKey, string) CreateAPIKeyOutput
+	}
+
+	// Output data, the key itself is only ever returned here
+	CreateAPIKeyOutput struct {
+		APIKeyOutput
+		Key string `json:"key"`
+	}
+
+	createAPIKeyInteractor struct {
+		repo       domain.APIKeyRepository
+		service    domain.APIKeyService
+		audit      domain.AuditLogger
+		presenter  CreateAPIKeyPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewCreateAPIKeyInteractor(
+	repo domain.APIKeyRepository,
+	service domain.APIKeyService,
+	audit domain.AuditLogger,
+	presenter CreateAPIKeyPresenter,
+	t time.Duration,
+) CreateAPIKeyUseCase {
+	return createAPIKeyInteractor{
+		repo:       repo,
+		service:    service,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (c createAPIKeyInteractor) Execute(ctx context.Context, input CreateAPIKeyInput) (CreateAPIKeyOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.ctxTimeout)
+	defer cancel()
+
+	plaintext, prefix, hashedKey, err := c.service.GenerateAPIKey(ctx)
+	if err != nil {
+		return c.presenter.Output(domain.APIKey{}, ""), err
+	}
+
+	key := domain.NewAPIKey(
+		primitive.NewObjectID(),
+		input.Name,
+		prefix,
+		hashedKey,
+		input.Scopes,
+		input.CreatedBy,
+		time.Now(),
+	)
+
+	createdKey, err := c.repo.CreateAPIKey(ctx, key)
+	if err != nil {
+		return c.presenter.Output(domain.APIKey{}, ""), err
+	}
+
+	c.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditAPIKeyCreated,
+		Actor:     input.CreatedBy,
+		Target:    createdKey.Id().Hex(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return c.presenter.Output(createdKey, plaintext), nil
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockCreateAPIKeyPresenter struct{}
+
+func (m mockCreateAPIKeyPresenter) Output(key domain.APIKey, plaintext string) CreateAPIKeyOutput {
+	return CreateAPIKeyOutput{
+		APIKeyOutput: APIKeyOutput{Name: key.Name(), Prefix: key.Prefix(), Scopes: key.Scopes(), CreatedBy: key.CreatedBy()},
+		Key:          plaintext,
+	}
+}
+
+func TestCreateAPIKeyInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		events  []domain.AuditEvent
+		created domain.APIKey
+		uc      = NewCreateAPIKeyInteractor(
+			mockAPIKeyRepo{created: &created},
+			mockAPIKeyService{},
+			mockAuditLogger{events: &events},
+			mockCreateAPIKeyPresenter{},
+			time.Second,
+		)
+	)
+
+	got, err := uc.Execute(context.Background(), CreateAPIKeyInput{
+		Name:      "socket",
+		Scopes:    []string{domain.ScopeMessagesWrite},
+		CreatedBy: "admin@email.com",
+	})
+	if err != nil {
+		t.Fatalf("[TestCase 'create api key'] Result: '%v' | ExpectedError: '%v'", err, nil)
+	}
+
+	expected := CreateAPIKeyOutput{
+		APIKeyOutput: APIKeyOutput{Name: "socket", Prefix: "prefix", Scopes: []string{domain.ScopeMessagesWrite}, CreatedBy: "admin@email.com"},
+		Key:          "prefix.secret",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("[TestCase 'create api key'] Result: '%v' | Expected: '%v'", got, expected)
+	}
+
+	// Only the hash may reach the repository
+	if created.HashedKey() != "secret" {
+		t.Errorf("[TestCase 'create api key'] Stored: '%v' | Expected: '%v'", created.HashedKey(), "secret")
+	}
+
+	if len(events) != 1 || events[0].Action != domain.AuditAPIKeyCreated || events[0].Actor != "admin@email.com" {
+		t.Errorf("[TestCase 'create api key'] Audit: '%v' | Expected: '%v'", events, domain.AuditAPIKeyCreated)
+	}
+}
+
+func TestRevokeAPIKeyInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key     = domain.NewAPIKey(newUserId, "socket", "prefix", "secret", []string{domain.ScopeMessagesWrite}, "admin@email.com", time.Now())
+		revoked = key
+	)
+	revoked.Revoke(time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name          string
+		key           domain.APIKey
+		err           error
+		expectedError error
+		expectedAudit []string
+	}{
+		{
+			name:          "revoke active key",
+			key:           key,
+			expectedAudit: []string{domain.AuditAPIKeyRevoked},
+		},
+		{
+			name: "revoke already revoked key",
+			key:  revoked,
+		},
+		{
+			name:          "unknown key",
+			err:           domain.ErrAPIKeyNotFound,
+			expectedError: domain.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				events []domain.AuditEvent
+				stored domain.APIKey
+				uc     = NewRevokeAPIKeyInteractor(
+					mockAPIKeyRepo{key: tt.key, err: tt.err, revoked: &stored},
+					mockAuditLogger{events: &events},
+					mockRevokeAPIKeyPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), RevokeAPIKeyInput{Id: newUserId.Hex(), RevokedBy: "admin@email.com"})
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if err == nil && got.RevokedAt == nil {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: revoked key", tt.name, got)
+			}
+
+			var actions []string
+			for _, event := range events {
+				actions = append(actions, event.Action)
+			}
+			if !reflect.DeepEqual(actions, tt.expectedAudit) {
+				t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, actions, tt.expectedAudit)
+			}
+		})
+	}
+}
+
+type mockRevokeAPIKeyPresenter struct{}
+
+func (m mockRevokeAPIKeyPresenter) Output(key domain.APIKey) APIKeyOutput {
+	if !key.IsRevoked() {
+		return APIKeyOutput{Name: key.Name()}
+	}
+	revokedAt := key.RevokedAt()
+	return APIKeyOutput{Name: key.Name(), RevokedAt: &revokedAt}
+}
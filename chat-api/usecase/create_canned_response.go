@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	// Input port
+	CreateCannedResponseUseCase interface {
+		Execute(context.Context, CreateCannedResponseInput) (CannedResponseOutput, error)
+	}
+
+	// CreateCannedResponseInput describes a response, the body may use the
+	// variables of domain.TemplateVariables such as {{customer.firstName}}
+	CreateCannedResponseInput struct {
+		Shortcut string      `json:"shortcut" validate:"required"`
+		Title    string      `json:"title" validate:"required,max=120"`
+		Body     string      `json:"body" validate:"required,max=4000"`
+		Tags     []string    `json:"tags" validate:"omitempty,max=20,dive,max=64"`
+		Shared   bool        `json:"shared"`
+		Macro    MacroFields `json:"macro"`
+		// Owner is the authenticated rep
+		Owner string `json:"-" validate:"required"`
+	}
+
+	// Output port
+	CreateCannedResponsePresenter interface {
+		Output(domain.CannedResponse) CannedResponseOutput
+	}
+
+	createCannedResponseInteractor struct {
+		repo       domain.CannedResponseRepository
+		presenter  CreateCannedResponsePresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewCreateCannedResponseInteractor(
+	repo domain.CannedResponseRepository,
+	presenter CreateCannedResponsePresenter,
+	t time.Duration,
+) CreateCannedResponseUseCase {
+	return createCannedResponseInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (c createCannedResponseInteractor) Execute(ctx context.Context, input CreateCannedResponseInput) (CannedResponseOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.ctxTimeout)
+	defer cancel()
+
+	now := time.Now()
+	response := domain.NewCannedResponse(
+		primitive.NewObjectID(),
+		input.Shortcut,
+		input.Title,
+		input.Body,
+		input.Tags,
+		input.Owner,
+		input.Shared,
+		input.Macro.macro(),
+		now,
+		now,
+	)
+	if err := response.Validate(); err != nil {
+		return c.presenter.Output(domain.CannedResponse{}), err
+	}
+	if err := checkShortcutAvailable(ctx, c.repo, response); err != nil {
+		return c.presenter.Output(domain.CannedResponse{}), err
+	}
+
+	createdResponse, err := c.repo.CreateCannedResponse(ctx, response)
+	if err != nil {
+		return c.presenter.Output(domain.CannedResponse{}), err
+	}
+
+	return c.presenter.Output(createdResponse), nil
+}
+
+func (m MacroFields) macro() domain.Macro {
+	return domain.Macro{
+		Status:     m.Status,
+		AddTags:    m.AddTags,
+		RemoveTags: m.RemoveTags,
+	}
+}
+
+// checkShortcutAvailable makes sure a rep never sees two responses behind the same
+// shortcut. Personal responses clash with the shared ones and those of their owner,
+// shared responses clash with every response of the organization
+func checkShortcutAvailable(ctx context.Context, repo domain.CannedResponseRepository, response domain.CannedResponse) error {
+	query := domain.CannedResponseQuery{Shortcut: response.Shortcut()}
+	if !response.IsShared() {
+		query.VisibleTo = response.Owner()
+	}
+
+	clashes, err := repo.GetCannedResponses(ctx, query)
+	if err != nil {
+		return err
+	}
+	for _, clash := range clashes {
+		if clash.Id() != response.Id() {
+			return domain.ErrCannedResponseShortcutTaken
+		}
+	}
+	return nil
+}
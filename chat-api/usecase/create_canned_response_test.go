@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockCannedResponseRepo struct {
+	domain.CannedResponseRepository
+
+	responses *[]domain.CannedResponse
+}
+
+func (m mockCannedResponseRepo) CreateCannedResponse(_ context.Context, response domain.CannedResponse) (domain.CannedResponse, error) {
+	*m.responses = append(*m.responses, response)
+	return response, nil
+}
+
+func (m mockCannedResponseRepo) GetCannedResponseById(_ context.Context, id string) (domain.CannedResponse, error) {
+	for _, response := range *m.responses {
+		if response.Id().Hex() == id {
+			return response, nil
+		}
+	}
+	return domain.CannedResponse{}, domain.ErrCannedResponseNotFound
+}
+
+func (m mockCannedResponseRepo) GetCannedResponses(_ context.Context, query domain.CannedResponseQuery) ([]domain.CannedResponse, error) {
+	var found []domain.CannedResponse
+	for _, response := range *m.responses {
+		if (query.VisibleTo == "" || response.VisibleTo(query.VisibleTo)) &&
+			(query.Shortcut == "" || response.Shortcut() == query.Shortcut) &&
+			strings.HasPrefix(response.Shortcut(), query.ShortcutPrefix) {
+			found = append(found, response)
+		}
+	}
+	return found, nil
+}
+
+func (m mockCannedResponseRepo) UpdateCannedResponse(_ context.Context, response domain.CannedResponse) error {
+	for i := range *m.responses {
+		if (*m.responses)[i].Id() == response.Id() {
+			(*m.responses)[i] = response
+		}
+	}
+	return nil
+}
+
+func (m mockCannedResponseRepo) DeleteCannedResponse(_ context.Context, response domain.CannedResponse) error {
+	var kept []domain.CannedResponse
+	for _, r := range *m.responses {
+		if r.Id() != response.Id() {
+			kept = append(kept, r)
+		}
+	}
+	*m.responses = kept
+	return nil
+}
+
+type mockCannedResponsePresenter struct{}
+
+func (m mockCannedResponsePresenter) Output(response domain.CannedResponse) CannedResponseOutput {
+	return CannedResponseOutput{Shortcut: response.Shortcut(), Owner: response.Owner(), Shared: response.IsShared()}
+}
+
+// cannedResponses holds a shared /refund and a /hello owned by rep@email.com
+func cannedResponses() []domain.CannedResponse {
+	return []domain.CannedResponse{
+		domain.NewCannedResponse(primitive.NewObjectID(), "refund", "Refund", "Refunded", nil, "other@email.com", true, domain.Macro{}, time.Now(), time.Now()),
+		domain.NewCannedResponse(primitive.NewObjectID(), "hello", "Hello", "Hi {{customer.firstName}}", nil, "rep@email.com", false, domain.Macro{}, time.Now(), time.Now()),
+	}
+}
+
+func TestCreateCannedResponseInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		input         CreateCannedResponseInput
+		expected      string
+		expectedError error
+	}{
+		{
+			name:     "personal response",
+			input:    CreateCannedResponseInput{Shortcut: "/Bye", Title: "Bye", Body: "Bye {{ customer.firstName }}, {{rep.fullName}}", Owner: "rep@email.com"},
+			expected: "bye",
+		},
+		{
+			name:     "shortcut personal to another rep",
+			input:    CreateCannedResponseInput{Shortcut: "hello", Title: "Hello", Body: "Hello", Owner: "new@email.com"},
+			expected: "hello",
+		},
+		{
+			name:          "shortcut of a shared response",
+			input:         CreateCannedResponseInput{Shortcut: "refund", Title: "Refund", Body: "Refund", Owner: "rep@email.com"},
+			expectedError: domain.ErrCannedResponseShortcutTaken,
+		},
+		{
+			name:          "shared over a personal shortcut",
+			input:         CreateCannedResponseInput{Shortcut: "hello", Title: "Hello", Body: "Hello", Shared: true, Owner: "new@email.com"},
+			expectedError: domain.ErrCannedResponseShortcutTaken,
+		},
+		{
+			name:          "invalid shortcut",
+			input:         CreateCannedResponseInput{Shortcut: "good bye", Title: "Bye", Body: "Bye", Owner: "rep@email.com"},
+			expectedError: domain.ErrInvalidShortcut,
+		},
+		{
+			name:          "unknown variable",
+			input:         CreateCannedResponseInput{Shortcut: "bye", Title: "Bye", Body: "Bye {{customer.password}}", Owner: "rep@email.com"},
+			expectedError: domain.ErrUnknownTemplateVariable,
+		},
+	}
+
+	for _, tt := range tests {
+		var (
+			responses = cannedResponses()
+			uc        = NewCreateCannedResponseInteractor(mockCannedResponseRepo{responses: &responses}, mockCannedResponsePresenter{}, time.Second)
+		)
+
+		got, err := uc.Execute(context.Background(), tt.input)
+		if err != tt.expectedError {
+			t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			continue
+		}
+		if got.Shortcut != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got.Shortcut, tt.expected)
+		}
+		if stored := len(responses) == 3; stored != (tt.expectedError == nil) {
+			t.Errorf("[TestCase '%s'] Stored: '%v' | Expected: '%v'", tt.name, stored, tt.expectedError == nil)
+		}
+	}
+}
+
+func TestCannedResponseInteractors_Visibility(t *testing.T) {
+	t.Parallel()
+
+	var (
+		responses = cannedResponses()
+		repo      = mockCannedResponseRepo{responses: &responses}
+		shared    = responses[0].Id().Hex()
+		personal  = responses[1].Id().Hex()
+		get       = NewGetCannedResponseInteractor(repo, mockCannedResponsePresenter{}, time.Second)
+		update    = NewUpdateCannedResponseInteractor(repo, mockCannedResponsePresenter{}, time.Second)
+		remove    = NewDeleteCannedResponseInteractor(repo, mockCannedResponsePresenter{}, time.Second)
+		list      = NewGetCannedResponsesInteractor(repo, mockGetCannedResponsesPresenter{}, time.Second)
+	)
+
+	if got, err := list.Execute(context.Background(), GetCannedResponsesInput{Rep: "new@email.com"}); err != nil || got.Count != 1 {
+		t.Errorf("[TestCase 'list shared responses'] Result: '%v', '%v' | Expected: '%v'", got.Count, err, 1)
+	}
+	if got, err := list.Execute(context.Background(), GetCannedResponsesInput{Shortcut: "he", Rep: "rep@email.com"}); err != nil || got.Count != 1 {
+		t.Errorf("[TestCase 'list by shortcut prefix'] Result: '%v', '%v' | Expected: '%v'", got.Count, err, 1)
+	}
+	if _, err := get.Execute(context.Background(), GetCannedResponseInput{Id: personal, Rep: "new@email.com"}); err != domain.ErrCannedResponseNotFound {
+		t.Errorf("[TestCase 'personal response of another rep'] Result: '%v' | ExpectedError: '%v'", err, domain.ErrCannedResponseNotFound)
+	}
+	if _, err := remove.Execute(context.Background(), DeleteCannedResponseInput{Id: personal, DeletedBy: "new@email.com"}); err != domain.ErrCannedResponseNotFound {
+		t.Errorf("[TestCase 'delete personal response of another rep'] Result: '%v' | ExpectedError: '%v'", err, domain.ErrCannedResponseNotFound)
+	}
+
+	got, err := update.Execute(context.Background(), UpdateCannedResponseInput{Id: shared, Shortcut: "refund", Title: "Refund", Body: "Done", Shared: true, UpdatedBy: "rep@email.com"})
+	if err != nil || got.Owner != "other@email.com" || !got.Shared {
+		t.Errorf("[TestCase 'update shared response'] Result: '%v', '%v' | Expected: '%v'", got, err, "other@email.com")
+	}
+	if _, err := update.Execute(context.Background(), UpdateCannedResponseInput{Id: personal, Shortcut: "refund", Title: "Hello", Body: "Hello", UpdatedBy: "rep@email.com"}); err != domain.ErrCannedResponseShortcutTaken {
+		t.Errorf("[TestCase 'rename to a shared shortcut'] Result: '%v' | ExpectedError: '%v'", err, domain.ErrCannedResponseShortcutTaken)
+	}
+	if _, err := remove.Execute(context.Background(), DeleteCannedResponseInput{Id: personal, DeletedBy: "rep@email.com"}); err != nil || len(responses) != 1 {
+		t.Errorf("[TestCase 'delete own response'] Result: '%v', '%v' | Expected: '%v'", len(responses), err, 1)
+	}
+}
+
+type mockGetCannedResponsesPresenter struct{}
+
+func (m mockGetCannedResponsesPresenter) Output(responses []domain.CannedResponse) GetCannedResponsesOutput {
+	return GetCannedResponsesOutput{Count: len(responses)}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"chat-api/domain"
@@ -15,37 +16,58 @@ type (
 		Execute(context.Context, CreateChannelInput) (CreateChannelOutput, error)
 	}
 
+	// Input data, the tags, the priority and the attributes classify the channel from the start
 	CreateChannelInput struct {
-		UserEmail string `json:"userEmail" validate:"required"`
+		UserEmail    string            `json:"userEmail" validate:"required"`
+		UserFullName string            `json:"userFullName"`
+		Tags         []string          `json:"tags" validate:"omitempty,max=20,dive,max=64"`
+		Priority     string            `json:"priority" validate:"omitempty,oneof=LOW NORMAL HIGH URGENT"`
+		Attributes   map[string]string `json:"attributes" validate:"omitempty,max=20"`
+		Guest        bool              `json:"-"`
 	}
 
 	// Output port
 	CreateChannelPresenter interface {
-		Output(domain.Channel) CreateChannelOutput
+		Output(domain.Channel, bool) CreateChannelOutput
 	}
 
 	// Output data
 	CreateChannelOutput struct {
 		Id            string `json:"id"`
 		UserEmail     string `json:"userEmail"`
+		RepEmail      string `json:"repEmail,omitempty"`
 		CurrentStatus string `json:"currentStatus"`
+		// OutsideBusinessHours tells the channel waits in the queue until the organization opens
+		OutsideBusinessHours bool `json:"outsideBusinessHours,omitempty"`
 		// CreatedAt     time.Time `json:"createdAt"`
 	}
 
 	createChannelInteractor struct {
 		repo       domain.ChannelRepository
+		index      domain.SearchIndex
+		orgRepo    domain.OrganizationRepository
+		userRepo   domain.UserRepository
 		presenter  CreateChannelPresenter
 		ctxTimeout time.Duration
 	}
 )
 
+// maxRoutingCandidates bounds the admins considered when routing a channel
+const maxRoutingCandidates = 100
+
 func NewCreateChannelInteractor(
 	repo domain.ChannelRepository,
+	index domain.SearchIndex,
+	orgRepo domain.OrganizationRepository,
+	userRepo domain.UserRepository,
 	presenter CreateChannelPresenter,
 	t time.Duration,
 ) CreateChannelUseCase {
 	return createChannelInteractor{
 		repo:       repo,
+		index:      index,
+		orgRepo:    orgRepo,
+		userRepo:   userRepo,
 		presenter:  presenter,
 		ctxTimeout: t,
 	}
@@ -65,11 +87,87 @@ func (c createChannelInteractor) Execute(ctx context.Context, input CreateChanne
 	)
 
 	channel.UpdateStatus(domain.ACTIVE, input.UserEmail, time.Now().Unix())
+	if input.Guest {
+		channel.MarkGuest()
+	}
+	if err := applyChannelDetails(&channel, input.Tags, input.Priority, input.Attributes); err != nil {
+		return c.presenter.Output(domain.Channel{}, false), err
+	}
+
+	userFullName, err := c.userFullName(ctx, input)
+	if err != nil {
+		return c.presenter.Output(domain.Channel{}, false), err
+	}
+	channel.UpdateUserFullName(userFullName)
+
+	organization, err := currentOrganization(ctx, c.orgRepo)
+	if err != nil {
+		return c.presenter.Output(domain.Channel{}, false), err
+	}
+
+	settings := organization.Settings()
+	open := settings.BusinessHours.IsOpen(time.Now())
+	if open && settings.RoutingStrategy == domain.RoutingLeastBusy {
+		repEmail, err := c.leastBusyRep(ctx)
+		if err != nil {
+			return c.presenter.Output(domain.Channel{}, false), err
+		}
+		if repEmail != "" {
+			channel.UpdateRepEmail(repEmail)
+			channel.UpdateStatus(domain.IN_PROGRESS, repEmail, time.Now().Unix())
+		}
+	}
 
 	createdChannel, err := c.repo.CreateChannel(ctx, channel)
 	if err != nil {
-		return c.presenter.Output(domain.Channel{}), err
+		return c.presenter.Output(domain.Channel{}, false), err
+	}
+	c.index.IndexChannel(ctx, createdChannel)
+
+	return c.presenter.Output(createdChannel, !open), nil
+}
+
+// userFullName keeps the name of the customer on the channel for searches,
+// guests give theirs and registered customers have it on their account
+func (c createChannelInteractor) userFullName(ctx context.Context, input CreateChannelInput) (string, error) {
+	if input.UserFullName != "" || input.Guest {
+		return input.UserFullName, nil
+	}
+
+	user, err := c.userRepo.GetUserByEmail(ctx, input.UserEmail)
+	switch err {
+	case nil:
+		return strings.TrimSpace(user.FirstName() + " " + user.LastName()), nil
+	case domain.ErrUserNotFound:
+		return "", nil
+	default:
+		return "", err
+	}
+}
+
+// leastBusyRep returns the active admin of the organization with the fewest channels in progress
+func (c createChannelInteractor) leastBusyRep(ctx context.Context) (string, error) {
+	admins, err := c.userRepo.GetUsers(ctx, domain.ADMIN, 0, maxRoutingCandidates)
+	if err != nil {
+		return "", err
 	}
 
-	return c.presenter.Output(createdChannel), nil
+	var (
+		repEmail string
+		minCount int64 = -1
+	)
+	for _, admin := range admins {
+		if !admin.IsActive() {
+			continue
+		}
+
+		count, err := c.repo.GetChannelsByQueryCount(ctx, domain.ChannelQuery{RepEmail: admin.Email(), Statuses: []string{domain.IN_PROGRESS}})
+		if err != nil {
+			return "", err
+		}
+		if minCount == -1 || count < minCount {
+			repEmail, minCount = admin.Email(), count
+		}
+	}
+	return repEmail, nil
 }
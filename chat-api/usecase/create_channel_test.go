@@ -49,7 +49,7 @@ type mockCreateChannelPresenter struct {
 	result CreateChannelOutput
 }
 
-func (m mockCreateChannelPresenter) Output(_ domain.Channel) CreateChannelOutput {
+func (m mockCreateChannelPresenter) Output(_ domain.Channel, _ bool) CreateChannelOutput {
 	return m.result
 }
 
@@ -133,7 +133,14 @@ func TestCreateChannelInteractor_Execute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var uc = NewCreateChannelInteractor(tt.channelRepo, tt.presenter, time.Second)
+			var uc = NewCreateChannelInteractor(
+				tt.channelRepo,
+				mockSearchIndex{},
+				mockOrganizationRepo{err: domain.ErrOrganizationNotFound},
+				mockRoutingUserRepo{},
+				tt.presenter,
+				time.Second,
+			)
 
 			got, err := uc.Execute(context.Background(), tt.args.input)
 			if (err != nil) && (err.Error() != tt.expectedError) {
@@ -148,3 +155,191 @@ func TestCreateChannelInteractor_Execute(t *testing.T) {
 	}
 
 }
+
+type mockOrganizationRepo struct {
+	domain.OrganizationRepository
+
+	organization domain.Organization
+	err          error
+	updated      *domain.Organization
+}
+
+func (m mockOrganizationRepo) GetOrganizationById(_ context.Context, _ string) (domain.Organization, error) {
+	return m.organization, m.err
+}
+
+func (m mockOrganizationRepo) CreateOrganization(_ context.Context, organization domain.Organization) (domain.Organization, error) {
+	return organization, m.err
+}
+
+func (m mockOrganizationRepo) UpdateOrganizationSettings(_ context.Context, organization domain.Organization) error {
+	*m.updated = organization
+	return nil
+}
+
+type mockRoutingUserRepo struct {
+	domain.UserRepository
+
+	admins   []domain.User
+	customer *domain.User
+}
+
+func (m mockRoutingUserRepo) GetUsers(_ context.Context, _ string, _, _ int) ([]domain.User, error) {
+	return m.admins, nil
+}
+
+func (m mockRoutingUserRepo) GetUserByEmail(_ context.Context, _ string) (domain.User, error) {
+	if m.customer == nil {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return *m.customer, nil
+}
+
+type mockRoutingChannelRepo struct {
+	domain.ChannelRepository
+
+	inProgress map[string]int64
+}
+
+func (m mockRoutingChannelRepo) CreateChannel(_ context.Context, channel domain.Channel) (domain.Channel, error) {
+	return channel, nil
+}
+
+func (m mockRoutingChannelRepo) GetChannelsByQueryCount(_ context.Context, query domain.ChannelQuery) (int64, error) {
+	return m.inProgress[query.RepEmail], nil
+}
+
+type mockRoutingPresenter struct{}
+
+func (m mockRoutingPresenter) Output(channel domain.Channel, outsideBusinessHours bool) CreateChannelOutput {
+	return CreateChannelOutput{
+		UserEmail:            channel.UserEmail(),
+		RepEmail:             channel.RepEmail(),
+		CurrentStatus:        channel.CurrentStatus(),
+		OutsideBusinessHours: outsideBusinessHours,
+	}
+}
+
+func TestCreateChannelInteractor_Routing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now      = time.Now().UTC()
+		alwaysOn = domain.BusinessHours{TimeZone: "UTC", Days: []domain.BusinessDay{{Weekday: now.Weekday(), Open: "00:00", Close: "24:00"}}}
+		closed   = domain.BusinessHours{TimeZone: "UTC", Days: []domain.BusinessDay{{Weekday: (now.Weekday() + 1) % 7, Open: "00:00", Close: "24:00"}}}
+
+		busy     = domain.NewUser(newUserId, "Busy", "Rep", "busy@email.com", "hash", now, now)
+		free     = domain.NewUser(newUserId, "Free", "Rep", "free@email.com", "hash", now, now)
+		inactive = domain.NewUser(newUserId, "Gone", "Rep", "gone@email.com", "hash", now, now)
+	)
+	inactive.Deactivate(now)
+
+	organization := func(hours domain.BusinessHours, strategy string) domain.Organization {
+		o := domain.NewOrganization("acme", "Acme", []string{"support.acme.com"}, now, now)
+		o.UpdateSettings(domain.OrganizationSettings{BusinessHours: hours, RoutingStrategy: strategy}, now)
+		return o
+	}
+
+	tests := []struct {
+		name         string
+		organization domain.Organization
+		admins       []domain.User
+		inProgress   map[string]int64
+		expected     CreateChannelOutput
+	}{
+		{
+			name:         "least busy admin gets the channel",
+			organization: organization(alwaysOn, domain.RoutingLeastBusy),
+			admins:       []domain.User{busy, free},
+			inProgress:   map[string]int64{"busy@email.com": 3, "free@email.com": 1},
+			expected:     CreateChannelOutput{UserEmail: "user@email.com", RepEmail: "free@email.com", CurrentStatus: domain.IN_PROGRESS},
+		},
+		{
+			name:         "deactivated admins are never assigned",
+			organization: organization(alwaysOn, domain.RoutingLeastBusy),
+			admins:       []domain.User{inactive, busy},
+			inProgress:   map[string]int64{"busy@email.com": 3},
+			expected:     CreateChannelOutput{UserEmail: "user@email.com", RepEmail: "busy@email.com", CurrentStatus: domain.IN_PROGRESS},
+		},
+		{
+			name:         "closed organization queues the channel",
+			organization: organization(closed, domain.RoutingLeastBusy),
+			admins:       []domain.User{busy, free},
+			expected:     CreateChannelOutput{UserEmail: "user@email.com", CurrentStatus: domain.ACTIVE, OutsideBusinessHours: true},
+		},
+		{
+			name:         "manual routing queues the channel",
+			organization: organization(alwaysOn, domain.RoutingManual),
+			admins:       []domain.User{busy, free},
+			expected:     CreateChannelOutput{UserEmail: "user@email.com", CurrentStatus: domain.ACTIVE},
+		},
+		{
+			name:         "no admin to route to",
+			organization: organization(alwaysOn, domain.RoutingLeastBusy),
+			expected:     CreateChannelOutput{UserEmail: "user@email.com", CurrentStatus: domain.ACTIVE},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewCreateChannelInteractor(
+				mockRoutingChannelRepo{inProgress: tt.inProgress},
+				mockSearchIndex{},
+				mockOrganizationRepo{organization: tt.organization},
+				mockRoutingUserRepo{admins: tt.admins},
+				mockRoutingPresenter{},
+				time.Second,
+			)
+
+			got, err := uc.Execute(domain.WithTenant(context.Background(), "acme"), CreateChannelInput{UserEmail: "user@email.com"})
+			if err != nil {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, nil)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCreateChannelInteractor_UserFullName(t *testing.T) {
+	t.Parallel()
+
+	customer := domain.NewUser(newUserId, "First", "Last", "user@email.com", "hash", time.Now(), time.Now())
+
+	tests := []struct {
+		name     string
+		input    CreateChannelInput
+		customer *domain.User
+		expected string
+	}{
+		{name: "name of the account", input: CreateChannelInput{UserEmail: "user@email.com"}, customer: &customer, expected: "First Last"},
+		{name: "name given by a guest", input: CreateChannelInput{UserEmail: "user@email.com", UserFullName: "Guest Name", Guest: true}, customer: &customer, expected: "Guest Name"},
+		{name: "customer without an account", input: CreateChannelInput{UserEmail: "user@email.com"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				indexed []domain.Channel
+				uc      = NewCreateChannelInteractor(
+					mockRoutingChannelRepo{},
+					mockSearchIndex{indexed: &indexed},
+					mockOrganizationRepo{err: domain.ErrOrganizationNotFound},
+					mockRoutingUserRepo{customer: tt.customer},
+					mockRoutingPresenter{},
+					time.Second,
+				)
+			)
+
+			if _, err := uc.Execute(context.Background(), tt.input); err != nil {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, nil)
+			}
+			if len(indexed) != 1 || indexed[0].UserFullName() != tt.expected {
+				t.Errorf("[TestCase '%s'] Indexed: '%v' | Expected: '%v'", tt.name, indexed, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	CreateOrganizationUseCase interface {
+		Execute(context.Context, CreateOrganizationInput) (OrganizationOutput, error)
+	}
+
+	// Input data
+	CreateOrganizationInput struct {
+		Id        string   `json:"id" validate:"required,min=2,max=32,alphanum,lowercase"`
+		Name      string   `json:"name" validate:"required"`
+		Domains   []string `json:"domains" validate:"required,min=1,dive,hostname_rfc1123"`
+		CreatedBy string   `json:"-"`
+		IP        string   `json:"-"`
+	}
+
+	// Output port
+	CreateOrganizationPresenter interface {
+		Output(domain.Organization) OrganizationOutput
+	}
+
+	createOrganizationInteractor struct {
+		repo       domain.OrganizationRepository
+		audit      domain.AuditLogger
+		presenter  CreateOrganizationPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewCreateOrganizationInteractor(
+	repo domain.OrganizationRepository,
+	audit domain.AuditLogger,
+	presenter CreateOrganizationPresenter,
+	t time.Duration,
+) CreateOrganizationUseCase {
+	return createOrganizationInteractor{
+		repo:       repo,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case, only admins of the default organization may host new ones
+func (c createOrganizationInteractor) Execute(ctx context.Context, input CreateOrganizationInput) (OrganizationOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.ctxTimeout)
+	defer cancel()
+
+	if domain.TenantFromContext(ctx) != domain.DefaultTenantID {
+		return c.presenter.Output(domain.Organization{}), domain.ErrTenantForbidden
+	}
+	if input.Id == domain.DefaultTenantID {
+		return c.presenter.Output(domain.Organization{}), domain.ErrOrganizationAlreadyExists
+	}
+
+	organization := domain.NewOrganization(input.Id, input.Name, input.Domains, time.Now(), time.Now())
+
+	createdOrganization, err := c.repo.CreateOrganization(ctx, organization)
+	if err != nil {
+		return c.presenter.Output(domain.Organization{}), err
+	}
+
+	c.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditOrganizationCreated,
+		Actor:     input.CreatedBy,
+		Target:    createdOrganization.Id(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return c.presenter.Output(createdOrganization), nil
+}
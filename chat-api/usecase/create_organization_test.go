@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockOrganizationPresenter struct{}
+
+func (m mockOrganizationPresenter) Output(organization domain.Organization) OrganizationOutput {
+	return OrganizationOutput{
+		Id:      organization.Id(),
+		Domains: organization.Domains(),
+		Settings: OrganizationSettingsOutput{
+			TimeZone:        organization.Settings().BusinessHours.TimeZone,
+			RoutingStrategy: organization.Settings().RoutingStrategy,
+		},
+	}
+}
+
+func TestCreateOrganizationInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		tenant        string
+		input         CreateOrganizationInput
+		err           error
+		expected      OrganizationOutput
+		expectedError error
+		expectedAudit []string
+	}{
+		{
+			name:          "default organization admin creates an organization",
+			tenant:        domain.DefaultTenantID,
+			input:         CreateOrganizationInput{Id: "acme", Name: "Acme", Domains: []string{"Support.Acme.com:443"}, CreatedBy: "admin@email.com"},
+			expected:      OrganizationOutput{Id: "acme", Domains: []string{"support.acme.com"}, Settings: OrganizationSettingsOutput{RoutingStrategy: domain.RoutingManual}},
+			expectedAudit: []string{domain.AuditOrganizationCreated},
+		},
+		{
+			name:          "admins of another organization cannot create organizations",
+			tenant:        "globex",
+			input:         CreateOrganizationInput{Id: "acme", Name: "Acme", Domains: []string{"support.acme.com"}, CreatedBy: "admin@globex.com"},
+			expectedError: domain.ErrTenantForbidden,
+		},
+		{
+			name:          "default organization id is reserved",
+			tenant:        domain.DefaultTenantID,
+			input:         CreateOrganizationInput{Id: domain.DefaultTenantID, Name: "Default", Domains: []string{"support.acme.com"}},
+			expectedError: domain.ErrOrganizationAlreadyExists,
+		},
+		{
+			name:          "organization id or domain already taken",
+			tenant:        domain.DefaultTenantID,
+			input:         CreateOrganizationInput{Id: "acme", Name: "Acme", Domains: []string{"support.acme.com"}},
+			err:           domain.ErrOrganizationAlreadyExists,
+			expectedError: domain.ErrOrganizationAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				events []domain.AuditEvent
+				uc     = NewCreateOrganizationInteractor(
+					mockOrganizationRepo{err: tt.err},
+					mockAuditLogger{events: &events},
+					mockOrganizationPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(domain.WithTenant(context.Background(), tt.tenant), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if err == nil && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+
+			var actions []string
+			for _, event := range events {
+				actions = append(actions, event.Action)
+			}
+			if !reflect.DeepEqual(actions, tt.expectedAudit) {
+				t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, actions, tt.expectedAudit)
+			}
+		})
+	}
+}
+
+func TestUpdateOrganizationSettingsInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	monday := int(time.Monday)
+
+	configured := domain.NewOrganization("acme", "Acme", []string{"support.acme.com"}, time.Now(), time.Now())
+	configured.UpdateSettings(domain.OrganizationSettings{SLAPolicies: []domain.SLAPolicy{
+		{Priority: domain.PriorityUrgent, FirstResponse: 10 * time.Minute, Resolution: 2 * time.Hour},
+		{Priority: domain.PriorityLow, FirstResponse: time.Hour, Resolution: time.Hour},
+	}}, time.Now())
+
+	tests := []struct {
+		name             string
+		tenant           string
+		organization     domain.Organization
+		err              error
+		input            UpdateOrganizationSettingsInput
+		expected         OrganizationOutput
+		expectedPolicies []domain.SLAPolicy
+		expectedError    error
+	}{
+		{
+			name:     "default organization settings are saved before it is stored",
+			tenant:   domain.DefaultTenantID,
+			err:      domain.ErrOrganizationNotFound,
+			input:    UpdateOrganizationSettingsInput{TimeZone: "Europe/Paris", RoutingStrategy: domain.RoutingLeastBusy},
+			expected: OrganizationOutput{Id: domain.DefaultTenantID, Domains: []string{}, Settings: OrganizationSettingsOutput{TimeZone: "Europe/Paris", RoutingStrategy: domain.RoutingLeastBusy}},
+		},
+		{
+			name:         "organization updates its business hours",
+			tenant:       "acme",
+			organization: domain.NewOrganization("acme", "Acme", []string{"support.acme.com"}, time.Now(), time.Now()),
+			input: UpdateOrganizationSettingsInput{
+				TimeZone:        "America/New_York",
+				BusinessDays:    []BusinessDayInput{{Weekday: &monday, Open: "09:00", Close: "17:30"}},
+				RoutingStrategy: domain.RoutingManual,
+			},
+			expected: OrganizationOutput{Id: "acme", Domains: []string{"support.acme.com"}, Settings: OrganizationSettingsOutput{TimeZone: "America/New_York", RoutingStrategy: domain.RoutingManual}},
+		},
+		{
+			name:         "organization changes the SLA policy of a priority",
+			tenant:       "acme",
+			organization: configured,
+			input: UpdateOrganizationSettingsInput{
+				TimeZone:        "UTC",
+				RoutingStrategy: domain.RoutingManual,
+				SLAPolicies:     []SLAPolicyInput{{Priority: domain.PriorityUrgent, FirstResponseMinutes: 5, ResolutionMinutes: 60}},
+			},
+			expected: OrganizationOutput{Id: "acme", Domains: []string{"support.acme.com"}, Settings: OrganizationSettingsOutput{TimeZone: "UTC", RoutingStrategy: domain.RoutingManual}},
+			expectedPolicies: []domain.SLAPolicy{
+				{Priority: domain.PriorityLow, FirstResponse: time.Hour, Resolution: time.Hour},
+				{Priority: domain.PriorityUrgent, FirstResponse: 5 * time.Minute, Resolution: time.Hour},
+			},
+		},
+		{
+			name:         "priority configured twice",
+			tenant:       "acme",
+			organization: configured,
+			input: UpdateOrganizationSettingsInput{
+				TimeZone:        "UTC",
+				RoutingStrategy: domain.RoutingManual,
+				SLAPolicies: []SLAPolicyInput{
+					{Priority: domain.PriorityHigh, FirstResponseMinutes: 30},
+					{Priority: domain.PriorityHigh, FirstResponseMinutes: 60},
+				},
+			},
+			expectedError: domain.ErrInvalidSLAPolicy,
+		},
+		{
+			name:          "unknown time zone",
+			tenant:        "acme",
+			organization:  domain.NewOrganization("acme", "Acme", []string{"support.acme.com"}, time.Now(), time.Now()),
+			input:         UpdateOrganizationSettingsInput{TimeZone: "Mars/Olympus", RoutingStrategy: domain.RoutingManual},
+			expectedError: domain.ErrInvalidBusinessHours,
+		},
+		{
+			name:         "day closing before it opens",
+			tenant:       "acme",
+			organization: domain.NewOrganization("acme", "Acme", []string{"support.acme.com"}, time.Now(), time.Now()),
+			input: UpdateOrganizationSettingsInput{
+				TimeZone:        "UTC",
+				BusinessDays:    []BusinessDayInput{{Weekday: &monday, Open: "17:00", Close: "09:00"}},
+				RoutingStrategy: domain.RoutingManual,
+			},
+			expectedError: domain.ErrInvalidBusinessHours,
+		},
+		{
+			name:          "stored organization missing outside the default tenant",
+			tenant:        "acme",
+			err:           domain.ErrOrganizationNotFound,
+			input:         UpdateOrganizationSettingsInput{TimeZone: "UTC", RoutingStrategy: domain.RoutingManual},
+			expectedError: domain.ErrOrganizationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				events  []domain.AuditEvent
+				updated domain.Organization
+				uc      = NewUpdateOrganizationSettingsInteractor(
+					mockOrganizationRepo{organization: tt.organization, err: tt.err, updated: &updated},
+					mockAuditLogger{events: &events},
+					mockOrganizationPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(domain.WithTenant(context.Background(), tt.tenant), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+			if updated.Id() != tt.expected.Id || len(events) != 1 {
+				t.Errorf("[TestCase '%s'] Saved: '%v' with %d audit events | Expected: '%v'", tt.name, updated.Id(), len(events), tt.expected.Id)
+			}
+			if policies := updated.Settings().SLAPolicies; !reflect.DeepEqual(policies, tt.expectedPolicies) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, policies, tt.expectedPolicies)
+			}
+		})
+	}
+}
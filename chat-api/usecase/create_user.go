@@ -2,8 +2,6 @@ package usecase
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"time"
 
 	"chat-api/domain"
@@ -22,7 +20,16 @@ type (
 		LastName  string `json:"lastName" validate:"required"`
 		Email     string `json:"email" validate:"required"`
 		Password  string `json:"password" validate:"required"`
-		Role      string `json:"role" validate:"required"`
+		Role      string `json:"role" validate:"required,oneof=USER ADMIN"`
+		// CreatedBy is empty when users register themselves, only an admin
+		// creating the user, CreatedByAdmin, can give the ADMIN role
+		CreatedBy      string `json:"-"`
+		CreatedByAdmin bool   `json:"-"`
+		IP             string `json:"-"`
+		// GuestChannelId and GuestEmail come from the guest token presented by a
+		// guest registering, holding it proves they opened that channel
+		GuestChannelId string `json:"-"`
+		GuestEmail     string `json:"-"`
 	}
 
 	// Output port
@@ -38,24 +45,30 @@ type (
 	}
 
 	createUserInteractor struct {
-		repo       domain.UserRepository
-		service    domain.AuthenticationUtilityService
-		presenter  CreateUserPresenter
-		ctxTimeout time.Duration
+		repo        domain.UserRepository
+		channelRepo domain.ChannelRepository
+		service     domain.AuthenticationUtilityService
+		audit       domain.AuditLogger
+		presenter   CreateUserPresenter
+		ctxTimeout  time.Duration
 	}
 )
 
 func NewCreateUserInteractor(
 	repo domain.UserRepository,
+	channelRepo domain.ChannelRepository,
 	service domain.AuthenticationUtilityService,
+	audit domain.AuditLogger,
 	presenter CreateUserPresenter,
 	t time.Duration,
 ) CreateUserUseCase {
 	return createUserInteractor{
-		repo:       repo,
-		service:    service,
-		presenter:  presenter,
-		ctxTimeout: t,
+		repo:        repo,
+		channelRepo: channelRepo,
+		service:     service,
+		audit:       audit,
+		presenter:   presenter,
+		ctxTimeout:  t,
 	}
 }
 
@@ -64,13 +77,15 @@ func (c createUserInteractor) Execute(ctx context.Context, input CreateUserInput
 	ctx, cancel := context.WithTimeout(ctx, c.ctxTimeout)
 	defer cancel()
 
-	existingUser, _ := c.repo.GetUserByEmail(ctx, input.Email)
-	// if err != nil {
-	// 	return c.presenter.Output(domain.User{}), err
-	// }
+	if input.Role == domain.ADMIN && !input.CreatedByAdmin {
+		return c.presenter.Output(domain.User{}), domain.ErrAdminCreatedByAdmin
+	}
 
+	// Fails fast on the common case, the unique index on email is what
+	// actually guards against two concurrent registrations
+	existingUser, _ := c.repo.GetUserByEmail(ctx, input.Email)
 	if existingUser.Email() != "" {
-		return c.presenter.Output(domain.User{}), errors.New(fmt.Sprintf("User already exists with email"))
+		return c.presenter.Output(domain.User{}), domain.ErrUserAlreadyExists
 	}
 
 	hashedPassword, err := c.service.HashPassword(ctx, input.Password)
@@ -89,10 +104,35 @@ func (c createUserInteractor) Execute(ctx context.Context, input CreateUserInput
 	)
 	user.UpdateRole(input.Role)
 
+	// Anyone can open a guest channel with any email, so only the channel of the
+	// guest token presented is handed over, and only to the email it was opened
+	// with. It is merged before the account is created, so a failure can be retried
+	if input.GuestChannelId != "" && domain.NormalizeEmail(input.GuestEmail) == user.Email() {
+		if err := c.channelRepo.MergeGuestChannel(ctx, user.Email(), input.GuestChannelId); err != nil {
+			return c.presenter.Output(domain.User{}), err
+		}
+	}
+
 	createdUser, err := c.repo.CreateUser(ctx, user)
 	if err != nil {
 		return c.presenter.Output(domain.User{}), err
 	}
 
+	action, actor := domain.AuditUserCreated, input.CreatedBy
+	if user.Role() == domain.ADMIN {
+		action = domain.AuditAdminCreated
+	}
+	if actor == "" {
+		actor = createdUser.Email()
+	}
+	c.audit.Record(ctx, domain.AuditEvent{
+		Action:    action,
+		Actor:     actor,
+		Target:    createdUser.Email(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
 	return c.presenter.Output(createdUser), nil
 }
@@ -3,6 +3,7 @@ package usecase
 import (
 	"chat-api/domain"
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -62,6 +63,18 @@ func (m mockCreateUserRepo) GetUserByEmail(_ context.Context, _ string) (domain.
 	return m.getUserByEmailFake()
 }
 
+type mockMergeGuestChannelRepo struct {
+	domain.ChannelRepository
+
+	merged *string
+	err    error
+}
+
+func (m mockMergeGuestChannelRepo) MergeGuestChannel(_ context.Context, email, id string) error {
+	*m.merged = email + " " + id
+	return m.err
+}
+
 type mockCreateUserPresenter struct {
 	result CreateUserOutput
 }
@@ -85,6 +98,9 @@ func TestCreateUserInteractor_Execute(t *testing.T) {
 		presenter     CreateUserPresenter
 		expected      CreateUserOutput
 		expectedError string
+		mergeErr      error
+		expectedMerge string
+		expectedAudit []domain.AuditEvent
 	}{
 		{
 			name: "create a user successful",
@@ -94,15 +110,117 @@ func TestCreateUserInteractor_Execute(t *testing.T) {
 			}, getUserByEmailFake: func() (domain.User, error) {
 				return domain.User{}, nil
 			}},
-			service:   mockAuthenticationService{result: "03jr04jf03jlkjfeo3nflp23049tfj30"},
-			presenter: mockCreateUserPresenter{result: CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "newEmail@email.com"}},
-			expected:  CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "newEmail@email.com"},
+			service:       mockAuthenticationService{result: "03jr04jf03jlkjfeo3nflp23049tfj30"},
+			presenter:     mockCreateUserPresenter{result: CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "newEmail@email.com"}},
+			expected:      CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "newEmail@email.com"},
+			expectedAudit: []domain.AuditEvent{{Action: domain.AuditUserCreated, Actor: "newemail@email.com", Target: "newemail@email.com"}},
+		},
+		{
+			name: "create a user with the guest token of their channel merges it",
+			args: args{input: CreateUserInput{Email: "newEmail@email.com", Password: "password", FirstName: "firstName", LastName: "lastName", Role: "user", GuestChannelId: "c1", GuestEmail: "NewEmail@Email.com"}},
+			userRepo: mockCreateUserRepo{createUserFake: func() (domain.User, error) {
+				return domain.NewUser(newUserId, "firstName", "lastName", "newEmail@email.com", "password", createdTime, createdTime), nil
+			}, getUserByEmailFake: func() (domain.User, error) {
+				return domain.User{}, nil
+			}},
+			service:       mockAuthenticationService{result: "03jr04jf03jlkjfeo3nflp23049tfj30"},
+			presenter:     mockCreateUserPresenter{result: CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "newEmail@email.com"}},
+			expected:      CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "newEmail@email.com"},
+			expectedMerge: "newemail@email.com c1",
+			expectedAudit: []domain.AuditEvent{{Action: domain.AuditUserCreated, Actor: "newemail@email.com", Target: "newemail@email.com"}},
+		},
+		{
+			name: "create a user with the guest token of another email merges nothing",
+			args: args{input: CreateUserInput{Email: "newEmail@email.com", Password: "password", FirstName: "firstName", LastName: "lastName", Role: "user", GuestChannelId: "c1", GuestEmail: "guest@email.com"}},
+			userRepo: mockCreateUserRepo{createUserFake: func() (domain.User, error) {
+				return domain.NewUser(newUserId, "firstName", "lastName", "newEmail@email.com", "password", createdTime, createdTime), nil
+			}, getUserByEmailFake: func() (domain.User, error) {
+				return domain.User{}, nil
+			}},
+			service:       mockAuthenticationService{result: "03jr04jf03jlkjfeo3nflp23049tfj30"},
+			presenter:     mockCreateUserPresenter{result: CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "newEmail@email.com"}},
+			expected:      CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "newEmail@email.com"},
+			expectedAudit: []domain.AuditEvent{{Action: domain.AuditUserCreated, Actor: "newemail@email.com", Target: "newemail@email.com"}},
+		},
+		{
+			name: "create a user failing to merge their guest channel",
+			args: args{input: CreateUserInput{Email: "newEmail@email.com", Password: "password", FirstName: "firstName", LastName: "lastName", Role: "user", GuestChannelId: "c1", GuestEmail: "newemail@email.com"}},
+			userRepo: mockCreateUserRepo{createUserFake: func() (domain.User, error) {
+				return domain.NewUser(newUserId, "firstName", "lastName", "newEmail@email.com", "password", createdTime, createdTime), nil
+			}, getUserByEmailFake: func() (domain.User, error) {
+				return domain.User{}, nil
+			}},
+			service:       mockAuthenticationService{result: "03jr04jf03jlkjfeo3nflp23049tfj30"},
+			presenter:     mockCreateUserPresenter{},
+			mergeErr:      errors.New("connection lost"),
+			expectedError: "connection lost",
+			expectedMerge: "newemail@email.com c1",
+		},
+		{
+			name: "create an admin records who created it",
+			args: args{input: CreateUserInput{Email: "admin@email.com", Password: "password", FirstName: "firstName", LastName: "lastName", Role: domain.ADMIN, CreatedBy: "root@email.com", CreatedByAdmin: true, IP: "10.0.0.1"}},
+			userRepo: mockCreateUserRepo{createUserFake: func() (domain.User, error) {
+				return domain.NewUser(newUserId, "firstName", "lastName", "admin@email.com", "password", createdTime, createdTime), nil
+			}, getUserByEmailFake: func() (domain.User, error) {
+				return domain.User{}, domain.ErrUserNotFound
+			}},
+			service:       mockAuthenticationService{result: "03jr04jf03jlkjfeo3nflp23049tfj30"},
+			presenter:     mockCreateUserPresenter{result: CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "admin@email.com"}},
+			expected:      CreateUserOutput{FirstName: "firstName", LastName: "lastName", Email: "admin@email.com"},
+			expectedAudit: []domain.AuditEvent{{Action: domain.AuditAdminCreated, Actor: "root@email.com", Target: "admin@email.com", IP: "10.0.0.1"}},
+		},
+		{
+			name: "create an admin without being one",
+			args: args{input: CreateUserInput{Email: "admin@email.com", Password: "password", FirstName: "firstName", LastName: "lastName", Role: domain.ADMIN, CreatedBy: "user@email.com"}},
+			userRepo: mockCreateUserRepo{createUserFake: func() (domain.User, error) {
+				return domain.NewUser(newUserId, "firstName", "lastName", "admin@email.com", "password", createdTime, createdTime), nil
+			}, getUserByEmailFake: func() (domain.User, error) {
+				return domain.User{}, domain.ErrUserNotFound
+			}},
+			service:       mockAuthenticationService{result: "03jr04jf03jlkjfeo3nflp23049tfj30"},
+			presenter:     mockCreateUserPresenter{},
+			expectedError: domain.ErrAdminCreatedByAdmin.Error(),
+		},
+		{
+			name: "create a user with an email already registered in another case",
+			args: args{input: CreateUserInput{Email: "NewEmail@Email.com", Password: "password", FirstName: "firstName", LastName: "lastName", Role: "user"}},
+			userRepo: mockCreateUserRepo{createUserFake: func() (domain.User, error) {
+				return domain.User{}, nil
+			}, getUserByEmailFake: func() (domain.User, error) {
+				return domain.NewUser(newUserId, "firstName", "lastName", "newemail@email.com", "password", createdTime, createdTime), nil
+			}},
+			service:       mockAuthenticationService{result: "03jr04jf03jlkjfeo3nflp23049tfj30"},
+			presenter:     mockCreateUserPresenter{},
+			expectedError: domain.ErrUserAlreadyExists.Error(),
+		},
+		{
+			name: "create a user losing a concurrent registration",
+			args: args{input: CreateUserInput{Email: "newEmail@email.com", Password: "password", FirstName: "firstName", LastName: "lastName", Role: "user"}},
+			userRepo: mockCreateUserRepo{createUserFake: func() (domain.User, error) {
+				return domain.User{}, domain.ErrUserAlreadyExists
+			}, getUserByEmailFake: func() (domain.User, error) {
+				return domain.User{}, domain.ErrUserNotFound
+			}},
+			service:       mockAuthenticationService{result: "03jr04jf03jlkjfeo3nflp23049tfj30"},
+			presenter:     mockCreateUserPresenter{},
+			expectedError: domain.ErrUserAlreadyExists.Error(),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var uc = NewCreateUserInteractor(tt.userRepo, tt.service, tt.presenter, time.Second)
+			var (
+				merged string
+				events []domain.AuditEvent
+				uc     = NewCreateUserInteractor(
+					tt.userRepo,
+					mockMergeGuestChannelRepo{merged: &merged, err: tt.mergeErr},
+					tt.service,
+					mockAuditLogger{events: &events},
+					tt.presenter,
+					time.Second,
+				)
+			)
 
 			got, err := uc.Execute(context.Background(), tt.args.input)
 			if (err != nil) && (err.Error() != tt.expectedError) {
@@ -113,6 +231,20 @@ func TestCreateUserInteractor_Execute(t *testing.T) {
 			if !reflect.DeepEqual(got, tt.expected) {
 				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
 			}
+
+			if merged != tt.expectedMerge {
+				t.Errorf("[TestCase '%s'] Merged guest channel: '%v' | Expected: '%v'", tt.name, merged, tt.expectedMerge)
+			}
+
+			if len(events) != len(tt.expectedAudit) {
+				t.Fatalf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, events, tt.expectedAudit)
+			}
+			for i, event := range events {
+				expected := tt.expectedAudit[i]
+				if event.Action != expected.Action || event.Actor != expected.Actor || event.Target != expected.Target || event.IP != expected.IP {
+					t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, event, expected)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	DeleteCannedResponseUseCase interface {
+		Execute(context.Context, DeleteCannedResponseInput) (CannedResponseOutput, error)
+	}
+
+	// Input data
+	DeleteCannedResponseInput struct {
+		Id string `json:"id" validate:"required"`
+		// DeletedBy is the authenticated rep
+		DeletedBy string `json:"-" validate:"required"`
+	}
+
+	// Output port
+	DeleteCannedResponsePresenter interface {
+		Output(domain.CannedResponse) CannedResponseOutput
+	}
+
+	deleteCannedResponseInteractor struct {
+		repo       domain.CannedResponseRepository
+		presenter  DeleteCannedResponsePresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewDeleteCannedResponseInteractor(
+	repo domain.CannedResponseRepository,
+	presenter DeleteCannedResponsePresenter,
+	t time.Duration,
+) DeleteCannedResponseUseCase {
+	return deleteCannedResponseInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (d deleteCannedResponseInteractor) Execute(ctx context.Context, input DeleteCannedResponseInput) (CannedResponseOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.ctxTimeout)
+	defer cancel()
+
+	response, err := visibleCannedResponse(ctx, d.repo, input.Id, input.DeletedBy)
+	if err != nil {
+		return d.presenter.Output(domain.CannedResponse{}), err
+	}
+
+	if err := d.repo.DeleteCannedResponse(ctx, response); err != nil {
+		return d.presenter.Output(domain.CannedResponse{}), err
+	}
+
+	return d.presenter.Output(response), nil
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	DeleteMessageUseCase interface {
+		Execute(context.Context, DeleteMessageInput) (DeleteMessageOutput, error)
+	}
+
+	// Input data
+	DeleteMessageInput struct {
+		ChannelId string `json:"channelId" validate:"required"`
+		MessageId string `json:"messageId" validate:"required"`
+		// Editor is the authenticated caller, Admin lets them delete messages of anyone at any time
+		Editor string `json:"-" validate:"required"`
+		Admin  bool   `json:"-"`
+		IP     string `json:"-"`
+	}
+
+	// Output port
+	DeleteMessagePresenter interface {
+		Output(domain.Channel, domain.Message) DeleteMessageOutput
+	}
+
+	// Output data
+	DeleteMessageOutput struct {
+		ChannelId string        `json:"channelId"`
+		Message   MessageOutput `json:"message"`
+	}
+
+	deleteMessageInteractor struct {
+		repo       domain.ChannelRepository
+		index      domain.SearchIndex
+		notifier   domain.ChannelNotifier
+		audit      domain.AuditLogger
+		presenter  DeleteMessagePresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewDeleteMessageInteractor(
+	repo domain.ChannelRepository,
+	index domain.SearchIndex,
+	notifier domain.ChannelNotifier,
+	audit domain.AuditLogger,
+	presenter DeleteMessagePresenter,
+	t time.Duration,
+) DeleteMessageUseCase {
+	return deleteMessageInteractor{
+		repo:       repo,
+		index:      index,
+		notifier:   notifier,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a deleteMessageInteractor) Execute(ctx context.Context, input DeleteMessageInput) (DeleteMessageOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	channel, message, err := changeableMessage(ctx, a.repo, input.ChannelId, input.MessageId, input.Admin)
+	if err != nil {
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	now := time.Now()
+	if err := message.CanBeChangedBy(input.Editor, input.Admin, now); err != nil {
+		a.record(ctx, input, domain.AuditOutcomeFailure)
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	if message, err = channel.DeleteMessage(message.Id, now); err != nil {
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	if err := a.repo.UpdateMessages(ctx, channel); err != nil {
+		a.record(ctx, input, domain.AuditOutcomeFailure)
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	a.record(ctx, input, domain.AuditOutcomeSuccess)
+	a.index.IndexChannel(ctx, channel)
+	a.notifier.Notify(ctx, domain.ChannelEvent{
+		Type:        domain.ChannelEventMessageDeleted,
+		ChannelId:   channel.Id().Hex(),
+		UserEmail:   channel.UserEmail(),
+		MessageId:   message.Id.Hex(),
+		MessageFrom: message.MessageFrom,
+		Internal:    message.Internal,
+		Timestamp:   message.DeletedAt,
+	})
+
+	return a.presenter.Output(channel, message), nil
+}
+
+func (a deleteMessageInteractor) record(ctx context.Context, input DeleteMessageInput, outcome string) {
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditMessageDeleted,
+		Actor:     input.Editor,
+		Target:    input.ChannelId + "/" + input.MessageId,
+		IP:        input.IP,
+		Outcome:   outcome,
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockDeleteMessagePresenter struct{}
+
+func (m mockDeleteMessagePresenter) Output(channel domain.Channel, message domain.Message) DeleteMessageOutput {
+	return DeleteMessageOutput{ChannelId: channel.Id().Hex(), Message: MessageOutput{Message: message.Message, Deleted: message.IsDeleted}}
+}
+
+func TestDeleteMessageInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		age           time.Duration
+		editor        string
+		admin         bool
+		deleteTwice   bool
+		note          bool
+		expectedError error
+	}{
+		{
+			name:   "author within the window",
+			age:    time.Minute,
+			editor: "user@email.com",
+		},
+		{
+			name:          "author after the window",
+			age:           domain.MessageEditWindow + time.Minute,
+			editor:        "user@email.com",
+			expectedError: domain.ErrMessageEditWindowClosed,
+		},
+		{
+			name:          "someone else",
+			age:           time.Minute,
+			editor:        "other@email.com",
+			expectedError: domain.ErrNotMessageAuthor,
+		},
+		{
+			name:   "admin after the window",
+			age:    24 * time.Hour,
+			editor: "rep@email.com",
+			admin:  true,
+		},
+		{
+			name:          "message already deleted",
+			age:           time.Minute,
+			editor:        "user@email.com",
+			deleteTwice:   true,
+			expectedError: domain.ErrMessageDeleted,
+		},
+		{
+			name:          "internal note for the customer",
+			age:           time.Minute,
+			editor:        "user@email.com",
+			note:          true,
+			expectedError: domain.ErrMessageNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, id := messageChannel(tt.age)
+			if tt.note {
+				channel.AddNote("rep@email.com", "refund approved", time.Now())
+				id = channel.Messages()[1].Id
+			}
+			if tt.deleteTwice {
+				if _, err := channel.DeleteMessage(id, time.Now()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var (
+				updated  domain.Channel
+				audit    []domain.AuditEvent
+				notified []domain.ChannelEvent
+				uc       = NewDeleteMessageInteractor(
+					mockMessageRepo{channel: channel, updated: &updated},
+					mockSearchIndex{},
+					mockChannelNotifier{events: &notified},
+					mockAuditLogger{events: &audit},
+					mockDeleteMessagePresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), DeleteMessageInput{
+				ChannelId: channel.Id().Hex(),
+				MessageId: id.Hex(),
+				Editor:    tt.editor,
+				Admin:     tt.admin,
+			})
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+			if err != nil {
+				if len(notified) != 0 {
+					t.Errorf("[TestCase '%s'] Notified: '%v' | Expected: none", tt.name, notified)
+				}
+				return
+			}
+
+			if expected := (MessageOutput{Deleted: true}); !reflect.DeepEqual(got.Message, expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got.Message, expected)
+			}
+			message := updated.Messages()[0]
+			if !message.IsDeleted || message.Message != "" || len(message.Edits) != 1 || message.Edits[0].Message != "helo" {
+				t.Errorf("[TestCase '%s'] Stored: '%+v' | Expected a tombstone keeping the text in its edits", tt.name, message)
+			}
+			if len(audit) != 1 || audit[0].Action != domain.AuditMessageDeleted || audit[0].Outcome != domain.AuditOutcomeSuccess {
+				t.Errorf("[TestCase '%s'] Audit: '%v' | Expected one successful deletion", tt.name, audit)
+			}
+			expected := []domain.ChannelEvent{{
+				Type:        domain.ChannelEventMessageDeleted,
+				ChannelId:   channel.Id().Hex(),
+				UserEmail:   "user@email.com",
+				MessageId:   id.Hex(),
+				MessageFrom: "user@email.com",
+				Timestamp:   message.DeletedAt,
+			}}
+			if !reflect.DeepEqual(notified, expected) {
+				t.Errorf("[TestCase '%s'] Notified: '%v' | Expected: '%v'", tt.name, notified, expected)
+			}
+		})
+	}
+}
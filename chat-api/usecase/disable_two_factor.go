@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	DisableTwoFactorUseCase interface {
+		Execute(context.Context, DisableTwoFactorInput) (DisableTwoFactorOutput, error)
+	}
+
+	// Input data
+	DisableTwoFactorInput struct {
+		Email string `json:"-" validate:"required"`
+		Code  string `json:"code" validate:"required"`
+		IP    string `json:"-"`
+	}
+
+	// Output port
+	DisableTwoFactorPresenter interface {
+		Output(domain.User) DisableTwoFactorOutput
+	}
+
+	// Output data
+	DisableTwoFactorOutput struct {
+		Email            string `json:"email"`
+		TwoFactorEnabled bool   `json:"twoFactorEnabled"`
+	}
+
+	disableTwoFactorInteractor struct {
+		repo       domain.UserRepository
+		twoFactor  domain.TwoFactorService
+		audit      domain.AuditLogger
+		presenter  DisableTwoFactorPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewDisableTwoFactorInteractor(
+	repo domain.UserRepository,
+	twoFactor domain.TwoFactorService,
+	audit domain.AuditLogger,
+	presenter DisableTwoFactorPresenter,
+	t time.Duration,
+) DisableTwoFactorUseCase {
+	return disableTwoFactorInteractor{
+		repo:       repo,
+		twoFactor:  twoFactor,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a disableTwoFactorInteractor) Execute(ctx context.Context, input DisableTwoFactorInput) (DisableTwoFactorOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	user, err := a.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	if !user.TwoFactorEnabled() {
+		return a.presenter.Output(domain.User{}), domain.ErrTwoFactorNotEnrolled
+	}
+
+	valid, _, err := verifyTwoFactorCode(ctx, a.twoFactor, &user, input.Code)
+	if err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+	if !valid {
+		return a.presenter.Output(domain.User{}), domain.ErrInvalidTwoFactorCode
+	}
+
+	user.UpdateTwoFactor("", false, nil)
+	if err := a.repo.UpdateTwoFactor(ctx, user); err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditTwoFactorDisabled,
+		Actor:     user.Email(),
+		Target:    user.Email(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return a.presenter.Output(user), nil
+}
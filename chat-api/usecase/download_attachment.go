@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	DownloadAttachmentUseCase interface {
+		Execute(context.Context, DownloadAttachmentInput) (DownloadAttachmentOutput, error)
+	}
+
+	// Input data, the query of a link signed by GetAttachmentURLUseCase
+	DownloadAttachmentInput struct {
+		AttachmentId string `validate:"required"`
+		Expires      int64  `validate:"required"`
+		Signature    string `validate:"required"`
+	}
+
+	// Output port
+	DownloadAttachmentPresenter interface {
+		Output(domain.Attachment, io.ReadCloser) DownloadAttachmentOutput
+	}
+
+	// Output data, the caller closes Content
+	DownloadAttachmentOutput struct {
+		FileName    string
+		ContentType string
+		Size        int64
+		Content     io.ReadCloser
+	}
+
+	downloadAttachmentInteractor struct {
+		attachments domain.AttachmentRepository
+		blobs       domain.BlobStore
+		signer      domain.AttachmentURLSigner
+		presenter   DownloadAttachmentPresenter
+		ctxTimeout  time.Duration
+	}
+)
+
+func NewDownloadAttachmentInteractor(
+	attachments domain.AttachmentRepository,
+	blobs domain.BlobStore,
+	signer domain.AttachmentURLSigner,
+	presenter DownloadAttachmentPresenter,
+	t time.Duration,
+) DownloadAttachmentUseCase {
+	return downloadAttachmentInteractor{
+		attachments: attachments,
+		blobs:       blobs,
+		signer:      signer,
+		presenter:   presenter,
+		ctxTimeout:  t,
+	}
+}
+
+// Execute checks the signature of the link, which stands in for the
+// credentials of the participant it was issued to
+func (a downloadAttachmentInteractor) Execute(ctx context.Context, input DownloadAttachmentInput) (DownloadAttachmentOutput, error) {
+	expiresAt := time.Unix(input.Expires, 0)
+	if err := a.signer.Verify(ctx, input.AttachmentId, expiresAt, input.Signature); err != nil {
+		return a.presenter.Output(domain.Attachment{}, nil), err
+	}
+
+	// The timeout only bounds the lookups, the content streams for as long as the client reads
+	lookupCtx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	attachment, err := a.attachments.GetAttachmentById(lookupCtx, input.AttachmentId)
+	if err != nil {
+		return a.presenter.Output(domain.Attachment{}, nil), err
+	}
+
+	content, err := a.blobs.Get(ctx, attachment.Key())
+	switch err {
+	case nil:
+	case domain.ErrBlobNotFound:
+		return a.presenter.Output(domain.Attachment{}, nil), domain.ErrAttachmentNotFound
+	default:
+		return a.presenter.Output(domain.Attachment{}, nil), err
+	}
+
+	return a.presenter.Output(attachment, content), nil
+}
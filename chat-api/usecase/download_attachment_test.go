@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockDownloadAttachmentPresenter struct{}
+
+func (m mockDownloadAttachmentPresenter) Output(attachment domain.Attachment, content io.ReadCloser) DownloadAttachmentOutput {
+	return DownloadAttachmentOutput{FileName: attachment.FileName(), Content: content}
+}
+
+func TestDownloadAttachmentInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		signer     = mockAttachmentURLSigner{}
+		attachment = domain.NewAttachment(primitive.NewObjectID(), primitive.NewObjectID().Hex(), "user@email.com", "a.txt", "text/plain; charset=utf-8", 5, time.Now())
+		missing    = domain.NewAttachment(primitive.NewObjectID(), primitive.NewObjectID().Hex(), "user@email.com", "b.txt", "text/plain; charset=utf-8", 5, time.Now())
+		repo       = mockAttachmentRepo{attachments: map[string]domain.Attachment{
+			attachment.Id().Hex(): attachment,
+			missing.Id().Hex():    missing,
+		}}
+		blobs   = mockBlobStore{blobs: map[string][]byte{attachment.Key(): []byte("hello")}}
+		expires = time.Now().Add(time.Minute).Truncate(time.Second)
+		expired = time.Now().Add(-time.Minute).Truncate(time.Second)
+	)
+
+	tests := []struct {
+		name          string
+		input         DownloadAttachmentInput
+		expected      string
+		expectedError error
+	}{
+		{
+			name: "signed link",
+			input: DownloadAttachmentInput{
+				AttachmentId: attachment.Id().Hex(),
+				Expires:      expires.Unix(),
+				Signature:    signer.Sign(context.Background(), attachment.Id().Hex(), expires),
+			},
+			expected: "hello",
+		},
+		{
+			name: "expired link",
+			input: DownloadAttachmentInput{
+				AttachmentId: attachment.Id().Hex(),
+				Expires:      expired.Unix(),
+				Signature:    signer.Sign(context.Background(), attachment.Id().Hex(), expired),
+			},
+			expectedError: domain.ErrInvalidAttachmentURL,
+		},
+		{
+			name: "signature of another attachment",
+			input: DownloadAttachmentInput{
+				AttachmentId: attachment.Id().Hex(),
+				Expires:      expires.Unix(),
+				Signature:    signer.Sign(context.Background(), missing.Id().Hex(), expires),
+			},
+			expectedError: domain.ErrInvalidAttachmentURL,
+		},
+		{
+			name: "content missing from the store",
+			input: DownloadAttachmentInput{
+				AttachmentId: missing.Id().Hex(),
+				Expires:      expires.Unix(),
+				Signature:    signer.Sign(context.Background(), missing.Id().Hex(), expires),
+			},
+			expectedError: domain.ErrAttachmentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewDownloadAttachmentInteractor(repo, blobs, signer, mockDownloadAttachmentPresenter{}, time.Second)
+
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+			if err != nil {
+				return
+			}
+
+			content, _ := ioutil.ReadAll(got.Content)
+			got.Content.Close()
+			if string(content) != tt.expected || got.FileName != "a.txt" {
+				t.Errorf("[TestCase '%s'] Result: '%s' '%s' | Expected: '%v'", tt.name, got.FileName, content, tt.expected)
+			}
+		})
+	}
+}
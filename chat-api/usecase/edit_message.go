@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	// Input port
+	EditMessageUseCase interface {
+		Execute(context.Context, EditMessageInput) (EditMessageOutput, error)
+	}
+
+	// Input data
+	EditMessageInput struct {
+		ChannelId string `json:"channelId" validate:"required"`
+		MessageId string `json:"messageId" validate:"required"`
+		Message   string `json:"message" validate:"required"`
+		// Editor is the authenticated caller, Admin lets them change messages of anyone at any time
+		Editor string `json:"-" validate:"required"`
+		Admin  bool   `json:"-"`
+		IP     string `json:"-"`
+	}
+
+	// Output port
+	EditMessagePresenter interface {
+		Output(domain.Channel, domain.Message) EditMessageOutput
+	}
+
+	// Output data
+	EditMessageOutput struct {
+		ChannelId string        `json:"channelId"`
+		Message   MessageOutput `json:"message"`
+	}
+
+	editMessageInteractor struct {
+		repo       domain.ChannelRepository
+		index      domain.SearchIndex
+		notifier   domain.ChannelNotifier
+		audit      domain.AuditLogger
+		presenter  EditMessagePresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewEditMessageInteractor(
+	repo domain.ChannelRepository,
+	index domain.SearchIndex,
+	notifier domain.ChannelNotifier,
+	audit domain.AuditLogger,
+	presenter EditMessagePresenter,
+	t time.Duration,
+) EditMessageUseCase {
+	return editMessageInteractor{
+		repo:       repo,
+		index:      index,
+		notifier:   notifier,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a editMessageInteractor) Execute(ctx context.Context, input EditMessageInput) (EditMessageOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	channel, message, err := changeableMessage(ctx, a.repo, input.ChannelId, input.MessageId, input.Admin)
+	if err != nil {
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	now := time.Now()
+	if err := message.CanBeChangedBy(input.Editor, input.Admin, now); err != nil {
+		a.record(ctx, input, domain.AuditOutcomeFailure)
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	if message, err = channel.EditMessage(message.Id, input.Message, now); err != nil {
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	if err := a.repo.UpdateMessages(ctx, channel); err != nil {
+		a.record(ctx, input, domain.AuditOutcomeFailure)
+		return a.presenter.Output(domain.Channel{}, domain.Message{}), err
+	}
+
+	a.record(ctx, input, domain.AuditOutcomeSuccess)
+	a.index.IndexChannel(ctx, channel)
+	a.notifier.Notify(ctx, domain.ChannelEvent{
+		Type:        domain.ChannelEventMessageEdited,
+		ChannelId:   channel.Id().Hex(),
+		UserEmail:   channel.UserEmail(),
+		MessageId:   message.Id.Hex(),
+		MessageFrom: message.MessageFrom,
+		Internal:    message.Internal,
+		Message:     message.Message,
+		Timestamp:   message.EditedAt,
+	})
+
+	return a.presenter.Output(channel, message), nil
+}
+
+func (a editMessageInteractor) record(ctx context.Context, input EditMessageInput, outcome string) {
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditMessageEdited,
+		Actor:     input.Editor,
+		Target:    input.ChannelId + "/" + input.MessageId,
+		IP:        input.IP,
+		Outcome:   outcome,
+		Timestamp: time.Now(),
+	})
+}
+
+// changeableMessage reads the channel and the message an edit or a deletion is
+// about, internal notes are not found by callers who cannot see them
+func changeableMessage(ctx context.Context, repo domain.ChannelRepository, channelId, messageId string, internal bool) (domain.Channel, domain.Message, error) {
+	channel, err := repo.GetChannelById(ctx, channelId)
+	if err != nil {
+		return domain.Channel{}, domain.Message{}, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(messageId)
+	if err != nil {
+		return domain.Channel{}, domain.Message{}, domain.ErrMessageNotFound
+	}
+
+	message, err := channel.Message(id)
+	if err != nil {
+		return domain.Channel{}, domain.Message{}, err
+	}
+	if message.Internal && !internal {
+		return domain.Channel{}, domain.Message{}, domain.ErrMessageNotFound
+	}
+	if message.IsDeleted {
+		return domain.Channel{}, domain.Message{}, domain.ErrMessageDeleted
+	}
+	return channel, message, nil
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockMessageRepo struct {
+	domain.ChannelRepository
+
+	channel   domain.Channel
+	updateErr error
+	updated   *domain.Channel
+}
+
+func (m mockMessageRepo) GetChannelById(_ context.Context, _ string) (domain.Channel, error) {
+	return m.channel, nil
+}
+
+func (m mockMessageRepo) UpdateMessages(_ context.Context, channel domain.Channel) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	*m.updated = channel
+	return nil
+}
+
+func (m mockMessageRepo) AddMessage(ctx context.Context, channel domain.Channel) error {
+	return m.UpdateMessages(ctx, channel)
+}
+
+func (m mockMessageRepo) UpdateReadMarkers(ctx context.Context, channel domain.Channel) error {
+	return m.UpdateMessages(ctx, channel)
+}
+
+type mockChannelNotifier struct {
+	events *[]domain.ChannelEvent
+}
+
+func (m mockChannelNotifier) Notify(_ context.Context, event domain.ChannelEvent) {
+	*m.events = append(*m.events, event)
+}
+
+type mockEditMessagePresenter struct{}
+
+func (m mockEditMessagePresenter) Output(channel domain.Channel, message domain.Message) EditMessageOutput {
+	return EditMessageOutput{ChannelId: channel.Id().Hex(), Message: MessageOutput{Message: message.Message}}
+}
+
+// messageChannel holds a message sent by user@email.com the given time ago
+func messageChannel(age time.Duration) (domain.Channel, primitive.ObjectID) {
+	channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.IN_PROGRESS, time.Now(), time.Now())
+	channel.AddMessage("user@email.com", "helo", time.Now().Add(-age))
+	return channel, channel.Messages()[0].Id
+}
+
+func TestEditMessageInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		age           time.Duration
+		editor        string
+		admin         bool
+		messageId     string
+		note          bool
+		updateErr     error
+		expectedError error
+		expectedAudit string
+	}{
+		{
+			name:          "author within the window",
+			age:           time.Minute,
+			editor:        "User@Email.com",
+			expectedAudit: domain.AuditOutcomeSuccess,
+		},
+		{
+			name:          "author after the window",
+			age:           domain.MessageEditWindow + time.Minute,
+			editor:        "user@email.com",
+			expectedError: domain.ErrMessageEditWindowClosed,
+			expectedAudit: domain.AuditOutcomeFailure,
+		},
+		{
+			name:          "someone else",
+			age:           time.Minute,
+			editor:        "other@email.com",
+			expectedError: domain.ErrNotMessageAuthor,
+			expectedAudit: domain.AuditOutcomeFailure,
+		},
+		{
+			name:          "admin after the window",
+			age:           24 * time.Hour,
+			editor:        "rep@email.com",
+			admin:         true,
+			expectedAudit: domain.AuditOutcomeSuccess,
+		},
+		{
+			name:          "unknown message",
+			age:           time.Minute,
+			editor:        "user@email.com",
+			messageId:     primitive.NewObjectID().Hex(),
+			expectedError: domain.ErrMessageNotFound,
+		},
+		{
+			name:          "malformed message id",
+			age:           time.Minute,
+			editor:        "user@email.com",
+			messageId:     "42",
+			expectedError: domain.ErrMessageNotFound,
+		},
+		{
+			name:          "internal note for the customer",
+			age:           time.Minute,
+			editor:        "user@email.com",
+			note:          true,
+			expectedError: domain.ErrMessageNotFound,
+		},
+		{
+			name:          "failed update",
+			age:           time.Minute,
+			editor:        "user@email.com",
+			updateErr:     errors.New("db down"),
+			expectedError: errors.New("db down"),
+			expectedAudit: domain.AuditOutcomeFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, id := messageChannel(tt.age)
+			if tt.note {
+				channel.AddNote("rep@email.com", "refund approved", time.Now())
+				id = channel.Messages()[1].Id
+			}
+
+			var (
+				updated  domain.Channel
+				indexed  []domain.Channel
+				audit    []domain.AuditEvent
+				notified []domain.ChannelEvent
+				uc       = NewEditMessageInteractor(
+					mockMessageRepo{channel: channel, updateErr: tt.updateErr, updated: &updated},
+					mockSearchIndex{indexed: &indexed},
+					mockChannelNotifier{events: &notified},
+					mockAuditLogger{events: &audit},
+					mockEditMessagePresenter{},
+					time.Second,
+				)
+				input = EditMessageInput{
+					ChannelId: channel.Id().Hex(),
+					MessageId: id.Hex(),
+					Message:   "hello",
+					Editor:    tt.editor,
+					Admin:     tt.admin,
+				}
+			)
+			if tt.messageId != "" {
+				input.MessageId = tt.messageId
+			}
+
+			got, err := uc.Execute(context.Background(), input)
+			if !reflect.DeepEqual(err, tt.expectedError) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			var outcomes []string
+			for _, event := range audit {
+				if event.Action != domain.AuditMessageEdited || event.Actor != tt.editor || event.Target != input.ChannelId+"/"+input.MessageId {
+					t.Errorf("[TestCase '%s'] Audit: '%v'", tt.name, event)
+				}
+				outcomes = append(outcomes, event.Outcome)
+			}
+			if tt.expectedAudit == "" && len(outcomes) > 0 || tt.expectedAudit != "" && !reflect.DeepEqual(outcomes, []string{tt.expectedAudit}) {
+				t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, outcomes, tt.expectedAudit)
+			}
+
+			if err != nil {
+				if len(indexed) != 0 || len(notified) != 0 {
+					t.Errorf("[TestCase '%s'] Indexed: '%v' | Notified: '%v' | Expected: none", tt.name, indexed, notified)
+				}
+				return
+			}
+
+			if got.Message.Message != "hello" {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got.Message.Message, "hello")
+			}
+			message := updated.Messages()[0]
+			if message.Message != "hello" || message.EditedAt.IsZero() || len(message.Edits) != 1 || message.Edits[0].Message != "helo" {
+				t.Errorf("[TestCase '%s'] Stored: '%+v' | Expected the edit with the previous text kept", tt.name, message)
+			}
+			if len(indexed) != 1 {
+				t.Errorf("[TestCase '%s'] Indexed: '%v' | Expected: '%v'", tt.name, len(indexed), 1)
+			}
+			expected := []domain.ChannelEvent{{
+				Type:        domain.ChannelEventMessageEdited,
+				ChannelId:   channel.Id().Hex(),
+				UserEmail:   "user@email.com",
+				MessageId:   id.Hex(),
+				MessageFrom: "user@email.com",
+				Message:     "hello",
+				Timestamp:   message.EditedAt,
+			}}
+			if !reflect.DeepEqual(notified, expected) {
+				t.Errorf("[TestCase '%s'] Notified: '%v' | Expected: '%v'", tt.name, notified, expected)
+			}
+		})
+	}
+}
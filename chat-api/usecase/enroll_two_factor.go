@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	EnrollTwoFactorUseCase interface {
+		Execute(context.Context, EnrollTwoFactorInput) (EnrollTwoFactorOutput, error)
+	}
+
+	// Input data
+	EnrollTwoFactorInput struct {
+		Email string `json:"-" validate:"required"`
+	}
+
+	// Output port
+	EnrollTwoFactorPresenter interface {
+		Output(string, string) EnrollTwoFactorOutput
+	}
+
+	// Output data
+	EnrollTwoFactorOutput struct {
+		Secret          string `json:"secret"`
+		ProvisioningURI string `json:"provisioningUri"`
+	}
+
+	enrollTwoFactorInteractor struct {
+		repo       domain.UserRepository
+		twoFactor  domain.TwoFactorService
+		presenter  EnrollTwoFactorPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewEnrollTwoFactorInteractor(
+	repo domain.UserRepository,
+	twoFactor domain.TwoFactorService,
+	presenter EnrollTwoFactorPresenter,
+	t time.Duration,
+) EnrollTwoFactorUseCase {
+	return enrollTwoFactorInteractor{
+		repo:       repo,
+		twoFactor:  twoFactor,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a enrollTwoFactorInteractor) Execute(ctx context.Context, input EnrollTwoFactorInput) (EnrollTwoFactorOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	user, err := a.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		return a.presenter.Output("", ""), err
+	}
+
+	if user.TwoFactorEnabled() {
+		return a.presenter.Output("", ""), domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := a.twoFactor.GenerateSecret(ctx)
+	if err != nil {
+		return a.presenter.Output("", ""), err
+	}
+
+	encryptedSecret, err := a.twoFactor.EncryptSecret(ctx, secret)
+	if err != nil {
+		return a.presenter.Output("", ""), err
+	}
+
+	// The secret stays pending until a first code is confirmed through activation
+	user.UpdateTwoFactor(encryptedSecret, false, nil)
+	if err := a.repo.UpdateTwoFactor(ctx, user); err != nil {
+		return a.presenter.Output("", ""), err
+	}
+
+	return a.presenter.Output(secret, a.twoFactor.ProvisioningURI(ctx, secret, user.Email())), nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	ExportAuditEventsUseCase interface {
+		Execute(context.Context, ExportAuditEventsInput) (ExportAuditEventsOutput, error)
+	}
+
+	// Input data
+	ExportAuditEventsInput struct {
+		AuditQueryInput
+		ExportedBy string `json:"-"`
+		IP         string `json:"-"`
+	}
+
+	// Output port
+	ExportAuditEventsPresenter interface {
+		Output([]domain.AuditEvent, int) ExportAuditEventsOutput
+	}
+
+	// Output data
+	ExportAuditEventsOutput struct {
+		// TotalCount may be above the number of exported events when the export was capped
+		TotalCount int                `json:"totalCount"`
+		Data       []AuditEventOutput `json:"data"`
+	}
+
+	exportAuditEventsInteractor struct {
+		repo       domain.AuditEventRepository
+		audit      domain.AuditLogger
+		presenter  ExportAuditEventsPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewExportAuditEventsInteractor(
+	repo domain.AuditEventRepository,
+	audit domain.AuditLogger,
+	presenter ExportAuditEventsPresenter,
+	t time.Duration,
+) ExportAuditEventsUseCase {
+	return exportAuditEventsInteractor{
+		repo:       repo,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (e exportAuditEventsInteractor) Execute(ctx context.Context, input ExportAuditEventsInput) (ExportAuditEventsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.ctxTimeout)
+	defer cancel()
+
+	query, err := input.auditQuery()
+	if err != nil {
+		return e.presenter.Output([]domain.AuditEvent{}, 0), err
+	}
+
+	count, err := e.repo.GetAuditEventsCount(ctx, query)
+	if err != nil {
+		return e.presenter.Output([]domain.AuditEvent{}, 0), err
+	}
+
+	events, err := e.repo.GetAuditEvents(ctx, query, 0, domain.MaxAuditExportRows)
+	if err != nil {
+		return e.presenter.Output([]domain.AuditEvent{}, 0), err
+	}
+
+	// Exporting the trail is itself audited, the event lands after the
+	// export so it never shows up in its own file
+	e.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditEventsExported,
+		Actor:     input.ExportedBy,
+		Target:    "audit_events",
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return e.presenter.Output(events, int(count)), nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetAPIKeysUseCase interface {
+		Execute(context.Context) (GetAPIKeysOutput, error)
+	}
+
+	// Output port
+	GetAPIKeysPresenter interface {
+		Output([]domain.APIKey) GetAPIKeysOutput
+	}
+
+	APIKeyOutput struct {
+		Id        string     `json:"id"`
+		Name      string     `json:"name"`
+		Prefix    string     `json:"prefix"`
+		Scopes    []string   `json:"scopes"`
+		CreatedBy string     `json:"createdBy"`
+		CreatedAt time.Time  `json:"createdAt"`
+		RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	}
+
+	// Output data
+	GetAPIKeysOutput struct {
+		Count int            `json:"count"`
+		Data  []APIKeyOutput `json:"data"`
+	}
+
+	getAPIKeysInteractor struct {
+		repo       domain.APIKeyRepository
+		presenter  GetAPIKeysPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetAPIKeysInteractor(
+	repo domain.APIKeyRepository,
+	presenter GetAPIKeysPresenter,
+	t time.Duration,
+) GetAPIKeysUseCase {
+	return getAPIKeysInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (g getAPIKeysInteractor) Execute(ctx context.Context) (GetAPIKeysOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ctxTimeout)
+	defer cancel()
+
+	keys, err := g.repo.GetAPIKeys(ctx)
+	if err != nil {
+		return g.presenter.Output([]domain.APIKey{}), err
+	}
+
+	return g.presenter.Output(keys), nil
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetAttachmentURLUseCase interface {
+		Execute(context.Context, GetAttachmentURLInput) (AttachmentURLOutput, error)
+	}
+
+	// Input data
+	GetAttachmentURLInput struct {
+		ChannelId    string `validate:"required"`
+		AttachmentId string `validate:"required"`
+		RequestedBy  string `validate:"required"`
+		// Internal lets reps get the attachments of internal notes
+		Internal bool
+	}
+
+	// Output port
+	GetAttachmentURLPresenter interface {
+		Output(attachment domain.Attachment, expiresAt time.Time, signature string) AttachmentURLOutput
+	}
+
+	// Output data
+	AttachmentURLOutput struct {
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+
+	getAttachmentURLInteractor struct {
+		channels    domain.ChannelRepository
+		attachments domain.AttachmentRepository
+		signer      domain.AttachmentURLSigner
+		presenter   GetAttachmentURLPresenter
+		ctxTimeout  time.Duration
+	}
+)
+
+func NewGetAttachmentURLInteractor(
+	channels domain.ChannelRepository,
+	attachments domain.AttachmentRepository,
+	signer domain.AttachmentURLSigner,
+	presenter GetAttachmentURLPresenter,
+	t time.Duration,
+) GetAttachmentURLUseCase {
+	return getAttachmentURLInteractor{
+		channels:    channels,
+		attachments: attachments,
+		signer:      signer,
+		presenter:   presenter,
+		ctxTimeout:  t,
+	}
+}
+
+// Execute signs a download link valid for domain.AttachmentURLTTL, only the
+// participants of the channel of the attachment get one
+func (a getAttachmentURLInteractor) Execute(ctx context.Context, input GetAttachmentURLInput) (AttachmentURLOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	attachment, err := a.attachments.GetAttachmentById(ctx, input.AttachmentId)
+	if err != nil {
+		return a.presenter.Output(domain.Attachment{}, time.Time{}, ""), err
+	}
+	if attachment.ChannelId() != input.ChannelId {
+		return a.presenter.Output(domain.Attachment{}, time.Time{}, ""), domain.ErrAttachmentNotFound
+	}
+
+	channel, err := a.channels.GetChannelById(ctx, input.ChannelId)
+	if err != nil {
+		return a.presenter.Output(domain.Attachment{}, time.Time{}, ""), err
+	}
+	if !channel.IsParticipant(input.RequestedBy) {
+		return a.presenter.Output(domain.Attachment{}, time.Time{}, ""), domain.ErrNotChannelParticipant
+	}
+	if channel.HidesAttachment(attachment.Id(), input.Internal) {
+		return a.presenter.Output(domain.Attachment{}, time.Time{}, ""), domain.ErrAttachmentNotFound
+	}
+
+	expiresAt := time.Now().Add(domain.AttachmentURLTTL).Truncate(time.Second)
+	signature := a.signer.Sign(ctx, attachment.Id().Hex(), expiresAt)
+
+	return a.presenter.Output(attachment, expiresAt, signature), nil
+}
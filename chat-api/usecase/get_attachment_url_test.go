@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mockAttachmentURLSigner signs with the id and the expiry, links expire like real ones
+type mockAttachmentURLSigner struct{}
+
+func (m mockAttachmentURLSigner) Sign(_ context.Context, attachmentId string, expiresAt time.Time) string {
+	return attachmentId + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+}
+
+func (m mockAttachmentURLSigner) Verify(ctx context.Context, attachmentId string, expiresAt time.Time, signature string) error {
+	if signature != m.Sign(ctx, attachmentId, expiresAt) || !time.Now().Before(expiresAt) {
+		return domain.ErrInvalidAttachmentURL
+	}
+	return nil
+}
+
+type mockGetAttachmentURLPresenter struct{}
+
+func (m mockGetAttachmentURLPresenter) Output(attachment domain.Attachment, expiresAt time.Time, signature string) AttachmentURLOutput {
+	if signature == "" {
+		return AttachmentURLOutput{}
+	}
+	return AttachmentURLOutput{URL: attachment.Id().Hex() + "?signature=" + signature, ExpiresAt: expiresAt}
+}
+
+func TestGetAttachmentURLInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		channel    = attachmentChannel()
+		attachment = domain.NewAttachment(primitive.NewObjectID(), channel.Id().Hex(), "user@email.com", "a.png", "image/png", 10, time.Now())
+		other      = domain.NewAttachment(primitive.NewObjectID(), primitive.NewObjectID().Hex(), "user@email.com", "b.png", "image/png", 10, time.Now())
+		hidden     = domain.NewAttachment(primitive.NewObjectID(), channel.Id().Hex(), "user@email.com", "c.png", "image/png", 10, time.Now())
+		noted      = domain.NewAttachment(primitive.NewObjectID(), channel.Id().Hex(), "rep@email.com", "d.pdf", "application/pdf", 10, time.Now())
+		repo       = mockAttachmentRepo{attachments: map[string]domain.Attachment{
+			attachment.Id().Hex(): attachment,
+			other.Id().Hex():      other,
+			hidden.Id().Hex():     hidden,
+			noted.Id().Hex():      noted,
+		}}
+	)
+	channel.AddMessage("user@email.com", "", time.Now(), hidden.MessageAttachment())
+	if _, err := channel.DeleteMessage(channel.Messages()[0].Id, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	channel.AddNote("rep@email.com", "", time.Now(), noted.MessageAttachment())
+
+	tests := []struct {
+		name          string
+		attachmentId  string
+		requestedBy   string
+		internal      bool
+		expectedError error
+	}{
+		{
+			name:         "customer",
+			attachmentId: attachment.Id().Hex(),
+			requestedBy:  "user@email.com",
+		},
+		{
+			name:         "rep",
+			attachmentId: attachment.Id().Hex(),
+			requestedBy:  "Rep@Email.com",
+		},
+		{
+			name:          "someone else",
+			attachmentId:  attachment.Id().Hex(),
+			requestedBy:   "other@email.com",
+			expectedError: domain.ErrNotChannelParticipant,
+		},
+		{
+			name:          "attachment of another channel",
+			attachmentId:  other.Id().Hex(),
+			requestedBy:   "user@email.com",
+			expectedError: domain.ErrAttachmentNotFound,
+		},
+		{
+			name:          "attachment of a deleted message",
+			attachmentId:  hidden.Id().Hex(),
+			requestedBy:   "user@email.com",
+			expectedError: domain.ErrAttachmentNotFound,
+		},
+		{
+			name:          "attachment of an internal note for the customer",
+			attachmentId:  noted.Id().Hex(),
+			requestedBy:   "user@email.com",
+			expectedError: domain.ErrAttachmentNotFound,
+		},
+		{
+			name:         "attachment of an internal note for a rep",
+			attachmentId: noted.Id().Hex(),
+			requestedBy:  "rep@email.com",
+			internal:     true,
+		},
+		{
+			name:          "unknown attachment",
+			attachmentId:  primitive.NewObjectID().Hex(),
+			requestedBy:   "user@email.com",
+			expectedError: domain.ErrAttachmentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewGetAttachmentURLInteractor(
+				mockMessageRepo{channel: channel},
+				repo,
+				mockAttachmentURLSigner{},
+				mockGetAttachmentURLPresenter{},
+				time.Second,
+			)
+
+			got, err := uc.Execute(context.Background(), GetAttachmentURLInput{
+				ChannelId:    channel.Id().Hex(),
+				AttachmentId: tt.attachmentId,
+				RequestedBy:  tt.requestedBy,
+				Internal:     tt.internal,
+			})
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+			if err != nil {
+				if got != (AttachmentURLOutput{}) {
+					t.Errorf("[TestCase '%s'] Result: '%v' | Expected: no link", tt.name, got)
+				}
+				return
+			}
+
+			if ttl := time.Until(got.ExpiresAt); ttl <= 0 || ttl > domain.AttachmentURLTTL {
+				t.Errorf("[TestCase '%s'] Expires in: '%v' | Expected at most: '%v'", tt.name, ttl, domain.AttachmentURLTTL)
+			}
+			if got.URL == "" {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected a signed link", tt.name, got)
+			}
+		})
+	}
+}
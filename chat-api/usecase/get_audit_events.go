@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetAuditEventsUseCase interface {
+		Execute(context.Context, GetAuditEventsInput) (GetAuditEventsOutput, error)
+	}
+
+	// AuditQueryInput filters audit events, From is inclusive and To exclusive
+	AuditQueryInput struct {
+		Actor  string    `json:"actor"`
+		Target string    `json:"target"`
+		From   time.Time `json:"from"`
+		To     time.Time `json:"to"`
+	}
+
+	// Input data
+	GetAuditEventsInput struct {
+		AuditQueryInput
+		Page  int `json:"page" validate:"min=1"`
+		Limit int `json:"limit" validate:"min=1,max=100"`
+	}
+
+	// Output port
+	GetAuditEventsPresenter interface {
+		Output([]domain.AuditEvent, int, int, int) GetAuditEventsOutput
+	}
+
+	AuditEventOutput struct {
+		Action    string    `json:"action"`
+		Actor     string    `json:"actor"`
+		Target    string    `json:"target"`
+		IP        string    `json:"ip"`
+		Outcome   string    `json:"outcome"`
+		Timestamp time.Time `json:"timestamp"`
+	}
+
+	// Output data
+	GetAuditEventsOutput struct {
+		Page       int                `json:"page"`
+		Count      int                `json:"count"`
+		Limit      int                `json:"limit"`
+		TotalCount int                `json:"totalCount"`
+		Data       []AuditEventOutput `json:"data"`
+	}
+
+	getAuditEventsInteractor struct {
+		repo       domain.AuditEventRepository
+		presenter  GetAuditEventsPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetAuditEventsInteractor(
+	repo domain.AuditEventRepository,
+	presenter GetAuditEventsPresenter,
+	t time.Duration,
+) GetAuditEventsUseCase {
+	return getAuditEventsInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (g getAuditEventsInteractor) Execute(ctx context.Context, input GetAuditEventsInput) (GetAuditEventsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ctxTimeout)
+	defer cancel()
+
+	query, err := input.auditQuery()
+	if err != nil {
+		return g.presenter.Output([]domain.AuditEvent{}, 0, 0, 0), err
+	}
+
+	events, err := g.repo.GetAuditEvents(ctx, query, (input.Page-1)*input.Limit, input.Limit)
+	if err != nil {
+		return g.presenter.Output([]domain.AuditEvent{}, 0, 0, 0), err
+	}
+
+	count, err := g.repo.GetAuditEventsCount(ctx, query)
+	if err != nil {
+		return g.presenter.Output([]domain.AuditEvent{}, 0, 0, 0), err
+	}
+
+	return g.presenter.Output(events, input.Page, input.Limit, int(count)), nil
+}
+
+func (i AuditQueryInput) auditQuery() (domain.AuditQuery, error) {
+	if !i.From.IsZero() && !i.To.IsZero() && !i.To.After(i.From) {
+		return domain.AuditQuery{}, domain.ErrInvalidTimeRange
+	}
+
+	return domain.AuditQuery{
+		Actor:  i.Actor,
+		Target: i.Target,
+		From:   i.From,
+		To:     i.To,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockAuditEventRepo struct {
+	domain.AuditEventRepository
+
+	events []domain.AuditEvent
+	count  int64
+	query  *domain.AuditQuery
+	limit  *int
+}
+
+func (m mockAuditEventRepo) GetAuditEvents(_ context.Context, query domain.AuditQuery, _, limit int) ([]domain.AuditEvent, error) {
+	if m.query != nil {
+		*m.query = query
+	}
+	if m.limit != nil {
+		*m.limit = limit
+	}
+	return m.events, nil
+}
+
+func (m mockAuditEventRepo) GetAuditEventsCount(_ context.Context, _ domain.AuditQuery) (int64, error) {
+	return m.count, nil
+}
+
+type mockGetAuditEventsPresenter struct{}
+
+func (m mockGetAuditEventsPresenter) Output(events []domain.AuditEvent, page, limit, totalCount int) GetAuditEventsOutput {
+	return GetAuditEventsOutput{Page: page, Count: len(events), Limit: limit, TotalCount: totalCount}
+}
+
+type mockExportAuditEventsPresenter struct{}
+
+func (m mockExportAuditEventsPresenter) Output(events []domain.AuditEvent, totalCount int) ExportAuditEventsOutput {
+	return ExportAuditEventsOutput{TotalCount: totalCount, Data: make([]AuditEventOutput, len(events))}
+}
+
+func TestGetAuditEventsInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		from   = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		to     = from.Add(24 * time.Hour)
+		events = []domain.AuditEvent{{Action: domain.AuditLogin, Actor: "admin@email.com"}}
+	)
+
+	tests := []struct {
+		name          string
+		input         GetAuditEventsInput
+		expected      GetAuditEventsOutput
+		expectedQuery domain.AuditQuery
+		expectedError error
+	}{
+		{
+			name: "filters by actor, target and time range",
+			input: GetAuditEventsInput{
+				AuditQueryInput: AuditQueryInput{Actor: "admin@email.com", Target: "user@email.com", From: from, To: to},
+				Page:            2,
+				Limit:           10,
+			},
+			expected:      GetAuditEventsOutput{Page: 2, Count: 1, Limit: 10, TotalCount: 11},
+			expectedQuery: domain.AuditQuery{Actor: "admin@email.com", Target: "user@email.com", From: from, To: to},
+		},
+		{
+			name: "open ended range",
+			input: GetAuditEventsInput{
+				AuditQueryInput: AuditQueryInput{From: from},
+				Page:            1,
+				Limit:           10,
+			},
+			expected:      GetAuditEventsOutput{Page: 1, Count: 1, Limit: 10, TotalCount: 11},
+			expectedQuery: domain.AuditQuery{From: from},
+		},
+		{
+			name: "range ending before it starts refused",
+			input: GetAuditEventsInput{
+				AuditQueryInput: AuditQueryInput{From: to, To: from},
+				Page:            1,
+				Limit:           10,
+			},
+			expectedError: domain.ErrInvalidTimeRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				query domain.AuditQuery
+				uc    = NewGetAuditEventsInteractor(
+					mockAuditEventRepo{events: events, count: 11, query: &query},
+					mockGetAuditEventsPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+
+			if query != tt.expectedQuery {
+				t.Errorf("[TestCase '%s'] Query: '%v' | Expected: '%v'", tt.name, query, tt.expectedQuery)
+			}
+		})
+	}
+}
+
+func TestExportAuditEventsInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		events []domain.AuditEvent
+		limit  int
+		uc     = NewExportAuditEventsInteractor(
+			mockAuditEventRepo{events: make([]domain.AuditEvent, 3), count: 3, limit: &limit},
+			mockAuditLogger{events: &events},
+			mockExportAuditEventsPresenter{},
+			time.Second,
+		)
+	)
+
+	got, err := uc.Execute(context.Background(), ExportAuditEventsInput{ExportedBy: "admin@email.com", IP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("[TestCase 'export'] Result: '%v' | ExpectedError: '%v'", err, nil)
+	}
+
+	if got.TotalCount != 3 || len(got.Data) != 3 {
+		t.Errorf("[TestCase 'export'] Result: '%v' | Expected: '%v' events", got, 3)
+	}
+
+	if limit != domain.MaxAuditExportRows {
+		t.Errorf("[TestCase 'export'] Limit: '%v' | Expected: '%v'", limit, domain.MaxAuditExportRows)
+	}
+
+	if len(events) != 1 || events[0].Action != domain.AuditEventsExported || events[0].Actor != "admin@email.com" || events[0].IP != "10.0.0.1" {
+		t.Errorf("[TestCase 'export'] Audit: '%v' | Expected: '%v' by '%v'", events, domain.AuditEventsExported, "admin@email.com")
+	}
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetCannedResponseUseCase interface {
+		Execute(context.Context, GetCannedResponseInput) (CannedResponseOutput, error)
+	}
+
+	// Input data
+	GetCannedResponseInput struct {
+		Id  string `json:"id" validate:"required"`
+		Rep string `json:"-" validate:"required"`
+	}
+
+	// Output port
+	GetCannedResponsePresenter interface {
+		Output(domain.CannedResponse) CannedResponseOutput
+	}
+
+	getCannedResponseInteractor struct {
+		repo       domain.CannedResponseRepository
+		presenter  GetCannedResponsePresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetCannedResponseInteractor(
+	repo domain.CannedResponseRepository,
+	presenter GetCannedResponsePresenter,
+	t time.Duration,
+) GetCannedResponseUseCase {
+	return getCannedResponseInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (g getCannedResponseInteractor) Execute(ctx context.Context, input GetCannedResponseInput) (CannedResponseOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ctxTimeout)
+	defer cancel()
+
+	response, err := visibleCannedResponse(ctx, g.repo, input.Id, input.Rep)
+	if err != nil {
+		return g.presenter.Output(domain.CannedResponse{}), err
+	}
+
+	return g.presenter.Output(response), nil
+}
+
+// visibleCannedResponse fetches a response the rep can see, the personal
+// responses of other reps are reported missing rather than forbidden
+func visibleCannedResponse(ctx context.Context, repo domain.CannedResponseRepository, id, rep string) (domain.CannedResponse, error) {
+	response, err := repo.GetCannedResponseById(ctx, id)
+	if err != nil {
+		return domain.CannedResponse{}, err
+	}
+	if !response.VisibleTo(rep) {
+		return domain.CannedResponse{}, domain.ErrCannedResponseNotFound
+	}
+	return response, nil
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetCannedResponsesUseCase interface {
+		Execute(context.Context, GetCannedResponsesInput) (GetCannedResponsesOutput, error)
+	}
+
+	// GetCannedResponsesInput lists the responses the rep can insert, Shortcut is
+	// a prefix so the composer can suggest responses while the rep types
+	GetCannedResponsesInput struct {
+		Shortcut string `json:"shortcut"`
+		Tag      string `json:"tag"`
+		Rep      string `json:"-" validate:"required"`
+	}
+
+	// Output port
+	GetCannedResponsesPresenter interface {
+		Output([]domain.CannedResponse) GetCannedResponsesOutput
+	}
+
+	// MacroFields are the channel changes a canned response makes when inserted
+	MacroFields struct {
+		Status     string   `json:"status,omitempty" validate:"omitempty,oneof=INACTIVE ACTIVE IN_PROGRESS COMPLETE"`
+		AddTags    []string `json:"addTags,omitempty" validate:"omitempty,max=20,dive,max=64"`
+		RemoveTags []string `json:"removeTags,omitempty" validate:"omitempty,max=20,dive,max=64"`
+	}
+
+	CannedResponseOutput struct {
+		Id        string      `json:"id"`
+		Shortcut  string      `json:"shortcut"`
+		Title     string      `json:"title"`
+		Body      string      `json:"body"`
+		Tags      []string    `json:"tags"`
+		Owner     string      `json:"owner"`
+		Shared    bool        `json:"shared"`
+		Macro     MacroFields `json:"macro"`
+		CreatedAt time.Time   `json:"createdAt"`
+		UpdatedAt time.Time   `json:"updatedAt"`
+	}
+
+	// Output data
+	GetCannedResponsesOutput struct {
+		Count int                    `json:"count"`
+		Data  []CannedResponseOutput `json:"data"`
+	}
+
+	getCannedResponsesInteractor struct {
+		repo       domain.CannedResponseRepository
+		presenter  GetCannedResponsesPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetCannedResponsesInteractor(
+	repo domain.CannedResponseRepository,
+	presenter GetCannedResponsesPresenter,
+	t time.Duration,
+) GetCannedResponsesUseCase {
+	return getCannedResponsesInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (g getCannedResponsesInteractor) Execute(ctx context.Context, input GetCannedResponsesInput) (GetCannedResponsesOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ctxTimeout)
+	defer cancel()
+
+	responses, err := g.repo.GetCannedResponses(ctx, domain.CannedResponseQuery{
+		VisibleTo:      input.Rep,
+		ShortcutPrefix: input.Shortcut,
+		Tag:            input.Tag,
+	})
+	if err != nil {
+		return g.presenter.Output([]domain.CannedResponse{}), err
+	}
+
+	return g.presenter.Output(responses), nil
+}
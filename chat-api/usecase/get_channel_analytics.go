@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+// defaultAnalyticsDays is the range of a series without a From
+const defaultAnalyticsDays = 30
+
+type (
+	// Input port
+	GetChannelAnalyticsUseCase interface {
+		Execute(context.Context, AnalyticsInput) (GetChannelAnalyticsOutput, error)
+	}
+
+	// AnalyticsInput buckets what happened from the inclusive From to the exclusive
+	// To by hour or day, day by default. To defaults to now and From to 30 days
+	// before To. Buckets start in TimeZone, the one of the business hours of the
+	// organization by default. Tag keeps the channels with the tag
+	AnalyticsInput struct {
+		Interval string    `json:"interval" validate:"omitempty,oneof=hour day"`
+		From     time.Time `json:"from"`
+		To       time.Time `json:"to"`
+		TimeZone string    `json:"timeZone"`
+		Tag      string    `json:"tag"`
+	}
+
+	// Output port
+	GetChannelAnalyticsPresenter interface {
+		Output(domain.AnalyticsQuery, []domain.ChannelBucket, map[string]int) GetChannelAnalyticsOutput
+	}
+
+	// ChannelAnalyticsBucketOutput leaves a median out when no clock stopped in the bucket
+	ChannelAnalyticsBucketOutput struct {
+		Start                      time.Time `json:"start"`
+		Opened                     int       `json:"opened"`
+		Closed                     int       `json:"closed"`
+		MedianFirstResponseSeconds *float64  `json:"medianFirstResponseSeconds"`
+		MedianResolutionSeconds    *float64  `json:"medianResolutionSeconds"`
+		MessagesPerChannel         float64   `json:"messagesPerChannel"`
+	}
+
+	// Output data, Backlog counts the open channels by status right now
+	GetChannelAnalyticsOutput struct {
+		Interval string                         `json:"interval"`
+		TimeZone string                         `json:"timeZone"`
+		From     time.Time                      `json:"from"`
+		To       time.Time                      `json:"to"`
+		Series   []ChannelAnalyticsBucketOutput `json:"series"`
+		Backlog  map[string]int                 `json:"backlog"`
+	}
+
+	getChannelAnalyticsInteractor struct {
+		repo       domain.AnalyticsRepository
+		orgRepo    domain.OrganizationRepository
+		presenter  GetChannelAnalyticsPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetChannelAnalyticsInteractor(
+	repo domain.AnalyticsRepository,
+	orgRepo domain.OrganizationRepository,
+	presenter GetChannelAnalyticsPresenter,
+	t time.Duration,
+) GetChannelAnalyticsUseCase {
+	return getChannelAnalyticsInteractor{
+		repo:       repo,
+		orgRepo:    orgRepo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (g getChannelAnalyticsInteractor) Execute(ctx context.Context, input AnalyticsInput) (GetChannelAnalyticsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ctxTimeout)
+	defer cancel()
+
+	query, err := input.analyticsQuery(ctx, g.orgRepo, time.Now())
+	if err != nil {
+		return g.presenter.Output(domain.AnalyticsQuery{}, nil, nil), err
+	}
+
+	buckets, err := g.repo.GetChannelBuckets(ctx, query)
+	if err != nil {
+		return g.presenter.Output(domain.AnalyticsQuery{}, nil, nil), err
+	}
+
+	backlog, err := g.repo.GetBacklog(ctx, query.Tag)
+	if err != nil {
+		return g.presenter.Output(domain.AnalyticsQuery{}, nil, nil), err
+	}
+
+	return g.presenter.Output(query, buckets, backlog), nil
+}
+
+func (i AnalyticsInput) analyticsQuery(ctx context.Context, orgRepo domain.OrganizationRepository, now time.Time) (domain.AnalyticsQuery, error) {
+	interval := i.Interval
+	if interval == "" {
+		interval = domain.AnalyticsByDay
+	}
+
+	timeZone := i.TimeZone
+	if timeZone == "" {
+		organization, err := currentOrganization(ctx, orgRepo)
+		if err != nil {
+			return domain.AnalyticsQuery{}, err
+		}
+		timeZone = organization.Settings().BusinessHours.TimeZone
+	}
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	// The empty name and Local would read the time zone of the server
+	location, err := time.LoadLocation(timeZone)
+	if err != nil || timeZone == "Local" {
+		return domain.AnalyticsQuery{}, domain.ErrUnknownTimeZone
+	}
+
+	to, from := i.To, i.From
+	if to.IsZero() {
+		to = reportEnd(now)
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -defaultAnalyticsDays)
+	}
+	if !to.After(from) {
+		return domain.AnalyticsQuery{}, domain.ErrInvalidTimeRange
+	}
+	if to.Sub(from) > domain.MaxSatisfactionReportDays*24*time.Hour {
+		return domain.AnalyticsQuery{}, domain.ErrReportRangeTooLong
+	}
+	if interval == domain.AnalyticsByHour && to.Sub(from) > domain.MaxHourlyAnalyticsDays*24*time.Hour {
+		return domain.AnalyticsQuery{}, domain.ErrHourlyRangeTooLong
+	}
+
+	query := domain.AnalyticsQuery{
+		From:     from.In(location),
+		To:       to.In(location),
+		Interval: interval,
+		Location: location,
+	}
+	if tags := domain.NormalizeTags([]string{i.Tag}); len(tags) > 0 {
+		query.Tag = tags[0]
+	}
+	return query, nil
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockAnalyticsRepo struct {
+	domain.AnalyticsRepository
+
+	query *domain.AnalyticsQuery
+	tag   *string
+}
+
+func (m mockAnalyticsRepo) GetChannelBuckets(_ context.Context, query domain.AnalyticsQuery) ([]domain.ChannelBucket, error) {
+	*m.query = query
+	return []domain.ChannelBucket{}, nil
+}
+
+func (m mockAnalyticsRepo) GetBacklog(_ context.Context, tag string) (map[string]int, error) {
+	*m.tag = tag
+	return map[string]int{}, nil
+}
+
+type mockGetChannelAnalyticsPresenter struct{}
+
+func (m mockGetChannelAnalyticsPresenter) Output(query domain.AnalyticsQuery, _ []domain.ChannelBucket, _ map[string]int) GetChannelAnalyticsOutput {
+	return GetChannelAnalyticsOutput{Interval: query.Interval, From: query.From, To: query.To}
+}
+
+func TestGetChannelAnalyticsInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("[TestCase 'load time zone'] Result: '%v' | Expected: '%v'", err, nil)
+	}
+
+	var (
+		to           = time.Date(2021, 3, 31, 22, 0, 0, 0, time.UTC)
+		organization = domain.NewOrganization("acme", "Acme", []string{"support.acme.com"}, time.Now(), time.Now())
+	)
+	organization.UpdateSettings(domain.OrganizationSettings{BusinessHours: domain.BusinessHours{TimeZone: "Europe/Paris"}}, time.Now())
+
+	tests := []struct {
+		name          string
+		tenant        string
+		input         AnalyticsInput
+		expected      domain.AnalyticsQuery
+		expectedError error
+	}{
+		{
+			name:   "time zone of the business hours",
+			tenant: "acme",
+			input:  AnalyticsInput{To: to, Tag: " Billing "},
+			expected: domain.AnalyticsQuery{
+				From:     to.AddDate(0, 0, -30).In(paris),
+				To:       to.In(paris),
+				Interval: domain.AnalyticsByDay,
+				Location: paris,
+				Tag:      "billing",
+			},
+		},
+		{
+			name:   "time zone of the query",
+			tenant: "acme",
+			input:  AnalyticsInput{Interval: domain.AnalyticsByHour, From: to.Add(-time.Hour), To: to, TimeZone: "UTC"},
+			expected: domain.AnalyticsQuery{
+				From:     to.Add(-time.Hour),
+				To:       to,
+				Interval: domain.AnalyticsByHour,
+				Location: time.UTC,
+			},
+		},
+		{
+			name:   "default organization without business hours",
+			tenant: domain.DefaultTenantID,
+			input:  AnalyticsInput{From: to.AddDate(0, 0, -1), To: to},
+			expected: domain.AnalyticsQuery{
+				From:     to.AddDate(0, 0, -1),
+				To:       to,
+				Interval: domain.AnalyticsByDay,
+				Location: time.UTC,
+			},
+		},
+		{
+			name:          "unknown time zone",
+			tenant:        "acme",
+			input:         AnalyticsInput{To: to, TimeZone: "Mars/Olympus"},
+			expectedError: domain.ErrUnknownTimeZone,
+		},
+		{
+			name:          "time zone of the server",
+			tenant:        "acme",
+			input:         AnalyticsInput{To: to, TimeZone: "Local"},
+			expectedError: domain.ErrUnknownTimeZone,
+		},
+		{
+			name:          "range ending before it starts",
+			tenant:        "acme",
+			input:         AnalyticsInput{From: to, To: to.Add(-time.Hour)},
+			expectedError: domain.ErrInvalidTimeRange,
+		},
+		{
+			name:          "hourly series over a month",
+			tenant:        "acme",
+			input:         AnalyticsInput{Interval: domain.AnalyticsByHour, From: to.AddDate(0, 0, -32), To: to},
+			expectedError: domain.ErrHourlyRangeTooLong,
+		},
+		{
+			name:          "daily series over a year",
+			tenant:        "acme",
+			input:         AnalyticsInput{From: to.AddDate(-2, 0, 0), To: to},
+			expectedError: domain.ErrReportRangeTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				query domain.AnalyticsQuery
+				tag   string
+				orgs  = mockOrganizationRepo{organization: organization}
+			)
+			if tt.tenant == domain.DefaultTenantID {
+				orgs = mockOrganizationRepo{err: domain.ErrOrganizationNotFound}
+			}
+
+			uc := NewGetChannelAnalyticsInteractor(
+				mockAnalyticsRepo{query: &query, tag: &tag},
+				orgs,
+				mockGetChannelAnalyticsPresenter{},
+				time.Second,
+			)
+
+			_, err := uc.Execute(domain.WithTenant(context.Background(), tt.tenant), tt.input)
+			if err != tt.expectedError {
+				t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, tt.expectedError)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(query, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%+v' | Expected: '%+v'", tt.name, query, tt.expected)
+			}
+			if tag != tt.expected.Tag {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, tag, tt.expected.Tag)
+			}
+		})
+	}
+}
@@ -18,25 +18,47 @@ type (
 
 	GetChannelByIdInput struct {
 		Id string `json:"id" validate:"required"`
+		// Internal returns the internal notes and the SLA too, only reps see them
+		Internal bool `json:"-"`
 	}
 
+	// Message of a deleted message is a tombstone without text nor attachments
 	Message struct {
-		Message     string    `json:"message"`
-		MessageFrom string    `json:"messageFrom"`
-		Timestamp   time.Time `json:"timeStamp"`
+		Id          string                    `json:"id"`
+		Message     string                    `json:"message"`
+		MessageFrom string                    `json:"messageFrom"`
+		Deleted     bool                      `json:"deleted"`
+		Timestamp   time.Time                 `json:"timeStamp"`
+		EditedAt    *time.Time                `json:"editedAt,omitempty"`
+		Attachments []MessageAttachmentOutput `json:"attachments,omitempty"`
+		Internal    bool                      `json:"internal,omitempty"`
+	}
+
+	// ReadMarkerOutput tells that the participant read every message sent up to LastReadAt
+	ReadMarkerOutput struct {
+		Participant string    `json:"participant"`
+		LastReadAt  time.Time `json:"lastReadAt"`
 	}
 
 	GetChannelByIdOutput struct {
-		Id            string    `json:"id"`
-		UserEmail     string    `json:"userEmail"`
-		RepEmail      string    `json:"repEmail"`
-		CurrentStatus string    `json:"currentStatus"`
-		CreatedAt     time.Time `json:"createdAt"`
-		Messages      []Message `json:"messages"`
+		Id            string             `json:"id"`
+		UserEmail     string             `json:"userEmail"`
+		RepEmail      string             `json:"repEmail"`
+		CurrentStatus string             `json:"currentStatus"`
+		CreatedAt     time.Time          `json:"createdAt"`
+		Tags          []string           `json:"tags"`
+		Priority      string             `json:"priority"`
+		Attributes    map[string]string  `json:"attributes"`
+		Messages      []Message          `json:"messages"`
+		ReadMarkers   []ReadMarkerOutput `json:"readMarkers"`
+		// SurveyPending tells the customer the latest completion is still to be rated
+		SurveyPending bool       `json:"surveyPending"`
+		SLA           *SLAOutput `json:"sla,omitempty"`
 	}
 
 	getChannelByIdInteractor struct {
 		repo       domain.ChannelRepository
+		orgRepo    domain.OrganizationRepository
 		presenter  GetChannelByIdPresenter
 		ctxTimeout time.Duration
 	}
@@ -45,11 +67,13 @@ type (
 // NewFindUserByIdInteractor creates new finduserByIdInteractor with its dependencies
 func NewGetChannelByIdInteractor(
 	repo domain.ChannelRepository,
+	orgRepo domain.OrganizationRepository,
 	presenter GetChannelByIdPresenter,
 	t time.Duration,
 ) GetChannelByIdUseCase {
 	return getChannelByIdInteractor{
 		repo:       repo,
+		orgRepo:    orgRepo,
 		presenter:  presenter,
 		ctxTimeout: t,
 	}
@@ -64,6 +88,13 @@ func (a getChannelByIdInteractor) Execute(ctx context.Context, input GetChannelB
 	if err != nil {
 		return a.presenter.Output(domain.Channel{}), err
 	}
+	if !input.Internal {
+		return a.presenter.Output(channel.WithoutInternalNotes()), nil
+	}
 
-	return a.presenter.Output(channel), nil
+	channels := []domain.Channel{channel}
+	if err := trackSLA(ctx, a.orgRepo, channels, time.Now()); err != nil {
+		return a.presenter.Output(domain.Channel{}), err
+	}
+	return a.presenter.Output(channels[0]), nil
 }
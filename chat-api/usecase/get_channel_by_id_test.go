@@ -79,9 +79,9 @@ func TestGetChannelByIdInteractor_Execute(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		var uc = NewGetChannelByIdInteractor(tt.repository, tt.presenter, time.Second)
+		var uc = NewGetChannelByIdInteractor(tt.repository, mockOrganizationRepo{err: domain.ErrOrganizationNotFound}, tt.presenter, time.Second)
 
-		result, err := uc.Execute(context.Background(), GetChannelByIdInput{tt.args.Id})
+		result, err := uc.Execute(context.Background(), GetChannelByIdInput{Id: tt.args.Id})
 		if (err != nil) && (err.Error() != tt.expectedError) {
 			t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
 			return
@@ -92,3 +92,51 @@ func TestGetChannelByIdInteractor_Execute(t *testing.T) {
 		}
 	}
 }
+
+type mockInternalNotesPresenter struct {
+	channel *domain.Channel
+}
+
+func (m mockInternalNotesPresenter) Output(channel domain.Channel) GetChannelByIdOutput {
+	*m.channel = channel
+	return GetChannelByIdOutput{}
+}
+
+func TestGetChannelByIdInteractor_InternalNotes(t *testing.T) {
+	t.Parallel()
+
+	channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.IN_PROGRESS, time.Now(), time.Now())
+	channel.AddMessage("user@email.com", "my order is late", time.Now())
+	channel.AddNote("rep@email.com", "customer verified, refund approved", time.Now())
+	channel.AddMessage("rep@email.com", "your refund is on its way", time.Now())
+
+	for _, tt := range []struct {
+		name     string
+		internal bool
+		expected []string
+	}{
+		{name: "customer", expected: []string{"my order is late", "your refund is on its way"}},
+		{name: "rep", internal: true, expected: []string{"my order is late", "customer verified, refund approved", "your refund is on its way"}},
+	} {
+		var (
+			presented domain.Channel
+			uc        = NewGetChannelByIdInteractor(
+				mockGetChannelByIdRepo{result: channel},
+				mockOrganizationRepo{err: domain.ErrOrganizationNotFound},
+				mockInternalNotesPresenter{channel: &presented},
+				time.Second,
+			)
+		)
+
+		if _, err := uc.Execute(context.Background(), GetChannelByIdInput{Id: channel.Id().Hex(), Internal: tt.internal}); err != nil {
+			t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, nil)
+		}
+		var texts []string
+		for _, message := range presented.Messages() {
+			texts = append(texts, message.Message)
+		}
+		if !reflect.DeepEqual(texts, tt.expected) {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, texts, tt.expected)
+		}
+	}
+}
@@ -4,42 +4,78 @@ import (
 	"chat-api/domain"
 	"context"
 	"fmt"
-	"strconv"
 	"time"
-
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 type (
 	// Input port
 	GetChannelByQueryUseCase interface {
-		Execute(context.Context, interface{}, string, string) (GetChannelByQueryOutput, error)
+		Execute(context.Context, GetChannelByQueryInput) (GetChannelByQueryOutput, error)
+	}
+
+	// Input data, time ranges include From and exclude To. Tags and Attributes
+	// match channels that have all of them, Priorities any of them. A cursor from
+	// a previous response pages from its position instead of the page number
+	GetChannelByQueryInput struct {
+		UserEmail   string            `json:"userEmail"`
+		RepEmail    string            `json:"repEmail"`
+		Statuses    []string          `json:"statuses" validate:"dive,oneof=INACTIVE ACTIVE IN_PROGRESS COMPLETE"`
+		Unassigned  bool              `json:"unassigned"`
+		Tags        []string          `json:"tags" validate:"omitempty,max=20,dive,max=64"`
+		Priorities  []string          `json:"priorities" validate:"dive,oneof=LOW NORMAL HIGH URGENT"`
+		Attributes  map[string]string `json:"attributes" validate:"omitempty,max=20"`
+		CreatedFrom time.Time         `json:"createdFrom"`
+		CreatedTo   time.Time         `json:"createdTo"`
+		UpdatedFrom time.Time         `json:"updatedFrom"`
+		UpdatedTo   time.Time         `json:"updatedTo"`
+		Sort        string            `json:"sort" validate:"omitempty,oneof=createdAt updatedAt lastMessageAt priority"`
+		Order       string            `json:"order" validate:"omitempty,oneof=asc desc"`
+		Cursor      string            `json:"cursor"`
+		Page        int               `json:"page" validate:"min=1"`
+		Limit       int               `json:"limit" validate:"min=1,max=50"`
+		// Reader is the authenticated caller, unread counts are theirs and
+		// include internal notes when Internal. Only reps see the SLA
+		Reader   string `json:"-"`
+		Internal bool   `json:"-"`
 	}
 
+	// Output port
 	GetChannelByQueryPresenter interface {
-		Output([]domain.Channel, int, int, int, int) GetChannelByQueryOutput
+		Output([]domain.Channel, int, int, int, int, string, string) GetChannelByQueryOutput
 	}
 
 	ChannelByQueryOutput struct {
-		Id            string    `json:"id"`
-		UserEmail     string    `json:"userEmail"`
-		RepEmail      string    `json:"repEmail"`
-		CurrentStatus string    `json:"currentStatus"`
-		UserFullName  string    `json:"userFullName"`
-		CreatedAt     time.Time `json:"createdAt"`
+		Id            string            `json:"id"`
+		UserEmail     string            `json:"userEmail"`
+		RepEmail      string            `json:"repEmail"`
+		CurrentStatus string            `json:"currentStatus"`
+		UserFullName  string            `json:"userFullName"`
+		CreatedAt     time.Time         `json:"createdAt"`
+		UpdatedAt     time.Time         `json:"updatedAt"`
+		LastMessageAt *time.Time        `json:"lastMessageAt,omitempty"`
+		UnreadCount   int               `json:"unreadCount"`
+		Tags          []string          `json:"tags"`
+		Priority      string            `json:"priority"`
+		Attributes    map[string]string `json:"attributes"`
+		SLA           *SLAOutput        `json:"sla,omitempty"`
 	}
 
+	// Output data, Page is zero for pages read with a cursor
 	GetChannelByQueryOutput struct {
 		Page       int                    `json:"page"`
 		Count      int                    `json:"count"`
 		Limit      int                    `json:"limit"`
 		TotalCount int                    `json:"totalCount"`
+		NextCursor string                 `json:"nextCursor,omitempty"`
+		PrevCursor string                 `json:"prevCursor,omitempty"`
 		Data       []ChannelByQueryOutput `json:"data"`
 	}
 
 	getChannelByQueryInteractor struct {
 		repo       domain.ChannelRepository
 		userRepo   domain.UserRepository
+		orgRepo    domain.OrganizationRepository
+		cursors    domain.ChannelCursorCodec
 		presenter  GetChannelByQueryPresenter
 		ctxTimeout time.Duration
 	}
@@ -48,67 +84,211 @@ type (
 func NewGetChannelByQueryInteractor(
 	repo domain.ChannelRepository,
 	userRepo domain.UserRepository,
+	orgRepo domain.OrganizationRepository,
+	cursors domain.ChannelCursorCodec,
 	presenter GetChannelByQueryPresenter,
 	t time.Duration,
 ) GetChannelByQueryUseCase {
 	return getChannelByQueryInteractor{
 		repo:       repo,
 		userRepo:   userRepo,
+		orgRepo:    orgRepo,
+		cursors:    cursors,
 		presenter:  presenter,
 		ctxTimeout: t,
 	}
 }
 
 // Execute orchestrates the use case
-func (a getChannelByQueryInteractor) Execute(ctx context.Context, query interface{}, limit, page string) (GetChannelByQueryOutput, error) {
+func (a getChannelByQueryInteractor) Execute(ctx context.Context, input GetChannelByQueryInput) (GetChannelByQueryOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
 	defer cancel()
 
-	var intLimit int
-	var intPage int
+	query, err := input.channelQuery()
+	if err != nil {
+		return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0, "", ""), err
+	}
 
-	if page != "" {
-		parsedPage, err := strconv.Atoi(page)
-		if err != nil {
-			intPage = 1
+	// The total counts every channel matching the filters, not only the page
+	channelsCount, err := a.repo.GetChannelsByQueryCount(ctx, query)
+	if err != nil {
+		return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0, "", ""), err
+	}
+
+	var (
+		channels         []domain.Channel
+		page             int
+		hasPrev, hasNext bool
+	)
+	if input.Cursor == "" {
+		page = input.Page
+		query.Skip = (input.Page - 1) * input.Limit
+		query.Limit = input.Limit
+
+		if channels, err = a.repo.GetChannelsByQuery(ctx, query); err != nil {
+			return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0, "", ""), err
 		}
-		intPage = parsedPage
+		hasPrev = query.Skip > 0
+		hasNext = int64(query.Skip+len(channels)) < channelsCount
 	} else {
-		intPage = 1
+		if channels, hasPrev, hasNext, err = a.channelsFromCursor(ctx, query, input.Cursor, input.Limit); err != nil {
+			return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0, "", ""), err
+		}
 	}
 
-	if limit != "" {
-		parsedLim, err := strconv.Atoi(limit)
-		if err != nil {
-			parsedLim = 10
+	var nextCursor, prevCursor string
+	if len(channels) > 0 {
+		if hasNext {
+			if nextCursor, err = a.cursors.Encode(channelCursor(query, channels[len(channels)-1], false)); err != nil {
+				return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0, "", ""), err
+			}
 		}
+		if hasPrev {
+			if prevCursor, err = a.cursors.Encode(channelCursor(query, channels[0], true)); err != nil {
+				return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0, "", ""), err
+			}
+		}
+	}
 
-		if parsedLim > 50 {
-			intLimit = 50
-		} else {
-			intLimit = parsedLim
+	if err := a.fillUserFullNames(ctx, channels); err != nil {
+		return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0, "", ""), err
+	}
+	if input.Internal {
+		if err := trackSLA(ctx, a.orgRepo, channels, time.Now()); err != nil {
+			return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0, "", ""), err
 		}
-	} else {
-		intLimit = 10
 	}
 
-	start := (intPage - 1) * intLimit
+	return a.presenter.Output(channels, page, input.Limit, len(channels), int(channelsCount), nextCursor, prevCursor), nil
+}
 
-	channels, err := a.repo.GetChannelsByQuery(ctx, query, start, intLimit)
+// channelsFromCursor reads the page next to a cursor position. One channel more
+// than the limit is read to tell whether the listing goes on, backward pages
+// are read in the reverse order and flipped back
+func (a getChannelByQueryInteractor) channelsFromCursor(
+	ctx context.Context,
+	query domain.ChannelQuery,
+	token string,
+	limit int,
+) ([]domain.Channel, bool, bool, error) {
+	cursor, err := a.cursors.Decode(token)
 	if err != nil {
-		return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0), err
+		return nil, false, false, domain.ErrInvalidCursor
+	}
+	if cursor.SortBy != query.SortBy || cursor.SortDescending != query.SortDescending {
+		return nil, false, false, domain.ErrInvalidCursor
 	}
 
-	for i, channel := range channels {
-		u, _ := a.userRepo.GetUserByEmail(ctx, channel.UserEmail())
-		channels[i].UpdateUserFullName(fmt.Sprintf("%s %s", u.FirstName(), u.LastName()))
+	query.After = &cursor
+	query.Limit = limit + 1
+	if cursor.Backward {
+		query.SortDescending = !query.SortDescending
 	}
 
-	i := bson.M{}
-	channelsCount, err := a.repo.GetChannelsByQueryCount(ctx, i)
+	channels, err := a.repo.GetChannelsByQuery(ctx, query)
 	if err != nil {
-		return a.presenter.Output([]domain.Channel{}, 0, 0, 0, 0), err
+		return nil, false, false, err
+	}
+
+	more := len(channels) > limit
+	if more {
+		channels = channels[:limit]
+	}
+	if !cursor.Backward {
+		return channels, true, more, nil
+	}
+
+	for i, j := 0, len(channels)-1; i < j; i, j = i+1, j-1 {
+		channels[i], channels[j] = channels[j], channels[i]
+	}
+	return channels, more, true, nil
+}
+
+// fillUserFullNames reads the users of a page in a single lookup, channels of
+// users that no longer exist keep an empty name
+func (a getChannelByQueryInteractor) fillUserFullNames(ctx context.Context, channels []domain.Channel) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	var (
+		emails = make([]string, 0, len(channels))
+		seen   = make(map[string]bool, len(channels))
+	)
+	for _, channel := range channels {
+		email := domain.NormalizeEmail(channel.UserEmail())
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+
+	users, err := a.userRepo.GetUsersByEmails(ctx, emails)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		names[domain.NormalizeEmail(u.Email())] = fmt.Sprintf("%s %s", u.FirstName(), u.LastName())
+	}
+	for i, channel := range channels {
+		channels[i].UpdateUserFullName(names[domain.NormalizeEmail(channel.UserEmail())])
+	}
+	return nil
+}
+
+func channelCursor(query domain.ChannelQuery, channel domain.Channel, backward bool) domain.ChannelCursor {
+	cursor := domain.ChannelCursor{
+		SortBy:         query.SortBy,
+		SortDescending: query.SortDescending,
+		Backward:       backward,
+		Id:             channel.Id(),
+	}
+	if query.SortBy == domain.ChannelSortPriority {
+		cursor.Rank = channel.PriorityRank()
+	} else {
+		cursor.Value = channel.SortValue(query.SortBy)
+	}
+	return cursor
+}
+
+func (i GetChannelByQueryInput) channelQuery() (domain.ChannelQuery, error) {
+	for _, r := range [][2]time.Time{{i.CreatedFrom, i.CreatedTo}, {i.UpdatedFrom, i.UpdatedTo}} {
+		if !r[0].IsZero() && !r[1].IsZero() && !r[1].After(r[0]) {
+			return domain.ChannelQuery{}, domain.ErrInvalidTimeRange
+		}
+	}
+	if i.Unassigned && i.RepEmail != "" {
+		return domain.ChannelQuery{}, domain.ErrUnassignedChannelsHaveRep
+	}
+	for name := range i.Attributes {
+		if !domain.IsAttributeName(name) {
+			return domain.ChannelQuery{}, domain.ErrInvalidAttribute
+		}
+	}
+
+	// Cursors carry the sort they were made for, the default is spelled out to compare them
+	sortBy := i.Sort
+	if sortBy == "" {
+		sortBy = domain.ChannelSortCreatedAt
 	}
 
-	return a.presenter.Output(channels, intPage, intLimit, len(channels), int(channelsCount)), nil
+	return domain.ChannelQuery{
+		UserEmail:      i.UserEmail,
+		RepEmail:       i.RepEmail,
+		Statuses:       i.Statuses,
+		Unassigned:     i.Unassigned,
+		Tags:           i.Tags,
+		Priorities:     i.Priorities,
+		Attributes:     i.Attributes,
+		Reader:         i.Reader,
+		Internal:       i.Internal,
+		CreatedFrom:    i.CreatedFrom,
+		CreatedTo:      i.CreatedTo,
+		UpdatedFrom:    i.UpdatedFrom,
+		UpdatedTo:      i.UpdatedTo,
+		SortBy:         sortBy,
+		SortDescending: i.Order == "desc",
+	}, nil
 }
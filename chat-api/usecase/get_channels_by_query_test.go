@@ -0,0 +1,403 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockQueryChannelRepo struct {
+	domain.ChannelRepository
+
+	channels   []domain.Channel
+	count      int64
+	query      *domain.ChannelQuery
+	countQuery *domain.ChannelQuery
+}
+
+func (m mockQueryChannelRepo) GetChannelsByQuery(_ context.Context, query domain.ChannelQuery) ([]domain.Channel, error) {
+	*m.query = query
+	return m.channels, nil
+}
+
+func (m mockQueryChannelRepo) GetChannelsByQueryCount(_ context.Context, query domain.ChannelQuery) (int64, error) {
+	*m.countQuery = query
+	return m.count, nil
+}
+
+type mockQueryUserRepo struct {
+	domain.UserRepository
+
+	err error
+	// latency stands for the round trip to the database
+	latency time.Duration
+	// lookups counts the calls, asked keeps the emails of the last one
+	lookups *int
+	asked   *[]string
+}
+
+func (m mockQueryUserRepo) GetUsersByEmails(_ context.Context, emails []string) ([]domain.User, error) {
+	if m.lookups != nil {
+		*m.lookups++
+	}
+	if m.asked != nil {
+		*m.asked = emails
+	}
+	time.Sleep(m.latency)
+
+	var users []domain.User
+	for _, email := range emails {
+		users = append(users, domain.NewUser(primitive.NewObjectID(), "first", "last", email, "", time.Time{}, time.Time{}))
+	}
+	return users, m.err
+}
+
+// mockChannelCursorCodec leaves cursors readable so tests can assert on them
+type mockChannelCursorCodec struct{}
+
+func (m mockChannelCursorCodec) Encode(cursor domain.ChannelCursor) (string, error) {
+	token, err := json.Marshal(cursor)
+	return string(token), err
+}
+
+func (m mockChannelCursorCodec) Decode(token string) (domain.ChannelCursor, error) {
+	var cursor domain.ChannelCursor
+	if err := json.Unmarshal([]byte(token), &cursor); err != nil {
+		return domain.ChannelCursor{}, domain.ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+func encodeCursor(cursor domain.ChannelCursor) string {
+	token, _ := mockChannelCursorCodec{}.Encode(cursor)
+	return token
+}
+
+type mockGetChannelByQueryPresenter struct{}
+
+func (m mockGetChannelByQueryPresenter) Output(channels []domain.Channel, page, limit, count, totalCount int, next, prev string) GetChannelByQueryOutput {
+	output := GetChannelByQueryOutput{Page: page, Count: count, Limit: limit, TotalCount: totalCount, NextCursor: next, PrevCursor: prev}
+	for _, channel := range channels {
+		output.Data = append(output.Data, ChannelByQueryOutput{UserEmail: channel.UserEmail(), UserFullName: channel.UserFullName()})
+	}
+	return output
+}
+
+func TestGetChannelByQueryInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		from    = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		to      = from.Add(24 * time.Hour)
+		channel = domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, from, from)
+	)
+
+	tests := []struct {
+		name               string
+		input              GetChannelByQueryInput
+		expected           GetChannelByQueryOutput
+		expectedQuery      domain.ChannelQuery
+		expectedCountQuery domain.ChannelQuery
+		expectedError      error
+	}{
+		{
+			name: "filtered page with a filtered total",
+			input: GetChannelByQueryInput{
+				RepEmail:    "rep@email.com",
+				Statuses:    []string{domain.ACTIVE, domain.IN_PROGRESS},
+				CreatedFrom: from,
+				CreatedTo:   to,
+				Sort:        domain.ChannelSortLastMessageAt,
+				Order:       "desc",
+				Page:        3,
+				Limit:       10,
+			},
+			expected: GetChannelByQueryOutput{
+				Page:       3,
+				Count:      1,
+				Limit:      10,
+				TotalCount: 21,
+				PrevCursor: encodeCursor(domain.ChannelCursor{
+					SortBy:         domain.ChannelSortLastMessageAt,
+					SortDescending: true,
+					Backward:       true,
+					Id:             channel.Id(),
+				}),
+				Data: []ChannelByQueryOutput{{UserEmail: "user@email.com", UserFullName: "first last"}},
+			},
+			expectedQuery: domain.ChannelQuery{
+				RepEmail:       "rep@email.com",
+				Statuses:       []string{domain.ACTIVE, domain.IN_PROGRESS},
+				CreatedFrom:    from,
+				CreatedTo:      to,
+				SortBy:         domain.ChannelSortLastMessageAt,
+				SortDescending: true,
+				Skip:           20,
+				Limit:          10,
+			},
+			expectedCountQuery: domain.ChannelQuery{
+				RepEmail:       "rep@email.com",
+				Statuses:       []string{domain.ACTIVE, domain.IN_PROGRESS},
+				CreatedFrom:    from,
+				CreatedTo:      to,
+				SortBy:         domain.ChannelSortLastMessageAt,
+				SortDescending: true,
+			},
+		},
+		{
+			name:  "unassigned channels",
+			input: GetChannelByQueryInput{Unassigned: true, UpdatedFrom: from, Page: 1, Limit: 10},
+			expected: GetChannelByQueryOutput{
+				Page:       1,
+				Count:      1,
+				Limit:      10,
+				TotalCount: 21,
+				NextCursor: encodeCursor(domain.ChannelCursor{SortBy: domain.ChannelSortCreatedAt, Value: from, Id: channel.Id()}),
+				Data:       []ChannelByQueryOutput{{UserEmail: "user@email.com", UserFullName: "first last"}},
+			},
+			expectedQuery:      domain.ChannelQuery{Unassigned: true, UpdatedFrom: from, SortBy: domain.ChannelSortCreatedAt, Limit: 10},
+			expectedCountQuery: domain.ChannelQuery{Unassigned: true, UpdatedFrom: from, SortBy: domain.ChannelSortCreatedAt},
+		},
+		{
+			name:          "range ending before it starts refused",
+			input:         GetChannelByQueryInput{UpdatedFrom: to, UpdatedTo: from, Page: 1, Limit: 10},
+			expectedError: domain.ErrInvalidTimeRange,
+		},
+		{
+			name:          "unassigned channels of a rep refused",
+			input:         GetChannelByQueryInput{RepEmail: "rep@email.com", Unassigned: true, Page: 1, Limit: 10},
+			expectedError: domain.ErrUnassignedChannelsHaveRep,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				query, countQuery domain.ChannelQuery
+				uc                = NewGetChannelByQueryInteractor(
+					mockQueryChannelRepo{channels: []domain.Channel{channel}, count: 21, query: &query, countQuery: &countQuery},
+					mockQueryUserRepo{},
+					mockOrganizationRepo{err: domain.ErrOrganizationNotFound},
+					mockChannelCursorCodec{},
+					mockGetChannelByQueryPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+			if !reflect.DeepEqual(query, tt.expectedQuery) {
+				t.Errorf("[TestCase '%s'] Query: '%v' | Expected: '%v'", tt.name, query, tt.expectedQuery)
+			}
+			if !reflect.DeepEqual(countQuery, tt.expectedCountQuery) {
+				t.Errorf("[TestCase '%s'] Count query: '%v' | Expected: '%v'", tt.name, countQuery, tt.expectedCountQuery)
+			}
+		})
+	}
+}
+
+func TestGetChannelByQueryInteractor_Cursor(t *testing.T) {
+	t.Parallel()
+
+	var (
+		from     = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		channels []domain.Channel
+	)
+	for i := 0; i < 3; i++ {
+		channels = append(channels, domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, from.Add(time.Duration(i)*time.Hour), from))
+	}
+	position := domain.ChannelCursor{SortBy: domain.ChannelSortCreatedAt, Value: from, Id: primitive.NewObjectID()}
+	backward := position
+	backward.Backward = true
+
+	tests := []struct {
+		name          string
+		cursor        string
+		found         []domain.Channel
+		expectedQuery domain.ChannelQuery
+		expectedIds   []primitive.ObjectID
+		expectedNext  string
+		expectedPrev  string
+		expectedError error
+	}{
+		{
+			name:          "forward page with more to come",
+			cursor:        encodeCursor(position),
+			found:         channels,
+			expectedQuery: domain.ChannelQuery{SortBy: domain.ChannelSortCreatedAt, After: &position, Limit: 3},
+			expectedIds:   []primitive.ObjectID{channels[0].Id(), channels[1].Id()},
+			expectedNext:  encodeCursor(domain.ChannelCursor{SortBy: domain.ChannelSortCreatedAt, Value: channels[1].CreatedAt(), Id: channels[1].Id()}),
+			expectedPrev:  encodeCursor(domain.ChannelCursor{SortBy: domain.ChannelSortCreatedAt, Backward: true, Value: channels[0].CreatedAt(), Id: channels[0].Id()}),
+		},
+		{
+			name:          "last forward page",
+			cursor:        encodeCursor(position),
+			found:         channels[:1],
+			expectedQuery: domain.ChannelQuery{SortBy: domain.ChannelSortCreatedAt, After: &position, Limit: 3},
+			expectedIds:   []primitive.ObjectID{channels[0].Id()},
+			expectedPrev:  encodeCursor(domain.ChannelCursor{SortBy: domain.ChannelSortCreatedAt, Backward: true, Value: channels[0].CreatedAt(), Id: channels[0].Id()}),
+		},
+		{
+			name:   "backward page read in reverse",
+			cursor: encodeCursor(backward),
+			// The repository returns the channels closest to the cursor first
+			found:         []domain.Channel{channels[2], channels[1]},
+			expectedQuery: domain.ChannelQuery{SortBy: domain.ChannelSortCreatedAt, SortDescending: true, After: &backward, Limit: 3},
+			expectedIds:   []primitive.ObjectID{channels[1].Id(), channels[2].Id()},
+			expectedNext:  encodeCursor(domain.ChannelCursor{SortBy: domain.ChannelSortCreatedAt, Value: channels[2].CreatedAt(), Id: channels[2].Id()}),
+		},
+		{
+			name:          "cursor made for another sort",
+			cursor:        encodeCursor(domain.ChannelCursor{SortBy: domain.ChannelSortUpdatedAt, Id: position.Id}),
+			expectedError: domain.ErrInvalidCursor,
+		},
+		{
+			name:          "cursor that does not decode",
+			cursor:        "not a cursor",
+			expectedError: domain.ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				query, countQuery domain.ChannelQuery
+				uc                = NewGetChannelByQueryInteractor(
+					mockQueryChannelRepo{channels: append([]domain.Channel{}, tt.found...), count: 5, query: &query, countQuery: &countQuery},
+					mockQueryUserRepo{},
+					mockOrganizationRepo{err: domain.ErrOrganizationNotFound},
+					mockChannelCursorCodec{},
+					mockGetChannelByQueryPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), GetChannelByQueryInput{Cursor: tt.cursor, Page: 1, Limit: 2})
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(query, tt.expectedQuery) {
+				t.Errorf("[TestCase '%s'] Query: '%v' | Expected: '%v'", tt.name, query, tt.expectedQuery)
+			}
+			if countQuery.After != nil {
+				t.Errorf("[TestCase '%s'] Count query: '%v' | Expected: '%v'", tt.name, countQuery.After, nil)
+			}
+			if got.Page != 0 || got.TotalCount != 5 || got.Count != len(tt.expectedIds) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v' channels of '%v'", tt.name, got, len(tt.expectedIds), 5)
+			}
+			if got.NextCursor != tt.expectedNext || got.PrevCursor != tt.expectedPrev {
+				t.Errorf("[TestCase '%s'] Cursors: '%v', '%v' | Expected: '%v', '%v'", tt.name, got.NextCursor, got.PrevCursor, tt.expectedNext, tt.expectedPrev)
+			}
+		})
+	}
+}
+
+func TestGetChannelByQueryInteractor_UserLookup(t *testing.T) {
+	t.Parallel()
+
+	var (
+		query, countQuery domain.ChannelQuery
+		lookups           int
+		asked             []string
+		channels          []domain.Channel
+	)
+	for _, email := range []string{"first@email.com", "second@email.com", "First@email.com"} {
+		channels = append(channels, domain.NewChannel(primitive.NewObjectID(), email, domain.ACTIVE, time.Now(), time.Now()))
+	}
+
+	uc := NewGetChannelByQueryInteractor(
+		mockQueryChannelRepo{channels: channels, count: 3, query: &query, countQuery: &countQuery},
+		mockQueryUserRepo{lookups: &lookups, asked: &asked},
+		mockOrganizationRepo{err: domain.ErrOrganizationNotFound},
+		mockChannelCursorCodec{},
+		mockGetChannelByQueryPresenter{},
+		time.Second,
+	)
+	got, err := uc.Execute(context.Background(), GetChannelByQueryInput{Page: 1, Limit: 10})
+	if err != nil || lookups != 1 {
+		t.Errorf("[TestCase 'one lookup per page'] Result: '%v', '%v' | Expected: '%v'", lookups, err, 1)
+	}
+	sort.Strings(asked)
+	if expected := []string{"first@email.com", "second@email.com"}; !reflect.DeepEqual(asked, expected) {
+		t.Errorf("[TestCase 'distinct emails'] Result: '%v' | Expected: '%v'", asked, expected)
+	}
+	for _, channel := range got.Data {
+		if channel.UserFullName != "first last" {
+			t.Errorf("[TestCase 'full names'] Result: '%v' | Expected: '%v'", channel.UserFullName, "first last")
+		}
+	}
+
+	failing := NewGetChannelByQueryInteractor(
+		mockQueryChannelRepo{channels: channels, count: 3, query: &query, countQuery: &countQuery},
+		mockQueryUserRepo{err: errors.New("connection lost")},
+		mockOrganizationRepo{err: domain.ErrOrganizationNotFound},
+		mockChannelCursorCodec{},
+		mockGetChannelByQueryPresenter{},
+		time.Second,
+	)
+	if _, err := failing.Execute(context.Background(), GetChannelByQueryInput{Page: 1, Limit: 10}); err == nil {
+		t.Errorf("[TestCase 'lookup failure'] Result: '%v' | Expected: an error", err)
+	}
+}
+
+// BenchmarkGetChannelByQueryInteractor reads full pages of distinct users with
+// a simulated round trip, a page costs one user lookup whatever its size
+func BenchmarkGetChannelByQueryInteractor(b *testing.B) {
+	for _, size := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("page of %d", size), func(b *testing.B) {
+			var (
+				query, countQuery domain.ChannelQuery
+				lookups           int
+				channels          []domain.Channel
+			)
+			for i := 0; i < size; i++ {
+				channels = append(channels, domain.NewChannel(primitive.NewObjectID(), fmt.Sprintf("user%d@email.com", i), domain.ACTIVE, time.Now(), time.Now()))
+			}
+
+			uc := NewGetChannelByQueryInteractor(
+				mockQueryChannelRepo{channels: channels, count: int64(size), query: &query, countQuery: &countQuery},
+				mockQueryUserRepo{latency: 100 * time.Microsecond, lookups: &lookups},
+				mockOrganizationRepo{err: domain.ErrOrganizationNotFound},
+				mockChannelCursorCodec{},
+				mockGetChannelByQueryPresenter{},
+				time.Second,
+			)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := uc.Execute(context.Background(), GetChannelByQueryInput{Page: 1, Limit: size}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			if lookups != b.N {
+				b.Fatalf("[TestCase 'page of %d'] Lookups: '%v' | Expected: '%v'", size, lookups, b.N)
+			}
+			b.ReportMetric(float64(lookups)/float64(b.N), "lookups/op")
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetCurrentUserUseCase interface {
+		Execute(context.Context, GetUserByEmailInput) (GetUserByEmailOutput, error)
+	}
+
+	getCurrentUserInteractor struct {
+		repo       domain.UserRepository
+		presenter  GetUserByEmailPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+// NewGetCurrentUserInteractor resolves the authenticated user, refusing deactivated accounts
+func NewGetCurrentUserInteractor(
+	repo domain.UserRepository,
+	presenter GetUserByEmailPresenter,
+	t time.Duration,
+) GetCurrentUserUseCase {
+	return getCurrentUserInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a getCurrentUserInteractor) Execute(ctx context.Context, input GetUserByEmailInput) (GetUserByEmailOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	user, err := a.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	if !user.IsActive() {
+		return a.presenter.Output(domain.User{}), domain.ErrUserDeactivated
+	}
+
+	return a.presenter.Output(user), nil
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetGuestSessionUseCase interface {
+		Execute(context.Context, GetGuestSessionInput) (GetGuestSessionOutput, error)
+	}
+
+	// Input data
+	GetGuestSessionInput struct {
+		Email     string `json:"-"`
+		ChannelId string `json:"-"`
+	}
+
+	// Output port
+	GetGuestSessionPresenter interface {
+		Output(domain.Channel) GetGuestSessionOutput
+	}
+
+	// Output data
+	GetGuestSessionOutput struct {
+		ChannelId     string `json:"channelId"`
+		Email         string `json:"email"`
+		FullName      string `json:"fullName"`
+		CurrentStatus string `json:"currentStatus"`
+		TenantId      string `json:"tenantId"`
+	}
+
+	getGuestSessionInteractor struct {
+		repo       domain.ChannelRepository
+		presenter  GetGuestSessionPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetGuestSessionInteractor(
+	repo domain.ChannelRepository,
+	presenter GetGuestSessionPresenter,
+	t time.Duration,
+) GetGuestSessionUseCase {
+	return getGuestSessionInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (g getGuestSessionInteractor) Execute(ctx context.Context, input GetGuestSessionInput) (GetGuestSessionOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ctxTimeout)
+	defer cancel()
+
+	if input.ChannelId == "" {
+		return g.presenter.Output(domain.Channel{}), domain.ErrGuestSessionInvalid
+	}
+
+	channel, err := g.repo.GetChannelById(ctx, input.ChannelId)
+	if err != nil {
+		return g.presenter.Output(domain.Channel{}), domain.ErrGuestSessionInvalid
+	}
+
+	// Once the guest registered the channel belongs to the account and the guest token is spent
+	if !channel.IsGuest() || channel.UserEmail() != input.Email {
+		return g.presenter.Output(domain.Channel{}), domain.ErrGuestSessionInvalid
+	}
+
+	return g.presenter.Output(channel), nil
+}
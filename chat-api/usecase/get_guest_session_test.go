@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockGuestChannelRepo struct {
+	domain.ChannelRepository
+
+	channel domain.Channel
+	err     error
+}
+
+func (m mockGuestChannelRepo) GetChannelById(_ context.Context, _ string) (domain.Channel, error) {
+	return m.channel, m.err
+}
+
+type mockGetGuestSessionPresenter struct{}
+
+func (m mockGetGuestSessionPresenter) Output(channel domain.Channel) GetGuestSessionOutput {
+	return GetGuestSessionOutput{Email: channel.UserEmail()}
+}
+
+func TestGetGuestSessionInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	channelId := primitive.NewObjectID()
+	guestChannel := domain.NewChannel(channelId, "guest@email.com", domain.ACTIVE, time.Now(), time.Now())
+	guestChannel.MarkGuest()
+	mergedChannel := domain.NewChannel(channelId, "guest@email.com", domain.ACTIVE, time.Now(), time.Now())
+
+	tests := []struct {
+		name          string
+		channel       domain.Channel
+		err           error
+		input         GetGuestSessionInput
+		expectedError error
+	}{
+		{
+			name:    "guest channel",
+			channel: guestChannel,
+			input:   GetGuestSessionInput{Email: "guest@email.com", ChannelId: channelId.Hex()},
+		},
+		{
+			name:          "channel merged into an account",
+			channel:       mergedChannel,
+			input:         GetGuestSessionInput{Email: "guest@email.com", ChannelId: channelId.Hex()},
+			expectedError: domain.ErrGuestSessionInvalid,
+		},
+		{
+			name:          "token for another guest",
+			channel:       guestChannel,
+			input:         GetGuestSessionInput{Email: "other@email.com", ChannelId: channelId.Hex()},
+			expectedError: domain.ErrGuestSessionInvalid,
+		},
+		{
+			name:          "token without a channel",
+			input:         GetGuestSessionInput{Email: "user@email.com"},
+			expectedError: domain.ErrGuestSessionInvalid,
+		},
+		{
+			name:          "channel not found",
+			err:           domain.ChannelNotFound,
+			input:         GetGuestSessionInput{Email: "guest@email.com", ChannelId: channelId.Hex()},
+			expectedError: domain.ErrGuestSessionInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewGetGuestSessionInteractor(mockGuestChannelRepo{channel: tt.channel, err: tt.err}, mockGetGuestSessionPresenter{}, time.Second)
+
+			if _, err := uc.Execute(context.Background(), tt.input); err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+		})
+	}
+}
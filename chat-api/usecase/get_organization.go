@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetOrganizationUseCase interface {
+		Execute(context.Context) (OrganizationOutput, error)
+	}
+
+	// Output port
+	GetOrganizationPresenter interface {
+		Output(domain.Organization) OrganizationOutput
+	}
+
+	BusinessDayOutput struct {
+		Weekday int    `json:"weekday"`
+		Open    string `json:"open"`
+		Close   string `json:"close"`
+	}
+
+	SLAPolicyOutput struct {
+		Priority             string `json:"priority"`
+		FirstResponseMinutes int    `json:"firstResponseMinutes"`
+		ResolutionMinutes    int    `json:"resolutionMinutes"`
+	}
+
+	// OrganizationSettingsOutput lists the SLA policy of every priority, configured or default
+	OrganizationSettingsOutput struct {
+		TimeZone        string              `json:"timeZone"`
+		BusinessDays    []BusinessDayOutput `json:"businessDays"`
+		RoutingStrategy string              `json:"routingStrategy"`
+		SLAPolicies     []SLAPolicyOutput   `json:"slaPolicies"`
+	}
+
+	// Output data
+	OrganizationOutput struct {
+		Id        string                     `json:"id"`
+		Name      string                     `json:"name"`
+		Domains   []string                   `json:"domains"`
+		Settings  OrganizationSettingsOutput `json:"settings"`
+		CreatedAt time.Time                  `json:"createdAt"`
+		UpdatedAt time.Time                  `json:"updatedAt"`
+	}
+
+	getOrganizationInteractor struct {
+		repo       domain.OrganizationRepository
+		presenter  GetOrganizationPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetOrganizationInteractor(
+	repo domain.OrganizationRepository,
+	presenter GetOrganizationPresenter,
+	t time.Duration,
+) GetOrganizationUseCase {
+	return getOrganizationInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute returns the organization of the request
+func (g getOrganizationInteractor) Execute(ctx context.Context) (OrganizationOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ctxTimeout)
+	defer cancel()
+
+	organization, err := currentOrganization(ctx, g.repo)
+	if err != nil {
+		return g.presenter.Output(domain.Organization{}), err
+	}
+
+	return g.presenter.Output(organization), nil
+}
+
+// currentOrganization loads the organization of the request, the default one
+// has sensible settings even before anything was saved for it
+func currentOrganization(ctx context.Context, repo domain.OrganizationRepository) (domain.Organization, error) {
+	tenantID := domain.TenantFromContext(ctx)
+
+	organization, err := repo.GetOrganizationById(ctx, tenantID)
+	switch {
+	case err == nil:
+		return organization, nil
+	case err == domain.ErrOrganizationNotFound && tenantID == domain.DefaultTenantID:
+		return domain.DefaultOrganization(), nil
+	default:
+		return domain.Organization{}, err
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetRepAnalyticsUseCase interface {
+		Execute(context.Context, AnalyticsInput) (GetRepAnalyticsOutput, error)
+	}
+
+	// Output port
+	GetRepAnalyticsPresenter interface {
+		Output(domain.AnalyticsQuery, []domain.RepBucket, []domain.RepResolution, []domain.RepWorkload) GetRepAnalyticsOutput
+	}
+
+	RepAnalyticsBucketOutput struct {
+		Start    time.Time `json:"start"`
+		Resolved int       `json:"resolved"`
+	}
+
+	// RepAnalyticsOutput is the workload of a rep right now, the open channels
+	// assigned to them by status, and their throughput over the range
+	RepAnalyticsOutput struct {
+		RepEmail                string                     `json:"repEmail"`
+		Open                    int                        `json:"open"`
+		Workload                map[string]int             `json:"workload"`
+		Resolved                int                        `json:"resolved"`
+		MedianResolutionSeconds *float64                   `json:"medianResolutionSeconds"`
+		Series                  []RepAnalyticsBucketOutput `json:"series"`
+	}
+
+	// Output data
+	GetRepAnalyticsOutput struct {
+		Interval string               `json:"interval"`
+		TimeZone string               `json:"timeZone"`
+		From     time.Time            `json:"from"`
+		To       time.Time            `json:"to"`
+		Reps     []RepAnalyticsOutput `json:"reps"`
+	}
+
+	getRepAnalyticsInteractor struct {
+		repo       domain.AnalyticsRepository
+		orgRepo    domain.OrganizationRepository
+		presenter  GetRepAnalyticsPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetRepAnalyticsInteractor(
+	repo domain.AnalyticsRepository,
+	orgRepo domain.OrganizationRepository,
+	presenter GetRepAnalyticsPresenter,
+	t time.Duration,
+) GetRepAnalyticsUseCase {
+	return getRepAnalyticsInteractor{
+		repo:       repo,
+		orgRepo:    orgRepo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (g getRepAnalyticsInteractor) Execute(ctx context.Context, input AnalyticsInput) (GetRepAnalyticsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ctxTimeout)
+	defer cancel()
+
+	query, err := input.analyticsQuery(ctx, g.orgRepo, time.Now())
+	if err != nil {
+		return g.presenter.Output(domain.AnalyticsQuery{}, nil, nil, nil), err
+	}
+
+	buckets, err := g.repo.GetRepBuckets(ctx, query)
+	if err != nil {
+		return g.presenter.Output(domain.AnalyticsQuery{}, nil, nil, nil), err
+	}
+
+	resolutions, err := g.repo.GetRepResolutions(ctx, query)
+	if err != nil {
+		return g.presenter.Output(domain.AnalyticsQuery{}, nil, nil, nil), err
+	}
+
+	workload, err := g.repo.GetRepWorkload(ctx, query.Tag)
+	if err != nil {
+		return g.presenter.Output(domain.AnalyticsQuery{}, nil, nil, nil), err
+	}
+
+	return g.presenter.Output(query, buckets, resolutions, workload), nil
+}
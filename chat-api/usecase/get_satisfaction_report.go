@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+// defaultSatisfactionReportDays is the range of a report without a From
+const defaultSatisfactionReportDays = 30
+
+type (
+	// Input port
+	GetSatisfactionReportUseCase interface {
+		Execute(context.Context, GetSatisfactionReportInput) (GetSatisfactionReportOutput, error)
+	}
+
+	// GetSatisfactionReportInput groups the ratings given from the inclusive From to
+	// the exclusive To. To defaults to now and From to 30 days before To
+	GetSatisfactionReportInput struct {
+		GroupBy  string    `json:"groupBy" validate:"required,oneof=rep tag day week month"`
+		From     time.Time `json:"from"`
+		To       time.Time `json:"to"`
+		RepEmail string    `json:"repEmail"`
+		Tag      string    `json:"tag"`
+	}
+
+	// Output port
+	GetSatisfactionReportPresenter interface {
+		Output(string, domain.SatisfactionQuery, domain.SatisfactionGroup, []domain.SatisfactionGroup) GetSatisfactionReportOutput
+	}
+
+	SatisfactionGroupOutput struct {
+		Key       string  `json:"key"`
+		Responses int     `json:"responses"`
+		Average   float64 `json:"average"`
+		CSAT      float64 `json:"csat"`
+		// Scores counts the ratings of each score from 1 to 5
+		Scores []int `json:"scores"`
+	}
+
+	// Output data
+	GetSatisfactionReportOutput struct {
+		GroupBy string                    `json:"groupBy"`
+		From    time.Time                 `json:"from"`
+		To      time.Time                 `json:"to"`
+		Overall SatisfactionGroupOutput   `json:"overall"`
+		Groups  []SatisfactionGroupOutput `json:"groups"`
+	}
+
+	getSatisfactionReportInteractor struct {
+		repo       domain.ChannelRepository
+		presenter  GetSatisfactionReportPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetSatisfactionReportInteractor(
+	repo domain.ChannelRepository,
+	presenter GetSatisfactionReportPresenter,
+	t time.Duration,
+) GetSatisfactionReportUseCase {
+	return getSatisfactionReportInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (g getSatisfactionReportInteractor) Execute(ctx context.Context, input GetSatisfactionReportInput) (GetSatisfactionReportOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ctxTimeout)
+	defer cancel()
+
+	query, err := input.satisfactionQuery(time.Now())
+	if err != nil {
+		return g.presenter.Output("", domain.SatisfactionQuery{}, domain.SatisfactionGroup{}, nil), err
+	}
+
+	ratings, err := g.repo.GetSatisfactionRatings(ctx, query)
+	if err != nil {
+		return g.presenter.Output("", domain.SatisfactionQuery{}, domain.SatisfactionGroup{}, nil), err
+	}
+
+	groups, err := domain.SatisfactionReport(ratings, input.GroupBy)
+	if err != nil {
+		return g.presenter.Output("", domain.SatisfactionQuery{}, domain.SatisfactionGroup{}, nil), err
+	}
+
+	// Ratings are counted once overall even when they fall in several tag groups
+	var overall domain.SatisfactionGroup
+	for _, rating := range ratings {
+		overall.Add(rating)
+	}
+
+	return g.presenter.Output(input.GroupBy, query, overall, groups), nil
+}
+
+func (i GetSatisfactionReportInput) satisfactionQuery(now time.Time) (domain.SatisfactionQuery, error) {
+	to, from := i.To, i.From
+	if to.IsZero() {
+		to = reportEnd(now)
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -defaultSatisfactionReportDays)
+	}
+	if !to.After(from) {
+		return domain.SatisfactionQuery{}, domain.ErrInvalidTimeRange
+	}
+	if to.Sub(from) > domain.MaxSatisfactionReportDays*24*time.Hour {
+		return domain.SatisfactionQuery{}, domain.ErrReportRangeTooLong
+	}
+
+	query := domain.SatisfactionQuery{
+		From:     from,
+		To:       to,
+		RepEmail: domain.NormalizeEmail(i.RepEmail),
+	}
+	if tags := domain.NormalizeTags([]string{i.Tag}); len(tags) > 0 {
+		query.Tag = tags[0]
+	}
+	return query, nil
+}
+
+// reportEnd is the exclusive end of a report up to now. Times are stored to the
+// millisecond, so it is rounded up past the millisecond of now to count what
+// happened in it, like the rating just given
+func reportEnd(now time.Time) time.Time {
+	return now.Truncate(time.Millisecond).Add(time.Millisecond)
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetSatisfactionReportInput_satisfactionQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		now  time.Time
+	}{
+		{name: "now within a millisecond", now: time.Date(2021, 3, 1, 9, 0, 0, 123456789, time.UTC)},
+		{name: "now on a millisecond", now: time.Date(2021, 3, 1, 9, 0, 0, 123000000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		query, err := GetSatisfactionReportInput{GroupBy: "day"}.satisfactionQuery(tt.now)
+		if err != nil {
+			t.Fatalf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, err, nil)
+		}
+
+		// A rating given now is stored truncated to the millisecond and still counts
+		if stored := tt.now.Truncate(time.Millisecond); !stored.Before(query.To) {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: after '%v'", tt.name, query.To, stored)
+		}
+		if expected := query.To.AddDate(0, 0, -defaultSatisfactionReportDays); !query.From.Equal(expected) {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, query.From, expected)
+		}
+	}
+}
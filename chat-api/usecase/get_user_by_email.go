@@ -27,6 +27,7 @@ type (
 		Role      string    `json:"role"`
 		CreatedAt time.Time `json:"createdAt"`
 		Email     string    `json:"email"`
+		TenantId  string    `json:"tenantId"`
 	}
 
 	getUserByEmailInteractor struct {
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	GetUsersUseCase interface {
+		Execute(context.Context, GetUsersInput) (GetUsersOutput, error)
+	}
+
+	// Input data
+	GetUsersInput struct {
+		Role  string `json:"role" validate:"omitempty,oneof=ADMIN USER"`
+		Page  int    `json:"page" validate:"min=1"`
+		Limit int    `json:"limit" validate:"min=1,max=50"`
+	}
+
+	// Output port
+	GetUsersPresenter interface {
+		Output([]domain.User, int, int, int) GetUsersOutput
+	}
+
+	UserOutput struct {
+		Id               string    `json:"id"`
+		FirstName        string    `json:"firstName"`
+		LastName         string    `json:"lastName"`
+		Email            string    `json:"email"`
+		Role             string    `json:"role"`
+		Active           bool      `json:"active"`
+		TwoFactorEnabled bool      `json:"twoFactorEnabled"`
+		CreatedAt        time.Time `json:"createdAt"`
+	}
+
+	// Output data
+	GetUsersOutput struct {
+		Page       int          `json:"page"`
+		Count      int          `json:"count"`
+		Limit      int          `json:"limit"`
+		TotalCount int          `json:"totalCount"`
+		Data       []UserOutput `json:"data"`
+	}
+
+	getUsersInteractor struct {
+		repo       domain.UserRepository
+		presenter  GetUsersPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewGetUsersInteractor(
+	repo domain.UserRepository,
+	presenter GetUsersPresenter,
+	t time.Duration,
+) GetUsersUseCase {
+	return getUsersInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a getUsersInteractor) Execute(ctx context.Context, input GetUsersInput) (GetUsersOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	users, err := a.repo.GetUsers(ctx, input.Role, (input.Page-1)*input.Limit, input.Limit)
+	if err != nil {
+		return a.presenter.Output([]domain.User{}, 0, 0, 0), err
+	}
+
+	count, err := a.repo.GetUsersCount(ctx, input.Role)
+	if err != nil {
+		return a.presenter.Output([]domain.User{}, 0, 0, 0), err
+	}
+
+	return a.presenter.Output(users, input.Page, input.Limit, int(count)), nil
+}
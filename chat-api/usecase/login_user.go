@@ -2,8 +2,6 @@ package usecase
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"time"
 
 	"chat-api/domain"
@@ -18,6 +16,8 @@ type (
 	LoginUserInput struct {
 		Email    string `json:"email" validate:"required"`
 		Password string `json:"password" validate:"required"`
+		Code     string `json:"code,omitempty"`
+		IP       string `json:"-"`
 	}
 
 	// Output port
@@ -32,27 +32,45 @@ type (
 		Email     string `json:"email"`
 		Role      string `json:"role"`
 		Token     string `json:"token"`
+
+		TwoFactorEnrollmentRequired bool `json:"twoFactorEnrollmentRequired,omitempty"`
 	}
 
 	loginUserInteractor struct {
-		repo       domain.UserRepository
-		service    domain.AuthenticationUtilityService
-		presenter  LoginUserPresenter
-		ctxTimeout time.Duration
+		repo          domain.UserRepository
+		attemptRepo   domain.LoginAttemptRepository
+		settingsRepo  domain.SettingsRepository
+		service       domain.AuthenticationUtilityService
+		twoFactor     domain.TwoFactorService
+		audit         domain.AuditLogger
+		presenter     LoginUserPresenter
+		accountPolicy domain.LoginThrottlePolicy
+		ipPolicy      domain.LoginThrottlePolicy
+		ctxTimeout    time.Duration
 	}
 )
 
 func NewLoginUserInteractor(
 	repo domain.UserRepository,
+	attemptRepo domain.LoginAttemptRepository,
+	settingsRepo domain.SettingsRepository,
 	service domain.AuthenticationUtilityService,
+	twoFactor domain.TwoFactorService,
+	audit domain.AuditLogger,
 	presenter LoginUserPresenter,
 	t time.Duration,
 ) LoginUserUseCase {
 	return loginUserInteractor{
-		repo:       repo,
-		service:    service,
-		presenter:  presenter,
-		ctxTimeout: t,
+		repo:          repo,
+		attemptRepo:   attemptRepo,
+		settingsRepo:  settingsRepo,
+		service:       service,
+		twoFactor:     twoFactor,
+		audit:         audit,
+		presenter:     presenter,
+		accountPolicy: domain.DefaultAccountThrottlePolicy(),
+		ipPolicy:      domain.DefaultIPThrottlePolicy(),
+		ctxTimeout:    t,
 	}
 }
 
@@ -61,19 +79,107 @@ func (l loginUserInteractor) Execute(ctx context.Context, input LoginUserInput)
 	ctx, cancel := context.WithTimeout(ctx, l.ctxTimeout)
 	defer cancel()
 
+	now := time.Now()
+
+	accountAttempt, err := l.attemptRepo.GetLoginAttempt(ctx, domain.AccountAttemptKey(input.Email))
+	if err != nil {
+		return l.presenter.Output(domain.User{}, ""), err
+	}
+
+	var ipAttempt domain.LoginAttempt
+	if input.IP != "" {
+		ipAttempt, err = l.attemptRepo.GetLoginAttempt(ctx, domain.IPAttemptKey(input.IP))
+		if err != nil {
+			return l.presenter.Output(domain.User{}, ""), err
+		}
+	}
+
+	// Throttling is checked before the password hash is compared so that a
+	// locked account or IP cannot be used to burn CPU on hashing.
+	if accountAttempt.IsLocked(now) || (input.IP != "" && ipAttempt.IsLocked(now)) {
+		l.record(ctx, input, domain.AuditOutcomeFailure, now)
+		return l.presenter.Output(domain.User{}, ""), domain.ErrAccountLocked
+	}
+	if now.Before(accountAttempt.RetryAt(l.accountPolicy)) ||
+		(input.IP != "" && now.Before(ipAttempt.RetryAt(l.ipPolicy))) {
+		l.record(ctx, input, domain.AuditOutcomeFailure, now)
+		return l.presenter.Output(domain.User{}, ""), domain.ErrTooManyLoginAttempts
+	}
+
 	existingUser, err := l.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil && err != domain.ErrUserNotFound {
+		return l.presenter.Output(domain.User{}, ""), err
+	}
 
-	if existingUser.Email() == "" {
-		return l.presenter.Output(domain.User{}, ""), errors.New(fmt.Sprintf("Username or password incorrect"))
+	if existingUser.Email() == "" || !l.service.CheckPasswordHash(ctx, input.Password, existingUser.Password()) {
+		return l.presenter.Output(domain.User{}, ""), l.fail(ctx, accountAttempt, ipAttempt, input, now, domain.ErrUsernameOrPasswordIncorrect)
 	}
 
-	isPasswordCorrect := l.service.CheckPasswordHash(ctx, input.Password, existingUser.Password())
-	if err != nil {
-		return l.presenter.Output(domain.User{}, ""), errors.New(fmt.Sprintf("Username or password incorrect"))
+	// Only reported once the password is known to be right, so the account
+	// state is not disclosed to someone guessing passwords.
+	if !existingUser.IsActive() {
+		l.record(ctx, input, domain.AuditOutcomeFailure, now)
+		return l.presenter.Output(domain.User{}, ""), domain.ErrUserDeactivated
+	}
+
+	// Second step: the password is correct but the account also needs a TOTP
+	// or recovery code. Asking for it does not count as a failed attempt,
+	// a wrong code does.
+	if existingUser.TwoFactorEnabled() {
+		if input.Code == "" {
+			return l.presenter.Output(domain.User{}, ""), domain.ErrTwoFactorRequired
+		}
+
+		valid, usedRecoveryCode, err := verifyTwoFactorCode(ctx, l.twoFactor, &existingUser, input.Code)
+		if err != nil {
+			return l.presenter.Output(domain.User{}, ""), err
+		}
+		if !valid {
+			return l.presenter.Output(domain.User{}, ""), l.fail(ctx, accountAttempt, ipAttempt, input, now, domain.ErrInvalidTwoFactorCode)
+		}
+		if usedRecoveryCode {
+			if err := l.repo.UpdateTwoFactor(ctx, existingUser); err != nil {
+				return l.presenter.Output(domain.User{}, ""), err
+			}
+		}
+	}
+
+	if accountAttempt.Failures() > 0 {
+		accountAttempt.Reset()
+		if err := l.attemptRepo.SaveLoginAttempt(ctx, accountAttempt); err != nil {
+			return l.presenter.Output(domain.User{}, ""), err
+		}
 	}
 
-	if !isPasswordCorrect {
-		return l.presenter.Output(domain.User{}, ""), errors.New(fmt.Sprintf("Username or password incorrect"))
+	// Hashes produced with outdated parameters are upgraded while the plain
+	// password is at hand. This is best effort, a failure is retried on the
+	// next successful login.
+	if l.service.NeedsRehash(ctx, existingUser.Password()) {
+		if hashedPassword, err := l.service.HashPassword(ctx, input.Password); err == nil {
+			existingUser.UpdatePassword(hashedPassword, now)
+			_ = l.repo.UpdatePassword(ctx, existingUser)
+		}
+	}
+
+	if existingUser.Role() == domain.ADMIN && !existingUser.TwoFactorEnabled() {
+		settings, err := l.settingsRepo.GetSecuritySettings(ctx)
+		if err != nil {
+			return l.presenter.Output(domain.User{}, ""), err
+		}
+
+		// Admins without a second factor only get a token able to enroll one
+		if settings.RequireTwoFactorForAdmin {
+			token, err := l.service.GenerateScopedToken(ctx, existingUser, domain.ScopeTwoFactorEnrollment)
+			if err != nil {
+				return l.presenter.Output(domain.User{}, ""), err
+			}
+
+			l.record(ctx, input, domain.AuditOutcomeSuccess, now)
+
+			output := l.presenter.Output(existingUser, token)
+			output.TwoFactorEnrollmentRequired = true
+			return output, nil
+		}
 	}
 
 	token, err := l.service.GenerateToken(ctx, existingUser)
@@ -81,5 +187,95 @@ func (l loginUserInteractor) Execute(ctx context.Context, input LoginUserInput)
 		return l.presenter.Output(domain.User{}, ""), err
 	}
 
+	l.record(ctx, input, domain.AuditOutcomeSuccess, now)
+
 	return l.presenter.Output(existingUser, token), nil
 }
+
+func (l loginUserInteractor) record(ctx context.Context, input LoginUserInput, outcome string, now time.Time) {
+	l.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditLogin,
+		Actor:     input.Email,
+		Target:    input.Email,
+		IP:        input.IP,
+		Outcome:   outcome,
+		Timestamp: now,
+	})
+}
+
+// fail records the failed attempt against the account and the IP, returning the error to report
+func (l loginUserInteractor) fail(
+	ctx context.Context,
+	accountAttempt domain.LoginAttempt,
+	ipAttempt domain.LoginAttempt,
+	input LoginUserInput,
+	now time.Time,
+	reason error,
+) error {
+	l.record(ctx, input, domain.AuditOutcomeFailure, now)
+
+	if err := l.registerFailure(ctx, accountAttempt.Key(), l.accountPolicy, input, now); err != nil {
+		return err
+	}
+	if input.IP != "" {
+		if err := l.registerFailure(ctx, ipAttempt.Key(), l.ipPolicy, input, now); err != nil {
+			return err
+		}
+	}
+	return reason
+}
+
+// registerFailure counts the failure in the repository and locks the subject out
+// when the count it got back reaches the threshold. Concurrent failures never
+// read the same count, and only one of them gets to lock. Failures outside the
+// window of the policy, or from before an expired lockout, no longer count
+func (l loginUserInteractor) registerFailure(
+	ctx context.Context,
+	key string,
+	policy domain.LoginThrottlePolicy,
+	input LoginUserInput,
+	now time.Time,
+) error {
+	attempt, err := l.attemptRepo.AddLoginFailure(ctx, key, now, policy.FailuresSince(now))
+	if err != nil {
+		return err
+	}
+	if !policy.ShouldLock(attempt, now) {
+		return nil
+	}
+
+	locked, err := l.attemptRepo.LockLoginAttempt(ctx, key, now, now.Add(policy.LockoutDuration))
+	if err != nil {
+		return err
+	}
+	if locked {
+		l.audit.Record(ctx, domain.AuditEvent{
+			Action:    domain.AuditAccountLocked,
+			Actor:     input.Email,
+			Target:    key,
+			IP:        input.IP,
+			Outcome:   domain.AuditOutcomeSuccess,
+			Timestamp: now,
+		})
+	}
+	return nil
+}
+
+// verifyTwoFactorCode accepts either a current TOTP code or an unused recovery
+// code, in which case the code is consumed from the user
+func verifyTwoFactorCode(ctx context.Context, twoFactor domain.TwoFactorService, user *domain.User, code string) (bool, bool, error) {
+	secret, err := twoFactor.DecryptSecret(ctx, user.TwoFactorSecret())
+	if err != nil {
+		return false, false, err
+	}
+
+	if twoFactor.ValidateCode(ctx, secret, code) {
+		return true, false, nil
+	}
+
+	if user.UseRecoveryCode(twoFactor.HashRecoveryCode(ctx, code)) {
+		return true, true, nil
+	}
+
+	return false, false, nil
+}
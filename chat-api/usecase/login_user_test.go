@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockLoginAuthenticationService struct {
+	domain.AuthenticationUtilityService
+
+	passwordCorrect bool
+	needsRehash     bool
+	token           string
+	scopedToken     string
+}
+
+func (m mockLoginAuthenticationService) GenerateScopedToken(_ context.Context, _ domain.User, _ string) (string, error) {
+	return m.scopedToken, nil
+}
+
+func (m mockLoginAuthenticationService) NeedsRehash(_ context.Context, _ string) bool {
+	return m.needsRehash
+}
+
+func (m mockLoginAuthenticationService) HashPassword(_ context.Context, _ string) (string, error) {
+	return "rehashed", nil
+}
+
+func (m mockLoginAuthenticationService) CheckPasswordHash(_ context.Context, _, _ string) bool {
+	return m.passwordCorrect
+}
+
+func (m mockLoginAuthenticationService) GenerateToken(_ context.Context, _ domain.User) (string, error) {
+	return m.token, nil
+}
+
+type mockLoginUserRepo struct {
+	domain.UserRepository
+
+	user    domain.User
+	err     error
+	updated *domain.User
+}
+
+func (m mockLoginUserRepo) GetUserByEmail(_ context.Context, _ string) (domain.User, error) {
+	return m.user, m.err
+}
+
+func (m mockLoginUserRepo) UpdateTwoFactor(_ context.Context, user domain.User) error {
+	if m.updated != nil {
+		*m.updated = user
+	}
+	return nil
+}
+
+func (m mockLoginUserRepo) UpdatePassword(_ context.Context, user domain.User) error {
+	if m.updated != nil {
+		*m.updated = user
+	}
+	return nil
+}
+
+type mockLoginAttemptRepo struct {
+	attempts map[string]domain.LoginAttempt
+}
+
+func (m mockLoginAttemptRepo) GetLoginAttempt(_ context.Context, key string) (domain.LoginAttempt, error) {
+	if attempt, ok := m.attempts[key]; ok {
+		return attempt, nil
+	}
+	return domain.NewLoginAttempt(key, 0, time.Time{}, time.Time{}), nil
+}
+
+func (m mockLoginAttemptRepo) SaveLoginAttempt(_ context.Context, attempt domain.LoginAttempt) error {
+	m.attempts[attempt.Key()] = attempt
+	return nil
+}
+
+func (m mockLoginAttemptRepo) AddLoginFailure(ctx context.Context, key string, now, since time.Time) (domain.LoginAttempt, error) {
+	attempt, _ := m.GetLoginAttempt(ctx, key)
+	if attempt.LastFailureAt().Before(since) || (!attempt.LockedUntil().IsZero() && !attempt.IsLocked(now)) {
+		attempt = domain.NewLoginAttempt(key, 0, now, time.Time{})
+	}
+	m.attempts[key] = domain.NewLoginAttempt(key, attempt.Failures()+1, now, attempt.LockedUntil())
+	return m.attempts[key], nil
+}
+
+func (m mockLoginAttemptRepo) LockLoginAttempt(ctx context.Context, key string, now, until time.Time) (bool, error) {
+	attempt, _ := m.GetLoginAttempt(ctx, key)
+	if attempt.IsLocked(now) {
+		return false, nil
+	}
+	m.attempts[key] = domain.NewLoginAttempt(key, attempt.Failures(), attempt.LastFailureAt(), until)
+	return true, nil
+}
+
+type mockSettingsRepo struct {
+	domain.SettingsRepository
+
+	settings domain.SecuritySettings
+}
+
+func (m mockSettingsRepo) GetSecuritySettings(_ context.Context) (domain.SecuritySettings, error) {
+	return m.settings, nil
+}
+
+type mockTwoFactorService struct {
+	domain.TwoFactorService
+
+	validCode string
+}
+
+func (m mockTwoFactorService) DecryptSecret(_ context.Context, secret string) (string, error) {
+	return secret, nil
+}
+
+func (m mockTwoFactorService) ValidateCode(_ context.Context, _, code string) bool {
+	return code == m.validCode
+}
+
+func (m mockTwoFactorService) HashRecoveryCode(_ context.Context, code string) string {
+	return "hashed-" + code
+}
+
+type mockAuditLogger struct {
+	events *[]domain.AuditEvent
+}
+
+func (m mockAuditLogger) Record(_ context.Context, event domain.AuditEvent) {
+	if m.events != nil {
+		*m.events = append(*m.events, event)
+	}
+}
+
+type mockLoginUserPresenter struct{}
+
+func (m mockLoginUserPresenter) Output(user domain.User, token string) LoginUserOutput {
+	return LoginUserOutput{Email: user.Email(), Token: token}
+}
+
+func TestLoginUserInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	user := domain.NewUser(newUserId, "firstName", "lastName", "user@email.com", "hash", time.Now(), time.Now())
+	admin := domain.NewUser(newUserId, "firstName", "lastName", "user@email.com", "hash", time.Now(), time.Now())
+	admin.UpdateRole(domain.ADMIN)
+	deactivated := domain.NewUser(newUserId, "firstName", "lastName", "user@email.com", "hash", time.Now(), time.Now())
+	deactivated.Deactivate(time.Now())
+	twoFactorUser := domain.NewUser(newUserId, "firstName", "lastName", "user@email.com", "hash", time.Now(), time.Now())
+	twoFactorUser.UpdateTwoFactor("secret", true, []string{"hashed-recovery-1", "hashed-recovery-2"})
+	accountKey := domain.AccountAttemptKey("user@email.com")
+	ipKey := domain.IPAttemptKey("10.0.0.1")
+
+	tests := []struct {
+		name             string
+		user             *domain.User
+		settings         domain.SecuritySettings
+		input            LoginUserInput
+		attempts         map[string]domain.LoginAttempt
+		passwordCorrect  bool
+		needsRehash      bool
+		expectedPassword string
+		expected         LoginUserOutput
+		expectedError    error
+		expectedFailures map[string]int
+		expectedAudit    []string
+		expectedCodes    []string
+	}{
+		{
+			name:             "login successful resets account failures",
+			input:            LoginUserInput{Email: "user@email.com", Password: "password", IP: "10.0.0.1"},
+			attempts:         map[string]domain.LoginAttempt{accountKey: domain.NewLoginAttempt(accountKey, 2, time.Now().Add(-time.Hour), time.Time{})},
+			passwordCorrect:  true,
+			expected:         LoginUserOutput{Email: "user@email.com", Token: "token"},
+			expectedFailures: map[string]int{accountKey: 0},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:             "login with outdated hash upgrades stored password",
+			input:            LoginUserInput{Email: "user@email.com", Password: "password"},
+			attempts:         map[string]domain.LoginAttempt{},
+			passwordCorrect:  true,
+			needsRehash:      true,
+			expectedPassword: "rehashed",
+			expected:         LoginUserOutput{Email: "user@email.com", Token: "token"},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:             "wrong password records failure for account and ip",
+			input:            LoginUserInput{Email: "user@email.com", Password: "wrong", IP: "10.0.0.1"},
+			attempts:         map[string]domain.LoginAttempt{},
+			expectedError:    domain.ErrUsernameOrPasswordIncorrect,
+			expectedFailures: map[string]int{accountKey: 1, ipKey: 1},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:             "failure reaching threshold locks account",
+			input:            LoginUserInput{Email: "user@email.com", Password: "wrong"},
+			attempts:         map[string]domain.LoginAttempt{accountKey: domain.NewLoginAttempt(accountKey, 9, time.Now().Add(-30*time.Minute), time.Time{})},
+			expectedError:    domain.ErrUsernameOrPasswordIncorrect,
+			expectedFailures: map[string]int{accountKey: 10},
+			expectedAudit:    []string{domain.AuditLogin, domain.AuditAccountLocked},
+		},
+		{
+			name:             "failure after the window starts the count over",
+			input:            LoginUserInput{Email: "user@email.com", Password: "wrong", IP: "10.0.0.1"},
+			attempts:         map[string]domain.LoginAttempt{accountKey: domain.NewLoginAttempt(accountKey, 9, time.Now().Add(-2*time.Hour), time.Time{}), ipKey: domain.NewLoginAttempt(ipKey, 49, time.Now().Add(-2*time.Hour), time.Time{})},
+			expectedError:    domain.ErrUsernameOrPasswordIncorrect,
+			expectedFailures: map[string]int{accountKey: 1, ipKey: 1},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:             "failure after an expired lockout starts the count over",
+			input:            LoginUserInput{Email: "user@email.com", Password: "wrong"},
+			attempts:         map[string]domain.LoginAttempt{accountKey: domain.NewLoginAttempt(accountKey, 10, time.Now().Add(-31*time.Minute), time.Now().Add(-time.Minute))},
+			expectedError:    domain.ErrUsernameOrPasswordIncorrect,
+			expectedFailures: map[string]int{accountKey: 1},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:             "locked account refused even with correct password",
+			input:            LoginUserInput{Email: "user@email.com", Password: "password"},
+			attempts:         map[string]domain.LoginAttempt{accountKey: domain.NewLoginAttempt(accountKey, 10, time.Now(), time.Now().Add(time.Hour))},
+			passwordCorrect:  true,
+			expectedError:    domain.ErrAccountLocked,
+			expectedFailures: map[string]int{accountKey: 10},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:             "attempt during backoff refused",
+			input:            LoginUserInput{Email: "user@email.com", Password: "password"},
+			attempts:         map[string]domain.LoginAttempt{accountKey: domain.NewLoginAttempt(accountKey, 5, time.Now(), time.Time{})},
+			passwordCorrect:  true,
+			expectedError:    domain.ErrTooManyLoginAttempts,
+			expectedFailures: map[string]int{accountKey: 5},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:             "throttled ip refused",
+			input:            LoginUserInput{Email: "user@email.com", Password: "password", IP: "10.0.0.1"},
+			attempts:         map[string]domain.LoginAttempt{ipKey: domain.NewLoginAttempt(ipKey, 20, time.Now(), time.Time{})},
+			passwordCorrect:  true,
+			expectedError:    domain.ErrTooManyLoginAttempts,
+			expectedFailures: map[string]int{ipKey: 20},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:             "deactivated user refused without counting a failure",
+			user:             &deactivated,
+			input:            LoginUserInput{Email: "user@email.com", Password: "password"},
+			attempts:         map[string]domain.LoginAttempt{},
+			passwordCorrect:  true,
+			expectedError:    domain.ErrUserDeactivated,
+			expectedFailures: map[string]int{accountKey: 0},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:             "two factor user asked for a code without counting a failure",
+			user:             &twoFactorUser,
+			input:            LoginUserInput{Email: "user@email.com", Password: "password"},
+			attempts:         map[string]domain.LoginAttempt{},
+			passwordCorrect:  true,
+			expectedError:    domain.ErrTwoFactorRequired,
+			expectedFailures: map[string]int{accountKey: 0},
+		},
+		{
+			name:             "two factor user with wrong code records failure",
+			user:             &twoFactorUser,
+			input:            LoginUserInput{Email: "user@email.com", Password: "password", Code: "000000"},
+			attempts:         map[string]domain.LoginAttempt{},
+			passwordCorrect:  true,
+			expectedError:    domain.ErrInvalidTwoFactorCode,
+			expectedFailures: map[string]int{accountKey: 1},
+			expectedAudit:    []string{domain.AuditLogin},
+		},
+		{
+			name:            "two factor user with valid code logged in",
+			user:            &twoFactorUser,
+			input:           LoginUserInput{Email: "user@email.com", Password: "password", Code: "123456"},
+			attempts:        map[string]domain.LoginAttempt{},
+			passwordCorrect: true,
+			expected:        LoginUserOutput{Email: "user@email.com", Token: "token"},
+			expectedAudit:   []string{domain.AuditLogin},
+		},
+		{
+			name:            "two factor user with recovery code consumes it",
+			user:            &twoFactorUser,
+			input:           LoginUserInput{Email: "user@email.com", Password: "password", Code: "recovery-1"},
+			attempts:        map[string]domain.LoginAttempt{},
+			passwordCorrect: true,
+			expected:        LoginUserOutput{Email: "user@email.com", Token: "token"},
+			expectedCodes:   []string{"hashed-recovery-2"},
+			expectedAudit:   []string{domain.AuditLogin},
+		},
+		{
+			name:            "admin without two factor gets enrollment token when required",
+			user:            &admin,
+			settings:        domain.SecuritySettings{RequireTwoFactorForAdmin: true},
+			input:           LoginUserInput{Email: "user@email.com", Password: "password"},
+			attempts:        map[string]domain.LoginAttempt{},
+			passwordCorrect: true,
+			expected:        LoginUserOutput{Email: "user@email.com", Token: "enrollment-token", TwoFactorEnrollmentRequired: true},
+			expectedAudit:   []string{domain.AuditLogin},
+		},
+		{
+			name:            "admin without two factor logged in when not required",
+			user:            &admin,
+			input:           LoginUserInput{Email: "user@email.com", Password: "password"},
+			attempts:        map[string]domain.LoginAttempt{},
+			passwordCorrect: true,
+			expected:        LoginUserOutput{Email: "user@email.com", Token: "token"},
+			expectedAudit:   []string{domain.AuditLogin},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				events      []domain.AuditEvent
+				updated     domain.User
+				loginUser   = user
+				attemptRepo = mockLoginAttemptRepo{attempts: tt.attempts}
+			)
+			if tt.user != nil {
+				loginUser = *tt.user
+			}
+
+			var (
+				uc = NewLoginUserInteractor(
+					mockLoginUserRepo{user: loginUser, updated: &updated},
+					attemptRepo,
+					mockSettingsRepo{settings: tt.settings},
+					mockLoginAuthenticationService{
+						passwordCorrect: tt.passwordCorrect,
+						needsRehash:     tt.needsRehash,
+						token:           "token",
+						scopedToken:     "enrollment-token",
+					},
+					mockTwoFactorService{validCode: "123456"},
+					mockAuditLogger{events: &events},
+					mockLoginUserPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+
+			for key, failures := range tt.expectedFailures {
+				if attemptRepo.attempts[key].Failures() != failures {
+					t.Errorf("[TestCase '%s'] Failures for '%s': '%v' | Expected: '%v'", tt.name, key, attemptRepo.attempts[key].Failures(), failures)
+				}
+			}
+
+			if tt.expectedCodes != nil && !reflect.DeepEqual(updated.RecoveryCodes(), tt.expectedCodes) {
+				t.Errorf("[TestCase '%s'] Recovery codes: '%v' | Expected: '%v'", tt.name, updated.RecoveryCodes(), tt.expectedCodes)
+			}
+
+			if tt.expectedCodes == nil && updated.Password() != tt.expectedPassword {
+				t.Errorf("[TestCase '%s'] Stored password: '%v' | Expected: '%v'", tt.name, updated.Password(), tt.expectedPassword)
+			}
+
+			var actions []string
+			for _, event := range events {
+				actions = append(actions, event.Action)
+			}
+			if !reflect.DeepEqual(actions, tt.expectedAudit) {
+				t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, actions, tt.expectedAudit)
+			}
+		})
+	}
+}
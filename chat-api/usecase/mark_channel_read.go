@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	// Input port
+	MarkChannelReadUseCase interface {
+		Execute(context.Context, MarkChannelReadInput) (MarkChannelReadOutput, error)
+	}
+
+	// Input data, the channel is read up to the message with MessageId or
+	// entirely when it is empty
+	MarkChannelReadInput struct {
+		ChannelId string `json:"channelId" validate:"required"`
+		MessageId string `json:"messageId"`
+		// Reader is the authenticated caller, Admin lets them read channels they
+		// do not take part in and see internal notes
+		Reader string `json:"-" validate:"required"`
+		Admin  bool   `json:"-"`
+	}
+
+	// Output port
+	MarkChannelReadPresenter interface {
+		Output(domain.Channel, domain.ReadMarker) MarkChannelReadOutput
+	}
+
+	// Output data
+	MarkChannelReadOutput struct {
+		ChannelId   string    `json:"channelId"`
+		Participant string    `json:"participant"`
+		LastReadAt  time.Time `json:"lastReadAt"`
+		UnreadCount int       `json:"unreadCount"`
+	}
+
+	markChannelReadInteractor struct {
+		repo       domain.ChannelRepository
+		notifier   domain.ChannelNotifier
+		presenter  MarkChannelReadPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewMarkChannelReadInteractor(
+	repo domain.ChannelRepository,
+	notifier domain.ChannelNotifier,
+	presenter MarkChannelReadPresenter,
+	t time.Duration,
+) MarkChannelReadUseCase {
+	return markChannelReadInteractor{
+		repo:       repo,
+		notifier:   notifier,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a markChannelReadInteractor) Execute(ctx context.Context, input MarkChannelReadInput) (MarkChannelReadOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	channel, err := a.repo.GetChannelById(ctx, input.ChannelId)
+	if err != nil {
+		return a.presenter.Output(domain.Channel{}, domain.ReadMarker{}), err
+	}
+	if !input.Admin {
+		if !channel.IsParticipant(input.Reader) {
+			return a.presenter.Output(domain.Channel{}, domain.ReadMarker{}), domain.ErrNotChannelParticipant
+		}
+		// Internal notes neither count as unread nor can be read up to
+		channel = channel.WithoutInternalNotes()
+	}
+
+	// Databases keep times to the millisecond, the marker answered is the one stored
+	readAt := time.Now().Truncate(time.Millisecond)
+	if input.MessageId != "" {
+		id, err := primitive.ObjectIDFromHex(input.MessageId)
+		if err != nil {
+			return a.presenter.Output(domain.Channel{}, domain.ReadMarker{}), domain.ErrMessageNotFound
+		}
+		message, err := channel.Message(id)
+		if err != nil {
+			return a.presenter.Output(domain.Channel{}, domain.ReadMarker{}), err
+		}
+		readAt = message.Timestamp
+	}
+
+	// Markers only move forward, reading an older message again changes nothing
+	marker, moved := channel.MarkRead(input.Reader, readAt)
+	if !moved {
+		return a.presenter.Output(channel, marker), nil
+	}
+
+	if err := a.repo.UpdateReadMarkers(ctx, channel); err != nil {
+		return a.presenter.Output(domain.Channel{}, domain.ReadMarker{}), err
+	}
+
+	a.notifier.Notify(ctx, domain.ChannelEvent{
+		Type:        domain.ChannelEventRead,
+		ChannelId:   channel.Id().Hex(),
+		UserEmail:   channel.UserEmail(),
+		MessageFrom: marker.Participant,
+		Timestamp:   marker.LastReadAt,
+	})
+
+	return a.presenter.Output(channel, marker), nil
+}
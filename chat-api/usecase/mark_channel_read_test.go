@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockMarkChannelReadPresenter struct{}
+
+func (m mockMarkChannelReadPresenter) Output(channel domain.Channel, marker domain.ReadMarker) MarkChannelReadOutput {
+	return MarkChannelReadOutput{
+		ChannelId:   channel.Id().Hex(),
+		Participant: marker.Participant,
+		LastReadAt:  marker.LastReadAt,
+		UnreadCount: channel.CountUnread(marker.Participant),
+	}
+}
+
+// readChannel holds two messages of user@email.com around one of rep@email.com
+func readChannel(now time.Time) domain.Channel {
+	channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.IN_PROGRESS, now, now)
+	channel.UpdateRepEmail("rep@email.com")
+	channel.AddMessage("user@email.com", "helo", now.Add(-3*time.Minute))
+	channel.AddMessage("rep@email.com", "hi", now.Add(-2*time.Minute))
+	channel.AddMessage("user@email.com", "thanks", now.Add(-time.Minute))
+	return channel
+}
+
+func TestMarkChannelReadInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		reader        string
+		admin         bool
+		message       int
+		note          bool
+		readBefore    time.Time
+		expectedRead  time.Time
+		expectedCount int
+		expectStored  bool
+		expectedError error
+	}{
+		{
+			name:          "rep reads up to a message",
+			reader:        "rep@email.com",
+			message:       1,
+			expectedRead:  now.Add(-2 * time.Minute),
+			expectedCount: 1,
+			expectStored:  true,
+		},
+		{
+			name:         "customer reads the whole channel",
+			reader:       "User@Email.com",
+			message:      -1,
+			expectStored: true,
+		},
+		{
+			name:          "reading an older message again",
+			reader:        "rep@email.com",
+			message:       1,
+			readBefore:    now.Add(-time.Minute),
+			expectedRead:  now.Add(-time.Minute),
+			expectedCount: 0,
+		},
+		{
+			name:          "admin not taking part",
+			reader:        "admin@email.com",
+			admin:         true,
+			message:       0,
+			expectedRead:  now.Add(-3 * time.Minute),
+			expectedCount: 2,
+			expectStored:  true,
+		},
+		{
+			name:          "someone else",
+			reader:        "other@email.com",
+			message:       -1,
+			expectedError: domain.ErrNotChannelParticipant,
+		},
+		{
+			name:          "unknown message",
+			reader:        "rep@email.com",
+			message:       3,
+			expectedError: domain.ErrMessageNotFound,
+		},
+		{
+			name:          "customer reads up to an internal note",
+			reader:        "user@email.com",
+			message:       3,
+			note:          true,
+			expectedError: domain.ErrMessageNotFound,
+		},
+		{
+			name:          "admin reads up to an internal note",
+			reader:        "admin@email.com",
+			admin:         true,
+			message:       3,
+			note:          true,
+			expectedRead:  now.Add(-30 * time.Second),
+			expectedCount: 0,
+			expectStored:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := readChannel(now)
+			if tt.note {
+				channel.AddNote("rep@email.com", "customer asked twice", now.Add(-30*time.Second))
+			}
+			if !tt.readBefore.IsZero() {
+				channel.MarkRead(tt.reader, tt.readBefore)
+			}
+
+			input := MarkChannelReadInput{ChannelId: channel.Id().Hex(), Reader: tt.reader, Admin: tt.admin}
+			switch {
+			case tt.note:
+				input.MessageId = channel.Messages()[tt.message].Id.Hex()
+			case tt.message >= len(channel.Messages()):
+				input.MessageId = primitive.NewObjectID().Hex()
+			case tt.message >= 0:
+				input.MessageId = channel.Messages()[tt.message].Id.Hex()
+			}
+
+			var (
+				updated  domain.Channel
+				notified []domain.ChannelEvent
+				uc       = NewMarkChannelReadInteractor(
+					mockMessageRepo{channel: channel, updated: &updated},
+					mockChannelNotifier{events: &notified},
+					mockMarkChannelReadPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if tt.expectedRead.IsZero() {
+				// Reading the whole channel marks it read as of now
+				if got.LastReadAt.Before(now.Truncate(time.Millisecond)) {
+					t.Errorf("[TestCase '%s'] Result: '%v' | Expected: after '%v'", tt.name, got.LastReadAt, now)
+				}
+			} else if !got.LastReadAt.Equal(tt.expectedRead) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got.LastReadAt, tt.expectedRead)
+			}
+			if got.Participant != domain.NormalizeEmail(tt.reader) || got.UnreadCount != tt.expectedCount {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%d' unread for '%s'", tt.name, got, tt.expectedCount, tt.reader)
+			}
+
+			if !tt.expectStored {
+				if len(notified) != 0 || !updated.Id().IsZero() {
+					t.Errorf("[TestCase '%s'] Notified: '%v' | Expected: no change stored nor notified", tt.name, notified)
+				}
+				return
+			}
+			if !updated.LastReadAt(tt.reader).Equal(got.LastReadAt) {
+				t.Errorf("[TestCase '%s'] Stored: '%v' | Expected: '%v'", tt.name, updated.ReadMarkers(), got.LastReadAt)
+			}
+			expected := []domain.ChannelEvent{{
+				Type:        domain.ChannelEventRead,
+				ChannelId:   channel.Id().Hex(),
+				UserEmail:   "user@email.com",
+				MessageFrom: got.Participant,
+				Timestamp:   got.LastReadAt,
+			}}
+			if !reflect.DeepEqual(notified, expected) {
+				t.Errorf("[TestCase '%s'] Notified: '%v' | Expected: '%v'", tt.name, notified, expected)
+			}
+		})
+	}
+}
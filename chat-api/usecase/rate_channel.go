@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	RateChannelUseCase interface {
+		Execute(context.Context, RateChannelInput) (RateChannelOutput, error)
+	}
+
+	// Input data, the answer of the customer to the survey sent when the
+	// channel was completed
+	RateChannelInput struct {
+		ChannelId string `json:"channelId" validate:"required"`
+		Score     int    `json:"score" validate:"required,min=1,max=5"`
+		Comment   string `json:"comment" validate:"max=1000"`
+		// RatedBy is the authenticated customer, only they rate their channel
+		RatedBy string `json:"-" validate:"required"`
+	}
+
+	// Output port
+	RateChannelPresenter interface {
+		Output(domain.SatisfactionRating) RateChannelOutput
+	}
+
+	// Output data
+	RateChannelOutput struct {
+		ChannelId  string    `json:"channelId"`
+		Completion int       `json:"completion"`
+		Score      int       `json:"score"`
+		Comment    string    `json:"comment"`
+		RatedAt    time.Time `json:"ratedAt"`
+	}
+
+	rateChannelInteractor struct {
+		repo       domain.ChannelRepository
+		presenter  RateChannelPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewRateChannelInteractor(
+	repo domain.ChannelRepository,
+	presenter RateChannelPresenter,
+	t time.Duration,
+) RateChannelUseCase {
+	return rateChannelInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a rateChannelInteractor) Execute(ctx context.Context, input RateChannelInput) (RateChannelOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	channel, err := a.repo.GetChannelById(ctx, input.ChannelId)
+	if err != nil {
+		return a.presenter.Output(domain.SatisfactionRating{}), err
+	}
+	if domain.NormalizeEmail(input.RatedBy) != domain.NormalizeEmail(channel.UserEmail()) {
+		return a.presenter.Output(domain.SatisfactionRating{}), domain.ErrNotChannelParticipant
+	}
+
+	// Databases keep times to the millisecond, the rating answered is the one stored
+	rating, err := channel.Rate(input.Score, input.Comment, input.RatedBy, time.Now().Truncate(time.Millisecond))
+	if err != nil {
+		return a.presenter.Output(domain.SatisfactionRating{}), err
+	}
+
+	if err := a.repo.UpdateRatings(ctx, channel); err != nil {
+		return a.presenter.Output(domain.SatisfactionRating{}), err
+	}
+
+	return a.presenter.Output(rating), nil
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockRatingChannelRepo struct {
+	domain.ChannelRepository
+
+	channel domain.Channel
+	ratings *[]domain.SatisfactionRating
+}
+
+func (m mockRatingChannelRepo) GetChannelById(_ context.Context, _ string) (domain.Channel, error) {
+	return m.channel, nil
+}
+
+func (m mockRatingChannelRepo) UpdateRatings(_ context.Context, channel domain.Channel) error {
+	*m.ratings = channel.Ratings()
+	return nil
+}
+
+func (m mockRatingChannelRepo) GetSatisfactionRatings(_ context.Context, query domain.SatisfactionQuery) ([]domain.SatisfactionRating, error) {
+	var found []domain.SatisfactionRating
+	for _, rating := range *m.ratings {
+		if !rating.RatedAt.Before(query.From) && rating.RatedAt.Before(query.To) {
+			found = append(found, rating)
+		}
+	}
+	return found, nil
+}
+
+type mockRateChannelPresenter struct{}
+
+func (m mockRateChannelPresenter) Output(rating domain.SatisfactionRating) RateChannelOutput {
+	return RateChannelOutput{Completion: rating.Completion, Score: rating.Score, Comment: rating.Comment}
+}
+
+func TestRateChannelInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	// newChannel completes a channel, the rated one is rated and then completed again
+	newChannel := func(status string, rated bool) domain.Channel {
+		channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, time.Now(), time.Now())
+		channel.UpdateRepEmail("rep@email.com")
+		channel.UpdateStatus(domain.COMPLETE, "rep@email.com", time.Now().Unix())
+		if rated {
+			_, _ = channel.Rate(4, "", "user@email.com", time.Now())
+			channel.UpdateStatus(domain.COMPLETE, "rep@email.com", time.Now().Unix())
+		}
+		if status != domain.COMPLETE {
+			channel.UpdateStatus(status, "rep@email.com", time.Now().Unix())
+		}
+		return channel
+	}
+
+	tests := []struct {
+		name          string
+		channel       domain.Channel
+		input         RateChannelInput
+		expected      RateChannelOutput
+		expectedError error
+	}{
+		{
+			name:     "customer rates",
+			channel:  newChannel(domain.COMPLETE, false),
+			input:    RateChannelInput{Score: 5, Comment: " quick answer ", RatedBy: "User@Email.com"},
+			expected: RateChannelOutput{Completion: 1, Score: 5, Comment: "quick answer"},
+		},
+		{
+			name:     "completed again",
+			channel:  newChannel(domain.COMPLETE, true),
+			input:    RateChannelInput{Score: 2, RatedBy: "user@email.com"},
+			expected: RateChannelOutput{Completion: 2, Score: 2},
+		},
+		{
+			name:          "already rated",
+			channel:       newChannel(domain.COMPLETE, false),
+			input:         RateChannelInput{Score: 5, RatedBy: "user@email.com"},
+			expectedError: domain.ErrAlreadyRated,
+		},
+		{
+			name:          "reopened",
+			channel:       newChannel(domain.IN_PROGRESS, false),
+			input:         RateChannelInput{Score: 5, RatedBy: "user@email.com"},
+			expectedError: domain.ErrChannelNotComplete,
+		},
+		{
+			name:          "rep rating",
+			channel:       newChannel(domain.COMPLETE, false),
+			input:         RateChannelInput{Score: 5, RatedBy: "rep@email.com"},
+			expectedError: domain.ErrNotChannelParticipant,
+		},
+		{
+			name:          "score out of range",
+			channel:       newChannel(domain.COMPLETE, false),
+			input:         RateChannelInput{Score: 6, RatedBy: "user@email.com"},
+			expectedError: domain.ErrInvalidRating,
+		},
+	}
+	// The channel of "already rated" was rated for its only completion
+	_, _ = tests[2].channel.Rate(3, "", "user@email.com", time.Now())
+
+	for _, tt := range tests {
+		var (
+			ratings []domain.SatisfactionRating
+			uc      = NewRateChannelInteractor(mockRatingChannelRepo{channel: tt.channel, ratings: &ratings}, mockRateChannelPresenter{}, time.Second)
+		)
+
+		tt.input.ChannelId = tt.channel.Id().Hex()
+		got, err := uc.Execute(context.Background(), tt.input)
+		if err != tt.expectedError {
+			t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+		}
+		if stored := len(ratings) > 0; stored != (err == nil) {
+			t.Errorf("[TestCase '%s'] Stored: '%v' | Expected: '%v'", tt.name, stored, err == nil)
+		}
+	}
+}
+
+func TestGetSatisfactionReportInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		// A Wednesday, the week of the ratings starts on Monday the 8th
+		day     = time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+		ratings = []domain.SatisfactionRating{
+			{Score: 5, RepEmail: "rep@email.com", Tags: []string{"refund", "vip"}, RatedAt: day},
+			{Score: 2, RepEmail: "other@email.com", Tags: []string{"refund"}, RatedAt: day.Add(24 * time.Hour)},
+			{Score: 4, RatedAt: day.AddDate(0, 1, 0)},
+		}
+		uc = NewGetSatisfactionReportInteractor(mockRatingChannelRepo{ratings: &ratings}, mockGetSatisfactionReportPresenter{}, time.Second)
+	)
+
+	tests := []struct {
+		name            string
+		input           GetSatisfactionReportInput
+		expected        map[string]int
+		expectedOverall int
+		expectedError   error
+	}{
+		{
+			name:            "by rep",
+			input:           GetSatisfactionReportInput{GroupBy: domain.SatisfactionByRep, From: day, To: day.AddDate(0, 2, 0)},
+			expected:        map[string]int{"rep@email.com": 1, "other@email.com": 1, "": 1},
+			expectedOverall: 3,
+		},
+		{
+			name:            "by tag",
+			input:           GetSatisfactionReportInput{GroupBy: domain.SatisfactionByTag, From: day, To: day.AddDate(0, 0, 7)},
+			expected:        map[string]int{"refund": 2, "vip": 1},
+			expectedOverall: 2,
+		},
+		{
+			name:            "by week",
+			input:           GetSatisfactionReportInput{GroupBy: domain.SatisfactionByWeek, From: day, To: day.AddDate(0, 2, 0)},
+			expected:        map[string]int{"2024-01-08": 2, "2024-02-05": 1},
+			expectedOverall: 3,
+		},
+		{
+			name:            "by month",
+			input:           GetSatisfactionReportInput{GroupBy: domain.SatisfactionByMonth, From: day, To: day.AddDate(0, 2, 0)},
+			expected:        map[string]int{"2024-01": 2, "2024-02": 1},
+			expectedOverall: 3,
+		},
+		{
+			name:          "range ending before it starts",
+			input:         GetSatisfactionReportInput{GroupBy: domain.SatisfactionByDay, From: day, To: day.Add(-time.Hour)},
+			expectedError: domain.ErrInvalidTimeRange,
+		},
+		{
+			name:          "range too long",
+			input:         GetSatisfactionReportInput{GroupBy: domain.SatisfactionByDay, From: day, To: day.AddDate(2, 0, 0)},
+			expectedError: domain.ErrReportRangeTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := uc.Execute(context.Background(), tt.input)
+		if err != tt.expectedError {
+			t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		groups := make(map[string]int)
+		for _, group := range got.Groups {
+			groups[group.Key] = group.Responses
+		}
+		if len(groups) != len(tt.expected) || got.Overall.Responses != tt.expectedOverall {
+			t.Errorf("[TestCase '%s'] Result: '%v', '%v' | Expected: '%v', '%v'", tt.name, groups, got.Overall.Responses, tt.expected, tt.expectedOverall)
+		}
+		for key, responses := range tt.expected {
+			if groups[key] != responses {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, groups, tt.expected)
+			}
+		}
+	}
+}
+
+type mockGetSatisfactionReportPresenter struct{}
+
+func (m mockGetSatisfactionReportPresenter) Output(groupBy string, _ domain.SatisfactionQuery, overall domain.SatisfactionGroup, groups []domain.SatisfactionGroup) GetSatisfactionReportOutput {
+	var o []SatisfactionGroupOutput
+	for _, group := range groups {
+		o = append(o, SatisfactionGroupOutput{Key: group.Key, Responses: group.Responses, Average: group.Average(), CSAT: group.CSAT()})
+	}
+	return GetSatisfactionReportOutput{GroupBy: groupBy, Overall: SatisfactionGroupOutput{Responses: overall.Responses, CSAT: overall.CSAT()}, Groups: o}
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	RevokeAPIKeyUseCase interface {
+		Execute(context.Context, RevokeAPIKeyInput) (APIKeyOutput, error)
+	}
+
+	// Input data
+	RevokeAPIKeyInput struct {
+		Id        string `json:"id" validate:"required"`
+		RevokedBy string `json:"-"`
+		IP        string `json:"-"`
+	}
+
+	// Output port
+	RevokeAPIKeyPresenter interface {
+		Output(domain.APIKey) APIKeyOutput
+	}
+
+	revokeAPIKeyInteractor struct {
+		repo       domain.APIKeyRepository
+		audit      domain.AuditLogger
+		presenter  RevokeAPIKeyPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewRevokeAPIKeyInteractor(
+	repo domain.APIKeyRepository,
+	audit domain.AuditLogger,
+	presenter RevokeAPIKeyPresenter,
+	t time.Duration,
+) RevokeAPIKeyUseCase {
+	return revokeAPIKeyInteractor{
+		repo:       repo,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (r revokeAPIKeyInteractor) Execute(ctx context.Context, input RevokeAPIKeyInput) (APIKeyOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	key, err := r.repo.GetAPIKeyById(ctx, input.Id)
+	if err != nil {
+		return r.presenter.Output(domain.APIKey{}), err
+	}
+
+	// Revoking twice keeps the original revocation time
+	if key.IsRevoked() {
+		return r.presenter.Output(key), nil
+	}
+
+	key.Revoke(time.Now())
+	if err := r.repo.RevokeAPIKey(ctx, key); err != nil {
+		return r.presenter.Output(domain.APIKey{}), err
+	}
+
+	r.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditAPIKeyRevoked,
+		Actor:     input.RevokedBy,
+		Target:    key.Id().Hex(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return r.presenter.Output(key), nil
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	SearchChannelsUseCase interface {
+		Execute(context.Context, SearchChannelsInput) (SearchChannelsOutput, error)
+	}
+
+	// Input data, the created range includes From and excludes To
+	SearchChannelsInput struct {
+		Text        string    `json:"q" validate:"required,max=200"`
+		RepEmail    string    `json:"repEmail"`
+		Statuses    []string  `json:"statuses" validate:"dive,oneof=INACTIVE ACTIVE IN_PROGRESS COMPLETE"`
+		CreatedFrom time.Time `json:"createdFrom"`
+		CreatedTo   time.Time `json:"createdTo"`
+		Page        int       `json:"page" validate:"min=1"`
+		Limit       int       `json:"limit" validate:"min=1,max=50"`
+	}
+
+	// Output port
+	SearchChannelsPresenter interface {
+		Output([]domain.SearchHit, int, int, int) SearchChannelsOutput
+	}
+
+	SearchHighlightOutput struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
+	}
+
+	// SearchSnippetOutput highlights are character offsets in Text
+	SearchSnippetOutput struct {
+		Field      string                  `json:"field"`
+		Text       string                  `json:"text"`
+		Highlights []SearchHighlightOutput `json:"highlights"`
+	}
+
+	SearchChannelOutput struct {
+		Id            string                `json:"id"`
+		UserEmail     string                `json:"userEmail"`
+		UserFullName  string                `json:"userFullName"`
+		RepEmail      string                `json:"repEmail"`
+		CurrentStatus string                `json:"currentStatus"`
+		CreatedAt     time.Time             `json:"createdAt"`
+		LastMessageAt *time.Time            `json:"lastMessageAt,omitempty"`
+		Snippets      []SearchSnippetOutput `json:"snippets"`
+	}
+
+	// Output data
+	SearchChannelsOutput struct {
+		Page       int                   `json:"page"`
+		Count      int                   `json:"count"`
+		Limit      int                   `json:"limit"`
+		TotalCount int                   `json:"totalCount"`
+		Data       []SearchChannelOutput `json:"data"`
+	}
+
+	searchChannelsInteractor struct {
+		index      domain.SearchIndex
+		presenter  SearchChannelsPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewSearchChannelsInteractor(
+	index domain.SearchIndex,
+	presenter SearchChannelsPresenter,
+	t time.Duration,
+) SearchChannelsUseCase {
+	return searchChannelsInteractor{
+		index:      index,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a searchChannelsInteractor) Execute(ctx context.Context, input SearchChannelsInput) (SearchChannelsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	query, err := input.searchQuery()
+	if err != nil {
+		return a.presenter.Output([]domain.SearchHit{}, 0, 0, 0), err
+	}
+
+	hits, total, err := a.index.SearchChannels(ctx, query)
+	if err != nil {
+		return a.presenter.Output([]domain.SearchHit{}, 0, 0, 0), err
+	}
+
+	return a.presenter.Output(hits, input.Page, input.Limit, int(total)), nil
+}
+
+func (i SearchChannelsInput) searchQuery() (domain.SearchQuery, error) {
+	if len(domain.SearchTerms(i.Text)) == 0 {
+		return domain.SearchQuery{}, domain.ErrEmptySearch
+	}
+	if !i.CreatedFrom.IsZero() && !i.CreatedTo.IsZero() && !i.CreatedTo.After(i.CreatedFrom) {
+		return domain.SearchQuery{}, domain.ErrInvalidTimeRange
+	}
+
+	return domain.SearchQuery{
+		Text:        i.Text,
+		RepEmail:    i.RepEmail,
+		Statuses:    i.Statuses,
+		CreatedFrom: i.CreatedFrom,
+		CreatedTo:   i.CreatedTo,
+		Skip:        (i.Page - 1) * i.Limit,
+		Limit:       i.Limit,
+	}, nil
+}
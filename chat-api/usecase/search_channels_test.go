@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockSearchIndex struct {
+	indexed *[]domain.Channel
+
+	hits  []domain.SearchHit
+	total int64
+	query *domain.SearchQuery
+}
+
+func (m mockSearchIndex) IndexChannel(_ context.Context, channel domain.Channel) {
+	if m.indexed != nil {
+		*m.indexed = append(*m.indexed, channel)
+	}
+}
+
+func (m mockSearchIndex) SearchChannels(_ context.Context, query domain.SearchQuery) ([]domain.SearchHit, int64, error) {
+	*m.query = query
+	return m.hits, m.total, nil
+}
+
+type mockSearchChannelsPresenter struct{}
+
+func (m mockSearchChannelsPresenter) Output(hits []domain.SearchHit, page, limit, totalCount int) SearchChannelsOutput {
+	output := SearchChannelsOutput{Page: page, Count: len(hits), Limit: limit, TotalCount: totalCount}
+	for _, hit := range hits {
+		output.Data = append(output.Data, SearchChannelOutput{Id: hit.Channel.Id().Hex()})
+	}
+	return output
+}
+
+func TestSearchChannelsInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		from    = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		to      = from.Add(24 * time.Hour)
+		channel = domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, from, from)
+	)
+
+	tests := []struct {
+		name          string
+		input         SearchChannelsInput
+		expected      SearchChannelsOutput
+		expectedQuery domain.SearchQuery
+		expectedError error
+	}{
+		{
+			name: "second page of a filtered search",
+			input: SearchChannelsInput{
+				Text:        "refund 4411",
+				RepEmail:    "rep@email.com",
+				Statuses:    []string{domain.COMPLETE},
+				CreatedFrom: from,
+				CreatedTo:   to,
+				Page:        2,
+				Limit:       10,
+			},
+			expected: SearchChannelsOutput{
+				Page:       2,
+				Count:      1,
+				Limit:      10,
+				TotalCount: 11,
+				Data:       []SearchChannelOutput{{Id: channel.Id().Hex()}},
+			},
+			expectedQuery: domain.SearchQuery{
+				Text:        "refund 4411",
+				RepEmail:    "rep@email.com",
+				Statuses:    []string{domain.COMPLETE},
+				CreatedFrom: from,
+				CreatedTo:   to,
+				Skip:        10,
+				Limit:       10,
+			},
+		},
+		{
+			name:          "text without words refused",
+			input:         SearchChannelsInput{Text: "?! --", Page: 1, Limit: 10},
+			expectedError: domain.ErrEmptySearch,
+		},
+		{
+			name:          "range ending before it starts refused",
+			input:         SearchChannelsInput{Text: "refund", CreatedFrom: to, CreatedTo: from, Page: 1, Limit: 10},
+			expectedError: domain.ErrInvalidTimeRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				query domain.SearchQuery
+				uc    = NewSearchChannelsInteractor(
+					mockSearchIndex{hits: []domain.SearchHit{{Channel: channel}}, total: 11, query: &query},
+					mockSearchChannelsPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+			if !reflect.DeepEqual(query, tt.expectedQuery) {
+				t.Errorf("[TestCase '%s'] Query: '%v' | Expected: '%v'", tt.name, query, tt.expectedQuery)
+			}
+		})
+	}
+}
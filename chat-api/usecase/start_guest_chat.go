@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	// Input port
+	StartGuestChatUseCase interface {
+		Execute(context.Context, StartGuestChatInput) (StartGuestChatOutput, error)
+	}
+
+	// Input data, the attributes tell reps where the guest writes from
+	StartGuestChatInput struct {
+		FirstName  string            `json:"firstName" validate:"required"`
+		LastName   string            `json:"lastName"`
+		Email      string            `json:"email" validate:"required,email"`
+		Attributes map[string]string `json:"attributes" validate:"omitempty,max=20"`
+	}
+
+	// Output port
+	StartGuestChatPresenter interface {
+		Output(CreateChannelOutput, string) StartGuestChatOutput
+	}
+
+	// Output data
+	StartGuestChatOutput struct {
+		ChannelId     string `json:"channelId"`
+		Email         string `json:"email"`
+		CurrentStatus string `json:"currentStatus"`
+		Token         string `json:"token"`
+	}
+
+	startGuestChatInteractor struct {
+		repo          domain.UserRepository
+		createChannel CreateChannelUseCase
+		service       domain.AuthenticationUtilityService
+		presenter     StartGuestChatPresenter
+		ctxTimeout    time.Duration
+	}
+)
+
+func NewStartGuestChatInteractor(
+	repo domain.UserRepository,
+	createChannel CreateChannelUseCase,
+	service domain.AuthenticationUtilityService,
+	presenter StartGuestChatPresenter,
+	t time.Duration,
+) StartGuestChatUseCase {
+	return startGuestChatInteractor{
+		repo:          repo,
+		createChannel: createChannel,
+		service:       service,
+		presenter:     presenter,
+		ctxTimeout:    t,
+	}
+}
+
+// Execute orchestrates the use case
+func (s startGuestChatInteractor) Execute(ctx context.Context, input StartGuestChatInput) (StartGuestChatOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ctxTimeout)
+	defer cancel()
+
+	email := domain.NormalizeEmail(input.Email)
+
+	// Account holders have to log in, otherwise anyone could post into their history
+	_, err := s.repo.GetUserByEmail(ctx, email)
+	switch err {
+	case nil:
+		return s.presenter.Output(CreateChannelOutput{}, ""), domain.ErrUserAlreadyExists
+	case domain.ErrUserNotFound:
+	default:
+		return s.presenter.Output(CreateChannelOutput{}, ""), err
+	}
+
+	fullName := strings.TrimSpace(input.FirstName + " " + input.LastName)
+	channel, err := s.createChannel.Execute(ctx, CreateChannelInput{
+		UserEmail:    email,
+		UserFullName: fullName,
+		Attributes:   input.Attributes,
+		Guest:        true,
+	})
+	if err != nil {
+		return s.presenter.Output(CreateChannelOutput{}, ""), err
+	}
+
+	guest := domain.NewUser(primitive.NilObjectID, input.FirstName, input.LastName, email, "", time.Now(), time.Now())
+	guest.UpdateRole(domain.GUEST)
+
+	token, err := s.service.GenerateGuestToken(ctx, guest, channel.Id)
+	if err != nil {
+		return s.presenter.Output(CreateChannelOutput{}, ""), err
+	}
+
+	return s.presenter.Output(channel, token), nil
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockGuestCreateChannel struct {
+	input *CreateChannelInput
+}
+
+func (m mockGuestCreateChannel) Execute(_ context.Context, input CreateChannelInput) (CreateChannelOutput, error) {
+	*m.input = input
+	return CreateChannelOutput{Id: "channel-id", UserEmail: input.UserEmail, CurrentStatus: domain.ACTIVE}, nil
+}
+
+type mockGuestAuthenticationService struct {
+	domain.AuthenticationUtilityService
+
+	user      *domain.User
+	channelId *string
+}
+
+func (m mockGuestAuthenticationService) GenerateGuestToken(_ context.Context, user domain.User, channelId string) (string, error) {
+	*m.user = user
+	*m.channelId = channelId
+	return "guest-token", nil
+}
+
+type mockStartGuestChatPresenter struct{}
+
+func (m mockStartGuestChatPresenter) Output(channel CreateChannelOutput, token string) StartGuestChatOutput {
+	return StartGuestChatOutput{ChannelId: channel.Id, Email: channel.UserEmail, CurrentStatus: channel.CurrentStatus, Token: token}
+}
+
+func TestStartGuestChatInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	registered := domain.NewUser(newUserId, "firstName", "lastName", "guest@email.com", "hash", time.Now(), time.Now())
+
+	tests := []struct {
+		name          string
+		user          domain.User
+		userErr       error
+		input         StartGuestChatInput
+		expected      StartGuestChatOutput
+		expectedError error
+		expectedInput CreateChannelInput
+	}{
+		{
+			name:          "guest gets a channel and a token bound to it",
+			userErr:       domain.ErrUserNotFound,
+			input:         StartGuestChatInput{FirstName: "Jane", LastName: "Doe", Email: "Guest@Email.com"},
+			expected:      StartGuestChatOutput{ChannelId: "channel-id", Email: "guest@email.com", CurrentStatus: domain.ACTIVE, Token: "guest-token"},
+			expectedInput: CreateChannelInput{UserEmail: "guest@email.com", UserFullName: "Jane Doe", Guest: true},
+		},
+		{
+			name:          "registered email must log in",
+			user:          registered,
+			input:         StartGuestChatInput{FirstName: "Jane", Email: "guest@email.com"},
+			expectedError: domain.ErrUserAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				channelInput CreateChannelInput
+				guest        domain.User
+				channelId    string
+				uc           = NewStartGuestChatInteractor(
+					mockLoginUserRepo{user: tt.user, err: tt.userErr},
+					mockGuestCreateChannel{input: &channelInput},
+					mockGuestAuthenticationService{user: &guest, channelId: &channelId},
+					mockStartGuestChatPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+
+			if !reflect.DeepEqual(channelInput, tt.expectedInput) {
+				t.Errorf("[TestCase '%s'] Channel input: '%v' | Expected: '%v'", tt.name, channelInput, tt.expectedInput)
+			}
+
+			if err == nil && (guest.Role() != domain.GUEST || channelId != "channel-id") {
+				t.Errorf("[TestCase '%s'] Token issued for role '%v' and channel '%v'", tt.name, guest.Role(), channelId)
+			}
+		})
+	}
+}
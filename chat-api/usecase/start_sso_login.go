@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	StartSSOLoginUseCase interface {
+		Execute(context.Context) (StartSSOLoginOutput, error)
+	}
+
+	// Output port
+	StartSSOLoginPresenter interface {
+		Output(domain.SSOState, string) StartSSOLoginOutput
+	}
+
+	// Output data
+	StartSSOLoginOutput struct {
+		AuthorizationURL string `json:"authorizationUrl"`
+		State            string `json:"state"`
+	}
+
+	startSSOLoginInteractor struct {
+		stateRepo  domain.SSOStateRepository
+		provider   domain.IdentityProvider
+		presenter  StartSSOLoginPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewStartSSOLoginInteractor(
+	stateRepo domain.SSOStateRepository,
+	provider domain.IdentityProvider,
+	presenter StartSSOLoginPresenter,
+	t time.Duration,
+) StartSSOLoginUseCase {
+	return startSSOLoginInteractor{
+		stateRepo:  stateRepo,
+		provider:   provider,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (s startSSOLoginInteractor) Execute(ctx context.Context) (StartSSOLoginOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.ctxTimeout)
+	defer cancel()
+
+	state, authorizationURL, err := s.provider.AuthorizationRequest(ctx)
+	if err != nil {
+		return s.presenter.Output(domain.SSOState{}, ""), err
+	}
+
+	if err := s.stateRepo.CreateSSOState(ctx, state); err != nil {
+		return s.presenter.Output(domain.SSOState{}, ""), err
+	}
+
+	return s.presenter.Output(state, authorizationURL), nil
+}
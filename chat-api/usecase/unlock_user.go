@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	UnlockUserUseCase interface {
+		Execute(context.Context, UnlockUserInput) (UnlockUserOutput, error)
+	}
+
+	// Input data
+	UnlockUserInput struct {
+		Email      string `json:"email" validate:"required"`
+		UnlockedBy string `json:"-"`
+		IP         string `json:"-"`
+	}
+
+	// Output port
+	UnlockUserPresenter interface {
+		Output(domain.User) UnlockUserOutput
+	}
+
+	// Output data
+	UnlockUserOutput struct {
+		Email    string `json:"email"`
+		Unlocked bool   `json:"unlocked"`
+	}
+
+	unlockUserInteractor struct {
+		repo        domain.UserRepository
+		attemptRepo domain.LoginAttemptRepository
+		audit       domain.AuditLogger
+		presenter   UnlockUserPresenter
+		ctxTimeout  time.Duration
+	}
+)
+
+func NewUnlockUserInteractor(
+	repo domain.UserRepository,
+	attemptRepo domain.LoginAttemptRepository,
+	audit domain.AuditLogger,
+	presenter UnlockUserPresenter,
+	t time.Duration,
+) UnlockUserUseCase {
+	return unlockUserInteractor{
+		repo:        repo,
+		attemptRepo: attemptRepo,
+		audit:       audit,
+		presenter:   presenter,
+		ctxTimeout:  t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a unlockUserInteractor) Execute(ctx context.Context, input UnlockUserInput) (UnlockUserOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	user, err := a.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	attempt, err := a.attemptRepo.GetLoginAttempt(ctx, domain.AccountAttemptKey(user.Email()))
+	if err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	attempt.Reset()
+	if err := a.attemptRepo.SaveLoginAttempt(ctx, attempt); err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditAccountUnlocked,
+		Actor:     input.UnlockedBy,
+		Target:    user.Email(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return a.presenter.Output(user), nil
+}
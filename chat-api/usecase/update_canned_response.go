@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	UpdateCannedResponseUseCase interface {
+		Execute(context.Context, UpdateCannedResponseInput) (CannedResponseOutput, error)
+	}
+
+	// UpdateCannedResponseInput replaces the response, any rep who sees a shared
+	// response may change it but it keeps its owner
+	UpdateCannedResponseInput struct {
+		Id       string      `json:"id" validate:"required"`
+		Shortcut string      `json:"shortcut" validate:"required"`
+		Title    string      `json:"title" validate:"required,max=120"`
+		Body     string      `json:"body" validate:"required,max=4000"`
+		Tags     []string    `json:"tags" validate:"omitempty,max=20,dive,max=64"`
+		Shared   bool        `json:"shared"`
+		Macro    MacroFields `json:"macro"`
+		// UpdatedBy is the authenticated rep
+		UpdatedBy string `json:"-" validate:"required"`
+	}
+
+	// Output port
+	UpdateCannedResponsePresenter interface {
+		Output(domain.CannedResponse) CannedResponseOutput
+	}
+
+	updateCannedResponseInteractor struct {
+		repo       domain.CannedResponseRepository
+		presenter  UpdateCannedResponsePresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewUpdateCannedResponseInteractor(
+	repo domain.CannedResponseRepository,
+	presenter UpdateCannedResponsePresenter,
+	t time.Duration,
+) UpdateCannedResponseUseCase {
+	return updateCannedResponseInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (u updateCannedResponseInteractor) Execute(ctx context.Context, input UpdateCannedResponseInput) (CannedResponseOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.ctxTimeout)
+	defer cancel()
+
+	response, err := visibleCannedResponse(ctx, u.repo, input.Id, input.UpdatedBy)
+	if err != nil {
+		return u.presenter.Output(domain.CannedResponse{}), err
+	}
+
+	response.Update(input.Shortcut, input.Title, input.Body, input.Tags, input.Shared, input.Macro.macro(), time.Now())
+	if err := response.Validate(); err != nil {
+		return u.presenter.Output(domain.CannedResponse{}), err
+	}
+	if err := checkShortcutAvailable(ctx, u.repo, response); err != nil {
+		return u.presenter.Output(domain.CannedResponse{}), err
+	}
+
+	if err := u.repo.UpdateCannedResponse(ctx, response); err != nil {
+		return u.presenter.Output(domain.CannedResponse{}), err
+	}
+
+	return u.presenter.Output(response), nil
+}
@@ -15,9 +15,13 @@ type (
 
 	// Input data
 	UpdateChannelStatusInput struct {
-		ID        string `json:"id" validate:"required"`
-		UpdatedBy string `json:"updatedBy" validate:"required"`
-		Status    string `json:"status" validate:"required"`
+		ID     string `json:"id" validate:"required"`
+		Status string `json:"status" validate:"required"`
+		// Actor is the authenticated caller, the status history, the rep taking
+		// the channel and the audit trail all name them
+		Actor string `json:"-" validate:"required"`
+		IP    string `json:"-"`
+		Admin bool   `json:"-"`
 	}
 
 	// Output port
@@ -35,6 +39,9 @@ type (
 
 	updateChannelStatusInteractor struct {
 		repo       domain.ChannelRepository
+		index      domain.SearchIndex
+		notifier   domain.ChannelNotifier
+		audit      domain.AuditLogger
 		presenter  UpdateChannelStatusPresenter
 		ctxTimeout time.Duration
 	}
@@ -43,11 +50,17 @@ type (
 // NewCreateUserInteractor creates new createUserInteractor with its dependencies
 func NewUpdateChannelStatusInteractor(
 	repo domain.ChannelRepository,
+	index domain.SearchIndex,
+	notifier domain.ChannelNotifier,
+	audit domain.AuditLogger,
 	presenter UpdateChannelStatusPresenter,
 	t time.Duration,
 ) UpdateChannelStatusUseCase {
 	return updateChannelStatusInteractor{
 		repo:       repo,
+		index:      index,
+		notifier:   notifier,
+		audit:      audit,
 		presenter:  presenter,
 		ctxTimeout: t,
 	}
@@ -68,15 +81,75 @@ func (a updateChannelStatusInteractor) Execute(ctx context.Context, input Update
 		}
 	}
 
+	if !input.Admin {
+		// Taking a channel makes the caller its rep, customers only get
+		// to change the status of their own channels
+		if input.Status == domain.IN_PROGRESS {
+			return a.presenter.Output(domain.Channel{}), domain.ErrChannelTakenByAdmin
+		}
+		if !channel.IsParticipant(input.Actor) {
+			return a.presenter.Output(domain.Channel{}), domain.ErrNotChannelParticipant
+		}
+	}
+
+	action := channelStatusAuditAction(channel, input)
+
 	if input.Status == domain.IN_PROGRESS {
-		channel.UpdateRepEmail(input.UpdatedBy)
+		channel.UpdateRepEmail(input.Actor)
 	}
-	channel.UpdateStatus(input.Status, input.UpdatedBy, time.Now().Unix())
+	channel.UpdateStatus(input.Status, input.Actor, time.Now().Unix())
 
 	err = a.repo.UpdateChannelStatus(ctx, channel)
 	if err != nil {
+		a.record(ctx, action, input, domain.AuditOutcomeFailure)
 		return a.presenter.Output(domain.Channel{}), err
 	}
 
+	a.record(ctx, action, input, domain.AuditOutcomeSuccess)
+	a.index.IndexChannel(ctx, channel)
+	promptSatisfactionSurvey(ctx, a.notifier, channel)
+
 	return a.presenter.Output(channel), nil
 }
+
+func (a updateChannelStatusInteractor) record(ctx context.Context, action string, input UpdateChannelStatusInput, outcome string) {
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    action,
+		Actor:     input.Actor,
+		Target:    input.ID,
+		IP:        input.IP,
+		Outcome:   outcome,
+		Timestamp: time.Now(),
+	})
+}
+
+// channelStatusAuditAction tells a rep picking up a channel apart from a
+// channel being handed over to another rep
+func channelStatusAuditAction(channel domain.Channel, input UpdateChannelStatusInput) string {
+	switch input.Status {
+	case domain.IN_PROGRESS:
+		if channel.RepEmail() != "" && channel.RepEmail() != input.Actor {
+			return domain.AuditChannelTransferred
+		}
+		return domain.AuditChannelClaimed
+	case domain.COMPLETE:
+		return domain.AuditChannelClosed
+	default:
+		return domain.AuditChannelStatusChanged
+	}
+}
+
+// promptSatisfactionSurvey asks the customer to rate a channel that was just
+// completed, customers who miss the prompt see the survey pending on the channel
+func promptSatisfactionSurvey(ctx context.Context, notifier domain.ChannelNotifier, channel domain.Channel) {
+	if !channel.SurveyPending() {
+		return
+	}
+	notifier.Notify(ctx, domain.ChannelEvent{
+		Type:        domain.ChannelEventSurvey,
+		ChannelId:   channel.Id().Hex(),
+		UserEmail:   channel.UserEmail(),
+		MessageFrom: channel.RepEmail(),
+		Timestamp:   time.Now(),
+	})
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUpdateChannelStatusRepo struct {
+	domain.ChannelRepository
+
+	channel   domain.Channel
+	updateErr error
+}
+
+func (m mockUpdateChannelStatusRepo) GetChannelById(_ context.Context, _ string) (domain.Channel, error) {
+	return m.channel, nil
+}
+
+func (m mockUpdateChannelStatusRepo) UpdateChannelStatus(_ context.Context, _ domain.Channel) error {
+	return m.updateErr
+}
+
+type mockUpdateChannelStatusPresenter struct{}
+
+func (m mockUpdateChannelStatusPresenter) Output(channel domain.Channel) UpdateChannelStatusOutput {
+	return UpdateChannelStatusOutput{RepEmail: channel.RepEmail(), CurrentStatus: channel.CurrentStatus()}
+}
+
+func TestUpdateChannelStatusInteractor_Audit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		id       = primitive.NewObjectID()
+		queued   = domain.NewChannel(id, "user@email.com", domain.ACTIVE, time.Now(), time.Now())
+		assigned = domain.NewChannel(id, "user@email.com", domain.IN_PROGRESS, time.Now(), time.Now())
+	)
+	assigned.UpdateRepEmail("rep@email.com")
+
+	tests := []struct {
+		name          string
+		channel       domain.Channel
+		updateErr     error
+		input         UpdateChannelStatusInput
+		expectedError error
+		expected      domain.AuditEvent
+		expectSurvey  bool
+	}{
+		{
+			name:    "rep claiming a queued channel",
+			channel: queued,
+			input:   UpdateChannelStatusInput{ID: id.Hex(), Status: domain.IN_PROGRESS, Actor: "rep@email.com", IP: "10.0.0.1", Admin: true},
+			expected: domain.AuditEvent{
+				Action: domain.AuditChannelClaimed, Actor: "rep@email.com", Target: id.Hex(), IP: "10.0.0.1", Outcome: domain.AuditOutcomeSuccess,
+			},
+		},
+		{
+			name:    "channel handed over to another rep",
+			channel: assigned,
+			input:   UpdateChannelStatusInput{ID: id.Hex(), Status: domain.IN_PROGRESS, Actor: "other@email.com", Admin: true},
+			expected: domain.AuditEvent{
+				Action: domain.AuditChannelTransferred, Actor: "other@email.com", Target: id.Hex(), Outcome: domain.AuditOutcomeSuccess,
+			},
+		},
+		{
+			name:    "rep closing a channel",
+			channel: assigned,
+			input:   UpdateChannelStatusInput{ID: id.Hex(), Status: domain.COMPLETE, Actor: "rep@email.com", Admin: true},
+			expected: domain.AuditEvent{
+				Action: domain.AuditChannelClosed, Actor: "rep@email.com", Target: id.Hex(), Outcome: domain.AuditOutcomeSuccess,
+			},
+			expectSurvey: true,
+		},
+		{
+			name:         "customer closing their own channel",
+			channel:      assigned,
+			input:        UpdateChannelStatusInput{ID: id.Hex(), Status: domain.COMPLETE, Actor: "user@email.com"},
+			expected:     domain.AuditEvent{Action: domain.AuditChannelClosed, Actor: "user@email.com", Target: id.Hex(), Outcome: domain.AuditOutcomeSuccess},
+			expectSurvey: true,
+		},
+		{
+			name:          "customer taking their own channel",
+			channel:       queued,
+			input:         UpdateChannelStatusInput{ID: id.Hex(), Status: domain.IN_PROGRESS, Actor: "user@email.com"},
+			expectedError: domain.ErrChannelTakenByAdmin,
+		},
+		{
+			name:          "customer closing someone else's channel",
+			channel:       assigned,
+			input:         UpdateChannelStatusInput{ID: id.Hex(), Status: domain.COMPLETE, Actor: "other@email.com"},
+			expectedError: domain.ErrNotChannelParticipant,
+		},
+		{
+			name:          "failed update recorded as a failure",
+			channel:       assigned,
+			updateErr:     errors.New("db down"),
+			input:         UpdateChannelStatusInput{ID: id.Hex(), Status: domain.COMPLETE, Actor: "rep@email.com", Admin: true},
+			expectedError: errors.New("db down"),
+			expected: domain.AuditEvent{
+				Action: domain.AuditChannelClosed, Actor: "rep@email.com", Target: id.Hex(), Outcome: domain.AuditOutcomeFailure,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				events  []domain.AuditEvent
+				surveys []domain.ChannelEvent
+				uc      = NewUpdateChannelStatusInteractor(
+					mockUpdateChannelStatusRepo{channel: tt.channel, updateErr: tt.updateErr},
+					mockSearchIndex{},
+					mockChannelNotifier{events: &surveys},
+					mockAuditLogger{events: &events},
+					mockUpdateChannelStatusPresenter{},
+					time.Second,
+				)
+			)
+
+			_, err := uc.Execute(context.Background(), tt.input)
+			if !reflect.DeepEqual(err, tt.expectedError) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+
+			if tt.expected == (domain.AuditEvent{}) {
+				if len(events) != 0 {
+					t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: none", tt.name, events)
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, events, tt.expected)
+			}
+			events[0].Timestamp = time.Time{}
+			if events[0] != tt.expected {
+				t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, events[0], tt.expected)
+			}
+			if surveyed := len(surveys) == 1 && surveys[0].Type == domain.ChannelEventSurvey; surveyed != tt.expectSurvey {
+				t.Errorf("[TestCase '%s'] Survey: '%v' | Expected: '%v'", tt.name, surveys, tt.expectSurvey)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	UpdateChannelDetailsUseCase interface {
+		Execute(context.Context, UpdateChannelDetailsInput) (UpdateChannelDetailsOutput, error)
+	}
+
+	// UpdateChannelDetailsInput classifies a channel. Tags replace the tags of the
+	// channel when given, attributes are merged in and an empty value removes one
+	UpdateChannelDetailsInput struct {
+		ChannelId  string            `json:"channelId" validate:"required"`
+		Tags       []string          `json:"tags" validate:"omitempty,max=20,dive,max=64"`
+		Priority   string            `json:"priority" validate:"omitempty,oneof=LOW NORMAL HIGH URGENT"`
+		Attributes map[string]string `json:"attributes" validate:"omitempty,max=20"`
+		// UpdatedBy is the authenticated rep
+		UpdatedBy string `json:"-" validate:"required"`
+	}
+
+	// Output port
+	UpdateChannelDetailsPresenter interface {
+		Output(domain.Channel) UpdateChannelDetailsOutput
+	}
+
+	// Output data
+	UpdateChannelDetailsOutput struct {
+		ChannelId  string            `json:"channelId"`
+		Tags       []string          `json:"tags"`
+		Priority   string            `json:"priority"`
+		Attributes map[string]string `json:"attributes"`
+	}
+
+	updateChannelDetailsInteractor struct {
+		repo       domain.ChannelRepository
+		index      domain.SearchIndex
+		presenter  UpdateChannelDetailsPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewUpdateChannelDetailsInteractor(
+	repo domain.ChannelRepository,
+	index domain.SearchIndex,
+	presenter UpdateChannelDetailsPresenter,
+	t time.Duration,
+) UpdateChannelDetailsUseCase {
+	return updateChannelDetailsInteractor{
+		repo:       repo,
+		index:      index,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (u updateChannelDetailsInteractor) Execute(ctx context.Context, input UpdateChannelDetailsInput) (UpdateChannelDetailsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.ctxTimeout)
+	defer cancel()
+
+	channel, err := u.repo.GetChannelById(ctx, input.ChannelId)
+	if err != nil {
+		return u.presenter.Output(domain.Channel{}), err
+	}
+
+	if err := applyChannelDetails(&channel, input.Tags, input.Priority, input.Attributes); err != nil {
+		return u.presenter.Output(domain.Channel{}), err
+	}
+
+	if err := u.repo.UpdateChannelDetails(ctx, channel); err != nil {
+		return u.presenter.Output(domain.Channel{}), err
+	}
+	u.index.IndexChannel(ctx, channel)
+
+	return u.presenter.Output(channel), nil
+}
+
+// applyChannelDetails sets the details given at creation or by a rep, a nil tag
+// list keeps the tags and an empty priority the priority
+func applyChannelDetails(channel *domain.Channel, tags []string, priority string, attributes map[string]string) error {
+	if tags != nil {
+		channel.RemoveTags(channel.Tags()...)
+		channel.AddTags(tags...)
+	}
+	if priority != "" {
+		if err := channel.UpdatePriority(priority); err != nil {
+			return err
+		}
+	}
+	return channel.UpdateAttributes(attributes)
+}
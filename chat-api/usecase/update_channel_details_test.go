@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUpdateChannelDetailsPresenter struct{}
+
+func (m mockUpdateChannelDetailsPresenter) Output(channel domain.Channel) UpdateChannelDetailsOutput {
+	return UpdateChannelDetailsOutput{Tags: channel.Tags(), Priority: channel.Priority(), Attributes: channel.Attributes()}
+}
+
+func TestUpdateChannelDetailsInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	newChannel := func() domain.Channel {
+		channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.ACTIVE, time.Now(), time.Now())
+		channel.AddTags("billing")
+		_ = channel.UpdateAttributes(map[string]string{"plan": "pro", "page": "/pricing"})
+		return channel
+	}
+
+	tests := []struct {
+		name          string
+		input         UpdateChannelDetailsInput
+		expected      UpdateChannelDetailsOutput
+		expectedError error
+	}{
+		{
+			name:  "priority only",
+			input: UpdateChannelDetailsInput{Priority: domain.PriorityUrgent},
+			expected: UpdateChannelDetailsOutput{
+				Tags:       []string{"billing"},
+				Priority:   domain.PriorityUrgent,
+				Attributes: map[string]string{"plan": "pro", "page": "/pricing"},
+			},
+		},
+		{
+			name:  "tags replaced and attributes merged",
+			input: UpdateChannelDetailsInput{Tags: []string{"Refund", "vip"}, Attributes: map[string]string{"orderId": "A-1", "page": ""}},
+			expected: UpdateChannelDetailsOutput{
+				Tags:       []string{"refund", "vip"},
+				Priority:   domain.PriorityNormal,
+				Attributes: map[string]string{"plan": "pro", "orderId": "A-1"},
+			},
+		},
+		{
+			name:     "tags cleared",
+			input:    UpdateChannelDetailsInput{Tags: []string{}},
+			expected: UpdateChannelDetailsOutput{Priority: domain.PriorityNormal, Attributes: map[string]string{"plan": "pro", "page": "/pricing"}},
+		},
+		{
+			name:          "invalid attribute name",
+			input:         UpdateChannelDetailsInput{Attributes: map[string]string{"order id": "A-1"}},
+			expectedError: domain.ErrInvalidAttribute,
+		},
+	}
+
+	for _, tt := range tests {
+		var (
+			channel    = newChannel()
+			tagUpdates []domain.Channel
+			indexed    []domain.Channel
+			uc         = NewUpdateChannelDetailsInteractor(
+				mockMacroChannelRepo{channel: channel, tagUpdates: &tagUpdates},
+				mockSearchIndex{indexed: &indexed},
+				mockUpdateChannelDetailsPresenter{},
+				time.Second,
+			)
+		)
+
+		tt.input.ChannelId = channel.Id().Hex()
+		tt.input.UpdatedBy = "rep@email.com"
+		got, err := uc.Execute(context.Background(), tt.input)
+		if err != tt.expectedError {
+			t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			continue
+		}
+		if stored := len(tagUpdates) == 1 && len(indexed) == 1; stored != (err == nil) {
+			t.Errorf("[TestCase '%s'] Stored: '%v' | Expected: '%v'", tt.name, stored, err == nil)
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	UpdateOrganizationSettingsUseCase interface {
+		Execute(context.Context, UpdateOrganizationSettingsInput) (OrganizationOutput, error)
+	}
+
+	BusinessDayInput struct {
+		Weekday *int   `json:"weekday" validate:"required,min=0,max=6"`
+		Open    string `json:"open" validate:"required"`
+		Close   string `json:"close" validate:"required"`
+	}
+
+	// SLAPolicyInput sets the targets of a priority in minutes, zero stops tracking a target
+	SLAPolicyInput struct {
+		Priority             string `json:"priority" validate:"required,oneof=LOW NORMAL HIGH URGENT"`
+		FirstResponseMinutes int    `json:"firstResponseMinutes" validate:"min=0"`
+		ResolutionMinutes    int    `json:"resolutionMinutes" validate:"min=0"`
+	}
+
+	// Input data, the SLA policies of the priorities left out are kept
+	UpdateOrganizationSettingsInput struct {
+		TimeZone        string             `json:"timeZone" validate:"required"`
+		BusinessDays    []BusinessDayInput `json:"businessDays" validate:"dive"`
+		RoutingStrategy string             `json:"routingStrategy" validate:"required,oneof=MANUAL LEAST_BUSY"`
+		SLAPolicies     []SLAPolicyInput   `json:"slaPolicies" validate:"dive"`
+		UpdatedBy       string             `json:"-"`
+		IP              string             `json:"-"`
+	}
+
+	// Output port
+	UpdateOrganizationSettingsPresenter interface {
+		Output(domain.Organization) OrganizationOutput
+	}
+
+	updateOrganizationSettingsInteractor struct {
+		repo       domain.OrganizationRepository
+		audit      domain.AuditLogger
+		presenter  UpdateOrganizationSettingsPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewUpdateOrganizationSettingsInteractor(
+	repo domain.OrganizationRepository,
+	audit domain.AuditLogger,
+	presenter UpdateOrganizationSettingsPresenter,
+	t time.Duration,
+) UpdateOrganizationSettingsUseCase {
+	return updateOrganizationSettingsInteractor{
+		repo:       repo,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (u updateOrganizationSettingsInteractor) Execute(ctx context.Context, input UpdateOrganizationSettingsInput) (OrganizationOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.ctxTimeout)
+	defer cancel()
+
+	organization, err := currentOrganization(ctx, u.repo)
+	if err != nil {
+		return u.presenter.Output(domain.Organization{}), err
+	}
+
+	var days []domain.BusinessDay
+	for _, day := range input.BusinessDays {
+		days = append(days, domain.BusinessDay{
+			Weekday: time.Weekday(*day.Weekday),
+			Open:    day.Open,
+			Close:   day.Close,
+		})
+	}
+	hours := domain.BusinessHours{TimeZone: input.TimeZone, Days: days}
+	if err := hours.Validate(); err != nil {
+		return u.presenter.Output(domain.Organization{}), err
+	}
+
+	var policies []domain.SLAPolicy
+	for _, policy := range input.SLAPolicies {
+		policies = append(policies, domain.SLAPolicy{
+			Priority:      policy.Priority,
+			FirstResponse: time.Duration(policy.FirstResponseMinutes) * time.Minute,
+			Resolution:    time.Duration(policy.ResolutionMinutes) * time.Minute,
+		})
+	}
+	if err := domain.ValidateSLAPolicies(policies); err != nil {
+		return u.presenter.Output(domain.Organization{}), err
+	}
+
+	organization.UpdateSettings(domain.OrganizationSettings{
+		BusinessHours:   hours,
+		RoutingStrategy: input.RoutingStrategy,
+		SLAPolicies:     domain.MergeSLAPolicies(organization.Settings().SLAPolicies, policies),
+	}, time.Now())
+
+	if err := u.repo.UpdateOrganizationSettings(ctx, organization); err != nil {
+		return u.presenter.Output(domain.Organization{}), err
+	}
+
+	u.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditOrganizationSettingsUpdated,
+		Actor:     input.UpdatedBy,
+		Target:    organization.Id(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return u.presenter.Output(organization), nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	UpdateSecuritySettingsUseCase interface {
+		Execute(context.Context, UpdateSecuritySettingsInput) (UpdateSecuritySettingsOutput, error)
+	}
+
+	// Input data
+	UpdateSecuritySettingsInput struct {
+		RequireTwoFactorForAdmin *bool  `json:"requireTwoFactorForAdmin" validate:"required"`
+		UpdatedBy                string `json:"-"`
+		IP                       string `json:"-"`
+	}
+
+	// Output port
+	UpdateSecuritySettingsPresenter interface {
+		Output(domain.SecuritySettings) UpdateSecuritySettingsOutput
+	}
+
+	// Output data
+	UpdateSecuritySettingsOutput struct {
+		RequireTwoFactorForAdmin bool `json:"requireTwoFactorForAdmin"`
+	}
+
+	updateSecuritySettingsInteractor struct {
+		repo       domain.SettingsRepository
+		audit      domain.AuditLogger
+		presenter  UpdateSecuritySettingsPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewUpdateSecuritySettingsInteractor(
+	repo domain.SettingsRepository,
+	audit domain.AuditLogger,
+	presenter UpdateSecuritySettingsPresenter,
+	t time.Duration,
+) UpdateSecuritySettingsUseCase {
+	return updateSecuritySettingsInteractor{
+		repo:       repo,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a updateSecuritySettingsInteractor) Execute(ctx context.Context, input UpdateSecuritySettingsInput) (UpdateSecuritySettingsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	settings, err := a.repo.GetSecuritySettings(ctx)
+	if err != nil {
+		return a.presenter.Output(domain.SecuritySettings{}), err
+	}
+
+	settings.RequireTwoFactorForAdmin = *input.RequireTwoFactorForAdmin
+	if err := a.repo.SaveSecuritySettings(ctx, settings); err != nil {
+		return a.presenter.Output(domain.SecuritySettings{}), err
+	}
+
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    domain.AuditSecuritySettingsUpdated,
+		Actor:     input.UpdatedBy,
+		Target:    "security_settings",
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return a.presenter.Output(settings), nil
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	UpdateUserActivationUseCase interface {
+		Execute(context.Context, UpdateUserActivationInput) (UpdateUserActivationOutput, error)
+	}
+
+	// Input data
+	UpdateUserActivationInput struct {
+		Email     string `json:"email" validate:"required"`
+		Active    bool   `json:"-"`
+		UpdatedBy string `json:"-"`
+		IP        string `json:"-"`
+	}
+
+	// Output port
+	UpdateUserActivationPresenter interface {
+		Output(domain.User) UpdateUserActivationOutput
+	}
+
+	// Output data
+	UpdateUserActivationOutput struct {
+		Email     string    `json:"email"`
+		Active    bool      `json:"active"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	updateUserActivationInteractor struct {
+		repo       domain.UserRepository
+		audit      domain.AuditLogger
+		presenter  UpdateUserActivationPresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewUpdateUserActivationInteractor(
+	repo domain.UserRepository,
+	audit domain.AuditLogger,
+	presenter UpdateUserActivationPresenter,
+	t time.Duration,
+) UpdateUserActivationUseCase {
+	return updateUserActivationInteractor{
+		repo:       repo,
+		audit:      audit,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a updateUserActivationInteractor) Execute(ctx context.Context, input UpdateUserActivationInput) (UpdateUserActivationOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	user, err := a.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	action := domain.AuditUserReactivated
+	if input.Active {
+		user.Reactivate(time.Now())
+	} else {
+		if user.Email() == input.UpdatedBy {
+			return a.presenter.Output(domain.User{}), domain.ErrCannotDeactivateSelf
+		}
+		user.Deactivate(time.Now())
+		action = domain.AuditUserDeactivated
+	}
+
+	if err := a.repo.UpdateActivation(ctx, user); err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	a.audit.Record(ctx, domain.AuditEvent{
+		Action:    action,
+		Actor:     input.UpdatedBy,
+		Target:    user.Email(),
+		IP:        input.IP,
+		Outcome:   domain.AuditOutcomeSuccess,
+		Timestamp: time.Now(),
+	})
+
+	return a.presenter.Output(user), nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"chat-api/domain"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mockUserActivationRepo struct {
+	domain.UserRepository
+
+	user    domain.User
+	err     error
+	updated *domain.User
+}
+
+func (m mockUserActivationRepo) GetUserByEmail(_ context.Context, _ string) (domain.User, error) {
+	return m.user, m.err
+}
+
+func (m mockUserActivationRepo) UpdateActivation(_ context.Context, user domain.User) error {
+	*m.updated = user
+	return nil
+}
+
+type mockUpdateUserActivationPresenter struct{}
+
+func (m mockUpdateUserActivationPresenter) Output(user domain.User) UpdateUserActivationOutput {
+	return UpdateUserActivationOutput{Email: user.Email(), Active: user.Email() != "" && user.IsActive()}
+}
+
+func TestUpdateUserActivationInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	active := domain.NewUser(newUserId, "firstName", "lastName", "user@email.com", "hash", time.Now(), time.Now())
+	deactivated := domain.NewUser(newUserId, "firstName", "lastName", "user@email.com", "hash", time.Now(), time.Now())
+	deactivated.Deactivate(time.Now())
+
+	tests := []struct {
+		name          string
+		user          domain.User
+		err           error
+		input         UpdateUserActivationInput
+		expected      UpdateUserActivationOutput
+		expectedError error
+		expectedAudit []string
+	}{
+		{
+			name:          "admin deactivates user",
+			user:          active,
+			input:         UpdateUserActivationInput{Email: "user@email.com", UpdatedBy: "admin@email.com"},
+			expected:      UpdateUserActivationOutput{Email: "user@email.com"},
+			expectedAudit: []string{domain.AuditUserDeactivated},
+		},
+		{
+			name:          "admin reactivates user",
+			user:          deactivated,
+			input:         UpdateUserActivationInput{Email: "user@email.com", Active: true, UpdatedBy: "admin@email.com"},
+			expected:      UpdateUserActivationOutput{Email: "user@email.com", Active: true},
+			expectedAudit: []string{domain.AuditUserReactivated},
+		},
+		{
+			name:          "admin cannot deactivate own account",
+			user:          active,
+			input:         UpdateUserActivationInput{Email: "user@email.com", UpdatedBy: "user@email.com"},
+			expectedError: domain.ErrCannotDeactivateSelf,
+		},
+		{
+			name:          "unknown user",
+			err:           domain.ErrUserNotFound,
+			input:         UpdateUserActivationInput{Email: "missing@email.com", UpdatedBy: "admin@email.com"},
+			expectedError: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				events  []domain.AuditEvent
+				updated domain.User
+				uc      = NewUpdateUserActivationInteractor(
+					mockUserActivationRepo{user: tt.user, err: tt.err, updated: &updated},
+					mockAuditLogger{events: &events},
+					mockUpdateUserActivationPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+
+			var actions []string
+			for _, event := range events {
+				actions = append(actions, event.Action)
+			}
+			if !reflect.DeepEqual(actions, tt.expectedAudit) {
+				t.Errorf("[TestCase '%s'] Audit: '%v' | Expected: '%v'", tt.name, actions, tt.expectedAudit)
+			}
+		})
+	}
+}
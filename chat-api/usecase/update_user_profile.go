@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"chat-api/domain"
+)
+
+type (
+	// Input port
+	UpdateUserProfileUseCase interface {
+		Execute(context.Context, UpdateUserProfileInput) (UpdateUserProfileOutput, error)
+	}
+
+	// Input data
+	UpdateUserProfileInput struct {
+		Email     string `json:"-" validate:"required"`
+		FirstName string `json:"firstName" validate:"required"`
+		LastName  string `json:"lastName" validate:"required"`
+	}
+
+	// Output port
+	UpdateUserProfilePresenter interface {
+		Output(domain.User) UpdateUserProfileOutput
+	}
+
+	// Output data
+	UpdateUserProfileOutput struct {
+		FirstName string    `json:"firstName"`
+		LastName  string    `json:"lastName"`
+		Email     string    `json:"email"`
+		Role      string    `json:"role"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	updateUserProfileInteractor struct {
+		repo       domain.UserRepository
+		presenter  UpdateUserProfilePresenter
+		ctxTimeout time.Duration
+	}
+)
+
+func NewUpdateUserProfileInteractor(
+	repo domain.UserRepository,
+	presenter UpdateUserProfilePresenter,
+	t time.Duration,
+) UpdateUserProfileUseCase {
+	return updateUserProfileInteractor{
+		repo:       repo,
+		presenter:  presenter,
+		ctxTimeout: t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a updateUserProfileInteractor) Execute(ctx context.Context, input UpdateUserProfileInput) (UpdateUserProfileOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	user, err := a.repo.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	user.UpdateProfile(input.FirstName, input.LastName, time.Now())
+	if err := a.repo.UpdateProfile(ctx, user); err != nil {
+		return a.presenter.Output(domain.User{}), err
+	}
+
+	return a.presenter.Output(user), nil
+}
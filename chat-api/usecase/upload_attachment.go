@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	// Input port
+	UploadAttachmentUseCase interface {
+		Execute(context.Context, UploadAttachmentInput) (AttachmentOutput, error)
+	}
+
+	// Input data
+	UploadAttachmentInput struct {
+		ChannelId string `validate:"required"`
+		FileName  string
+		// Content is read up to one byte past domain.MaxAttachmentSize
+		Content    io.Reader `validate:"required"`
+		UploadedBy string    `validate:"required"`
+	}
+
+	// Output port
+	UploadAttachmentPresenter interface {
+		Output(domain.Attachment) AttachmentOutput
+	}
+
+	// Output data
+	AttachmentOutput struct {
+		Id          string    `json:"id"`
+		ChannelId   string    `json:"channelId"`
+		FileName    string    `json:"fileName"`
+		ContentType string    `json:"contentType"`
+		Size        int64     `json:"size"`
+		CreatedAt   time.Time `json:"createdAt"`
+	}
+
+	uploadAttachmentInteractor struct {
+		channels    domain.ChannelRepository
+		attachments domain.AttachmentRepository
+		blobs       domain.BlobStore
+		presenter   UploadAttachmentPresenter
+		ctxTimeout  time.Duration
+	}
+)
+
+func NewUploadAttachmentInteractor(
+	channels domain.ChannelRepository,
+	attachments domain.AttachmentRepository,
+	blobs domain.BlobStore,
+	presenter UploadAttachmentPresenter,
+	t time.Duration,
+) UploadAttachmentUseCase {
+	return uploadAttachmentInteractor{
+		channels:    channels,
+		attachments: attachments,
+		blobs:       blobs,
+		presenter:   presenter,
+		ctxTimeout:  t,
+	}
+}
+
+// Execute orchestrates the use case
+func (a uploadAttachmentInteractor) Execute(ctx context.Context, input UploadAttachmentInput) (AttachmentOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.ctxTimeout)
+	defer cancel()
+
+	channel, err := a.channels.GetChannelById(ctx, input.ChannelId)
+	if err != nil {
+		return a.presenter.Output(domain.Attachment{}), err
+	}
+	if !channel.IsParticipant(input.UploadedBy) {
+		return a.presenter.Output(domain.Attachment{}), domain.ErrNotChannelParticipant
+	}
+
+	content, err := ioutil.ReadAll(io.LimitReader(input.Content, domain.MaxAttachmentSize+1))
+	if err != nil {
+		return a.presenter.Output(domain.Attachment{}), err
+	}
+	switch {
+	case len(content) == 0:
+		return a.presenter.Output(domain.Attachment{}), domain.ErrAttachmentEmpty
+	case len(content) > domain.MaxAttachmentSize:
+		return a.presenter.Output(domain.Attachment{}), domain.ErrAttachmentTooLarge
+	}
+
+	// The type is sniffed from the content, what the client claims is not trusted
+	contentType := http.DetectContentType(content)
+	if !domain.IsAttachmentContentType(contentType) {
+		return a.presenter.Output(domain.Attachment{}), domain.ErrAttachmentType
+	}
+
+	attachment := domain.NewAttachment(
+		primitive.NewObjectID(),
+		channel.Id().Hex(),
+		domain.NormalizeEmail(input.UploadedBy),
+		domain.AttachmentFileName(input.FileName),
+		contentType,
+		int64(len(content)),
+		time.Now(),
+	)
+	attachment.AssignTenant(domain.TenantFromContext(ctx))
+
+	if err := a.blobs.Put(ctx, attachment.Key(), bytes.NewReader(content), attachment.Size(), contentType); err != nil {
+		return a.presenter.Output(domain.Attachment{}), err
+	}
+
+	created, err := a.attachments.CreateAttachment(ctx, attachment)
+	if err != nil {
+		// Nothing references the blob without its attachment
+		_ = a.blobs.Delete(ctx, attachment.Key())
+		return a.presenter.Output(domain.Attachment{}), err
+	}
+
+	return a.presenter.Output(created), nil
+}
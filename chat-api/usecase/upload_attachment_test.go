@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-api/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockAttachmentRepo struct {
+	attachments map[string]domain.Attachment
+	created     *[]domain.Attachment
+	createErr   error
+}
+
+func (m mockAttachmentRepo) CreateAttachment(_ context.Context, attachment domain.Attachment) (domain.Attachment, error) {
+	if m.createErr != nil {
+		return domain.Attachment{}, m.createErr
+	}
+	*m.created = append(*m.created, attachment)
+	return attachment, nil
+}
+
+func (m mockAttachmentRepo) GetAttachmentById(_ context.Context, id string) (domain.Attachment, error) {
+	attachment, ok := m.attachments[id]
+	if !ok {
+		return domain.Attachment{}, domain.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+type mockBlobStore struct {
+	blobs map[string][]byte
+}
+
+func (m mockBlobStore) Put(_ context.Context, key string, content io.Reader, _ int64, _ string) error {
+	data, err := ioutil.ReadAll(content)
+	m.blobs[key] = data
+	return err
+}
+
+func (m mockBlobStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, domain.ErrBlobNotFound
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m mockBlobStore) Delete(_ context.Context, key string) error {
+	delete(m.blobs, key)
+	return nil
+}
+
+type mockUploadAttachmentPresenter struct{}
+
+func (m mockUploadAttachmentPresenter) Output(attachment domain.Attachment) AttachmentOutput {
+	return AttachmentOutput{
+		FileName:    attachment.FileName(),
+		ContentType: attachment.ContentType(),
+		Size:        attachment.Size(),
+	}
+}
+
+// attachmentChannel is a channel between user@email.com and rep@email.com
+func attachmentChannel() domain.Channel {
+	channel := domain.NewChannel(primitive.NewObjectID(), "user@email.com", domain.IN_PROGRESS, time.Now(), time.Now())
+	channel.UpdateRepEmail("rep@email.com")
+	return channel
+}
+
+func TestUploadAttachmentInteractor_Execute(t *testing.T) {
+	t.Parallel()
+
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("x", 100)
+
+	tests := []struct {
+		name          string
+		uploadedBy    string
+		fileName      string
+		content       string
+		createErr     error
+		expected      AttachmentOutput
+		expectedError error
+		expectedBlobs int
+	}{
+		{
+			name:          "customer uploading an image",
+			uploadedBy:    "User@Email.com",
+			fileName:      "../../screenshot.png",
+			content:       png,
+			expected:      AttachmentOutput{FileName: "screenshot.png", ContentType: "image/png", Size: int64(len(png))},
+			expectedBlobs: 1,
+		},
+		{
+			name:          "rep uploading a text file",
+			uploadedBy:    "rep@email.com",
+			fileName:      "notes.txt",
+			content:       "hello",
+			expected:      AttachmentOutput{FileName: "notes.txt", ContentType: "text/plain; charset=utf-8", Size: 5},
+			expectedBlobs: 1,
+		},
+		{
+			name:          "someone else",
+			uploadedBy:    "other@email.com",
+			content:       png,
+			expectedError: domain.ErrNotChannelParticipant,
+		},
+		{
+			name:          "html claiming to be an image",
+			uploadedBy:    "user@email.com",
+			fileName:      "image.png",
+			content:       "<html><script>alert(1)</script></html>",
+			expectedError: domain.ErrAttachmentType,
+		},
+		{
+			name:          "too large",
+			uploadedBy:    "user@email.com",
+			content:       png + strings.Repeat("x", domain.MaxAttachmentSize),
+			expectedError: domain.ErrAttachmentTooLarge,
+		},
+		{
+			name:          "empty",
+			uploadedBy:    "user@email.com",
+			expectedError: domain.ErrAttachmentEmpty,
+		},
+		{
+			name:          "failed create",
+			uploadedBy:    "user@email.com",
+			content:       png,
+			createErr:     errors.New("db down"),
+			expectedError: errors.New("db down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				channel = attachmentChannel()
+				created []domain.Attachment
+				blobs   = mockBlobStore{blobs: map[string][]byte{}}
+				uc      = NewUploadAttachmentInteractor(
+					mockMessageRepo{channel: channel},
+					mockAttachmentRepo{created: &created, createErr: tt.createErr},
+					blobs,
+					mockUploadAttachmentPresenter{},
+					time.Second,
+				)
+			)
+
+			got, err := uc.Execute(context.Background(), UploadAttachmentInput{
+				ChannelId:  channel.Id().Hex(),
+				FileName:   tt.fileName,
+				Content:    strings.NewReader(tt.content),
+				UploadedBy: tt.uploadedBy,
+			})
+			if !reflect.DeepEqual(err, tt.expectedError) {
+				t.Errorf("[TestCase '%s'] Result: '%v' | ExpectedError: '%v'", tt.name, err, tt.expectedError)
+			}
+			if got != tt.expected {
+				t.Errorf("[TestCase '%s'] Result: '%v' | Expected: '%v'", tt.name, got, tt.expected)
+			}
+			if len(blobs.blobs) != tt.expectedBlobs {
+				t.Errorf("[TestCase '%s'] Blobs: '%v' | Expected: '%v'", tt.name, len(blobs.blobs), tt.expectedBlobs)
+			}
+			if err != nil {
+				return
+			}
+
+			attachment := created[0]
+			if attachment.UploadedBy() != "user@email.com" && attachment.UploadedBy() != "rep@email.com" {
+				t.Errorf("[TestCase '%s'] UploadedBy: '%v' | Expected a normalized email", tt.name, attachment.UploadedBy())
+			}
+			if content := string(blobs.blobs[attachment.Key()]); content != tt.content {
+				t.Errorf("[TestCase '%s'] Stored: '%v' bytes | Expected: '%v' bytes", tt.name, len(content), len(tt.content))
+			}
+		})
+	}
+}
@@ -9,6 +9,27 @@ export default function Index({ channel }) {
       <span className="channelName">
         {`${channel?.userFullName}`}
       </span>
+      {channel?.priority && channel.priority !== "NORMAL" ? (
+        <span className={`channelPriority ${channel.priority.toLowerCase()}`}>
+          {channel.priority}
+        </span>
+      ) : null}
+      {channel?.sla?.status === "APPROACHING" || channel?.sla?.status === "BREACHED" ? (
+        <span
+          className={`channelSLA ${channel.sla.status.toLowerCase()}`}
+          title={channel.sla.status === "BREACHED" ? "SLA breached" : "SLA target approaching"}
+        >
+          SLA
+        </span>
+      ) : null}
+      {channel?.tags?.map((tag) => (
+        <span className="channelTag" key={tag}>
+          {tag}
+        </span>
+      ))}
+      {channel?.unreadCount > 0 ? (
+        <span className="channelUnread">{channel.unreadCount}</span>
+      ) : null}
       <span className="channelTime">
         {`${moment(channel.createdAt).format("DD/MM/YYYY")}`}
       </span>
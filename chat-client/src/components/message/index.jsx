@@ -1,14 +1,54 @@
 import React from "react";
 import "./style.css";
 import { format } from "timeago.js";
+import { notification } from "antd";
+import { PaperClipOutlined } from "@ant-design/icons";
+
+const { getAttachmentURL } = require("../../services/index");
+
+export default function index({ message, isOwn, seen, channelId, token }) {
+  // Links expire within minutes, one is asked for on every click
+  const openAttachment = async (attachment) => {
+    try {
+      window.open(await getAttachmentURL(channelId, attachment.id, token), "_blank", "noopener");
+    } catch (error) {
+      notification.error({
+        message: "Could not open attachment",
+        description: error.message,
+        placement: "topRight",
+        duration: 1.5,
+      });
+    }
+  };
 
-export default function index({ message, isOwn }) {
   return (
-    <div className={isOwn ? "message isOwn" : "message"}>
+    <div className={`message${isOwn ? " isOwn" : ""}${message?.internal ? " isInternal" : ""}`}>
       <div className="messageTop">
-        <p className="messageText">{message?.message}</p>
+        {message?.deleted ? (
+          <p className="messageText">
+            <i>This message was deleted</i>
+          </p>
+        ) : (
+          <>
+            {message?.message ? <p className="messageText">{message.message}</p> : null}
+            {(message?.attachments || []).map((attachment) => (
+              <button
+                key={attachment.id}
+                className="messageAttachment"
+                onClick={() => openAttachment(attachment)}
+              >
+                <PaperClipOutlined /> {attachment.fileName}
+              </button>
+            ))}
+          </>
+        )}
+      </div>
+      <div className="messageBottom">
+        {message?.internal ? "Internal note · " : ""}
+        {format(message?.timeStamp)}
+        {message?.editedAt && !message?.deleted ? " (edited)" : ""}
+        {seen ? " · Seen" : ""}
       </div>
-      <div className="messageBottom">{format(message?.timeStamp)}</div>
     </div>
   );
 }
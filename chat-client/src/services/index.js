@@ -128,6 +128,68 @@ const createMessage = async (requestBody, token) => {
   return data;
 };
 
+// uploadAttachment sends a file the next message can reference by its id
+const uploadAttachment = async (channelId, file, token) => {
+  const form = new FormData();
+  form.append("file", file);
+
+  const { data } = await axios.post(
+    `${process.env.REACT_APP_SERVER_URL}/channel/${channelId}/attachment`,
+    form,
+    {
+      headers: {
+        Authorization: `Bearer ${token}`,
+      },
+    }
+  );
+
+  return data;
+};
+
+// getAttachmentURL returns a short lived link that downloads without credentials
+const getAttachmentURL = async (channelId, attachmentId, token) => {
+  const { data } = await axios.get(
+    `${process.env.REACT_APP_SERVER_URL}/channel/${channelId}/attachment/${attachmentId}`,
+    {
+      headers: {
+        Authorization: `Bearer ${token}`,
+      },
+    }
+  );
+
+  return new URL(data.url, process.env.REACT_APP_SERVER_URL).href;
+};
+
+// getCannedResponses suggests the responses whose shortcut starts with what the rep typed
+const getCannedResponses = async (shortcut, token) => {
+  const { data } = await axios.get(
+    `${process.env.REACT_APP_SERVER_URL}/cannedresponse`,
+    {
+      params: { shortcut },
+      headers: {
+        Authorization: `Bearer ${token}`,
+      },
+    }
+  );
+
+  return data;
+};
+
+// applyCannedResponse renders a response for the channel and runs its macro
+const applyCannedResponse = async (channelId, responseId, token) => {
+  const { data } = await axios.post(
+    `${process.env.REACT_APP_SERVER_URL}/channel/${channelId}/cannedresponse/${responseId}`,
+    null,
+    {
+      headers: {
+        Authorization: `Bearer ${token}`,
+      },
+    }
+  );
+
+  return data;
+};
+
 const getUser = async (email, token) => {
   const data = await axios.get(
     `${process.env.REACT_APP_SERVER_URL}/user/${email}`,
@@ -152,4 +214,8 @@ export {
   updateChannelStatus,
   createChannel,
   createMessage,
+  uploadAttachment,
+  getAttachmentURL,
+  getCannedResponses,
+  applyCannedResponse,
 };
@@ -1,6 +1,6 @@
 import React, { useState, useEffect, useContext, useRef, useMemo } from "react";
 import { notification, Spin } from "antd";
-import { LoadingOutlined } from "@ant-design/icons";
+import { LoadingOutlined, PaperClipOutlined } from "@ant-design/icons";
 import { Redirect } from "react-router-dom";
 import useWebSocket, { ReadyState } from 'react-use-websocket';
 import "./style.css";
@@ -12,17 +12,26 @@ const {
   getActiveMessages,
   getChannel,
   updateChannelStatus,
+  uploadAttachment,
+  getCannedResponses,
+  applyCannedResponse,
 } = require("../../services/index");
 
 export default function Index() {
   const [state, dispatch] = useContext(Context);
-  const [socketUrl, setSocketUrl] = useState(process.env.REACT_APP_SOCKET_SERVER_URL);
+  const [socketUrl, setSocketUrl] = useState(
+    `${process.env.REACT_APP_SOCKET_SERVER_URL}?token=${state.user?.token}`
+  );
   const [myChannels, setMyChannels] = useState([]);
   const [activeChannels, setActiveChannels] = useState([]);
   const [selectedChannel, setSelectedChannel] = useState({});
   const [selectedMessages, setSelectedMessages] = useState([]);
   const [arrivedMessage, setArrivedMessage] = useState({});
   const [newMessage, setNewMessage] = useState("");
+  const [attachments, setAttachments] = useState([]);
+  const [isNote, setIsNote] = useState(false);
+  const [suggestions, setSuggestions] = useState([]);
+  const [readMarkers, setReadMarkers] = useState([]);
   const [redirect, setRedirect] = useState("");
   const [isLoading, setIsLoading] = useState(false);
   const scrollRef = useRef();
@@ -39,13 +48,62 @@ export default function Index() {
 
   useEffect(() => {
     const abortController = new AbortController();
-    if (
+    if (arrivedMessage?.type === "read") {
+      // Read markers only move forward, the one that arrived replaces the previous
+      if (arrivedMessage?.channelId === selectedChannel?.id) {
+        setReadMarkers((prev) => [
+          ...prev.filter((marker) => marker.participant !== arrivedMessage.messageFrom),
+          { participant: arrivedMessage.messageFrom, lastReadAt: arrivedMessage.timeStamp },
+        ]);
+      }
+    } else if (arrivedMessage?.type === "breach") {
+      // Breaches flag the channel and warn the rep, they are not messages
+      const flag = (c) =>
+        c.id === arrivedMessage.channelId ? { ...c, sla: { ...c.sla, status: "BREACHED" } } : c;
+      setMyChannels((prev) => prev.map(flag));
+      setActiveChannels((prev) => prev.map(flag));
+      notification.warning({
+        message: "SLA breached",
+        description: `A conversation missed its ${
+          arrivedMessage.message === "firstResponse" ? "first response" : "resolution"
+        } target.`,
+        placement: "topRight",
+        duration: 3,
+      });
+    } else if (
+      (arrivedMessage?.type === "edit" || arrivedMessage?.type === "delete") &&
+      arrivedMessage?.channelId === selectedChannel?.id
+    ) {
+      // Edits and deletions replace the message they are about
+      setSelectedMessages((prev) =>
+        prev.map((message) =>
+          message.id === arrivedMessage.messageId
+            ? {
+                ...message,
+                message: arrivedMessage.message,
+                deleted: arrivedMessage.type === "delete",
+                editedAt: arrivedMessage.timeStamp,
+              }
+            : message
+        )
+      );
+    } else if (
       typeof arrivedMessage?.message !== "undefined" &&
-      arrivedMessage?.message !== "" &&
+      (arrivedMessage?.message !== "" || arrivedMessage?.attachments?.length > 0) &&
       arrivedMessage?.channelId === selectedChannel?.id &&
       arrivedMessage?.messageFrom !== state.user.email
     ) {
       setSelectedMessages((prev) => [...prev, arrivedMessage]);
+      sendMessage(JSON.stringify({ type: "read", channelId: selectedChannel.id }));
+    } else if (
+      arrivedMessage?.type === "message" &&
+      arrivedMessage?.messageFrom !== state.user.email
+    ) {
+      setMyChannels((prev) =>
+        prev.map((c) =>
+          c.id === arrivedMessage.channelId ? { ...c, unreadCount: (c.unreadCount || 0) + 1 } : c
+        )
+      );
     }
     return () => {
       abortController.abort();
@@ -104,6 +162,11 @@ export default function Index() {
           const data = await getChannel(selectedChannel.id, state.user.token);
           dispatch({ type: "SET_SELECTED_CHANNEL", payload: data });
           setSelectedMessages(data.messages);
+          setReadMarkers(data.readMarkers || []);
+          setMyChannels((prev) =>
+            prev.map((c) => (c.id === selectedChannel.id ? { ...c, unreadCount: 0 } : c))
+          );
+          sendMessage(JSON.stringify({ type: "read", channelId: selectedChannel.id }));
         }
       } catch (error) {
         notification.error({
@@ -125,6 +188,72 @@ export default function Index() {
     };
   }, [selectedChannel]);
 
+  // Other participants have seen a message once their read marker reached it
+  const isSeen = (message) =>
+    readMarkers.some(
+      (marker) =>
+        marker.participant !== state.user.email.toLowerCase() &&
+        new Date(marker.lastReadAt) >= new Date(message?.timeStamp)
+    );
+
+  // Pending attachments belong to the conversation they were uploaded to
+  useEffect(() => {
+    setAttachments([]);
+  }, [selectedChannel?.id]);
+
+  // Attachments are uploaded as soon as they are picked and sent with the next message
+  const handleAttach = async (e) => {
+    const file = e.target.files[0];
+    e.target.value = "";
+    if (!file || selectedChannel?.id === undefined) {
+      return;
+    }
+    try {
+      const uploaded = await uploadAttachment(selectedChannel.id, file, state.user.token);
+      setAttachments((prev) => [...prev, uploaded]);
+    } catch (error) {
+      notification.error({
+        message: "Could not attach file",
+        description: error?.response?.data?.errors?.join(", ") || error.message,
+        placement: "topRight",
+        duration: 2.0,
+      });
+    }
+  };
+
+  // Typing "/shortcut" alone suggests the canned responses behind it
+  useEffect(() => {
+    const shortcut = newMessage.match(/^\/(\S*)$/);
+    if (!shortcut) {
+      setSuggestions([]);
+      return;
+    }
+    let cancelled = false;
+    getCannedResponses(shortcut[1], state.user.token)
+      .then((resp) => !cancelled && setSuggestions(resp.data))
+      .catch(() => !cancelled && setSuggestions([]));
+    return () => {
+      cancelled = true;
+    };
+  }, [newMessage]);
+
+  // The rendered text replaces the shortcut, the macro has already changed the channel
+  const handleCannedResponse = async (response) => {
+    setSuggestions([]);
+    try {
+      const applied = await applyCannedResponse(selectedChannel.id, response.id, state.user.token);
+      setNewMessage(applied.text);
+      setSelectedChannel((prev) => ({ ...prev, currentStatus: applied.currentStatus, tags: applied.tags }));
+    } catch (error) {
+      notification.error({
+        message: "Could not insert canned response",
+        description: error?.response?.data?.errors?.join(", ") || error.message,
+        placement: "topRight",
+        duration: 2.0,
+      });
+    }
+  };
+
   const handleSubmit = async (e) => {
     e.preventDefault();
     if (selectedChannel?.id === undefined) {
@@ -144,14 +273,21 @@ export default function Index() {
       channelId: selectedChannel?.id,
       message: newMessage,
       messageFrom: state.user.email,
+      attachmentIds: attachments.map((attachment) => attachment.id),
+      attachments,
     };
-    setSelectedMessages([...selectedMessages, message]);
+    setSelectedMessages([...selectedMessages, { ...message, internal: isNote }]);
     setNewMessage("");
+    setAttachments([]);
+    // Notes are kept from the customer and do not answer them
+    if (isNote) {
+      sendMessage(JSON.stringify({ ...message, type: "note" }));
+      return;
+    }
     if (selectedChannel?.currentStatus === "ACTIVE") {
       const requestBody = {
         id: selectedChannel?.id,
         status: "IN_PROGRESS",
-        updatedBy: state.user.email,
         token: state.user.token,
       };
       await updateChannelStatus(requestBody);
@@ -191,19 +327,51 @@ export default function Index() {
                       <div ref={scrollRef}>
                       <Message
                         message={m}
+                        channelId={selectedChannel?.id}
+                        token={state.user.token}
                         isOwn={state.user.email === m?.messageFrom}
+                      seen={state.user.email === m?.messageFrom && isSeen(m)}
                       />
                       </div>
                     ))}
                   </div>
                   <div className="messageBoxBottom">
+                    {suggestions.length > 0 ? (
+                      <ul className="cannedResponses">
+                        {suggestions.map((response) => (
+                          <li key={response.id} onClick={() => handleCannedResponse(response)}>
+                            <b>/{response.shortcut}</b> {response.title}
+                          </li>
+                        ))}
+                      </ul>
+                    ) : null}
                     <textarea
                       required
                       className="messageBoxBottomTextarea"
-                      placeholder="Type a message..."
+                      placeholder={isNote ? "Type an internal note, the customer will not see it..." : "Type a message..."}
                       onChange={(e) => setNewMessage(e.target.value)}
                       value={newMessage}
                     ></textarea>
+                    <label className="chatNote" title="Only reps see internal notes">
+                      <input
+                        type="checkbox"
+                        checked={isNote}
+                        onChange={(e) => setIsNote(e.target.checked)}
+                      />
+                      Note
+                    </label>
+                    <label className="chatAttach" title={attachments.map((attachment) => attachment.fileName).join("\n") || "Attach a file"}>
+                      <PaperClipOutlined />
+                      {attachments.length > 0 ? (
+                        <span className="chatAttachCount">{attachments.length}</span>
+                      ) : null}
+                      <input
+                        type="file"
+                        hidden
+                        accept="image/png,image/jpeg,image/gif,image/webp,application/pdf,text/plain"
+                        onChange={handleAttach}
+                      />
+                    </label>
                     <button disabled={readyState !== ReadyState.OPEN} className="chatSubmit" onClick={handleSubmit}>
                       {isLoading ? (
                         <Spin
@@ -170,7 +170,6 @@ function Signup() {
                   className="loginInput"
                 >
                   <option selected>Select Role</option>
-                  <option value="ADMIN">Admin</option>
                   <option value="USER">User</option>
                 </select>
 
@@ -1,7 +1,7 @@
 import React, { useState, useEffect, useContext, useRef, useMemo } from "react";
 import { Redirect } from "react-router-dom";
-import { notification, Spin } from "antd";
-import { LoadingOutlined } from "@ant-design/icons";
+import { notification, Spin, Rate } from "antd";
+import { LoadingOutlined, PaperClipOutlined } from "@ant-design/icons";
 import "./style.css";
 import Channels from "../../components/channels/index";
 import Message from "../../components/message/index";
@@ -14,17 +14,24 @@ const {
   updateChannelStatus,
   createChannel,
   createMessage,
+  uploadAttachment,
 } = require("../../services/index");
 
 export default function Index() {
   // const [inActiveMessages, setInActiveMessages] = useState([]);
   const [state, dispatch] = useContext(Context);
   const [myChannels, setMyChannels] = useState([]);
-  const [socketUrl, setSocketUrl] = useState(process.env.REACT_APP_SOCKET_SERVER_URL);
+  const [socketUrl, setSocketUrl] = useState(
+    `${process.env.REACT_APP_SOCKET_SERVER_URL}?token=${state.user?.token}`
+  );
   const [selectedChannel, setSelectedChannel] = useState({});
   const [selectedMessages, setSelectedMessages] = useState([]);
   const [arrivedMessage, setArrivedMessage] = useState({});
   const [newMessage, setNewMessage] = useState("");
+  const [attachments, setAttachments] = useState([]);
+  const [readMarkers, setReadMarkers] = useState([]);
+  const [surveyPending, setSurveyPending] = useState(false);
+  const [surveyComment, setSurveyComment] = useState("");
   const [isNewMessage, setIsNewMessage] = useState(false);
   const [redirect, setRedirect] = useState("");
   const [isLoading, setIsLoading] = useState(false);
@@ -42,13 +49,53 @@ export default function Index() {
 
   useEffect(() => {
     const abortController = new AbortController();
-    if (
+    if (arrivedMessage?.type === "read") {
+      // Read markers only move forward, the one that arrived replaces the previous
+      if (arrivedMessage?.channelId === selectedChannel?.id) {
+        setReadMarkers((prev) => [
+          ...prev.filter((marker) => marker.participant !== arrivedMessage.messageFrom),
+          { participant: arrivedMessage.messageFrom, lastReadAt: arrivedMessage.timeStamp },
+        ]);
+      }
+    } else if (arrivedMessage?.type === "survey") {
+      // The rep completed the conversation, the customer is asked to rate it
+      if (arrivedMessage?.channelId === selectedChannel?.id) {
+        setSurveyPending(true);
+      }
+    } else if (
+      (arrivedMessage?.type === "edit" || arrivedMessage?.type === "delete") &&
+      arrivedMessage?.channelId === selectedChannel?.id
+    ) {
+      // Edits and deletions replace the message they are about
+      setSelectedMessages((prev) =>
+        prev.map((message) =>
+          message.id === arrivedMessage.messageId
+            ? {
+                ...message,
+                message: arrivedMessage.message,
+                deleted: arrivedMessage.type === "delete",
+                editedAt: arrivedMessage.timeStamp,
+              }
+            : message
+        )
+      );
+    } else if (
       typeof arrivedMessage?.message !== "undefined" &&
-      arrivedMessage?.message !== "" &&
+      (arrivedMessage?.message !== "" || arrivedMessage?.attachments?.length > 0) &&
       arrivedMessage?.channelId === selectedChannel?.id &&
       arrivedMessage?.messageFrom !== state.user.email
     ) {
       setSelectedMessages((prev) => [...prev, arrivedMessage]);
+      sendMessage(JSON.stringify({ type: "read", channelId: selectedChannel.id }));
+    } else if (
+      arrivedMessage?.type === "message" &&
+      arrivedMessage?.messageFrom !== state.user.email
+    ) {
+      setMyChannels((prev) =>
+        prev.map((c) =>
+          c.id === arrivedMessage.channelId ? { ...c, unreadCount: (c.unreadCount || 0) + 1 } : c
+        )
+      );
     }
     return () => {
       abortController.abort();
@@ -110,6 +157,13 @@ export default function Index() {
           const data = await getChannel(selectedChannel?.id, state.user.token);
           dispatch({ type: "SET_SELECTED_CHANNEL", payload: data });
           setSelectedMessages(data.messages);
+          setReadMarkers(data.readMarkers || []);
+          setSurveyPending(data.surveyPending === true);
+          setSurveyComment("");
+          setMyChannels((prev) =>
+            prev.map((c) => (c.id === selectedChannel.id ? { ...c, unreadCount: 0 } : c))
+          );
+          sendMessage(JSON.stringify({ type: "read", channelId: selectedChannel.id }));
           setIsNewMessage(false);
         }
       } catch (error) {
@@ -132,9 +186,42 @@ export default function Index() {
     };
   }, [selectedChannel]);
 
+  // Other participants have seen a message once their read marker reached it
+  const isSeen = (message) =>
+    readMarkers.some(
+      (marker) =>
+        marker.participant !== state.user.email.toLowerCase() &&
+        new Date(marker.lastReadAt) >= new Date(message?.timeStamp)
+    );
+
+  // Pending attachments belong to the conversation they were uploaded to
+  useEffect(() => {
+    setAttachments([]);
+  }, [selectedChannel?.id]);
+
+  // Attachments are uploaded as soon as they are picked and sent with the next message
+  const handleAttach = async (e) => {
+    const file = e.target.files[0];
+    e.target.value = "";
+    if (!file || selectedChannel?.id === undefined) {
+      return;
+    }
+    try {
+      const uploaded = await uploadAttachment(selectedChannel.id, file, state.user.token);
+      setAttachments((prev) => [...prev, uploaded]);
+    } catch (error) {
+      notification.error({
+        message: "Could not attach file",
+        description: error?.response?.data?.errors?.join(", ") || error.message,
+        placement: "topRight",
+        duration: 2.0,
+      });
+    }
+  };
+
   const handleSubmit = async (e) => {
     e.preventDefault();
-    if (newMessage.trim() === "") {
+    if (newMessage.trim() === "" && attachments.length === 0) {
       notification.error({
         message: "Could not send message",
         description: "Please type in a message",
@@ -164,16 +251,18 @@ export default function Index() {
       channelId: selectedChannel?.id,
       message: newMessage,
       messageFrom: state.user.email,
+      attachmentIds: attachments.map((attachment) => attachment.id),
+      attachments,
     };
     setSelectedMessages([...selectedMessages, message]);
     setNewMessage("");
+    setAttachments([]);
 
     if (selectedChannel && !isNewMessage) {
       if (selectedChannel?.status === "INACTIVE") {
         const requestBody = {
           id: selectedChannel?.id,
           status: "ACTIVE",
-          updatedBy: state.user.email,
         };
         try {
           await updateChannelStatus(requestBody);
@@ -229,6 +318,24 @@ export default function Index() {
     sendMessage(JSON.stringify(message));
   };
 
+  // Ratings go through the socket server, which stores them with the token of the customer
+  const handleRate = (score) => {
+    sendMessage(
+      JSON.stringify({
+        type: "rating",
+        channelId: selectedChannel.id,
+        rating: score,
+        message: surveyComment,
+      })
+    );
+    setSurveyPending(false);
+    notification.success({
+      message: "Thank you for your feedback!",
+      placement: "topRight",
+      duration: 1.5,
+    });
+  };
+
   const handleNewMessage = async (e) => {
     notification.success({
       message: "Add New Message!",
@@ -272,11 +379,27 @@ export default function Index() {
                     <div ref={scrollRef}>
                     <Message
                       message={m}
+                      channelId={selectedChannel?.id}
+                      token={state.user.token}
                       isOwn={state.user.email === m?.messageFrom}
+                      seen={state.user.email === m?.messageFrom && isSeen(m)}
                     />
                     </div>
                   ))}
                 </div>
+                {surveyPending ? (
+                  <div className="satisfactionSurvey">
+                    <span>How did we do?</span>
+                    <input
+                      className="satisfactionSurveyComment"
+                      placeholder="Anything to add? (optional)"
+                      maxLength={1000}
+                      onChange={(e) => setSurveyComment(e.target.value)}
+                      value={surveyComment}
+                    />
+                    <Rate onChange={handleRate} />
+                  </div>
+                ) : null}
                 <div className="messageBoxBottom">
                   <textarea
                     required
@@ -285,6 +408,18 @@ export default function Index() {
                     onChange={(e) => setNewMessage(e.target.value)}
                     value={newMessage}
                   ></textarea>
+                  <label className="chatAttach" title={attachments.map((attachment) => attachment.fileName).join("\n") || "Attach a file"}>
+                    <PaperClipOutlined />
+                    {attachments.length > 0 ? (
+                      <span className="chatAttachCount">{attachments.length}</span>
+                    ) : null}
+                    <input
+                      type="file"
+                      hidden
+                      accept="image/png,image/jpeg,image/gif,image/webp,application/pdf,text/plain"
+                      onChange={handleAttach}
+                    />
+                  </label>
                   <button disabled={readyState !== ReadyState.OPEN} className="chatSubmit" onClick={handleSubmit}>
                     {isLoading ? (
                       <Spin